@@ -5,7 +5,11 @@ import (
 	"os"
 
 	"github.com/bsmartlabs/dev-vault/internal/cli"
+	"github.com/bsmartlabs/dev-vault/internal/config"
+	"github.com/bsmartlabs/dev-vault/internal/secretprovider"
+	"github.com/bsmartlabs/dev-vault/internal/secretprovider/awssm"
 	scwprovider "github.com/bsmartlabs/dev-vault/internal/secretprovider/scaleway"
+	"github.com/bsmartlabs/dev-vault/internal/secretprovider/vaultkv"
 )
 
 var (
@@ -20,6 +24,18 @@ func main() {
 }
 
 func runMain(args []string, stdout, stderr io.Writer, version, commit, date string, runFn func([]string, io.Writer, io.Writer, cli.Dependencies) int) int {
-	deps := cli.DefaultDependencies(version, commit, date, scwprovider.Open)
+	deps := cli.DefaultDependencies(version, commit, date, openSecretAPI, scwprovider.OpenAccount)
 	return runFn(args, stdout, stderr, deps)
 }
+
+// openSecretAPI opens the secret store the manifest's provider selects.
+func openSecretAPI(cfg config.Config, profileOverride string) (secretprovider.SecretAPI, error) {
+	switch cfg.Provider {
+	case config.ProviderAWS:
+		return awssm.Open(cfg, profileOverride)
+	case config.ProviderVault:
+		return vaultkv.Open(cfg, profileOverride)
+	default:
+		return scwprovider.Open(cfg, profileOverride)
+	}
+}
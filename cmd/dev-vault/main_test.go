@@ -6,6 +6,9 @@ import (
 	"testing"
 
 	"github.com/bsmartlabs/dev-vault/internal/cli"
+	"github.com/bsmartlabs/dev-vault/internal/config"
+	"github.com/bsmartlabs/dev-vault/internal/secretprovider/awssm"
+	"github.com/bsmartlabs/dev-vault/internal/secretprovider/vaultkv"
 )
 
 func TestRunMain_UsesInjectedRunnerAndBuildMetadata(t *testing.T) {
@@ -43,3 +46,28 @@ func TestMain_UsesExitFnWithRunMainStatus(t *testing.T) {
 		t.Fatalf("expected exit code 0, got %d", exitCode)
 	}
 }
+
+func TestOpenSecretAPI_SelectsProvider(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKID")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	t.Setenv("AWS_PROFILE", "")
+	api, err := openSecretAPI(config.Config{Provider: config.ProviderAWS, Region: "eu-west-3"}, "")
+	if err != nil {
+		t.Fatalf("open aws: %v", err)
+	}
+	if _, ok := api.(*awssm.API); !ok {
+		t.Fatalf("expected the aws provider, got %T", api)
+	}
+	t.Setenv("VAULT_ADDR", "http://127.0.0.1:8200")
+	t.Setenv("VAULT_TOKEN", "s.token")
+	api, err = openSecretAPI(config.Config{Provider: config.ProviderVault}, "")
+	if err != nil {
+		t.Fatalf("open vault: %v", err)
+	}
+	if _, ok := api.(*vaultkv.API); !ok {
+		t.Fatalf("expected the vault provider, got %T", api)
+	}
+	if _, err := openSecretAPI(config.Config{Region: "invalid-region"}, ""); err == nil {
+		t.Fatalf("expected the scaleway provider to reject the config")
+	}
+}
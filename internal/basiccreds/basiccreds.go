@@ -0,0 +1,103 @@
+// Package basiccreds renders basic_credentials payloads into the files other
+// tools read them from: .netrc, docker config.json, and curl header files.
+package basiccreds
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+type Credentials struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// Parse decodes a basic_credentials payload.
+func Parse(payload []byte) (Credentials, error) {
+	var c Credentials
+	if err := json.Unmarshal(payload, &c); err != nil {
+		return Credentials{}, fmt.Errorf("decode basic credentials: %w", err)
+	}
+	if c.Username == "" {
+		return Credentials{}, errors.New("basic credentials: missing username")
+	}
+	return c, nil
+}
+
+func (c Credentials) basic() string {
+	return base64.StdEncoding.EncodeToString([]byte(c.Username + ":" + c.Password))
+}
+
+// Header is a curl header file line: curl -H @file.
+func (c Credentials) Header() []byte {
+	return []byte("Authorization: Basic " + c.basic() + "\n")
+}
+
+// MergeNetrc returns existing with the entry for host replaced, or appended
+// when there is none. Entries are matched line by line: an entry runs from
+// its "machine" line to the next line starting a machine, default, or macdef.
+func (c Credentials) MergeNetrc(existing []byte, host string) ([]byte, error) {
+	for _, field := range []string{host, c.Username, c.Password} {
+		if field == "" || strings.ContainsAny(field, " \t\r\n\"") {
+			return nil, errors.New("netrc cannot hold empty values, whitespace, or quotes in machine, login, or password")
+		}
+	}
+	var out bytes.Buffer
+	skipping := false
+	for _, line := range strings.SplitAfter(string(existing), "\n") {
+		if line == "" {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) > 0 {
+			switch fields[0] {
+			case "machine":
+				skipping = len(fields) > 1 && fields[1] == host
+			case "default", "macdef":
+				skipping = false
+			}
+		}
+		if !skipping {
+			out.WriteString(line)
+		}
+	}
+	if out.Len() > 0 && !bytes.HasSuffix(out.Bytes(), []byte("\n")) {
+		out.WriteByte('\n')
+	}
+	fmt.Fprintf(&out, "machine %s login %s password %s\n", host, c.Username, c.Password)
+	return out.Bytes(), nil
+}
+
+// MergeDockerConfig sets auths[registry] in a docker config.json and keeps
+// every other field as is. An empty existing document starts a new config.
+func (c Credentials) MergeDockerConfig(existing []byte, registry string) ([]byte, error) {
+	if registry == "" {
+		return nil, errors.New("docker config: registry is empty")
+	}
+	doc := map[string]json.RawMessage{}
+	if len(bytes.TrimSpace(existing)) > 0 {
+		if err := json.Unmarshal(existing, &doc); err != nil {
+			return nil, fmt.Errorf("docker config: %w", err)
+		}
+	}
+	var auths map[string]json.RawMessage
+	if raw, ok := doc["auths"]; ok {
+		if err := json.Unmarshal(raw, &auths); err != nil {
+			return nil, fmt.Errorf("docker config auths: %w", err)
+		}
+	}
+	if doc == nil { // "null"
+		doc = map[string]json.RawMessage{}
+	}
+	if auths == nil {
+		auths = map[string]json.RawMessage{}
+	}
+	auths[registry], _ = json.Marshal(map[string]string{"auth": c.basic()}) // string maps always encode
+	doc["auths"], _ = json.Marshal(auths)                                   // raw messages were decoded from valid JSON
+	out, _ := json.MarshalIndent(doc, "", "\t")
+	return append(out, '\n'), nil
+}
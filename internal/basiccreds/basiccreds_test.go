@@ -0,0 +1,88 @@
+package basiccreds
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	creds, err := Parse([]byte(`{"username":"ci","password":"pw"}`))
+	if err != nil || creds != (Credentials{Username: "ci", Password: "pw"}) {
+		t.Fatalf("unexpected credentials: %#v %v", creds, err)
+	}
+	if _, err := Parse([]byte(`nope`)); err == nil || !strings.Contains(err.Error(), "decode basic credentials") {
+		t.Fatalf("expected decode error, got %v", err)
+	}
+	if _, err := Parse([]byte(`{"password":"pw"}`)); err == nil || err.Error() != "basic credentials: missing username" {
+		t.Fatalf("expected missing username, got %v", err)
+	}
+}
+
+func TestHeader(t *testing.T) {
+	if got := string((Credentials{Username: "ci", Password: "pw"}).Header()); got != "Authorization: Basic Y2k6cHc=\n" {
+		t.Fatalf("unexpected header: %q", got)
+	}
+}
+
+func TestMergeNetrc(t *testing.T) {
+	creds := Credentials{Username: "ci", Password: "pw"}
+	got, err := creds.MergeNetrc(nil, "a.test")
+	if err != nil || string(got) != "machine a.test login ci password pw\n" {
+		t.Fatalf("unexpected new netrc: %q %v", got, err)
+	}
+
+	existing := "machine a.test\n  login old\n  password old\nmachine b.test login b password b\nmacdef init\ncd /\n\ndefault login anon"
+	got, err = creds.MergeNetrc([]byte(existing), "a.test")
+	want := "machine b.test login b password b\nmacdef init\ncd /\n\ndefault login anon\nmachine a.test login ci password pw\n"
+	if err != nil || string(got) != want {
+		t.Fatalf("unexpected merged netrc:\n%s", got)
+	}
+	got, _ = creds.MergeNetrc([]byte("machine b.test login b password b\ndefault login anon\n"), "c.test")
+	if string(got) != "machine b.test login b password b\ndefault login anon\nmachine c.test login ci password pw\n" {
+		t.Fatalf("unexpected appended netrc:\n%s", got)
+	}
+
+	for _, bad := range []struct{ host, user, pass string }{
+		{"", "ci", "pw"},
+		{"a.test", "c i", "pw"},
+		{"a.test", "ci", `p"w`},
+	} {
+		if _, err := (Credentials{Username: bad.user, Password: bad.pass}).MergeNetrc(nil, bad.host); err == nil || !strings.Contains(err.Error(), "netrc cannot hold") {
+			t.Fatalf("%#v: expected netrc error, got %v", bad, err)
+		}
+	}
+}
+
+func TestMergeDockerConfig(t *testing.T) {
+	creds := Credentials{Username: "ci", Password: "pw"}
+	got, err := creds.MergeDockerConfig(nil, "registry.test")
+	if err != nil || string(got) != "{\n\t\"auths\": {\n\t\t\"registry.test\": {\n\t\t\t\"auth\": \"Y2k6cHc=\"\n\t\t}\n\t}\n}\n" {
+		t.Fatalf("unexpected new config: %q %v", got, err)
+	}
+
+	existing := `{"credsStore":"desktop","auths":{"other.test":{"auth":"b3RoZXI="},"registry.test":{"auth":"b2xk"}}}`
+	got, err = creds.MergeDockerConfig([]byte(existing), "registry.test")
+	if err != nil || !strings.Contains(string(got), `"credsStore": "desktop"`) || !strings.Contains(string(got), `"auth": "b3RoZXI="`) ||
+		!strings.Contains(string(got), `"auth": "Y2k6cHc="`) || strings.Contains(string(got), "b2xk") {
+		t.Fatalf("unexpected merged config:\n%s", got)
+	}
+	for _, doc := range []string{"null", `{"auths":null}`, " \n"} {
+		if got, err := creds.MergeDockerConfig([]byte(doc), "registry.test"); err != nil || !strings.Contains(string(got), "Y2k6cHc=") {
+			t.Fatalf("%q: unexpected config %q %v", doc, got, err)
+		}
+	}
+
+	for doc, wantErr := range map[string]string{
+		`[]`:            "docker config:",
+		`{"auths":[]}`:  "docker config auths:",
+		`{"auths":"x"}`: "docker config auths:",
+		`{"auths":{}}x`: "docker config:",
+	} {
+		if _, err := creds.MergeDockerConfig([]byte(doc), "registry.test"); err == nil || !strings.Contains(err.Error(), wantErr) {
+			t.Fatalf("%q: expected %q, got %v", doc, wantErr, err)
+		}
+	}
+	if _, err := creds.MergeDockerConfig(nil, ""); err == nil || err.Error() != "docker config: registry is empty" {
+		t.Fatalf("expected empty registry error, got %v", err)
+	}
+}
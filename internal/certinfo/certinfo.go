@@ -0,0 +1,87 @@
+// Package certinfo reads the public metadata of a certificate secret. Private
+// key blocks are only counted, never decoded.
+package certinfo
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type Certificate struct {
+	Subject   string    `json:"subject"`
+	Issuer    string    `json:"issuer"`
+	SANs      []string  `json:"sans"`
+	Serial    string    `json:"serial"`
+	NotBefore time.Time `json:"not_before"`
+	NotAfter  time.Time `json:"not_after"`
+}
+
+// Bundle is a PEM payload: its certificates in file order (leaf first by
+// convention) and whether it also carries a private key.
+type Bundle struct {
+	Certificates  []Certificate `json:"certificates"`
+	HasPrivateKey bool          `json:"has_private_key"`
+}
+
+// Parse reads every CERTIFICATE block of a PEM payload.
+func Parse(payload []byte) (Bundle, error) {
+	var bundle Bundle
+	for rest := payload; ; {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if strings.HasSuffix(block.Type, "PRIVATE KEY") {
+			bundle.HasPrivateKey = true
+			continue
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return Bundle{}, fmt.Errorf("parse certificate %d: %w", len(bundle.Certificates), err)
+		}
+		bundle.Certificates = append(bundle.Certificates, describe(cert))
+	}
+	if len(bundle.Certificates) == 0 {
+		return Bundle{}, errors.New("no PEM certificate found")
+	}
+	return bundle, nil
+}
+
+func describe(cert *x509.Certificate) Certificate {
+	sans := append([]string{}, cert.DNSNames...)
+	for _, ip := range cert.IPAddresses {
+		sans = append(sans, ip.String())
+	}
+	sans = append(sans, cert.EmailAddresses...)
+	for _, uri := range cert.URIs {
+		sans = append(sans, uri.String())
+	}
+	return Certificate{
+		Subject:   cert.Subject.String(),
+		Issuer:    cert.Issuer.String(),
+		SANs:      sans,
+		Serial:    cert.SerialNumber.Text(16),
+		NotBefore: cert.NotBefore.UTC(),
+		NotAfter:  cert.NotAfter.UTC(),
+	}
+}
+
+// Expiry is the earliest NotAfter in the bundle: the chain stops validating
+// as soon as any of its certificates expires.
+func (b Bundle) Expiry() time.Time {
+	expiry := b.Certificates[0].NotAfter
+	for _, cert := range b.Certificates[1:] {
+		if cert.NotAfter.Before(expiry) {
+			expiry = cert.NotAfter
+		}
+	}
+	return expiry
+}
@@ -0,0 +1,78 @@
+package certinfo
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testCert(t *testing.T, cn string, notAfter time.Time, template x509.Certificate) []byte {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	template.SerialNumber = big.NewInt(0x1f)
+	template.Subject = pkix.Name{CommonName: cn}
+	template.NotBefore = notAfter.Add(-24 * time.Hour)
+	template.NotAfter = notAfter
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("create certificate: %v", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func TestParse(t *testing.T) {
+	leafExpiry := time.Date(2027, 1, 2, 3, 4, 5, 0, time.UTC)
+	caExpiry := time.Date(2026, 12, 1, 0, 0, 0, 0, time.UTC)
+	uri, _ := url.Parse("spiffe://dev/api")
+	leaf := testCert(t, "api.local", leafExpiry, x509.Certificate{
+		DNSNames:       []string{"api.local"},
+		IPAddresses:    []net.IP{net.ParseIP("127.0.0.1")},
+		EmailAddresses: []string{"ops@example.test"},
+		URIs:           []*url.URL{uri},
+	})
+	ca := testCert(t, "dev CA", caExpiry, x509.Certificate{})
+	key := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: []byte("not decoded")})
+	other := pem.EncodeToMemory(&pem.Block{Type: "X509 CRL", Bytes: []byte("skipped")})
+
+	bundle, err := Parse(append(append(append(leaf, ca...), key...), other...))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(bundle.Certificates) != 2 || !bundle.HasPrivateKey {
+		t.Fatalf("unexpected bundle: %#v", bundle)
+	}
+	got := bundle.Certificates[0]
+	if got.Subject != "CN=api.local" || got.Issuer != "CN=api.local" || got.Serial != "1f" || !got.NotAfter.Equal(leafExpiry) {
+		t.Fatalf("unexpected leaf: %#v", got)
+	}
+	if want := []string{"api.local", "127.0.0.1", "ops@example.test", "spiffe://dev/api"}; !reflect.DeepEqual(got.SANs, want) {
+		t.Fatalf("unexpected SANs: %#v", got.SANs)
+	}
+	if !bundle.Expiry().Equal(caExpiry) {
+		t.Fatalf("expected the earliest expiry, got %s", bundle.Expiry())
+	}
+	if single, _ := Parse(leaf); !single.Expiry().Equal(leafExpiry) || single.HasPrivateKey {
+		t.Fatalf("unexpected single-certificate bundle: %#v", single)
+	}
+
+	if _, err := Parse(key); err == nil || err.Error() != "no PEM certificate found" {
+		t.Fatalf("expected no certificate error, got %v", err)
+	}
+	bad := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte("garbage")})
+	if _, err := Parse(append(leaf, bad...)); err == nil || !strings.Contains(err.Error(), "parse certificate 1") {
+		t.Fatalf("expected parse error, got %v", err)
+	}
+}
@@ -0,0 +1,101 @@
+// Package checkouts remembers the project directories dev-vault runs in, so
+// where can list them across the machine. Entries hold config paths and times
+// only, never secret names or payloads.
+package checkouts
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/bsmartlabs/dev-vault/internal/fsx"
+)
+
+const fileName = "checkouts.json"
+
+// refreshAfter limits writes: commands such as prompt run on every shell
+// prompt, and a last use to the hour is enough to tell checkouts apart.
+const refreshAfter = time.Hour
+
+type Checkout struct {
+	Config   string    `json:"config"`
+	LastUsed time.Time `json:"last_used"`
+}
+
+// Store keeps the list of checkouts in one file under a per-user directory.
+type Store struct {
+	path string
+}
+
+func NewStore(dir string) Store {
+	return Store{path: filepath.Join(dir, fileName)}
+}
+
+// List returns the recorded checkouts sorted by config path.
+func (s Store) List() ([]Checkout, error) {
+	raw, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read checkouts: %w", err)
+	}
+	var checkouts []Checkout
+	if err := json.Unmarshal(raw, &checkouts); err != nil {
+		return nil, fmt.Errorf("decode checkouts: %w", err)
+	}
+	sort.Slice(checkouts, func(i, j int) bool { return checkouts[i].Config < checkouts[j].Config })
+	return checkouts, nil
+}
+
+// Record notes that configPath was used at now. It writes nothing when the
+// checkout was already used within the last hour.
+func (s Store) Record(configPath string, now time.Time) error {
+	checkouts, err := s.List()
+	if err != nil {
+		return err
+	}
+	for i, checkout := range checkouts {
+		if checkout.Config != configPath {
+			continue
+		}
+		if now.Sub(checkout.LastUsed) < refreshAfter {
+			return nil
+		}
+		checkouts[i].LastUsed = now
+		return s.save(checkouts)
+	}
+	return s.save(append(checkouts, Checkout{Config: configPath, LastUsed: now}))
+}
+
+// Forget drops the checkouts of configPaths, such as deleted clones.
+func (s Store) Forget(configPaths ...string) error {
+	checkouts, err := s.List()
+	if err != nil {
+		return err
+	}
+	drop := make(map[string]bool, len(configPaths))
+	for _, configPath := range configPaths {
+		drop[configPath] = true
+	}
+	kept := checkouts[:0]
+	for _, checkout := range checkouts {
+		if !drop[checkout.Config] {
+			kept = append(kept, checkout)
+		}
+	}
+	return s.save(kept)
+}
+
+func (s Store) save(checkouts []Checkout) error {
+	sort.Slice(checkouts, func(i, j int) bool { return checkouts[i].Config < checkouts[j].Config })
+	raw, _ := json.MarshalIndent(checkouts, "", "  ") // strings and times only; always encodes
+	if err := fsx.AtomicWriteFile(s.path, append(raw, '\n'), 0o600, true); err != nil {
+		return fmt.Errorf("write checkouts: %w", err)
+	}
+	return nil
+}
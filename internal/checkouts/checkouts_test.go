@@ -0,0 +1,82 @@
+package checkouts
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStore(t *testing.T) {
+	dir := t.TempDir()
+	store := NewStore(dir)
+	if got, err := store.List(); got != nil || err != nil {
+		t.Fatalf("expected no checkouts, got %v %v", got, err)
+	}
+
+	now := time.Date(2026, 3, 4, 5, 0, 0, 0, time.UTC)
+	for _, step := range []struct {
+		config string
+		at     time.Time
+	}{
+		{"/b/.scw.json", now},
+		{"/a/.scw.json", now},
+		// Within the hour the last use is kept; after it, it moves on.
+		{"/a/.scw.json", now.Add(30 * time.Minute)},
+		{"/b/.scw.json", now.Add(2 * time.Hour)},
+	} {
+		if err := store.Record(step.config, step.at); err != nil {
+			t.Fatalf("Record %s: %v", step.config, err)
+		}
+	}
+	got, err := store.List()
+	if err != nil || len(got) != 2 || got[0] != (Checkout{"/a/.scw.json", now}) || !got[1].LastUsed.Equal(now.Add(2*time.Hour)) {
+		t.Fatalf("unexpected checkouts: %v %v", got, err)
+	}
+	if info, err := os.Stat(filepath.Join(dir, fileName)); err != nil || info.Mode().Perm() != 0o600 {
+		t.Fatalf("expected a 0600 file, got %v %v", info, err)
+	}
+
+	if err := store.Forget("/a/.scw.json", "/c/.scw.json"); err != nil {
+		t.Fatalf("Forget: %v", err)
+	}
+	if got, _ := store.List(); len(got) != 1 || got[0].Config != "/b/.scw.json" {
+		t.Fatalf("expected only /b left, got %v", got)
+	}
+}
+
+func TestStore_Errors(t *testing.T) {
+	dir := t.TempDir()
+	store := NewStore(dir)
+	path := filepath.Join(dir, fileName)
+	if err := os.WriteFile(path, []byte("{"), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	for name, err := range map[string]error{
+		"list":   func() error { _, err := store.List(); return err }(),
+		"record": store.Record("/a", time.Now()),
+		"forget": store.Forget("/a"),
+	} {
+		if err == nil || !strings.Contains(err.Error(), "decode checkouts") {
+			t.Fatalf("%s: expected decode error, got %v", name, err)
+		}
+	}
+
+	if err := os.Remove(path); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	if err := os.Mkdir(path, 0o700); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if _, err := store.List(); err == nil || !strings.Contains(err.Error(), "read checkouts") {
+		t.Fatalf("expected read error, got %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "file"), []byte("x"), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := NewStore(filepath.Join(dir, "file")).save(nil); err == nil || !strings.Contains(err.Error(), "write checkouts") {
+		t.Fatalf("expected write error, got %v", err)
+	}
+}
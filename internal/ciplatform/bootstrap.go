@@ -0,0 +1,94 @@
+package ciplatform
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ModulePath is the go install path of the dev-vault binary.
+const ModulePath = "github.com/bsmartlabs/dev-vault/cmd/dev-vault"
+
+// BootstrapOptions parameterizes a generated pipeline job.
+type BootstrapOptions struct {
+	// Version is the go install version: a release tag such as v1.4.0, or
+	// "latest".
+	Version string
+	// ConfigPath is passed as --config when set; the job runs from the
+	// repository root.
+	ConfigPath string
+	// CIExport adds --ci-export, for manifests with dotenv mappings.
+	CIExport bool
+}
+
+var shellSafe = regexp.MustCompile(`^[A-Za-z0-9._/@:=+-]+$`)
+
+// PullCommand is the dev-vault invocation run by a generated job.
+func (o BootstrapOptions) PullCommand() string {
+	args := []string{"dev-vault"}
+	if o.ConfigPath != "" {
+		args = append(args, "--config", shellQuote(o.ConfigPath))
+	}
+	args = append(args, "pull", "--all", "--overwrite")
+	if o.CIExport {
+		args = append(args, "--ci-export")
+	}
+	return strings.Join(args, " ")
+}
+
+// Bootstrap renders a job that installs dev-vault, authenticates with
+// SCW_ACCESS_KEY/SCW_SECRET_KEY from the platform's secret store, and pulls
+// every mapping into the workspace. Organization, project, and region are
+// read from the manifest, so they are not repeated in the job.
+func (p Platform) Bootstrap(opts BootstrapOptions) (string, error) {
+	install := fmt.Sprintf("go install %s@%s", ModulePath, opts.Version)
+	var b strings.Builder
+	switch p {
+	case GitHubActions:
+		b.WriteString("# Paste under \"jobs:\" in a workflow. Add SCW_ACCESS_KEY and SCW_SECRET_KEY as\n")
+		b.WriteString("# repository secrets; organization, project, and region come from the manifest.\n")
+		b.WriteString("dev-vault:\n")
+		b.WriteString("  runs-on: ubuntu-latest\n")
+		b.WriteString("  steps:\n")
+		b.WriteString("    - uses: actions/checkout@v4\n")
+		b.WriteString("    - uses: actions/setup-go@v5\n")
+		b.WriteString("      with:\n")
+		b.WriteString("        go-version: stable\n")
+		b.WriteString("    - name: Install dev-vault\n")
+		fmt.Fprintf(&b, "      run: %s\n", install)
+		b.WriteString("    - name: Pull secrets\n")
+		b.WriteString("      env:\n")
+		b.WriteString("        SCW_ACCESS_KEY: ${{ secrets.SCW_ACCESS_KEY }}\n")
+		b.WriteString("        SCW_SECRET_KEY: ${{ secrets.SCW_SECRET_KEY }}\n")
+		fmt.Fprintf(&b, "      run: %s\n", opts.PullCommand())
+		b.WriteString("    # Later steps of this job see the pulled files")
+		if opts.CIExport {
+			b.WriteString(" and variables")
+		}
+		b.WriteString(".\n")
+	case GitLabCI:
+		b.WriteString("# Add SCW_ACCESS_KEY and SCW_SECRET_KEY as masked CI/CD variables;\n")
+		b.WriteString("# organization, project, and region come from the manifest.\n")
+		b.WriteString("dev-vault:\n")
+		b.WriteString("  image: golang:1\n")
+		b.WriteString("  script:\n")
+		fmt.Fprintf(&b, "    - %s\n", install)
+		fmt.Fprintf(&b, "    - %s\n", opts.PullCommand())
+		if opts.CIExport {
+			b.WriteString("  # Later jobs receive the dotenv variables; the pulled files stay in this job.\n")
+			b.WriteString("  artifacts:\n")
+			b.WriteString("    reports:\n")
+			fmt.Fprintf(&b, "      dotenv: %s\n", GitLabDotenvFile)
+		}
+	default:
+		return "", fmt.Errorf("no bootstrap snippet for CI platform %q (supported: %s, %s)", p, GitHubActions, GitLabCI)
+	}
+	return b.String(), nil
+}
+
+func shellQuote(s string) string {
+	if shellSafe.MatchString(s) {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
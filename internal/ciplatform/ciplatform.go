@@ -0,0 +1,144 @@
+// Package ciplatform detects the CI system dev-vault runs under and writes
+// pulled dotenv variables where that system passes them to later steps or
+// jobs. It never masks values: every platform's runtime masking works by
+// printing the value to the job log, which dev-vault refuses to do.
+package ciplatform
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/bsmartlabs/dev-vault/internal/fsx"
+)
+
+type Platform string
+
+const (
+	GitHubActions Platform = "github-actions"
+	GitLabCI      Platform = "gitlab-ci"
+	CircleCI      Platform = "circleci"
+)
+
+// GitLabDotenvFile is written under CI_PROJECT_DIR; declare it as an
+// artifacts:reports:dotenv report to hand the variables to later jobs.
+const GitLabDotenvFile = "dev-vault.env"
+
+// Detect returns the CI platform from its well-known marker variables, or ""
+// outside CI.
+func Detect(getenv func(string) string) Platform {
+	switch {
+	case getenv("GITHUB_ACTIONS") == "true":
+		return GitHubActions
+	case getenv("GITLAB_CI") == "true":
+		return GitLabCI
+	case getenv("CIRCLECI") == "true":
+		return CircleCI
+	}
+	return ""
+}
+
+// Running reports whether dev-vault runs in any CI job: a detected platform,
+// or the CI variable most other systems set.
+func Running(getenv func(string) string) bool {
+	return Detect(getenv) != "" || getenv("CI") != ""
+}
+
+// ExportPath is the file that carries variables to later steps: the
+// GITHUB_ENV file, CircleCI's BASH_ENV, or the GitLab dotenv report.
+func (p Platform) ExportPath(getenv func(string) string) (string, error) {
+	var name, path string
+	switch p {
+	case GitHubActions:
+		name, path = "GITHUB_ENV", getenv("GITHUB_ENV")
+	case CircleCI:
+		name, path = "BASH_ENV", getenv("BASH_ENV")
+	case GitLabCI:
+		name = "CI_PROJECT_DIR"
+		if dir := getenv(name); dir != "" {
+			path = filepath.Join(dir, GitLabDotenvFile)
+		}
+	default:
+		return "", fmt.Errorf("unsupported CI platform %q", p)
+	}
+	if path == "" {
+		return "", fmt.Errorf("%s: %s is not set", p, name)
+	}
+	return path, nil
+}
+
+// Render formats env in the platform's convention, keys sorted.
+func (p Platform) Render(env map[string]string) ([]byte, error) {
+	keys := make([]string, 0, len(env))
+	for k := range env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for _, k := range keys {
+		v := env[k]
+		switch p {
+		case GitHubActions:
+			if strings.ContainsAny(v, "\r\n") {
+				delim := heredocDelimiter(v)
+				fmt.Fprintf(&b, "%s<<%s\n%s\n%s\n", k, delim, v, delim)
+			} else {
+				fmt.Fprintf(&b, "%s=%s\n", k, v)
+			}
+		case CircleCI:
+			fmt.Fprintf(&b, "export %s='%s'\n", k, strings.ReplaceAll(v, "'", `'\''`))
+		case GitLabCI:
+			// GitLab reads dotenv reports literally: no quoting, one line each.
+			if strings.ContainsAny(v, "\r\n") {
+				return nil, fmt.Errorf("%s: %s has a multi-line value, which dotenv reports cannot hold", p, k)
+			}
+			fmt.Fprintf(&b, "%s=%s\n", k, v)
+		default:
+			return nil, fmt.Errorf("unsupported CI platform %q", p)
+		}
+	}
+	return []byte(b.String()), nil
+}
+
+// Export writes env to the platform's export file. GitHub and CircleCI files
+// are shared with the runner and other steps, so they are appended to; the
+// GitLab report belongs to dev-vault and is replaced.
+func (p Platform) Export(getenv func(string) string, env map[string]string) (string, error) {
+	path, err := p.ExportPath(getenv)
+	if err != nil {
+		return "", err
+	}
+	data, err := p.Render(env)
+	if err != nil {
+		return "", err
+	}
+	if p == GitLabCI {
+		if err := fsx.AtomicWriteFile(path, data, 0o600, true); err != nil {
+			return "", err
+		}
+		return path, nil
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return "", err
+	}
+	_, writeErr := f.Write(data)
+	if err := errors.Join(writeErr, f.Close()); err != nil {
+		return "", fmt.Errorf("write %s: %w", path, err)
+	}
+	return path, nil
+}
+
+// heredocDelimiter picks a GITHUB_ENV heredoc delimiter that cannot end the
+// value early.
+func heredocDelimiter(value string) string {
+	delim := "DEV_VAULT_EOF"
+	for strings.Contains(value, delim) {
+		delim += "_"
+	}
+	return delim
+}
@@ -0,0 +1,178 @@
+package ciplatform
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func envFunc(env map[string]string) func(string) string {
+	return func(k string) string { return env[k] }
+}
+
+func TestDetect(t *testing.T) {
+	for _, tc := range []struct {
+		env  map[string]string
+		want Platform
+	}{
+		{map[string]string{"GITHUB_ACTIONS": "true"}, GitHubActions},
+		{map[string]string{"GITLAB_CI": "true"}, GitLabCI},
+		{map[string]string{"CIRCLECI": "true"}, CircleCI},
+		{map[string]string{"CI": "true"}, ""},
+	} {
+		if got := Detect(envFunc(tc.env)); got != tc.want {
+			t.Fatalf("%v: got %q, want %q", tc.env, got, tc.want)
+		}
+	}
+}
+
+func TestRunning(t *testing.T) {
+	for _, tc := range []struct {
+		env  map[string]string
+		want bool
+	}{
+		{map[string]string{"GITLAB_CI": "true"}, true},
+		{map[string]string{"CI": "1"}, true},
+		{map[string]string{"GITHUB_ACTIONS": "false"}, false},
+	} {
+		if got := Running(envFunc(tc.env)); got != tc.want {
+			t.Fatalf("%v: got %v, want %v", tc.env, got, tc.want)
+		}
+	}
+}
+
+func TestExportPath(t *testing.T) {
+	env := envFunc(map[string]string{"GITHUB_ENV": "/gh/env", "BASH_ENV": "/circle/env", "CI_PROJECT_DIR": "/builds/app"})
+	for p, want := range map[Platform]string{
+		GitHubActions: "/gh/env",
+		CircleCI:      "/circle/env",
+		GitLabCI:      filepath.Join("/builds/app", GitLabDotenvFile),
+	} {
+		if got, err := p.ExportPath(env); err != nil || got != want {
+			t.Fatalf("%s: got %q %v", p, got, err)
+		}
+	}
+	empty := envFunc(nil)
+	for p, want := range map[Platform]string{
+		GitHubActions: "GITHUB_ENV is not set",
+		CircleCI:      "BASH_ENV is not set",
+		GitLabCI:      "CI_PROJECT_DIR is not set",
+		"jenkins":     "unsupported CI platform",
+	} {
+		if _, err := p.ExportPath(empty); err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("%s: expected %q, got %v", p, want, err)
+		}
+	}
+}
+
+func TestRender(t *testing.T) {
+	env := map[string]string{"B": "it's", "A": "1"}
+	for p, want := range map[Platform]string{
+		GitHubActions: "A=1\nB=it's\n",
+		CircleCI:      "export A='1'\nexport B='it'\\''s'\n",
+		GitLabCI:      "A=1\nB=it's\n",
+	} {
+		got, err := p.Render(env)
+		if err != nil || string(got) != want {
+			t.Fatalf("%s: got %q %v", p, got, err)
+		}
+	}
+
+	got, err := GitHubActions.Render(map[string]string{"K": "a\nDEV_VAULT_EOF\nb"})
+	if err != nil || string(got) != "K<<DEV_VAULT_EOF_\na\nDEV_VAULT_EOF\nb\nDEV_VAULT_EOF_\n" {
+		t.Fatalf("unexpected heredoc: %q %v", got, err)
+	}
+	if _, err := GitLabCI.Render(map[string]string{"K": "a\nb"}); err == nil || !strings.Contains(err.Error(), "K has a multi-line value") {
+		t.Fatalf("expected multi-line error, got %v", err)
+	}
+	if _, err := Platform("jenkins").Render(map[string]string{"K": "v"}); err == nil {
+		t.Fatal("expected unsupported platform error")
+	}
+}
+
+func TestExport(t *testing.T) {
+	dir := t.TempDir()
+	ghEnv := filepath.Join(dir, "github_env")
+	if err := os.WriteFile(ghEnv, []byte("EXISTING=1\n"), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	env := envFunc(map[string]string{"GITHUB_ENV": ghEnv, "CI_PROJECT_DIR": dir, "BASH_ENV": filepath.Join(dir, "missing", "bash_env")})
+
+	if path, err := GitHubActions.Export(env, map[string]string{"A": "1"}); err != nil || path != ghEnv {
+		t.Fatalf("github export: %q %v", path, err)
+	}
+	if raw, _ := os.ReadFile(ghEnv); string(raw) != "EXISTING=1\nA=1\n" {
+		t.Fatalf("expected append, got %q", raw)
+	}
+
+	report := filepath.Join(dir, GitLabDotenvFile)
+	for range 2 {
+		if path, err := GitLabCI.Export(env, map[string]string{"A": "1"}); err != nil || path != report {
+			t.Fatalf("gitlab export: %q %v", path, err)
+		}
+	}
+	if raw, _ := os.ReadFile(report); string(raw) != "A=1\n" {
+		t.Fatalf("expected replaced report, got %q", raw)
+	}
+
+	if _, err := CircleCI.Export(env, map[string]string{"A": "1"}); err == nil {
+		t.Fatal("expected open error")
+	}
+	if _, err := GitLabCI.Export(env, map[string]string{"A": "a\nb"}); err == nil {
+		t.Fatal("expected render error")
+	}
+	if _, err := GitHubActions.Export(envFunc(nil), nil); err == nil {
+		t.Fatal("expected path error")
+	}
+	if _, err := GitLabCI.Export(envFunc(map[string]string{"CI_PROJECT_DIR": filepath.Join(ghEnv, "sub")}), nil); err == nil {
+		t.Fatal("expected atomic write error")
+	}
+	if _, err := GitHubActions.Export(envFunc(map[string]string{"GITHUB_ENV": "/dev/full"}), map[string]string{"A": "1"}); err == nil || !strings.Contains(err.Error(), "write /dev/full") {
+		t.Fatalf("expected write error, got %v", err)
+	}
+}
+
+func TestBootstrap(t *testing.T) {
+	gh, err := GitHubActions.Bootstrap(BootstrapOptions{Version: "v1.4.0", ConfigPath: "deploy/my app/.scw.json", CIExport: true})
+	if err != nil {
+		t.Fatalf("github: %v", err)
+	}
+	for _, want := range []string{
+		"      run: go install " + ModulePath + "@v1.4.0\n",
+		"        SCW_SECRET_KEY: ${{ secrets.SCW_SECRET_KEY }}\n",
+		"      run: dev-vault --config 'deploy/my app/.scw.json' pull --all --overwrite --ci-export\n",
+		"pulled files and variables.\n",
+	} {
+		if !strings.Contains(gh, want) {
+			t.Fatalf("github snippet missing %q:\n%s", want, gh)
+		}
+	}
+	if gh, _ := GitHubActions.Bootstrap(BootstrapOptions{Version: "latest"}); !strings.Contains(gh, "      run: dev-vault pull --all --overwrite\n") || !strings.HasSuffix(gh, "pulled files.\n") {
+		t.Fatalf("unexpected github snippet:\n%s", gh)
+	}
+
+	gl, err := GitLabCI.Bootstrap(BootstrapOptions{Version: "latest", ConfigPath: "ci/.scw.json", CIExport: true})
+	if err != nil {
+		t.Fatalf("gitlab: %v", err)
+	}
+	for _, want := range []string{
+		"    - go install " + ModulePath + "@latest\n",
+		"    - dev-vault --config ci/.scw.json pull --all --overwrite --ci-export\n",
+		"      dotenv: " + GitLabDotenvFile + "\n",
+	} {
+		if !strings.Contains(gl, want) {
+			t.Fatalf("gitlab snippet missing %q:\n%s", want, gl)
+		}
+	}
+	if gl, _ := GitLabCI.Bootstrap(BootstrapOptions{Version: "latest"}); strings.Contains(gl, "artifacts:") {
+		t.Fatalf("expected no dotenv report without --ci-export:\n%s", gl)
+	}
+
+	if _, err := CircleCI.Bootstrap(BootstrapOptions{}); err == nil || !strings.Contains(err.Error(), "supported: github-actions, gitlab-ci") {
+		t.Fatalf("expected unsupported platform, got %v", err)
+	}
+	if got := shellQuote("it's"); got != `'it'\''s'` {
+		t.Fatalf("unexpected quoting: %s", got)
+	}
+}
@@ -0,0 +1,25 @@
+package cli
+
+import (
+	"os/exec"
+	"strings"
+)
+
+// openURL opens rawURL in the user's browser: $BROWSER when set, else the
+// platform's opener. It returns once the browser is started.
+func openURL(getenv func(string) string, goos, rawURL string, start func(name string, args []string) error) error {
+	if browser := strings.TrimSpace(getenv("BROWSER")); browser != "" {
+		return start(browser, []string{rawURL})
+	}
+	switch goos {
+	case "darwin":
+		return start("open", []string{rawURL})
+	case "windows":
+		return start("rundll32", []string{"url.dll,FileProtocolHandler", rawURL})
+	}
+	return start("xdg-open", []string{rawURL})
+}
+
+func startProcess(name string, args []string) error {
+	return exec.Command(name, args...).Start()
+}
@@ -0,0 +1,32 @@
+package cli
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestOpenURL(t *testing.T) {
+	const page = "https://access.example.test/"
+	cases := []struct {
+		browser, goos string
+		want          []string
+	}{
+		{"firefox", "linux", []string{"firefox", page}},
+		{"", "darwin", []string{"open", page}},
+		{"", "windows", []string{"rundll32", "url.dll,FileProtocolHandler", page}},
+		{" ", "linux", []string{"xdg-open", page}},
+	}
+	for _, tc := range cases {
+		var got []string
+		err := openURL(func(string) string { return tc.browser }, tc.goos, page, func(name string, args []string) error {
+			got = append([]string{name}, args...)
+			return nil
+		})
+		if err != nil || !reflect.DeepEqual(got, tc.want) {
+			t.Fatalf("%s/%q: got %v %v, want %v", tc.goos, tc.browser, got, err, tc.want)
+		}
+	}
+	if err := startProcess("dev-vault-no-such-browser", nil); err == nil {
+		t.Fatalf("expected start error, got %v", err)
+	}
+}
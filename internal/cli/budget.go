@@ -0,0 +1,86 @@
+package cli
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/bsmartlabs/dev-vault/internal/config"
+	"github.com/bsmartlabs/dev-vault/internal/i18n"
+	"github.com/bsmartlabs/dev-vault/internal/secretprovider"
+)
+
+// countingAPI counts the provider calls of one command for the budget check.
+// It sits under the access cache, so only calls that reach the provider count.
+type countingAPI struct {
+	secretprovider.SecretAPI
+	calls *atomic.Int64
+}
+
+func (c countingAPI) ListSecrets(req secretprovider.ListSecretsInput) ([]secretprovider.SecretRecord, error) {
+	c.calls.Add(1)
+	return c.SecretAPI.ListSecrets(req)
+}
+
+func (c countingAPI) AccessSecretVersion(req secretprovider.AccessSecretVersionInput) (*secretprovider.SecretVersionRecord, error) {
+	c.calls.Add(1)
+	return c.SecretAPI.AccessSecretVersion(req)
+}
+
+func (c countingAPI) ListSecretVersions(req secretprovider.ListSecretVersionsInput) ([]secretprovider.SecretVersionRecord, error) {
+	c.calls.Add(1)
+	return c.SecretAPI.ListSecretVersions(req)
+}
+
+func (c countingAPI) CreateSecret(req secretprovider.CreateSecretInput) (*secretprovider.SecretRecord, error) {
+	c.calls.Add(1)
+	return c.SecretAPI.CreateSecret(req)
+}
+
+func (c countingAPI) CreateSecretVersion(req secretprovider.CreateSecretVersionInput) (*secretprovider.SecretVersionRecord, error) {
+	c.calls.Add(1)
+	return c.SecretAPI.CreateSecretVersion(req)
+}
+
+func (c countingAPI) DeleteSecret(req secretprovider.DeleteSecretInput) error {
+	c.calls.Add(1)
+	return c.SecretAPI.DeleteSecret(req)
+}
+
+func (c countingAPI) DeleteSecretVersion(req secretprovider.DeleteSecretVersionInput) error {
+	c.calls.Add(1)
+	return c.SecretAPI.DeleteSecretVersion(req)
+}
+
+func (c countingAPI) DisableSecretVersion(req secretprovider.DisableSecretVersionInput) error {
+	c.calls.Add(1)
+	return c.SecretAPI.DisableSecretVersion(req)
+}
+
+// budgetHints point an over-budget command toward a cheaper invocation.
+var budgetHints = map[string]i18n.MessageID{
+	"pull":   i18n.MsgBudgetHintPull,
+	"push":   i18n.MsgBudgetHintPush,
+	"doctor": i18n.MsgBudgetHintDoctor,
+	"list":   i18n.MsgBudgetHintList,
+}
+
+// checkBudget warns when a command made more provider calls or ran longer
+// than the manifest's budget allows.
+func (r commandRuntime) checkBudget(budget *config.Budget, calls int64, elapsed time.Duration) error {
+	maxCalls, maxDuration := budget.Limits()
+	command := r.parsed.fs.Name()
+	hint := budgetHints[command]
+	var over []error
+	if calls > int64(maxCalls) {
+		over = append(over, i18n.Errorf(i18n.MsgWarnBudgetCalls, command, calls, maxCalls, hint))
+	}
+	if elapsed > maxDuration {
+		over = append(over, i18n.Errorf(i18n.MsgWarnBudgetDuration, command, elapsed.Round(100*time.Millisecond), maxDuration, hint))
+	}
+	for _, message := range over {
+		if err := r.parsed.warnings.warnError(warningBudget, message); err != nil {
+			return outputError(err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bsmartlabs/dev-vault/internal/config"
+	secret "github.com/scaleway/scaleway-sdk-go/api/secret/v1beta1"
+)
+
+func TestRun_Budget(t *testing.T) {
+	root := t.TempDir()
+	cfgPath := writeConfig(t, root, `{"organization_id":"org","project_id":"proj","region":"fr-par","budget":{"max_api_calls":1,"max_duration":"30s"},"mapping":{
+		"app-dev":{"file":"app.bin","format":"raw"},
+		"new-dev":{"file":"new.bin","format":"raw","mode":"push","type":"opaque"}
+	}}`)
+	if err := os.WriteFile(filepath.Join(root, "new.bin"), []byte("N"), 0o644); err != nil {
+		t.Fatalf("write new.bin: %v", err)
+	}
+	api := newFakeSecretAPI()
+	sec := api.AddSecret("proj", "app-dev", "/", secret.SecretTypeOpaque)
+	api.AddEnabledVersion(sec.ID, []byte("A"))
+	deps := baseDeps(func(config.Config, string) (SecretAPI, error) { return api, nil })
+
+	// Over the call budget: the pull still completes, then warns with a hint.
+	var out, errBuf bytes.Buffer
+	code := Run([]string{"dev-vault", "--config", cfgPath, "pull", "app-dev"}, &out, &errBuf, deps)
+	if code != 0 || !strings.Contains(out.String(), "pulled app-dev") || !strings.Contains(errBuf.String(), "warning: pull made 2 API calls (budget 1); each mapping costs a read") {
+		t.Fatalf("unexpected pull: %d %q %q", code, out.String(), errBuf.String())
+	}
+
+	// Creating a secret counts the create and the version write.
+	out.Reset()
+	errBuf.Reset()
+	code = Run([]string{"dev-vault", "--config", cfgPath, "--strict-warnings", "push", "new-dev", "--create-missing"}, &out, &errBuf, deps)
+	if code != 1 || !strings.Contains(errBuf.String(), "warning: push made 6 API calls (budget 1)") || !strings.HasSuffix(errBuf.String(), "1 warning(s) treated as errors (--strict-warnings)\ninvocation: TESTINVOCATION\n") {
+		t.Fatalf("unexpected push: %d %q %q", code, out.String(), errBuf.String())
+	}
+
+	// Over the duration budget, for a command without a hint.
+	cfgPath = writeConfig(t, root, `{"organization_id":"org","project_id":"proj","region":"fr-par","budget":{"max_duration":"30s"},"mapping":{
+		"app-dev":{"file":"app.bin","format":"raw"}
+	}}`)
+	now := time.Unix(0, 0)
+	slowDeps := deps
+	slowDeps.Now = func() time.Time {
+		now = now.Add(45 * time.Second)
+		return now
+	}
+	out.Reset()
+	errBuf.Reset()
+	code = Run([]string{"dev-vault", "--config", cfgPath, "report"}, &out, &errBuf, slowDeps)
+	if code != 0 || !strings.HasPrefix(errBuf.String(), "warning: report took ") || !strings.HasSuffix(errBuf.String(), " (budget 30s)\n") {
+		t.Fatalf("unexpected report: %d %q %q", code, out.String(), errBuf.String())
+	}
+
+	// A warning that cannot be written fails the command.
+	code = Run([]string{"dev-vault", "--config", cfgPath, "report"}, &out, &failingWriter{}, slowDeps)
+	if code == 0 {
+		t.Fatalf("expected warning write failure, got %d", code)
+	}
+}
@@ -0,0 +1,58 @@
+package cli
+
+import (
+	"runtime"
+	"runtime/debug"
+	"sort"
+)
+
+// providerSDKModules lists the provider SDKs reported in build metadata.
+var providerSDKModules = map[string]bool{
+	"github.com/scaleway/scaleway-sdk-go": true,
+}
+
+var readBuildInfo = debug.ReadBuildInfo
+
+type buildInfo struct {
+	Version      string       `json:"version"`
+	Commit       string       `json:"commit"`
+	Date         string       `json:"date"`
+	GoVersion    string       `json:"go_version"`
+	Platform     string       `json:"platform"`
+	ProviderSDKs []sdkVersion `json:"provider_sdks"`
+}
+
+type sdkVersion struct {
+	Module  string `json:"module"`
+	Version string `json:"version"`
+}
+
+// currentBuildInfo merges the ldflags-injected version with what the Go
+// runtime knows about the binary. SDK versions come from the embedded module
+// list and are empty when the binary was built without it.
+func currentBuildInfo(deps Dependencies) buildInfo {
+	info := buildInfo{
+		Version:      deps.Version,
+		Commit:       deps.Commit,
+		Date:         deps.Date,
+		GoVersion:    runtime.Version(),
+		Platform:     runtime.GOOS + "/" + runtime.GOARCH,
+		ProviderSDKs: []sdkVersion{},
+	}
+	bi, ok := readBuildInfo()
+	if !ok {
+		return info
+	}
+	for _, dep := range bi.Deps {
+		if !providerSDKModules[dep.Path] {
+			continue
+		}
+		version := dep.Version
+		if dep.Replace != nil && dep.Replace.Version != "" {
+			version = dep.Replace.Version
+		}
+		info.ProviderSDKs = append(info.ProviderSDKs, sdkVersion{Module: dep.Path, Version: version})
+	}
+	sort.Slice(info.ProviderSDKs, func(i, j int) bool { return info.ProviderSDKs[i].Module < info.ProviderSDKs[j].Module })
+	return info
+}
@@ -0,0 +1,73 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"testing"
+
+	"github.com/bsmartlabs/dev-vault/internal/config"
+)
+
+func stubBuildInfo(t *testing.T, info *debug.BuildInfo, ok bool) {
+	t.Helper()
+	orig := readBuildInfo
+	readBuildInfo = func() (*debug.BuildInfo, bool) { return info, ok }
+	t.Cleanup(func() { readBuildInfo = orig })
+}
+
+func TestCurrentBuildInfo(t *testing.T) {
+	deps := Dependencies{Version: "v1.2.3", Commit: "abc", Date: "2024-01-02"}
+	stubBuildInfo(t, &debug.BuildInfo{Deps: []*debug.Module{
+		{Path: "golang.org/x/text", Version: "v0.1.0"},
+		{Path: "github.com/scaleway/scaleway-sdk-go", Version: "v0.9.0", Replace: &debug.Module{Path: "../sdk"}},
+		{Path: "github.com/scaleway/scaleway-sdk-go", Version: "v1.0.0", Replace: &debug.Module{Version: "v1.0.1"}},
+	}}, true)
+	info := currentBuildInfo(deps)
+	if info.Version != "v1.2.3" || info.Commit != "abc" || info.Date != "2024-01-02" ||
+		info.GoVersion != runtime.Version() || info.Platform != runtime.GOOS+"/"+runtime.GOARCH {
+		t.Fatalf("unexpected build info: %#v", info)
+	}
+	if len(info.ProviderSDKs) != 2 || info.ProviderSDKs[0].Version != "v0.9.0" || info.ProviderSDKs[1] != (sdkVersion{Module: "github.com/scaleway/scaleway-sdk-go", Version: "v1.0.1"}) {
+		t.Fatalf("unexpected sdks: %#v", info.ProviderSDKs)
+	}
+
+	stubBuildInfo(t, nil, false)
+	if info := currentBuildInfo(deps); info.ProviderSDKs == nil || len(info.ProviderSDKs) != 0 {
+		t.Fatalf("expected empty sdk list without build info, got %#v", info.ProviderSDKs)
+	}
+}
+
+func TestRun_VersionJSON(t *testing.T) {
+	stubBuildInfo(t, &debug.BuildInfo{Deps: []*debug.Module{
+		{Path: "github.com/scaleway/scaleway-sdk-go", Version: "v1.0.0"},
+	}}, true)
+	deps := baseDeps(func(config.Config, string) (SecretAPI, error) { return nil, nil })
+	var out, errBuf bytes.Buffer
+	if code := Run([]string{"dev-vault", "version", "--json"}, &out, &errBuf, deps); code != 0 {
+		t.Fatalf("expected 0, got %d: %s", code, errBuf.String())
+	}
+	var got buildInfo
+	if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+		t.Fatalf("decode: %v\n%s", err, out.String())
+	}
+	if got.Version != deps.Version || got.GoVersion != runtime.Version() || len(got.ProviderSDKs) != 1 || got.ProviderSDKs[0].Version != "v1.0.0" {
+		t.Fatalf("unexpected version json: %#v", got)
+	}
+
+	if code := Run([]string{"dev-vault", "version", "--json"}, &failingWriter{}, &errBuf, deps); code != 1 {
+		t.Fatalf("expected output failure, got %d", code)
+	}
+}
+
+func TestCrashReport_IncludesSDKVersions(t *testing.T) {
+	stubBuildInfo(t, &debug.BuildInfo{Deps: []*debug.Module{
+		{Path: "github.com/scaleway/scaleway-sdk-go", Version: "v1.0.0"},
+	}}, true)
+	report := crashReport(Dependencies{Version: "v", Commit: "c", Date: "d"}, []string{"dev-vault"}, "boom", nil)
+	if !strings.Contains(report, "sdk: github.com/scaleway/scaleway-sdk-go v1.0.0\n") {
+		t.Fatalf("missing sdk line:\n%s", report)
+	}
+}
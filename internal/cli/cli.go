@@ -1,14 +1,22 @@
 package cli
 
 import (
+	"context"
+	"crypto/rand"
 	"errors"
 	"flag"
 	"fmt"
 	"io"
 	"os"
+	"os/signal"
+	"runtime"
+	"runtime/debug"
+	"syscall"
 	"time"
 
+	"github.com/bsmartlabs/dev-vault/internal/ciplatform"
 	"github.com/bsmartlabs/dev-vault/internal/config"
+	"github.com/bsmartlabs/dev-vault/internal/i18n"
 	"github.com/bsmartlabs/dev-vault/internal/secretprovider"
 )
 
@@ -17,110 +25,196 @@ type Dependencies struct {
 	Commit  string
 	Date    string
 
-	OpenSecretAPI func(cfg config.Config, profileOverride string) (secretprovider.SecretAPI, error)
+	OpenSecretAPI  func(cfg config.Config, profileOverride string) (secretprovider.SecretAPI, error)
+	OpenAccountAPI func(profileOverride string) (secretprovider.AccountAPI, error)
 
 	Now      func() time.Time
 	Hostname func() (string, error)
 	Getwd    func() (string, error)
+	Getenv   func(string) string
+
+	UserConfigDir func() (string, error)
+	UserHomeDir   func() (string, error)
+	// Stdin answers confirmation prompts; nil answers none of them.
+	Stdin io.Reader
+	// StdinIsTerminal reports whether Stdin is a terminal. Prompts that stand
+	// in for a flag, such as push --yes, are only asked there.
+	StdinIsTerminal func() bool
+	// InvocationID returns a fresh ID for the run, sent to the provider with
+	// every call and printed when the command fails.
+	InvocationID func() string
+	// SignalContext returns a context cancelled on SIGINT/SIGTERM; batch
+	// commands use it to stop between targets.
+	SignalContext func() (context.Context, context.CancelFunc)
+	// OpenURL opens a page in the user's browser.
+	OpenURL func(rawURL string) error
 }
 
-func DefaultDependencies(version, commit, date string, openSecretAPI func(cfg config.Config, profileOverride string) (secretprovider.SecretAPI, error)) Dependencies {
+func DefaultDependencies(
+	version, commit, date string,
+	openSecretAPI func(cfg config.Config, profileOverride string) (secretprovider.SecretAPI, error),
+	openAccountAPI func(profileOverride string) (secretprovider.AccountAPI, error),
+) Dependencies {
 	return Dependencies{
-		Version:       version,
-		Commit:        commit,
-		Date:          date,
-		OpenSecretAPI: openSecretAPI,
-		Now:           time.Now,
-		Hostname:      os.Hostname,
-		Getwd:         os.Getwd,
+		Version:         version,
+		Commit:          commit,
+		Date:            date,
+		OpenSecretAPI:   openSecretAPI,
+		OpenAccountAPI:  openAccountAPI,
+		Now:             time.Now,
+		Hostname:        os.Hostname,
+		Getwd:           os.Getwd,
+		Getenv:          os.Getenv,
+		UserConfigDir:   os.UserConfigDir,
+		UserHomeDir:     os.UserHomeDir,
+		Stdin:           os.Stdin,
+		StdinIsTerminal: func() bool { return isTerminal(os.Stdin) },
+		InvocationID:    rand.Text,
+		SignalContext:   notifySignalContext,
+		OpenURL: func(rawURL string) error {
+			return openURL(os.Getenv, runtime.GOOS, rawURL, startProcess)
+		},
 	}
 }
 
-func Run(args []string, stdout, stderr io.Writer, deps Dependencies) int {
-	if deps.OpenSecretAPI == nil || deps.Now == nil || deps.Hostname == nil || deps.Getwd == nil {
+func notifySignalContext() (context.Context, context.CancelFunc) {
+	return signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+}
+
+// Run is the CLI entry point. A panic anywhere below it is converted into a
+// local crash log and exit code 1 instead of a raw Go stack trace.
+func Run(args []string, stdout, stderr io.Writer, deps Dependencies) (code int) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			code = reportCrash(stderr, deps, args, recovered, debug.Stack())
+		}
+	}()
+	return dispatch(args, stdout, stderr, deps)
+}
+
+func dispatch(args []string, stdout, stderr io.Writer, deps Dependencies) int {
+	if deps.OpenSecretAPI == nil || deps.OpenAccountAPI == nil || deps.Now == nil || deps.Hostname == nil || deps.Getwd == nil || deps.Getenv == nil || deps.UserConfigDir == nil || deps.UserHomeDir == nil || deps.InvocationID == nil || deps.SignalContext == nil {
 		if _, err := fmt.Fprintln(stderr, "internal error: missing dependencies"); err != nil {
 			return 1
 		}
 		return 1
 	}
+	msg := i18n.New(i18n.FromEnv(deps.Getenv))
 	if len(args) == 0 {
-		if err := printMainUsage(stderr); err != nil {
+		if err := printMainUsage(stderr, msg); err != nil {
 			return 1
 		}
 		return 2
 	}
-	if len(args) > 1 && (args[1] == "-h" || args[1] == "--help") {
-		if err := printMainUsage(stdout); err != nil {
-			return 1
-		}
-		return 0
-	}
-
 	global := flag.NewFlagSet("dev-vault", flag.ContinueOnError)
 	global.SetOutput(stderr)
-	configPath := ""
-	profileOverride := ""
-	bindGlobalOptionFlags(global, &configPath, &profileOverride)
+	opts := globalOptions{}
+	bindGlobalOptionFlags(global, &opts)
 
-	global.Usage = func() {
-		_ = printMainUsage(stderr)
-	}
+	// Usage is printed below: requested help is a result, a parse error is not.
+	global.Usage = func() {}
 
 	if err := global.Parse(args[1:]); err != nil {
 		if errors.Is(err, flag.ErrHelp) {
+			if err := printMainUsage(stdout, msg); err != nil {
+				return 1
+			}
 			return 0
 		}
+		_ = printMainUsage(stderr, msg)
 		return 2
 	}
+	if opts.lang != "" {
+		lang, err := i18n.ParseLang(opts.lang)
+		if err != nil {
+			if _, err := fmt.Fprintln(stderr, msg.Error(err)); err != nil {
+				return 1
+			}
+			return 2
+		}
+		msg = i18n.New(lang)
+	}
 	rest := global.Args()
-	if len(rest) == 0 {
-		if err := printMainUsage(stderr); err != nil {
-			return 1
+	invocation := deps.InvocationID()
+	secretprovider.SetInvocationID(invocation)
+	secretprovider.SetClient(secretprovider.Client{
+		Version: deps.Version,
+		CI:      ciplatform.Running(deps.Getenv),
+		Suffix:  deps.Getenv(secretprovider.UserAgentSuffixEnv),
+	})
+	if opts.logFile != "" {
+		command := "dev-vault"
+		if len(rest) > 0 {
+			command += " " + rest[0]
 		}
-		return 2
+		logged, closeLog, err := openLogFile(stderr, opts.logFile, deps.Now(), command, invocation)
+		if err != nil {
+			if _, err := fmt.Fprintln(stderr, msg.Error(err)); err != nil {
+				return 1
+			}
+			return exitCodeForError(err)
+		}
+		defer closeLog()
+		stderr = logged
 	}
-
-	cmd := rest[0]
 	ctx := commandContext{
 		stdout:          stdout,
 		stderr:          stderr,
-		configPath:      configPath,
-		profileOverride: profileOverride,
+		configPath:      opts.configPath,
+		profileOverride: opts.profileOverride,
+		environment:     opts.environment,
+		lang:            opts.lang,
+		msg:             msg,
+		plain:           opts.plain,
+		explainConfig:   opts.explainConfig,
+		enforceVersion:  opts.enforceVersion,
+		ignoreUnknown:   opts.ignoreUnknown,
+		strictWarnings:  opts.strictWarnings,
+		logFile:         opts.logFile,
+		noFsync:         opts.noFsync,
+		invocation:      invocation,
 		deps:            deps,
 	}
-	switch cmd {
-	case "help":
-		if len(rest) > 1 {
-			usagePrinter, ok := usageForCommand(rest[1])
-			if !ok {
-				if _, err := fmt.Fprintf(stderr, "unknown command for help: %s\n", rest[1]); err != nil {
-					return 1
-				}
-				if err := printMainUsage(stderr); err != nil {
-					return 1
-				}
-				return 2
-			}
-			if err := usagePrinter(stdout); err != nil {
-				return 1
-			}
-			return 0
-		}
-		if err := printMainUsage(stdout); err != nil {
+	if len(rest) == 0 && opts.explainConfig {
+		return runExplainConfig(ctx, &parsedCommand{
+			configPath:      opts.configPath,
+			profileOverride: opts.profileOverride,
+			environment:     opts.environment,
+			msg:             msg,
+			plain:           opts.plain,
+		})
+	}
+	if len(rest) == 0 {
+		if err := printMainUsage(stderr, msg); err != nil {
 			return 1
 		}
-		return 0
+		return 2
+	}
+
+	cmd := rest[0]
+	switch cmd {
+	case "help":
+		return runHelp(rest[1:], stdout, stderr, msg)
 	default:
 		def, ok := commandForName(cmd)
 		if !ok {
-			if _, err := fmt.Fprintf(stderr, "unknown command: %s\n", cmd); err != nil {
+			if _, err := fmt.Fprintln(stderr, msg.Sprintf(i18n.MsgUnknownCommand, cmd)); err != nil {
 				return 1
 			}
-			if err := printMainUsage(stderr); err != nil {
+			if err := printMainUsage(stderr, msg); err != nil {
 				return 1
 			}
 			return 2
 		}
-		return runCommand(ctx, rest[1:], def)
+		if def.Experimental != "" {
+			if err := requireFeature(deps, def); err != nil {
+				_, _ = fmt.Fprintln(stderr, msg.Error(err))
+				return exitCodeForError(err)
+			}
+		}
+		start := deps.Now()
+		code := runCommand(ctx, rest[1:], def)
+		recordTelemetry(deps, def.Name, deps.Now().Sub(start), code)
+		return code
 	}
 }
@@ -2,13 +2,20 @@ package cli
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/bsmartlabs/dev-vault/internal/config"
+	"github.com/bsmartlabs/dev-vault/internal/secretsync"
 	secret "github.com/scaleway/scaleway-sdk-go/api/secret/v1beta1"
 )
 
@@ -19,8 +26,12 @@ func (*failingWriter) Write(p []byte) (int, error) { return 0, errors.New("nope"
 type stubSecretAPI struct {
 	listFn        func(req ListSecretsInput) ([]SecretRecord, error)
 	accessFn      func(req AccessSecretVersionInput) (*SecretVersionRecord, error)
+	listVersions  func(req ListSecretVersionsInput) ([]SecretVersionRecord, error)
 	createSecret  func(req CreateSecretInput) (*SecretRecord, error)
 	createVersion func(req CreateSecretVersionInput) (*SecretVersionRecord, error)
+	deleteSecret  func(req DeleteSecretInput) error
+	deleteVersion func(req DeleteSecretVersionInput) error
+	disable       func(req DisableSecretVersionInput) error
 }
 
 func (s *stubSecretAPI) ListSecrets(req ListSecretsInput) ([]SecretRecord, error) {
@@ -31,6 +42,10 @@ func (s *stubSecretAPI) AccessSecretVersion(req AccessSecretVersionInput) (*Secr
 	return s.accessFn(req)
 }
 
+func (s *stubSecretAPI) ListSecretVersions(req ListSecretVersionsInput) ([]SecretVersionRecord, error) {
+	return s.listVersions(req)
+}
+
 func (s *stubSecretAPI) CreateSecret(req CreateSecretInput) (*SecretRecord, error) {
 	return s.createSecret(req)
 }
@@ -39,6 +54,18 @@ func (s *stubSecretAPI) CreateSecretVersion(req CreateSecretVersionInput) (*Secr
 	return s.createVersion(req)
 }
 
+func (s *stubSecretAPI) DeleteSecret(req DeleteSecretInput) error {
+	return s.deleteSecret(req)
+}
+
+func (s *stubSecretAPI) DeleteSecretVersion(req DeleteSecretVersionInput) error {
+	return s.deleteVersion(req)
+}
+
+func (s *stubSecretAPI) DisableSecretVersion(req DisableSecretVersionInput) error {
+	return s.disable(req)
+}
+
 func TestRunList_MoreBranches(t *testing.T) {
 	t.Run("ParseError", func(t *testing.T) {
 		var out, errBuf bytes.Buffer
@@ -202,6 +229,23 @@ func TestRunPull_RawAndErrors(t *testing.T) {
 		}
 	})
 
+	t.Run("Mtime", func(t *testing.T) {
+		// The file gets the version's creation time, not the secret's update.
+		sec.UpdatedAt = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+		createdAt := time.Date(2026, 1, 1, 1, 0, 0, 0, time.UTC)
+		var out, errBuf bytes.Buffer
+		if code := Run([]string{"dev-vault", "--config", cfgPath, "pull", "foo-dev", "--overwrite", "--mtime", "remote"}, &out, &errBuf, deps); code != 0 {
+			t.Fatalf("expected 0, got %d (%s)", code, errBuf.String())
+		}
+		if info, err := os.Stat(filepath.Join(root, "out.bin")); err != nil || !info.ModTime().Equal(createdAt) {
+			t.Fatalf("expected remote mtime, got %v %v", info, err)
+		}
+		errBuf.Reset()
+		if code := Run([]string{"dev-vault", "--config", cfgPath, "pull", "foo-dev", "--overwrite", "--mtime", "touch"}, &out, &errBuf, deps); code != 2 || !strings.Contains(errBuf.String(), `invalid --mtime: invalid mtime "touch"`) {
+			t.Fatalf("expected usage error, got %d %q", code, errBuf.String())
+		}
+	})
+
 	t.Run("WriteError", func(t *testing.T) {
 		// Make parent a file so AtomicWriteFile fails with mkdirall error.
 		if err := os.WriteFile(filepath.Join(root, "notadir"), []byte("x"), 0o644); err != nil {
@@ -332,3 +376,480 @@ func TestRunPull_DotenvSuccess(t *testing.T) {
 		t.Fatalf("unexpected dotenv file: %q", string(got))
 	}
 }
+
+func TestRunPull_Revision(t *testing.T) {
+	root := t.TempDir()
+	cfgPath := writeConfig(t, root, `{"organization_id":"org","project_id":"proj","region":"fr-par","mapping":{
+		"a-dev":{"file":"a.bin"},
+		"b-dev":{"file":"b.bin","revision":1}
+	}}`)
+	api := newFakeSecretAPI()
+	for _, name := range []string{"a-dev", "b-dev"} {
+		sec := api.AddSecret("proj", name, "/", secret.SecretTypeOpaque)
+		api.AddEnabledVersion(sec.ID, []byte(name+"-one"))
+		api.AddEnabledVersion(sec.ID, []byte(name+"-two"))
+	}
+	deps := baseDeps(func(cfg config.Config, s string) (SecretAPI, error) { return api, nil })
+	run := func(args ...string) (int, string, string) {
+		var out, errBuf bytes.Buffer
+		code := Run(append([]string{"dev-vault", "--config", cfgPath, "pull", "--overwrite"}, args...), &out, &errBuf, deps)
+		return code, out.String(), errBuf.String()
+	}
+
+	// mapping.revision pins b-dev; --revision overrides it.
+	if code, out, errOut := run("--all"); code != 0 || !strings.Contains(out, "pulled a-dev -> a.bin (rev=2 ") || !strings.Contains(out, "pulled b-dev -> b.bin (rev=1 ") {
+		t.Fatalf("unexpected pinned pull: %d %q %q", code, out, errOut)
+	}
+	if code, out, errOut := run("b-dev", "--revision", "2"); code != 0 || !strings.Contains(out, "pulled b-dev -> b.bin (rev=2 ") {
+		t.Fatalf("unexpected revision pull: %d %q %q", code, out, errOut)
+	}
+	if got, _ := os.ReadFile(filepath.Join(root, "b.bin")); string(got) != "b-dev-two" {
+		t.Fatalf("unexpected file %q", got)
+	}
+
+	cases := []struct {
+		name     string
+		args     []string
+		wantCode int
+		wantSub  string
+	}{
+		{"NotANumber", []string{"a-dev", "--revision", "x"}, 2, `invalid --revision "x": want a positive revision number`},
+		{"Zero", []string{"a-dev", "--revision", "0"}, 2, `invalid --revision "0"`},
+		{"All", []string{"--all", "--revision", "1"}, 2, "--revision cannot be combined with --all: a revision belongs to one secret; name it instead"},
+		{"Several", []string{"a-dev", "b-dev", "--revision", "1"}, 2, "--revision needs exactly one <secret-dev>"},
+		{"Missing", []string{"a-dev", "--revision", "5"}, 1, "access a-dev: revision 5 does not exist"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if code, _, errOut := run(tc.args...); code != tc.wantCode || !strings.Contains(errOut, tc.wantSub) {
+				t.Fatalf("unexpected result: %d %q", code, errOut)
+			}
+		})
+	}
+}
+
+func TestRunPull_Fake(t *testing.T) {
+	root := t.TempDir()
+	cfgPath := writeConfig(t, root, `{"organization_id":"org","project_id":"proj","region":"fr-par","mapping":{
+		"app-env-dev":{"file":".env","format":"dotenv","fake_keys":["DATABASE_URL"]},
+		"bare-env-dev":{"file":"bare.env","format":"dotenv"},
+		"cert-dev":{"file":"cert.pem"}
+	}}`)
+	// The provider is never opened, so contributors need no credentials.
+	deps := baseDeps(func(config.Config, string) (SecretAPI, error) { return nil, errors.New("no credentials") })
+	run := func(args ...string) (int, string, string) {
+		var out, errBuf bytes.Buffer
+		code := Run(append([]string{"dev-vault", "--config", cfgPath, "pull", "--fake"}, args...), &out, &errBuf, deps)
+		return code, out.String(), errBuf.String()
+	}
+
+	if code, out, errOut := run("--all"); code != 0 || out != "pulled app-env-dev -> .env (fake values)\n" {
+		t.Fatalf("unexpected fake pull: %d %q %q", code, out, errOut)
+	}
+	if got, _ := os.ReadFile(filepath.Join(root, ".env")); !strings.Contains(string(got), `DATABASE_URL="fake-database-url-`) {
+		t.Fatalf("unexpected fake file %q", got)
+	}
+	if code, _, errOut := run("app-env-dev"); code != 1 || !strings.Contains(errOut, "pull app-env-dev: file exists (use --overwrite)") {
+		t.Fatalf("expected exists error, got %d %q", code, errOut)
+	}
+
+	cases := map[string][]string{
+		"--fake cannot be combined with --ci-export":                           {"--all", "--ci-export"},
+		"--fake cannot be combined with --revision":                            {"app-env-dev", "--revision", "1"},
+		"--fake cannot be combined with --watch":                               {"--all", "--watch"},
+		"pull --fake cert-dev: mapping format must be dotenv":                  {"cert-dev"},
+		"fake bare-env-dev: no fake_keys in the mapping or its compose layers": {"bare-env-dev"},
+		"refusing non-dev secret name: prod":                                   {"prod"},
+	}
+	for want, args := range cases {
+		if code, _, errOut := run(args...); code == 0 || !strings.Contains(errOut, want) {
+			t.Fatalf("%v: expected %q, got %d %q", args, want, code, errOut)
+		}
+	}
+
+	rawOnly := writeConfig(t, t.TempDir(), `{"organization_id":"org","project_id":"proj","region":"fr-par","mapping":{"cert-dev":{"file":"cert.pem"}}}`)
+	var errBuf bytes.Buffer
+	if code := Run([]string{"dev-vault", "--config", rawOnly, "pull", "--fake", "--all"}, &bytes.Buffer{}, &errBuf, deps); code != 2 || !strings.Contains(errBuf.String(), "no dotenv mappings with fake_keys selected") {
+		t.Fatalf("expected nothing selected, got %d %q", code, errBuf.String())
+	}
+
+	ctx := commandContext{stdout: &failingWriter{}, stderr: &bytes.Buffer{}, configPath: cfgPath, deps: deps}
+	if code := runPull(ctx, []string{"--fake", "--all", "--overwrite"}); code != 1 {
+		t.Fatalf("expected output error, got %d", code)
+	}
+}
+
+func TestRunPull_FetchesSharedSecretOnce(t *testing.T) {
+	root := t.TempDir()
+	cfgPath := writeConfig(t, root, `{"organization_id":"org","project_id":"proj","region":"fr-par","mapping":{
+		"base-env-dev":{"file":"base.env","format":"dotenv"},
+		"web-env-dev":{"file":"web.env","format":"dotenv","compose":["base-env-dev"]},
+		"api-env-dev":{"file":"api.env","format":"dotenv","compose":["base-env-dev"]}
+	}}`)
+
+	api := newFakeSecretAPI()
+	for _, name := range []string{"base-env-dev", "web-env-dev", "api-env-dev"} {
+		sec := api.AddSecret("proj", name, "/", secret.SecretTypeKeyValue)
+		api.AddEnabledVersion(sec.ID, []byte(`{"A":"1"}`))
+	}
+	deps := baseDeps(func(cfg config.Config, s string) (SecretAPI, error) { return api, nil })
+
+	var out, errBuf bytes.Buffer
+	if code := Run([]string{"dev-vault", "--config", cfgPath, "pull", "--all"}, &out, &errBuf, deps); code != 0 {
+		t.Fatalf("expected 0, got %d (%s)", code, errBuf.String())
+	}
+	if api.accessCalls != 3 {
+		t.Fatalf("expected one access per secret, got %d", api.accessCalls)
+	}
+}
+
+func TestRun_BatchInterrupted(t *testing.T) {
+	root := t.TempDir()
+	cfgPath := writeConfig(t, root, `{"organization_id":"org","project_id":"proj","region":"fr-par","mapping":{
+		"a-dev":{"file":"a.bin","format":"raw"},
+		"b-dev":{"file":"b.bin","format":"raw"}
+	}}`)
+	for _, name := range []string{"a.bin", "b.bin"} {
+		if err := os.WriteFile(filepath.Join(root, name), []byte("DATA"), 0o600); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+	api := newFakeSecretAPI()
+	api.AddSecret("proj", "a-dev", "/", secret.SecretTypeOpaque)
+	api.AddSecret("proj", "b-dev", "/", secret.SecretTypeOpaque)
+	deps := baseDeps(func(cfg config.Config, s string) (SecretAPI, error) { return api, nil })
+	// The stop func also runs from the context.AfterFunc goroutine.
+	var stopped atomic.Int32
+	deps.SignalContext = func() (context.Context, context.CancelFunc) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		return ctx, func() { stopped.Add(1) }
+	}
+
+	var out, errBuf bytes.Buffer
+	code := Run([]string{"dev-vault", "--config", cfgPath, "push", "--all", "--yes"}, &out, &errBuf, deps)
+	if code != 130 {
+		t.Fatalf("expected 130, got %d stderr=%s", code, errBuf.String())
+	}
+	if !strings.Contains(errBuf.String(), "interrupted: push completed 0/2; aborted: a-dev, b-dev") {
+		t.Fatalf("unexpected stderr: %q", errBuf.String())
+	}
+	if len(api.versions["sec-1"]) != 0 || len(api.versions["sec-2"]) != 0 {
+		t.Fatalf("expected no versions to be created")
+	}
+	if stopped.Load() == 0 {
+		t.Fatalf("expected signal handling to be released")
+	}
+
+	errBuf.Reset()
+	code = Run([]string{"dev-vault", "--config", cfgPath, "pull", "a-dev"}, &out, &errBuf, deps)
+	if code != 130 || !strings.Contains(errBuf.String(), "interrupted: pull completed 0/1; aborted: a-dev") {
+		t.Fatalf("expected interrupted pull, got %d stderr=%s", code, errBuf.String())
+	}
+}
+
+// hookWriter records what is written and lets a test react to each write,
+// or fail it, from the goroutine of the command writing.
+type hookWriter struct {
+	bytes.Buffer
+	onWrite func(text string) error
+}
+
+func (w *hookWriter) Write(p []byte) (int, error) {
+	if err := w.onWrite(string(p)); err != nil {
+		return 0, err
+	}
+	return w.Buffer.Write(p)
+}
+
+func TestRunPull_Watch(t *testing.T) {
+	root := t.TempDir()
+	cfgPath := writeConfig(t, root, `{"organization_id":"org","project_id":"proj","region":"fr-par","mapping":{
+		"a-dev":{"file":"a.bin","format":"raw"},
+		"b-dev":{"file":"b.bin","format":"raw"}
+	}}`)
+	api := newFakeSecretAPI()
+	a := api.AddSecret("proj", "a-dev", "/", secret.SecretTypeOpaque)
+	api.AddEnabledVersion(a.ID, []byte("A1"))
+	b := api.AddSecret("proj", "b-dev", "/", secret.SecretTypeOpaque)
+	api.AddEnabledVersion(b.ID, []byte("B1"))
+	deps := baseDeps(func(config.Config, string) (SecretAPI, error) { return api, nil })
+	deps.Now = func() time.Time { return time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC) }
+	state := t.TempDir()
+	deps.UserConfigDir = func() (string, error) { return state, nil }
+	signalCtx, cancel := context.WithCancel(context.Background())
+	deps.SignalContext = func() (context.Context, context.CancelFunc) { return signalCtx, cancel }
+	loaded := &config.Loaded{Path: cfgPath}
+
+	// A failed poll is retried; the new version of a-dev is then pulled and
+	// the test stops the watch like Ctrl-C would.
+	stdout := &hookWriter{onWrite: func(text string) error {
+		if strings.Contains(text, "pulled a-dev -> a.bin (rev=2") {
+			cancel()
+		}
+		return nil
+	}}
+	stderr := &hookWriter{}
+	stderr.onWrite = func(text string) error {
+		switch {
+		case strings.HasPrefix(text, "watching 2 secret(s) every 1ms"):
+			api.accessErr = errors.New("offline")
+		case strings.Contains(text, "warning: access a-dev: offline; retrying in 1ms"):
+			if check, ok := lastRemoteCheck(deps, loaded); !ok || check.Reachable {
+				t.Errorf("expected the failed poll saved for prompt, got %+v %v", check, ok)
+			}
+			api.accessErr = nil
+			api.AddEnabledVersion(a.ID, []byte("A2"))
+		}
+		return nil
+	}
+	code := Run([]string{"dev-vault", "--config", cfgPath, "pull", "--all", "--watch", "--interval", "1ms"}, stdout, stderr, deps)
+	if code != 130 || !strings.Contains(stderr.String(), "pull --watch stopped: interrupted") {
+		t.Fatalf("expected the watch to stop with 130, got %d %q", code, stderr.String())
+	}
+	wantOut := "pulled a-dev -> a.bin (rev=1 type=opaque)\npulled b-dev -> b.bin (rev=1 type=opaque)\n2026-01-02T03:04:05Z pulled a-dev -> a.bin (rev=2 type=opaque)\n"
+	if stdout.String() != wantOut {
+		t.Fatalf("unexpected output:\n%s", stdout.String())
+	}
+	if got, _ := os.ReadFile(filepath.Join(root, "a.bin")); string(got) != "A2" {
+		t.Fatalf("expected a.bin rewritten, got %q", got)
+	}
+	if check, ok := lastRemoteCheck(deps, loaded); !ok || !check.Reachable || len(check.Drifted) != 0 {
+		t.Fatalf("expected the last poll saved without drift, got %+v %v", check, ok)
+	}
+	if names := notPulled([]secretsync.MappingTarget{{Name: "a-dev"}, {Name: "b-dev"}}, []secretsync.PullResult{{Name: "a-dev"}}); len(names) != 1 || names[0] != "b-dev" {
+		t.Fatalf("unexpected drift: %v", names)
+	}
+
+	deps.SignalContext = func() (context.Context, context.CancelFunc) { return context.WithCancel(context.Background()) }
+	run := func(stdout, stderr io.Writer, args ...string) int {
+		return Run(append([]string{"dev-vault", "--config", cfgPath, "pull", "--overwrite"}, args...), stdout, stderr, deps)
+	}
+	for want, args := range map[string][]string{
+		"--interval requires --watch: it sets how often --watch polls":                            {"a-dev", "--interval", "1s"},
+		`invalid --interval: invalid age "soon"`:                                                  {"a-dev", "--watch", "--interval", "soon"},
+		"--watch cannot be combined with --revision: --watch follows the latest enabled revision": {"a-dev", "--watch", "--revision", "1"},
+	} {
+		var errBuf bytes.Buffer
+		if code := run(&bytes.Buffer{}, &errBuf, args...); code != 2 || !strings.Contains(errBuf.String(), want) {
+			t.Fatalf("%v: expected %q, got %d %q", args, want, code, errBuf.String())
+		}
+	}
+
+	// The start line, a warning, and an update line cannot be written.
+	if code := run(&bytes.Buffer{}, &failingWriter{}, "a-dev", "--watch"); code != 1 {
+		t.Fatalf("expected start line failure, got %d", code)
+	}
+	failPoll := &hookWriter{onWrite: func(string) error {
+		api.accessErr = errors.New("offline")
+		return nil
+	}}
+	if code := run(failPoll, &failAfterWriter{okWrites: 1}, "a-dev", "--watch", "--interval", "1ms"); code != 1 {
+		t.Fatalf("expected warning failure, got %d", code)
+	}
+	api.accessErr = nil
+	writes := 0
+	failUpdate := &hookWriter{onWrite: func(string) error {
+		if writes++; writes == 1 {
+			api.AddEnabledVersion(a.ID, []byte("A3"))
+			return nil
+		}
+		return errors.New("closed")
+	}}
+	if code := run(failUpdate, &bytes.Buffer{}, "a-dev", "--watch", "--interval", "1ms"); code != 1 {
+		t.Fatalf("expected update line failure, got %d", code)
+	}
+}
+
+func TestRunPull_DryRun(t *testing.T) {
+	root := t.TempDir()
+	cfgPath := writeConfig(t, root, `{"organization_id":"org","project_id":"proj","region":"fr-par","mapping":{
+		"a-dev":{"file":"a.bin","format":"raw"},
+		"b-env-dev":{"file":"b.env","format":"dotenv"}
+	}}`)
+	api := newFakeSecretAPI()
+	a := api.AddSecret("proj", "a-dev", "/", secret.SecretTypeOpaque)
+	api.AddEnabledVersion(a.ID, []byte("A"))
+	b := api.AddSecret("proj", "b-env-dev", "/", secret.SecretTypeKeyValue)
+	api.AddEnabledVersion(b.ID, []byte(`{"K":"v"}`))
+	deps := baseDeps(func(config.Config, string) (SecretAPI, error) { return api, nil })
+	userDir := t.TempDir()
+	deps.UserConfigDir = func() (string, error) { return userDir, nil }
+	run := func(args ...string) (int, string, string) {
+		var out, errBuf bytes.Buffer
+		code := Run(append([]string{"dev-vault", "--config", cfgPath, "pull", "--dry-run"}, args...), &out, &errBuf, deps)
+		return code, out.String(), errBuf.String()
+	}
+
+	code, out, errOut := run("--all")
+	if code != 0 || out != "would pull a-dev -> a.bin (rev=1 type=opaque file=create)\nwould pull b-env-dev -> b.env (rev=1 type=key_value file=create)\n" {
+		t.Fatalf("unexpected dry run: %d %q %q", code, out, errOut)
+	}
+	if entries, _ := os.ReadDir(root); len(entries) != 1 {
+		t.Fatalf("expected only the manifest, got %d entries", len(entries))
+	}
+	if _, err := os.Stat(filepath.Join(userDir, stateDirName, "progress")); !os.IsNotExist(err) {
+		t.Fatalf("expected no progress recorded, got %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(root, "a.bin"), []byte("A"), 0o600); err != nil {
+		t.Fatalf("write a.bin: %v", err)
+	}
+	if code, _, errOut := run("a-dev"); code != 1 || !strings.Contains(errOut, "pull a-dev: file exists (use --overwrite)") {
+		t.Fatalf("expected the exists error, got %d %q", code, errOut)
+	}
+	if code, out, errOut := run("a-dev", "--overwrite"); code != 0 || out != "would pull a-dev -> a.bin (rev=1 type=opaque file=unchanged)\n" {
+		t.Fatalf("unexpected dry run: %d %q %q", code, out, errOut)
+	}
+
+	for _, args := range [][]string{{"--all", "--watch"}, {"--all", "--ci-export"}} {
+		if code, _, errOut := run(args...); code != 2 || !strings.Contains(errOut, "--dry-run cannot be combined with "+args[1]+": a dry run writes nothing to watch or export") {
+			t.Fatalf("%v: expected a usage error, got %d %q", args, code, errOut)
+		}
+	}
+	if code, _, errOut := run("--all", "--fake"); code != 2 || !strings.Contains(errOut, "--fake cannot be combined with --dry-run") {
+		t.Fatalf("expected a usage error, got %d %q", code, errOut)
+	}
+}
+
+func TestRunPull_ExpireAfter(t *testing.T) {
+	root := t.TempDir()
+	manifest := `{"organization_id":"org","project_id":"proj","region":"fr-par",%s"mapping":{
+		"a-dev":{"file":"a.bin","format":"raw"},
+		"b-dev":{"file":"b.bin","format":"raw"}
+	}}`
+	cfgPath := writeConfig(t, root, fmt.Sprintf(manifest, `"expire_after":"8h",`))
+	api := newFakeSecretAPI()
+	a := api.AddSecret("proj", "a-dev", "/", secret.SecretTypeOpaque)
+	api.AddEnabledVersion(a.ID, []byte("A1"))
+	b := api.AddSecret("proj", "b-dev", "/", secret.SecretTypeOpaque)
+	api.AddEnabledVersion(b.ID, []byte("B1"))
+	deps := baseDeps(func(config.Config, string) (SecretAPI, error) { return api, nil })
+	now := time.Now()
+	deps.Now = func() time.Time { return now }
+	userDir := t.TempDir()
+	deps.UserConfigDir = func() (string, error) { return userDir, nil }
+	run := func(stdout, stderr io.Writer, args ...string) int {
+		return Run(append([]string{"dev-vault", "--config", cfgPath}, args...), stdout, stderr, deps)
+	}
+	output := func(args ...string) string {
+		t.Helper()
+		var out, errBuf bytes.Buffer
+		if code := run(&out, &errBuf, args...); code != 0 {
+			t.Fatalf("%v: expected success, got %d %q", args, code, errBuf.String())
+		}
+		return out.String()
+	}
+
+	output("pull", "--all")
+	if got := output("prompt"); got != "vault 2/2\n" {
+		t.Fatalf("expected nothing expired yet, got %q", got)
+	}
+	now = now.Add(9 * time.Hour)
+	if got := output("prompt"); got != "vault 2/2 (2 expired)\n" {
+		t.Fatalf("expected both files expired, got %q", got)
+	}
+	var stale []staleRecord
+	if err := json.Unmarshal([]byte(output("list", "--stale", "--older-than", "30d", "--json")), &stale); err != nil || len(stale) != 2 || !stale[0].Expired || !stale[1].Expired {
+		t.Fatalf("expected both files stale by expiry, got %+v %v", stale, err)
+	}
+
+	// The flag overrides the manifest, and a pull without either drops the
+	// expiry of the files it writes.
+	output("pull", "a-dev", "--overwrite", "--expire-after", "1h")
+	if got := output("list", "--stale", "--older-than", "30d"); !strings.Contains(got, "b-dev") || !strings.Contains(got, " (expired)") || strings.Contains(got, "a-dev") {
+		t.Fatalf("expected only b-dev expired, got %q", got)
+	}
+	writeConfig(t, root, fmt.Sprintf(manifest, ""))
+	output("pull", "b-dev", "--overwrite")
+	now = now.Add(2 * time.Hour)
+	if got := output("prompt"); got != "vault 2/2 (1 expired)\n" {
+		t.Fatalf("expected only a-dev expired, got %q", got)
+	}
+
+	var errBuf bytes.Buffer
+	if code := run(&bytes.Buffer{}, &errBuf, "pull", "a-dev", "--overwrite", "--expire-after", "soon"); code != 2 || !strings.Contains(errBuf.String(), `invalid --expire-after: invalid age "soon"`) {
+		t.Fatalf("expected a usage error, got %d %q", code, errBuf.String())
+	}
+
+	// Without a state dir the files are still pulled, with a warning.
+	deps.UserConfigDir = func() (string, error) { return "", errors.New("no config dir") }
+	errBuf.Reset()
+	if code := run(&bytes.Buffer{}, &errBuf, "pull", "a-dev", "--overwrite", "--expire-after", "1h"); code != 0 || !strings.Contains(errBuf.String(), "warning: expiry of the pulled files not recorded") {
+		t.Fatalf("expected a warning, got %d %q", code, errBuf.String())
+	}
+	if code := run(&bytes.Buffer{}, &failingWriter{}, "pull", "a-dev", "--overwrite", "--expire-after", "1h"); code != 1 {
+		t.Fatalf("expected warning failure, got %d", code)
+	}
+}
+
+func TestRunPull_WatchExpiry(t *testing.T) {
+	root := t.TempDir()
+	cfgPath := writeConfig(t, root, `{"organization_id":"org","project_id":"proj","region":"fr-par","mapping":{
+		"a-dev":{"file":"a.bin","format":"raw"},
+		"b-dev":{"file":"b.bin","format":"raw"}
+	}}`)
+	api := newFakeSecretAPI()
+	a := api.AddSecret("proj", "a-dev", "/", secret.SecretTypeOpaque)
+	api.AddEnabledVersion(a.ID, []byte("A1"))
+	b := api.AddSecret("proj", "b-dev", "/", secret.SecretTypeOpaque)
+	api.AddEnabledVersion(b.ID, []byte("B1"))
+	deps := baseDeps(func(config.Config, string) (SecretAPI, error) { return api, nil })
+	now := time.Now()
+	deps.Now = func() time.Time { return now }
+	userDir := t.TempDir()
+	deps.UserConfigDir = func() (string, error) { return userDir, nil }
+	signalCtx, cancel := context.WithCancel(context.Background())
+	deps.SignalContext = func() (context.Context, context.CancelFunc) { return signalCtx, cancel }
+
+	// Both files expire during the watch and are flagged once; a-dev then
+	// gets a new version, which pulls it again with a new expiry.
+	stdout := &hookWriter{onWrite: func(text string) error {
+		if strings.Contains(text, "pulled a-dev -> a.bin (rev=2") {
+			cancel()
+		}
+		return nil
+	}}
+	stderr := &hookWriter{}
+	stderr.onWrite = func(text string) error {
+		switch {
+		case strings.HasPrefix(text, "watching 2 secret(s)"):
+			now = now.Add(2 * time.Hour)
+		case strings.Contains(text, "b.bin (b-dev) has expired"):
+			api.AddEnabledVersion(a.ID, []byte("A2"))
+		}
+		return nil
+	}
+	code := Run([]string{"dev-vault", "--config", cfgPath, "pull", "--all", "--watch", "--interval", "1ms", "--expire-after", "1h"}, stdout, stderr, deps)
+	if code != 130 {
+		t.Fatalf("expected the watch to stop with 130, got %d %q", code, stderr.String())
+	}
+	for _, warning := range []string{"warning: a.bin (a-dev) has expired; pull it again or delete it", "warning: b.bin (b-dev) has expired"} {
+		if n := strings.Count(stderr.String(), warning); n != 1 {
+			t.Fatalf("expected %q once, got %d in %q", warning, n, stderr.String())
+		}
+	}
+
+	deps.SignalContext = func() (context.Context, context.CancelFunc) { return context.WithCancel(context.Background()) }
+	run := func(stdout, stderr io.Writer) int {
+		return Run([]string{"dev-vault", "--config", cfgPath, "pull", "a-dev", "--overwrite", "--watch", "--interval", "1ms", "--expire-after", "1h"}, stdout, stderr, deps)
+	}
+	// The expiry warning cannot be written.
+	deps.Now = func() time.Time {
+		now = now.Add(time.Hour)
+		return now
+	}
+	if code := run(&bytes.Buffer{}, &failAfterWriter{okWrites: 1}); code != 1 {
+		t.Fatalf("expected expiry warning failure, got %d", code)
+	}
+	// Nor can the warning that the expiry of an update was not recorded.
+	deps.Now = func() time.Time { return now }
+	deps.UserConfigDir = func() (string, error) { return "", errors.New("no config dir") }
+	update := &hookWriter{onWrite: func(string) error {
+		api.AddEnabledVersion(a.ID, []byte("A3"))
+		return nil
+	}}
+	if code := run(update, &failAfterWriter{okWrites: 2}); code != 1 {
+		t.Fatalf("expected record warning failure, got %d", code)
+	}
+}
@@ -2,8 +2,11 @@ package cli
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"reflect"
@@ -12,6 +15,7 @@ import (
 	"time"
 
 	"github.com/bsmartlabs/dev-vault/internal/config"
+	"github.com/bsmartlabs/dev-vault/internal/secretprovider"
 	secret "github.com/scaleway/scaleway-sdk-go/api/secret/v1beta1"
 )
 
@@ -349,6 +353,14 @@ func TestRunPush_DefaultDescriptionAndHostnameErrorAndVersionError(t *testing.T)
 		Now:           func() time.Time { return time.Unix(0, 0) },
 		Hostname:      func() (string, error) { return "", errors.New("nope") },
 		Getwd:         os.Getwd,
+		Getenv:        func(string) string { return "" },
+		OpenAccountAPI: func(string) (AccountAPI, error) {
+			return &fakeAccountAPI{}, nil
+		},
+		UserConfigDir: func() (string, error) { return "", errors.New("no config dir") },
+		UserHomeDir:   func() (string, error) { return "", errors.New("no home dir") },
+		InvocationID:  func() string { return "TESTINVOCATION" },
+		SignalContext: func() (context.Context, context.CancelFunc) { return context.WithCancel(context.Background()) },
 	}
 
 	api.createVerErr = errors.New("boom")
@@ -617,15 +629,13 @@ func TestRunPush_CreateMissing_ResolveStillFails(t *testing.T) {
 	if err := os.WriteFile(filepath.Join(root, "in.bin"), []byte("DATA"), 0o644); err != nil {
 		t.Fatalf("write in.bin: %v", err)
 	}
-	api := newFakeSecretAPI()
 	// Override CreateSecret to succeed but not persist, forcing resolve to still fail.
-	api2 := *api
-	api2.createSecretErr = nil
+	api2 := newFakeSecretAPI()
 	deps := baseDeps(func(cfg config.Config, s string) (SecretAPI, error) {
-		return &api2, nil
+		return api2, nil
 	})
 	// Monkey patch method by embedding a wrapper.
-	wrapped := &createSecretNoPersist{inner: &api2}
+	wrapped := &createSecretNoPersist{inner: api2}
 	deps.OpenSecretAPI = func(cfg config.Config, s string) (SecretAPI, error) { return wrapped, nil }
 
 	var out, errBuf bytes.Buffer
@@ -634,3 +644,216 @@ func TestRunPush_CreateMissing_ResolveStillFails(t *testing.T) {
 		t.Fatalf("expected 1, got %d", code)
 	}
 }
+
+func TestRunPush_ReadOnlyCredentials(t *testing.T) {
+	root := t.TempDir()
+	cfgPath := writeConfig(t, root, `{"organization_id":"org","project_id":"proj","region":"fr-par","profile":"ci","mapping":{
+		"a-dev":{"file":"a.bin"},
+		"b-dev":{"file":"b.bin"}
+	}}`)
+	api := newFakeSecretAPI()
+	for _, name := range []string{"a", "b"} {
+		api.AddSecret("proj", name+"-dev", "/", secret.SecretTypeOpaque)
+		if err := os.WriteFile(filepath.Join(root, name+".bin"), []byte("X"), 0o600); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+	api.createVerErr = fmt.Errorf("%w: 403", secretprovider.ErrPermissionDenied)
+	deps := baseDeps(func(config.Config, string) (SecretAPI, error) { return api, nil })
+
+	for _, tc := range []struct {
+		args   []string
+		source string
+	}{
+		{[]string{"push", "--all", "--yes"}, `profile "ci"`},
+		{[]string{"--profile", "reader", "push", "a-dev"}, `profile "reader"`},
+	} {
+		var out, errBuf bytes.Buffer
+		code := Run(append([]string{"dev-vault", "--config", cfgPath}, tc.args...), &out, &errBuf, deps)
+		want := "read-only credentials: push a-dev: create version: permission denied: 403\nhint: the credentials from " + tc.source + " can read secrets but not write them in project proj;"
+		if code != 1 || out.Len() != 0 || !strings.HasPrefix(errBuf.String(), want) {
+			t.Fatalf("%v: unexpected result: %d %q %q", tc.args, code, out.String(), errBuf.String())
+		}
+	}
+
+	envOnly := writeConfig(t, root, `{"organization_id":"org","project_id":"proj","region":"fr-par","mapping":{"a-dev":{"file":"a.bin"}}}`)
+	var errBuf bytes.Buffer
+	if code := Run([]string{"dev-vault", "--config", envOnly, "push", "a-dev"}, &bytes.Buffer{}, &errBuf, deps); code != 1 || !strings.Contains(errBuf.String(), "credentials from SCW_ACCESS_KEY/SCW_SECRET_KEY") {
+		t.Fatalf("unexpected env credentials hint: %d %q", code, errBuf.String())
+	}
+}
+
+func TestRunPush_Retention(t *testing.T) {
+	root := t.TempDir()
+	cfgPath := writeConfig(t, root, `{"organization_id":"org","project_id":"proj","region":"fr-par","mapping":{"foo-dev":{"file":"in.bin","format":"raw","path":"/","mode":"sync","type":"opaque"}}}`)
+	if err := os.WriteFile(filepath.Join(root, "in.bin"), []byte("A"), 0o644); err != nil {
+		t.Fatalf("write in.bin: %v", err)
+	}
+	api := newFakeSecretAPI()
+	foo := api.AddSecret("proj", "foo-dev", "/", secret.SecretTypeOpaque)
+	for range 3 {
+		api.AddEnabledVersion(foo.ID, []byte("old"))
+	}
+	deps := baseDeps(func(cfg config.Config, s string) (SecretAPI, error) { return api, nil })
+	// The fake creates rev=N at N o'clock on 2026-01-01.
+	deps.Now = func() time.Time { return time.Date(2026, 1, 1, 2, 30, 0, 0, time.UTC) }
+	enabled := func() []uint32 {
+		var out []uint32
+		for _, v := range api.versions[foo.ID] {
+			if v.enabled {
+				out = append(out, v.revision)
+			}
+		}
+		return out
+	}
+
+	var out, errBuf bytes.Buffer
+	if code := Run([]string{"dev-vault", "--config", cfgPath, "push", "foo-dev", "--disable-older-than", "1h"}, &out, &errBuf, deps); code != 0 {
+		t.Fatalf("push: %d (%s)", code, errBuf.String())
+	}
+	if out.String() != "pushed foo-dev (rev=4)\ndisabled foo-dev (rev=1)\n" || !reflect.DeepEqual(enabled(), []uint32{2, 3, 4}) {
+		t.Fatalf("unexpected retention: %q %v", out.String(), enabled())
+	}
+
+	out.Reset()
+	if code := Run([]string{"dev-vault", "--config", cfgPath, "push", "foo-dev", "--keep-enabled", "2"}, &out, &errBuf, deps); code != 0 {
+		t.Fatalf("push: %d (%s)", code, errBuf.String())
+	}
+	if out.String() != "pushed foo-dev (rev=5)\ndisabled foo-dev (rev=3)\ndisabled foo-dev (rev=2)\n" || !reflect.DeepEqual(enabled(), []uint32{4, 5}) {
+		t.Fatalf("unexpected retention: %q %v", out.String(), enabled())
+	}
+
+	for _, args := range [][]string{{"--disable-older-than", "soon"}, {"--keep-enabled", "0"}, {"--keep-enabled", "-1"}} {
+		errBuf.Reset()
+		if code := Run(append([]string{"dev-vault", "--config", cfgPath, "push", "foo-dev"}, args...), &out, &errBuf, deps); code != 2 || !strings.Contains(errBuf.String(), "invalid "+args[0]) {
+			t.Fatalf("%v: expected usage error, got %d (%s)", args, code, errBuf.String())
+		}
+	}
+
+	// The disabled line cannot be written.
+	if code := Run([]string{"dev-vault", "--config", cfgPath, "push", "foo-dev", "--keep-enabled", "1"}, &failAfterWriter{okWrites: 1}, &errBuf, deps); code != 1 {
+		t.Fatalf("expected output error, got %d", code)
+	}
+
+	api.disableErr = errors.New("disable boom")
+	out.Reset()
+	errBuf.Reset()
+	if code := Run([]string{"dev-vault", "--config", cfgPath, "push", "foo-dev", "--keep-enabled", "1"}, &out, &errBuf, deps); code != 1 || out.String() != "pushed foo-dev (rev=7)\n" || !strings.Contains(errBuf.String(), "disable foo-dev rev=6: disable boom") {
+		t.Fatalf("expected disable error, got %d %q (%s)", code, out.String(), errBuf.String())
+	}
+}
+
+func TestRunPullPush_SplitFiles(t *testing.T) {
+	root := t.TempDir()
+	cfgPath := writeConfig(t, root, `{"organization_id":"org","project_id":"proj","region":"fr-par","mapping":{"db-tls-dev":{"files":{"DB_CA_CERT":"ca.pem","DB_KEY":"key.pem"},"type":"key_value"}}}`)
+	api := newFakeSecretAPI()
+	sec := api.AddSecret("proj", "db-tls-dev", "/", secret.SecretTypeKeyValue)
+	api.AddEnabledVersion(sec.ID, []byte(`{"DB_CA_CERT":"ca","DB_KEY":"key","OTHER":"keep"}`))
+	deps := baseDeps(func(cfg config.Config, s string) (SecretAPI, error) { return api, nil })
+
+	var out, errBuf bytes.Buffer
+	if code := Run([]string{"dev-vault", "--config", cfgPath, "pull", "db-tls-dev"}, &out, &errBuf, deps); code != 0 {
+		t.Fatalf("pull: %d (%s)", code, errBuf.String())
+	}
+	if out.String() != "pulled db-tls-dev -> ca.pem, key.pem (rev=1 type=key_value)\n" {
+		t.Fatalf("unexpected pull output: %q", out.String())
+	}
+	if err := os.WriteFile(filepath.Join(root, "key.pem"), []byte("rotated"), 0o600); err != nil {
+		t.Fatalf("write key.pem: %v", err)
+	}
+	out.Reset()
+	if code := Run([]string{"dev-vault", "--config", cfgPath, "push", "db-tls-dev"}, &out, &errBuf, deps); code != 0 {
+		t.Fatalf("push: %d (%s)", code, errBuf.String())
+	}
+	if got := string(api.versions[sec.ID][1].data); out.String() != "pushed db-tls-dev (rev=2)\n" || got != `{"DB_CA_CERT":"ca","DB_KEY":"rotated","OTHER":"keep"}` {
+		t.Fatalf("unexpected push: %q %s", out.String(), got)
+	}
+}
+
+func TestRunPush_DryRun(t *testing.T) {
+	root := t.TempDir()
+	cfgPath := writeConfig(t, root, `{"organization_id":"org","project_id":"proj","region":"fr-par","state_secret":"team-state-dev","mapping":{
+		"foo-dev":{"file":"in.bin","format":"raw","type":"opaque"},
+		"new-dev":{"file":"in.bin","format":"raw","type":"opaque"}
+	}}`)
+	if err := os.WriteFile(filepath.Join(root, "in.bin"), []byte("A"), 0o644); err != nil {
+		t.Fatalf("write in.bin: %v", err)
+	}
+	api := newFakeSecretAPI()
+	foo := api.AddSecret("proj", "foo-dev", "/", secret.SecretTypeOpaque)
+	for range 3 {
+		api.AddEnabledVersion(foo.ID, []byte("old"))
+	}
+	deps := baseDeps(func(cfg config.Config, s string) (SecretAPI, error) { return api, nil })
+	run := func(stdout io.Writer, args ...string) (int, string) {
+		var errBuf bytes.Buffer
+		code := Run(append([]string{"dev-vault", "--config", cfgPath, "push", "--dry-run"}, args...), stdout, &errBuf, deps)
+		return code, errBuf.String()
+	}
+
+	// A dry run needs no --yes.
+	var out bytes.Buffer
+	if code, errOut := run(&out, "--all", "--create-missing", "--keep-enabled", "2"); code != 0 {
+		t.Fatalf("dry run: %d %q", code, errOut)
+	}
+	if out.String() != "would push foo-dev\nwould create new-dev and push its first version\nwould disable foo-dev (rev=2)\nwould disable foo-dev (rev=1)\n" {
+		t.Fatalf("unexpected dry run: %q", out.String())
+	}
+	if len(api.secrets) != 1 || len(api.versions[foo.ID]) != 3 || !api.versions[foo.ID][0].enabled {
+		t.Fatalf("expected nothing changed remotely, got %d secrets", len(api.secrets))
+	}
+
+	out.Reset()
+	if code, errOut := run(&out, "foo-dev", "--disable-previous"); code != 0 || out.String() != "would push foo-dev and disable its previous version\n" {
+		t.Fatalf("unexpected dry run: %d %q %q", code, out.String(), errOut)
+	}
+	if code, errOut := run(&out, "new-dev"); code != 1 || !strings.Contains(errOut, "resolve new-dev: secret not found") {
+		t.Fatalf("expected a resolve error, got %d %q", code, errOut)
+	}
+	if code, _ := run(&failingWriter{}, "foo-dev"); code != 1 {
+		t.Fatalf("expected an output error, got %d", code)
+	}
+}
+
+func TestRunPush_ConfirmBatch(t *testing.T) {
+	root := t.TempDir()
+	cfgPath := writeConfig(t, root, `{"organization_id":"org","project_id":"proj","region":"fr-par","mapping":{
+		"a-dev":{"file":"in.bin","format":"raw"},
+		"b-dev":{"file":"in.bin","format":"raw"}
+	}}`)
+	if err := os.WriteFile(filepath.Join(root, "in.bin"), []byte("A"), 0o644); err != nil {
+		t.Fatalf("write in.bin: %v", err)
+	}
+	api := newFakeSecretAPI()
+	a := api.AddSecret("proj", "a-dev", "/", secret.SecretTypeOpaque)
+	b := api.AddSecret("proj", "b-dev", "/", secret.SecretTypeOpaque)
+	deps := baseDeps(func(cfg config.Config, s string) (SecretAPI, error) { return api, nil })
+	terminal := true
+	deps.StdinIsTerminal = func() bool { return terminal }
+	run := func(stdin string, stderr io.Writer) int {
+		deps.Stdin = strings.NewReader(stdin)
+		return Run([]string{"dev-vault", "--config", cfgPath, "push", "--all"}, &bytes.Buffer{}, stderr, deps)
+	}
+
+	var errBuf bytes.Buffer
+	if code := run("n\n", &errBuf); code != 2 || errBuf.String() != "About to push 2 secrets:\n  a-dev\n  b-dev\nPush them? [y/N] push: not confirmed (answer y, or pass --yes)\n" {
+		t.Fatalf("expected a refused confirmation, got %d %q", code, errBuf.String())
+	}
+	if len(api.versions[a.ID]) != 0 {
+		t.Fatalf("expected nothing pushed, got %d versions", len(api.versions[a.ID]))
+	}
+	if code := run("y\n", &bytes.Buffer{}); code != 0 || len(api.versions[a.ID]) != 1 || len(api.versions[b.ID]) != 1 {
+		t.Fatalf("expected both pushed, got %d", code)
+	}
+	// The list cannot be written, which answers no.
+	if code := run("y\n", &failingWriter{}); code != 2 || len(api.versions[a.ID]) != 1 {
+		t.Fatalf("expected a refused confirmation, got %d", code)
+	}
+
+	// Without a terminal --yes stays required, whatever stdin holds.
+	terminal = false
+	errBuf.Reset()
+	if code := run("y\n", &errBuf); code != 2 || !strings.Contains(errBuf.String(), "refusing to push multiple secrets without --yes") || strings.Contains(errBuf.String(), "Push them?") {
+		t.Fatalf("expected the hard refusal, got %d %q", code, errBuf.String())
+	}
+}
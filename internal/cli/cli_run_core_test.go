@@ -4,11 +4,13 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
+	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/bsmartlabs/dev-vault/internal/config"
+	"github.com/bsmartlabs/dev-vault/internal/secretprovider"
 	secret "github.com/scaleway/scaleway-sdk-go/api/secret/v1beta1"
 )
 
@@ -56,8 +58,8 @@ func TestRun_GlobalHelpViaFlagSetPath(t *testing.T) {
 	if code != 0 {
 		t.Fatalf("expected 0, got %d", code)
 	}
-	if !strings.Contains(errBuf.String(), "Usage:") {
-		t.Fatalf("expected usage in stderr, got: %s", errBuf.String())
+	if !strings.Contains(out.String(), "Usage:") || errBuf.Len() != 0 {
+		t.Fatalf("expected usage on stdout only, got: %q %q", out.String(), errBuf.String())
 	}
 }
 
@@ -69,6 +71,53 @@ func TestRun_MissingDeps(t *testing.T) {
 	}
 }
 
+func TestRun_InvocationID(t *testing.T) {
+	t.Cleanup(func() {
+		secretprovider.SetInvocationID("")
+		secretprovider.SetClient(secretprovider.Client{})
+	})
+	root := t.TempDir()
+	cfgPath := writeConfig(t, root, `{"organization_id":"org","project_id":"proj","region":"fr-par","mapping":{"app-dev":{"file":"app.env"}}}`)
+	api := newFakeSecretAPI()
+	var opened, agent string
+	deps := baseDeps(func(config.Config, string) (SecretAPI, error) {
+		opened, agent = secretprovider.InvocationID(), secretprovider.UserAgent()
+		return api, nil
+	})
+	deps.Getenv = func(key string) string {
+		return map[string]string{"CI": "true", secretprovider.UserAgentSuffixEnv: "wrapper/1.0"}[key]
+	}
+	run := func(args ...string) (int, string) {
+		var out, errBuf bytes.Buffer
+		code := Run(append([]string{"dev-vault", "--config", cfgPath}, args...), &out, &errBuf, deps)
+		return code, errBuf.String()
+	}
+
+	// Providers are opened with the ID set, and a failure names it last.
+	code, errOut := run("pull", "app-dev")
+	if code != 1 || opened != "TESTINVOCATION" || !strings.HasSuffix(errOut, "path=/\ninvocation: TESTINVOCATION\n") {
+		t.Fatalf("unexpected failed pull: %d %q %q", code, opened, errOut)
+	}
+	if !strings.HasPrefix(agent, "dev-vault/v (") || !strings.HasSuffix(agent, "; ci=true) invocation/TESTINVOCATION wrapper/1.0") {
+		t.Fatalf("unexpected user agent %q", agent)
+	}
+	// The log file opened after the command name records it too.
+	logPath := filepath.Join(root, "dev-vault.log")
+	if code, _ := run("pull", "app-dev", "--log-file", logPath); code != 1 {
+		t.Fatalf("expected failure, got %d", code)
+	}
+	if logged, err := os.ReadFile(logPath); err != nil || !strings.HasPrefix(string(logged), "--- 1970-01-01T00:02:03Z dev-vault pull invocation=TESTINVOCATION\n") || !strings.HasSuffix(string(logged), "invocation: TESTINVOCATION\n") {
+		t.Fatalf("unexpected log: %q %v", logged, err)
+	}
+	// Usage errors and successes do not.
+	if code, errOut := run("pull"); code != 2 || strings.Contains(errOut, "invocation") {
+		t.Fatalf("unexpected usage error: %d %q", code, errOut)
+	}
+	if code, errOut := run("list"); code != 0 || strings.Contains(errOut, "invocation") {
+		t.Fatalf("unexpected list: %d %q", code, errOut)
+	}
+}
+
 func TestRun_UnknownCommand(t *testing.T) {
 	var out, errBuf bytes.Buffer
 	code := Run([]string{"dev-vault", "nope"}, &out, &errBuf, baseDeps(func(cfg config.Config, s string) (SecretAPI, error) {
@@ -204,8 +253,8 @@ func TestRun_SubcommandHelpFlag(t *testing.T) {
 	if code != 0 {
 		t.Fatalf("expected 0, got %d", code)
 	}
-	if !strings.Contains(errBuf.String(), "Usage:") {
-		t.Fatalf("expected usage in stderr, got: %s", errBuf.String())
+	if !strings.Contains(out.String(), "Usage:") || errBuf.Len() != 0 {
+		t.Fatalf("expected usage on stdout only, got: %q %q", out.String(), errBuf.String())
 	}
 }
 
@@ -217,8 +266,8 @@ func TestRun_SubcommandHelpFlag_List(t *testing.T) {
 	if code != 0 {
 		t.Fatalf("expected 0, got %d", code)
 	}
-	if !strings.Contains(errBuf.String(), "Usage:") {
-		t.Fatalf("expected usage in stderr, got: %s", errBuf.String())
+	if !strings.Contains(out.String(), "Usage:") || errBuf.Len() != 0 {
+		t.Fatalf("expected usage on stdout only, got: %q %q", out.String(), errBuf.String())
 	}
 }
 
@@ -230,8 +279,8 @@ func TestRun_SubcommandHelpFlag_Push(t *testing.T) {
 	if code != 0 {
 		t.Fatalf("expected 0, got %d", code)
 	}
-	if !strings.Contains(errBuf.String(), "Usage:") {
-		t.Fatalf("expected usage in stderr, got: %s", errBuf.String())
+	if !strings.Contains(out.String(), "Usage:") || errBuf.Len() != 0 {
+		t.Fatalf("expected usage on stdout only, got: %q %q", out.String(), errBuf.String())
 	}
 }
 
@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/bsmartlabs/dev-vault/internal/config"
+	"github.com/bsmartlabs/dev-vault/internal/i18n"
 )
 
 type createSecretNoPersist struct{ inner *fakeSecretAPI }
@@ -17,6 +18,9 @@ func (c *createSecretNoPersist) ListSecrets(req ListSecretsInput) ([]SecretRecor
 func (c *createSecretNoPersist) AccessSecretVersion(req AccessSecretVersionInput) (*SecretVersionRecord, error) {
 	return c.inner.AccessSecretVersion(req)
 }
+func (c *createSecretNoPersist) ListSecretVersions(req ListSecretVersionsInput) ([]SecretVersionRecord, error) {
+	return c.inner.ListSecretVersions(req)
+}
 func (c *createSecretNoPersist) CreateSecret(req CreateSecretInput) (*SecretRecord, error) {
 	// Do not persist.
 	if c.inner.createSecretErr != nil {
@@ -27,10 +31,19 @@ func (c *createSecretNoPersist) CreateSecret(req CreateSecretInput) (*SecretReco
 func (c *createSecretNoPersist) CreateSecretVersion(req CreateSecretVersionInput) (*SecretVersionRecord, error) {
 	return c.inner.CreateSecretVersion(req)
 }
+func (c *createSecretNoPersist) DeleteSecret(req DeleteSecretInput) error {
+	return c.inner.DeleteSecret(req)
+}
+func (c *createSecretNoPersist) DeleteSecretVersion(req DeleteSecretVersionInput) error {
+	return c.inner.DeleteSecretVersion(req)
+}
+func (c *createSecretNoPersist) DisableSecretVersion(req DisableSecretVersionInput) error {
+	return c.inner.DisableSecretVersion(req)
+}
 
 func TestPrintUsage_Coverage(t *testing.T) {
 	var b bytes.Buffer
-	if err := printMainUsage(&b); err != nil {
+	if err := printMainUsage(&b, i18n.Localizer{}); err != nil {
 		t.Fatalf("printMainUsage: %v", err)
 	}
 	if !strings.Contains(b.String(), config.DefaultConfigName) {
@@ -1,14 +1,19 @@
 package cli
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"strconv"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/bsmartlabs/dev-vault/internal/config"
+	"github.com/bsmartlabs/dev-vault/internal/secretprovider"
 	secret "github.com/scaleway/scaleway-sdk-go/api/secret/v1beta1"
 )
 
@@ -17,8 +22,13 @@ type fakeSecretAPI struct {
 	accessErr       error
 	createSecretErr error
 	createVerErr    error
+	versionsErr     error
+	deleteErr       error
+	disableErr      error
 
-	listCalls int
+	mu          sync.Mutex
+	listCalls   int
+	accessCalls int
 
 	secrets  []SecretRecord
 	versions map[string][]fakeVersion // secretID -> versions (1-based)
@@ -62,6 +72,8 @@ func (f *fakeSecretAPI) AddEnabledVersion(secretID string, data []byte) uint32 {
 }
 
 func (f *fakeSecretAPI) ListSecrets(req ListSecretsInput) ([]SecretRecord, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
 	f.listCalls++
 	if f.listErr != nil {
 		return nil, f.listErr
@@ -86,6 +98,9 @@ func (f *fakeSecretAPI) ListSecrets(req ListSecretsInput) ([]SecretRecord, error
 }
 
 func (f *fakeSecretAPI) AccessSecretVersion(req AccessSecretVersionInput) (*SecretVersionRecord, error) {
+	f.mu.Lock()
+	f.accessCalls++
+	f.mu.Unlock()
 	if f.accessErr != nil {
 		return nil, f.accessErr
 	}
@@ -106,7 +121,11 @@ func (f *fakeSecretAPI) AccessSecretVersion(req AccessSecretVersionInput) (*Secr
 			}
 		}
 	default:
-		return nil, errors.New("unsupported revision selector")
+		for i := range versions {
+			if strconv.FormatUint(uint64(versions[i].revision), 10) == string(req.Revision) {
+				chosen = &versions[i]
+			}
+		}
 	}
 	if chosen == nil {
 		return nil, errors.New("no enabled version")
@@ -119,6 +138,30 @@ func (f *fakeSecretAPI) AccessSecretVersion(req AccessSecretVersionInput) (*Secr
 	}, nil
 }
 
+// ListSecretVersions reports revision n as created n hours into 2026.
+func (f *fakeSecretAPI) ListSecretVersions(req ListSecretVersionsInput) ([]SecretVersionRecord, error) {
+	if f.versionsErr != nil {
+		return nil, f.versionsErr
+	}
+	var out []SecretVersionRecord
+	for _, v := range f.versions[req.SecretID] {
+		record := SecretVersionRecord{
+			SecretID:  req.SecretID,
+			Revision:  v.revision,
+			Status:    "disabled",
+			CreatedAt: time.Date(2026, 1, 1, int(v.revision), 0, 0, 0, time.UTC),
+		}
+		if v.enabled {
+			record.Status = "enabled"
+		}
+		if v.description != nil {
+			record.Description = *v.description
+		}
+		out = append(out, record)
+	}
+	return out, nil
+}
+
 func (f *fakeSecretAPI) CreateSecret(req CreateSecretInput) (*SecretRecord, error) {
 	if f.createSecretErr != nil {
 		return nil, f.createSecretErr
@@ -162,6 +205,35 @@ func (f *fakeSecretAPI) CreateSecretVersion(req CreateSecretVersionInput) (*Secr
 	}, nil
 }
 
+func (f *fakeSecretAPI) DeleteSecret(req DeleteSecretInput) error {
+	if f.deleteErr != nil {
+		return f.deleteErr
+	}
+	f.secrets = slices.DeleteFunc(f.secrets, func(s SecretRecord) bool { return s.ID == req.SecretID })
+	delete(f.versions, req.SecretID)
+	return nil
+}
+
+func (f *fakeSecretAPI) DeleteSecretVersion(req DeleteSecretVersionInput) error {
+	if f.deleteErr != nil {
+		return f.deleteErr
+	}
+	f.versions[req.SecretID] = slices.DeleteFunc(f.versions[req.SecretID], func(v fakeVersion) bool { return v.revision == req.Revision })
+	return nil
+}
+
+func (f *fakeSecretAPI) DisableSecretVersion(req DisableSecretVersionInput) error {
+	if f.disableErr != nil {
+		return f.disableErr
+	}
+	for i := range f.versions[req.SecretID] {
+		if f.versions[req.SecretID][i].revision == req.Revision {
+			f.versions[req.SecretID][i].enabled = false
+		}
+	}
+	return nil
+}
+
 func (f *fakeSecretAPI) findSecret(id string) *SecretRecord {
 	for i := range f.secrets {
 		if f.secrets[i].ID == id {
@@ -186,8 +258,38 @@ func baseDeps(open func(cfg config.Config, profileOverride string) (SecretAPI, e
 		Commit:        "c",
 		Date:          "d",
 		OpenSecretAPI: open,
-		Now:           func() time.Time { return time.Unix(123, 0) },
-		Hostname:      func() (string, error) { return "host", nil },
-		Getwd:         os.Getwd,
+		OpenAccountAPI: func(string) (AccountAPI, error) {
+			return &fakeAccountAPI{}, nil
+		},
+		Now:      func() time.Time { return time.Unix(123, 0) },
+		Hostname: func() (string, error) { return "host", nil },
+		Getwd:    os.Getwd,
+		Getenv:   func(string) string { return "" },
+		// Tests never touch the real user config dir; telemetry tests
+		// point this at a temp dir explicitly.
+		UserConfigDir: func() (string, error) { return "", errors.New("no user config dir in tests") },
+		UserHomeDir:   func() (string, error) { return "", errors.New("no home dir in tests") },
+		InvocationID:  func() string { return "TESTINVOCATION" },
+		SignalContext: func() (context.Context, context.CancelFunc) { return context.WithCancel(context.Background()) },
+		OpenURL:       func(string) error { return errors.New("no browser in tests") },
 	}
 }
+
+type fakeAccountAPI struct {
+	listErr  error
+	projects []secretprovider.ProjectRecord
+	regions  []string
+	lastOrg  string
+}
+
+func (f *fakeAccountAPI) ListProjects(req secretprovider.ListProjectsInput) ([]secretprovider.ProjectRecord, error) {
+	f.lastOrg = req.OrganizationID
+	if f.listErr != nil {
+		return nil, f.listErr
+	}
+	return f.projects, nil
+}
+
+func (f *fakeAccountAPI) Regions() []string {
+	return append([]string(nil), f.regions...)
+}
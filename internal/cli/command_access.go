@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/bsmartlabs/dev-vault/internal/config"
+	"github.com/bsmartlabs/dev-vault/internal/i18n"
+	"github.com/bsmartlabs/dev-vault/internal/secretsync"
+)
+
+var accessCommandDef = commandDef{
+	Name:    "access",
+	Summary: "Show who grants access to the project's secrets and where to request it",
+	Flags: []commandFlagDef{
+		{Name: "open", Kind: commandFlagBool, Help: "Open access_contact.request_url in the browser"},
+	},
+	Doc: commandDoc{
+		Synopsis: "dev-vault [--config <path>] access [--open]",
+		Description: []string{
+			"Prints the owner, contact, and access request page from access_contact in .scw.json,",
+			"so a new contributor whose pull is denied knows whom to ask. No credentials are needed.",
+			"With --open, the request page opens in $BROWSER or the platform's default browser.",
+		},
+		Notes: []string{
+			"Commands denied by the provider print the same contact as a hint.",
+		},
+		Examples: []string{
+			"dev-vault access",
+			"dev-vault access --open",
+		},
+	},
+	RunParsed: runAccessParsed,
+}
+
+func runAccess(ctx commandContext, argv []string) int {
+	return runCommand(ctx, argv, accessCommandDef)
+}
+
+func runAccessParsed(ctx commandContext, parsed *parsedCommand) int {
+	return newCommandRuntime(ctx, parsed).executeLocal(func(loaded *config.Loaded, _ secretsync.Service) error {
+		contact := loaded.Cfg.AccessContact
+		if contact == nil {
+			return runtimeError(i18n.Errorf(i18n.MsgAccessNoContact, loaded.Path))
+		}
+		if parsed.Bool("open") && contact.RequestURL == "" {
+			return usageError(i18n.Errorf(i18n.MsgAccessNoRequestURL))
+		}
+		if err := writeAccessContact(ctx.stdout, contact); err != nil {
+			return outputError(err)
+		}
+		if !parsed.Bool("open") {
+			return nil
+		}
+		if err := ctx.deps.OpenURL(contact.RequestURL); err != nil {
+			return runtimeError(i18n.Errorf(i18n.MsgAccessOpen, contact.RequestURL, err))
+		}
+		if _, err := fmt.Fprintf(ctx.stdout, "opened %s\n", contact.RequestURL); err != nil {
+			return outputError(err)
+		}
+		return nil
+	})
+}
+
+func writeAccessContact(w io.Writer, contact *config.AccessContact) error {
+	for _, field := range []struct{ label, value string }{
+		{"owner", contact.Owner},
+		{"contact", contact.Contact},
+		{"request", contact.RequestURL},
+	} {
+		if field.value == "" {
+			continue
+		}
+		if _, err := fmt.Fprintf(w, "%-8s %s\n", field.label+":", field.value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
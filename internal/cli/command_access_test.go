@@ -0,0 +1,119 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/bsmartlabs/dev-vault/internal/config"
+	"github.com/bsmartlabs/dev-vault/internal/secretprovider"
+	secret "github.com/scaleway/scaleway-sdk-go/api/secret/v1beta1"
+)
+
+func TestRunAccess(t *testing.T) {
+	dir := t.TempDir()
+	cfgPath := writeConfig(t, dir, `{"organization_id":"org","project_id":"proj","region":"fr-par","mapping":{"a-dev":{"file":"a"}},
+		"access_contact":{"owner":"Platform team","contact":"#platform-access","request_url":"https://access.example.test/dev-vault"}}`)
+	var opened []string
+	deps := baseDeps(func(config.Config, string) (SecretAPI, error) { return nil, errors.New("no credentials") })
+	deps.OpenURL = func(rawURL string) error {
+		opened = append(opened, rawURL)
+		return nil
+	}
+	run := func(cfg string, args ...string) (int, string, string) {
+		var stdout, stderr bytes.Buffer
+		code := Run(append([]string{"dev-vault", "--config", cfg, "access"}, args...), &stdout, &stderr, deps)
+		return code, stdout.String(), stderr.String()
+	}
+
+	want := "owner:   Platform team\ncontact: #platform-access\nrequest: https://access.example.test/dev-vault\n"
+	if code, stdout, stderr := run(cfgPath); code != 0 || stdout != want || stderr != "" || opened != nil {
+		t.Fatalf("unexpected access: %d %q %q", code, stdout, stderr)
+	}
+	if code, stdout, _ := run(cfgPath, "--open"); code != 0 || stdout != want+"opened https://access.example.test/dev-vault\n" || !reflect.DeepEqual(opened, []string{"https://access.example.test/dev-vault"}) {
+		t.Fatalf("unexpected access --open: %d %q %v", code, stdout, opened)
+	}
+	deps.OpenURL = func(string) error { return errors.New("no browser") }
+	if code, _, stderr := run(cfgPath, "--open"); code != 1 || !strings.Contains(stderr, "access: open https://access.example.test/dev-vault: no browser") {
+		t.Fatalf("expected open error, got %d %q", code, stderr)
+	}
+
+	ownerOnly := writeConfig(t, t.TempDir(), `{"organization_id":"org","project_id":"proj","region":"fr-par","mapping":{"a-dev":{"file":"a"}},"access_contact":{"owner":"Platform team"}}`)
+	if code, stdout, _ := run(ownerOnly); code != 0 || stdout != "owner:   Platform team\n" {
+		t.Fatalf("unexpected owner-only access: %d %q", code, stdout)
+	}
+	if code, _, stderr := run(ownerOnly, "--open"); code != 2 || !strings.Contains(stderr, "access --open: access_contact has no request_url") {
+		t.Fatalf("expected missing request_url, got %d %q", code, stderr)
+	}
+	bare := writeConfig(t, t.TempDir(), `{"organization_id":"org","project_id":"proj","region":"fr-par","mapping":{"a-dev":{"file":"a"}}}`)
+	if code, _, stderr := run(bare); code != 1 || !strings.Contains(stderr, "has no access_contact; ask the project maintainers") {
+		t.Fatalf("expected no access_contact, got %d %q", code, stderr)
+	}
+
+	for _, args := range [][]string{nil, {"--open"}} {
+		ctx := commandContext{stdout: &failingWriter{}, stderr: &bytes.Buffer{}, configPath: cfgPath, deps: deps}
+		if code := runAccess(ctx, args); code != 1 {
+			t.Fatalf("%v: expected output error, got %d", args, code)
+		}
+	}
+	deps.OpenURL = func(string) error { return nil }
+	ctx := commandContext{stdout: &limitedWriter{limit: 3}, stderr: &bytes.Buffer{}, configPath: cfgPath, deps: deps}
+	if code := runAccess(ctx, []string{"--open"}); code != 1 {
+		t.Fatalf("expected output error after opening, got %d", code)
+	}
+}
+
+// limitedWriter accepts limit writes, then fails.
+type limitedWriter struct{ limit int }
+
+func (w *limitedWriter) Write(p []byte) (int, error) {
+	if w.limit == 0 {
+		return 0, errors.New("nope")
+	}
+	w.limit--
+	return len(p), nil
+}
+
+func TestAccessGuidance(t *testing.T) {
+	root := t.TempDir()
+	api := newFakeSecretAPI()
+	api.AddSecret("proj", "a-dev", "/", secret.SecretTypeOpaque)
+	api.accessErr = fmt.Errorf("access secret version: %w: 403", secretprovider.ErrPermissionDenied)
+	deps := baseDeps(func(config.Config, string) (SecretAPI, error) { return api, nil })
+	pull := func(contact string) (int, string) {
+		cfgPath := writeConfig(t, root, `{"organization_id":"org","project_id":"proj","region":"fr-par","mapping":{"a-dev":{"file":"a"}}`+contact+`}`)
+		var errBuf bytes.Buffer
+		code := Run([]string{"dev-vault", "--config", cfgPath, "pull", "a-dev"}, &bytes.Buffer{}, &errBuf, deps)
+		return code, errBuf.String()
+	}
+
+	cases := map[string]string{
+		``: "",
+		`,"access_contact":{"owner":"Platform team","contact":"#platform-access","request_url":"https://access.example.test/"}`: "\nhint: your credentials cannot read this project's secrets yet; ask Platform team (#platform-access) for access; request it at https://access.example.test/ ('dev-vault access --open' opens the page)\n",
+		`,"access_contact":{"contact":"#platform-access"}`:                                                                      "\nhint: your credentials cannot read this project's secrets yet; ask #platform-access for access\n",
+		`,"access_contact":{"request_url":"https://access.example.test/"}`:                                                      "\nhint: your credentials cannot read this project's secrets yet; request it at https://access.example.test/ ('dev-vault access --open' opens the page)\n",
+	}
+	for contact, hint := range cases {
+		code, stderr := pull(contact)
+		if code != 1 || !strings.Contains(stderr, "permission denied: 403") || strings.Contains(stderr, "hint:") != (hint != "") || !strings.Contains(stderr, hint) {
+			t.Fatalf("%s: unexpected result: %d %q", contact, code, stderr)
+		}
+	}
+
+	// Refused writes keep the read-only hint alone.
+	api.accessErr = nil
+	api.createVerErr = fmt.Errorf("%w: 403", secretprovider.ErrPermissionDenied)
+	cfgPath := writeConfig(t, root, `{"organization_id":"org","project_id":"proj","region":"fr-par","mapping":{"a-dev":{"file":"a"}},"access_contact":{"owner":"Platform team"}}`)
+	if err := os.WriteFile(filepath.Join(root, "a"), []byte("X"), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	var errBuf bytes.Buffer
+	if code := Run([]string{"dev-vault", "--config", cfgPath, "push", "a-dev"}, &bytes.Buffer{}, &errBuf, deps); code != 1 || strings.Contains(errBuf.String(), "Platform team") || !strings.Contains(errBuf.String(), "read-only credentials") {
+		t.Fatalf("unexpected push result: %d %q", code, errBuf.String())
+	}
+}
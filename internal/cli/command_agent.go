@@ -0,0 +1,272 @@
+package cli
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/bsmartlabs/dev-vault/internal/config"
+	"github.com/bsmartlabs/dev-vault/internal/i18n"
+	"github.com/bsmartlabs/dev-vault/internal/secretsync"
+)
+
+var agentCommandDef = commandDef{
+	Name:    "agent",
+	Summary: "Serve a localhost API for editor plugins to list mappings, check status, and pull",
+	Flags: []commandFlagDef{
+		{Name: "listen", Kind: commandFlagString, ValueName: "<addr>", Help: "Loopback address to listen on (default 127.0.0.1:0, a free port)"},
+		{Name: "socket", Kind: commandFlagString, ValueName: "<path>", Help: "Listen on a unix socket at this path instead"},
+	},
+	Constraints: []flagConstraint{
+		{Flag: "socket", Excludes: []string{"listen"}, Reason: i18n.MsgReasonAgentOneAddress},
+	},
+	Doc: commandDoc{
+		Synopsis: "dev-vault [--config <path>] [--profile <name>] agent [--listen <addr> | --socket <path>]",
+		Description: []string{
+			"Serves a JSON API on a loopback address or a unix socket until Ctrl-C, for IDE",
+			"extensions. On start it prints one JSON line with the base url (or the socket path) and",
+			"a bearer token made for this session; every request must send",
+			"'Authorization: Bearer <token>'.",
+			"",
+			"Endpoints:",
+			"  GET  /v1/mappings  the mapping entries: name, file, files, format, mode, type, disabled.",
+			"  GET  /v1/status    the records of status --json.",
+			"  POST /v1/pull      pulls {\"names\": [...]} or {\"all\": true}, with \"overwrite\": true",
+			"                     to replace existing files, and returns what it wrote.",
+			"Errors are {\"error\": \"...\"} with status 400 for a bad request and 500 otherwise.",
+		},
+		Notes: []string{
+			"Only loopback addresses are accepted. Requests with an Origin header, which browsers",
+			"send, are refused, so web pages cannot reach the API.",
+			"The socket is made readable and writable by the user only, and removed on exit.",
+			"Requests are served one at a time, and each reads the provider again. Payloads are never",
+			"returned: pull writes files like the pull command does.",
+			"The agent exits with code 130 when stopped.",
+		},
+		Examples: []string{
+			"dev-vault agent",
+			"dev-vault agent --listen 127.0.0.1:7777",
+			"dev-vault agent --socket \"$XDG_RUNTIME_DIR/dev-vault.sock\"",
+		},
+	},
+	RunParsed:    runAgentParsed,
+	Experimental: "agent",
+}
+
+const defaultAgentListen = "127.0.0.1:0"
+
+type agentEndpoint struct {
+	URL    string `json:"url,omitempty"`
+	Socket string `json:"socket,omitempty"`
+	Token  string `json:"token"`
+}
+
+type agentMapping struct {
+	Name     string            `json:"name"`
+	File     string            `json:"file,omitempty"`
+	Files    map[string]string `json:"files,omitempty"`
+	Format   string            `json:"format"`
+	Mode     string            `json:"mode"`
+	Type     string            `json:"type,omitempty"`
+	Disabled bool              `json:"disabled"`
+}
+
+type agentPullRequest struct {
+	Names     []string `json:"names"`
+	All       bool     `json:"all"`
+	Overwrite bool     `json:"overwrite"`
+}
+
+type agentPulled struct {
+	Name     string  `json:"name"`
+	File     string  `json:"file"`
+	Revision uint32  `json:"revision"`
+	Type     string  `json:"type"`
+	Expires  *string `json:"expires,omitempty"`
+}
+
+type agentPullResponse struct {
+	Pulled []agentPulled `json:"pulled"`
+	Error  string        `json:"error,omitempty"`
+}
+
+type agentError struct {
+	Error string `json:"error"`
+}
+
+func runAgent(ctx commandContext, argv []string) int {
+	return runCommand(ctx, argv, agentCommandDef)
+}
+
+func runAgentParsed(ctx commandContext, parsed *parsedCommand) int {
+	return newCommandRuntime(ctx, parsed).execute(func(loaded *config.Loaded, service secretsync.Service) error {
+		if len(parsed.fs.Args()) > 0 {
+			return usageError(i18n.Errorf(i18n.MsgTakesNoArguments, "agent"))
+		}
+		network, address := "tcp", parsed.String("listen")
+		if socket := parsed.String("socket"); socket != "" {
+			network, address = "unix", socket
+		} else if address == "" {
+			address = defaultAgentListen
+		}
+		if network == "tcp" {
+			if err := requireLoopback(address); err != nil {
+				return usageError(err)
+			}
+		}
+
+		signalCtx, stop := ctx.deps.SignalContext()
+		defer stop()
+		listener, err := net.Listen(network, address)
+		if err != nil {
+			return runtimeError(i18n.Errorf(i18n.MsgCommandError, "agent", err))
+		}
+		endpoint := agentEndpoint{URL: "http://" + listener.Addr().String()}
+		if network == "unix" {
+			// Best effort: the token guards the API either way.
+			_ = os.Chmod(address, 0o600)
+			endpoint = agentEndpoint{Socket: address}
+		}
+		api := newAgentAPI(rand.Text(), ctx.deps, loaded, service)
+		endpoint.Token = api.token
+		server := &http.Server{Handler: api, ReadHeaderTimeout: 10 * time.Second}
+		go func() { _ = server.Serve(listener) }() // Serve returns once Shutdown is called
+		// Shutdown lets a pull in progress finish writing its files.
+		defer func() { _ = server.Shutdown(context.Background()) }()
+
+		if err := json.NewEncoder(ctx.stdout).Encode(endpoint); err != nil {
+			return outputError(err)
+		}
+		<-signalCtx.Done()
+		return interruptedError(i18n.Errorf(i18n.MsgAgentStopped, secretsync.ErrInterrupted))
+	})
+}
+
+// requireLoopback refuses listen addresses other machines could reach.
+func requireLoopback(addr string) error {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return i18n.Errorf(i18n.MsgListenInvalid, addr, err)
+	}
+	if ip := net.ParseIP(host); host != "localhost" && (ip == nil || !ip.IsLoopback()) {
+		return i18n.Errorf(i18n.MsgListenNotLoopback, addr)
+	}
+	return nil
+}
+
+// agentAPI serves the agent's endpoints. Requests are handled one at a time,
+// since a pull writes files and the service is not meant for concurrent use.
+type agentAPI struct {
+	token   string
+	deps    Dependencies
+	loaded  *config.Loaded
+	service secretsync.Service
+
+	mux *http.ServeMux
+	mu  sync.Mutex
+}
+
+func newAgentAPI(token string, deps Dependencies, loaded *config.Loaded, service secretsync.Service) *agentAPI {
+	a := &agentAPI{token: token, deps: deps, loaded: loaded, service: service, mux: http.NewServeMux()}
+	a.mux.HandleFunc("GET /v1/mappings", a.mappings)
+	a.mux.HandleFunc("GET /v1/status", a.status)
+	a.mux.HandleFunc("POST /v1/pull", a.pull)
+	return a
+}
+
+func (a *agentAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Origin") != "" {
+		writeAgentJSON(w, http.StatusForbidden, agentError{Error: "cross-origin requests are refused"})
+		return
+	}
+	if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), []byte("Bearer "+a.token)) != 1 {
+		writeAgentJSON(w, http.StatusUnauthorized, agentError{Error: "missing or wrong bearer token"})
+		return
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.service.ForgetCached()
+	a.mux.ServeHTTP(w, r)
+}
+
+func (a *agentAPI) mappings(w http.ResponseWriter, _ *http.Request) {
+	names := make([]string, 0, len(a.loaded.Cfg.Mapping))
+	for name := range a.loaded.Cfg.Mapping {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	records := make([]agentMapping, 0, len(names))
+	for _, name := range names {
+		entry := a.loaded.Cfg.Mapping[name]
+		records = append(records, agentMapping{
+			Name:     name,
+			File:     entry.File,
+			Files:    entry.Files,
+			Format:   string(entry.Format),
+			Mode:     string(entry.Mode),
+			Type:     entry.Type,
+			Disabled: entry.Disabled,
+		})
+	}
+	writeAgentJSON(w, http.StatusOK, records)
+}
+
+func (a *agentAPI) status(w http.ResponseWriter, _ *http.Request) {
+	statuses, err := a.service.MappingStatuses()
+	if err != nil {
+		writeAgentJSON(w, http.StatusInternalServerError, agentError{Error: err.Error()})
+		return
+	}
+	writeAgentJSON(w, http.StatusOK, statusRecords(statuses))
+}
+
+func (a *agentAPI) pull(w http.ResponseWriter, r *http.Request) {
+	var request agentPullRequest
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&request); err != nil {
+		writeAgentJSON(w, http.StatusBadRequest, agentError{Error: fmt.Sprintf("decode request: %v", err)})
+		return
+	}
+	targets, err := selectMappingTargetsForMode(a.loaded.Cfg.Mapping, request.All, request.Names, commandModePull)
+	if err != nil {
+		writeAgentJSON(w, http.StatusBadRequest, agentError{Error: err.Error()})
+		return
+	}
+	results, err := a.service.Pull(targets, request.Overwrite)
+	// Recorded like a pull without --expire-after. The response has no room
+	// for warnings, so a record that cannot be written is dropped.
+	ttl, _ := config.ParseAge(a.loaded.Cfg.ExpireAfter) // validated on load; zero when unset
+	_ = recordPull(a.deps, a.loaded, ttl, results)
+	response := agentPullResponse{Pulled: make([]agentPulled, 0, len(results))}
+	for _, item := range results {
+		pulled := agentPulled{Name: item.Name, File: item.File, Revision: item.Revision, Type: item.Type}
+		if !item.Expires.IsZero() {
+			expires := item.Expires.Format(time.DateOnly)
+			pulled.Expires = &expires
+		}
+		response.Pulled = append(response.Pulled, pulled)
+	}
+	status := http.StatusOK
+	if err != nil {
+		// The files pulled before the failure are listed with it.
+		status, response.Error = http.StatusInternalServerError, err.Error()
+	}
+	writeAgentJSON(w, status, response)
+}
+
+// writeAgentJSON sends body as the response. A client that went away is not
+// the agent's failure, so write errors are dropped.
+func writeAgentJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,226 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bsmartlabs/dev-vault/internal/config"
+	secret "github.com/scaleway/scaleway-sdk-go/api/secret/v1beta1"
+)
+
+func TestRunAgent(t *testing.T) {
+	root := t.TempDir()
+	cfgPath := writeConfig(t, root, `{"organization_id":"org","project_id":"proj","region":"fr-par","mapping":{
+		"a-dev":{"file":"a.bin","format":"raw"},
+		"tls-dev":{"file":"tls.pem","format":"raw","mode":"pull","type":"certificate"},
+		"off-dev":{"file":"off.env","format":"dotenv","disabled":true}
+	}}`)
+	api := newFakeSecretAPI()
+	a := api.AddSecret("proj", "a-dev", "/", secret.SecretTypeOpaque)
+	api.AddEnabledVersion(a.ID, []byte("s3cret"))
+	tls := api.AddSecret("proj", "tls-dev", "/", secret.SecretTypeCertificate)
+	api.AddEnabledVersion(tls.ID, testCertificatePEM(t, "api", time.Date(2027, 11, 19, 0, 0, 0, 0, time.UTC), false))
+	deps := baseDeps(func(config.Config, string) (SecretAPI, error) { return api, nil })
+	env := map[string]string{}
+	deps.Getenv = func(key string) string { return env[key] }
+	userDir := t.TempDir()
+	deps.UserConfigDir = func() (string, error) { return userDir, nil }
+	signalCtx, cancel := context.WithCancel(context.Background())
+	deps.SignalContext = func() (context.Context, context.CancelFunc) { return signalCtx, cancel }
+	run := func(stdout io.Writer, args ...string) (int, string) {
+		var errBuf bytes.Buffer
+		code := Run(append([]string{"dev-vault", "--config", cfgPath, "agent"}, args...), stdout, &errBuf, deps)
+		return code, errBuf.String()
+	}
+
+	if code, errOut := run(&bytes.Buffer{}); code != 2 || !strings.Contains(errOut, "agent is experimental") {
+		t.Fatalf("expected the feature gate, got %d %q", code, errOut)
+	}
+	env["DEV_VAULT_EXPERIMENTAL"] = "agent"
+
+	client := &http.Client{Transport: &http.Transport{}}
+	type reply struct {
+		status int
+		body   string
+	}
+	var endpoint agentEndpoint
+	call := func(method, path, body string, header map[string]string) reply {
+		req, err := http.NewRequest(method, endpoint.URL+path, strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("request: %v", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+endpoint.Token)
+		for key, value := range header {
+			req.Header.Set(key, value)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("%s %s: %v", method, path, err)
+		}
+		defer resp.Body.Close()
+		data, _ := io.ReadAll(resp.Body)
+		return reply{resp.StatusCode, string(data)}
+	}
+
+	// The test drives the API once the agent prints its endpoint, then stops
+	// it like Ctrl-C would.
+	var replies []reply
+	stdout := &hookWriter{onWrite: func(text string) error {
+		if err := json.Unmarshal([]byte(text), &endpoint); err != nil {
+			t.Fatalf("endpoint line %q: %v", text, err)
+		}
+		replies = append(replies,
+			call("GET", "/v1/mappings", "", map[string]string{"Authorization": "Bearer wrong"}),
+			call("GET", "/v1/mappings", "", map[string]string{"Origin": "https://example.com"}),
+			call("GET", "/v1/mappings", "", nil),
+			call("GET", "/v1/status", "", nil),
+			call("POST", "/v1/pull", `{"all":true}`, nil),
+			call("POST", "/v1/pull", `{"names":["a-dev"]}`, nil),
+			call("POST", "/v1/pull", `{"names":["a-dev"],"force":true}`, nil),
+			call("POST", "/v1/pull", `{}`, nil),
+			call("DELETE", "/v1/pull", "", nil),
+		)
+		// A failure mid-batch lists the files pulled before it.
+		if err := os.Remove(filepath.Join(root, "tls.pem")); err != nil {
+			t.Fatalf("remove tls.pem: %v", err)
+		}
+		if err := os.Mkdir(filepath.Join(root, "tls.pem"), 0o700); err != nil {
+			t.Fatalf("mkdir tls.pem: %v", err)
+		}
+		replies = append(replies, call("POST", "/v1/pull", `{"all":true,"overwrite":true}`, nil))
+		api.AddEnabledVersion(a.ID, []byte("s3cret2"))
+		replies = append(replies, call("GET", "/v1/status", "", nil))
+		api.listErr = errors.New("list boom")
+		replies = append(replies, call("GET", "/v1/status", "", nil))
+		client.CloseIdleConnections()
+		cancel()
+		return nil
+	}}
+	code, errOut := run(stdout)
+	if code != 130 || !strings.Contains(errOut, "agent stopped: interrupted") {
+		t.Fatalf("expected the agent to stop with 130, got %d %q", code, errOut)
+	}
+	if !strings.HasPrefix(endpoint.URL, "http://127.0.0.1:") || len(endpoint.Token) < 16 {
+		t.Fatalf("unexpected endpoint %+v", endpoint)
+	}
+	want := []struct {
+		status int
+		body   string
+	}{
+		{401, `{"error":"missing or wrong bearer token"}`},
+		{403, `{"error":"cross-origin requests are refused"}`},
+		{200, `[{"name":"a-dev","file":"a.bin","format":"raw","mode":"both","disabled":false},{"name":"off-dev","file":"off.env","format":"dotenv","mode":"both","disabled":true},{"name":"tls-dev","file":"tls.pem","format":"raw","mode":"pull","type":"certificate","disabled":false}]`},
+		{200, `"name":"a-dev","file":"a.bin","remote_exists":true,"remote_revision":1,"local_exists":false`},
+		{200, `{"pulled":[{"name":"a-dev","file":"a.bin","revision":1,"type":"opaque"},{"name":"tls-dev","file":"tls.pem","revision":1,"type":"certificate","expires":"2027-11-19"}]}`},
+		{500, `{"pulled":[],"error":"`},
+		{400, `{"error":"decode request: json: unknown field \"force\""}`},
+		{400, `{"error":"no secrets specified (use --all or pass secret names)"}`},
+		{405, "Method Not Allowed"},
+		{500, `{"pulled":[{"name":"a-dev","file":"a.bin","revision":1,"type":"opaque"}],"error":"pull tls-dev: refusing to replace`},
+		{200, `"name":"a-dev","file":"a.bin","remote_exists":true,"remote_revision":2,"local_exists":true`},
+		{500, `list boom`},
+	}
+	if len(replies) != len(want) {
+		t.Fatalf("expected %d replies, got %d", len(want), len(replies))
+	}
+	for i, w := range want {
+		if replies[i].status != w.status || !strings.Contains(replies[i].body, w.body) || strings.Contains(replies[i].body, "s3cret") {
+			t.Fatalf("reply %d: expected %d %s, got %d %s", i, w.status, w.body, replies[i].status, replies[i].body)
+		}
+	}
+	if got, _ := os.ReadFile(filepath.Join(root, "a.bin")); string(got) != "s3cret" {
+		t.Fatalf("expected a.bin pulled, got %q", got)
+	}
+	if pulled := pullTimes(deps, &config.Loaded{Path: cfgPath}); len(pulled) != 2 {
+		t.Fatalf("expected the agent pulls recorded, got %v", pulled)
+	}
+
+	deps.SignalContext = func() (context.Context, context.CancelFunc) { return context.WithCancel(context.Background()) }
+	busy, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer busy.Close()
+	for _, tc := range []struct {
+		args    []string
+		code    int
+		wantErr string
+	}{
+		{[]string{"extra"}, 2, "agent takes no arguments"},
+		{[]string{"--listen", "7777"}, 2, `invalid --listen "7777": address 7777: missing port in address`},
+		{[]string{"--listen", "0.0.0.0:0"}, 2, `invalid --listen "0.0.0.0:0": the agent only listens on a loopback address`},
+		{[]string{"--listen", "example.com:80"}, 2, "only listens on a loopback address"},
+		{[]string{"--listen", busy.Addr().String()}, 1, "agent: listen tcp " + busy.Addr().String()},
+	} {
+		if code, errOut := run(&bytes.Buffer{}, tc.args...); code != tc.code || !strings.Contains(errOut, tc.wantErr) {
+			t.Fatalf("%v: expected %d %q, got %d %q", tc.args, tc.code, tc.wantErr, code, errOut)
+		}
+	}
+
+	var errBuf bytes.Buffer
+	if code := runAgent(commandContext{stdout: &failingWriter{}, stderr: &errBuf, configPath: cfgPath, deps: deps}, []string{"--listen", "localhost:0"}); code != 1 {
+		t.Fatalf("expected endpoint output failure, got %d %q", code, errBuf.String())
+	}
+}
+
+func TestRunAgent_Socket(t *testing.T) {
+	root := t.TempDir()
+	cfgPath := writeConfig(t, root, `{"organization_id":"org","project_id":"proj","region":"fr-par","mapping":{"a-dev":{"file":"a.bin","format":"raw"}}}`)
+	deps := baseDeps(func(config.Config, string) (SecretAPI, error) { return newFakeSecretAPI(), nil })
+	deps.Getenv = func(key string) string { return map[string]string{"DEV_VAULT_EXPERIMENTAL": "agent"}[key] }
+	deps.UserConfigDir = func() (string, error) { return t.TempDir(), nil }
+	signalCtx, cancel := context.WithCancel(context.Background())
+	deps.SignalContext = func() (context.Context, context.CancelFunc) { return signalCtx, cancel }
+	socket := filepath.Join(root, "agent.sock")
+
+	var endpoint agentEndpoint
+	var status int
+	var mode os.FileMode
+	stdout := &hookWriter{onWrite: func(text string) error {
+		defer cancel()
+		if err := json.Unmarshal([]byte(text), &endpoint); err != nil {
+			t.Fatalf("endpoint line %q: %v", text, err)
+		}
+		if info, err := os.Stat(socket); err == nil {
+			mode = info.Mode().Perm()
+		}
+		transport := &http.Transport{DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, "unix", endpoint.Socket)
+		}}
+		defer transport.CloseIdleConnections()
+		req, _ := http.NewRequest("GET", "http://agent/v1/mappings", nil)
+		req.Header.Set("Authorization", "Bearer "+endpoint.Token)
+		resp, err := (&http.Client{Transport: transport}).Do(req)
+		if err != nil {
+			t.Fatalf("request over socket: %v", err)
+		}
+		resp.Body.Close()
+		status = resp.StatusCode
+		return nil
+	}}
+	var errBuf bytes.Buffer
+	if code := Run([]string{"dev-vault", "--config", cfgPath, "agent", "--socket", socket}, stdout, &errBuf, deps); code != 130 {
+		t.Fatalf("expected the agent to stop with 130, got %d %q", code, errBuf.String())
+	}
+	if endpoint.Socket != socket || endpoint.URL != "" || status != http.StatusOK || mode != 0o600 {
+		t.Fatalf("unexpected endpoint %+v, status %d, mode %v", endpoint, status, mode)
+	}
+	if _, err := os.Stat(socket); !os.IsNotExist(err) {
+		t.Fatalf("expected the socket removed, got %v", err)
+	}
+
+	errBuf.Reset()
+	if code := Run([]string{"dev-vault", "--config", cfgPath, "agent", "--socket", socket, "--listen", "127.0.0.1:0"}, &bytes.Buffer{}, &errBuf, deps); code != 2 || !strings.Contains(errBuf.String(), "--socket cannot be combined with --listen") {
+		t.Fatalf("expected a usage error, got %d %q", code, errBuf.String())
+	}
+}
@@ -1,6 +1,10 @@
 package cli
 
-import "io"
+import (
+	"io"
+
+	"github.com/bsmartlabs/dev-vault/internal/i18n"
+)
 
 type commandFlagKind int
 
@@ -25,18 +29,67 @@ type commandDoc struct {
 }
 
 type commandDef struct {
-	Name      string
-	Summary   string
-	Flags     []commandFlagDef
-	Doc       commandDoc
-	RunParsed func(commandContext, *parsedCommand) int
+	Name    string
+	Summary string
+	Flags   []commandFlagDef
+	// Constraints are checked when the flags are parsed, so a nonsensical
+	// combination fails before anything runs.
+	Constraints []flagConstraint
+	Doc         commandDoc
+	RunParsed   func(commandContext, *parsedCommand) int
+	// Experimental names the feature gating the command; empty for stable
+	// commands.
+	Experimental string
+	// PassThrough stops flag parsing after the first positional argument, so
+	// the rest reaches another program as written.
+	PassThrough bool
 }
 
 var commandDefs = []commandDef{
 	versionCommandDef,
+	initCommandDef,
+	configCommandDef,
 	listCommandDef,
+	searchCommandDef,
 	pullCommandDef,
 	pushCommandDef,
+	syncCommandDef,
+	ciCommandDef,
+	generateCommandDef,
+	importEnvCommandDef,
+	importEnvDirCommandDef,
+	dbCommandDef,
+	sshCommandDef,
+	certCommandDef,
+	getCommandDef,
+	fixturesCommandDef,
+	versionsCommandDef,
+	rollbackCommandDef,
+	deleteCommandDef,
+	deleteVersionCommandDef,
+	gcCommandDef,
+	execCommandDef,
+	promptCommandDef,
+	projectsCommandDef,
+	regionsCommandDef,
+	reportCommandDef,
+	lintNamesCommandDef,
+	doctorCommandDef,
+	validateCommandDef,
+	accessCommandDef,
+	statusCommandDef,
+	stateCommandDef,
+	telemetryCommandDef,
+	disableMappingCommandDef,
+	enableMappingCommandDef,
+	agentCommandDef,
+	whereCommandDef,
+}
+
+func init() {
+	// completion lists the catalog, which it cannot be part of in the
+	// initializer above.
+	commandDefs = append(commandDefs, completionCommandDef)
 }
 
 func commandForName(name string) (commandDef, bool) {
@@ -48,13 +101,13 @@ func commandForName(name string) (commandDef, bool) {
 	return commandDef{}, false
 }
 
-func usageForCommand(name string) (func(io.Writer) error, bool) {
+func usageForCommand(name string, msg i18n.Localizer) (func(io.Writer) error, bool) {
 	def, ok := commandForName(name)
 	if !ok {
 		return nil, false
 	}
 	return func(w io.Writer) error {
-		return printCommandUsage(w, def)
+		return printCommandUsage(w, msg, def)
 	}, true
 }
 
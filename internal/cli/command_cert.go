@@ -0,0 +1,106 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/bsmartlabs/dev-vault/internal/certinfo"
+	"github.com/bsmartlabs/dev-vault/internal/config"
+	"github.com/bsmartlabs/dev-vault/internal/i18n"
+	"github.com/bsmartlabs/dev-vault/internal/secretcontract"
+	"github.com/bsmartlabs/dev-vault/internal/secretsync"
+)
+
+var certCommandDef = commandDef{
+	Name:    "cert",
+	Summary: "Show subject, SANs, and expiry of a certificate secret",
+	Flags: []commandFlagDef{
+		{Name: "json", Kind: commandFlagBool, Help: "Output JSON"},
+	},
+	Doc: commandDoc{
+		Synopsis: "dev-vault [--config <path>] [--profile <name>] cert info <secret-dev> [--json]",
+		Description: []string{
+			"Reads the latest enabled version of a mapped certificate secret and prints the",
+			"subject, issuer, SANs, serial, and validity of each certificate in the PEM bundle.",
+			"Nothing is written to disk.",
+		},
+		Notes: []string{
+			"A private key in the bundle is reported as present; its contents are never printed.",
+		},
+		Examples: []string{
+			"dev-vault cert info api-tls-dev",
+			"dev-vault cert info api-tls-dev --json",
+		},
+	},
+	RunParsed: runCertParsed,
+}
+
+func runCert(ctx commandContext, argv []string) int {
+	return runCommand(ctx, argv, certCommandDef)
+}
+
+func runCertParsed(ctx commandContext, parsed *parsedCommand) int {
+	return newCommandRuntime(ctx, parsed).execute(func(loaded *config.Loaded, service secretsync.Service) error {
+		args := parsed.fs.Args()
+		if len(args) != 2 || args[0] != "info" {
+			return usageError(i18n.Errorf(i18n.MsgExpectedUsage, "cert info <secret-dev>"))
+		}
+		targets, err := selectMappingTargetsForMode(loaded.Cfg.Mapping, false, args[1:], commandModePull)
+		if err != nil {
+			return err
+		}
+		target := targets[0]
+		access, err := service.Access(target)
+		if err != nil {
+			return runtimeError(err)
+		}
+		if string(access.Type) != secretcontract.TypeCertificate {
+			return usageError(i18n.Errorf(i18n.MsgWrongSecretType, "cert info", target.Name, access.Type, secretcontract.TypeCertificate))
+		}
+		bundle, err := certinfo.Parse(access.Data)
+		if err != nil {
+			return runtimeError(i18n.Errorf(i18n.MsgTargetError, "cert info", target.Name, err))
+		}
+		if parsed.Bool("json") {
+			enc := json.NewEncoder(ctx.stdout)
+			enc.SetIndent("", "  ")
+			if err := enc.Encode(bundle); err != nil {
+				return outputError(err)
+			}
+			return nil
+		}
+		if err := writeCertInfo(ctx.stdout, bundle, ctx.deps.Now()); err != nil {
+			return outputError(err)
+		}
+		return nil
+	})
+}
+
+func writeCertInfo(w io.Writer, bundle certinfo.Bundle, now time.Time) error {
+	var b strings.Builder
+	for i, cert := range bundle.Certificates {
+		sans := strings.Join(cert.SANs, ", ")
+		if sans == "" {
+			sans = "(none)"
+		}
+		validity := "expired"
+		if left := cert.NotAfter.Sub(now); left > 0 {
+			validity = fmt.Sprintf("expires in %d days", int(left.Hours()/24))
+		}
+		fmt.Fprintf(&b, "certificate %d of %d\n", i+1, len(bundle.Certificates))
+		fmt.Fprintf(&b, "  subject:    %s\n", cert.Subject)
+		fmt.Fprintf(&b, "  issuer:     %s\n", cert.Issuer)
+		fmt.Fprintf(&b, "  sans:       %s\n", sans)
+		fmt.Fprintf(&b, "  serial:     %s\n", cert.Serial)
+		fmt.Fprintf(&b, "  not before: %s\n", cert.NotBefore.Format(time.RFC3339))
+		fmt.Fprintf(&b, "  not after:  %s (%s)\n", cert.NotAfter.Format(time.RFC3339), validity)
+	}
+	if bundle.HasPrivateKey {
+		b.WriteString("private key: present (not shown)\n")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,169 @@
+package cli
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bsmartlabs/dev-vault/internal/certinfo"
+	"github.com/bsmartlabs/dev-vault/internal/config"
+	secret "github.com/scaleway/scaleway-sdk-go/api/secret/v1beta1"
+)
+
+func testCertificatePEM(t *testing.T, cn string, notAfter time.Time, withKey bool) []byte {
+	t.Helper()
+	_, priv, _ := ed25519.GenerateKey(rand.Reader)
+	template := x509.Certificate{SerialNumber: big.NewInt(42), Subject: pkix.Name{CommonName: cn}, DNSNames: []string{cn}, NotBefore: notAfter.Add(-365 * 24 * time.Hour), NotAfter: notAfter}
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, priv.Public(), priv)
+	if err != nil {
+		t.Fatalf("create certificate: %v", err)
+	}
+	out := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	if withKey {
+		keyDER, _ := x509.MarshalPKCS8PrivateKey(priv)
+		out = append(out, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER})...)
+	}
+	return out
+}
+
+func certTestSetup(t *testing.T) (string, *fakeSecretAPI, Dependencies, []byte) {
+	t.Helper()
+	dir := t.TempDir()
+	cfgPath := writeConfig(t, dir, `{"organization_id":"org","project_id":"proj","region":"fr-par","mapping":{
+		"api-tls-dev":{"file":"api.pem"},
+		"soon-tls-dev":{"file":"soon.pem"},
+		"old-tls-dev":{"file":"old.pem"},
+		"bad-tls-dev":{"file":"bad.pem"},
+		"plain-dev":{"file":"plain"},
+		"push-tls-dev":{"file":"push.pem","mode":"push"}
+	}}`)
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	api := newFakeSecretAPI()
+	add := func(name string, typ secret.SecretType, payload []byte) {
+		sec := api.AddSecret("proj", name, "/", typ)
+		api.AddEnabledVersion(sec.ID, payload)
+	}
+	apiPEM := testCertificatePEM(t, "api.local", now.Add(400*24*time.Hour), true)
+	add("api-tls-dev", secret.SecretTypeCertificate, apiPEM)
+	add("soon-tls-dev", secret.SecretTypeCertificate, testCertificatePEM(t, "soon.local", now.Add(10*24*time.Hour+time.Hour), false))
+	add("old-tls-dev", secret.SecretTypeCertificate, testCertificatePEM(t, "old.local", now.Add(-48*time.Hour), false))
+	add("bad-tls-dev", secret.SecretTypeCertificate, []byte("not pem"))
+	add("plain-dev", secret.SecretTypeOpaque, []byte("x"))
+	deps := baseDeps(func(config.Config, string) (SecretAPI, error) { return api, nil })
+	deps.Now = func() time.Time { return now }
+	return cfgPath, api, deps, apiPEM
+}
+
+func TestRunCertInfo(t *testing.T) {
+	cfgPath, api, deps, apiPEM := certTestSetup(t)
+	run := func(args ...string) (int, string, string) {
+		var out, errBuf bytes.Buffer
+		code := Run(append([]string{"dev-vault", "--config", cfgPath, "cert"}, args...), &out, &errBuf, deps)
+		return code, out.String(), errBuf.String()
+	}
+
+	code, out, errOut := run("info", "api-tls-dev")
+	if code != 0 {
+		t.Fatalf("cert info: %d %q", code, errOut)
+	}
+	for _, want := range []string{
+		"certificate 1 of 1\n",
+		"  subject:    CN=api.local\n",
+		"  sans:       api.local\n",
+		"  serial:     2a\n",
+		"(expires in 400 days)\n",
+		"private key: present (not shown)\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("missing %q in:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "BEGIN") || strings.Contains(out, string(apiPEM[:40])) {
+		t.Fatal("cert info must never print PEM material")
+	}
+	if code, out, _ := run("info", "old-tls-dev"); code != 0 || !strings.Contains(out, "(expired)\n") || strings.Contains(out, "private key") {
+		t.Fatalf("unexpected expired output: %d\n%s", code, out)
+	}
+
+	var noSANs bytes.Buffer
+	if err := writeCertInfo(&noSANs, certinfo.Bundle{Certificates: []certinfo.Certificate{{Subject: "CN=x"}}}, time.Now()); err != nil || !strings.Contains(noSANs.String(), "  sans:       (none)\n") {
+		t.Fatalf("unexpected output without SANs: %v\n%s", err, noSANs.String())
+	}
+
+	code, out, _ = run("info", "api-tls-dev", "--json")
+	var bundle certinfo.Bundle
+	if code != 0 || json.Unmarshal([]byte(out), &bundle) != nil || bundle.Certificates[0].Subject != "CN=api.local" || !bundle.HasPrivateKey {
+		t.Fatalf("unexpected json output: %d %s", code, out)
+	}
+
+	for _, tc := range []struct {
+		args    []string
+		code    int
+		wantErr string
+	}{
+		{[]string{"info"}, 2, "expected: cert info <secret-dev>"},
+		{[]string{"show", "api-tls-dev"}, 2, "expected: cert info <secret-dev>"},
+		{[]string{"info", "api-tls"}, 2, "refusing non-dev secret name"},
+		{[]string{"info", "push-tls-dev"}, 2, "not allowed in pull mode"},
+		{[]string{"info", "plain-dev"}, 2, "secret type is opaque, expected certificate"},
+		{[]string{"info", "bad-tls-dev"}, 1, "cert info bad-tls-dev: no PEM certificate found"},
+	} {
+		if code, _, errOut := run(tc.args...); code != tc.code || !strings.Contains(errOut, tc.wantErr) {
+			t.Fatalf("%v: expected %d %q, got %d %q", tc.args, tc.code, tc.wantErr, code, errOut)
+		}
+	}
+
+	for _, args := range [][]string{{"info", "api-tls-dev"}, {"info", "api-tls-dev", "--json"}} {
+		var errBuf bytes.Buffer
+		if code := runCert(commandContext{stdout: &failingWriter{}, stderr: &errBuf, configPath: cfgPath, deps: deps}, args); code != 1 {
+			t.Fatalf("%v: expected output error, got %d", args, code)
+		}
+	}
+	api.accessErr = errors.New("access boom")
+	if code, _, errOut := run("info", "api-tls-dev"); code != 1 || !strings.Contains(errOut, "access boom") {
+		t.Fatalf("expected access error, got %d %q", code, errOut)
+	}
+}
+
+func TestRunPullCertificateExpiry(t *testing.T) {
+	cfgPath, _, deps, _ := certTestSetup(t)
+	run := func(args ...string) (int, string, string) {
+		var out, errBuf bytes.Buffer
+		code := Run(append([]string{"dev-vault", "--config", cfgPath, "pull"}, args...), &out, &errBuf, deps)
+		return code, out.String(), errBuf.String()
+	}
+
+	code, out, errOut := run("api-tls-dev", "soon-tls-dev", "old-tls-dev", "plain-dev")
+	if code != 0 {
+		t.Fatalf("pull: %d %q", code, errOut)
+	}
+	if !strings.Contains(out, "pulled api-tls-dev -> api.pem (rev=1 type=certificate expires=2027-11-19)\n") ||
+		!strings.Contains(out, "pulled plain-dev -> plain (rev=1 type=opaque)\n") {
+		t.Fatalf("unexpected pull output:\n%s", out)
+	}
+	wantWarnings := "warning: certificate soon-tls-dev expires in 10 days (2026-10-25)\nwarning: certificate old-tls-dev expired on 2026-10-13\n"
+	if errOut != wantWarnings {
+		t.Fatalf("unexpected warnings:\n%s", errOut)
+	}
+
+	if code, _, errOut := run("--overwrite", "--expiry-warning", "500d", "api-tls-dev"); code != 0 || !strings.Contains(errOut, "certificate api-tls-dev expires in 400 days") {
+		t.Fatalf("expected widened warning window, got %d %q", code, errOut)
+	}
+	if code, _, errOut := run("--expiry-warning", "soon", "api-tls-dev"); code != 2 || !strings.Contains(errOut, `invalid --expiry-warning: invalid age "soon"`) {
+		t.Fatalf("expected usage error, got %d %q", code, errOut)
+	}
+
+	var out2 bytes.Buffer
+	if code := Run([]string{"dev-vault", "--config", cfgPath, "pull", "--overwrite", "soon-tls-dev"}, &out2, &failingWriter{}, deps); code != 1 {
+		t.Fatalf("expected warning output error, got %d", code)
+	}
+}
@@ -0,0 +1,91 @@
+package cli
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/bsmartlabs/dev-vault/internal/ciplatform"
+	"github.com/bsmartlabs/dev-vault/internal/config"
+	"github.com/bsmartlabs/dev-vault/internal/i18n"
+	"github.com/bsmartlabs/dev-vault/internal/secretsync"
+)
+
+var ciCommandDef = commandDef{
+	Name:    "ci",
+	Summary: "Print a CI job that installs dev-vault and pulls mapped secrets",
+	Flags: []commandFlagDef{
+		{Name: "platform", Kind: commandFlagString, ValueName: "<github-actions|gitlab-ci>", Help: "CI platform to generate for (required)"},
+	},
+	Doc: commandDoc{
+		Synopsis: "dev-vault [--config <path>] ci bootstrap --platform <github-actions|gitlab-ci>",
+		Description: []string{
+			"Prints a ready-to-paste job that installs this dev-vault release, authenticates",
+			"with SCW_ACCESS_KEY and SCW_SECRET_KEY from the platform's secret store, and runs",
+			"'pull --all --overwrite' into the workspace.",
+			"The job follows the current manifest: --config is passed when it is not ./.scw.json,",
+			"and --ci-export is added when a pulled mapping uses format dotenv.",
+		},
+		Notes: []string{
+			"Run it from the repository root; the --config path in the job is relative to it.",
+			"Development builds install @latest.",
+			"Nothing is written; redirect the output or paste it into the pipeline file.",
+		},
+		Examples: []string{
+			"dev-vault ci bootstrap --platform github-actions",
+			"dev-vault ci bootstrap --platform gitlab-ci >> .gitlab-ci.yml",
+		},
+	},
+	RunParsed: runCIParsed,
+}
+
+func runCI(ctx commandContext, argv []string) int {
+	return runCommand(ctx, argv, ciCommandDef)
+}
+
+func runCIParsed(ctx commandContext, parsed *parsedCommand) int {
+	return newCommandRuntime(ctx, parsed).executeLocal(func(loaded *config.Loaded, _ secretsync.Service) error {
+		args := parsed.fs.Args()
+		if len(args) != 1 || args[0] != "bootstrap" {
+			return usageError(i18n.Errorf(i18n.MsgExpectedSubcommand, "bootstrap"))
+		}
+		platform := ciplatform.Platform(parsed.String("platform"))
+		if platform == "" {
+			return usageError(i18n.Errorf(i18n.MsgRequiresFlag, "ci bootstrap", "--platform"))
+		}
+		targets := mappingTargetsForMode(loaded.Cfg.Mapping, commandModePull)
+		if len(targets) == 0 {
+			return usageError(i18n.Errorf(i18n.MsgNoMappingSelected, commandModePull))
+		}
+
+		opts := ciplatform.BootstrapOptions{Version: "latest"}
+		if config.IsReleaseVersion(ctx.deps.Version) {
+			opts.Version = "v" + strings.TrimPrefix(ctx.deps.Version, "v")
+		}
+		wd, err := ctx.deps.Getwd()
+		if err != nil {
+			return runtimeError(i18n.Errorf(i18n.MsgGetwd, err))
+		}
+		configPath := loaded.Path
+		if rel, err := filepath.Rel(wd, loaded.Path); err == nil {
+			configPath = filepath.ToSlash(rel)
+		}
+		if configPath != config.DefaultConfigName {
+			opts.ConfigPath = configPath
+		}
+		for _, target := range targets {
+			if target.Entry.Format == secretsync.MappingFormatDotenv {
+				opts.CIExport = true
+			}
+		}
+
+		snippet, err := platform.Bootstrap(opts)
+		if err != nil {
+			return usageError(err)
+		}
+		if _, err := fmt.Fprint(ctx.stdout, snippet); err != nil {
+			return outputError(err)
+		}
+		return nil
+	})
+}
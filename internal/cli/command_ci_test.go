@@ -0,0 +1,80 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bsmartlabs/dev-vault/internal/config"
+)
+
+func TestRunCIBootstrap(t *testing.T) {
+	root := t.TempDir()
+	cfgPath := writeConfig(t, root, `{"organization_id":"org","project_id":"proj","region":"fr-par","mapping":{
+		"app-env-dev":{"file":".env","format":"dotenv"},
+		"tls-dev":{"file":"tls.pem","mode":"pull"},
+		"upload-env-dev":{"file":"upload.env","format":"dotenv","mode":"push"}
+	}}`)
+	deps := baseDeps(func(config.Config, string) (SecretAPI, error) { return nil, errors.New("provider must not be opened") })
+	deps.Version = "1.4.0"
+	deps.Getwd = func() (string, error) { return root, nil }
+	run := func(args ...string) (int, string, string) {
+		var out, errBuf bytes.Buffer
+		code := Run(append([]string{"dev-vault"}, args...), &out, &errBuf, deps)
+		return code, out.String(), errBuf.String()
+	}
+
+	code, out, errOut := run("ci", "bootstrap", "--platform", "github-actions")
+	if code != 0 || !strings.Contains(out, "@v1.4.0\n") || !strings.Contains(out, "run: dev-vault pull --all --overwrite --ci-export\n") {
+		t.Fatalf("unexpected github output: %d %q %q", code, out, errOut)
+	}
+
+	// A config elsewhere is passed with --config; without dotenv mappings
+	// there is nothing to export.
+	sub := filepath.Join(root, "deploy")
+	if err := os.Mkdir(sub, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	rawCfg := writeConfig(t, sub, `{"organization_id":"org","project_id":"proj","region":"fr-par","mapping":{"tls-dev":{"file":"tls.pem"}}}`)
+	deps.Version = "dev"
+	code, out, errOut = run("--config", rawCfg, "ci", "bootstrap", "--platform", "gitlab-ci")
+	if code != 0 || !strings.Contains(out, "@latest\n") || !strings.Contains(out, "    - dev-vault --config deploy/.scw.json pull --all --overwrite\n") || strings.Contains(out, "dotenv:") {
+		t.Fatalf("unexpected gitlab output: %d %q %q", code, out, errOut)
+	}
+
+	pushOnly := writeConfig(t, sub, `{"organization_id":"org","project_id":"proj","region":"fr-par","mapping":{"a-dev":{"file":"a","mode":"push"}}}`)
+	for _, tc := range []struct {
+		args []string
+		want string
+	}{
+		{[]string{"ci", "--platform", "gitlab-ci"}, "expected subcommand: bootstrap"},
+		{[]string{"ci", "bootstrap"}, "ci bootstrap requires --platform"},
+		{[]string{"ci", "bootstrap", "--platform", "circleci"}, "no bootstrap snippet for CI platform \"circleci\""},
+		{[]string{"--config", pushOnly, "ci", "bootstrap", "--platform", "gitlab-ci"}, "no mapping entries selected for pull"},
+	} {
+		if code, _, errOut := run(tc.args...); code != 2 || !strings.Contains(errOut, tc.want) {
+			t.Fatalf("%v: expected usage error %q, got %d %q", tc.args, tc.want, code, errOut)
+		}
+	}
+	writeConfig(t, sub, `{"organization_id":"org","project_id":"proj","region":"fr-par","mapping":{"tls-dev":{"file":"tls.pem"}}}`)
+
+	calls := 0
+	deps.Getwd = func() (string, error) {
+		if calls++; calls > 1 {
+			return "", errors.New("boom")
+		}
+		return root, nil
+	}
+	if code, _, errOut := run("ci", "bootstrap", "--platform", "gitlab-ci"); code != 1 || !strings.Contains(errOut, "getwd: boom") {
+		t.Fatalf("expected getwd error, got %d %q", code, errOut)
+	}
+	deps.Getwd = func() (string, error) { return root, nil }
+
+	var errBuf bytes.Buffer
+	if code := runCI(commandContext{stdout: &failingWriter{}, stderr: &errBuf, configPath: cfgPath, deps: deps}, []string{"bootstrap", "--platform", "gitlab-ci"}); code != 1 {
+		t.Fatalf("expected output error, got %d", code)
+	}
+}
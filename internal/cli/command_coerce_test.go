@@ -0,0 +1,43 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/bsmartlabs/dev-vault/internal/config"
+	secret "github.com/scaleway/scaleway-sdk-go/api/secret/v1beta1"
+)
+
+func TestRunCoerce(t *testing.T) {
+	dir := t.TempDir()
+	cfgPath := writeConfig(t, dir, `{"organization_id":"org","project_id":"proj","region":"fr-par","mapping":{
+		"app-env-dev":{"file":".env","format":"dotenv","type":"key_value"}
+	}}`)
+	api := newFakeSecretAPI()
+	sec := api.AddSecret("proj", "app-env-dev", "/", secret.SecretTypeOpaque)
+	api.AddEnabledVersion(sec.ID, []byte(`{"A":"1"}`))
+	deps := baseDeps(func(config.Config, string) (SecretAPI, error) { return api, nil })
+	run := func(args ...string) (int, string, string) {
+		var out, errBuf bytes.Buffer
+		code := Run(append([]string{"dev-vault", "--config", cfgPath}, args...), &out, &errBuf, deps)
+		return code, out.String(), errBuf.String()
+	}
+
+	if code, _, errOut := run("pull", "app-env-dev"); code != 1 || !strings.Contains(errOut, "secret app-env-dev is opaque but mapping.type is key_value (use --coerce") {
+		t.Fatalf("expected strict mismatch, got %d %q", code, errOut)
+	}
+	code, out, errOut := run("pull", "--coerce", "app-env-dev")
+	if code != 0 || out != "pulled app-env-dev -> .env (rev=1 type=opaque)\n" || errOut != "warning: coerced app-env-dev: secret is opaque, mapping.type is key_value\n" {
+		t.Fatalf("unexpected coerced pull: %d %q %q", code, out, errOut)
+	}
+	code, out, errOut = run("push", "--coerce", "app-env-dev")
+	if code != 0 || out != "pushed app-env-dev (rev=2)\n" || !strings.Contains(errOut, "warning: coerced app-env-dev") {
+		t.Fatalf("unexpected coerced push: %d %q %q", code, out, errOut)
+	}
+
+	var out2 bytes.Buffer
+	if code := Run([]string{"dev-vault", "--config", cfgPath, "pull", "--overwrite", "--coerce", "app-env-dev"}, &out2, &failingWriter{}, deps); code != 1 {
+		t.Fatalf("expected warning output error, got %d", code)
+	}
+}
@@ -0,0 +1,141 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/bsmartlabs/dev-vault/internal/i18n"
+	"github.com/bsmartlabs/dev-vault/internal/shellcompletion"
+)
+
+var completionCommandDef = commandDef{
+	Name:    "completion",
+	Summary: "Print or install shell tab completion",
+	Flags: []commandFlagDef{
+		{Name: "install", Kind: commandFlagBool, Help: "Add the line loading completion to the shell's startup file"},
+		{Name: "names", Kind: commandFlagBool, Help: "Print the secret names completing a secret argument, one per line"},
+		{Name: "uninstall", Kind: commandFlagBool, Help: "Remove the line added by --install"},
+	},
+	Doc: commandDoc{
+		Synopsis: "dev-vault [--config <path>] completion [bash|zsh|fish] [--install | --uninstall | --names]",
+		Description: []string{
+			"Prints the completion script for commands and options of the shell, which defaults to $SHELL.",
+			"--install adds a line loading it to ~/.bashrc, ~/.zshrc ($ZDOTDIR), or",
+			"~/.config/fish/config.fish ($XDG_CONFIG_HOME), between '# >>> dev-vault completion >>>'",
+			"marker comments. Running it again changes nothing; --uninstall removes the marked block",
+			"and leaves the rest of the file untouched.",
+			"",
+			"In bash and zsh, secret arguments complete with the mapping names of the manifest. With",
+			"DEV_VAULT_COMPLETE_REMOTE=1 they also complete with the project's remote -dev names, cached",
+			"in the state dir for 10 minutes and refreshed by list; a refresh never holds up the shell",
+			"more than 100ms, and offline the cached names are used. --names prints what would be offered.",
+		},
+		Notes: []string{
+			"A startup file that is a symbolic link is edited through the link; one with other hard",
+			"links is refused. The installed line does nothing once dev-vault is no longer on PATH.",
+		},
+		Examples: []string{
+			"dev-vault completion --install",
+			"dev-vault completion zsh --uninstall",
+			"dev-vault completion bash > /etc/bash_completion.d/dev-vault",
+		},
+	},
+	RunParsed: runCompletionParsed,
+}
+
+func runCompletion(ctx commandContext, argv []string) int {
+	return runCommand(ctx, argv, completionCommandDef)
+}
+
+func runCompletionParsed(ctx commandContext, parsed *parsedCommand) int {
+	return newCommandRuntime(ctx, parsed).executeStandalone(func() error {
+		args := parsed.fs.Args()
+		install, uninstall := parsed.Bool("install"), parsed.Bool("uninstall")
+		switch {
+		case parsed.Bool("names") && (len(args) > 0 || install || uninstall):
+			return usageError(i18n.Errorf(i18n.MsgCompletionNamesAlone))
+		case parsed.Bool("names"):
+			return writeCompletionNames(ctx, parsed)
+		case len(args) > 1:
+			return usageError(i18n.Errorf(i18n.MsgCompletionOneShell))
+		case install && uninstall:
+			return usageError(i18n.Errorf(i18n.MsgMutuallyExclusive, "--install", "--uninstall"))
+		}
+		name := ctx.deps.Getenv("SHELL")
+		if len(args) == 1 {
+			name = args[0]
+		}
+		shell, err := shellcompletion.Detect(name)
+		if err != nil && len(args) == 0 {
+			err = i18n.Errorf(i18n.MsgCompletionNameShell, err, strings.Join(shellcompletion.Shells(), "|"))
+		}
+		if err != nil {
+			return usageError(err)
+		}
+
+		if !install && !uninstall {
+			script, _ := shellcompletion.Script(shell, completionSpec())
+			if _, err := fmt.Fprint(ctx.stdout, script); err != nil {
+				return outputError(err)
+			}
+			return nil
+		}
+
+		home, err := ctx.deps.UserHomeDir()
+		if err != nil {
+			return runtimeError(i18n.Errorf(i18n.MsgLocateHomeDir, err))
+		}
+		path, _ := shellcompletion.RCFile(shell, home, ctx.deps.Getenv)
+		var message string
+		if install {
+			line, _ := shellcompletion.SourceLine(shell, "dev-vault")
+			changed, err := shellcompletion.Install(path, line)
+			if err != nil {
+				return runtimeError(err)
+			}
+			message = "completion already installed in " + path
+			if changed {
+				message = "installed completion in " + path + "; open a new shell to use it"
+			}
+		} else {
+			changed, err := shellcompletion.Uninstall(path)
+			if err != nil {
+				return runtimeError(err)
+			}
+			message = "no completion installed in " + path
+			if changed {
+				message = "removed completion from " + path
+			}
+		}
+		if _, err := fmt.Fprintln(ctx.stdout, message); err != nil {
+			return outputError(err)
+		}
+		return nil
+	})
+}
+
+// completionSpec describes the global options and the listed commands with
+// their options. Commands whose synopsis takes a positional <secret-dev>
+// right after the command complete it with the names printed by completion
+// --names; the ones starting with a subcommand word such as db connect do not.
+func completionSpec() shellcompletion.Spec {
+	global := flag.NewFlagSet("dev-vault", flag.ContinueOnError)
+	bindGlobalOptionFlags(global, &globalOptions{})
+	spec := shellcompletion.Spec{Program: "dev-vault", NamesArgs: "completion --names", NamesForward: []string{"config", "profile", "env"}}
+	global.VisitAll(func(f *flag.Flag) {
+		boolFlag, _ := f.Value.(interface{ IsBoolFlag() bool })
+		spec.Global = append(spec.Global, shellcompletion.Flag{Name: f.Name, Help: f.Usage, TakesValue: boolFlag == nil})
+	})
+	for _, def := range listedCommandDefs() {
+		_, rest, _ := strings.Cut(def.Doc.Synopsis, " "+def.Name+" ")
+		subcommand := rest != "" && rest[0] >= 'a' && rest[0] <= 'z'
+		positional := strings.ReplaceAll(rest, "--name <secret-dev>", "")
+		command := shellcompletion.Command{Name: def.Name, Summary: def.Summary, Names: !subcommand && strings.Contains(positional, "<secret-dev>")}
+		for _, flagDef := range def.Flags {
+			command.Flags = append(command.Flags, shellcompletion.Flag{Name: flagDef.Name, Help: flagDef.Help, TakesValue: flagDef.Kind != commandFlagBool})
+		}
+		spec.Commands = append(spec.Commands, command)
+	}
+	return spec
+}
@@ -0,0 +1,199 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bsmartlabs/dev-vault/internal/config"
+	secret "github.com/scaleway/scaleway-sdk-go/api/secret/v1beta1"
+)
+
+func TestRunCompletion(t *testing.T) {
+	home := t.TempDir()
+	env := map[string]string{"SHELL": "/bin/zsh"}
+	deps := baseDeps(func(config.Config, string) (SecretAPI, error) { return nil, nil })
+	deps.Getenv = func(key string) string { return env[key] }
+	deps.UserHomeDir = func() (string, error) { return home, nil }
+	run := func(args ...string) (int, string, string) {
+		var out, errBuf bytes.Buffer
+		code := Run(append([]string{"dev-vault", "completion"}, args...), &out, &errBuf, deps)
+		return code, out.String(), errBuf.String()
+	}
+
+	code, out, errOut := run()
+	if code != 0 || !strings.HasPrefix(out, "# zsh completion for dev-vault\n") || errOut != "" {
+		t.Fatalf("unexpected zsh script: %d %q %q", code, out, errOut)
+	}
+	for _, want := range []string{" rollback ", "--to-revision", "--lang|--log-file) ((i++))", "completion) words=\"--install --names --uninstall ", "            pull|push|sync|generate|get|fixtures|versions|rollback|delete|delete-version|gc|exec|disable-mapping|enable-mapping) COMPREPLY="} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("script lacks %q:\n%s", want, out)
+		}
+	}
+	if code, out, _ := run("fish"); code != 0 || !strings.Contains(out, "complete -c dev-vault -n '__fish_seen_subcommand_from pull' -l revision -r -d ") {
+		t.Fatalf("unexpected fish script: %d %q", code, out)
+	}
+
+	rc := filepath.Join(home, ".bashrc")
+	steps := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"bash", "--install"}, "installed completion in " + rc + "; open a new shell to use it\n"},
+		{[]string{"bash", "--install"}, "completion already installed in " + rc + "\n"},
+		{[]string{"bash", "--uninstall"}, "removed completion from " + rc + "\n"},
+		{[]string{"bash", "--uninstall"}, "no completion installed in " + rc + "\n"},
+	}
+	for i, step := range steps {
+		if code, out, errOut := run(step.args...); code != 0 || out != step.want || errOut != "" {
+			t.Fatalf("step %d: %d %q %q", i, code, out, errOut)
+		}
+		if i == 0 {
+			if raw, _ := os.ReadFile(rc); !strings.Contains(string(raw), "source <(dev-vault completion bash)") {
+				t.Fatalf("unexpected rc file %q", raw)
+			}
+		}
+	}
+
+	env["XDG_CONFIG_HOME"] = filepath.Join(home, "xdg")
+	if code, out, _ := run("fish", "--install"); code != 0 || !strings.Contains(out, filepath.Join(home, "xdg", "fish", "config.fish")) {
+		t.Fatalf("unexpected fish install: %d %q", code, out)
+	}
+}
+
+func TestRunCompletion_Errors(t *testing.T) {
+	home := t.TempDir()
+	env := map[string]string{}
+	deps := baseDeps(func(config.Config, string) (SecretAPI, error) { return nil, nil })
+	deps.Getenv = func(key string) string { return env[key] }
+	run := func(deps Dependencies, args ...string) (int, string) {
+		var errBuf bytes.Buffer
+		code := Run(append([]string{"dev-vault", "completion"}, args...), &bytes.Buffer{}, &errBuf, deps)
+		return code, errBuf.String()
+	}
+
+	usage := map[string][]string{
+		"completion takes at most one shell name":                                    {"bash", "zsh"},
+		"--install and --uninstall are mutually exclusive":                           {"--install", "--uninstall"},
+		"--names takes no shell name, --install or --uninstall":                      {"bash", "--names"},
+		"cannot detect the shell: SHELL is not set; name it: dev-vault completion <": nil,
+		`unsupported shell "tcsh" (expected one of: bash, fish, zsh)` + "\n":         {"tcsh"},
+	}
+	for want, args := range usage {
+		if code, errOut := run(deps, args...); code != 2 || !strings.Contains(errOut, want) {
+			t.Fatalf("%v: expected %q, got %d %q", args, want, code, errOut)
+		}
+	}
+
+	if code, errOut := run(deps, "bash", "--install"); code != 1 || !strings.Contains(errOut, "locate home dir: no home dir in tests") {
+		t.Fatalf("expected home dir error, got %d %q", code, errOut)
+	}
+	deps.UserHomeDir = func() (string, error) { return home, nil }
+	if err := os.Mkdir(filepath.Join(home, ".bashrc"), 0o700); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	for _, flag := range []string{"--install", "--uninstall"} {
+		if code, errOut := run(deps, "bash", flag); code != 1 || !strings.Contains(errOut, "refusing to edit "+filepath.Join(home, ".bashrc")) {
+			t.Fatalf("%s: expected refusal, got %d %q", flag, code, errOut)
+		}
+	}
+
+	for _, args := range [][]string{{"zsh"}, {"zsh", "--uninstall"}} {
+		if code := runCompletion(commandContext{stdout: &failingWriter{}, stderr: &bytes.Buffer{}, deps: deps}, args); code != 1 {
+			t.Fatalf("%v: expected output error, got %d", args, code)
+		}
+	}
+}
+
+func TestRunCompletion_Names(t *testing.T) {
+	dir := t.TempDir()
+	cfgPath := writeConfig(t, dir, `{"organization_id":"org","project_id":"proj","region":"fr-par","mapping":{
+		"db-dev":{"file":"db.json"},
+		"api-dev":{"file":"api.json"}
+	}}`)
+	api := newFakeSecretAPI()
+	api.AddSecret("proj", "db-dev", "/", secret.SecretTypeOpaque)
+	api.AddSecret("proj", "remote-dev", "/", secret.SecretTypeOpaque)
+	api.AddSecret("proj", "prod", "/", secret.SecretTypeOpaque)
+	env := map[string]string{}
+	now := time.Unix(1000, 0)
+	state := t.TempDir()
+	deps := baseDeps(func(config.Config, string) (SecretAPI, error) { return api, nil })
+	deps.Getenv = func(key string) string { return env[key] }
+	deps.Now = func() time.Time { return now }
+	deps.UserConfigDir = func() (string, error) { return state, nil }
+	run := func(deps Dependencies, args ...string) (int, string, string) {
+		var out, errBuf bytes.Buffer
+		code := Run(append([]string{"dev-vault", "--config", cfgPath}, args...), &out, &errBuf, deps)
+		return code, out.String(), errBuf.String()
+	}
+	names := func(deps Dependencies, want string) {
+		t.Helper()
+		if code, out, errOut := run(deps, "completion", "--names"); code != 0 || out != want || errOut != "" {
+			t.Fatalf("expected %q, got %d %q %q", want, code, out, errOut)
+		}
+	}
+
+	// Mapping names only, without asking the provider.
+	names(deps, "api-dev\ndb-dev\n")
+	if api.listCalls != 0 {
+		t.Fatalf("expected no listing, got %d", api.listCalls)
+	}
+
+	// Remote -dev names are listed once, then served from the cache.
+	env[completionRemoteEnvVar] = "1"
+	names(deps, "api-dev\ndb-dev\nremote-dev\n")
+	names(deps, "api-dev\ndb-dev\nremote-dev\n")
+	if api.listCalls != 1 {
+		t.Fatalf("expected one listing, got %d", api.listCalls)
+	}
+
+	// A stale cache is kept when the refresh fails or is too slow.
+	now = now.Add(completionNamesTTL)
+	api.listErr = errors.New("offline")
+	names(deps, "api-dev\ndb-dev\nremote-dev\n")
+	release := make(chan struct{})
+	defer close(release)
+	slow := deps
+	slow.OpenSecretAPI = func(config.Config, string) (SecretAPI, error) {
+		<-release
+		return nil, errors.New("late")
+	}
+	names(slow, "api-dev\ndb-dev\nremote-dev\n")
+
+	// list refreshes the cache unless it is filtered.
+	api.listErr = nil
+	api.AddSecret("proj", "listed-dev", "/", secret.SecretTypeOpaque)
+	if code, _, _ := run(deps, "list", "--name-contains", "listed"); code != 0 {
+		t.Fatalf("filtered list failed: %d", code)
+	}
+	api.listErr = errors.New("offline")
+	names(deps, "api-dev\ndb-dev\nremote-dev\n")
+	api.listErr = nil
+	if code, _, _ := run(deps, "list"); code != 0 {
+		t.Fatalf("list failed: %d", code)
+	}
+	api.listErr = errors.New("offline")
+	names(deps, "api-dev\ndb-dev\nlisted-dev\nremote-dev\n")
+
+	// Without a state dir or a config nothing fails.
+	noState := deps
+	noState.UserConfigDir = func() (string, error) { return "", errors.New("no state dir") }
+	names(noState, "api-dev\ndb-dev\n")
+	api.listErr = nil
+	if code, _, _ := run(noState, "list"); code != 0 {
+		t.Fatalf("list without state dir failed: %d", code)
+	}
+	var out bytes.Buffer
+	if code := Run([]string{"dev-vault", "--config", filepath.Join(dir, "missing.json"), "completion", "--names"}, &out, &out, deps); code != 0 || out.String() != "" {
+		t.Fatalf("expected silent success without config, got %d %q", code, out.String())
+	}
+
+	if code := runCompletion(commandContext{stdout: &failingWriter{}, stderr: &bytes.Buffer{}, configPath: cfgPath, deps: deps}, []string{"--names"}); code != 1 {
+		t.Fatalf("expected output error, got %d", code)
+	}
+}
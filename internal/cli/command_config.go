@@ -0,0 +1,70 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/bsmartlabs/dev-vault/internal/config"
+	"github.com/bsmartlabs/dev-vault/internal/i18n"
+)
+
+var configCommandDef = commandDef{
+	Name:    "config",
+	Summary: "Format the .scw.json manifest",
+	Flags: []commandFlagDef{
+		{Name: "check", Kind: commandFlagBool, Help: "Only check formatting; exit 1 if the file would change"},
+	},
+	Doc: commandDoc{
+		Synopsis: "dev-vault [--config <path>] config fmt [--check]",
+		Description: []string{
+			"Rewrites .scw.json in canonical form: fixed top-level key order, mapping entries",
+			"sorted by name, two-space indentation, and a trailing newline.",
+			"Values are kept as written; defaults are not added.",
+		},
+		Notes: []string{
+			"--check writes nothing and exits with code 1 when the file is not canonical, for CI.",
+			"The manifest must be valid; fix reported errors first.",
+		},
+		Examples: []string{
+			"dev-vault config fmt",
+			"dev-vault config fmt --check",
+		},
+	},
+	RunParsed: runConfigParsed,
+}
+
+func runConfig(ctx commandContext, argv []string) int {
+	return runCommand(ctx, argv, configCommandDef)
+}
+
+func runConfigParsed(ctx commandContext, parsed *parsedCommand) int {
+	return newCommandRuntime(ctx, parsed).executeStandalone(func() error {
+		args := parsed.fs.Args()
+		if len(args) != 1 || args[0] != "fmt" {
+			return usageError(i18n.Errorf(i18n.MsgExpectedSubcommand, "fmt"))
+		}
+		wd, err := ctx.deps.Getwd()
+		if err != nil {
+			return runtimeError(i18n.Errorf(i18n.MsgGetwd, err))
+		}
+		path, err := config.LocatePath(wd, parsed.configPath)
+		if err != nil {
+			return runtimeError(err)
+		}
+		check := parsed.Bool("check")
+		changed, err := config.FormatFile(path, !check)
+		if err != nil {
+			return runtimeError(err)
+		}
+		if check && changed {
+			return runtimeError(i18n.Errorf(i18n.MsgNotFormatted, path))
+		}
+		line := path + " is already formatted"
+		if changed {
+			line = "formatted " + path
+		}
+		if _, err := fmt.Fprintln(ctx.stdout, line); err != nil {
+			return outputError(err)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,64 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestRunConfigFmt(t *testing.T) {
+	cfgPath := writeConfig(t, t.TempDir(), `{"region":"fr-par","organization_id":"org","project_id":"proj","mapping":{"a-dev":{"file":"a"}}}`)
+	deps := baseDeps(nil)
+	run := func(args ...string) (int, string, string) {
+		var out, errBuf bytes.Buffer
+		code := runConfig(commandContext{stdout: &out, stderr: &errBuf, configPath: cfgPath, deps: deps}, args)
+		return code, out.String(), errBuf.String()
+	}
+
+	if code, _, errOut := run("fmt", "--check"); code != 1 || !strings.Contains(errOut, cfgPath+" is not formatted") {
+		t.Fatalf("expected check failure, got %d %q", code, errOut)
+	}
+	if code, out, errOut := run("fmt"); code != 0 || out != "formatted "+cfgPath+"\n" {
+		t.Fatalf("unexpected fmt: %d %q %q", code, out, errOut)
+	}
+	if raw, _ := os.ReadFile(cfgPath); !strings.HasPrefix(string(raw), "{\n  \"organization_id\": \"org\",") {
+		t.Fatalf("unexpected file: %s", raw)
+	}
+	if code, out, _ := run("fmt", "--check"); code != 0 || out != cfgPath+" is already formatted\n" {
+		t.Fatalf("unexpected check: %d %q", code, out)
+	}
+
+	for _, args := range [][]string{{}, {"lint"}, {"fmt", "extra"}} {
+		if code, _, errOut := run(args...); code != 2 || !strings.Contains(errOut, "expected subcommand: fmt") {
+			t.Fatalf("%v: expected usage error, got %d %q", args, code, errOut)
+		}
+	}
+
+	var errBuf bytes.Buffer
+	if code := runConfig(commandContext{stdout: &failingWriter{}, stderr: &errBuf, configPath: cfgPath, deps: deps}, []string{"fmt"}); code != 1 {
+		t.Fatalf("expected output error, got %d", code)
+	}
+
+	if err := os.WriteFile(cfgPath, []byte(`{"organization_id":"org"}`), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if code, _, errOut := run("fmt"); code != 1 || !strings.Contains(errOut, "missing required field") {
+		t.Fatalf("expected validation error, got %d %q", code, errOut)
+	}
+
+	noCwd := deps
+	noCwd.Getwd = func() (string, error) { return "", errors.New("no cwd") }
+	errBuf.Reset()
+	if code := runConfig(commandContext{stdout: &bytes.Buffer{}, stderr: &errBuf, deps: noCwd}, []string{"fmt"}); code != 1 || !strings.Contains(errBuf.String(), "getwd: no cwd") {
+		t.Fatalf("expected getwd error, got %d %q", code, errBuf.String())
+	}
+	emptyDir := t.TempDir()
+	noConfig := deps
+	noConfig.Getwd = func() (string, error) { return emptyDir, nil }
+	errBuf.Reset()
+	if code := runConfig(commandContext{stdout: &bytes.Buffer{}, stderr: &errBuf, deps: noConfig}, []string{"fmt"}); code != 1 || !strings.Contains(errBuf.String(), "not found") {
+		t.Fatalf("expected not found error, got %d %q", code, errBuf.String())
+	}
+}
@@ -1,8 +1,9 @@
 package cli
 
 import (
-	"fmt"
 	"io"
+
+	"github.com/bsmartlabs/dev-vault/internal/i18n"
 )
 
 type commandContext struct {
@@ -10,14 +11,18 @@ type commandContext struct {
 	stderr          io.Writer
 	configPath      string
 	profileOverride string
-	deps            Dependencies
-}
-
-func printConfigWarnings(w io.Writer, warnings []string) error {
-	for _, warning := range warnings {
-		if _, err := fmt.Fprintf(w, "warning: %s\n", warning); err != nil {
-			return err
-		}
-	}
-	return nil
+	environment     string
+	lang            string
+	msg             i18n.Localizer
+	plain           bool
+	explainConfig   bool
+	enforceVersion  bool
+	ignoreUnknown   bool
+	strictWarnings  bool
+	logFile         string
+	noFsync         bool
+	// invocation is the ID the provider receives with every call of this
+	// run; empty when a test calls a command directly.
+	invocation string
+	deps       Dependencies
 }
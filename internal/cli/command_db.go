@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/bsmartlabs/dev-vault/internal/config"
+	"github.com/bsmartlabs/dev-vault/internal/dbcreds"
+	"github.com/bsmartlabs/dev-vault/internal/i18n"
+	"github.com/bsmartlabs/dev-vault/internal/secretcontract"
+	"github.com/bsmartlabs/dev-vault/internal/secretsync"
+)
+
+var dbCommandDef = commandDef{
+	Name:    "db",
+	Summary: "Open a database client with a database_credentials secret",
+	Flags: []commandFlagDef{
+		{Name: "print", Kind: commandFlagBool, Help: "Print the connection string and client command instead of running it"},
+	},
+	Doc: commandDoc{
+		Synopsis: "dev-vault [--config <path>] [--profile <name>] db connect <secret-dev> [--print]",
+		Description: []string{
+			"Reads the latest enabled version of a mapped database_credentials secret and",
+			"starts psql (postgres) or mysql (mysql, mariadb) connected to it.",
+			"Nothing is written to disk.",
+		},
+		Notes: []string{
+			"The password reaches the client only through PGPASSWORD or MYSQL_PWD in its environment.",
+			"--print shows the connection string and command with the password elided.",
+			"The client's exit code is reported as a failure when it is not zero.",
+		},
+		Examples: []string{
+			"dev-vault db connect billing-db-dev",
+			"dev-vault db connect billing-db-dev --print",
+		},
+	},
+	RunParsed: runDBParsed,
+}
+
+func runDB(ctx commandContext, argv []string) int {
+	return runCommand(ctx, argv, dbCommandDef)
+}
+
+func runDBParsed(ctx commandContext, parsed *parsedCommand) int {
+	return newCommandRuntime(ctx, parsed).execute(func(loaded *config.Loaded, service secretsync.Service) error {
+		args := parsed.fs.Args()
+		if len(args) != 2 || args[0] != "connect" {
+			return usageError(i18n.Errorf(i18n.MsgExpectedUsage, "db connect <secret-dev>"))
+		}
+		targets, err := selectMappingTargetsForMode(loaded.Cfg.Mapping, false, args[1:], commandModePull)
+		if err != nil {
+			return err
+		}
+		access, err := service.Access(targets[0])
+		if err != nil {
+			return runtimeError(err)
+		}
+		if string(access.Type) != secretcontract.TypeDatabaseCreds {
+			return usageError(i18n.Errorf(i18n.MsgWrongSecretType, "db connect", targets[0].Name, access.Type, secretcontract.TypeDatabaseCreds))
+		}
+		creds, err := dbcreds.Parse(access.Data)
+		if err != nil {
+			return runtimeError(i18n.Errorf(i18n.MsgTargetError, "db connect", targets[0].Name, err))
+		}
+		url, err := creds.URL()
+		if err != nil {
+			return runtimeError(i18n.Errorf(i18n.MsgTargetError, "db connect", targets[0].Name, err))
+		}
+		// URL already validated the engine, so Client cannot fail here.
+		client, _ := creds.Client()
+		if parsed.Bool("print") {
+			if _, err := fmt.Fprintf(ctx.stdout, "%s\n%s\n", url, client); err != nil {
+				return outputError(err)
+			}
+			return nil
+		}
+		if err := client.Run(os.Stdin, ctx.stdout, ctx.stderr); err != nil {
+			return runtimeError(i18n.Errorf(i18n.MsgTargetError, "db connect", targets[0].Name, err))
+		}
+		return nil
+	})
+}
@@ -0,0 +1,89 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bsmartlabs/dev-vault/internal/config"
+	secret "github.com/scaleway/scaleway-sdk-go/api/secret/v1beta1"
+)
+
+func TestRunDBConnect(t *testing.T) {
+	// An empty PATH keeps a real psql from starting.
+	binDir := t.TempDir()
+	t.Setenv("PATH", binDir)
+	dir := t.TempDir()
+	cfgPath := writeConfig(t, dir, `{"organization_id":"org","project_id":"proj","region":"fr-par","mapping":{
+		"app-db-dev":{"file":"db.json"},
+		"bad-db-dev":{"file":"bad.json"},
+		"odd-db-dev":{"file":"odd.json"},
+		"plain-dev":{"file":"plain"},
+		"gone-db-dev":{"file":"gone.json"},
+		"push-db-dev":{"file":"push.json","mode":"push"}
+	}}`)
+	api := newFakeSecretAPI()
+	app := api.AddSecret("proj", "app-db-dev", "/", secret.SecretTypeDatabaseCredentials)
+	api.AddEnabledVersion(app.ID, []byte(`{"engine":"postgres","username":"app","password":"s3cret","host":"db.local","port":"5432","dbname":"app"}`))
+	bad := api.AddSecret("proj", "bad-db-dev", "/", secret.SecretTypeDatabaseCredentials)
+	api.AddEnabledVersion(bad.ID, []byte(`{"password":"s3cret"}`))
+	odd := api.AddSecret("proj", "odd-db-dev", "/", secret.SecretTypeDatabaseCredentials)
+	api.AddEnabledVersion(odd.ID, []byte(`{"engine":"oracle","username":"app","host":"db"}`))
+	plain := api.AddSecret("proj", "plain-dev", "/", secret.SecretTypeOpaque)
+	api.AddEnabledVersion(plain.ID, []byte(`x`))
+	deps := baseDeps(func(config.Config, string) (SecretAPI, error) { return api, nil })
+	run := func(args ...string) (int, string, string) {
+		var out, errBuf bytes.Buffer
+		code := Run(append([]string{"dev-vault", "--config", cfgPath, "db"}, args...), &out, &errBuf, deps)
+		return code, out.String(), errBuf.String()
+	}
+
+	code, out, errOut := run("connect", "app-db-dev", "--print")
+	want := "postgresql://app@db.local:5432/app\nPGPASSWORD=*** psql --host db.local --username app --port 5432 --dbname app\n"
+	if code != 0 || out != want {
+		t.Fatalf("unexpected --print: %d %q %q", code, out, errOut)
+	}
+	if strings.Contains(out+errOut, "s3cret") {
+		t.Fatal("password must never be printed")
+	}
+
+	for _, tc := range []struct {
+		args    []string
+		code    int
+		wantErr string
+	}{
+		{[]string{"connect", "app-db-dev"}, 1, "db connect app-db-dev: psql is not on PATH"},
+		{[]string{"connect"}, 2, "expected: db connect <secret-dev>"},
+		{[]string{"open", "app-db-dev"}, 2, "expected: db connect <secret-dev>"},
+		{[]string{"connect", "app-db"}, 2, "refusing non-dev secret name"},
+		{[]string{"connect", "push-db-dev"}, 2, "not allowed in pull mode"},
+		{[]string{"connect", "plain-dev"}, 2, "secret type is opaque, expected database_credentials"},
+		{[]string{"connect", "gone-db-dev"}, 1, "resolve gone-db-dev"},
+		{[]string{"connect", "bad-db-dev"}, 1, "db connect bad-db-dev: database credentials: missing engine, host, username"},
+		{[]string{"connect", "odd-db-dev"}, 1, `unsupported database engine "oracle"`},
+	} {
+		if code, _, errOut := run(tc.args...); code != tc.code || !strings.Contains(errOut, tc.wantErr) || strings.Contains(errOut, "s3cret") {
+			t.Fatalf("%v: expected %d %q, got %d %q", tc.args, tc.code, tc.wantErr, code, errOut)
+		}
+	}
+
+	psql := "#!/bin/sh\n[ \"$PGPASSWORD\" = s3cret ] && [ \"$*\" = '--host db.local --username app --port 5432 --dbname app' ]\n"
+	if err := os.WriteFile(filepath.Join(binDir, "psql"), []byte(psql), 0o700); err != nil {
+		t.Fatalf("write fake psql: %v", err)
+	}
+	if code, out, errOut := run("connect", "app-db-dev"); code != 0 || out != "" {
+		t.Fatalf("expected fake psql to succeed, got %d %q %q", code, out, errOut)
+	}
+
+	var errBuf bytes.Buffer
+	if code := runDB(commandContext{stdout: &failingWriter{}, stderr: &errBuf, configPath: cfgPath, deps: deps}, []string{"connect", "app-db-dev", "--print"}); code != 1 {
+		t.Fatalf("expected output error, got %d", code)
+	}
+	api.accessErr = errors.New("access boom")
+	if code, _, errOut := run("connect", "app-db-dev"); code != 1 || !strings.Contains(errOut, "access boom") {
+		t.Fatalf("expected access error, got %d %q", code, errOut)
+	}
+}
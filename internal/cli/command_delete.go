@@ -0,0 +1,128 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/bsmartlabs/dev-vault/internal/config"
+	"github.com/bsmartlabs/dev-vault/internal/i18n"
+	"github.com/bsmartlabs/dev-vault/internal/secretsync"
+)
+
+var deleteCommandDef = commandDef{
+	Name:    "delete",
+	Summary: "Delete a mapped secret with all its versions",
+	Flags: []commandFlagDef{
+		{Name: "yes", Kind: commandFlagBool, Help: "Confirm the deletion (required; the secret name is still asked on stdin)"},
+	},
+	Doc: commandDoc{
+		Synopsis: "dev-vault [--config <path>] [--profile <name>] delete <secret-dev> --yes",
+		Description: []string{
+			"Deletes a mapped secret and every version of it from the provider. The local file and",
+			"the mapping entry are left alone.",
+			"Needs --yes and the secret name typed back on stdin; anything else deletes nothing.",
+		},
+		Notes: []string{
+			"Only mappings with mode push|both can be deleted.",
+			"Scaleway and Vault delete at once; aws schedules the deletion after a 30-day recovery window.",
+		},
+		Examples: []string{
+			"dev-vault delete legacy-api-dev --yes",
+		},
+	},
+	RunParsed: runDeleteParsed,
+}
+
+var deleteVersionCommandDef = commandDef{
+	Name:    "delete-version",
+	Summary: "Delete one version of a mapped secret",
+	Flags: []commandFlagDef{
+		{Name: "revision", Kind: commandFlagString, ValueName: "<n>", Help: "Revision to delete (required)"},
+		{Name: "yes", Kind: commandFlagBool, Help: "Confirm the deletion (required; the secret name is still asked on stdin)"},
+	},
+	Doc: commandDoc{
+		Synopsis: "dev-vault [--config <path>] [--profile <name>] delete-version <secret-dev> --revision <n> --yes",
+		Description: []string{
+			"Deletes one version of a mapped secret; the other versions are kept. See the versions",
+			"command for revisions. Disabled revisions can be deleted too.",
+			"Needs --yes and the secret name typed back on stdin; anything else deletes nothing.",
+		},
+		Notes: []string{
+			"Only mappings with mode push|both can be changed.",
+			"Vault destroys the version's data; aws cannot delete a single version.",
+		},
+		Examples: []string{
+			"dev-vault versions bweb-env-bsmart-dev",
+			"dev-vault delete-version bweb-env-bsmart-dev --revision 4 --yes",
+		},
+	},
+	RunParsed: runDeleteVersionParsed,
+}
+
+func runDelete(ctx commandContext, argv []string) int {
+	return runCommand(ctx, argv, deleteCommandDef)
+}
+
+func runDeleteVersion(ctx commandContext, argv []string) int {
+	return runCommand(ctx, argv, deleteVersionCommandDef)
+}
+
+func runDeleteParsed(ctx commandContext, parsed *parsedCommand) int {
+	return newCommandRuntime(ctx, parsed).execute(func(loaded *config.Loaded, service secretsync.Service) error {
+		target, err := confirmDelete(ctx, parsed, loaded, "delete", i18n.MsgConfirmDeleteAll)
+		if err != nil {
+			return err
+		}
+		if err := service.Delete(target); err != nil {
+			return runtimeError(err)
+		}
+		if _, err := fmt.Fprintf(ctx.stdout, "deleted %s\n", target.Name); err != nil {
+			return outputError(err)
+		}
+		return nil
+	})
+}
+
+func runDeleteVersionParsed(ctx commandContext, parsed *parsedCommand) int {
+	return newCommandRuntime(ctx, parsed).execute(func(loaded *config.Loaded, service secretsync.Service) error {
+		value := parsed.String("revision")
+		revision, err := strconv.ParseUint(value, 10, 32)
+		if err != nil || revision == 0 {
+			return usageError(i18n.Errorf(i18n.MsgNeedsRevisionNumber, "delete-version", "revision", value))
+		}
+		target, err := confirmDelete(ctx, parsed, loaded, "delete-version", i18n.MsgConfirmDeleteVersion, revision)
+		if err != nil {
+			return err
+		}
+		if err := service.DeleteVersion(target, uint32(revision)); err != nil {
+			return runtimeError(err)
+		}
+		if _, err := fmt.Fprintf(ctx.stdout, "deleted %s rev=%d\n", target.Name, revision); err != nil {
+			return outputError(err)
+		}
+		return nil
+	})
+}
+
+// confirmDelete selects the one mapped secret a delete command names and
+// asks for its name on stdin with question, given the name then args. --yes
+// alone is not enough: deleting cannot be undone, so the name must be typed
+// back.
+func confirmDelete(ctx commandContext, parsed *parsedCommand, loaded *config.Loaded, command string, question i18n.MessageID, args ...any) (secretsync.MappingTarget, error) {
+	names := parsed.fs.Args()
+	if len(names) != 1 {
+		return secretsync.MappingTarget{}, usageError(i18n.Errorf(i18n.MsgTakesOneSecretName, command))
+	}
+	targets, err := selectMappingTargetsForMode(loaded.Cfg.Mapping, false, names, commandModePush)
+	if err != nil {
+		return secretsync.MappingTarget{}, err
+	}
+	name := targets[0].Name
+	if !parsed.Bool("yes") {
+		return secretsync.MappingTarget{}, usageError(i18n.Errorf(i18n.MsgNeedsYes, command))
+	}
+	if prompt(ctx, parsed.msg.Sprintf(question, append([]any{name}, args...)...)) != name {
+		return secretsync.MappingTarget{}, usageError(i18n.Errorf(i18n.MsgNotConfirmedName, command, name))
+	}
+	return targets[0], nil
+}
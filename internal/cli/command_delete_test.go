@@ -0,0 +1,108 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/bsmartlabs/dev-vault/internal/config"
+	"github.com/bsmartlabs/dev-vault/internal/secretprovider"
+	secret "github.com/scaleway/scaleway-sdk-go/api/secret/v1beta1"
+)
+
+func TestRunDelete(t *testing.T) {
+	dir := t.TempDir()
+	cfgPath := writeConfig(t, dir, `{"organization_id":"org","project_id":"proj","region":"fr-par","mapping":{
+		"db-dev":{"file":"db.json"},
+		"pull-dev":{"file":"pull.json","mode":"pull"}
+	}}`)
+	api := newFakeSecretAPI()
+	sec := api.AddSecret("proj", "db-dev", "/", secret.SecretTypeOpaque)
+	api.AddEnabledVersion(sec.ID, []byte("payload-1"))
+	api.AddEnabledVersion(sec.ID, []byte("payload-2"))
+	deps := baseDeps(func(config.Config, string) (SecretAPI, error) { return api, nil })
+	run := func(stdin string, args ...string) (int, string, string) {
+		var out, errBuf bytes.Buffer
+		deps := deps
+		deps.Stdin = strings.NewReader(stdin)
+		code := Run(append([]string{"dev-vault", "--config", cfgPath}, args...), &out, &errBuf, deps)
+		return code, out.String(), errBuf.String()
+	}
+
+	// Neither --yes alone nor a wrong name deletes anything.
+	refusals := []struct {
+		stdin string
+		args  []string
+		want  string
+	}{
+		{"db-dev\n", []string{"delete", "db-dev"}, "delete needs --yes"},
+		{"", []string{"delete", "db-dev", "--yes"}, "delete db-dev: not confirmed (type the secret name exactly)"},
+		{"y\n", []string{"delete", "db-dev", "--yes"}, "delete db-dev: not confirmed"},
+		{"DB-DEV\n", []string{"delete-version", "db-dev", "--revision", "1", "--yes"}, "delete-version db-dev: not confirmed"},
+		{"db-dev\n", []string{"delete-version", "db-dev", "--revision", "1"}, "delete-version needs --yes"},
+		{"db-dev\n", []string{"delete-version", "db-dev", "--revision", "0", "--yes"}, `delete-version needs --revision with a positive revision number, got "0"`},
+		{"db-dev\n", []string{"delete-version", "db-dev", "--yes"}, `got ""`},
+		{"db-dev\n", []string{"delete", "db-dev", "pull-dev", "--yes"}, "delete takes exactly one secret name"},
+		{"pull-dev\n", []string{"delete", "pull-dev", "--yes"}, "pull-dev"},
+		{"prod\n", []string{"delete", "prod", "--yes"}, "refusing non-dev secret name: prod"},
+	}
+	for _, tc := range refusals {
+		if code, out, errOut := run(tc.stdin, tc.args...); code != 2 || out != "" || !strings.Contains(errOut, tc.want) {
+			t.Fatalf("%v: expected %q, got %d %q %q", tc.args, tc.want, code, out, errOut)
+		}
+	}
+	if code, _, errOut := run("db-dev\n", "--lang", "fr", "delete", "db-dev"); code != 2 || !strings.HasSuffix(errOut, "delete exige --yes\n") {
+		t.Fatalf("expected french --yes error, got %d %q", code, errOut)
+	}
+	if code, _, errOut := run("nope\n", "--lang", "fr", "delete", "db-dev", "--yes"); code != 2 || !strings.HasPrefix(errOut, "Supprimer db-dev (toutes les versions) ? Cette action est irréversible. Tapez le nom du secret pour confirmer : ") {
+		t.Fatalf("expected french confirmation prompt, got %d %q", code, errOut)
+	}
+	if len(api.secrets) != 1 || len(api.versions[sec.ID]) != 2 {
+		t.Fatalf("refused deletes changed the secret: %#v", api.versions)
+	}
+
+	code, out, errOut := run(" db-dev \n", "delete-version", "db-dev", "--revision", "1", "--yes")
+	if code != 0 || out != "deleted db-dev rev=1\n" || errOut != "Delete db-dev (revision 1)? This cannot be undone. Type the secret name to confirm: " {
+		t.Fatalf("unexpected delete-version: %d %q %q", code, out, errOut)
+	}
+	if len(api.versions[sec.ID]) != 1 || api.versions[sec.ID][0].revision != 2 {
+		t.Fatalf("unexpected versions: %#v", api.versions[sec.ID])
+	}
+	if code, _, errOut := run("db-dev\n", "delete-version", "db-dev", "--revision", "1", "--yes"); code != 1 || !strings.Contains(errOut, "delete db-dev: revision 1 does not exist") {
+		t.Fatalf("expected missing revision, got %d %q", code, errOut)
+	}
+
+	api.deleteErr = fmt.Errorf("denied: %w", secretprovider.ErrPermissionDenied)
+	for _, args := range [][]string{{"delete", "db-dev", "--yes"}, {"delete-version", "db-dev", "--revision", "2", "--yes"}} {
+		if code, _, errOut := run("db-dev\n", args...); code != 1 || !strings.Contains(errOut, "read-only credentials: delete db-dev") || !strings.Contains(errOut, "hint: ") {
+			t.Fatalf("%v: expected read-only error, got %d %q", args, code, errOut)
+		}
+	}
+	api.deleteErr = nil
+
+	if code, out, _ := run("db-dev\n", "delete", "db-dev", "--yes"); code != 0 || out != "deleted db-dev\n" {
+		t.Fatalf("unexpected delete: %d %q", code, out)
+	}
+	if len(api.secrets) != 0 {
+		t.Fatalf("secret not deleted: %#v", api.secrets)
+	}
+	if strings.Contains(out+errOut, "payload-") {
+		t.Fatal("delete must never print payloads")
+	}
+
+	ctx := commandContext{stdout: &failingWriter{}, stderr: &bytes.Buffer{}, configPath: cfgPath, deps: deps}
+	for _, args := range [][]string{{"db-dev", "--yes"}, {"db-dev", "--revision", "2", "--yes"}} {
+		sec := api.AddSecret("proj", "db-dev", "/", secret.SecretTypeOpaque)
+		api.AddEnabledVersion(sec.ID, []byte("payload-1"))
+		api.AddEnabledVersion(sec.ID, []byte("payload-2"))
+		ctx.deps.Stdin = strings.NewReader("db-dev\n")
+		run := runDelete
+		if len(args) > 2 {
+			run = runDeleteVersion
+		}
+		if code := run(ctx, args); code != 1 {
+			t.Fatalf("%v: expected output error, got %d", args, code)
+		}
+	}
+}
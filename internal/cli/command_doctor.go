@@ -0,0 +1,181 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+
+	"github.com/bsmartlabs/dev-vault/internal/config"
+	"github.com/bsmartlabs/dev-vault/internal/i18n"
+	"github.com/bsmartlabs/dev-vault/internal/policy"
+	"github.com/bsmartlabs/dev-vault/internal/secretsync"
+)
+
+var doctorCommandDef = commandDef{
+	Name:    "doctor",
+	Summary: "Check mappings against their remote secrets before pull or push fails",
+	Flags: []commandFlagDef{
+		{Name: "json", Kind: commandFlagBool, Help: "Output JSON (same as --output json)"},
+		{Name: "output", Kind: commandFlagString, ValueName: "<table|json|vscode>", Help: "Output format (default table)"},
+		{Name: "policy-file", Kind: commandFlagString, ValueName: "<path>", Help: policyFileFlagHelp},
+	},
+	Doc: commandDoc{
+		Synopsis: "dev-vault [--config <path>] [--profile <name>] doctor [--json | --output <table|json|vscode>] [--policy-file <path>]",
+		Description: []string{
+			"Looks up every enabled mapping entry (metadata only, no payloads) and reports",
+			"pairings of secret type and mapping format that pull or push would trip over,",
+			"with the pairing that fits:",
+			"  - certificate or ssh_key mapped as dotenv: PEM text is not a JSON object; use raw.",
+			"  - key_value mapped as raw: the file gets the JSON payload; use dotenv.",
+			"  - mapping.type differing from the secret's type: lookups by type miss the secret.",
+			"  - no secret at the mapped name and path.",
+			"  - a local dotenv file that does not parse, with the line at fault.",
+			"  - a remote secret over the policy's max_secret_age or missing its required_tags.",
+		},
+		Notes: []string{
+			"Exits with code 1 when anything is reported, so it can gate CI.",
+			"--output vscode prints one 'file:line:column: error: message' line per problem, for a",
+			"VS Code task problem matcher. Problems with the remote secret point at the mapping",
+			"entry in .scw.json.",
+		},
+		Examples: []string{
+			"dev-vault doctor",
+			"dev-vault doctor --json",
+			"dev-vault doctor --output vscode",
+		},
+	},
+	RunParsed: runDoctorParsed,
+}
+
+func runDoctor(ctx commandContext, argv []string) int {
+	return runCommand(ctx, argv, doctorCommandDef)
+}
+
+func runDoctorParsed(ctx commandContext, parsed *parsedCommand) int {
+	rt := newCommandRuntime(ctx, parsed)
+	return rt.execute(func(loaded *config.Loaded, service secretsync.Service) error {
+		output, err := doctorOutput(parsed)
+		if err != nil {
+			return err
+		}
+		findings, checked, err := service.FormatFindings()
+		if err != nil {
+			return runtimeError(err)
+		}
+		violations, err := rt.remotePolicyViolations(loaded, service)
+		if err != nil {
+			return err
+		}
+		findings = append(findings, policyFindings(loaded.Cfg, violations)...)
+
+		if output == doctorOutputVSCode {
+			if err := writeProblemMatcherLines(ctx.stdout, loaded.Path, findings); err != nil {
+				return outputError(err)
+			}
+		} else if output == doctorOutputJSON {
+			enc := json.NewEncoder(ctx.stdout)
+			enc.SetIndent("", "  ")
+			if err := enc.Encode(findings); err != nil {
+				return outputError(err)
+			}
+		} else if len(findings) == 0 {
+			if _, err := fmt.Fprintf(ctx.stdout, "%d mappings checked, no problems\n", checked); err != nil {
+				return outputError(err)
+			}
+		} else {
+			table := newTable(ctx.stdout, parsed.plain, "NAME", "TYPE", "FORMAT", "PROBLEM", "SUGGESTION")
+			for _, f := range findings {
+				secretType := f.Type
+				if secretType == "" {
+					secretType = "-"
+				}
+				table.row(f.Name, secretType, f.Format, f.Problem, f.Suggestion)
+			}
+			if err := table.flush(); err != nil {
+				return outputError(err)
+			}
+		}
+		if len(findings) > 0 {
+			return runtimeError(i18n.Errorf(i18n.MsgMappingProblems, len(findings)))
+		}
+		return nil
+	})
+}
+
+// policySuggestions are the doctor suggestions for the rules
+// remotePolicyViolations checks.
+var policySuggestions = map[string]string{
+	policy.RuleMaxSecretAge: "rotate it with push or generate",
+	policy.RuleRequiredTags: "add the missing tags to the secret",
+}
+
+// policyFindings reports policy violations as doctor findings, whatever the
+// policy level: doctor gates CI on them.
+func policyFindings(cfg config.Config, violations []policy.Violation) []secretsync.FormatFinding {
+	findings := make([]secretsync.FormatFinding, 0, len(violations))
+	for _, violation := range violations {
+		findings = append(findings, secretsync.FormatFinding{
+			Name:       violation.Secret,
+			Format:     string(cfg.Mapping[violation.Secret].Format),
+			Problem:    fmt.Sprintf("policy %s: %s", violation.Rule, violation.Detail),
+			Suggestion: policySuggestions[violation.Rule],
+		})
+	}
+	return findings
+}
+
+const (
+	doctorOutputTable  = "table"
+	doctorOutputJSON   = "json"
+	doctorOutputVSCode = "vscode"
+)
+
+// doctorOutput resolves --output and its --json shorthand.
+func doctorOutput(parsed *parsedCommand) (string, error) {
+	output := parsed.String("output")
+	switch output {
+	case "":
+		if parsed.Bool("json") {
+			return doctorOutputJSON, nil
+		}
+		return doctorOutputTable, nil
+	case doctorOutputTable, doctorOutputJSON, doctorOutputVSCode:
+		if parsed.Bool("json") && output != doctorOutputJSON {
+			return "", usageError(i18n.Errorf(i18n.MsgJSONConflictsWithOutput, output))
+		}
+		return output, nil
+	default:
+		return "", usageError(i18n.Errorf(i18n.MsgInvalidDoctorOutput, output))
+	}
+}
+
+// writeProblemMatcherLines prints findings in the file:line:column form
+// editor problem matchers parse. Findings about the remote secret point at
+// the mapping entry in the manifest, or its first line when the entry is not
+// found there.
+func writeProblemMatcherLines(w io.Writer, manifestPath string, findings []secretsync.FormatFinding) error {
+	manifest, _ := os.ReadFile(manifestPath) // a missing manifest only costs the line number
+	for _, f := range findings {
+		file, line := f.File, f.Line
+		if file == "" {
+			file, line = manifestPath, manifestLine(manifest, f.Name)
+		}
+		if _, err := fmt.Fprintf(w, "%s:%d:1: error: %s: %s; %s\n", file, max(line, 1), f.Name, f.Problem, f.Suggestion); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// manifestLine returns the line of the mapping key name in manifest, or 0.
+func manifestLine(manifest []byte, name string) int {
+	loc := regexp.MustCompile(regexp.QuoteMeta(strconv.Quote(name)) + `\s*:`).FindIndex(manifest)
+	if loc == nil {
+		return 0
+	}
+	return 1 + bytes.Count(manifest[:loc[0]], []byte("\n"))
+}
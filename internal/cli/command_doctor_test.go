@@ -0,0 +1,162 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bsmartlabs/dev-vault/internal/config"
+	secret "github.com/scaleway/scaleway-sdk-go/api/secret/v1beta1"
+)
+
+func TestRunDoctor(t *testing.T) {
+	dir := t.TempDir()
+	cfgPath := writeConfig(t, dir, `{"organization_id":"org","project_id":"proj","region":"fr-par","mapping":{
+		"tls-dev":{"file":"tls.env","format":"dotenv"},
+		"kv-dev":{"file":"kv.env","format":"dotenv"}
+	}}`)
+	cleanPath := writeConfig(t, t.TempDir(), `{"organization_id":"org","project_id":"proj","region":"fr-par","mapping":{
+		"kv-dev":{"file":"kv.env","format":"dotenv"}
+	}}`)
+	api := newFakeSecretAPI()
+	api.AddSecret("proj", "tls-dev", "/", secret.SecretTypeCertificate)
+	api.AddSecret("proj", "kv-dev", "/", secret.SecretTypeKeyValue)
+	deps := baseDeps(func(config.Config, string) (SecretAPI, error) { return api, nil })
+	run := func(cfg string, args ...string) (int, string, string) {
+		var out, errBuf bytes.Buffer
+		code := Run(append([]string{"dev-vault", "--plain", "--config", cfg, "doctor"}, args...), &out, &errBuf, deps)
+		return code, out.String(), errBuf.String()
+	}
+
+	if code, out, errOut := run(cleanPath); code != 0 || out != "1 mappings checked, no problems\n" {
+		t.Fatalf("unexpected clean run: %d %q %q", code, out, errOut)
+	}
+	code, out, errOut := run(cfgPath)
+	want := "NAME\tTYPE\tFORMAT\tPROBLEM\tSUGGESTION\n" +
+		"tls-dev\tcertificate\tdotenv\tcertificate payloads are PEM text, not a JSON object, so pull fails as dotenv\tuse format raw\n"
+	if code != 1 || out != want || !strings.Contains(errOut, "1 mapping problem(s)") {
+		t.Fatalf("unexpected doctor output: %d\n%s\n%s", code, out, errOut)
+	}
+
+	missingPath := writeConfig(t, t.TempDir(), `{"organization_id":"org","project_id":"proj","region":"fr-par","mapping":{"gone-dev":{"file":"gone"}}}`)
+	if code, out, _ := run(missingPath); code != 1 || !strings.Contains(out, "gone-dev\t-\traw\tsecret not found at path /") {
+		t.Fatalf("unexpected missing-secret output: %d\n%s", code, out)
+	}
+
+	code, out, _ = run(cfgPath, "--json")
+	var findings []map[string]string
+	if code != 1 || json.Unmarshal([]byte(out), &findings) != nil || len(findings) != 1 || findings[0]["suggestion"] != "use format raw" {
+		t.Fatalf("unexpected json output: %d %s", code, out)
+	}
+
+	// Problems with the remote secret point at the mapping entry, those of a
+	// local file at the line at fault.
+	vscodeDir := t.TempDir()
+	vscodePath := writeConfig(t, vscodeDir, `{"organization_id":"org","project_id":"proj","region":"fr-par",
+	"mapping":{
+		"kv-dev":{"file":"kv.env","format":"dotenv"},
+		"tls-dev":{"file":"tls.env","format":"dotenv"}
+	}}`)
+	if err := os.WriteFile(filepath.Join(vscodeDir, "kv.env"), []byte("A=1\nNOPE\n"), 0o600); err != nil {
+		t.Fatalf("write kv.env: %v", err)
+	}
+	code, out, _ = run(vscodePath, "--output", "vscode")
+	want = filepath.Join(vscodeDir, "kv.env") + ":2:1: error: kv-dev: kv.env does not parse as dotenv: line 2: missing '='; fix the file, or replace it with pull --overwrite\n" +
+		vscodePath + ":4:1: error: tls-dev: certificate payloads are PEM text, not a JSON object, so pull fails as dotenv; use format raw\n"
+	if code != 1 || out != want {
+		t.Fatalf("unexpected vscode output: %d\n%s", code, out)
+	}
+	if manifestLine([]byte(`{"mapping":{}}`), "kv-dev") != 0 {
+		t.Fatal("expected no line for a name missing from the manifest")
+	}
+	for want, args := range map[string][]string{
+		`invalid --output "xml" (expected table, json or vscode)`: {"--output", "xml"},
+		"--json conflicts with --output vscode":                   {"--json", "--output", "vscode"},
+	} {
+		if code, _, errOut := run(cfgPath, args...); code != 2 || !strings.Contains(errOut, want) {
+			t.Fatalf("%v: expected %q, got %d %q", args, want, code, errOut)
+		}
+	}
+	if code, out, _ := run(cfgPath, "--output", "json"); code != 1 || !strings.Contains(out, `"suggestion": "use format raw"`) {
+		t.Fatalf("unexpected --output json: %d %s", code, out)
+	}
+
+	for _, args := range [][]string{nil, {"--json"}, {"--output", "vscode"}} {
+		var errBuf bytes.Buffer
+		if code := runDoctor(commandContext{stdout: &failingWriter{}, stderr: &errBuf, configPath: cfgPath, deps: deps}, args); code != 1 {
+			t.Fatalf("%v: expected output error, got %d", args, code)
+		}
+	}
+	var errBuf bytes.Buffer
+	if code := runDoctor(commandContext{stdout: &failingWriter{}, stderr: &errBuf, configPath: cleanPath, deps: deps}, nil); code != 1 {
+		t.Fatalf("expected output error for the clean summary, got %d", code)
+	}
+	api.listErr = errors.New("list boom")
+	if code, _, errOut := run(cfgPath); code != 1 || !strings.Contains(errOut, "list boom") {
+		t.Fatalf("expected lookup error, got %d %q", code, errOut)
+	}
+}
+
+func TestRunDoctor_Policy(t *testing.T) {
+	dir := t.TempDir()
+	cfgPath := writeConfig(t, dir, `{"organization_id":"org","project_id":"proj","region":"fr-par",
+	"policy":{"max_secret_age":"7d"},
+	"mapping":{
+		"kv-dev":{"file":"kv.env","format":"dotenv"},
+		"tagged-dev":{"file":"tagged.env","format":"dotenv"}
+	}}`)
+	now := time.Date(2026, 3, 11, 0, 0, 0, 0, time.UTC)
+	api := newFakeSecretAPI()
+	api.AddSecret("proj", "kv-dev", "/", secret.SecretTypeKeyValue).UpdatedAt = now.Add(-10 * 24 * time.Hour)
+	tagged := api.AddSecret("proj", "tagged-dev", "/", secret.SecretTypeKeyValue)
+	tagged.UpdatedAt, tagged.Tags = now, []string{"owner:web"}
+	deps := baseDeps(func(config.Config, string) (SecretAPI, error) { return api, nil })
+	deps.Now = func() time.Time { return now }
+	run := func(deps Dependencies, args ...string) (int, string, string) {
+		var out, errBuf bytes.Buffer
+		code := Run(append([]string{"dev-vault", "--plain", "--config", cfgPath, "doctor"}, args...), &out, &errBuf, deps)
+		return code, out.String(), errBuf.String()
+	}
+
+	code, out, errOut := run(deps)
+	want := "NAME\tTYPE\tFORMAT\tPROBLEM\tSUGGESTION\n" +
+		"kv-dev\t-\tdotenv\tpolicy max_secret_age: last updated 10d ago, limit is 7d\trotate it with push or generate\n"
+	if code != 1 || out != want || !strings.Contains(errOut, "1 mapping problem(s)") {
+		t.Fatalf("unexpected doctor output: %d\n%s\n%s", code, out, errOut)
+	}
+
+	// --policy-file replaces the manifest's policy.
+	policyPath := filepath.Join(t.TempDir(), "policy.json")
+	if err := os.WriteFile(policyPath, []byte(`{"required_tags":["owner"]}`), 0o600); err != nil {
+		t.Fatalf("write policy: %v", err)
+	}
+	code, out, _ = run(deps, "--policy-file", policyPath, "--output", "vscode")
+	want = cfgPath + ":4:1: error: kv-dev: policy required_tags: missing tags: owner; add the missing tags to the secret\n"
+	if code != 1 || out != want {
+		t.Fatalf("unexpected policy-file output: %d\n%s", code, out)
+	}
+	if code, _, errOut := run(deps, "--policy-file", filepath.Join(t.TempDir(), "missing.json")); code != 1 || !strings.Contains(errOut, "read policy file") {
+		t.Fatalf("expected policy file error, got %d %q", code, errOut)
+	}
+
+	// The lookups and the index listing succeed, the inventory listing
+	// fails.
+	lists := 0
+	stub := &stubSecretAPI{listFn: func(req ListSecretsInput) ([]SecretRecord, error) {
+		if req.Name == "" {
+			if lists++; lists > 1 {
+				return nil, errors.New("inventory boom")
+			}
+		}
+		return nil, nil
+	}}
+	stubDeps := baseDeps(func(config.Config, string) (SecretAPI, error) { return stub, nil })
+	if code, _, errOut := run(stubDeps); code != 1 || !strings.Contains(errOut, "list secrets: inventory boom") {
+		t.Fatalf("expected inventory error, got %d %q", code, errOut)
+	}
+}
@@ -8,11 +8,15 @@ const (
 	commandErrorUsage commandErrorKind = iota + 1
 	commandErrorRuntime
 	commandErrorOutput
+	commandErrorInterrupted
+	commandErrorExit
 )
 
 type commandError struct {
 	kind commandErrorKind
 	err  error
+	// code is the exit code of a commandErrorExit.
+	code int
 }
 
 func (e *commandError) Error() string {
@@ -46,6 +50,17 @@ func outputError(err error) error {
 	return wrapCommandError(commandErrorOutput, err)
 }
 
+// interruptedError marks a batch stopped by SIGINT/SIGTERM; it exits with 130
+// like a shell-interrupted process.
+func interruptedError(err error) error {
+	return wrapCommandError(commandErrorInterrupted, err)
+}
+
+// exitError passes on the exit code of a program dev-vault ran.
+func exitError(err error, code int) error {
+	return &commandError{kind: commandErrorExit, err: err, code: code}
+}
+
 func exitCodeForError(err error) int {
 	if err == nil {
 		return 0
@@ -55,6 +70,10 @@ func exitCodeForError(err error) int {
 		switch commandErr.kind {
 		case commandErrorUsage:
 			return 2
+		case commandErrorInterrupted:
+			return 130
+		case commandErrorExit:
+			return commandErr.code
 		default:
 			return 1
 		}
@@ -0,0 +1,90 @@
+package cli
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"sort"
+
+	"github.com/bsmartlabs/dev-vault/internal/config"
+	"github.com/bsmartlabs/dev-vault/internal/i18n"
+	"github.com/bsmartlabs/dev-vault/internal/secretsync"
+)
+
+var execCommandDef = commandDef{
+	Name:    "exec",
+	Summary: "Run a program with a key_value secret in its environment",
+	Doc: commandDoc{
+		Synopsis: "dev-vault [--config <path>] [--profile <name>] exec <secret-dev> [--] <command> [args...]",
+		Description: []string{
+			"Reads the latest enabled version of a mapped key_value secret and runs <command> with",
+			"its keys added to the environment. Nothing is written to disk.",
+			"For a dotenv mapping the variables are those pull would write, with compose, keys,",
+			"substitute, and key_prefix/key_suffix applied.",
+		},
+		Notes: []string{
+			"Everything after <secret-dev> goes to <command> as written; -- before it is optional.",
+			"Secret keys replace variables of the same name already in the environment.",
+			"dev-vault exits with the command's exit code, and ignores SIGINT/SIGTERM while it runs",
+			"so the command decides how to stop.",
+			"Values never appear in dev-vault's output.",
+		},
+		Examples: []string{
+			"dev-vault exec app-env-dev -- npm start",
+			"dev-vault exec app-env-dev go test ./...",
+		},
+	},
+	RunParsed:   runExecParsed,
+	PassThrough: true,
+}
+
+func runExec(ctx commandContext, argv []string) int {
+	return runCommand(ctx, argv, execCommandDef)
+}
+
+func runExecParsed(ctx commandContext, parsed *parsedCommand) int {
+	return newCommandRuntime(ctx, parsed).execute(func(loaded *config.Loaded, service secretsync.Service) error {
+		args := parsed.fs.Args()
+		if len(args) < 2 {
+			return usageError(i18n.Errorf(i18n.MsgExpectedUsage, "exec <secret-dev> [--] <command> [args...]"))
+		}
+		targets, err := selectMappingTargetsForMode(loaded.Cfg.Mapping, false, args[:1], commandModePull)
+		if err != nil {
+			return err
+		}
+		vars, err := service.Env(targets[0])
+		if err != nil {
+			return runtimeError(err)
+		}
+		path, err := exec.LookPath(args[1])
+		if err != nil {
+			return runtimeError(i18n.Errorf(i18n.MsgTargetError, "exec", targets[0].Name, err))
+		}
+
+		env := os.Environ()
+		names := make([]string, 0, len(vars))
+		for name := range vars {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			env = append(env, name+"="+vars[name])
+		}
+		cmd := exec.Command(path, args[2:]...)
+		cmd.Env = env // later entries win, so secret keys replace inherited ones
+		cmd.Stdin, cmd.Stdout, cmd.Stderr = ctx.deps.Stdin, ctx.stdout, ctx.stderr
+
+		// The terminal signals the whole process group; the command handles
+		// them, and dev-vault waits for it to exit.
+		_, stop := ctx.deps.SignalContext()
+		defer stop()
+		if err := cmd.Run(); err != nil {
+			var exitErr *exec.ExitError
+			if errors.As(err, &exitErr) && exitErr.ExitCode() > 0 {
+				return exitError(i18n.Errorf(i18n.MsgExecExited, targets[0].Name, args[1], exitErr.ExitCode()), exitErr.ExitCode())
+			}
+			return runtimeError(i18n.Errorf(i18n.MsgExecRun, targets[0].Name, args[1], err))
+		}
+		return nil
+	})
+}
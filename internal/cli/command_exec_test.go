@@ -0,0 +1,84 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bsmartlabs/dev-vault/internal/config"
+	secret "github.com/scaleway/scaleway-sdk-go/api/secret/v1beta1"
+)
+
+func TestRunExec(t *testing.T) {
+	binDir := t.TempDir()
+	t.Setenv("PATH", binDir)
+	t.Setenv("DB_HOST", "inherited")
+	t.Setenv("KEEP", "kept")
+	// The fake command checks its environment and arguments, then exits with
+	// the code it was given.
+	script := "#!/bin/sh\n[ \"$DB_HOST\" = db ] && [ \"$DB_PASSWORD\" = s3cret ] && [ \"$KEEP\" = kept ] && [ \"$1\" = -v ] || exit 9\nexit \"$2\"\n"
+	if err := os.WriteFile(filepath.Join(binDir, "app"), []byte(script), 0o700); err != nil {
+		t.Fatalf("write fake app: %v", err)
+	}
+	dir := t.TempDir()
+	cfgPath := writeConfig(t, dir, `{"organization_id":"org","project_id":"proj","region":"fr-par","mapping":{
+		"app-env-dev":{"file":".env","format":"dotenv"},
+		"blob-dev":{"file":"blob"},
+		"push-env-dev":{"file":"push.env","format":"dotenv","mode":"push"}
+	}}`)
+	api := newFakeSecretAPI()
+	app := api.AddSecret("proj", "app-env-dev", "/", secret.SecretTypeKeyValue)
+	api.AddEnabledVersion(app.ID, []byte(`{"DB_HOST":"db","DB_PASSWORD":"s3cret"}`))
+	blob := api.AddSecret("proj", "blob-dev", "/", secret.SecretTypeOpaque)
+	api.AddEnabledVersion(blob.ID, []byte("s3cret"))
+	deps := baseDeps(func(config.Config, string) (SecretAPI, error) { return api, nil })
+	run := func(args ...string) (int, string, string) {
+		var out, errBuf bytes.Buffer
+		code := Run(append([]string{"dev-vault", "--config", cfgPath, "exec"}, args...), &out, &errBuf, deps)
+		return code, out.String(), errBuf.String()
+	}
+
+	// Flags after the secret name belong to the command, with or without --.
+	for _, args := range [][]string{{"app-env-dev", "app", "-v", "0"}, {"app-env-dev", "--", "app", "-v", "0"}, {"--", "app-env-dev", "app", "-v", "0"}} {
+		if code, out, errOut := run(args...); code != 0 || out != "" || errOut != "" {
+			t.Fatalf("%v: expected success, got %d %q %q", args, code, out, errOut)
+		}
+	}
+	if code, _, errOut := run("app-env-dev", "app", "-v", "3"); code != 3 || !strings.Contains(errOut, "exec app-env-dev: app exited with code 3") {
+		t.Fatalf("expected the command's exit code, got %d %q", code, errOut)
+	}
+
+	for _, tc := range []struct {
+		args    []string
+		code    int
+		wantErr string
+	}{
+		{[]string{"app-env-dev"}, 2, "expected: exec <secret-dev> [--] <command> [args...]"},
+		{[]string{"app-env", "app"}, 2, "refusing non-dev secret name"},
+		{[]string{"push-env-dev", "app"}, 2, "not allowed in pull mode"},
+		{[]string{"blob-dev", "app"}, 1, "env blob-dev: expected a JSON object payload"},
+		{[]string{"app-env-dev", "missing-app"}, 1, "exec app-env-dev: exec: \"missing-app\": executable file not found in $PATH"},
+		{[]string{"app-env-dev", filepath.Join(dir, ".scw.json")}, 1, "permission denied"},
+	} {
+		if code, _, errOut := run(tc.args...); code != tc.code || !strings.Contains(errOut, tc.wantErr) || strings.Contains(errOut, "s3cret") {
+			t.Fatalf("%v: expected %d %q, got %d %q", tc.args, tc.code, tc.wantErr, code, errOut)
+		}
+	}
+
+	// A command that cannot start is a runtime failure, not its exit code.
+	if err := os.WriteFile(filepath.Join(binDir, "broken"), []byte("#!/nonexistent/interpreter\n"), 0o700); err != nil {
+		t.Fatalf("write broken: %v", err)
+	}
+	var errBuf bytes.Buffer
+	if code := runExec(commandContext{stdout: &bytes.Buffer{}, stderr: &errBuf, configPath: cfgPath, deps: deps}, []string{"app-env-dev", "broken"}); code != 1 || !strings.Contains(errBuf.String(), "exec app-env-dev: broken: ") {
+		t.Fatalf("expected start error, got %d %q", code, errBuf.String())
+	}
+
+	api.accessErr = errors.New("access boom")
+	if code, _, errOut := run("app-env-dev", "app"); code != 1 || !strings.Contains(errOut, "access boom") {
+		t.Fatalf("expected access error, got %d %q", code, errOut)
+	}
+}
@@ -13,13 +13,30 @@ import (
 func TestDefaultDependencies(t *testing.T) {
 	deps := DefaultDependencies("v1", "c1", "d1", func(cfg config.Config, profileOverride string) (SecretAPI, error) {
 		return nil, nil
+	}, func(profileOverride string) (AccountAPI, error) {
+		return nil, nil
 	})
 	if deps.Version != "v1" || deps.Commit != "c1" || deps.Date != "d1" {
 		t.Fatalf("unexpected deps: %#v", deps)
 	}
-	if deps.OpenSecretAPI == nil || deps.Now == nil || deps.Hostname == nil {
+	if deps.OpenSecretAPI == nil || deps.OpenAccountAPI == nil || deps.Now == nil || deps.Hostname == nil || deps.Getenv == nil || deps.UserHomeDir == nil || deps.InvocationID == nil || deps.Stdin != os.Stdin || deps.StdinIsTerminal == nil {
 		t.Fatalf("expected all funcs set: %#v", deps)
 	}
+	// The answer depends on how go test was started, so it is not checked.
+	_ = deps.StdinIsTerminal()
+	if first, second := deps.InvocationID(), deps.InvocationID(); len(first) < 16 || first == second {
+		t.Fatalf("expected distinct random invocation IDs, got %q %q", first, second)
+	}
+	// $BROWSER wins over the platform opener; "true" starts and exits.
+	t.Setenv("BROWSER", "true")
+	if err := deps.OpenURL("https://access.example.test/"); err != nil {
+		t.Fatalf("OpenURL: %v", err)
+	}
+	signalCtx, stop := deps.SignalContext()
+	if signalCtx.Err() != nil {
+		t.Fatalf("expected live signal context, got %v", signalCtx.Err())
+	}
+	stop()
 }
 
 func TestLoadAndOpenAPI_GetwdError(t *testing.T) {
@@ -27,7 +44,7 @@ func TestLoadAndOpenAPI_GetwdError(t *testing.T) {
 		return nil, nil
 	})
 	deps.Getwd = func() (string, error) { return "", errors.New("boom") }
-	_, _, err := loadAndOpenAPI("", "", deps)
+	_, _, err := loadAndOpenAPI("", "", config.LoadOptions{}, deps)
 	if err == nil {
 		t.Fatalf("expected error")
 	}
@@ -45,14 +62,14 @@ func TestLoadAndOpenAPI_Success(t *testing.T) {
 
 	api := newFakeSecretAPI()
 	deps := baseDeps(func(cfg config.Config, s string) (SecretAPI, error) { return api, nil })
-	loaded, gotAPI, err := loadAndOpenAPI(cfgPath, "", deps)
+	loaded, gotAPI, err := loadAndOpenAPI(cfgPath, "", config.LoadOptions{}, deps)
 	if err != nil || loaded == nil || gotAPI == nil {
 		t.Fatalf("expected success, got err=%v loaded=%v api=%v", err, loaded, gotAPI)
 	}
 }
 
 func TestLoadAndOpenAPI_ConfigError(t *testing.T) {
-	_, _, err := loadAndOpenAPI("/nope.json", "", baseDeps(func(cfg config.Config, s string) (SecretAPI, error) {
+	_, _, err := loadAndOpenAPI("/nope.json", "", config.LoadOptions{}, baseDeps(func(cfg config.Config, s string) (SecretAPI, error) {
 		return nil, nil
 	}))
 	if err == nil {
@@ -63,7 +80,7 @@ func TestLoadAndOpenAPI_ConfigError(t *testing.T) {
 func TestLoadAndOpenAPI_OpenError(t *testing.T) {
 	root := t.TempDir()
 	cfgPath := writeConfig(t, root, `{"organization_id":"org","project_id":"proj","region":"fr-par","mapping":{"x-dev":{"file":"x"}}}`)
-	_, _, err := loadAndOpenAPI(cfgPath, "", baseDeps(func(cfg config.Config, s string) (SecretAPI, error) {
+	_, _, err := loadAndOpenAPI(cfgPath, "", config.LoadOptions{}, baseDeps(func(cfg config.Config, s string) (SecretAPI, error) {
 		return nil, errors.New("boom")
 	}))
 	if err == nil {
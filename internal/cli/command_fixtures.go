@@ -0,0 +1,98 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/bsmartlabs/dev-vault/internal/config"
+	"github.com/bsmartlabs/dev-vault/internal/fixtures"
+	"github.com/bsmartlabs/dev-vault/internal/fsx"
+	"github.com/bsmartlabs/dev-vault/internal/i18n"
+	"github.com/bsmartlabs/dev-vault/internal/secretsync"
+)
+
+var fixturesCommandDef = commandDef{
+	Name:    "fixtures",
+	Summary: "Write the keys of dotenv secrets with fake values for application tests",
+	Flags: []commandFlagDef{
+		{Name: "out", Kind: commandFlagString, ValueName: "<path>", Help: "JSON file to write (required)"},
+		{Name: "mask", Kind: commandFlagBool, Help: "Write " + fixtures.Mask + " for every value instead of typed fakes"},
+		{Name: "overwrite", Kind: commandFlagBool, Help: "Replace an existing --out file"},
+	},
+	Doc: commandDoc{
+		Synopsis: "dev-vault [--config <path>] [--profile <name>] fixtures [<secret-dev> ...] --out <path> [--mask] [--overwrite]",
+		Description: []string{
+			"Reads dotenv secrets (every pull-eligible dotenv mapping when none is named) and writes",
+			"their keys to --out as a JSON object, so application tests can check config loading",
+			"without real credentials. Keys are the ones pull writes, filters and affixes applied.",
+			"",
+			"Values are replaced, never copied: true/false becomes false, an integer a number derived",
+			"from the key, a URL keeps its scheme and points at fixture.invalid, and anything else",
+			"becomes fixture-<key>. With --mask every value is " + fixtures.Mask + ". Empty values stay empty.",
+			"The output only depends on the keys and the kinds of values, so it is stable across runs",
+			"and secret rotations.",
+		},
+		Notes: []string{
+			"Only dotenv mappings with mode pull|both can be read.",
+		},
+		Examples: []string{
+			"dev-vault fixtures --out testdata/env.json --mask",
+			"dev-vault fixtures bweb-env-bsmart-dev --out testdata/env.json --overwrite",
+		},
+	},
+	RunParsed: runFixturesParsed,
+}
+
+func runFixtures(ctx commandContext, argv []string) int {
+	return runCommand(ctx, argv, fixturesCommandDef)
+}
+
+func runFixturesParsed(ctx commandContext, parsed *parsedCommand) int {
+	return newCommandRuntime(ctx, parsed).execute(func(loaded *config.Loaded, service secretsync.Service) error {
+		out := parsed.String("out")
+		if out == "" {
+			return usageError(i18n.Errorf(i18n.MsgRequiresFlag, "fixtures", "--out"))
+		}
+		args := parsed.fs.Args()
+		selected, err := selectMappingTargetsForMode(loaded.Cfg.Mapping, len(args) == 0, args, commandModePull)
+		if err != nil {
+			return err
+		}
+		var targets []secretsync.MappingTarget
+		names := make([]string, 0, len(selected))
+		for _, target := range selected {
+			if target.Entry.Format == secretsync.MappingFormatDotenv {
+				targets = append(targets, target)
+				names = append(names, target.Name)
+			} else if len(args) > 0 {
+				return usageError(i18n.Errorf(i18n.MsgFormatMustBeDotenv, "fixtures", target.Name))
+			}
+		}
+		if len(targets) == 0 {
+			return usageError(i18n.Errorf(i18n.MsgFixturesNoMappings))
+		}
+
+		values, err := service.RemoteDotenvValues(targets)
+		if err != nil {
+			return runtimeError(i18n.Errorf(i18n.MsgCommandError, "fixtures", err))
+		}
+		mask := parsed.Bool("mask")
+		rendered, _ := json.MarshalIndent(fixtures.Build(values, mask), "", "  ") // strings only; always encodes
+		if err := fsx.AtomicWriteFile(out, append(rendered, '\n'), 0o644, parsed.Bool("overwrite")); err != nil {
+			if errors.Is(err, fsx.ErrExists) {
+				return runtimeError(i18n.Errorf(i18n.MsgCommandError, "fixtures", i18n.Errorf(i18n.MsgFileExists, out)))
+			}
+			return runtimeError(i18n.Errorf(i18n.MsgCommandError, "fixtures", i18n.Errorf(i18n.MsgWriteFile, out, err)))
+		}
+		kind := "fake"
+		if mask {
+			kind = "masked"
+		}
+		if _, err := fmt.Fprintf(ctx.stdout, "wrote %d %s values from %s -> %s\n", len(values), kind, strings.Join(names, ", "), out); err != nil {
+			return outputError(err)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,113 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/bsmartlabs/dev-vault/internal/i18n"
+	"github.com/bsmartlabs/dev-vault/internal/secretsync"
+)
+
+var gcCommandDef = commandDef{
+	Name:    "gc",
+	Summary: "Disable or delete old versions of mapped secrets",
+	Flags: []commandFlagDef{
+		{Name: "all", Kind: commandFlagBool, Help: "Collect all mapping entries with mode push|both (mode defaults to both)"},
+		{Name: "keep", Kind: commandFlagString, ValueName: "<n>", Help: "Number of newest enabled versions to keep (required)"},
+		{Name: "delete", Kind: commandFlagBool, Help: "Delete old versions, disabled ones included, instead of disabling them"},
+		{Name: "yes", Kind: commandFlagBool, Help: "Apply the plan (without it, only the plan is printed)"},
+	},
+	Doc: commandDoc{
+		Synopsis: "dev-vault [--config <path>] [--profile <name>] gc (--all | <secret-dev> ...) --keep <n> [--delete] [--yes]",
+		Description: []string{
+			"Keeps the <n> newest enabled versions of each mapped secret and disables the older",
+			"enabled ones. With --delete, every other version is deleted instead, disabled ones",
+			"included. A revision pinned by mapping.revision is always kept.",
+			"Without --yes nothing is changed: a table lists, per secret, what would be removed.",
+			"Payloads are never read.",
+		},
+		Notes: []string{
+			"Only mappings with mode push|both are collected.",
+			"aws cannot delete one version: with --delete, its old enabled versions are disabled",
+			"and disabled ones are left to age out. Vault destroys deleted versions' data.",
+		},
+		Examples: []string{
+			"dev-vault gc --all --keep 5",
+			"dev-vault gc --all --keep 5 --yes",
+			"dev-vault gc bweb-env-bsmart-dev --keep 2 --delete --yes",
+		},
+	},
+	RunParsed: runGCParsed,
+}
+
+func runGC(ctx commandContext, argv []string) int {
+	return runCommand(ctx, argv, gcCommandDef)
+}
+
+func runGCParsed(ctx commandContext, parsed *parsedCommand) int {
+	var keep int
+	return newCommandRuntime(ctx, parsed).executeMapping(mappingCommandSpec{
+		mode: commandModePush,
+		all:  parsed.Bool("all"),
+		preflight: func(targets []secretsync.MappingTarget) error {
+			value := parsed.String("keep")
+			n, err := strconv.ParseUint(value, 10, 32)
+			if err != nil || n == 0 {
+				return usageError(i18n.Errorf(i18n.MsgNeedsKeep, value))
+			}
+			keep = int(n)
+			return nil
+		},
+		execute: func(service secretsync.Service, targets []secretsync.MappingTarget) error {
+			plans := make([][]secretsync.GCVersion, len(targets))
+			for i, target := range targets {
+				plan, err := service.PlanGC(target, keep, parsed.Bool("delete"))
+				if err != nil {
+					return err
+				}
+				plans[i] = plan
+			}
+			if !parsed.Bool("yes") {
+				return printGCPlan(ctx, parsed, targets, plans)
+			}
+			for i, target := range targets {
+				done, err := service.GC(target, plans[i])
+				for _, version := range done {
+					verb := "disabled"
+					if version.Action == secretsync.GCDelete {
+						verb = "deleted"
+					}
+					if _, err := fmt.Fprintf(ctx.stdout, "%s %s (rev=%d)\n", verb, target.Name, version.Revision); err != nil {
+						return outputError(err)
+					}
+				}
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	})
+}
+
+// printGCPlan prints what gc --yes would remove, one row per version.
+func printGCPlan(ctx commandContext, parsed *parsedCommand, targets []secretsync.MappingTarget, plans [][]secretsync.GCVersion) error {
+	tbl := newTable(ctx.stdout, parsed.plain, "NAME", "REVISION", "STATUS", "CREATED", "ACTION")
+	for i, target := range targets {
+		for _, version := range plans[i] {
+			created := "-"
+			if !version.CreatedAt.IsZero() {
+				created = version.CreatedAt.UTC().Format(time.RFC3339)
+			}
+			tbl.row(target.Name, fmt.Sprintf("%d", version.Revision), version.Status, created, version.Action)
+		}
+	}
+	if err := tbl.flush(); err != nil {
+		return outputError(err)
+	}
+	if _, err := fmt.Fprintln(ctx.stderr, parsed.msg.Text(i18n.MsgDryRunNothingChanged)); err != nil {
+		return outputError(err)
+	}
+	return nil
+}
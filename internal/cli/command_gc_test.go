@@ -0,0 +1,102 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/bsmartlabs/dev-vault/internal/config"
+	secret "github.com/scaleway/scaleway-sdk-go/api/secret/v1beta1"
+)
+
+func TestRunGC(t *testing.T) {
+	dir := t.TempDir()
+	cfgPath := writeConfig(t, dir, `{"organization_id":"org","project_id":"proj","region":"fr-par","mapping":{
+		"db-dev":{"file":"db.json"},
+		"api-dev":{"file":"api.json"},
+		"pull-dev":{"file":"pull.json","mode":"pull"}
+	}}`)
+	api := newFakeSecretAPI()
+	db := api.AddSecret("proj", "db-dev", "/", secret.SecretTypeOpaque)
+	for range 4 {
+		api.AddEnabledVersion(db.ID, []byte("payload"))
+	}
+	api.versions[db.ID][1].enabled = false
+	apiSecret := api.AddSecret("proj", "api-dev", "/", secret.SecretTypeOpaque)
+	api.AddEnabledVersion(apiSecret.ID, []byte("payload"))
+	deps := baseDeps(func(config.Config, string) (SecretAPI, error) { return api, nil })
+	run := func(args ...string) (int, string, string) {
+		var out, errBuf bytes.Buffer
+		code := Run(append([]string{"dev-vault", "--config", cfgPath, "--plain"}, args...), &out, &errBuf, deps)
+		return code, out.String(), errBuf.String()
+	}
+	listed := func() string {
+		var out []string
+		for _, v := range api.versions[db.ID] {
+			out = append(out, fmt.Sprintf("%d:%t", v.revision, v.enabled))
+		}
+		return strings.Join(out, " ")
+	}
+
+	// Without --yes only the plan is printed.
+	code, out, errOut := run("gc", "--all", "--keep", "2", "--delete")
+	if code != 0 || listed() != "1:true 2:false 3:true 4:true" {
+		t.Fatalf("dry run changed something: %d %s (%s)", code, listed(), errOut)
+	}
+	for _, want := range []string{"NAME", "ACTION", "db-dev", "2", "disabled", "2026-01-01T01:00:00Z", "delete"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("plan missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "api-dev") || !strings.Contains(errOut, "pass --yes to apply") {
+		t.Fatalf("unexpected plan: %s (%s)", out, errOut)
+	}
+
+	if code, out, errOut := run("gc", "db-dev", "--keep", "2", "--yes"); code != 0 || out != "disabled db-dev (rev=1)\n" || listed() != "1:false 2:false 3:true 4:true" {
+		t.Fatalf("unexpected gc: %d %q %s (%s)", code, out, listed(), errOut)
+	}
+	if code, out, errOut := run("gc", "db-dev", "--keep", "1", "--delete", "--yes"); code != 0 || out != "deleted db-dev (rev=3)\ndeleted db-dev (rev=2)\ndeleted db-dev (rev=1)\n" || listed() != "4:true" {
+		t.Fatalf("unexpected gc --delete: %d %q %s (%s)", code, out, listed(), errOut)
+	}
+
+	for _, tc := range []struct {
+		args []string
+		code int
+		want string
+	}{
+		{[]string{"gc", "db-dev"}, 2, `gc needs --keep with a positive number of versions, got ""`},
+		{[]string{"gc", "db-dev", "--keep", "0"}, 2, `got "0"`},
+		{[]string{"gc", "pull-dev", "--keep", "1"}, 2, "pull-dev"},
+	} {
+		if code, _, errOut := run(tc.args...); code != tc.code || !strings.Contains(errOut, tc.want) {
+			t.Fatalf("%v: expected %d with %q, got %d (%s)", tc.args, tc.code, tc.want, code, errOut)
+		}
+	}
+
+	for range 2 {
+		api.AddEnabledVersion(db.ID, []byte("payload"))
+	}
+	// The plan, the hint, and the change lines cannot be written.
+	var errBuf bytes.Buffer
+	if code := Run([]string{"dev-vault", "--config", cfgPath, "gc", "db-dev", "--keep", "1"}, &failAfterWriter{}, &errBuf, deps); code != 1 {
+		t.Fatalf("expected table error, got %d", code)
+	}
+	if code := Run([]string{"dev-vault", "--config", cfgPath, "gc", "db-dev", "--keep", "1"}, &bytes.Buffer{}, &failAfterWriter{}, deps); code != 1 {
+		t.Fatalf("expected hint error, got %d", code)
+	}
+	if code := runGC(commandContext{stdout: &failingWriter{}, stderr: &errBuf, configPath: cfgPath, deps: deps}, []string{"db-dev", "--keep", "1", "--yes"}); code != 1 {
+		t.Fatalf("expected output error, got %d", code)
+	}
+
+	api.AddEnabledVersion(db.ID, []byte("payload"))
+	api.disableErr = errors.New("disable boom")
+	if code, _, errOut := run("gc", "db-dev", "--keep", "1", "--yes"); code != 1 || !strings.Contains(errOut, "disable db-dev rev=") {
+		t.Fatalf("expected disable error, got %d (%s)", code, errOut)
+	}
+	api.versionsErr = errors.New("list boom")
+	if code, _, errOut := run("gc", "db-dev", "--keep", "1"); code != 1 || !strings.Contains(errOut, "list versions db-dev: list boom") {
+		t.Fatalf("expected list error, got %d (%s)", code, errOut)
+	}
+}
@@ -0,0 +1,90 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/bsmartlabs/dev-vault/internal/config"
+	"github.com/bsmartlabs/dev-vault/internal/generate"
+	"github.com/bsmartlabs/dev-vault/internal/i18n"
+	"github.com/bsmartlabs/dev-vault/internal/secretsync"
+)
+
+var generateCommandDef = commandDef{
+	Name:    "generate",
+	Summary: "Create a new secret and its local file from a generator template",
+	Flags: []commandFlagDef{
+		{Name: "template", Kind: commandFlagString, ValueName: "<path>", Help: "Generator template (required)"},
+		{Name: "description", Kind: commandFlagString, ValueName: "<text>", Help: "Description for the first version (optional)"},
+	},
+	Doc: commandDoc{
+		Synopsis: "dev-vault [--config <path>] [--profile <name>] generate --template <path> <secret-dev>",
+		Description: []string{
+			"Generates fresh values from a template, creates the secret with them as its first",
+			"version, and writes the mapped dotenv file. Never prints the generated values.",
+			"",
+			"Template keys map to a generator spec:",
+			"  - {\"generator\": \"hex\", \"bytes\": 32}   random bytes, hex-encoded (default 32 bytes)",
+			"  - {\"generator\": \"uuid\"}               random UUID (version 4)",
+			"  - {\"generator\": \"bcrypt\", \"cost\": 12} bcrypt hash of a value typed at a hidden prompt",
+			"  - {\"value\": \"app\"}                    literal value",
+		},
+		Notes: []string{
+			"The secret must be mapped with format dotenv and a mode that allows push.",
+			"generate refuses to run when the secret or the local file already exists.",
+			"The secret type comes from mapping.type, then the template's \"type\", then key_value.",
+			"bcrypt runs 'htpasswd -nBC <cost>' for the hidden prompt; htpasswd must be on PATH.",
+		},
+		Examples: []string{
+			"dev-vault generate --template templates/service-creds.json billing-env-dev",
+		},
+	},
+	RunParsed: runGenerateParsed,
+}
+
+func runGenerate(ctx commandContext, argv []string) int {
+	return runCommand(ctx, argv, generateCommandDef)
+}
+
+func runGenerateParsed(ctx commandContext, parsed *parsedCommand) int {
+	return newCommandRuntime(ctx, parsed).execute(func(loaded *config.Loaded, service secretsync.Service) error {
+		args := parsed.fs.Args()
+		if len(args) != 1 {
+			return usageError(i18n.Errorf(i18n.MsgTakesOneSecretName, "generate"))
+		}
+		templatePath := parsed.String("template")
+		if templatePath == "" {
+			return usageError(i18n.Errorf(i18n.MsgRequiresFlag, "generate", "--template"))
+		}
+		targets, err := selectMappingTargetsForMode(loaded.Cfg.Mapping, false, args, commandModePush)
+		if err != nil {
+			return err
+		}
+		target := targets[0]
+		if target.Entry.Format != secretsync.MappingFormatDotenv {
+			return usageError(i18n.Errorf(i18n.MsgFormatMustBeDotenv, "generate", target.Name))
+		}
+
+		template, err := generate.Load(templatePath)
+		if err != nil {
+			return runtimeError(err)
+		}
+		if target.Entry.Type == "" {
+			target.Entry.Type = template.Type
+		}
+		if target.Entry.Type == "" {
+			target.Entry.Type = "key_value"
+		}
+		values, err := template.Generate(ctx.stderr)
+		if err != nil {
+			return runtimeError(err)
+		}
+		result, err := service.Generate(target, values, parsed.String("description"))
+		if err != nil {
+			return runtimeError(err)
+		}
+		if _, err := fmt.Fprintf(ctx.stdout, "generated %s (rev=%d, %d keys) -> %s\n", result.Name, result.Revision, len(values), target.Entry.File); err != nil {
+			return outputError(err)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,91 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bsmartlabs/dev-vault/internal/config"
+	"github.com/bsmartlabs/dev-vault/internal/secretprovider"
+)
+
+func TestRunGenerate(t *testing.T) {
+	// Keep a real htpasswd off PATH so the bcrypt case never prompts.
+	t.Setenv("PATH", t.TempDir())
+	dir := t.TempDir()
+	cfgPath := writeConfig(t, dir, `{"organization_id":"org","project_id":"proj","region":"fr-par","mapping":{
+		"svc-env-dev":{"file":".env","format":"dotenv"},
+		"typed-env-dev":{"file":"typed.env","format":"dotenv","type":"opaque"},
+		"raw-dev":{"file":"raw"},
+		"pull-env-dev":{"file":"p.env","format":"dotenv","mode":"pull"}
+	}}`)
+	tplPath := filepath.Join(dir, "creds.json")
+	if err := os.WriteFile(tplPath, []byte(`{"keys":{"SESSION_SECRET":{"generator":"hex","bytes":8},"DB_USER":{"value":"app"}}}`), 0o600); err != nil {
+		t.Fatalf("write template: %v", err)
+	}
+	bcryptPath := filepath.Join(dir, "bcrypt.json")
+	if err := os.WriteFile(bcryptPath, []byte(`{"type":"opaque","keys":{"HASH":{"generator":"bcrypt"}}}`), 0o600); err != nil {
+		t.Fatalf("write template: %v", err)
+	}
+	api := newFakeSecretAPI()
+	deps := baseDeps(func(config.Config, string) (SecretAPI, error) { return api, nil })
+	run := func(stdout *bytes.Buffer, args ...string) (int, string) {
+		var errBuf bytes.Buffer
+		code := runGenerate(commandContext{stdout: stdout, stderr: &errBuf, configPath: cfgPath, deps: deps}, args)
+		return code, errBuf.String()
+	}
+
+	var out bytes.Buffer
+	if code, errOut := run(&out, "--template", tplPath, "svc-env-dev"); code != 0 || out.String() != "generated svc-env-dev (rev=1, 2 keys) -> .env\n" {
+		t.Fatalf("unexpected generate: %d %q %q", code, out.String(), errOut)
+	}
+	if api.secrets[0].Type != secretprovider.SecretTypeKeyValue {
+		t.Fatalf("expected key_value default type, got %s", api.secrets[0].Type)
+	}
+	got, err := os.ReadFile(filepath.Join(dir, ".env"))
+	if err != nil || !strings.HasPrefix(string(got), "# managed by dev-vault: svc-env-dev\nDB_USER=\"app\"\nSESSION_SECRET=\"") {
+		t.Fatalf("unexpected local file: %q %v", got, err)
+	}
+	if strings.Contains(out.String(), "app") {
+		t.Fatal("generated values must never be printed")
+	}
+
+	if code, errOut := run(&out, "--template", tplPath, "typed-env-dev"); code != 0 || api.secrets[1].Type != secretprovider.SecretTypeOpaque {
+		t.Fatalf("expected mapping type, got %d %q %s", code, errOut, api.secrets[1].Type)
+	}
+
+	for _, tc := range []struct {
+		args    []string
+		code    int
+		wantErr string
+	}{
+		{[]string{"--template", tplPath}, 2, "exactly one secret name"},
+		{[]string{"svc-env-dev"}, 2, "requires --template"},
+		{[]string{"--template", tplPath, "svc-env"}, 2, "refusing non-dev secret name"},
+		{[]string{"--template", tplPath, "pull-env-dev"}, 2, "not allowed in push mode"},
+		{[]string{"--template", tplPath, "raw-dev"}, 2, "mapping format must be dotenv"},
+		{[]string{"--template", filepath.Join(dir, "missing.json"), "svc-env-dev"}, 1, "read template"},
+		{[]string{"--template", bcryptPath, "svc-env-dev"}, 1, "requires htpasswd"},
+		{[]string{"--template", tplPath, "svc-env-dev"}, 1, "file exists"},
+	} {
+		if code, errOut := run(&bytes.Buffer{}, tc.args...); code != tc.code || !strings.Contains(errOut, tc.wantErr) {
+			t.Fatalf("%v: expected %d %q, got %d %q", tc.args, tc.code, tc.wantErr, code, errOut)
+		}
+	}
+
+	if err := os.Remove(filepath.Join(dir, "typed.env")); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	var errBuf bytes.Buffer
+	if code := runGenerate(commandContext{stdout: &failingWriter{}, stderr: &errBuf, configPath: cfgPath, deps: deps}, []string{"--template", tplPath, "svc-env-dev"}); code != 1 {
+		t.Fatalf("expected failure, got %d", code)
+	}
+	cleanDir := t.TempDir()
+	cleanCfg := writeConfig(t, cleanDir, `{"organization_id":"org","project_id":"proj","region":"fr-par","mapping":{"out-env-dev":{"file":".env","format":"dotenv"}}}`)
+	errBuf.Reset()
+	if code := runGenerate(commandContext{stdout: &failingWriter{}, stderr: &errBuf, configPath: cleanCfg, deps: deps}, []string{"--template", tplPath, "out-env-dev"}); code != 1 {
+		t.Fatalf("expected output error, got %d %q", code, errBuf.String())
+	}
+}
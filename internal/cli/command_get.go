@@ -0,0 +1,137 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+
+	"github.com/bsmartlabs/dev-vault/internal/basiccreds"
+	"github.com/bsmartlabs/dev-vault/internal/config"
+	"github.com/bsmartlabs/dev-vault/internal/fsx"
+	"github.com/bsmartlabs/dev-vault/internal/i18n"
+	"github.com/bsmartlabs/dev-vault/internal/secretcontract"
+	"github.com/bsmartlabs/dev-vault/internal/secretsync"
+)
+
+var getCommandDef = commandDef{
+	Name:    "get",
+	Summary: "Render a basic_credentials secret as a .netrc entry, docker auth, or curl header file",
+	Flags: []commandFlagDef{
+		{Name: "as-docker-config", Kind: commandFlagString, ValueName: "<registry>", Help: "Set auths[<registry>] in a docker config.json"},
+		{Name: "as-header", Kind: commandFlagBool, Help: "Write an Authorization header file for curl -H @<file>"},
+		{Name: "as-netrc", Kind: commandFlagString, ValueName: "<host>", Help: "Set the machine <host> entry in a .netrc file"},
+		{Name: "output", Kind: commandFlagString, ValueName: "<path>", Help: "File to write (required)"},
+		{Name: "overwrite", Kind: commandFlagBool, Help: "Replace an existing --as-header file"},
+	},
+	Doc: commandDoc{
+		Synopsis: "dev-vault [--config <path>] [--profile <name>] get <secret-dev> (--as-netrc <host> | --as-docker-config <registry> | --as-header) --output <path>",
+		Description: []string{
+			"Reads the latest enabled version of a mapped basic_credentials secret and writes it",
+			"in the format another tool expects. Output always goes to --output with mode 0600;",
+			"the credentials are never printed.",
+			"",
+			"Formats:",
+			"  - --as-netrc replaces the entry for <host> in an existing .netrc, or appends one.",
+			"  - --as-docker-config sets auths[<registry>] and keeps the rest of config.json.",
+			"  - --as-header writes 'Authorization: Basic ...' for curl -H @<path>.",
+		},
+		Notes: []string{
+			"netrc entries cannot hold whitespace or quotes; such credentials are refused.",
+		},
+		Examples: []string{
+			"dev-vault get artifacts-creds-dev --as-netrc artifacts.example.test --output ~/.netrc",
+			"dev-vault get registry-creds-dev --as-docker-config registry.example.test --output ~/.docker/config.json",
+			"dev-vault get api-creds-dev --as-header --output .api-auth && curl -H @.api-auth https://api.example.test",
+		},
+	},
+	RunParsed: runGetParsed,
+}
+
+func runGet(ctx commandContext, argv []string) int {
+	return runCommand(ctx, argv, getCommandDef)
+}
+
+func runGetParsed(ctx commandContext, parsed *parsedCommand) int {
+	return newCommandRuntime(ctx, parsed).execute(func(loaded *config.Loaded, service secretsync.Service) error {
+		args := parsed.fs.Args()
+		if len(args) != 1 {
+			return usageError(i18n.Errorf(i18n.MsgTakesOneSecretName, "get"))
+		}
+		netrcHost, registry, header := parsed.String("as-netrc"), parsed.String("as-docker-config"), parsed.Bool("as-header")
+		formats := 0
+		for _, set := range []bool{netrcHost != "", registry != "", header} {
+			if set {
+				formats++
+			}
+		}
+		if formats != 1 {
+			return usageError(i18n.Errorf(i18n.MsgGetOneFormat))
+		}
+		output := parsed.String("output")
+		if output == "" {
+			return usageError(i18n.Errorf(i18n.MsgGetRequiresOutput))
+		}
+		if parsed.Bool("overwrite") && !header {
+			return usageError(i18n.Errorf(i18n.MsgGetOverwriteHeaderOnly))
+		}
+		targets, err := selectMappingTargetsForMode(loaded.Cfg.Mapping, false, args, commandModePull)
+		if err != nil {
+			return err
+		}
+		target := targets[0]
+		// Like pull, never rename over a symlink or a hard link: the link
+		// itself would be replaced, or the other names left behind.
+		if info, err := os.Lstat(output); err == nil {
+			if err := fsx.CheckReplaceable(info); err != nil {
+				return runtimeError(i18n.Errorf(i18n.MsgTargetError, "get", target.Name, i18n.Errorf(i18n.MsgRefusingReplace, output, err)))
+			}
+		}
+		access, err := service.Access(target)
+		if err != nil {
+			return runtimeError(err)
+		}
+		if string(access.Type) != secretcontract.TypeBasicCreds {
+			return usageError(i18n.Errorf(i18n.MsgWrongSecretType, "get", target.Name, access.Type, secretcontract.TypeBasicCreds))
+		}
+		creds, err := basiccreds.Parse(access.Data)
+		if err != nil {
+			return runtimeError(i18n.Errorf(i18n.MsgTargetError, "get", target.Name, err))
+		}
+
+		var rendered []byte
+		var wrote string
+		switch {
+		case header:
+			rendered = creds.Header()
+			wrote = parsed.msg.Sprintf(i18n.MsgWroteHeader, target.Name, access.Revision, output)
+		default:
+			existing, err := os.ReadFile(output)
+			if err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return runtimeError(i18n.Errorf(i18n.MsgTargetError, "get", target.Name, i18n.Errorf(i18n.MsgReadFile, output, err)))
+			}
+			if netrcHost != "" {
+				rendered, err = creds.MergeNetrc(existing, netrcHost)
+				wrote = parsed.msg.Sprintf(i18n.MsgWroteNetrc, netrcHost, target.Name, access.Revision, output)
+			} else {
+				rendered, err = creds.MergeDockerConfig(existing, registry)
+				wrote = parsed.msg.Sprintf(i18n.MsgWroteDockerAuth, registry, target.Name, access.Revision, output)
+			}
+			if err != nil {
+				return runtimeError(i18n.Errorf(i18n.MsgTargetError, "get", target.Name, err))
+			}
+		}
+		// Merged formats rewrite the file they just read; a header file is
+		// only replaced on request.
+		if err := fsx.AtomicWriteFile(output, rendered, 0o600, !header || parsed.Bool("overwrite")); err != nil {
+			if errors.Is(err, fsx.ErrExists) {
+				return runtimeError(i18n.Errorf(i18n.MsgTargetError, "get", target.Name, i18n.Errorf(i18n.MsgFileExists, output)))
+			}
+			return runtimeError(i18n.Errorf(i18n.MsgTargetError, "get", target.Name, i18n.Errorf(i18n.MsgWriteFile, output, err)))
+		}
+		if _, err := fmt.Fprintln(ctx.stdout, wrote); err != nil {
+			return outputError(err)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,128 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bsmartlabs/dev-vault/internal/config"
+	secret "github.com/scaleway/scaleway-sdk-go/api/secret/v1beta1"
+)
+
+func TestRunGet(t *testing.T) {
+	dir := t.TempDir()
+	cfgPath := writeConfig(t, dir, `{"organization_id":"org","project_id":"proj","region":"fr-par","mapping":{
+		"ci-creds-dev":{"file":"ci.json"},
+		"spaced-creds-dev":{"file":"spaced.json"},
+		"bad-creds-dev":{"file":"bad.json"},
+		"plain-dev":{"file":"plain"},
+		"push-creds-dev":{"file":"push.json","mode":"push"}
+	}}`)
+	api := newFakeSecretAPI()
+	add := func(name string, typ secret.SecretType, payload string) {
+		sec := api.AddSecret("proj", name, "/", typ)
+		api.AddEnabledVersion(sec.ID, []byte(payload))
+	}
+	add("ci-creds-dev", secret.SecretTypeBasicCredentials, `{"username":"ci","password":"s3cret"}`)
+	add("spaced-creds-dev", secret.SecretTypeBasicCredentials, `{"username":"ci","password":"s3 cret"}`)
+	add("bad-creds-dev", secret.SecretTypeBasicCredentials, `{}`)
+	add("plain-dev", secret.SecretTypeOpaque, `x`)
+	deps := baseDeps(func(config.Config, string) (SecretAPI, error) { return api, nil })
+	run := func(args ...string) (int, string, string) {
+		var out, errBuf bytes.Buffer
+		code := Run(append([]string{"dev-vault", "--config", cfgPath, "get"}, args...), &out, &errBuf, deps)
+		return code, out.String(), errBuf.String()
+	}
+	out := t.TempDir()
+	netrc := filepath.Join(out, ".netrc")
+	docker := filepath.Join(out, "docker", "config.json")
+	header := filepath.Join(out, "auth")
+
+	if err := os.WriteFile(netrc, []byte("machine other.test login o password o\n"), 0o600); err != nil {
+		t.Fatalf("write netrc: %v", err)
+	}
+	code, stdout, errOut := run("ci-creds-dev", "--as-netrc", "a.test", "--output", netrc)
+	if code != 0 || stdout != "wrote netrc entry for a.test from ci-creds-dev (rev=1) -> "+netrc+"\n" {
+		t.Fatalf("unexpected netrc run: %d %q %q", code, stdout, errOut)
+	}
+	if got, _ := os.ReadFile(netrc); string(got) != "machine other.test login o password o\nmachine a.test login ci password s3cret\n" {
+		t.Fatalf("unexpected netrc:\n%s", got)
+	}
+
+	if code, stdout, errOut := run("ci-creds-dev", "--as-docker-config", "registry.test", "--output", docker); code != 0 || !strings.HasPrefix(stdout, "wrote docker auth for registry.test") {
+		t.Fatalf("unexpected docker run: %d %q %q", code, stdout, errOut)
+	}
+	if got, _ := os.ReadFile(docker); !strings.Contains(string(got), `"auth": "Y2k6czNjcmV0"`) {
+		t.Fatalf("unexpected docker config:\n%s", got)
+	}
+
+	if code, stdout, errOut := run("ci-creds-dev", "--as-header", "--output", header); code != 0 || !strings.HasPrefix(stdout, "wrote Authorization header") || strings.Contains(stdout+errOut, "s3cret") || strings.Contains(stdout, "Y2k6") {
+		t.Fatalf("unexpected header run: %d %q %q", code, stdout, errOut)
+	}
+	for _, path := range []string{netrc, docker, header} {
+		if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0o600 {
+			t.Fatalf("%s: expected mode 0600, got %v %v", path, info.Mode().Perm(), err)
+		}
+	}
+	if code, _, errOut := run("ci-creds-dev", "--as-header", "--output", header); code != 1 || !strings.Contains(errOut, "file exists (use --overwrite)") {
+		t.Fatalf("expected header exists error, got %d %q", code, errOut)
+	}
+	if code, _, errOut := run("ci-creds-dev", "--as-header", "--overwrite", "--output", header); code != 0 {
+		t.Fatalf("expected header overwrite, got %d %q", code, errOut)
+	}
+
+	// A symlinked --output is refused rather than replaced by a file.
+	linked := filepath.Join(out, "linked-netrc")
+	if err := os.Symlink(netrc, linked); err != nil {
+		t.Fatalf("symlink: %v", err)
+	}
+	if code, _, errOut := run("ci-creds-dev", "--as-netrc", "b.test", "--output", linked); code != 1 || !strings.Contains(errOut, "refusing to replace "+linked+": not a regular file (symbolic link)") {
+		t.Fatalf("expected symlink refusal, got %d %q", code, errOut)
+	}
+	if info, err := os.Lstat(linked); err != nil || info.Mode()&os.ModeSymlink == 0 {
+		t.Fatalf("expected the symlink kept, got %v %v", info, err)
+	}
+	if got, _ := os.ReadFile(netrc); strings.Contains(string(got), "b.test") {
+		t.Fatalf("expected the link target untouched:\n%s", got)
+	}
+
+	notDir := filepath.Join(out, "notdir")
+	if err := os.WriteFile(notDir, []byte("x"), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	for _, tc := range []struct {
+		args    []string
+		code    int
+		wantErr string
+	}{
+		{[]string{"--as-header", "--output", header}, 2, "exactly one secret name"},
+		{[]string{"ci-creds-dev", "--output", header}, 2, "exactly one of --as-netrc, --as-docker-config, --as-header"},
+		{[]string{"ci-creds-dev", "--as-header", "--as-netrc", "a.test", "--output", header}, 2, "exactly one of"},
+		{[]string{"ci-creds-dev", "--as-header"}, 2, "get requires --output; credentials are never printed"},
+		{[]string{"ci-creds-dev", "--as-netrc", "a.test", "--overwrite", "--output", netrc}, 2, "--overwrite only applies to --as-header"},
+		{[]string{"ci-creds", "--as-header", "--output", header}, 2, "refusing non-dev secret name"},
+		{[]string{"push-creds-dev", "--as-header", "--output", header}, 2, "not allowed in pull mode"},
+		{[]string{"plain-dev", "--as-header", "--output", header}, 2, "secret type is opaque, expected basic_credentials"},
+		{[]string{"bad-creds-dev", "--as-header", "--output", header}, 1, "get bad-creds-dev: basic credentials: missing username"},
+		{[]string{"spaced-creds-dev", "--as-netrc", "a.test", "--output", netrc}, 1, "netrc cannot hold"},
+		{[]string{"ci-creds-dev", "--as-docker-config", "registry.test", "--output", netrc}, 1, "get ci-creds-dev: docker config:"},
+		{[]string{"ci-creds-dev", "--as-netrc", "a.test", "--output", filepath.Join(notDir, "x")}, 1, "get ci-creds-dev: read"},
+		{[]string{"ci-creds-dev", "--as-header", "--output", filepath.Join(notDir, "x")}, 1, "get ci-creds-dev: write"},
+	} {
+		if code, _, errOut := run(tc.args...); code != tc.code || !strings.Contains(errOut, tc.wantErr) || strings.Contains(errOut, "s3cret") {
+			t.Fatalf("%v: expected %d %q, got %d %q", tc.args, tc.code, tc.wantErr, code, errOut)
+		}
+	}
+
+	var errBuf bytes.Buffer
+	if code := runGet(commandContext{stdout: &failingWriter{}, stderr: &errBuf, configPath: cfgPath, deps: deps}, []string{"ci-creds-dev", "--as-header", "--overwrite", "--output", header}); code != 1 {
+		t.Fatalf("expected output error, got %d", code)
+	}
+	api.accessErr = errors.New("access boom")
+	if code, _, errOut := run("ci-creds-dev", "--as-header", "--output", header); code != 1 || !strings.Contains(errOut, "access boom") {
+		t.Fatalf("expected access error, got %d %q", code, errOut)
+	}
+}
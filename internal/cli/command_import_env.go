@@ -0,0 +1,141 @@
+package cli
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/bsmartlabs/dev-vault/internal/config"
+	"github.com/bsmartlabs/dev-vault/internal/i18n"
+	"github.com/bsmartlabs/dev-vault/internal/secretsync"
+)
+
+var importEnvCommandDef = commandDef{
+	Name:    "import-env",
+	Summary: "Turn an existing .env file into a new key_value secret and its mapping entry",
+	Flags: []commandFlagDef{
+		{Name: "name", Kind: commandFlagString, ValueName: "<secret-dev>", Help: "Name of the secret to create (required)"},
+		{Name: "path", Kind: commandFlagString, ValueName: "<path>", Help: "Secret path (default /)"},
+		{Name: "description", Kind: commandFlagString, ValueName: "<text>", Help: "Description for the first version (optional)"},
+		{Name: "policy-file", Kind: commandFlagString, ValueName: "<path>", Help: policyFileFlagHelp},
+	},
+	Doc: commandDoc{
+		Synopsis: "dev-vault [--config <path>] [--profile <name>] import-env <file> --name <secret-dev> [options]",
+		Description: []string{
+			"Onboards a project that predates Secret Manager in one step: creates a key_value secret,",
+			"pushes the variables of <file> as its first version, and adds a dotenv mapping entry",
+			"for it to .scw.json. Never prints secret payloads.",
+		},
+		Notes: []string{
+			"<file> is relative to the current directory and must be inside the project root.",
+			"import-env refuses a name that is already mapped or a secret that already exists.",
+			"Policy rules are checked before the secret is created, as for push.",
+			".scw.json is rewritten in canonical form (see 'config fmt').",
+		},
+		Examples: []string{
+			"dev-vault import-env .env.local --name svc-env-dev",
+			"dev-vault import-env api/.env --name api-env-dev --path /api",
+		},
+	},
+	RunParsed: runImportEnvParsed,
+}
+
+func runImportEnv(ctx commandContext, argv []string) int {
+	return runCommand(ctx, argv, importEnvCommandDef)
+}
+
+func runImportEnvParsed(ctx commandContext, parsed *parsedCommand) int {
+	r := newCommandRuntime(ctx, parsed)
+	return r.execute(func(loaded *config.Loaded, service secretsync.Service) error {
+		args := parsed.fs.Args()
+		if len(args) != 1 {
+			return usageError(i18n.Errorf(i18n.MsgTakesOneFile, "import-env"))
+		}
+		name := parsed.String("name")
+		if name == "" {
+			return usageError(i18n.Errorf(i18n.MsgRequiresFlag, "import-env", "--name"))
+		}
+		if !config.IsDevSecretName(name) {
+			return usageError(i18n.Errorf(i18n.MsgRefusingNonDev, name))
+		}
+		if _, ok := loaded.Cfg.Mapping[name]; ok {
+			return usageError(i18n.Errorf(i18n.MsgAlreadyMapped, name))
+		}
+		secretPath, err := importSecretPath(parsed)
+		if err != nil {
+			return err
+		}
+		file, err := projectRelativePath(ctx, loaded, args[0])
+		if err != nil {
+			return err
+		}
+
+		imp := newEnvImport(name, file, secretPath)
+		if err := r.checkPushPolicy(loaded, service, []secretsync.MappingTarget{imp.target}); err != nil {
+			return err
+		}
+		revision, err := imp.run(loaded, service, parsed.String("description"))
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(ctx.stdout, "imported %s as %s (rev=%d) and mapped it in %s\n", file, name, revision, loaded.Path); err != nil {
+			return outputError(err)
+		}
+		return nil
+	})
+}
+
+// envImport is one .env file turned into a key_value secret and the dotenv
+// mapping entry added for it.
+type envImport struct {
+	entry  config.MappingEntry
+	target secretsync.MappingTarget
+}
+
+func newEnvImport(name, file, secretPath string) envImport {
+	entry := config.MappingEntry{File: file, Format: config.MappingFormatDotenv, Path: secretPath, Type: "key_value"}
+	target := secretsync.MappingTarget{Name: name, Entry: secretsync.MappingEntryFromConfig(entry)}
+	if target.Entry.Path == "" {
+		target.Entry.Path = "/"
+	}
+	return envImport{entry: entry, target: target}
+}
+
+// run creates the secret, then maps it; a manifest that cannot be rewritten
+// leaves the secret in place and says so.
+func (imp envImport) run(loaded *config.Loaded, service secretsync.Service, description string) (uint32, error) {
+	result, err := service.ImportEnv(imp.target, description)
+	if err != nil {
+		return 0, runtimeError(err)
+	}
+	if err := config.AddMapping(loaded.Path, imp.target.Name, imp.entry); err != nil {
+		return 0, runtimeError(i18n.Errorf(i18n.MsgImportMappingNotUpdated, imp.target.Name, result.Revision, loaded.Path, err))
+	}
+	return result.Revision, nil
+}
+
+func importSecretPath(parsed *parsedCommand) (string, error) {
+	secretPath := parsed.String("path")
+	if secretPath != "" && !strings.HasPrefix(secretPath, "/") {
+		return "", usageError(i18n.Errorf(i18n.MsgPathMustStartWithSlash, secretPath))
+	}
+	return secretPath, nil
+}
+
+// projectRelativePath resolves a path argument against the current directory
+// and returns it relative to the project root, as mapping entries store it.
+func projectRelativePath(ctx commandContext, loaded *config.Loaded, arg string) (string, error) {
+	path := arg
+	if !filepath.IsAbs(path) {
+		wd, err := ctx.deps.Getwd()
+		if err != nil {
+			return "", runtimeError(i18n.Errorf(i18n.MsgGetwd, err))
+		}
+		path = filepath.Join(wd, path)
+	}
+	rel, err := filepath.Rel(loaded.Root, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", usageError(i18n.Errorf(i18n.MsgOutsideProjectRoot, arg, loaded.Root))
+	}
+	return filepath.ToSlash(rel), nil
+}
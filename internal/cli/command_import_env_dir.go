@@ -0,0 +1,169 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/bsmartlabs/dev-vault/internal/config"
+	"github.com/bsmartlabs/dev-vault/internal/i18n"
+	"github.com/bsmartlabs/dev-vault/internal/secretsync"
+)
+
+const (
+	defaultImportPattern = "{name}.env"
+	defaultImportSuffix  = "-dev"
+)
+
+var importEnvDirCommandDef = commandDef{
+	Name:    "import-env-dir",
+	Summary: "Turn every matching .env file of a directory into a new secret and mapping entry",
+	Flags: []commandFlagDef{
+		{Name: "pattern", Kind: commandFlagString, ValueName: "<pattern>", Help: "File name pattern with one {name} placeholder (default {name}.env)"},
+		{Name: "suffix", Kind: commandFlagString, ValueName: "<suffix>", Help: "Appended to {name} to form the secret name (default -dev)"},
+		{Name: "path", Kind: commandFlagString, ValueName: "<path>", Help: "Secret path for every created secret (default /)"},
+		{Name: "description", Kind: commandFlagString, ValueName: "<text>", Help: "Description for the first versions (optional)"},
+		{Name: "policy-file", Kind: commandFlagString, ValueName: "<path>", Help: policyFileFlagHelp},
+		{Name: "dry-run", Kind: commandFlagBool, Help: "List the secrets that would be created and change nothing"},
+	},
+	Doc: commandDoc{
+		Synopsis: "dev-vault [--config <path>] [--profile <name>] import-env-dir <dir> [options]",
+		Description: []string{
+			"Migrates a legacy repository wholesale: each file of <dir> matching --pattern becomes",
+			"a key_value secret named {name}<suffix>, as 'import-env' would create it, with a",
+			"dotenv mapping entry in .scw.json. Never prints secret payloads.",
+		},
+		Notes: []string{
+			"<dir> is relative to the current directory and must be inside the project root.",
+			"Subdirectories are not searched. Files that do not match the pattern are ignored.",
+			"Every file is checked before anything is created: secret names must end in -dev,",
+			"must not be mapped yet, and must not exist remotely; policy rules apply as for push.",
+			"--dry-run runs the same checks and prints what it would create.",
+		},
+		Examples: []string{
+			"dev-vault import-env-dir ./envs --dry-run",
+			"dev-vault import-env-dir ./envs --pattern '{name}.env' --suffix -dev",
+			"dev-vault import-env-dir config --pattern '.env.{name}' --suffix -env-dev",
+		},
+	},
+	RunParsed: runImportEnvDirParsed,
+}
+
+func runImportEnvDir(ctx commandContext, argv []string) int {
+	return runCommand(ctx, argv, importEnvDirCommandDef)
+}
+
+func runImportEnvDirParsed(ctx commandContext, parsed *parsedCommand) int {
+	r := newCommandRuntime(ctx, parsed)
+	return r.execute(func(loaded *config.Loaded, service secretsync.Service) error {
+		args := parsed.fs.Args()
+		if len(args) != 1 {
+			return usageError(i18n.Errorf(i18n.MsgTakesOneDirectory, "import-env-dir"))
+		}
+		patternText := parsed.String("pattern")
+		if patternText == "" {
+			patternText = defaultImportPattern
+		}
+		pattern, err := importFilePattern(patternText)
+		if err != nil {
+			return err
+		}
+		suffix := parsed.String("suffix")
+		if suffix == "" {
+			suffix = defaultImportSuffix
+		}
+		secretPath, err := importSecretPath(parsed)
+		if err != nil {
+			return err
+		}
+		dir, err := projectRelativePath(ctx, loaded, args[0])
+		if err != nil {
+			return err
+		}
+
+		imports, err := planEnvImports(loaded, dir, pattern, suffix, secretPath)
+		if err != nil {
+			return err
+		}
+		if len(imports) == 0 {
+			return usageError(i18n.Errorf(i18n.MsgNoFilesMatch, args[0], patternText))
+		}
+		targets := make([]secretsync.MappingTarget, 0, len(imports))
+		for _, imp := range imports {
+			targets = append(targets, imp.target)
+		}
+		if err := r.checkPushPolicy(loaded, service, targets); err != nil {
+			return err
+		}
+		for _, imp := range imports {
+			if err := service.CheckImportable(imp.target); err != nil {
+				return runtimeError(err)
+			}
+		}
+
+		if parsed.Bool("dry-run") {
+			for _, imp := range imports {
+				if _, err := fmt.Fprintf(ctx.stdout, "would import %s as %s (%s)\n", imp.entry.File, imp.target.Name, imp.entry.Type); err != nil {
+					return outputError(err)
+				}
+			}
+			return nil
+		}
+		description := parsed.String("description")
+		for _, imp := range imports {
+			revision, err := imp.run(loaded, service, description)
+			if err != nil {
+				return err
+			}
+			if _, err := fmt.Fprintf(ctx.stdout, "imported %s as %s (rev=%d)\n", imp.entry.File, imp.target.Name, revision); err != nil {
+				return outputError(err)
+			}
+		}
+		if _, err := fmt.Fprintf(ctx.stdout, "mapped %d secret(s) in %s\n", len(imports), loaded.Path); err != nil {
+			return outputError(err)
+		}
+		return nil
+	})
+}
+
+// importFilePattern compiles a file name pattern such as "{name}.env" into a
+// regexp whose only group captures {name}.
+func importFilePattern(pattern string) (*regexp.Regexp, error) {
+	prefix, rest, ok := strings.Cut(pattern, "{name}")
+	if !ok || strings.Contains(rest, "{name}") || strings.ContainsAny(pattern, `/\`) {
+		return nil, usageError(i18n.Errorf(i18n.MsgInvalidPattern, pattern))
+	}
+	return regexp.MustCompile("^" + regexp.QuoteMeta(prefix) + "(.+)" + regexp.QuoteMeta(rest) + "$"), nil
+}
+
+// planEnvImports lists the files of dir (relative to the project root) that
+// match pattern, in name order, and refuses the batch if any secret name is
+// not a -dev name or is already mapped.
+func planEnvImports(loaded *config.Loaded, dir string, pattern *regexp.Regexp, suffix, secretPath string) ([]envImport, error) {
+	entries, err := os.ReadDir(filepath.Join(loaded.Root, filepath.FromSlash(dir)))
+	if err != nil {
+		return nil, runtimeError(i18n.Errorf(i18n.MsgReadDirectory, err))
+	}
+	var imports []envImport
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		match := pattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			continue
+		}
+		name := match[1] + suffix
+		if !config.IsDevSecretName(name) {
+			return nil, usageError(i18n.Errorf(i18n.MsgRefusingNonDevFrom, name, entry.Name()))
+		}
+		if _, ok := loaded.Cfg.Mapping[name]; ok {
+			return nil, usageError(i18n.Errorf(i18n.MsgAlreadyMappedFrom, name, entry.Name()))
+		}
+		imports = append(imports, newEnvImport(name, path.Join(dir, entry.Name()), secretPath))
+	}
+	return imports, nil
+}
@@ -0,0 +1,137 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bsmartlabs/dev-vault/internal/config"
+	secret "github.com/scaleway/scaleway-sdk-go/api/secret/v1beta1"
+)
+
+func TestRunImportEnvDir(t *testing.T) {
+	setup := func(t *testing.T, extra string) (string, *fakeSecretAPI, Dependencies) {
+		t.Helper()
+		root := t.TempDir()
+		writeConfig(t, root, `{"organization_id":"org","project_id":"proj","region":"fr-par"`+extra+`,"mapping":{"old-dev":{"file":"old.bin"}}}`)
+		envs := filepath.Join(root, "envs")
+		if err := os.MkdirAll(filepath.Join(envs, "nested.env"), 0o700); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		for name, body := range map[string]string{"billing.env": "A=1\n", "api.env": "PROD_B=2\n", "README.md": "docs\n"} {
+			if err := os.WriteFile(filepath.Join(envs, name), []byte(body), 0o600); err != nil {
+				t.Fatalf("write %s: %v", name, err)
+			}
+		}
+		api := newFakeSecretAPI()
+		deps := baseDeps(func(config.Config, string) (SecretAPI, error) { return api, nil })
+		deps.Getwd = func() (string, error) { return root, nil }
+		return root, api, deps
+	}
+
+	t.Run("DryRun", func(t *testing.T) {
+		_, api, deps := setup(t, "")
+		var out, errBuf bytes.Buffer
+		code := Run([]string{"dev-vault", "import-env-dir", "./envs", "--dry-run"}, &out, &errBuf, deps)
+		if code != 0 || out.String() != "would import envs/api.env as api-dev (key_value)\nwould import envs/billing.env as billing-dev (key_value)\n" || len(api.secrets) != 0 {
+			t.Fatalf("unexpected dry run: %d %q %q", code, out.String(), errBuf.String())
+		}
+	})
+
+	t.Run("ImportsAndMaps", func(t *testing.T) {
+		root, api, deps := setup(t, "")
+		var out, errBuf bytes.Buffer
+		code := Run([]string{"dev-vault", "import-env-dir", "envs", "--pattern", "{name}.env", "--suffix", "-env-dev", "--path", "/legacy"}, &out, &errBuf, deps)
+		cfgPath := filepath.Join(root, config.DefaultConfigName)
+		if code != 0 || out.String() != "imported envs/api.env as api-env-dev (rev=1)\nimported envs/billing.env as billing-env-dev (rev=1)\nmapped 2 secret(s) in "+cfgPath+"\n" {
+			t.Fatalf("unexpected import: %d %q %q", code, out.String(), errBuf.String())
+		}
+		if len(api.secrets) != 2 || api.secrets[0].Path != "/legacy" {
+			t.Fatalf("unexpected remote state: %#v", api.secrets)
+		}
+		loaded, err := config.Load(root, "")
+		if err != nil {
+			t.Fatalf("load: %v", err)
+		}
+		if entry := loaded.Cfg.Mapping["billing-env-dev"]; entry.File != "envs/billing.env" || entry.Format != config.MappingFormatDotenv || entry.Path != "/legacy" || len(loaded.Cfg.Mapping) != 3 {
+			t.Fatalf("unexpected mapping: %#v", loaded.Cfg.Mapping)
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		root, api, deps := setup(t, "")
+		api.AddSecret("proj", "billing-dev", "/", secret.SecretTypeKeyValue)
+		if err := os.WriteFile(filepath.Join(root, "envs", "old.cfg"), []byte("A=1\n"), 0o600); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(root, "envs", "bad.txt"), []byte("not dotenv\n"), 0o600); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+		cases := []struct {
+			args []string
+			code int
+			want string
+		}{
+			{nil, 2, "import-env-dir takes exactly one directory"},
+			{[]string{"envs", "--pattern", "env"}, 2, `--pattern must be a file name with exactly one {name}, got "env"`},
+			{[]string{"envs", "--pattern", "{name}-{name}"}, 2, "exactly one {name}"},
+			{[]string{"envs", "--pattern", "sub/{name}"}, 2, "exactly one {name}"},
+			{[]string{"envs", "--path", "x"}, 2, `--path must start with '/', got "x"`},
+			{[]string{".."}, 2, ".. is outside the project root"},
+			{[]string{"missing"}, 1, "read directory: "},
+			{[]string{"envs", "--pattern", "{name}.md"}, 2, "refusing non-dev secret name: README-prod"},
+			{[]string{"envs", "--pattern", "{name}.cfg", "--suffix", "-dev"}, 2, "old-dev (from old.cfg) is already mapped; use push"},
+			{[]string{"envs", "--pattern", "{name}.json"}, 2, "no files in envs match {name}.json"},
+			{[]string{"envs", "--pattern", "{name}.txt"}, 1, "format dotenv bad-dev: "},
+			{[]string{"envs"}, 1, "import billing-dev: secret already exists"},
+		}
+		for _, tc := range cases {
+			if strings.HasSuffix(tc.want, "README-prod") {
+				tc.args = append(tc.args, "--suffix", "-prod")
+			}
+			var out, errBuf bytes.Buffer
+			code := Run(append([]string{"dev-vault", "import-env-dir"}, tc.args...), &out, &errBuf, deps)
+			if code != tc.code || !strings.Contains(errBuf.String(), tc.want) {
+				t.Fatalf("%v: expected %d %q, got %d %q", tc.args, tc.code, tc.want, code, errBuf.String())
+			}
+		}
+		if len(api.secrets) != 1 {
+			t.Fatalf("expected nothing to be created, got %#v", api.secrets)
+		}
+	})
+
+	t.Run("PolicyBlocksTheBatch", func(t *testing.T) {
+		_, api, deps := setup(t, `,"policy":{"level":"enforce","forbidden_key_patterns":["^PROD_"]}`)
+		var out, errBuf bytes.Buffer
+		if code := Run([]string{"dev-vault", "import-env-dir", "envs"}, &out, &errBuf, deps); code != 1 || !strings.Contains(errBuf.String(), "push blocked by policy") || len(api.secrets) != 0 {
+			t.Fatalf("expected policy block, got %d %q", code, errBuf.String())
+		}
+	})
+
+	t.Run("StopsAtTheFirstFailure", func(t *testing.T) {
+		_, api, deps := setup(t, "")
+		api.createVerErr = errors.New("boom")
+		var out, errBuf bytes.Buffer
+		if code := Run([]string{"dev-vault", "import-env-dir", "envs"}, &out, &errBuf, deps); code != 1 || out.String() != "" || !strings.Contains(errBuf.String(), "import api-dev: create version") {
+			t.Fatalf("expected create failure, got %d %q %q", code, out.String(), errBuf.String())
+		}
+	})
+
+	t.Run("OutputErrors", func(t *testing.T) {
+		for _, args := range [][]string{{"envs", "--dry-run"}, {"envs"}} {
+			_, _, deps := setup(t, "")
+			var errBuf bytes.Buffer
+			if code := runImportEnvDir(commandContext{stdout: &failingWriter{}, stderr: &errBuf, deps: deps}, args); code != 1 {
+				t.Fatalf("%v: expected output error, got %d %q", args, code, errBuf.String())
+			}
+		}
+		_, _, deps := setup(t, "")
+		var errBuf bytes.Buffer
+		if code := runImportEnvDir(commandContext{stdout: &failAfterWriter{okWrites: 2}, stderr: &errBuf, deps: deps}, []string{"envs"}); code != 1 {
+			t.Fatalf("expected summary output error, got %d %q", code, errBuf.String())
+		}
+	})
+}
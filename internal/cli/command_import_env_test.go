@@ -0,0 +1,129 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bsmartlabs/dev-vault/internal/config"
+	secret "github.com/scaleway/scaleway-sdk-go/api/secret/v1beta1"
+)
+
+func TestRunImportEnv(t *testing.T) {
+	setup := func(t *testing.T, extra string) (string, *fakeSecretAPI, Dependencies) {
+		t.Helper()
+		root := t.TempDir()
+		writeConfig(t, root, `{"organization_id":"org","project_id":"proj","region":"fr-par"`+extra+`,"mapping":{"old-dev":{"file":"old.bin"}}}`)
+		if err := os.MkdirAll(filepath.Join(root, "api"), 0o700); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(root, "api", ".env"), []byte("A=1\nPROD_B=2\n"), 0o600); err != nil {
+			t.Fatalf("write env: %v", err)
+		}
+		api := newFakeSecretAPI()
+		deps := baseDeps(func(config.Config, string) (SecretAPI, error) { return api, nil })
+		deps.Getwd = func() (string, error) { return filepath.Join(root, "api"), nil }
+		return root, api, deps
+	}
+
+	t.Run("ImportsAndMaps", func(t *testing.T) {
+		root, api, deps := setup(t, "")
+		var out, errBuf bytes.Buffer
+		code := Run([]string{"dev-vault", "import-env", ".env", "--name", "api-env-dev", "--path", "/api", "--description", "onboarding"}, &out, &errBuf, deps)
+		cfgPath := filepath.Join(root, config.DefaultConfigName)
+		if code != 0 || out.String() != "imported api/.env as api-env-dev (rev=1) and mapped it in "+cfgPath+"\n" {
+			t.Fatalf("unexpected import: %d %q %q", code, out.String(), errBuf.String())
+		}
+		if len(api.secrets) != 1 || api.secrets[0].Path != "/api" || api.secrets[0].Type != "key_value" || string(api.versions[api.secrets[0].ID][0].data) != `{"A":"1","PROD_B":"2"}` {
+			t.Fatalf("unexpected remote state: %#v", api.secrets)
+		}
+		loaded, err := config.Load(root, "")
+		if err != nil {
+			t.Fatalf("load: %v", err)
+		}
+		if entry := loaded.Cfg.Mapping["api-env-dev"]; entry.File != "api/.env" || entry.Format != config.MappingFormatDotenv || entry.Type != "key_value" || entry.Path != "/api" {
+			t.Fatalf("unexpected mapping entry: %#v", entry)
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		_, api, deps := setup(t, "")
+		api.AddSecret("proj", "taken-env-dev", "/", secret.SecretTypeKeyValue)
+		cases := []struct {
+			args []string
+			code int
+			want string
+		}{
+			{[]string{"--name", "x-dev"}, 2, "import-env takes exactly one file"},
+			{[]string{".env"}, 2, "import-env requires --name"},
+			{[]string{".env", "--name", "x-prod"}, 2, "refusing non-dev secret name: x-prod"},
+			{[]string{".env", "--name", "old-dev"}, 2, "old-dev is already mapped; use push"},
+			{[]string{".env", "--name", "x-dev", "--path", "api"}, 2, `--path must start with '/', got "api"`},
+			{[]string{"../../elsewhere.env", "--name", "x-dev"}, 2, "../../elsewhere.env is outside the project root"},
+			{[]string{"../..", "--name", "x-dev"}, 2, "../.. is outside the project root"},
+			{[]string{"missing.env", "--name", "x-dev"}, 1, "push x-dev: read "},
+			{[]string{".env", "--name", "taken-env-dev"}, 1, "import taken-env-dev: secret already exists"},
+		}
+		for _, tc := range cases {
+			var out, errBuf bytes.Buffer
+			code := Run(append([]string{"dev-vault", "import-env"}, tc.args...), &out, &errBuf, deps)
+			if code != tc.code || !strings.Contains(errBuf.String(), tc.want) {
+				t.Fatalf("%v: expected %d %q, got %d %q", tc.args, tc.code, tc.want, code, errBuf.String())
+			}
+		}
+
+		var out, errBuf bytes.Buffer
+		if code := runImportEnv(commandContext{stdout: &failingWriter{}, stderr: &errBuf, deps: deps}, []string{filepath.Join(mustGetwd(t, deps), ".env"), "--name", "out-env-dev"}); code != 1 {
+			t.Fatalf("expected output error, got %d %q", code, errBuf.String())
+		}
+
+		calls := 0
+		flaky := deps
+		flaky.Getwd = func() (string, error) {
+			calls++
+			if calls > 1 {
+				return "", errors.New("no cwd")
+			}
+			return deps.Getwd()
+		}
+		errBuf.Reset()
+		if code := Run([]string{"dev-vault", "import-env", ".env", "--name", "x-dev"}, &out, &errBuf, flaky); code != 1 || !strings.Contains(errBuf.String(), "getwd: no cwd") {
+			t.Fatalf("expected getwd error, got %d %q", code, errBuf.String())
+		}
+	})
+
+	t.Run("PolicyBlocksBeforeCreating", func(t *testing.T) {
+		_, api, deps := setup(t, `,"policy":{"level":"enforce","forbidden_key_patterns":["^PROD_"]}`)
+		var out, errBuf bytes.Buffer
+		if code := Run([]string{"dev-vault", "import-env", ".env", "--name", "api-env-dev"}, &out, &errBuf, deps); code != 1 || !strings.Contains(errBuf.String(), "push blocked by policy") || len(api.secrets) != 0 {
+			t.Fatalf("expected policy block, got %d %q", code, errBuf.String())
+		}
+	})
+
+	t.Run("ManifestNotUpdated", func(t *testing.T) {
+		// The var only exists in the local overlay, so the shared manifest
+		// cannot be rewritten on its own.
+		root, api, deps := setup(t, "")
+		writeConfig(t, root, `{"organization_id":"org","project_id":"proj","region":"fr-par","mapping":{"{{service}}-dev":{"file":"old.bin"}}}`)
+		if err := os.WriteFile(filepath.Join(root, config.LocalConfigName), []byte(`{"vars":{"service":"old"}}`), 0o600); err != nil {
+			t.Fatalf("write local: %v", err)
+		}
+		var out, errBuf bytes.Buffer
+		if code := Run([]string{"dev-vault", "import-env", ".env", "--name", "api-env-dev"}, &out, &errBuf, deps); code != 1 || len(api.secrets) != 1 ||
+			!strings.Contains(errBuf.String(), "api-env-dev was created (rev=1) but "+filepath.Join(root, config.DefaultConfigName)+` was not updated: mapping "{{service}}-dev": unknown var "service"; add the mapping entry by hand`) {
+			t.Fatalf("expected manifest error, got %d %q", code, errBuf.String())
+		}
+	})
+}
+
+func mustGetwd(t *testing.T, deps Dependencies) string {
+	t.Helper()
+	wd, err := deps.Getwd()
+	if err != nil {
+		t.Fatalf("getwd: %v", err)
+	}
+	return wd
+}
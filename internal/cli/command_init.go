@@ -0,0 +1,259 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/bsmartlabs/dev-vault/internal/config"
+	"github.com/bsmartlabs/dev-vault/internal/configtemplate"
+	"github.com/bsmartlabs/dev-vault/internal/fsx"
+	"github.com/bsmartlabs/dev-vault/internal/i18n"
+	"github.com/bsmartlabs/dev-vault/internal/secretsync"
+)
+
+var initCommandDef = commandDef{
+	Name:    "init",
+	Summary: "Create .scw.json from a template, the project's secrets, or a stub mapping",
+	Flags: []commandFlagDef{
+		{Name: "template", Kind: commandFlagString, ValueName: "<name|path|git-url>", Help: "Template to start from"},
+		{Name: "from-remote", Kind: commandFlagBool, Help: "Map every existing -dev secret of the project instead of using a template"},
+		{Name: "name", Kind: commandFlagString, ValueName: "<project>", Help: "Project name substituted for {{name}}, or naming the stub secret (default: current directory name)"},
+		{Name: "organization-id", Kind: commandFlagString, ValueName: "<id>", Help: "Organization ID (overrides the template)"},
+		{Name: "project-id", Kind: commandFlagString, ValueName: "<id>", Help: "Project ID (overrides the template)"},
+		{Name: "region", Kind: commandFlagString, ValueName: "<region>", Help: "Region (overrides the template)"},
+		{Name: "force", Kind: commandFlagBool, Help: "Overwrite an existing config file"},
+	},
+	Constraints: []flagConstraint{
+		{Flag: "name", Excludes: []string{"from-remote"}, Reason: i18n.MsgReasonFromRemoteNames},
+	},
+	Doc: commandDoc{
+		Synopsis: "dev-vault [--config <path>] init [--template <name|path|git-url> | --from-remote] [options]",
+		Description: []string{
+			"Writes .scw.json (or --config) in the current directory from a template: a partial",
+			"manifest with the standard mapping entries, path conventions, and policy of your organization.",
+			"Every {{name}} in the template is replaced with the project name.",
+			"",
+			"Template references:",
+			"  - a git URL (https://, ssh://, git@, git+<url>, or *.git), optionally ending in #<file>",
+			"    (default file: template.json)",
+			"  - a path to a template file, or to a directory holding template.json",
+			"  - a bare name such as backend-service, read as <name>.json from $DEV_VAULT_TEMPLATES",
+			"    (a directory or git URL)",
+			"",
+			"With --from-remote, the mapping is drafted from the -dev secrets already in the project:",
+			"key_value secrets become dotenv files, the others raw files, and each file is named after",
+			"its secret (app-env-dev becomes app.env). Review the result before the first pull.",
+			"",
+			"With neither, init writes a manifest with one stub mapping, <name>-env-dev to a dotenv",
+			".env file; rename it and add entries, then 'push --create-missing' creates the secrets.",
+			"On a terminal, init asks for the organization, project, and region the flags leave out,",
+			"and offers to map the -dev secrets already in the project instead of the stub.",
+		},
+		Notes: []string{
+			"The result must be a valid manifest: pass --organization-id, --project-id, and --region",
+			"when the template leaves them empty ('dev-vault projects' and 'dev-vault regions' list them).",
+			"--from-remote and the stub always need all three, asked for on a terminal; a name found",
+			"under several paths is mapped once.",
+			"Git templates are fetched with a shallow 'git clone'; the git binary must be on PATH.",
+		},
+		Examples: []string{
+			"dev-vault init --template backend-service --project-id <id> --organization-id <id> --region fr-par",
+			"dev-vault init --template ../templates/worker.json --name billing-worker",
+			"dev-vault init --template https://git.example.com/platform/dev-vault-templates.git#backend.json",
+			"dev-vault init --from-remote --organization-id <id> --project-id <id> --region fr-par",
+			"dev-vault init",
+		},
+	},
+	RunParsed: runInitParsed,
+}
+
+func runInit(ctx commandContext, argv []string) int {
+	return runCommand(ctx, argv, initCommandDef)
+}
+
+func runInitParsed(ctx commandContext, parsed *parsedCommand) int {
+	return newCommandRuntime(ctx, parsed).executeStandalone(func() error {
+		if len(parsed.fs.Args()) > 0 {
+			return usageError(i18n.Errorf(i18n.MsgUnexpectedArguments, parsed.fs.Args()))
+		}
+		ref := parsed.String("template")
+		fromRemote := parsed.Bool("from-remote")
+		if ref != "" && fromRemote {
+			return usageError(i18n.Errorf(i18n.MsgMutuallyExclusive, "--template", "--from-remote"))
+		}
+		wd, err := ctx.deps.Getwd()
+		if err != nil {
+			return runtimeError(i18n.Errorf(i18n.MsgGetwd, err))
+		}
+
+		var cfg config.Config
+		source := "template " + ref
+		switch {
+		case fromRemote:
+			cfg, err = remoteConfig(ctx, parsed, askScope(ctx, parsed))
+			if err != nil {
+				return err
+			}
+			source = "project " + cfg.ProjectID
+		case ref == "":
+			cfg, source, err = stubConfig(ctx, parsed, wd)
+			if err != nil {
+				return err
+			}
+		default:
+			name := parsed.String("name")
+			if name == "" {
+				name = filepath.Base(wd)
+			}
+			cfg, err = configtemplate.Load(ref, configtemplate.Options{
+				Catalog: ctx.deps.Getenv(configtemplate.CatalogEnv),
+				Name:    name,
+				BaseDir: wd,
+			})
+			if err != nil {
+				return runtimeError(err)
+			}
+			if value := parsed.String("organization-id"); value != "" {
+				cfg.OrganizationID = value
+			}
+			if value := parsed.String("project-id"); value != "" {
+				cfg.ProjectID = value
+			}
+			if value := parsed.String("region"); value != "" {
+				cfg.Region = value
+			}
+		}
+		out, err := config.Encode(cfg)
+		if err != nil {
+			return runtimeError(i18n.Errorf(i18n.MsgCommandError, source, err))
+		}
+
+		path := parsed.configPath
+		if path == "" {
+			path = config.DefaultConfigName
+		}
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(wd, path)
+		}
+		if err := fsx.AtomicWriteFile(path, out, 0o644, parsed.Bool("force")); err != nil {
+			if errors.Is(err, fsx.ErrExists) {
+				return runtimeError(i18n.Errorf(i18n.MsgConfigExists, path))
+			}
+			return runtimeError(i18n.Errorf(i18n.MsgWriteFile, path, err))
+		}
+		if _, err := fmt.Fprintf(ctx.stdout, "wrote %s from %s (%d mapping entries)\n", path, source, len(cfg.Mapping)); err != nil {
+			return outputError(err)
+		}
+		return nil
+	})
+}
+
+// askScope returns the organization, project, and region of the flags, and
+// on a terminal asks for the ones they leave out.
+func askScope(ctx commandContext, parsed *parsedCommand) config.Config {
+	cfg := config.Config{
+		OrganizationID: parsed.String("organization-id"),
+		ProjectID:      parsed.String("project-id"),
+		Region:         parsed.String("region"),
+	}
+	if !stdinIsTerminal(ctx) {
+		return cfg
+	}
+	for _, field := range []struct {
+		value    *string
+		question string
+	}{
+		{&cfg.OrganizationID, "Organization ID: "},
+		{&cfg.ProjectID, "Project ID: "},
+		{&cfg.Region, "Region (e.g. fr-par): "},
+	} {
+		if *field.value == "" {
+			*field.value = prompt(ctx, field.question)
+		}
+	}
+	return cfg
+}
+
+func scopeComplete(cfg config.Config) bool {
+	return cfg.OrganizationID != "" && cfg.ProjectID != "" && cfg.Region != ""
+}
+
+// stubConfig drafts a manifest with one stub mapping for the project named
+// after the current directory, or maps the project's -dev secrets when asked
+// to on a terminal.
+func stubConfig(ctx commandContext, parsed *parsedCommand, wd string) (config.Config, string, error) {
+	cfg := askScope(ctx, parsed)
+	if !scopeComplete(cfg) {
+		return config.Config{}, "", usageError(i18n.Errorf(i18n.MsgInitNeedsTemplate))
+	}
+	if stdinIsTerminal(ctx) && confirm(ctx, fmt.Sprintf("Map the -dev secrets already in project %s? [y/N] ", cfg.ProjectID)) {
+		cfg, err := remoteConfig(ctx, parsed, cfg)
+		return cfg, "project " + cfg.ProjectID, err
+	}
+	name := parsed.String("name")
+	if name == "" {
+		name = filepath.Base(wd)
+	}
+	cfg.Mapping = map[string]config.MappingEntry{
+		stubSecretName(name): {File: ".env", Format: config.MappingFormatDotenv},
+	}
+	return cfg, "stub", nil
+}
+
+// stubSecretName derives a -dev secret name from a project name, keeping
+// lowercase letters and digits and joining the rest with dashes.
+func stubSecretName(project string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(project) {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			dash = false
+			continue
+		}
+		dash = true
+	}
+	if b.Len() == 0 {
+		b.WriteString("app")
+	}
+	return b.String() + "-env-dev"
+}
+
+// remoteConfig drafts a manifest from the -dev secrets already in the
+// project of cfg.
+func remoteConfig(ctx commandContext, parsed *parsedCommand, cfg config.Config) (config.Config, error) {
+	if !scopeComplete(cfg) {
+		return config.Config{}, usageError(i18n.Errorf(i18n.MsgFromRemoteNeedsIDs))
+	}
+	if err := requireSingleProfile(parsed.profileOverride, "--from-remote"); err != nil {
+		return config.Config{}, err
+	}
+	api, err := ctx.deps.OpenSecretAPI(cfg, parsed.profileOverride)
+	if err != nil {
+		return config.Config{}, runtimeError(i18n.Errorf(i18n.MsgOpenSecretAPI, err))
+	}
+	mapping, skipped, err := secretsync.New(secretsync.Config{}, api, secretsync.Dependencies{}).ProposeMapping(cfg.ProjectID)
+	if err != nil {
+		return config.Config{}, runtimeError(err)
+	}
+	notes := make([]error, len(skipped))
+	for i, skip := range skipped {
+		notes[i] = i18n.Errorf(i18n.MsgWarnSkippedCopy, skip.Name, skip.Path, skip.MappedPath)
+	}
+	if err := parsed.warnings.warnAll(warningConfig, notes); err != nil {
+		return config.Config{}, outputError(err)
+	}
+	if err := parsed.warnings.strictError(); err != nil {
+		return config.Config{}, err
+	}
+	if len(mapping) == 0 {
+		return config.Config{}, runtimeError(i18n.Errorf(i18n.MsgNoDevSecrets, cfg.ProjectID))
+	}
+	cfg.Mapping = mapping
+	return cfg, nil
+}
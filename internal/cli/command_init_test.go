@@ -0,0 +1,250 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bsmartlabs/dev-vault/internal/config"
+	secret "github.com/scaleway/scaleway-sdk-go/api/secret/v1beta1"
+)
+
+func TestRunInit(t *testing.T) {
+	setup := func(t *testing.T) (string, Dependencies) {
+		t.Helper()
+		wd := filepath.Join(t.TempDir(), "billing")
+		catalog := filepath.Join(t.TempDir(), "templates")
+		if err := os.MkdirAll(wd, 0o700); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := os.MkdirAll(catalog, 0o700); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		tpl := `{"organization_id":"org","project_id":"","region":"fr-par","mapping":{"{{name}}-env-dev":{"file":".env","format":"dotenv"}}}`
+		if err := os.WriteFile(filepath.Join(catalog, "backend-service.json"), []byte(tpl), 0o600); err != nil {
+			t.Fatalf("write template: %v", err)
+		}
+		deps := baseDeps(func(config.Config, string) (SecretAPI, error) { return nil, errors.New("init never opens the API") })
+		deps.Getwd = func() (string, error) { return wd, nil }
+		deps.Getenv = func(key string) string {
+			if key == "DEV_VAULT_TEMPLATES" {
+				return catalog
+			}
+			return ""
+		}
+		return wd, deps
+	}
+
+	t.Run("WritesConfig", func(t *testing.T) {
+		wd, deps := setup(t)
+		var out, errBuf bytes.Buffer
+		code := Run([]string{"dev-vault", "init", "--template", "backend-service", "--project-id", "proj"}, &out, &errBuf, deps)
+		if code != 0 {
+			t.Fatalf("expected 0, got %d stderr=%s", code, errBuf.String())
+		}
+		path := filepath.Join(wd, ".scw.json")
+		if out.String() != "wrote "+path+" from template backend-service (1 mapping entries)\n" {
+			t.Fatalf("unexpected stdout: %q", out.String())
+		}
+		loaded, err := config.Load(wd, "")
+		if err != nil {
+			t.Fatalf("load written config: %v", err)
+		}
+		if loaded.Cfg.ProjectID != "proj" || loaded.Cfg.OrganizationID != "org" {
+			t.Fatalf("unexpected config: %#v", loaded.Cfg)
+		}
+		if _, ok := loaded.Cfg.Mapping["billing-env-dev"]; !ok {
+			t.Fatalf("expected the directory name to fill {{name}}: %#v", loaded.Cfg.Mapping)
+		}
+
+		errBuf.Reset()
+		code = Run([]string{"dev-vault", "init", "--template", "backend-service", "--project-id", "proj"}, &out, &errBuf, deps)
+		if code != 1 || !strings.Contains(errBuf.String(), "config exists (use --force)") {
+			t.Fatalf("expected exists error, got %d stderr=%s", code, errBuf.String())
+		}
+
+		out.Reset()
+		code = Run([]string{"dev-vault", "--config", "custom.json", "init", "--template", "backend-service", "--name", "api",
+			"--organization-id", "org2", "--project-id", "proj2", "--region", "nl-ams", "--force"}, &out, &errBuf, deps)
+		if code != 0 {
+			t.Fatalf("expected 0, got %d stderr=%s", code, errBuf.String())
+		}
+		loaded, err = config.Load(wd, "custom.json")
+		if err != nil || loaded.Cfg.Region != "nl-ams" || loaded.Cfg.OrganizationID != "org2" {
+			t.Fatalf("unexpected config: %#v %v", loaded, err)
+		}
+		if _, ok := loaded.Cfg.Mapping["api-env-dev"]; !ok {
+			t.Fatalf("expected --name to fill {{name}}: %#v", loaded.Cfg.Mapping)
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		wd, deps := setup(t)
+		blocker := filepath.Join(wd, "blocker")
+		if err := os.WriteFile(blocker, []byte("x"), 0o600); err != nil {
+			t.Fatalf("write blocker: %v", err)
+		}
+		cases := []struct {
+			args []string
+			code int
+			want string
+		}{
+			{[]string{"init"}, 2, "init requires --template, or --organization-id, --project-id, and --region"},
+			{[]string{"init", "extra", "--template", "x"}, 2, "unexpected arguments"},
+			{[]string{"init", "--template", "missing-template"}, 1, "template missing-template: read:"},
+			{[]string{"init", "--template", "backend-service"}, 1, "template backend-service: missing required field: project_id"},
+			{[]string{"--config", "blocker/.scw.json", "init", "--template", "backend-service", "--project-id", "p"}, 1, "write " + filepath.Join(blocker, ".scw.json")},
+		}
+		for _, tc := range cases {
+			var out, errBuf bytes.Buffer
+			code := Run(append([]string{"dev-vault"}, tc.args...), &out, &errBuf, deps)
+			if code != tc.code || !strings.Contains(errBuf.String(), tc.want) {
+				t.Fatalf("%v: expected %d %q, got %d %q", tc.args, tc.code, tc.want, code, errBuf.String())
+			}
+		}
+
+		noWd := deps
+		noWd.Getwd = func() (string, error) { return "", errors.New("no cwd") }
+		var errBuf bytes.Buffer
+		if code := runInit(commandContext{stdout: &bytes.Buffer{}, stderr: &errBuf, deps: noWd}, []string{"--template", "x"}); code != 1 || !strings.Contains(errBuf.String(), "getwd: no cwd") {
+			t.Fatalf("expected getwd error, got %d %q", code, errBuf.String())
+		}
+		if code := runInit(commandContext{stdout: &failingWriter{}, stderr: &errBuf, deps: deps}, []string{"--template", "backend-service", "--project-id", "p"}); code != 1 {
+			t.Fatalf("expected output error, got %d", code)
+		}
+	})
+}
+
+func TestRunInit_FromRemote(t *testing.T) {
+	wd := t.TempDir()
+	api := newFakeSecretAPI()
+	api.AddSecret("proj", "app-env-dev", "/", secret.SecretTypeKeyValue)
+	api.AddSecret("proj", "tls-dev", "/certs", secret.SecretTypeCertificate)
+	api.AddSecret("proj", "tls-dev", "/old", secret.SecretTypeCertificate)
+	api.AddSecret("proj", "app-prod", "/", secret.SecretTypeKeyValue)
+	var opened config.Config
+	deps := baseDeps(func(cfg config.Config, _ string) (SecretAPI, error) {
+		opened = cfg
+		return api, nil
+	})
+	deps.Getwd = func() (string, error) { return wd, nil }
+	remote := []string{"dev-vault", "init", "--from-remote", "--organization-id", "org", "--project-id", "proj", "--region", "fr-par"}
+
+	var out, errBuf bytes.Buffer
+	code := Run(remote, &out, &errBuf, deps)
+	path := filepath.Join(wd, config.DefaultConfigName)
+	if code != 0 || out.String() != "wrote "+path+" from project proj (2 mapping entries)\n" || errBuf.String() != "warning: skipped tls-dev at path /old: already mapped from path /certs\n" {
+		t.Fatalf("unexpected init: %d %q %q", code, out.String(), errBuf.String())
+	}
+	if opened.ProjectID != "proj" || opened.Region != "fr-par" {
+		t.Fatalf("unexpected api config: %#v", opened)
+	}
+	loaded, err := config.Load(wd, "")
+	if err != nil {
+		t.Fatalf("load written config: %v", err)
+	}
+	if app, tls := loaded.Cfg.Mapping["app-env-dev"], loaded.Cfg.Mapping["tls-dev"]; len(loaded.Cfg.Mapping) != 2 ||
+		app.File != "app.env" || app.Format != config.MappingFormatDotenv || tls.File != "tls.pem" || tls.Path != "/certs" || tls.Type != "certificate" {
+		t.Fatalf("unexpected mapping: %#v", loaded.Cfg.Mapping)
+	}
+
+	// Strict warnings refuse to write a draft that skipped secrets.
+	errBuf.Reset()
+	if code := Run(append(remote, "--force", "--strict-warnings"), &out, &errBuf, deps); code != 1 || !strings.Contains(errBuf.String(), "treated as errors") {
+		t.Fatalf("expected strict failure, got %d %q", code, errBuf.String())
+	}
+
+	empty := baseDeps(func(config.Config, string) (SecretAPI, error) { return newFakeSecretAPI(), nil })
+	empty.Getwd = deps.Getwd
+	failing := newFakeSecretAPI()
+	failing.listErr = errors.New("boom")
+	listFails := baseDeps(func(config.Config, string) (SecretAPI, error) { return failing, nil })
+	listFails.Getwd = deps.Getwd
+	openFails := baseDeps(func(config.Config, string) (SecretAPI, error) { return nil, errors.New("no credentials") })
+	openFails.Getwd = deps.Getwd
+	cases := []struct {
+		args []string
+		deps Dependencies
+		code int
+		want string
+	}{
+		{[]string{"--template", "x"}, deps, 2, "--template and --from-remote are mutually exclusive"},
+		{[]string{"--name", "x"}, deps, 2, "--name cannot be combined with --from-remote"},
+		{[]string{"--profile", "a,b"}, deps, 2, "--from-remote takes a single --profile"},
+		{nil, openFails, 1, "open secret api: no credentials"},
+		{nil, listFails, 1, "list secrets: boom"},
+		{nil, empty, 1, "no -dev secrets found in project proj"},
+	}
+	for _, tc := range cases {
+		errBuf.Reset()
+		if code := Run(append(append([]string{}, remote...), tc.args...), &out, &errBuf, tc.deps); code != tc.code || !strings.Contains(errBuf.String(), tc.want) {
+			t.Fatalf("%v: expected %d %q, got %d %q", tc.args, tc.code, tc.want, code, errBuf.String())
+		}
+	}
+	errBuf.Reset()
+	if code := Run([]string{"dev-vault", "init", "--from-remote", "--project-id", "proj"}, &out, &errBuf, deps); code != 2 || !strings.Contains(errBuf.String(), "--from-remote requires --organization-id, --project-id, and --region") {
+		t.Fatalf("expected missing flags error, got %d %q", code, errBuf.String())
+	}
+	if code := runInit(commandContext{stdout: &out, stderr: &failingWriter{}, deps: deps}, append(remote[2:], "--force")); code != 1 {
+		t.Fatalf("expected warning write failure, got %d", code)
+	}
+}
+
+func TestRunInit_Stub(t *testing.T) {
+	wd := filepath.Join(t.TempDir(), "Billing Worker")
+	if err := os.Mkdir(wd, 0o700); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	api := newFakeSecretAPI()
+	api.AddSecret("proj", "app-env-dev", "/", secret.SecretTypeKeyValue)
+	deps := baseDeps(func(config.Config, string) (SecretAPI, error) { return api, nil })
+	deps.Getwd = func() (string, error) { return wd, nil }
+	path := filepath.Join(wd, config.DefaultConfigName)
+	run := func(stdin string, args ...string) (int, string, string) {
+		deps.Stdin = strings.NewReader(stdin)
+		var out, errBuf bytes.Buffer
+		code := Run(append([]string{"dev-vault", "init", "--force"}, args...), &out, &errBuf, deps)
+		return code, out.String(), errBuf.String()
+	}
+
+	// Without a terminal the flags must name the project; nothing is asked.
+	code, out, errOut := run("", "--organization-id", "org", "--project-id", "proj", "--region", "fr-par")
+	if code != 0 || out != "wrote "+path+" from stub (1 mapping entries)\n" || errOut != "" {
+		t.Fatalf("unexpected init: %d %q %q", code, out, errOut)
+	}
+	loaded, err := config.Load(wd, "")
+	if err != nil {
+		t.Fatalf("load written config: %v", err)
+	}
+	if entry, ok := loaded.Cfg.Mapping["billing-worker-env-dev"]; !ok || entry.File != ".env" || entry.Format != config.MappingFormatDotenv {
+		t.Fatalf("unexpected stub mapping: %#v", loaded.Cfg.Mapping)
+	}
+
+	// On a terminal the missing fields are asked, then whether to map the
+	// project's secrets instead of the stub.
+	deps.StdinIsTerminal = func() bool { return true }
+	code, out, errOut = run("org\nproj\nn\n", "--region", "fr-par", "--name", "__")
+	if code != 0 || !strings.Contains(out, "from stub") || errOut != "Organization ID: Project ID: Map the -dev secrets already in project proj? [y/N] " {
+		t.Fatalf("unexpected interactive init: %d %q %q", code, out, errOut)
+	}
+	if loaded, _ := config.Load(wd, ""); loaded.Cfg.ProjectID != "proj" || loaded.Cfg.Mapping["app-env-dev"].File != ".env" {
+		t.Fatalf("expected the answers and a fallback stub name, got %#v", loaded.Cfg)
+	}
+	code, out, errOut = run("org\nproj\nfr-par\ny\n")
+	if code != 0 || out != "wrote "+path+" from project proj (1 mapping entries)\n" || !strings.Contains(errOut, "Region (e.g. fr-par): ") {
+		t.Fatalf("unexpected interactive init: %d %q %q", code, out, errOut)
+	}
+	if code, out, _ := run("proj\nfr-par\n", "--from-remote", "--organization-id", "org"); code != 0 || !strings.Contains(out, "from project proj") {
+		t.Fatalf("expected --from-remote to ask for the project, got %d %q", code, out)
+	}
+	if code, _, errOut := run("org\n\n"); code != 2 || !strings.Contains(errOut, "init requires --template") {
+		t.Fatalf("expected an unanswered project to fail, got %d %q", code, errOut)
+	}
+	api.listErr = errors.New("boom")
+	if code, _, errOut := run("org\nproj\nfr-par\ny\n"); code != 1 || !strings.Contains(errOut, "list secrets: boom") {
+		t.Fatalf("expected the list error, got %d %q", code, errOut)
+	}
+}
@@ -0,0 +1,113 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/bsmartlabs/dev-vault/internal/config"
+	"github.com/bsmartlabs/dev-vault/internal/i18n"
+	"github.com/bsmartlabs/dev-vault/internal/naming"
+	"github.com/bsmartlabs/dev-vault/internal/secretsync"
+)
+
+var lintNamesCommandDef = commandDef{
+	Name:    "lint-names",
+	Summary: "Check mapped and remote secret names against the naming convention",
+	Flags: []commandFlagDef{
+		{Name: "json", Kind: commandFlagBool, Help: "Output JSON"},
+	},
+	Doc: commandDoc{
+		Synopsis: "dev-vault [--config <path>] [--profile <name>] lint-names [--json]",
+		Description: []string{
+			"Checks every mapping key and every remote -dev secret of the project against",
+			"the naming section of .scw.json and lists each deviation.",
+			"",
+			"Naming rules:",
+			"  - kebab_case: lowercase letters and digits in words separated by single dashes.",
+			"  - required_suffix: a suffix every name must end with, such as -env-dev.",
+			"  - patterns: regexps; a name must match at least one.",
+		},
+		Notes: []string{
+			"Without a naming section, names are only checked for kebab-case.",
+			"Exits with code 1 when any deviation is found, so it can gate CI.",
+		},
+		Examples: []string{
+			"dev-vault lint-names",
+			"dev-vault lint-names --json",
+		},
+	},
+	RunParsed: runLintNamesParsed,
+}
+
+type nameDeviation struct {
+	Name     string   `json:"name"`
+	Sources  []string `json:"sources"`
+	Problems []string `json:"problems"`
+}
+
+func runLintNames(ctx commandContext, argv []string) int {
+	return runCommand(ctx, argv, lintNamesCommandDef)
+}
+
+func runLintNamesParsed(ctx commandContext, parsed *parsedCommand) int {
+	return newCommandRuntime(ctx, parsed).execute(func(loaded *config.Loaded, service secretsync.Service) error {
+		deviations, checked, err := lintNames(loaded.Cfg, service)
+		if err != nil {
+			return runtimeError(err)
+		}
+
+		if parsed.Bool("json") {
+			enc := json.NewEncoder(ctx.stdout)
+			enc.SetIndent("", "  ")
+			if err := enc.Encode(deviations); err != nil {
+				return outputError(err)
+			}
+		} else if len(deviations) == 0 {
+			if _, err := fmt.Fprintf(ctx.stdout, "%d names checked, no deviations\n", checked); err != nil {
+				return outputError(err)
+			}
+		} else {
+			table := newTable(ctx.stdout, parsed.plain, "NAME", "SOURCE", "PROBLEMS")
+			for _, d := range deviations {
+				table.row(d.Name, strings.Join(d.Sources, ","), strings.Join(d.Problems, "; "))
+			}
+			if err := table.flush(); err != nil {
+				return outputError(err)
+			}
+		}
+		if len(deviations) > 0 {
+			return runtimeError(i18n.Errorf(i18n.MsgNamingDeviations, len(deviations)))
+		}
+		return nil
+	})
+}
+
+// lintNames checks each distinct mapped or remote name once and returns the
+// deviations sorted by name, plus the number of names checked.
+func lintNames(cfg config.Config, service secretsync.Service) ([]nameDeviation, int, error) {
+	rules, err := naming.Compile(cfg.Naming)
+	if err != nil {
+		return nil, 0, err
+	}
+	remote, err := service.List(secretsync.ListQuery{ProjectID: cfg.ProjectID})
+	if err != nil {
+		return nil, 0, err
+	}
+	sources := make(map[string][]string, len(cfg.Mapping)+len(remote))
+	for name := range cfg.Mapping {
+		sources[name] = append(sources[name], "mapping")
+	}
+	for _, record := range remote {
+		sources[record.Name] = append(sources[record.Name], "remote")
+	}
+	deviations := make([]nameDeviation, 0)
+	for name, from := range sources {
+		if problems := rules.Check(name); len(problems) > 0 {
+			deviations = append(deviations, nameDeviation{Name: name, Sources: from, Problems: problems})
+		}
+	}
+	sort.Slice(deviations, func(i, j int) bool { return deviations[i].Name < deviations[j].Name })
+	return deviations, len(sources), nil
+}
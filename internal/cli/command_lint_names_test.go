@@ -0,0 +1,98 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/bsmartlabs/dev-vault/internal/config"
+	"github.com/bsmartlabs/dev-vault/internal/secretsync"
+	secret "github.com/scaleway/scaleway-sdk-go/api/secret/v1beta1"
+)
+
+func TestRunLintNames(t *testing.T) {
+	setup := func(t *testing.T, naming string) (string, *fakeSecretAPI, Dependencies) {
+		t.Helper()
+		cfgPath := writeConfig(t, t.TempDir(), `{"organization_id":"org","project_id":"proj","region":"fr-par","mapping":{
+			"svc-api-env-dev":{"file":"a.env"},
+			"Legacy_App-dev":{"file":"b.env"}
+		}`+naming+`}`)
+		api := newFakeSecretAPI()
+		api.AddSecret("proj", "svc-api-env-dev", "/", secret.SecretTypeOpaque)
+		api.AddSecret("proj", "worker-dev", "/", secret.SecretTypeOpaque)
+		api.AddSecret("proj", "prod-secret", "/", secret.SecretTypeOpaque)
+		deps := baseDeps(func(cfg config.Config, s string) (SecretAPI, error) { return api, nil })
+		return cfgPath, api, deps
+	}
+
+	t.Run("DefaultConvention", func(t *testing.T) {
+		cfgPath, _, deps := setup(t, "")
+		var out, errBuf bytes.Buffer
+		code := Run([]string{"dev-vault", "--config", cfgPath, "--plain", "lint-names"}, &out, &errBuf, deps)
+		if code != 1 || !strings.Contains(errBuf.String(), "1 naming deviation(s)") {
+			t.Fatalf("expected one deviation, got %d stderr=%s", code, errBuf.String())
+		}
+		if out.String() != "NAME\tSOURCE\tPROBLEMS\nLegacy_App-dev\tmapping\tnot kebab-case\n" {
+			t.Fatalf("unexpected stdout: %q", out.String())
+		}
+	})
+
+	t.Run("ConfiguredConventionJSON", func(t *testing.T) {
+		cfgPath, _, deps := setup(t, `,"naming":{"patterns":["^svc-"],"required_suffix":"-env-dev"}`)
+		var out, errBuf bytes.Buffer
+		code := Run([]string{"dev-vault", "--config", cfgPath, "lint-names", "--json"}, &out, &errBuf, deps)
+		if code != 1 {
+			t.Fatalf("expected 1, got %d stderr=%s", code, errBuf.String())
+		}
+		var got []nameDeviation
+		if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+			t.Fatalf("decode: %v (%s)", err, out.String())
+		}
+		if len(got) != 2 || got[0].Name != "Legacy_App-dev" || got[1].Name != "worker-dev" || got[1].Sources[0] != "remote" ||
+			strings.Join(got[1].Problems, "; ") != `missing suffix "-env-dev"; matches no naming pattern` {
+			t.Fatalf("unexpected deviations: %#v", got)
+		}
+		if strings.Contains(out.String(), "prod-secret") {
+			t.Fatalf("non-dev secrets must not be listed: %s", out.String())
+		}
+	})
+
+	t.Run("Clean", func(t *testing.T) {
+		cfgPath, api, deps := setup(t, `,"naming":{"patterns":["."]}`)
+		var out, errBuf bytes.Buffer
+		code := Run([]string{"dev-vault", "--config", cfgPath, "lint-names"}, &out, &errBuf, deps)
+		if code != 0 || out.String() != "3 names checked, no deviations\n" {
+			t.Fatalf("unexpected result: %d stdout=%q stderr=%s", code, out.String(), errBuf.String())
+		}
+
+		api.listErr = errors.New("boom")
+		code = Run([]string{"dev-vault", "--config", cfgPath, "lint-names"}, &out, &errBuf, deps)
+		if code != 1 || !strings.Contains(errBuf.String(), "list secrets: boom") {
+			t.Fatalf("expected list error, got %d stderr=%s", code, errBuf.String())
+		}
+	})
+
+	t.Run("OutputErrors", func(t *testing.T) {
+		for _, args := range [][]string{{}, {"--json"}, {"--plain"}} {
+			naming := ""
+			if len(args) == 0 {
+				naming = `,"naming":{"patterns":["."]}`
+			}
+			cfgPath, _, deps := setup(t, naming)
+			var errBuf bytes.Buffer
+			code := runLintNames(commandContext{stdout: &failingWriter{}, stderr: &errBuf, configPath: cfgPath, deps: deps}, args)
+			if code != 1 || strings.Contains(errBuf.String(), "deviation") {
+				t.Fatalf("%v: expected output error, got %d %q", args, code, errBuf.String())
+			}
+		}
+	})
+}
+
+func TestLintNames_CompileError(t *testing.T) {
+	cfg := config.Config{Naming: &config.Naming{Patterns: []string{"("}}}
+	if _, _, err := lintNames(cfg, secretsync.Service{}); err == nil || !strings.Contains(err.Error(), "naming patterns") {
+		t.Fatalf("expected compile error, got %v", err)
+	}
+}
@@ -4,10 +4,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"regexp"
+	"sort"
 	"strings"
-	"text/tabwriter"
+	"time"
 
 	"github.com/bsmartlabs/dev-vault/internal/config"
+	"github.com/bsmartlabs/dev-vault/internal/i18n"
 	"github.com/bsmartlabs/dev-vault/internal/secretprovider"
 	"github.com/bsmartlabs/dev-vault/internal/secretsync"
 	"github.com/bsmartlabs/dev-vault/internal/secrettype"
@@ -17,24 +19,51 @@ var listCommandDef = commandDef{
 	Name:    "list",
 	Summary: "List mapped -dev secrets metadata",
 	Flags: []commandFlagDef{
-		{Name: "json", Kind: commandFlagBool, Help: "Output JSON"},
+		{Name: "all-projects", Kind: commandFlagBool, Help: "List across every project of the organization (adds a PROJECT column)"},
+		{Name: "columns", Kind: commandFlagString, ValueName: "<list>", Help: "Comma-separated columns: " + strings.Join(inventoryColumnNames(), ",")},
+		{Name: "json", Kind: commandFlagBool, Help: "Output JSON (same as --output json)"},
 		{Name: "name-contains", Kind: commandFlagStringSlice, ValueName: "<substring>", Help: "Substring filter (repeatable, AND semantics)"},
 		{Name: "name-regex", Kind: commandFlagString, ValueName: "<regexp>", Help: "Go regexp to match secret names"},
+		{Name: "older-than", Kind: commandFlagString, ValueName: "<age>", Help: "Staleness threshold for --stale, e.g. 14d or 36h (default 14d)"},
+		{Name: "output", Kind: commandFlagString, ValueName: "<table|json|csv>", Help: "Output format (default table)"},
 		{Name: "path", Kind: commandFlagString, ValueName: "<path>", Help: "Exact Scaleway secret path to filter"},
+		{Name: "stale", Kind: commandFlagBool, Help: "List pull-eligible mapping entries whose local file is missing, expired, or older than --older-than"},
 		{Name: "type", Kind: commandFlagString, ValueName: "<type>", Help: fmt.Sprintf("One of: %s", strings.Join(secrettype.Names(), "|"))},
 	},
+	Constraints: []flagConstraint{
+		{Flag: "older-than", Requires: []string{"stale"}, Reason: i18n.MsgReasonOlderThanStale},
+		{Flag: "stale", Excludes: []string{"all-projects", "name-contains", "name-regex", "path", "type"}, Reason: i18n.MsgReasonStaleLocal},
+	},
 	Doc: commandDoc{
 		Synopsis: "dev-vault [--config <path>] [--profile <name>] list [options]",
 		Description: []string{
 			"Lists secrets in the configured Scaleway project/region.",
 			"This command always filters to secret names ending with '-dev'.",
 			"It never prints secret payloads, only metadata (name/type/path/id).",
+			"",
+			"With --stale, lists mapping entries instead of remote secrets and never contacts Scaleway:",
+			"an entry is stale when its local file is missing or was last pulled before --older-than, or",
+			"when its expire_after (see pull) has passed. Pull times are recorded in the user state dir;",
+			"a file without one, such as one pulled by an older release, is judged by its modification time.",
+			"",
+			"With --all-projects, every project of organization_id is listed concurrently with the same filters.",
+			"",
+			"With --profile a,b, every profile is listed concurrently and a PROFILE column is added.",
+			"The first profile uses the manifest's project; the others use their own default_project_id.",
+			"",
+			"--columns picks the metadata to show, for inventory extracts; with --output json each",
+			"secret becomes an object keyed by column name. age counts days since the secret was last",
+			"updated, and owner reads an owner:<team> or owner=<team> tag. Payloads are never read.",
 		},
 		Examples: []string{
 			"dev-vault list",
 			"dev-vault list --json",
 			"dev-vault list --name-contains bweb --name-contains env",
 			"dev-vault list --name-regex '^bweb-env-.*-dev$' --path / --type key_value",
+			"dev-vault list --stale --older-than 14d",
+			"dev-vault list --all-projects --name-contains env",
+			"dev-vault list --all-projects --output csv --columns name,project,path,type,age,owner",
+			"dev-vault --profile acme,client-x list",
 		},
 	},
 	RunParsed: runListParsed,
@@ -45,7 +74,19 @@ func runList(ctx commandContext, argv []string) int {
 }
 
 func runListParsed(ctx commandContext, parsed *parsedCommand) int {
-	return newCommandRuntime(ctx, parsed).execute(func(_ *config.Loaded, service secretsync.Service) error {
+	if parsed.Bool("stale") {
+		return runListStale(ctx, parsed)
+	}
+	return newCommandRuntime(ctx, parsed).execute(func(loaded *config.Loaded, service secretsync.Service) error {
+		output, err := listOutput(parsed)
+		if err != nil {
+			return err
+		}
+		columns, err := parseInventoryColumns(parsed.String("columns"))
+		if err != nil {
+			return err
+		}
+
 		var re *regexp.Regexp
 		var selectedType secretprovider.SecretType
 
@@ -53,7 +94,7 @@ func runListParsed(ctx commandContext, parsed *parsedCommand) int {
 		if nameRegex != "" {
 			compiled, err := regexp.Compile(nameRegex)
 			if err != nil {
-				return usageError(fmt.Errorf("invalid --name-regex: %w", err))
+				return usageError(i18n.Errorf(i18n.MsgInvalidFlag, "name-regex", err))
 			}
 			re = compiled
 		}
@@ -62,22 +103,30 @@ func runListParsed(ctx commandContext, parsed *parsedCommand) int {
 		if typeFilter != "" {
 			parsedType, err := secretsync.ParseSecretType(typeFilter)
 			if err != nil {
-				return usageError(fmt.Errorf("invalid --type: %w", err))
+				return usageError(i18n.Errorf(i18n.MsgInvalidFlag, "type", err))
 			}
 			selectedType = parsedType
 		}
 
-		filtered, err := service.List(secretsync.ListQuery{
+		query := secretsync.ListQuery{
 			NameContains: parsed.Strings("name-contains"),
 			NameRegex:    re,
 			Path:         parsed.String("path"),
 			Type:         selectedType,
-		})
+		}
+		if parsed.Bool("all-projects") {
+			return listAllProjects(ctx, parsed, loaded, service, query, output, columns)
+		}
+
+		filtered, err := service.List(query)
 		if err != nil {
 			return err
 		}
+		if ctx.deps.Getenv(completionRemoteEnvVar) == "1" && len(query.NameContains) == 0 && re == nil && query.Path == "" && selectedType == "" {
+			saveCompletionNames(ctx.deps, loaded, parsed.profileOverride, filtered)
+		}
 
-		if parsed.Bool("json") {
+		if output == listOutputJSON && columns == nil {
 			enc := json.NewEncoder(ctx.stdout)
 			enc.SetIndent("", "  ")
 			if err := enc.Encode(filtered); err != nil {
@@ -86,12 +135,148 @@ func runListParsed(ctx commandContext, parsed *parsedCommand) int {
 			return nil
 		}
 
-		tw := tabwriter.NewWriter(ctx.stdout, 0, 0, 2, ' ', 0)
-		_, _ = fmt.Fprintln(tw, "NAME\tTYPE\tPATH\tID")
-		for _, it := range filtered {
-			_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", it.Name, it.Type, it.Path, it.ID)
+		if columns == nil {
+			columns = []string{"name", "type", "path", "id"}
+			if len(splitProfiles(parsed.profileOverride)) > 1 {
+				columns = append([]string{"profile"}, columns...)
+			}
+		}
+		rows := make([]inventoryRow, 0, len(filtered))
+		for _, record := range filtered {
+			project := record.ProjectID
+			if project == "" {
+				project = loaded.Cfg.ProjectID
+			}
+			rows = append(rows, inventoryRow{Project: project, Record: record})
+		}
+		return writeInventory(ctx, parsed, output, columns, rows)
+	})
+}
+
+type projectListRecord struct {
+	Project string `json:"project"`
+	secretsync.ListRecord
+}
+
+func listAllProjects(ctx commandContext, parsed *parsedCommand, loaded *config.Loaded, service secretsync.Service, query secretsync.ListQuery, output string, columns []string) error {
+	if err := requireSingleProfile(parsed.profileOverride, "--all-projects"); err != nil {
+		return err
+	}
+	accountAPI, err := ctx.deps.OpenAccountAPI(parsed.profileOverride)
+	if err != nil {
+		return runtimeError(i18n.Errorf(i18n.MsgOpenAccountAPI, err))
+	}
+	projects, err := accountAPI.ListProjects(secretprovider.ListProjectsInput{OrganizationID: loaded.Cfg.OrganizationID})
+	if err != nil {
+		return err
+	}
+	sort.Slice(projects, func(i, j int) bool {
+		if projects[i].Name != projects[j].Name {
+			return projects[i].Name < projects[j].Name
+		}
+		return projects[i].ID < projects[j].ID
+	})
+	projectIDs := make([]string, 0, len(projects))
+	projectNames := make(map[string]string, len(projects))
+	for _, project := range projects {
+		projectIDs = append(projectIDs, project.ID)
+		projectNames[project.ID] = project.Name
+	}
+
+	listed, err := service.ListProjects(projectIDs, query)
+	if err != nil {
+		return err
+	}
+	if output == listOutputJSON && columns == nil {
+		records := make([]projectListRecord, 0, len(listed))
+		for _, record := range listed {
+			records = append(records, projectListRecord{Project: projectNames[record.ProjectID], ListRecord: record})
+		}
+		enc := json.NewEncoder(ctx.stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(records); err != nil {
+			return outputError(err)
+		}
+		return nil
+	}
+
+	if columns == nil {
+		columns = []string{"project", "name", "type", "path", "id"}
+	}
+	rows := make([]inventoryRow, 0, len(listed))
+	for _, record := range listed {
+		rows = append(rows, inventoryRow{Project: projectNames[record.ProjectID], Record: record})
+	}
+	return writeInventory(ctx, parsed, output, columns, rows)
+}
+
+const defaultStaleAge = 14 * 24 * time.Hour
+
+type staleRecord struct {
+	Name       string  `json:"name"`
+	File       string  `json:"file"`
+	LastPulled *string `json:"last_pulled"`
+	AgeDays    *int    `json:"age_days"`
+	Expired    bool    `json:"expired"`
+}
+
+func runListStale(ctx commandContext, parsed *parsedCommand) int {
+	return newCommandRuntime(ctx, parsed).executeLocal(func(loaded *config.Loaded, service secretsync.Service) error {
+		output, err := listOutput(parsed)
+		if err != nil {
+			return err
+		}
+		if output == listOutputCSV || parsed.String("columns") != "" {
+			return usageError(i18n.Errorf(i18n.MsgStaleOutput))
+		}
+		olderThan := defaultStaleAge
+		if value := parsed.String("older-than"); value != "" {
+			age, err := config.ParseAge(value)
+			if err != nil {
+				return usageError(i18n.Errorf(i18n.MsgInvalidFlag, "older-than", err))
+			}
+			olderThan = age
+		}
+
+		now := ctx.deps.Now()
+		targets := mappingTargetsForMode(loaded.Cfg.Mapping, commandModePull)
+		records := make([]staleRecord, 0, len(targets))
+		// Without a readable record, files are only stale by the age of
+		// their modification time.
+		_, pulls, _ := loadExpiry(ctx.deps, loaded)
+		for _, file := range service.StaleFiles(targets, olderThan, pulls.Expires, pulls.Pulled) {
+			record := staleRecord{Name: file.Name, File: file.File, Expired: file.Expired}
+			if file.Present {
+				lastPulled := file.LastPull.UTC().Format(time.RFC3339)
+				ageDays := int(now.Sub(file.LastPull).Hours() / 24)
+				record.LastPulled = &lastPulled
+				record.AgeDays = &ageDays
+			}
+			records = append(records, record)
+		}
+
+		if output == listOutputJSON {
+			enc := json.NewEncoder(ctx.stdout)
+			enc.SetIndent("", "  ")
+			if err := enc.Encode(records); err != nil {
+				return outputError(err)
+			}
+			return nil
+		}
+
+		tbl := newTable(ctx.stdout, parsed.plain, "NAME", "FILE", "LAST_PULLED", "AGE")
+		for _, record := range records {
+			lastPulled, age := "never", "-"
+			if record.LastPulled != nil {
+				lastPulled = *record.LastPulled
+				age = fmt.Sprintf("%dd", *record.AgeDays)
+			}
+			if record.Expired {
+				age += " (expired)"
+			}
+			tbl.row(record.Name, record.File, lastPulled, age)
 		}
-		if err := tw.Flush(); err != nil {
+		if err := tbl.flush(); err != nil {
 			return outputError(err)
 		}
 		return nil
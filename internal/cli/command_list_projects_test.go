@@ -0,0 +1,91 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/bsmartlabs/dev-vault/internal/config"
+	"github.com/bsmartlabs/dev-vault/internal/secretprovider"
+	secret "github.com/scaleway/scaleway-sdk-go/api/secret/v1beta1"
+)
+
+func TestRunList_AllProjects(t *testing.T) {
+	root := t.TempDir()
+	cfgPath := writeConfig(t, root, `{"organization_id":"org","project_id":"p1","region":"fr-par","mapping":{"x-dev":{"file":"x"}}}`)
+	api := newFakeSecretAPI()
+	api.AddSecret("p1", "api-env-dev", "/", secret.SecretTypeKeyValue)
+	api.AddSecret("p2", "web-env-dev", "/", secret.SecretTypeKeyValue)
+	api.AddSecret("p2", "web-cert-dev", "/", secret.SecretTypeCertificate)
+	account := &fakeAccountAPI{projects: []secretprovider.ProjectRecord{
+		{ID: "p2", Name: "web", OrganizationID: "org"},
+		{ID: "p1", Name: "api", OrganizationID: "org"},
+		{ID: "p3", Name: "api", OrganizationID: "org"},
+	}}
+	deps := accountDeps(account, nil)
+	deps.OpenSecretAPI = func(cfg config.Config, s string) (SecretAPI, error) { return api, nil }
+
+	t.Run("Table", func(t *testing.T) {
+		var out, errBuf bytes.Buffer
+		code := Run([]string{"dev-vault", "--config", cfgPath, "list", "--all-projects", "--name-contains", "env"}, &out, &errBuf, deps)
+		if code != 0 {
+			t.Fatalf("expected 0, got %d stderr=%s", code, errBuf.String())
+		}
+		lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+		if len(lines) != 3 || !strings.HasPrefix(lines[0], "PROJECT") || !strings.HasPrefix(lines[1], "api ") || !strings.HasPrefix(lines[2], "web ") {
+			t.Fatalf("unexpected output: %q", out.String())
+		}
+		if account.lastOrg != "org" {
+			t.Fatalf("expected organization from config, got %q", account.lastOrg)
+		}
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		var out, errBuf bytes.Buffer
+		code := Run([]string{"dev-vault", "--config", cfgPath, "list", "--all-projects", "--json"}, &out, &errBuf, deps)
+		if code != 0 {
+			t.Fatalf("expected 0, got %d stderr=%s", code, errBuf.String())
+		}
+		var records []map[string]string
+		if err := json.Unmarshal(out.Bytes(), &records); err != nil {
+			t.Fatalf("unmarshal: %v", err)
+		}
+		if len(records) != 3 || records[0]["project"] != "api" || records[0]["project_id"] != "p1" || records[2]["name"] != "web-env-dev" {
+			t.Fatalf("unexpected records: %#v", records)
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		var out, errBuf bytes.Buffer
+		openFail := deps
+		openFail.OpenAccountAPI = func(string) (AccountAPI, error) { return nil, errors.New("no creds") }
+		if code := Run([]string{"dev-vault", "--config", cfgPath, "list", "--all-projects"}, &out, &errBuf, openFail); code != 1 {
+			t.Fatalf("expected open error exit 1, got %d", code)
+		}
+
+		account.listErr = errors.New("denied")
+		if code := Run([]string{"dev-vault", "--config", cfgPath, "list", "--all-projects"}, &out, &errBuf, deps); code != 1 {
+			t.Fatalf("expected project list error exit 1, got %d", code)
+		}
+		account.listErr = nil
+
+		api.listErr = errors.New("boom")
+		if code := Run([]string{"dev-vault", "--config", cfgPath, "list", "--all-projects"}, &out, &errBuf, deps); code != 1 {
+			t.Fatalf("expected secret list error exit 1, got %d", code)
+		}
+		api.listErr = nil
+
+		if code := Run([]string{"dev-vault", "--config", cfgPath, "list", "--all-projects", "--stale"}, &out, &errBuf, deps); code != 2 {
+			t.Fatalf("expected usage error, got %d", code)
+		}
+
+		for _, args := range [][]string{{"--all-projects"}, {"--all-projects", "--json"}} {
+			code := runList(commandContext{stdout: &failingWriter{}, stderr: &errBuf, configPath: cfgPath, deps: deps}, args)
+			if code != 1 {
+				t.Fatalf("%v: expected write failure exit 1, got %d", args, code)
+			}
+		}
+	})
+}
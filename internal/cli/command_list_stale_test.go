@@ -0,0 +1,142 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bsmartlabs/dev-vault/internal/config"
+	secret "github.com/scaleway/scaleway-sdk-go/api/secret/v1beta1"
+)
+
+func TestRunList_Stale(t *testing.T) {
+	root := t.TempDir()
+	cfgPath := writeConfig(t, root, `{"organization_id":"org","project_id":"proj","region":"fr-par","mapping":{
+		"fresh-dev":{"file":"fresh.env"},
+		"old-dev":{"file":"old.env","mode":"pull"},
+		"missing-dev":{"file":"missing.env"},
+		"pushonly-dev":{"file":"push.env","mode":"push"}
+	}}`)
+	now := time.Unix(1_000_000, 0)
+	for name, age := range map[string]time.Duration{"fresh.env": time.Hour, "old.env": 20 * 24 * time.Hour} {
+		path := filepath.Join(root, name)
+		if err := os.WriteFile(path, []byte("A=1\n"), 0o600); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+		if err := os.Chtimes(path, now, now.Add(-age)); err != nil {
+			t.Fatalf("chtimes %s: %v", name, err)
+		}
+	}
+	opened := false
+	deps := baseDeps(func(cfg config.Config, s string) (SecretAPI, error) {
+		opened = true
+		return newFakeSecretAPI(), nil
+	})
+	deps.Now = func() time.Time { return now }
+
+	t.Run("TableDefaultAge", func(t *testing.T) {
+		var out, errBuf bytes.Buffer
+		code := Run([]string{"dev-vault", "--config", cfgPath, "list", "--stale"}, &out, &errBuf, deps)
+		if code != 0 {
+			t.Fatalf("expected 0, got %d stderr=%s", code, errBuf.String())
+		}
+		lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+		if len(lines) != 3 || !strings.HasPrefix(lines[1], "missing-dev") || !strings.Contains(lines[1], "never") || !strings.HasPrefix(lines[2], "old-dev") || !strings.HasSuffix(lines[2], "20d") {
+			t.Fatalf("unexpected stale table: %q", out.String())
+		}
+	})
+
+	t.Run("JSONCustomAge", func(t *testing.T) {
+		var out, errBuf bytes.Buffer
+		code := Run([]string{"dev-vault", "--config", cfgPath, "list", "--stale", "--older-than", "30m", "--json"}, &out, &errBuf, deps)
+		if code != 0 {
+			t.Fatalf("expected 0, got %d stderr=%s", code, errBuf.String())
+		}
+		var records []staleRecord
+		if err := json.Unmarshal(out.Bytes(), &records); err != nil {
+			t.Fatalf("unmarshal: %v", err)
+		}
+		if len(records) != 3 || records[0].Name != "fresh-dev" || records[0].AgeDays == nil || *records[0].AgeDays != 0 || records[1].LastPulled != nil {
+			t.Fatalf("unexpected records: %#v", records)
+		}
+	})
+
+	if opened {
+		t.Fatal("list --stale must not open the secret API")
+	}
+
+	t.Run("UsageErrors", func(t *testing.T) {
+		for _, args := range [][]string{
+			{"list", "--stale", "--older-than", "soon"},
+			{"list", "--stale", "--type", "opaque"},
+			{"list", "--older-than", "1d"},
+		} {
+			var out, errBuf bytes.Buffer
+			code := Run(append([]string{"dev-vault", "--config", cfgPath}, args...), &out, &errBuf, deps)
+			if code != 2 {
+				t.Fatalf("%v: expected 2, got %d stderr=%s", args, code, errBuf.String())
+			}
+		}
+	})
+
+	t.Run("WriteFailures", func(t *testing.T) {
+		for _, args := range [][]string{{"--stale"}, {"--stale", "--json"}} {
+			var errBuf bytes.Buffer
+			code := runList(commandContext{
+				stdout:     &failingWriter{},
+				stderr:     &errBuf,
+				configPath: cfgPath,
+				deps:       deps,
+			}, args)
+			if code != 1 {
+				t.Fatalf("%v: expected 1, got %d", args, code)
+			}
+		}
+	})
+}
+
+func TestRunList_StaleRemoteMtime(t *testing.T) {
+	root := t.TempDir()
+	cfgPath := writeConfig(t, root, `{"organization_id":"org","project_id":"proj","region":"fr-par","mtime":"remote","mapping":{
+		"app-dev":{"file":"app.bin","format":"raw"}
+	}}`)
+	api := newFakeSecretAPI()
+	app := api.AddSecret("proj", "app-dev", "/", secret.SecretTypeOpaque)
+	api.AddEnabledVersion(app.ID, []byte("A1"))
+	deps := baseDeps(func(config.Config, string) (SecretAPI, error) { return api, nil })
+	// The version was created long before the pull.
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	deps.Now = func() time.Time { return now }
+	userDir := t.TempDir()
+	deps.UserConfigDir = func() (string, error) { return userDir, nil }
+	output := func(args ...string) string {
+		t.Helper()
+		var out, errBuf bytes.Buffer
+		if code := Run(append([]string{"dev-vault", "--config", cfgPath}, args...), &out, &errBuf, deps); code != 0 {
+			t.Fatalf("%v: expected success, got %d %q", args, code, errBuf.String())
+		}
+		return out.String()
+	}
+
+	output("pull", "--all")
+	if info, err := os.Stat(filepath.Join(root, "app.bin")); err != nil || now.Sub(info.ModTime()) < 30*24*time.Hour {
+		t.Fatalf("expected the old version time on the file, got %v %v", info, err)
+	}
+	var records []staleRecord
+	if err := json.Unmarshal([]byte(output("list", "--stale", "--json")), &records); err != nil || len(records) != 0 {
+		t.Fatalf("expected a fresh pull not to be stale, got %+v %v", records, err)
+	}
+	var status promptStatus
+	if err := json.Unmarshal([]byte(output("prompt", "--json")), &status); err != nil || status.LastSync == nil || *status.LastSync != now.Format(time.RFC3339) {
+		t.Fatalf("expected the pull time as last sync, got %+v %v", status, err)
+	}
+
+	now = now.Add(20 * 24 * time.Hour)
+	if err := json.Unmarshal([]byte(output("list", "--stale", "--json")), &records); err != nil || len(records) != 1 || records[0].AgeDays == nil || *records[0].AgeDays != 20 {
+		t.Fatalf("expected the file stale by its pull time, got %+v %v", records, err)
+	}
+}
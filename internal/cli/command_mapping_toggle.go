@@ -0,0 +1,92 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/bsmartlabs/dev-vault/internal/config"
+	"github.com/bsmartlabs/dev-vault/internal/i18n"
+	"github.com/bsmartlabs/dev-vault/internal/secretsync"
+)
+
+var disableMappingCommandDef = commandDef{
+	Name:    "disable-mapping",
+	Summary: "Exclude a mapping entry from --all and drift checks",
+	Doc: commandDoc{
+		Synopsis: "dev-vault [--config <path>] disable-mapping <secret-dev>",
+		Description: []string{
+			"Sets disabled: true on the mapping entry in .scw.json without removing it.",
+			"Disabled entries are skipped by pull --all, push --all, list --stale, prompt, and report.",
+			"Naming a disabled entry explicitly (for example 'pull <secret-dev>') still works.",
+		},
+		Notes: []string{
+			"The manifest is rewritten with two-space indentation and sorted mapping keys.",
+		},
+		Examples: []string{
+			"dev-vault disable-mapping legacy-api-dev",
+		},
+	},
+	RunParsed: runDisableMappingParsed,
+}
+
+var enableMappingCommandDef = commandDef{
+	Name:    "enable-mapping",
+	Summary: "Re-include a disabled mapping entry",
+	Doc: commandDoc{
+		Synopsis: "dev-vault [--config <path>] enable-mapping <secret-dev>",
+		Description: []string{
+			"Removes disabled: true from the mapping entry in .scw.json.",
+		},
+		Notes: []string{
+			"The manifest is rewritten with two-space indentation and sorted mapping keys.",
+		},
+		Examples: []string{
+			"dev-vault enable-mapping legacy-api-dev",
+		},
+	},
+	RunParsed: runEnableMappingParsed,
+}
+
+func runDisableMapping(ctx commandContext, argv []string) int {
+	return runCommand(ctx, argv, disableMappingCommandDef)
+}
+
+func runEnableMapping(ctx commandContext, argv []string) int {
+	return runCommand(ctx, argv, enableMappingCommandDef)
+}
+
+func runDisableMappingParsed(ctx commandContext, parsed *parsedCommand) int {
+	return runMappingToggle(ctx, parsed, true)
+}
+
+func runEnableMappingParsed(ctx commandContext, parsed *parsedCommand) int {
+	return runMappingToggle(ctx, parsed, false)
+}
+
+func runMappingToggle(ctx commandContext, parsed *parsedCommand, disabled bool) int {
+	return newCommandRuntime(ctx, parsed).executeLocal(func(loaded *config.Loaded, _ secretsync.Service) error {
+		args := parsed.fs.Args()
+		if len(args) != 1 {
+			return usageError(i18n.Errorf(i18n.MsgExpectedOneSecretName))
+		}
+		name := args[0]
+		if !config.IsDevSecretName(name) {
+			return usageError(i18n.Errorf(i18n.MsgRefusingNonDev, name))
+		}
+		changed, err := config.SetMappingDisabled(loaded.Path, name, disabled)
+		if err != nil {
+			return runtimeError(err)
+		}
+		state := "enabled"
+		if disabled {
+			state = "disabled"
+		}
+		line := fmt.Sprintf("%s mapping %s", state, name)
+		if !changed {
+			line = fmt.Sprintf("mapping %s is already %s", name, state)
+		}
+		if _, err := fmt.Fprintln(ctx.stdout, line); err != nil {
+			return outputError(err)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,86 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bsmartlabs/dev-vault/internal/config"
+	secret "github.com/scaleway/scaleway-sdk-go/api/secret/v1beta1"
+)
+
+func TestRunMappingToggle(t *testing.T) {
+	root := t.TempDir()
+	cfgPath := writeConfig(t, root, `{"organization_id":"org","project_id":"proj","region":"fr-par","mapping":{
+		"a-dev":{"file":"a.bin"},
+		"b-dev":{"file":"b.bin"}
+	}}`)
+	api := newFakeSecretAPI()
+	for _, name := range []string{"a-dev", "b-dev"} {
+		sec := api.AddSecret("proj", name, "/", secret.SecretTypeOpaque)
+		api.AddEnabledVersion(sec.ID, []byte("DATA"))
+	}
+	deps := baseDeps(func(cfg config.Config, s string) (SecretAPI, error) { return api, nil })
+
+	run := func(args ...string) (int, string, string) {
+		var out, errBuf bytes.Buffer
+		code := Run(append([]string{"dev-vault", "--config", cfgPath}, args...), &out, &errBuf, deps)
+		return code, out.String(), errBuf.String()
+	}
+
+	if code, out, errOut := run("disable-mapping", "a-dev"); code != 0 || out != "disabled mapping a-dev\n" {
+		t.Fatalf("unexpected disable: %d %q %q", code, out, errOut)
+	}
+	if code, out, _ := run("disable-mapping", "a-dev"); code != 0 || out != "mapping a-dev is already disabled\n" {
+		t.Fatalf("unexpected repeat disable: %d %q", code, out)
+	}
+
+	// --all skips the disabled entry; naming it explicitly still works.
+	if code, out, errOut := run("pull", "--all"); code != 0 || out != "pulled b-dev -> b.bin (rev=1 type=opaque)\n" {
+		t.Fatalf("unexpected pull --all: %d %q %q", code, out, errOut)
+	}
+	if _, err := os.Stat(filepath.Join(root, "a.bin")); !os.IsNotExist(err) {
+		t.Fatalf("expected a.bin not to be pulled, got %v", err)
+	}
+	if code, out, errOut := run("pull", "a-dev"); code != 0 || !strings.HasPrefix(out, "pulled a-dev") {
+		t.Fatalf("unexpected explicit pull: %d %q %q", code, out, errOut)
+	}
+
+	if code, out, _ := run("enable-mapping", "a-dev"); code != 0 || out != "enabled mapping a-dev\n" {
+		t.Fatalf("unexpected enable: %d %q", code, out)
+	}
+	if code, out, _ := run("enable-mapping", "a-dev"); code != 0 || out != "mapping a-dev is already enabled\n" {
+		t.Fatalf("unexpected repeat enable: %d %q", code, out)
+	}
+
+	for _, tc := range []struct {
+		args []string
+		want string
+	}{
+		{[]string{"disable-mapping"}, "expected exactly one secret name"},
+		{[]string{"disable-mapping", "a-prod"}, "refusing non-dev secret name: a-prod"},
+	} {
+		if code, _, errOut := run(tc.args...); code != 2 || !strings.Contains(errOut, tc.want) {
+			t.Fatalf("%v: expected usage error %q, got %d %q", tc.args, tc.want, code, errOut)
+		}
+	}
+	if code, _, errOut := run("enable-mapping", "c-dev"); code != 1 || !strings.Contains(errOut, "secret not found in mapping: c-dev") {
+		t.Fatalf("expected not found error, got %d %q", code, errOut)
+	}
+}
+
+func TestRunMappingToggle_Errors(t *testing.T) {
+	root := t.TempDir()
+	cfgPath := writeConfig(t, root, `{"organization_id":"org","project_id":"proj","region":"fr-par","mapping":{"a-dev":{"file":"a.bin"}}}`)
+	deps := baseDeps(nil)
+
+	var errBuf bytes.Buffer
+	if code := runDisableMapping(commandContext{stdout: &failingWriter{}, stderr: &errBuf, configPath: cfgPath, deps: deps}, []string{"a-dev"}); code != 1 {
+		t.Fatalf("expected output error, got %d", code)
+	}
+	if code := runEnableMapping(commandContext{stdout: &bytes.Buffer{}, stderr: &errBuf, configPath: cfgPath, deps: deps}, []string{"a-dev"}); code != 0 {
+		t.Fatalf("expected 0, got %d stderr=%s", code, errBuf.String())
+	}
+}
@@ -0,0 +1,196 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bsmartlabs/dev-vault/internal/config"
+	"github.com/bsmartlabs/dev-vault/internal/secretprovider"
+	secret "github.com/scaleway/scaleway-sdk-go/api/secret/v1beta1"
+)
+
+func TestRun_MultiProfile(t *testing.T) {
+	root := t.TempDir()
+	cfgPath := writeConfig(t, root, `{"organization_id":"org","project_id":"proj","region":"fr-par","mapping":{
+		"home-env-dev":{"file":"home.env","format":"dotenv"},
+		"client-cert-dev":{"file":"client.pem"}
+	}}`)
+	home := newFakeSecretAPI()
+	homeEnv := home.AddSecret("proj", "home-env-dev", "/", secret.SecretTypeKeyValue)
+	home.AddEnabledVersion(homeEnv.ID, []byte(`{"A":"1"}`))
+	client := newFakeSecretAPI()
+	clientCert := client.AddSecret("client-proj", "client-cert-dev", "/", secret.SecretTypeOpaque)
+	clientCert.ID = "client-sec-1" // secret IDs are unique across organizations
+	client.AddEnabledVersion(clientCert.ID, []byte("PEM"))
+
+	opened := map[string]config.Config{}
+	deps := baseDeps(func(cfg config.Config, profile string) (SecretAPI, error) {
+		opened[profile] = cfg
+		switch profile {
+		case "home":
+			return home, nil
+		case "client":
+			return client, nil
+		}
+		return nil, errors.New("no such profile")
+	})
+	run := func(args ...string) (int, string, string) {
+		var out, errBuf bytes.Buffer
+		code := Run(append([]string{"dev-vault", "--config", cfgPath}, args...), &out, &errBuf, deps)
+		return code, out.String(), errBuf.String()
+	}
+
+	code, out, errOut := run("--profile", "home, client,home", "list")
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if code != 0 || len(lines) != 3 || !strings.HasPrefix(lines[0], "PROFILE") || !strings.HasPrefix(lines[1], "client ") || !strings.HasPrefix(lines[2], "home ") {
+		t.Fatalf("unexpected list: %d %q %q", code, out, errOut)
+	}
+	if opened["home"].ProjectID != "proj" || opened["client"].ProjectID != "" || opened["client"].OrganizationID != "" || opened["client"].Region != "fr-par" {
+		t.Fatalf("unexpected configs: %#v", opened)
+	}
+
+	code, out, _ = run("list", "--profile", "home,client", "--json")
+	var records []map[string]string
+	if err := json.Unmarshal([]byte(out), &records); code != 0 || err != nil || len(records) != 2 || records[0]["profile"] != "client" {
+		t.Fatalf("unexpected json: %d %q %v", code, out, err)
+	}
+
+	// Each mapping is pulled from the profile that has it.
+	if code, out, errOut := run("--profile", "home,client", "pull", "--all"); code != 0 || !strings.Contains(out, "pulled client-cert-dev") || !strings.Contains(out, "pulled home-env-dev") {
+		t.Fatalf("unexpected pull: %d %q %q", code, out, errOut)
+	}
+	if raw, err := os.ReadFile(filepath.Join(root, "client.pem")); err != nil || string(raw) != "PEM" {
+		t.Fatalf("unexpected client.pem: %q %v", raw, err)
+	}
+
+	for _, tc := range []struct {
+		args []string
+		code int
+		want string
+	}{
+		{[]string{"--profile", "home,missing", "list"}, 1, "open secret api for profile missing: no such profile"},
+		{[]string{"--profile", "home,client", "list", "--all-projects"}, 2, "--all-projects takes a single --profile"},
+		{[]string{"--profile", "home,client", "projects"}, 2, "projects takes a single --profile"},
+	} {
+		if code, _, errOut := run(tc.args...); code != tc.code || !strings.Contains(errOut, tc.want) {
+			t.Fatalf("%v: expected %d %q, got %d %q", tc.args, tc.code, tc.want, code, errOut)
+		}
+	}
+
+	var errBuf bytes.Buffer
+	if code := Run([]string{"dev-vault", "--config", cfgPath, "--profile", "home,client", "list"}, &failingWriter{}, &errBuf, deps); code != 1 {
+		t.Fatalf("expected output error, got %d", code)
+	}
+}
+
+func TestRun_MappingProfile(t *testing.T) {
+	root := t.TempDir()
+	cfgPath := writeConfig(t, root, `{"organization_id":"org","project_id":"proj","region":"fr-par","mapping":{
+		"home-dev":{"file":"home.bin"},
+		"client-dev":{"file":"client.bin","profile":"client-x"}
+	}}`)
+	home := newFakeSecretAPI()
+	homeSecret := home.AddSecret("proj", "home-dev", "/", secret.SecretTypeOpaque)
+	home.AddEnabledVersion(homeSecret.ID, []byte("H"))
+	client := newFakeSecretAPI()
+	clientSecret := client.AddSecret("client-proj", "client-dev", "/", secret.SecretTypeOpaque)
+	client.AddEnabledVersion(clientSecret.ID, []byte("C"))
+
+	opened := map[string]config.Config{}
+	deps := baseDeps(func(cfg config.Config, profile string) (SecretAPI, error) {
+		opened[profile] = cfg
+		if profile == "client-x" {
+			return client, nil
+		}
+		return home, nil
+	})
+	run := func(args ...string) (int, string, string) {
+		var out, errBuf bytes.Buffer
+		code := Run(append([]string{"dev-vault", "--config", cfgPath}, args...), &out, &errBuf, deps)
+		return code, out.String(), errBuf.String()
+	}
+
+	// --profile replaces the manifest's identity, not a mapping's own.
+	if code, out, errOut := run("--profile", "me", "pull", "home-dev"); code != 0 || !strings.Contains(out, "pulled home-dev") || len(opened) != 1 {
+		t.Fatalf("unexpected pull: %d %q %q %v", code, out, errOut, opened)
+	}
+	if code, out, errOut := run("--profile", "me", "pull", "--all", "--overwrite"); code != 0 || !strings.Contains(out, "pulled client-dev") {
+		t.Fatalf("unexpected pull: %d %q %q", code, out, errOut)
+	}
+	if cfg := opened["client-x"]; cfg.ProjectID != "" || cfg.OrganizationID != "" || cfg.Region != "fr-par" {
+		t.Fatalf("unexpected client config: %#v", cfg)
+	}
+	if raw, err := os.ReadFile(filepath.Join(root, "client.bin")); err != nil || string(raw) != "C" {
+		t.Fatalf("unexpected client.bin: %q %v", raw, err)
+	}
+
+	deps.OpenSecretAPI = func(cfg config.Config, profile string) (SecretAPI, error) {
+		if profile == "client-x" {
+			return nil, errors.New("no such profile")
+		}
+		return home, nil
+	}
+	if code, _, errOut := run("pull", "client-dev", "--overwrite"); code != 1 || !strings.Contains(errOut, "open profile client-x: no such profile") {
+		t.Fatalf("unexpected open error: %d %q", code, errOut)
+	}
+	deps.OpenSecretAPI = func(cfg config.Config, profile string) (SecretAPI, error) { return client, nil }
+
+	client.createVerErr = fmt.Errorf("%w: 403", secretprovider.ErrPermissionDenied)
+	code, _, errOut := run("push", "client-dev", "--yes")
+	if code != 1 || !strings.Contains(errOut, `hint: the credentials from profile "client-x" (set by the mapping) can read secrets but not write them`) {
+		t.Fatalf("unexpected push refusal: %d %q", code, errOut)
+	}
+}
+
+func TestRun_Environments(t *testing.T) {
+	root := t.TempDir()
+	cfgPath := writeConfig(t, root, `{
+  "organization_id": "org",
+  "project_id": "proj",
+  "region": "fr-par",
+  "mapping": {"app-env-dev": {"file": "app.env", "format": "dotenv"}},
+  "environments": {
+    "sandbox": {"project_id": "sandbox-proj", "profile": "sb", "mapping": {"extra-dev": {"file": "extra"}}}
+  }
+}`)
+	// The provider is scoped to the project it is opened for.
+	apis := map[string]*fakeSecretAPI{"proj": newFakeSecretAPI(), "sandbox-proj": newFakeSecretAPI()}
+	for project, payload := range map[string]string{"proj": `{"A":"1"}`, "sandbox-proj": `{"A":"2"}`} {
+		app := apis[project].AddSecret(project, "app-env-dev", "/", secret.SecretTypeKeyValue)
+		apis[project].AddEnabledVersion(app.ID, []byte(payload))
+	}
+	var opened config.Config
+	deps := baseDeps(func(cfg config.Config, _ string) (SecretAPI, error) {
+		opened = cfg
+		return apis[cfg.ProjectID], nil
+	})
+	run := func(args ...string) (int, string, string) {
+		var out, errBuf bytes.Buffer
+		code := Run(append([]string{"dev-vault", "--plain", "--config", cfgPath}, args...), &out, &errBuf, deps)
+		return code, out.String(), errBuf.String()
+	}
+
+	if code, _, errOut := run("pull", "app-env-dev", "--env", "sandbox"); code != 0 || opened.ProjectID != "sandbox-proj" || opened.Profile != "sb" {
+		t.Fatalf("unexpected environment pull: %d %q %#v", code, errOut, opened)
+	}
+	if data, err := os.ReadFile(filepath.Join(root, "app.env")); err != nil || !strings.Contains(string(data), `A="2"`) {
+		t.Fatalf("expected the sandbox payload, got %q %v", data, err)
+	}
+	if code, _, errOut := run("pull", "extra-dev"); code != 2 || !strings.Contains(errOut, "extra-dev") {
+		t.Fatalf("expected environment mappings to stay out of the top level: %d %q", code, errOut)
+	}
+	if code, _, errOut := run("--env", "prod", "list"); code != 1 || !strings.Contains(errOut, `unknown environment "prod" (expected sandbox)`) {
+		t.Fatalf("expected unknown environment error: %d %q", code, errOut)
+	}
+
+	code, out, _ := run("--env", "sandbox", "--explain-config")
+	if code != 0 || !strings.Contains(out, "project_id\tsandbox-proj\t"+cfgPath+":7 (environment sandbox)\n") {
+		t.Fatalf("unexpected explain output: %d\n%s", code, out)
+	}
+}
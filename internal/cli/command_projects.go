@@ -0,0 +1,121 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/bsmartlabs/dev-vault/internal/secretprovider"
+)
+
+var projectsCommandDef = commandDef{
+	Name:    "projects",
+	Summary: "List Scaleway projects visible to your credentials",
+	Flags: []commandFlagDef{
+		{Name: "json", Kind: commandFlagBool, Help: "Output JSON"},
+		{Name: "organization-id", Kind: commandFlagString, ValueName: "<id>", Help: "Organization to list (default: from Scaleway profile/env)"},
+	},
+	Doc: commandDoc{
+		Synopsis: "dev-vault [--profile <name>] projects [options]",
+		Description: []string{
+			"Lists projects (name and ID) visible to the current Scaleway credentials.",
+			"Does not require a .scw.json, so it can be used to fill organization_id/project_id.",
+		},
+		Examples: []string{
+			"dev-vault projects",
+			"dev-vault --profile work projects --json",
+		},
+	},
+	RunParsed: runProjectsParsed,
+}
+
+type projectRecord struct {
+	ID             string `json:"id"`
+	Name           string `json:"name"`
+	OrganizationID string `json:"organization_id"`
+}
+
+func runProjects(ctx commandContext, argv []string) int {
+	return runCommand(ctx, argv, projectsCommandDef)
+}
+
+func runProjectsParsed(ctx commandContext, parsed *parsedCommand) int {
+	return newCommandRuntime(ctx, parsed).executeAccount(func(api secretprovider.AccountAPI) error {
+		projects, err := api.ListProjects(secretprovider.ListProjectsInput{
+			OrganizationID: parsed.String("organization-id"),
+		})
+		if err != nil {
+			return err
+		}
+		records := make([]projectRecord, 0, len(projects))
+		for _, project := range projects {
+			records = append(records, projectRecord{ID: project.ID, Name: project.Name, OrganizationID: project.OrganizationID})
+		}
+		sort.Slice(records, func(i, j int) bool {
+			if records[i].Name != records[j].Name {
+				return records[i].Name < records[j].Name
+			}
+			return records[i].ID < records[j].ID
+		})
+
+		if parsed.Bool("json") {
+			enc := json.NewEncoder(ctx.stdout)
+			enc.SetIndent("", "  ")
+			if err := enc.Encode(records); err != nil {
+				return outputError(err)
+			}
+			return nil
+		}
+
+		tbl := newTable(ctx.stdout, parsed.plain, "NAME", "ID", "ORGANIZATION_ID")
+		for _, record := range records {
+			tbl.row(record.Name, record.ID, record.OrganizationID)
+		}
+		if err := tbl.flush(); err != nil {
+			return outputError(err)
+		}
+		return nil
+	})
+}
+
+var regionsCommandDef = commandDef{
+	Name:    "regions",
+	Summary: "List regions where Secret Manager is available",
+	Flags: []commandFlagDef{
+		{Name: "json", Kind: commandFlagBool, Help: "Output JSON"},
+	},
+	Doc: commandDoc{
+		Synopsis: "dev-vault regions [--json]",
+		Description: []string{
+			"Lists valid values for the .scw.json region field.",
+		},
+		Examples: []string{
+			"dev-vault regions",
+		},
+	},
+	RunParsed: runRegionsParsed,
+}
+
+func runRegions(ctx commandContext, argv []string) int {
+	return runCommand(ctx, argv, regionsCommandDef)
+}
+
+func runRegionsParsed(ctx commandContext, parsed *parsedCommand) int {
+	return newCommandRuntime(ctx, parsed).executeAccount(func(api secretprovider.AccountAPI) error {
+		regions := api.Regions()
+		sort.Strings(regions)
+
+		if parsed.Bool("json") {
+			if err := json.NewEncoder(ctx.stdout).Encode(regions); err != nil {
+				return outputError(err)
+			}
+			return nil
+		}
+		for _, region := range regions {
+			if _, err := fmt.Fprintln(ctx.stdout, region); err != nil {
+				return outputError(err)
+			}
+		}
+		return nil
+	})
+}
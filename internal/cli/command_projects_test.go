@@ -0,0 +1,96 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/bsmartlabs/dev-vault/internal/config"
+	"github.com/bsmartlabs/dev-vault/internal/secretprovider"
+)
+
+func accountDeps(api *fakeAccountAPI, openErr error) Dependencies {
+	deps := baseDeps(func(cfg config.Config, s string) (SecretAPI, error) { return newFakeSecretAPI(), nil })
+	deps.OpenAccountAPI = func(profile string) (AccountAPI, error) {
+		if openErr != nil {
+			return nil, openErr
+		}
+		return api, nil
+	}
+	return deps
+}
+
+func TestRunProjects(t *testing.T) {
+	api := &fakeAccountAPI{projects: []secretprovider.ProjectRecord{
+		{ID: "p2", Name: "web", OrganizationID: "org"},
+		{ID: "p1", Name: "api", OrganizationID: "org"},
+		{ID: "p0", Name: "api", OrganizationID: "org"},
+	}}
+
+	t.Run("Table", func(t *testing.T) {
+		var out, errBuf bytes.Buffer
+		code := Run([]string{"dev-vault", "projects", "--organization-id", "org"}, &out, &errBuf, accountDeps(api, nil))
+		if code != 0 {
+			t.Fatalf("expected 0, got %d stderr=%s", code, errBuf.String())
+		}
+		lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+		if len(lines) != 4 || !strings.Contains(lines[1], "p0") || !strings.Contains(lines[3], "web") {
+			t.Fatalf("unexpected output: %q", out.String())
+		}
+		if api.lastOrg != "org" {
+			t.Fatalf("organization not forwarded: %q", api.lastOrg)
+		}
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		var out, errBuf bytes.Buffer
+		code := Run([]string{"dev-vault", "projects", "--json"}, &out, &errBuf, accountDeps(api, nil))
+		if code != 0 {
+			t.Fatalf("expected 0, got %d stderr=%s", code, errBuf.String())
+		}
+		var records []projectRecord
+		if err := json.Unmarshal(out.Bytes(), &records); err != nil {
+			t.Fatalf("unmarshal: %v", err)
+		}
+		if len(records) != 3 || records[0].ID != "p0" {
+			t.Fatalf("unexpected records: %#v", records)
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		var out, errBuf bytes.Buffer
+		if code := Run([]string{"dev-vault", "projects"}, &out, &errBuf, accountDeps(nil, errors.New("no creds"))); code != 1 || !strings.Contains(errBuf.String(), "open account api") {
+			t.Fatalf("expected open error, got code=%d stderr=%q", code, errBuf.String())
+		}
+		failing := &fakeAccountAPI{listErr: errors.New("denied")}
+		if code := Run([]string{"dev-vault", "projects"}, &out, &errBuf, accountDeps(failing, nil)); code != 1 {
+			t.Fatalf("expected list error exit 1, got %d", code)
+		}
+		for _, args := range [][]string{{}, {"--json"}} {
+			code := runProjects(commandContext{stdout: &failingWriter{}, stderr: &errBuf, deps: accountDeps(api, nil)}, args)
+			if code != 1 {
+				t.Fatalf("%v: expected write failure exit 1, got %d", args, code)
+			}
+		}
+	})
+}
+
+func TestRunRegions(t *testing.T) {
+	api := &fakeAccountAPI{regions: []string{"pl-waw", "fr-par"}}
+	var out, errBuf bytes.Buffer
+	if code := Run([]string{"dev-vault", "regions"}, &out, &errBuf, accountDeps(api, nil)); code != 0 || out.String() != "fr-par\npl-waw\n" {
+		t.Fatalf("unexpected regions output: code=%d out=%q", code, out.String())
+	}
+	out.Reset()
+	if code := Run([]string{"dev-vault", "regions", "--json"}, &out, &errBuf, accountDeps(api, nil)); code != 0 || out.String() != "[\"fr-par\",\"pl-waw\"]\n" {
+		t.Fatalf("unexpected regions json: code=%d out=%q", code, out.String())
+	}
+	for _, args := range [][]string{{}, {"--json"}} {
+		code := runRegions(commandContext{stdout: &failingWriter{}, stderr: &errBuf, deps: accountDeps(api, nil)}, args)
+		if code != 1 {
+			t.Fatalf("%v: expected write failure exit 1, got %d", args, code)
+		}
+	}
+}
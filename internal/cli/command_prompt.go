@@ -0,0 +1,192 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bsmartlabs/dev-vault/internal/config"
+	"github.com/bsmartlabs/dev-vault/internal/i18n"
+	"github.com/bsmartlabs/dev-vault/internal/remotecheck"
+	"github.com/bsmartlabs/dev-vault/internal/secretsync"
+)
+
+// Provider states of prompt, from the last remote check of status or
+// pull --watch.
+const (
+	promptProviderReachable   = "reachable"
+	promptProviderUnreachable = "unreachable"
+	promptProviderUnknown     = "unknown"
+)
+
+// promptBudget is how long prompt may take before it gives up, so a slow disk
+// never holds up the shell.
+const promptBudget = 50 * time.Millisecond
+
+var promptCommandDef = commandDef{
+	Name:    "prompt",
+	Summary: "Print local mapping status for shell prompts",
+	Flags: []commandFlagDef{
+		{Name: "json", Kind: commandFlagBool, Help: "Output JSON"},
+	},
+	Doc: commandDoc{
+		Synopsis: "dev-vault [--config <path>] prompt [--json]",
+		Description: []string{
+			"Prints a compact status line for prompt frameworks (starship, oh-my-zsh).",
+			"Only the manifest, local files, and the user state dir are read: the provider is never",
+			"contacted, and the command gives up after 50ms, so it can run on every prompt render.",
+		},
+		Notes: []string{
+			"drift.missing counts mapped files that do not exist locally.",
+			"drift.expired counts mapped files whose expire_after (see pull) has passed.",
+			"drift.changed counts mapped files that did not match their remote secret, in content or",
+			"revision, at the last status or pull --watch, and have not been pulled since.",
+			"last_sync is the latest recorded pull of a mapped file, or its modification time when no",
+			"pull was recorded (null if none exist).",
+			"provider is 'reachable' or 'unreachable' as of the last status or pull --watch, whose",
+			"time is checked_at, or 'unknown' (checked_at null) before any.",
+			"Past the 50ms budget, nothing is printed and the exit code is 1.",
+		},
+		Examples: []string{
+			"dev-vault prompt",
+			"dev-vault prompt --json",
+		},
+	},
+	RunParsed: runPromptParsed,
+}
+
+type promptDrift struct {
+	Missing int `json:"missing"`
+	Expired int `json:"expired"`
+	Changed int `json:"changed"`
+}
+
+type promptStatus struct {
+	Mapped    int         `json:"mapped"`
+	Present   int         `json:"present"`
+	Drift     promptDrift `json:"drift"`
+	LastSync  *string     `json:"last_sync"`
+	Provider  string      `json:"provider"`
+	CheckedAt *string     `json:"checked_at"`
+}
+
+func runPrompt(ctx commandContext, argv []string) int {
+	return runCommand(ctx, argv, promptCommandDef)
+}
+
+func runPromptParsed(ctx commandContext, parsed *parsedCommand) int {
+	// The budget covers the whole command, loading the manifest included.
+	budget, cancel := context.WithTimeout(context.Background(), promptBudget)
+	defer cancel()
+	return newCommandRuntime(ctx, parsed).executeLocal(func(loaded *config.Loaded, service secretsync.Service) error {
+		status, err := promptStatusWithin(budget, ctx.deps, loaded, service)
+		if err != nil {
+			return err
+		}
+
+		if parsed.Bool("json") {
+			if err := json.NewEncoder(ctx.stdout).Encode(status); err != nil {
+				return outputError(err)
+			}
+			return nil
+		}
+
+		line := fmt.Sprintf("vault %d/%d", status.Present, status.Mapped)
+		var drift []string
+		if status.Drift.Missing > 0 {
+			drift = append(drift, fmt.Sprintf("%d missing", status.Drift.Missing))
+		}
+		if status.Drift.Expired > 0 {
+			drift = append(drift, fmt.Sprintf("%d expired", status.Drift.Expired))
+		}
+		if status.Drift.Changed > 0 {
+			drift = append(drift, fmt.Sprintf("%d changed", status.Drift.Changed))
+		}
+		if status.Provider == promptProviderUnreachable {
+			drift = append(drift, "provider unreachable")
+		}
+		if len(drift) > 0 {
+			line += " (" + strings.Join(drift, ", ") + ")"
+		}
+		if _, err := fmt.Fprintln(ctx.stdout, line); err != nil {
+			return outputError(err)
+		}
+		return nil
+	})
+}
+
+// promptStatusWithin gathers the status unless budget ends first. A spent
+// budget always wins, even over a status that is ready.
+func promptStatusWithin(budget context.Context, deps Dependencies, loaded *config.Loaded, service secretsync.Service) (promptStatus, error) {
+	ready := make(chan promptStatus, 1)
+	go func() {
+		ready <- promptStatusOf(budget, deps, loaded, service)
+	}()
+	var status promptStatus
+	select {
+	case status = <-ready:
+	case <-budget.Done():
+	}
+	if budget.Err() != nil {
+		return promptStatus{}, runtimeError(i18n.Errorf(i18n.MsgPromptBudget, promptBudget))
+	}
+	return status, nil
+}
+
+// promptStatusOf gathers the status from local files and the user state dir.
+// Each step reads the disk; once budget is spent the remaining ones are
+// skipped, as nothing waits for the result any more.
+func promptStatusOf(budget context.Context, deps Dependencies, loaded *config.Loaded, service secretsync.Service) promptStatus {
+	var pulled map[string]time.Time
+	var status promptStatus
+	for _, step := range []func(){
+		func() { pulled = pullTimes(deps, loaded) },
+		func() { status = promptStatusFromLocal(service.LocalStatus(pulled)) },
+		func() { status.Drift.Expired = len(expiredFiles(deps, loaded, service)) },
+		func() {
+			if check, ok := lastRemoteCheck(deps, loaded); ok {
+				status.applyRemoteCheck(check, loaded.Cfg.Mapping, pulled)
+			}
+		},
+	} {
+		if budget.Err() != nil {
+			break
+		}
+		step()
+	}
+	return status
+}
+
+func promptStatusFromLocal(local secretsync.LocalStatus) promptStatus {
+	status := promptStatus{
+		Mapped:   local.Mapped,
+		Present:  local.Present,
+		Drift:    promptDrift{Missing: local.Missing},
+		Provider: promptProviderUnknown,
+	}
+	if !local.LastSync.IsZero() {
+		lastSync := local.LastSync.UTC().Format(time.RFC3339)
+		status.LastSync = &lastSync
+	}
+	return status
+}
+
+// applyRemoteCheck reports check. A drifted secret no longer counts once it
+// is pulled again or its mapping is disabled or removed.
+func (p *promptStatus) applyRemoteCheck(check remotecheck.Entry, mapping map[string]config.MappingEntry, pulled map[string]time.Time) {
+	p.Provider = promptProviderUnreachable
+	if check.Reachable {
+		p.Provider = promptProviderReachable
+	}
+	checkedAt := check.CheckedAt.UTC().Format(time.RFC3339)
+	p.CheckedAt = &checkedAt
+	for _, name := range check.Drifted {
+		entry, ok := mapping[name]
+		if !ok || entry.Disabled || pulled[name].After(check.CheckedAt) {
+			continue
+		}
+		p.Drift.Changed++
+	}
+}
@@ -0,0 +1,166 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bsmartlabs/dev-vault/internal/config"
+	"github.com/bsmartlabs/dev-vault/internal/secretsync"
+)
+
+func TestRunPrompt(t *testing.T) {
+	root := t.TempDir()
+	cfgPath := writeConfig(t, root, `{"organization_id":"org","project_id":"proj","region":"fr-par","mapping":{"a-dev":{"file":"a.env"},"b-dev":{"file":"b.env","mode":"sync"}}}`)
+	if err := os.WriteFile(filepath.Join(root, "a.env"), []byte("A=1\n"), 0o600); err != nil {
+		t.Fatalf("write a.env: %v", err)
+	}
+	opened := false
+	deps := baseDeps(func(cfg config.Config, s string) (SecretAPI, error) {
+		opened = true
+		return newFakeSecretAPI(), nil
+	})
+
+	t.Run("Text", func(t *testing.T) {
+		var out, errBuf bytes.Buffer
+		code := Run([]string{"dev-vault", "--config", cfgPath, "prompt"}, &out, &errBuf, deps)
+		if code != 0 {
+			t.Fatalf("expected 0, got %d stderr=%s", code, errBuf.String())
+		}
+		if got := out.String(); got != "vault 1/2 (1 missing)\n" {
+			t.Fatalf("unexpected prompt line: %q", got)
+		}
+		if !strings.Contains(errBuf.String(), "legacy mode=sync") {
+			t.Fatalf("expected config warning, got %q", errBuf.String())
+		}
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		var out, errBuf bytes.Buffer
+		code := Run([]string{"dev-vault", "--config", cfgPath, "prompt", "--json"}, &out, &errBuf, deps)
+		if code != 0 {
+			t.Fatalf("expected 0, got %d stderr=%s", code, errBuf.String())
+		}
+		var got promptStatus
+		if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+			t.Fatalf("unmarshal: %v", err)
+		}
+		if got.Mapped != 2 || got.Present != 1 || got.Drift.Missing != 1 || got.LastSync == nil || got.Provider != promptProviderUnknown || got.CheckedAt != nil {
+			t.Fatalf("unexpected prompt status: %#v", got)
+		}
+	})
+
+	if opened {
+		t.Fatal("prompt must not open the secret API")
+	}
+
+	t.Run("AllPresentNoLastSync", func(t *testing.T) {
+		status := promptStatusFromLocal(secretsync.LocalStatus{Mapped: 2, Present: 2})
+		if status.LastSync != nil || status.Drift.Missing != 0 {
+			t.Fatalf("unexpected status: %#v", status)
+		}
+	})
+
+	t.Run("AllPresentText", func(t *testing.T) {
+		if err := os.WriteFile(filepath.Join(root, "b.env"), []byte("B=1\n"), 0o600); err != nil {
+			t.Fatalf("write b.env: %v", err)
+		}
+		defer func() { _ = os.Remove(filepath.Join(root, "b.env")) }()
+		var out, errBuf bytes.Buffer
+		code := Run([]string{"dev-vault", "--config", cfgPath, "prompt"}, &out, &errBuf, deps)
+		if code != 0 || out.String() != "vault 2/2\n" {
+			t.Fatalf("unexpected result: code=%d out=%q", code, out.String())
+		}
+	})
+
+	t.Run("ConfigError", func(t *testing.T) {
+		var out, errBuf bytes.Buffer
+		code := Run([]string{"dev-vault", "--config", filepath.Join(root, "nope.json"), "prompt"}, &out, &errBuf, deps)
+		if code != 1 {
+			t.Fatalf("expected 1, got %d", code)
+		}
+	})
+
+	t.Run("RemoteCheck", func(t *testing.T) {
+		state := t.TempDir()
+		stateDeps := deps
+		stateDeps.UserConfigDir = func() (string, error) { return state, nil }
+		checked := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+		stateDeps.Now = func() time.Time { return checked }
+		loaded := &config.Loaded{Path: cfgPath}
+		run := func(args ...string) (int, string) {
+			var out, errBuf bytes.Buffer
+			code := Run(append([]string{"dev-vault", "--config", cfgPath, "prompt"}, args...), &out, &errBuf, stateDeps)
+			return code, out.String()
+		}
+
+		// Drift of secrets no longer mapped does not count.
+		recordRemoteCheck(stateDeps, loaded, true, []string{"a-dev", "b-dev", "gone-dev"})
+		var got promptStatus
+		if code, out := run("--json"); code != 0 || json.Unmarshal([]byte(out), &got) != nil ||
+			got.Provider != promptProviderReachable || got.CheckedAt == nil || *got.CheckedAt != "2026-01-02T03:04:05Z" || got.Drift.Changed != 2 {
+			t.Fatalf("unexpected prompt status: %d %s", code, out)
+		}
+
+		// A pull after the check settles the drift of its secret.
+		if err := recordPull(stateDeps, loaded, 0, []secretsync.PullResult{{Name: "a-dev"}}); err != nil {
+			t.Fatalf("record pull: %v", err)
+		}
+		stateDeps.Now = func() time.Time { return checked.Add(-time.Hour) }
+		recordRemoteCheck(stateDeps, loaded, false, []string{"a-dev", "b-dev"})
+		stateDeps.Now = func() time.Time { return checked }
+		if code, out := run(); code != 0 || out != "vault 1/2 (1 missing, 1 changed, provider unreachable)\n" {
+			t.Fatalf("unexpected prompt line: %d %q", code, out)
+		}
+	})
+
+	t.Run("Budget", func(t *testing.T) {
+		// Every read of the state dir outlasts the budget.
+		slow := deps
+		slow.UserConfigDir = func() (string, error) {
+			time.Sleep(5 * promptBudget)
+			return "", errors.New("late")
+		}
+		var out, errBuf bytes.Buffer
+		code := Run([]string{"dev-vault", "--config", cfgPath, "prompt"}, &out, &errBuf, slow)
+		if code != 1 || out.String() != "" || !strings.Contains(errBuf.String(), "prompt: no status within 50ms") {
+			t.Fatalf("expected the budget to stop prompt, got %d %q %q", code, out.String(), errBuf.String())
+		}
+	})
+
+	t.Run("BudgetAlreadySpent", func(t *testing.T) {
+		spent, cancel := context.WithDeadline(context.Background(), time.Unix(0, 0))
+		defer cancel()
+		// The worker sees the spent budget and returns at once without reading
+		// anything, so both select cases are ready: the budget must still win.
+		if status := promptStatusOf(spent, deps, nil, secretsync.Service{}); status != (promptStatus{}) {
+			t.Fatalf("expected the worker to stop, got %#v", status)
+		}
+		for range 100 {
+			if _, err := promptStatusWithin(spent, deps, nil, secretsync.Service{}); err == nil || err.Error() != "prompt: no status within 50ms" {
+				t.Fatalf("expected the spent budget to win, got %v", err)
+			}
+		}
+	})
+
+	t.Run("WriteFailures", func(t *testing.T) {
+		for _, args := range [][]string{{}, {"--json"}} {
+			var errBuf bytes.Buffer
+			code := runPrompt(commandContext{
+				stdout:     &failingWriter{},
+				stderr:     &errBuf,
+				configPath: cfgPath,
+				deps:       deps,
+			}, args)
+			if code != 1 {
+				t.Fatalf("expected 1 for %v, got %d", args, code)
+			}
+		}
+	})
+}
@@ -2,7 +2,12 @@ package cli
 
 import (
 	"fmt"
+	"strconv"
+	"time"
 
+	"github.com/bsmartlabs/dev-vault/internal/ciplatform"
+	"github.com/bsmartlabs/dev-vault/internal/config"
+	"github.com/bsmartlabs/dev-vault/internal/i18n"
 	"github.com/bsmartlabs/dev-vault/internal/secretsync"
 )
 
@@ -11,24 +16,89 @@ var pullCommandDef = commandDef{
 	Summary: "Pull mapped -dev secrets to local files",
 	Flags: []commandFlagDef{
 		{Name: "all", Kind: commandFlagBool, Help: "Pull all mapping entries with mode pull|both (mode defaults to both)"},
+		{Name: "ci-export", Kind: commandFlagBool, Help: "Also hand dotenv variables to later CI steps (GitHub Actions, GitLab CI, CircleCI)"},
+		{Name: "coerce", Kind: commandFlagBool, Help: coerceFlagHelp},
+		{Name: "dry-run", Kind: commandFlagBool, Help: "Read and convert the secrets and print what would be written, without writing"},
+		{Name: "expire-after", Kind: commandFlagString, ValueName: "<age>", Help: "Flag the pulled files as expired after this age, e.g. 8h (default: config expire_after)"},
+		{Name: "expiry-warning", Kind: commandFlagString, ValueName: "<age>", Help: "Warn about certificates expiring within this age, e.g. 30d (default 30d)"},
+		{Name: "fake", Kind: commandFlagBool, Help: "Write dotenv files with placeholders for the keys in mapping.fake_keys, without reading secrets"},
+		{Name: "interval", Kind: commandFlagString, ValueName: "<duration>", Help: "How often --watch polls the provider, e.g. 30s or 5m (default 30s)"},
+		{Name: "mtime", Kind: commandFlagString, ValueName: "<now|preserve|remote>", Help: "Modification time of pulled files (default: config mtime, else now)"},
 		{Name: "overwrite", Kind: commandFlagBool, Help: "Overwrite existing files"},
+		{Name: "resume", Kind: commandFlagBool, Help: resumeFlagHelp},
+		{Name: "revision", Kind: commandFlagString, ValueName: "<n>", Help: "Pull this secret revision instead of the latest enabled one (one secret only)"},
+		{Name: "watch", Kind: commandFlagBool, Help: "Keep running and pull again each secret that gets a new enabled revision"},
+	},
+	Constraints: []flagConstraint{
+		{Flag: "fake", Excludes: []string{"ci-export", "coerce", "dry-run", "resume", "watch", "revision"}, Reason: i18n.MsgReasonFakeManifestOnly},
+		{Flag: "revision", Excludes: []string{"all"}, Reason: i18n.MsgReasonRevisionOneSecret},
+		{Flag: "dry-run", Excludes: []string{"watch", "ci-export"}, Reason: i18n.MsgReasonDryRunWritesNothing},
+		{Flag: "watch", Excludes: []string{"ci-export"}, Reason: i18n.MsgReasonWatchNeverFinishes},
+		{Flag: "watch", Excludes: []string{"revision"}, Reason: i18n.MsgReasonWatchLatest},
+		{Flag: "interval", Requires: []string{"watch"}, Reason: i18n.MsgReasonIntervalWatch},
 	},
 	Doc: commandDoc{
 		Synopsis: "dev-vault [--config <path>] [--profile <name>] pull (--all | <secret-dev> ...) [options]",
 		Description: []string{
 			"Pulls one or more secrets to disk based on .scw.json mapping.",
 			"Secrets must exist in mapping and names must end with '-dev'.",
-			"Pull reads the latest enabled secret version (Scaleway revision selector: latest_enabled).",
+			"Pull reads the latest enabled secret version (Scaleway revision selector: latest_enabled),",
+			"or the revision pinned by mapping.revision or --revision.",
 			"Pull writes files atomically and chmods them to 0600 (on Unix).",
+			"Pulled files are marked as managed: dotenv files start with a '# managed by dev-vault'",
+			"comment, and the user.dev-vault extended attribute names the secret and revision.",
 			"Never prints secret payloads.",
 			"",
 			"Formats:",
 			"  - mapping.format=raw writes secret bytes as-is.",
 			"  - mapping.format=dotenv expects a JSON object payload and renders deterministic .env output.",
+			"  - mapping.format=yaml expects a JSON object payload and renders it as key-sorted YAML.",
+			"  - mapping.format=toml expects a JSON object payload and renders it as key-sorted TOML.",
+			"  - mapping.format=json pretty-prints the JSON payload with sorted keys; push compacts it.",
+			"  - mapping.format=template renders mapping.template_file (Go text/template) with the",
+			"    keys of a JSON object payload; such mappings are pull-only.",
+			"  - mapping.files writes each listed key of a key_value secret, as-is, to its own file.",
+			"",
+			"Certificate secrets also report their earliest expiry (expires=YYYY-MM-DD); a warning goes",
+			"to stderr when it falls within --expiry-warning.",
+		},
+		Notes: []string{
+			"A secret whose type differs from mapping.type is refused unless --coerce is set; a dotenv",
+			"mapping then still requires a JSON object payload.",
+			"--ci-export detects the CI platform and, after a successful pull, writes the variables of",
+			"dotenv mappings to GITHUB_ENV (GitHub Actions), BASH_ENV (CircleCI), or",
+			"$CI_PROJECT_DIR/dev-vault.env for an artifacts:reports:dotenv report (GitLab CI).",
+			"Values are never masked in job logs; masking would require printing them.",
+			"Completed targets are recorded in the user state dir; --resume skips them after an",
+			"interrupted or failed run. A fully successful run clears the record.",
+			"--mtime preserve keeps the modification time of an overwritten file and remote sets it",
+			"to the creation time of the pulled version, so build tools only rebuild when it changed. User",
+			"extended attributes (user.*) of an overwritten file are kept on Linux.",
+			"--revision rolls one file back to an older version (see the versions command); it fails",
+			"unless the revision exists and is enabled. It overrides mapping.revision.",
+			"--fake is for contributors without access to the secrets: it writes the dotenv mappings",
+			"that list mapping.fake_keys (with --all, only those) with a stable placeholder per key,",
+			"key filters and affixes applied. It needs no credentials, and push refuses the files.",
+			"--watch pulls once, then polls every --interval and rewrites, atomically, the files of",
+			"secrets with a new latest enabled revision, printing a timestamped line for each. Failed",
+			"polls are reported as warnings and retried. Each poll is saved for prompt, which shows",
+			"whether the provider answered. Ctrl-C stops it with exit code 130.",
+			"--dry-run does everything but write: each line starts with 'would pull' and ends with",
+			"file=create, file=overwrite, or file=unchanged. It fails where pull would, e.g. on an",
+			"existing file without --overwrite, and records no --resume progress.",
+			"--expire-after (or expire_after in the config) records in the user state dir when the",
+			"pulled files expire; prompt, list --stale, and --watch then flag the expired ones so",
+			"plaintext copies get refreshed or removed. Files are never deleted automatically. A pull",
+			"without either drops the recorded expiry of the files it writes.",
 		},
 		Examples: []string{
 			"dev-vault pull bweb-env-bsmart-dev --overwrite",
 			"dev-vault pull --all --overwrite",
+			"dev-vault pull --all --overwrite --ci-export",
+			"dev-vault pull bweb-env-bsmart-dev --revision 3 --overwrite",
+			"dev-vault pull --all --fake",
+			"dev-vault pull --all --overwrite --watch --interval 1m",
+			"dev-vault pull --all --overwrite --dry-run",
 			"dev-vault pull --config .scw.json bweb-env-bsmart-dev --overwrite",
 			"dev-vault pull bweb-env-bsmart-dev --config .scw.json --overwrite",
 		},
@@ -41,20 +111,259 @@ func runPull(ctx commandContext, argv []string) int {
 }
 
 func runPullParsed(ctx commandContext, parsed *parsedCommand) int {
+	if parsed.Bool("fake") {
+		return runPullFake(ctx, parsed)
+	}
+	expiryWarning := defaultExpiryWarning
+	interval := defaultWatchInterval
+	var revision uint32
+	var platform ciplatform.Platform
+	var loaded *config.Loaded
+	var expireAfter time.Duration
 	return newCommandRuntime(ctx, parsed).executeMapping(mappingCommandSpec{
-		mode: commandModePull,
-		all:  parsed.Bool("all"),
-		execute: func(service secretsync.Service, targets []secretsync.MappingTarget) error {
-			results, err := service.Pull(targets, parsed.Bool("overwrite"))
+		mode:   commandModePull,
+		all:    parsed.Bool("all"),
+		resume: parsed.Bool("resume"),
+		coerce: parsed.Bool("coerce"),
+		dryRun: parsed.Bool("dry-run"),
+		onLoad: func(l *config.Loaded) { loaded = l },
+		preflight: func(targets []secretsync.MappingTarget) error {
+			age, err := pullExpireAfter(parsed, loaded)
 			if err != nil {
 				return err
 			}
-			for _, item := range results {
-				if _, err := fmt.Fprintf(ctx.stdout, "pulled %s -> %s (rev=%d type=%s)\n", item.Name, item.File, item.Revision, item.Type); err != nil {
-					return outputError(err)
+			expireAfter = age
+			if value := parsed.String("revision"); value != "" {
+				n, err := strconv.ParseUint(value, 10, 32)
+				if err != nil || n == 0 {
+					return usageError(i18n.Errorf(i18n.MsgInvalidRevision, value))
+				}
+				if len(targets) != 1 {
+					return usageError(i18n.Errorf(i18n.MsgRevisionOneSecret))
+				}
+				revision = uint32(n)
+			}
+			if value := parsed.String("expiry-warning"); value != "" {
+				age, err := config.ParseAge(value)
+				if err != nil {
+					return usageError(i18n.Errorf(i18n.MsgInvalidFlag, "expiry-warning", err))
+				}
+				expiryWarning = age
+			}
+			if value := parsed.String("mtime"); value != "" {
+				if err := config.ValidateMtime(value); err != nil {
+					return usageError(i18n.Errorf(i18n.MsgInvalidFlag, "mtime", err))
+				}
+			}
+			if value := parsed.String("interval"); value != "" {
+				age, err := config.ParseAge(value)
+				if err != nil {
+					return usageError(i18n.Errorf(i18n.MsgInvalidFlag, "interval", err))
+				}
+				interval = age
+			}
+			if parsed.Bool("ci-export") {
+				platform = ciplatform.Detect(ctx.deps.Getenv)
+				if platform == "" {
+					return usageError(i18n.Errorf(i18n.MsgCIExportNoCI))
+				}
+				if _, err := platform.ExportPath(ctx.deps.Getenv); err != nil {
+					return runtimeError(i18n.Errorf(i18n.MsgCommandError, "--ci-export", err))
 				}
 			}
 			return nil
 		},
+		execute: func(service secretsync.Service, targets []secretsync.MappingTarget) error {
+			if value := parsed.String("mtime"); value != "" {
+				service = service.WithMtime(value)
+			}
+			if revision != 0 {
+				targets[0].Entry.Revision = revision
+			}
+			results, err := service.Pull(targets, parsed.Bool("overwrite"))
+			if err := printPulled(ctx, parsed, results, expiryWarning, ""); err != nil {
+				return err
+			}
+			if !parsed.Bool("dry-run") {
+				// Files written before a failure expire like the others.
+				if err := warnExpiryNotRecorded(parsed, recordPull(ctx.deps, loaded, expireAfter, results)); err != nil {
+					return err
+				}
+			}
+			if err != nil {
+				return err
+			}
+			if parsed.Bool("watch") {
+				return watchPull(ctx, parsed, service, loaded, targets, results, interval, expiryWarning, expireAfter)
+			}
+			if platform == "" {
+				return nil
+			}
+			return exportToCI(ctx, service, platform, targets)
+		},
+	})
+}
+
+const (
+	defaultExpiryWarning = 30 * 24 * time.Hour
+	defaultWatchInterval = 30 * time.Second
+)
+
+// printPulled prints one line per pulled file, each starting with prefix,
+// then warns about the certificates that expire soon.
+func printPulled(ctx commandContext, parsed *parsedCommand, results []secretsync.PullResult, expiryWarning time.Duration, prefix string) error {
+	var warnings []error
+	for _, item := range results {
+		expires := ""
+		if !item.Expires.IsZero() {
+			expires = " expires=" + item.Expires.Format(time.DateOnly)
+			if warning := certificateExpiryWarning(item.Name, item.Expires, ctx.deps.Now(), expiryWarning); warning != nil {
+				warnings = append(warnings, warning)
+			}
+		}
+		verb, change := "pulled", ""
+		if item.Change != "" {
+			verb, change = "would pull", " file="+string(item.Change)
+		}
+		if _, err := fmt.Fprintf(ctx.stdout, "%s%s %s -> %s (rev=%d type=%s%s%s)\n", prefix, verb, item.Name, item.File, item.Revision, item.Type, expires, change); err != nil {
+			return outputError(err)
+		}
+	}
+	if err := parsed.warnings.warnAll(warningCertExpiry, warnings); err != nil {
+		return outputError(err)
+	}
+	return nil
+}
+
+// notPulled names the targets that have no result.
+func notPulled(targets []secretsync.MappingTarget, results []secretsync.PullResult) []string {
+	done := make(map[string]bool, len(results))
+	for _, item := range results {
+		done[item.Name] = true
+	}
+	var names []string
+	for _, target := range targets {
+		if !done[target.Name] {
+			names = append(names, target.Name)
+		}
+	}
+	return names
+}
+
+// watchPull polls the provider every interval after the first pull and pulls
+// again the targets whose secret got a new enabled revision, until a signal
+// stops it. A failed poll is a warning, not the end of a session that may run
+// for hours; an update in progress always finishes before the watch stops.
+func watchPull(ctx commandContext, parsed *parsedCommand, service secretsync.Service, loaded *config.Loaded, targets []secretsync.MappingTarget, results []secretsync.PullResult, interval, expiryWarning, expireAfter time.Duration) error {
+	pulled := make(map[string]uint32, len(results))
+	for _, item := range results {
+		pulled[item.Name] = item.Revision
+	}
+	watched := make(map[string]bool, len(targets))
+	for _, target := range targets {
+		watched[target.Name] = true
+	}
+	expiredWarned := make(map[string]bool)
+	if _, err := fmt.Fprintln(ctx.stderr, parsed.msg.Sprintf(i18n.MsgWatching, len(targets), interval)); err != nil {
+		return outputError(err)
+	}
+	update := service.WithInterrupt(nil)
+	for service.Wait(interval) {
+		changed, err := update.Changed(targets, pulled)
+		reachable := err == nil
+		var updated []secretsync.PullResult
+		if reachable && len(changed) > 0 {
+			updated, err = update.Pull(changed, true)
+			for _, item := range updated {
+				pulled[item.Name] = item.Revision
+				delete(expiredWarned, item.Name)
+			}
+			if err := printPulled(ctx, parsed, updated, expiryWarning, ctx.deps.Now().Format(time.RFC3339)+" "); err != nil {
+				return err
+			}
+			if err := warnExpiryNotRecorded(parsed, recordPull(ctx.deps, loaded, expireAfter, updated)); err != nil {
+				return err
+			}
+		}
+		// The changed secrets the update did not pull are the drift prompt shows.
+		recordRemoteCheck(ctx.deps, loaded, reachable, notPulled(changed, updated))
+		// An unchanged secret is not pulled again, so its file can expire
+		// during the watch; it is flagged once until it gets pulled again.
+		for _, file := range expiredFiles(ctx.deps, loaded, service) {
+			if !watched[file.Name] || expiredWarned[file.Name] {
+				continue
+			}
+			expiredWarned[file.Name] = true
+			if err := parsed.warnings.warnError(warningExpiry, i18n.Errorf(i18n.MsgWarnExpired, file.File, file.Name)); err != nil {
+				return outputError(err)
+			}
+		}
+		if err != nil {
+			if err := parsed.warnings.warnError(warningWatch, i18n.Errorf(i18n.MsgWarnRetrying, err, interval)); err != nil {
+				return outputError(err)
+			}
+		}
+	}
+	return i18n.Errorf(i18n.MsgWatchStopped, secretsync.ErrInterrupted)
+}
+
+// certificateExpiryWarning describes a certificate that has expired or will
+// within window, and returns nil otherwise.
+func certificateExpiryWarning(name string, expires, now time.Time, window time.Duration) error {
+	left := expires.Sub(now)
+	switch {
+	case left <= 0:
+		return i18n.Errorf(i18n.MsgWarnCertExpired, name, expires.Format(time.DateOnly))
+	case left <= window:
+		return i18n.Errorf(i18n.MsgWarnCertExpires, name, int(left.Hours()/24), expires.Format(time.DateOnly))
+	}
+	return nil
+}
+
+// exportToCI hands the pulled dotenv variables to later CI steps. Only the
+// variable count and destination are printed.
+func exportToCI(ctx commandContext, service secretsync.Service, platform ciplatform.Platform, targets []secretsync.MappingTarget) error {
+	values, err := service.DotenvValues(targets)
+	if err != nil {
+		return runtimeError(err)
+	}
+	path, err := platform.Export(ctx.deps.Getenv, values)
+	if err != nil {
+		return runtimeError(i18n.Errorf(i18n.MsgCommandError, "--ci-export", err))
+	}
+	if _, err := fmt.Fprintf(ctx.stdout, "exported %d variables for %s -> %s\n", len(values), platform, path); err != nil {
+		return outputError(err)
+	}
+	return nil
+}
+
+// runPullFake writes placeholder dotenv files from the manifest alone; the
+// provider is never opened.
+func runPullFake(ctx commandContext, parsed *parsedCommand) int {
+	return newCommandRuntime(ctx, parsed).executeLocal(func(loaded *config.Loaded, service secretsync.Service) error {
+		all := parsed.Bool("all")
+		selected, err := selectMappingTargetsForMode(loaded.Cfg.Mapping, all, parsed.fs.Args(), commandModePull)
+		if err != nil {
+			return err
+		}
+		var targets []secretsync.MappingTarget
+		for _, target := range selected {
+			switch {
+			case target.Entry.Format == secretsync.MappingFormatDotenv && (!all || service.HasFakeKeys(target)):
+				targets = append(targets, target)
+			case !all:
+				return usageError(i18n.Errorf(i18n.MsgFormatMustBeDotenv, "pull --fake", target.Name))
+			}
+		}
+		if len(targets) == 0 {
+			return usageError(i18n.Errorf(i18n.MsgFakeNoMappings))
+		}
+		results, err := service.PullFake(targets, parsed.Bool("overwrite"))
+		for _, item := range results {
+			if _, err := fmt.Fprintf(ctx.stdout, "pulled %s -> %s (fake values)\n", item.Name, item.File); err != nil {
+				return outputError(err)
+			}
+		}
+		return err
 	})
 }
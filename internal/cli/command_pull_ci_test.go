@@ -0,0 +1,81 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bsmartlabs/dev-vault/internal/config"
+	secret "github.com/scaleway/scaleway-sdk-go/api/secret/v1beta1"
+)
+
+func TestRunPull_CIExport(t *testing.T) {
+	dir := t.TempDir()
+	cfgPath := writeConfig(t, dir, `{"organization_id":"org","project_id":"proj","region":"fr-par","mapping":{
+		"app-env-dev":{"file":".env","format":"dotenv"},
+		"tls-dev":{"file":"tls.pem","format":"raw"},
+		"dup-env-dev":{"file":"dup.env","format":"dotenv"}
+	}}`)
+	api := newFakeSecretAPI()
+	app := api.AddSecret("proj", "app-env-dev", "/", secret.SecretTypeKeyValue)
+	api.AddEnabledVersion(app.ID, []byte(`{"A":"1","B":"two\nlines"}`))
+	tls := api.AddSecret("proj", "tls-dev", "/", secret.SecretTypeOpaque)
+	api.AddEnabledVersion(tls.ID, []byte("pem"))
+	dup := api.AddSecret("proj", "dup-env-dev", "/", secret.SecretTypeKeyValue)
+	api.AddEnabledVersion(dup.ID, []byte(`{"A":"2"}`))
+
+	env := map[string]string{}
+	deps := baseDeps(func(config.Config, string) (SecretAPI, error) { return api, nil })
+	deps.Getenv = func(k string) string { return env[k] }
+	run := func(stdout *bytes.Buffer, args ...string) (int, string) {
+		var errBuf bytes.Buffer
+		code := Run(append([]string{"dev-vault", "--config", cfgPath, "pull", "--overwrite", "--ci-export"}, args...), stdout, &errBuf, deps)
+		return code, errBuf.String()
+	}
+
+	var out bytes.Buffer
+	if code, errOut := run(&out, "app-env-dev"); code != 2 || !strings.Contains(errOut, "--ci-export needs a CI environment") {
+		t.Fatalf("expected usage error outside CI: %d %q", code, errOut)
+	}
+
+	env["GITHUB_ACTIONS"] = "true"
+	if code, errOut := run(&out, "app-env-dev"); code != 1 || !strings.Contains(errOut, "--ci-export: github-actions: GITHUB_ENV is not set") {
+		t.Fatalf("expected missing GITHUB_ENV: %d %q", code, errOut)
+	}
+
+	ghEnv := filepath.Join(dir, "github_env")
+	env["GITHUB_ENV"] = ghEnv
+	out.Reset()
+	if code, errOut := run(&out, "app-env-dev", "tls-dev"); code != 0 {
+		t.Fatalf("expected success: %d %q", code, errOut)
+	}
+	if !strings.HasSuffix(out.String(), "exported 2 variables for github-actions -> "+ghEnv+"\n") || strings.Contains(out.String(), "lines") {
+		t.Fatalf("unexpected stdout: %q", out.String())
+	}
+	if raw, _ := os.ReadFile(ghEnv); string(raw) != "A=1\nB<<DEV_VAULT_EOF\ntwo\nlines\nDEV_VAULT_EOF\n" {
+		t.Fatalf("unexpected GITHUB_ENV: %q", raw)
+	}
+
+	delete(env, "GITHUB_ACTIONS")
+	env["GITLAB_CI"] = "true"
+	env["CI_PROJECT_DIR"] = dir
+	if code, errOut := run(&out, "app-env-dev"); code != 1 || !strings.Contains(errOut, "B has a multi-line value") {
+		t.Fatalf("expected gitlab render error: %d %q", code, errOut)
+	}
+	if code, errOut := run(&out, "app-env-dev", "dup-env-dev"); code != 1 || !strings.Contains(errOut, "key A is set by both") {
+		t.Fatalf("expected duplicate key error: %d %q", code, errOut)
+	}
+	if code, _ := run(&bytes.Buffer{}, "dup-env-dev"); code != 0 {
+		t.Fatalf("expected gitlab export success, got %d", code)
+	}
+	if raw, _ := os.ReadFile(filepath.Join(dir, "dev-vault.env")); string(raw) != "A=2\n" {
+		t.Fatalf("unexpected gitlab report: %q", raw)
+	}
+	// The pull line is the first write; only the export line fails.
+	var errBuf bytes.Buffer
+	if code := Run([]string{"dev-vault", "--config", cfgPath, "pull", "--overwrite", "--ci-export", "dup-env-dev"}, &failAfterWriter{okWrites: 1}, &errBuf, deps); code != 1 {
+		t.Fatalf("expected output error, got %d", code)
+	}
+}
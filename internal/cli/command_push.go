@@ -2,7 +2,10 @@ package cli
 
 import (
 	"fmt"
+	"strconv"
 
+	"github.com/bsmartlabs/dev-vault/internal/config"
+	"github.com/bsmartlabs/dev-vault/internal/i18n"
 	"github.com/bsmartlabs/dev-vault/internal/secretsync"
 )
 
@@ -11,10 +14,16 @@ var pushCommandDef = commandDef{
 	Summary: "Push local files as new secret versions",
 	Flags: []commandFlagDef{
 		{Name: "all", Kind: commandFlagBool, Help: "Push all mapping entries with mode push|both (mode defaults to both)"},
-		{Name: "yes", Kind: commandFlagBool, Help: "Confirm batch push (required when pushing more than one secret)"},
+		{Name: "yes", Kind: commandFlagBool, Help: "Confirm batch push (asked on a terminal, required elsewhere when pushing more than one secret)"},
 		{Name: "disable-previous", Kind: commandFlagBool, Help: "Disable previous enabled version when creating a new version"},
 		{Name: "description", Kind: commandFlagString, ValueName: "<text>", Help: "Description for the new version (optional)"},
+		{Name: "coerce", Kind: commandFlagBool, Help: coerceFlagHelp},
 		{Name: "create-missing", Kind: commandFlagBool, Help: "Create missing secrets (requires mapping.type)"},
+		{Name: "resume", Kind: commandFlagBool, Help: resumeFlagHelp},
+		{Name: "policy-file", Kind: commandFlagString, ValueName: "<path>", Help: policyFileFlagHelp},
+		{Name: "disable-older-than", Kind: commandFlagString, ValueName: "<age>", Help: "After pushing, disable enabled versions older than <age> (e.g. 30d)"},
+		{Name: "keep-enabled", Kind: commandFlagString, ValueName: "<n>", Help: "After pushing, disable all but the <n> newest enabled versions"},
+		{Name: "dry-run", Kind: commandFlagBool, Help: "Read and convert the files and print what would be pushed, without changing anything"},
 	},
 	Doc: commandDoc{
 		Synopsis: "dev-vault [--config <path>] [--profile <name>] push (--all | <secret-dev> ...) [options]",
@@ -26,17 +35,40 @@ var pushCommandDef = commandDef{
 			"Formats:",
 			"  - mapping.format=raw reads file bytes as-is.",
 			"  - mapping.format=dotenv reads a .env file and uploads a JSON payload.",
+			"  - mapping.format=yaml reads a YAML mapping and uploads a JSON payload.",
+			"  - mapping.format=toml reads a TOML document and uploads a JSON payload.",
+			"  - mapping.format=json uploads the file's JSON compacted.",
+			"  - mapping.format=template mappings are pull-only and cannot be pushed.",
+			"  - mapping.files reads each file back into its key; keys it does not list are kept.",
 		},
 		Notes: []string{
 			"--create-missing creates the secret if absent (requires mapping.type).",
 			"Secret creation uses mapping.path (default '/').",
-			"If more than one secret is being pushed, you must pass --yes.",
+			"If more than one secret is being pushed, push lists them and asks for confirmation on a",
+			"terminal; without one (CI, pipes) you must pass --yes.",
+			"A secret whose type differs from mapping.type is refused unless --coerce is set; the payload",
+			"must then suit the secret's type (anything for opaque, a JSON object for key_value,",
+			"basic_credentials, and database_credentials).",
+			"Policy rules (payload size, forbidden key names, required tags) are checked before any",
+			"version is created: level=warn prints warnings, level=enforce aborts the push.",
+			"A policy.rego hook is evaluated with `opa eval` on metadata only; any deny reason aborts the push.",
+			"Completed targets are recorded in the user state dir; --resume skips them after an",
+			"interrupted or failed run. A fully successful run clears the record.",
+			"--disable-older-than and --keep-enabled disable older versions of each pushed secret once",
+			"its new version exists; the new version always stays enabled. With both, a version either",
+			"one selects is disabled. Disabled versions stay listed and can be enabled again.",
+			"--dry-run reads, converts, and checks every file, resolves the secrets, and prints",
+			"'would push', 'would create', and 'would disable' lines; no secret, version, or shared",
+			"state is written, and no --resume progress is recorded.",
 		},
 		Examples: []string{
 			"dev-vault push bweb-env-bsmart-dev",
 			"dev-vault push bweb-env-bsmart-dev --description 'local refresh'",
 			"dev-vault push --all --yes",
 			"dev-vault push --config .scw.json --all --yes --disable-previous",
+			"dev-vault push --all --yes --disable-older-than 30d",
+			"dev-vault push bweb-env-bsmart-dev --keep-enabled 3",
+			"dev-vault push --all --yes --create-missing --dry-run",
 		},
 	},
 	RunParsed: runPushParsed,
@@ -47,30 +79,126 @@ func runPush(ctx commandContext, argv []string) int {
 }
 
 func runPushParsed(ctx commandContext, parsed *parsedCommand) int {
+	var retention secretsync.RetentionOptions
 	return newCommandRuntime(ctx, parsed).executeMapping(mappingCommandSpec{
-		mode: commandModePush,
-		all:  parsed.Bool("all"),
+		mode:   commandModePush,
+		all:    parsed.Bool("all"),
+		resume: parsed.Bool("resume"),
+		coerce: parsed.Bool("coerce"),
+		dryRun: parsed.Bool("dry-run"),
 		preflight: func(targets []secretsync.MappingTarget) error {
-			if len(targets) > 1 && !parsed.Bool("yes") {
-				return usageError(fmt.Errorf("refusing to push multiple secrets without --yes"))
+			if len(targets) > 1 && !parsed.Bool("yes") && !parsed.Bool("dry-run") {
+				if !stdinIsTerminal(ctx) {
+					return usageError(i18n.Errorf(i18n.MsgPushNeedsYes))
+				}
+				if !confirmBatchPush(ctx, targets) {
+					return usageError(i18n.Errorf(i18n.MsgNotConfirmedAnswer, "push"))
+				}
+			}
+			if value := parsed.String("disable-older-than"); value != "" {
+				age, err := config.ParseAge(value)
+				if err != nil {
+					return usageError(i18n.Errorf(i18n.MsgInvalidFlag, "disable-older-than", err))
+				}
+				retention.OlderThan = age
+			}
+			if value := parsed.String("keep-enabled"); value != "" {
+				n, err := strconv.ParseUint(value, 10, 32)
+				if err != nil || n == 0 {
+					return usageError(i18n.Errorf(i18n.MsgInvalidKeepEnabled, value))
+				}
+				retention.KeepEnabled = int(n)
 			}
 			return nil
 		},
+		checkPolicy: true,
 		execute: func(service secretsync.Service, targets []secretsync.MappingTarget) error {
 			results, err := service.Push(targets, secretsync.PushOptions{
 				Description:     parsed.String("description"),
 				DisablePrevious: parsed.Bool("disable-previous"),
 				CreateMissing:   parsed.Bool("create-missing"),
 			})
-			if err != nil {
-				return err
-			}
 			for _, item := range results {
-				if _, err := fmt.Fprintf(ctx.stdout, "pushed %s (rev=%d)\n", item.Name, item.Revision); err != nil {
-					return outputError(err)
+				if err := printPushed(ctx, parsed, item); err != nil {
+					return err
 				}
 			}
-			return nil
+			if stateErr := recordSharedState(parsed, service, results); stateErr != nil {
+				return stateErr
+			}
+			if retention.Enabled() {
+				if retainErr := retainPushed(ctx, service, targets, results, retention, parsed.Bool("dry-run")); retainErr != nil {
+					return retainErr
+				}
+			}
+			return err
 		},
 	})
 }
+
+// printPushed reports one pushed secret, or what a dry run would push.
+func printPushed(ctx commandContext, parsed *parsedCommand, item secretsync.PushResult) error {
+	var err error
+	switch {
+	case !parsed.Bool("dry-run"):
+		_, err = fmt.Fprintf(ctx.stdout, "pushed %s (rev=%d)\n", item.Name, item.Revision)
+	case item.Create:
+		_, err = fmt.Fprintf(ctx.stdout, "would create %s and push its first version\n", item.Name)
+	case parsed.Bool("disable-previous"):
+		_, err = fmt.Fprintf(ctx.stdout, "would push %s and disable its previous version\n", item.Name)
+	default:
+		_, err = fmt.Fprintf(ctx.stdout, "would push %s\n", item.Name)
+	}
+	if err != nil {
+		return outputError(err)
+	}
+	return nil
+}
+
+// retainPushed disables the versions retention selects for each pushed
+// secret, printing every disabled revision. A dry run prints the revisions it
+// would disable; a secret it would create has none.
+func retainPushed(ctx commandContext, service secretsync.Service, targets []secretsync.MappingTarget, results []secretsync.PushResult, retention secretsync.RetentionOptions, dryRun bool) error {
+	byName := make(map[string]secretsync.MappingTarget, len(targets))
+	for _, target := range targets {
+		byName[target.Name] = target
+	}
+	verb := "disabled"
+	if dryRun {
+		verb = "would disable"
+	}
+	for _, item := range results {
+		if item.Create {
+			continue
+		}
+		revisions, err := service.Retain(byName[item.Name], item.Revision, retention)
+		for _, revision := range revisions {
+			if _, err := fmt.Fprintf(ctx.stdout, "%s %s (rev=%d)\n", verb, item.Name, revision); err != nil {
+				return outputError(err)
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// stdinIsTerminal reports whether a confirmation can be asked instead of
+// requiring --yes.
+func stdinIsTerminal(ctx commandContext) bool {
+	return ctx.deps.Stdin != nil && ctx.deps.StdinIsTerminal != nil && ctx.deps.StdinIsTerminal()
+}
+
+// confirmBatchPush lists the secrets about to get a new version and asks
+// whether to push them.
+func confirmBatchPush(ctx commandContext, targets []secretsync.MappingTarget) bool {
+	lines := fmt.Sprintf("About to push %d secrets:\n", len(targets))
+	for _, target := range targets {
+		lines += "  " + target.Name + "\n"
+	}
+	if _, err := fmt.Fprint(ctx.stderr, lines); err != nil {
+		return false
+	}
+	return confirm(ctx, "Push them? [y/N] ")
+}
@@ -0,0 +1,163 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/bsmartlabs/dev-vault/internal/config"
+	"github.com/bsmartlabs/dev-vault/internal/policy"
+	"github.com/bsmartlabs/dev-vault/internal/report"
+	"github.com/bsmartlabs/dev-vault/internal/secretsync"
+)
+
+var reportCommandDef = commandDef{
+	Name:    "report",
+	Summary: "Generate a markdown or HTML inventory report",
+	Flags: []commandFlagDef{
+		{Name: "format", Kind: commandFlagString, ValueName: "<markdown|html>", Help: "Report format (default: markdown)"},
+		{Name: "policy-file", Kind: commandFlagString, ValueName: "<path>", Help: policyFileFlagHelp},
+	},
+	Doc: commandDoc{
+		Synopsis: "dev-vault [--config <path>] report [--format <markdown|html>] [--policy-file <path>]",
+		Description: []string{
+			"Writes a shareable inventory of the project's dev secrets to stdout:",
+			"mapping coverage, local/remote drift, last update age of each secret,",
+			"remote dev secrets that no mapping entry covers, and policy violations",
+			"(max_secret_age and required_tags, evaluated against remote metadata).",
+		},
+		Notes: []string{
+			"Payloads are read only to compare them with local files and are never printed.",
+			"HTML output is a single self-contained page (inline CSS, no external assets).",
+			"Last updated is the Scaleway secret update time, which moves on every new version.",
+			"Owner comes from an owner:<team> or owner=<team> tag on the secret.",
+		},
+		Examples: []string{
+			"dev-vault report > secrets-report.md",
+			"dev-vault report --format html > secrets-report.html",
+		},
+	},
+	RunParsed: runReportParsed,
+}
+
+const (
+	reportStatusPresent = "present"
+	reportStatusMissing = "missing"
+	reportDriftInSync   = "in sync"
+)
+
+func runReport(ctx commandContext, argv []string) int {
+	return runCommand(ctx, argv, reportCommandDef)
+}
+
+func runReportParsed(ctx commandContext, parsed *parsedCommand) int {
+	rt := newCommandRuntime(ctx, parsed)
+	return rt.execute(func(loaded *config.Loaded, service secretsync.Service) error {
+		formatValue := parsed.String("format")
+		if formatValue == "" {
+			formatValue = string(report.FormatMarkdown)
+		}
+		format, err := report.ParseFormat(formatValue)
+		if err != nil {
+			return usageError(err)
+		}
+
+		active, rules, err := rt.policyRules(loaded)
+		if err != nil {
+			return err
+		}
+		inv, err := service.Inventory()
+		if err != nil {
+			return runtimeError(err)
+		}
+		statuses, err := service.MappingStatuses()
+		if err != nil {
+			return runtimeError(err)
+		}
+		doc := reportDocument(loaded.Cfg, inv, statuses, ctx.deps.Now())
+		if active != nil {
+			doc.PolicyLevel = string(active.Level)
+			doc.Violations = reportViolations(inv, rules, ctx.deps.Now())
+		}
+		if err := report.Render(ctx.stdout, format, doc); err != nil {
+			return outputError(err)
+		}
+		return nil
+	})
+}
+
+// reportDocument builds the report from the inventory and the mapping
+// statuses, which both list the enabled mapping entries in the same order.
+func reportDocument(cfg config.Config, inv secretsync.Inventory, statuses []secretsync.MappingStatus, now time.Time) report.Document {
+	doc := report.Document{
+		ProjectID:   cfg.ProjectID,
+		Region:      cfg.Region,
+		GeneratedAt: now.UTC().Format(time.RFC3339),
+		Summary: report.Summary{
+			Mapped:   len(inv.Entries),
+			Unmapped: len(inv.Unmapped),
+		},
+	}
+	for i, entry := range inv.Entries {
+		row := report.Row{
+			Name:        entry.Name,
+			File:        entry.File,
+			Owner:       ownerTag(entry.Tags),
+			Remote:      reportStatusMissing,
+			Local:       reportStatusMissing,
+			LastUpdated: "-",
+		}
+		if entry.Remote {
+			doc.Summary.Remote++
+			row.Remote = reportStatusPresent
+			if !entry.UpdatedAt.IsZero() {
+				row.LastUpdated = fmt.Sprintf("%dd", int(now.Sub(entry.UpdatedAt).Hours()/24))
+			}
+		}
+		if entry.Local {
+			doc.Summary.Local++
+			row.Local = reportStatusPresent
+		}
+		if row.Owner == "" {
+			row.Owner = "-"
+		}
+		switch {
+		case entry.Remote && entry.Local:
+			row.Drift = reportDrift(statuses[i].InSync)
+			if row.Drift == reportDriftInSync {
+				doc.Summary.InSync++
+			}
+		case entry.Remote:
+			row.Drift = "not pulled"
+		case entry.Local:
+			row.Drift = "not pushed"
+		default:
+			row.Drift = "missing"
+		}
+		doc.Rows = append(doc.Rows, row)
+	}
+	for _, record := range inv.Unmapped {
+		doc.Unmapped = append(doc.Unmapped, report.UnmappedRow{Name: record.Name, Path: record.Path, Type: record.Type})
+	}
+	return doc
+}
+
+// reportDrift tells whether a file present on both sides matches the latest
+// version; "unknown" when that version cannot be rendered for the mapping.
+func reportDrift(inSync *bool) string {
+	switch {
+	case inSync == nil:
+		return "unknown"
+	case *inSync:
+		return reportDriftInSync
+	default:
+		return "differs"
+	}
+}
+
+func reportViolations(inv secretsync.Inventory, rules policy.Rules, now time.Time) []report.ViolationRow {
+	var rows []report.ViolationRow
+	for _, violation := range inventoryViolations(inv, rules, now) {
+		rows = append(rows, report.ViolationRow{Secret: violation.Secret, Rule: violation.Rule, Detail: violation.Detail})
+	}
+	return rows
+}
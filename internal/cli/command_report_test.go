@@ -0,0 +1,151 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bsmartlabs/dev-vault/internal/config"
+	secret "github.com/scaleway/scaleway-sdk-go/api/secret/v1beta1"
+)
+
+func TestRunReport(t *testing.T) {
+	root := t.TempDir()
+	cfgPath := writeConfig(t, root, `{"organization_id":"org","project_id":"proj","region":"fr-par","mapping":{
+		"synced-dev":{"file":"synced.env","format":"dotenv"},
+		"drifted-dev":{"file":"drifted.env","format":"dotenv"},
+		"remote-dev":{"file":"remote.env"},
+		"local-dev":{"file":"local.env"},
+		"gone-dev":{"file":"gone.env"}
+	}}`)
+	for name, body := range map[string]string{"synced.env": "# managed by dev-vault: synced-dev\nA=\"1\"\n", "drifted.env": "A=1\n", "local.env": "A=1\n"} {
+		if err := os.WriteFile(filepath.Join(root, name), []byte(body), 0o600); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+	now := time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC)
+	api := newFakeSecretAPI()
+	synced := api.AddSecret("proj", "synced-dev", "/", secret.SecretTypeKeyValue)
+	synced.UpdatedAt, synced.Tags = now.Add(-10*24*time.Hour), []string{"owner:web"}
+	api.AddEnabledVersion(synced.ID, []byte(`{"A":"1"}`))
+	drifted := api.AddSecret("proj", "drifted-dev", "/", secret.SecretTypeKeyValue)
+	api.AddEnabledVersion(drifted.ID, []byte(`{"A":"2"}`))
+	api.AddEnabledVersion(api.AddSecret("proj", "remote-dev", "/", secret.SecretTypeKeyValue).ID, []byte(`{"A":"1"}`))
+	api.AddSecret("proj", "orphan-dev", "/", secret.SecretTypeOpaque)
+	deps := baseDeps(func(cfg config.Config, s string) (SecretAPI, error) { return api, nil })
+	deps.Now = func() time.Time { return now }
+
+	t.Run("MarkdownDefault", func(t *testing.T) {
+		var out, errBuf bytes.Buffer
+		code := Run([]string{"dev-vault", "--config", cfgPath, "report"}, &out, &errBuf, deps)
+		if code != 0 {
+			t.Fatalf("expected 0, got %d stderr=%s", code, errBuf.String())
+		}
+		got := out.String()
+		for _, want := range []string{
+			"- Project: `proj`",
+			"- Generated: 2024-03-11T00:00:00Z",
+			"| 5 | 3 | 3 | 1 | 1 |",
+			"| drifted-dev | drifted.env | - | present | present | differs | - |",
+			"| gone-dev | gone.env | - | missing | missing | missing | - |",
+			"| local-dev | local.env | - | missing | present | not pushed | - |",
+			"| remote-dev | remote.env | - | present | missing | not pulled | - |",
+			"| synced-dev | synced.env | web | present | present | in sync | 10d |",
+			"| orphan-dev | / | opaque |",
+			"No policy configured.",
+		} {
+			if !strings.Contains(got, want) {
+				t.Fatalf("missing %q in:\n%s", want, got)
+			}
+		}
+		if strings.Contains(got, "## Notes") {
+			t.Fatalf("expected no notes:\n%s", got)
+		}
+		if got := reportDrift(nil); got != "unknown" {
+			t.Fatalf("expected unknown drift for an unrenderable version, got %q", got)
+		}
+	})
+
+	t.Run("HTML", func(t *testing.T) {
+		var out, errBuf bytes.Buffer
+		code := Run([]string{"dev-vault", "--config", cfgPath, "report", "--format", "html"}, &out, &errBuf, deps)
+		if code != 0 {
+			t.Fatalf("expected 0, got %d stderr=%s", code, errBuf.String())
+		}
+		if !strings.HasPrefix(out.String(), "<!DOCTYPE html>") || !strings.Contains(out.String(), "<td>synced-dev</td>") {
+			t.Fatalf("unexpected html: %s", out.String())
+		}
+	})
+
+	t.Run("PolicyViolations", func(t *testing.T) {
+		policyPath := filepath.Join(t.TempDir(), "policy.json")
+		if err := os.WriteFile(policyPath, []byte(`{"max_secret_age":"7d","required_tags":["owner"]}`), 0o600); err != nil {
+			t.Fatalf("write policy: %v", err)
+		}
+		var out, errBuf bytes.Buffer
+		code := Run([]string{"dev-vault", "--config", cfgPath, "report", "--policy-file", policyPath}, &out, &errBuf, deps)
+		if code != 0 {
+			t.Fatalf("expected 0, got %d stderr=%s", code, errBuf.String())
+		}
+		got := out.String()
+		for _, want := range []string{
+			"Level: warn",
+			"| remote-dev | required_tags | missing tags: owner |",
+			"| synced-dev | max_secret_age | last updated 10d ago, limit is 7d |",
+		} {
+			if !strings.Contains(got, want) {
+				t.Fatalf("missing %q in:\n%s", want, got)
+			}
+		}
+		if strings.Contains(got, "| gone-dev | required_tags") || strings.Contains(got, "| synced-dev | required_tags") {
+			t.Fatalf("expected secrets absent remotely to be skipped:\n%s", got)
+		}
+
+		errBuf.Reset()
+		code = Run([]string{"dev-vault", "--config", cfgPath, "report", "--policy-file", filepath.Join(t.TempDir(), "missing.json")}, &out, &errBuf, deps)
+		if code != 1 || !strings.Contains(errBuf.String(), "read policy file") {
+			t.Fatalf("expected policy error, got %d stderr=%s", code, errBuf.String())
+		}
+	})
+
+	t.Run("InvalidFormat", func(t *testing.T) {
+		var out, errBuf bytes.Buffer
+		code := Run([]string{"dev-vault", "--config", cfgPath, "report", "--format", "pdf"}, &out, &errBuf, deps)
+		if code != 2 || !strings.Contains(errBuf.String(), "invalid report format") {
+			t.Fatalf("expected usage error, got %d stderr=%s", code, errBuf.String())
+		}
+	})
+
+	t.Run("ListError", func(t *testing.T) {
+		failing := newFakeSecretAPI()
+		failing.listErr = errors.New("boom")
+		failDeps := baseDeps(func(cfg config.Config, s string) (SecretAPI, error) { return failing, nil })
+		var out, errBuf bytes.Buffer
+		code := Run([]string{"dev-vault", "--config", cfgPath, "report"}, &out, &errBuf, failDeps)
+		if code != 1 || !strings.Contains(errBuf.String(), "boom") {
+			t.Fatalf("expected runtime error, got %d stderr=%s", code, errBuf.String())
+		}
+	})
+
+	t.Run("StatusError", func(t *testing.T) {
+		api.accessErr = errors.New("access boom")
+		defer func() { api.accessErr = nil }()
+		var out, errBuf bytes.Buffer
+		code := Run([]string{"dev-vault", "--config", cfgPath, "report"}, &out, &errBuf, deps)
+		if code != 1 || !strings.Contains(errBuf.String(), "access boom") {
+			t.Fatalf("expected runtime error, got %d stderr=%s", code, errBuf.String())
+		}
+	})
+
+	t.Run("WriteError", func(t *testing.T) {
+		var errBuf bytes.Buffer
+		code := runReport(commandContext{stdout: &failingWriter{}, stderr: &errBuf, configPath: cfgPath, deps: deps}, nil)
+		if code != 1 {
+			t.Fatalf("expected 1, got %d stderr=%s", code, errBuf.String())
+		}
+	})
+}
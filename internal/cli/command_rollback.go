@@ -0,0 +1,110 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/bsmartlabs/dev-vault/internal/config"
+	"github.com/bsmartlabs/dev-vault/internal/i18n"
+	"github.com/bsmartlabs/dev-vault/internal/secretsync"
+)
+
+var rollbackCommandDef = commandDef{
+	Name:    "rollback",
+	Summary: "Restore a previous version of a mapped secret as its latest version",
+	Flags: []commandFlagDef{
+		{Name: "to-revision", Kind: commandFlagString, ValueName: "<n>", Help: "Revision whose payload becomes the new version (required)"},
+		{Name: "yes", Kind: commandFlagBool, Help: "Skip the confirmation prompt"},
+		{Name: "disable-previous", Kind: commandFlagBool, Help: "Disable previous enabled version when creating the new version"},
+		{Name: "description", Kind: commandFlagString, ValueName: "<text>", Help: "Description for the new version (optional)"},
+	},
+	Doc: commandDoc{
+		Synopsis: "dev-vault [--config <path>] [--profile <name>] rollback <secret-dev> --to-revision <n> [options]",
+		Description: []string{
+			"Creates a new version of a mapped secret from the payload of an older revision, so a bad",
+			"push can be undone. Earlier versions are kept; see the versions command for revisions.",
+			"The revision must exist and be enabled. The local file is not changed; pull it with",
+			"--overwrite afterwards.",
+			"Never prints secret payloads.",
+		},
+		Notes: []string{
+			"Asks for confirmation on stdin unless --yes is set; without an answer nothing is written.",
+			"Only mappings with mode push|both can be rolled back.",
+			"--disable-previous disables the bad version, so it is no longer read by default.",
+		},
+		Examples: []string{
+			"dev-vault versions bweb-env-bsmart-dev",
+			"dev-vault rollback bweb-env-bsmart-dev --to-revision 3",
+			"dev-vault rollback bweb-env-bsmart-dev --to-revision 3 --yes --disable-previous",
+		},
+	},
+	RunParsed: runRollbackParsed,
+}
+
+func runRollback(ctx commandContext, argv []string) int {
+	return runCommand(ctx, argv, rollbackCommandDef)
+}
+
+func runRollbackParsed(ctx commandContext, parsed *parsedCommand) int {
+	return newCommandRuntime(ctx, parsed).execute(func(loaded *config.Loaded, service secretsync.Service) error {
+		args := parsed.fs.Args()
+		if len(args) != 1 {
+			return usageError(i18n.Errorf(i18n.MsgTakesOneSecretName, "rollback"))
+		}
+		value := parsed.String("to-revision")
+		revision, err := strconv.ParseUint(value, 10, 32)
+		if err != nil || revision == 0 {
+			return usageError(i18n.Errorf(i18n.MsgNeedsRevisionNumber, "rollback", "to-revision", value))
+		}
+		targets, err := selectMappingTargetsForMode(loaded.Cfg.Mapping, false, args, commandModePush)
+		if err != nil {
+			return err
+		}
+		name := targets[0].Name
+		if !parsed.Bool("yes") && !confirm(ctx, fmt.Sprintf("Create a new version of %s from revision %d? [y/N] ", name, revision)) {
+			return usageError(i18n.Errorf(i18n.MsgNotConfirmedAnswer, "rollback "+name))
+		}
+
+		result, err := service.Rollback(targets[0], uint32(revision), secretsync.PushOptions{
+			Description:     parsed.String("description"),
+			DisablePrevious: parsed.Bool("disable-previous"),
+		})
+		if err != nil {
+			return runtimeError(err)
+		}
+		if _, err := fmt.Fprintf(ctx.stdout, "rolled back %s to rev=%d (new rev=%d)\n", name, revision, result.Revision); err != nil {
+			return outputError(err)
+		}
+		return recordSharedState(parsed, service, []secretsync.PushResult{result})
+	})
+}
+
+// confirm asks question on stderr and reports whether stdin answered yes.
+func confirm(ctx commandContext, question string) bool {
+	switch strings.ToLower(prompt(ctx, question)) {
+	case "y", "yes":
+		return true
+	}
+	return false
+}
+
+// prompt asks question on stderr and returns the line stdin answered without
+// surrounding spaces, or "" when it cannot ask. Stdin is read a byte at a
+// time, so a later prompt gets the next line rather than losing it to a buffer.
+func prompt(ctx commandContext, question string) string {
+	if _, err := fmt.Fprint(ctx.stderr, question); err != nil || ctx.deps.Stdin == nil {
+		return ""
+	}
+	var answer []byte
+	b := make([]byte, 1)
+	for {
+		n, err := ctx.deps.Stdin.Read(b)
+		if n == 1 && b[0] != '\n' {
+			answer = append(answer, b[0])
+		}
+		if err != nil || n == 1 && b[0] == '\n' {
+			return strings.TrimSpace(string(answer))
+		}
+	}
+}
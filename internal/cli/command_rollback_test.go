@@ -0,0 +1,88 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/bsmartlabs/dev-vault/internal/config"
+	secret "github.com/scaleway/scaleway-sdk-go/api/secret/v1beta1"
+)
+
+func TestRunRollback(t *testing.T) {
+	dir := t.TempDir()
+	cfgPath := writeConfig(t, dir, `{"organization_id":"org","project_id":"proj","region":"fr-par","mapping":{
+		"db-dev":{"file":"db.json"},
+		"pull-dev":{"file":"pull.json","mode":"pull"}
+	}}`)
+	api := newFakeSecretAPI()
+	sec := api.AddSecret("proj", "db-dev", "/", secret.SecretTypeOpaque)
+	api.AddEnabledVersion(sec.ID, []byte("good"))
+	api.AddEnabledVersion(sec.ID, []byte("bad"))
+	deps := baseDeps(func(config.Config, string) (SecretAPI, error) { return api, nil })
+	run := func(stdin string, args ...string) (int, string, string) {
+		var out, errBuf bytes.Buffer
+		deps := deps
+		deps.Stdin = strings.NewReader(stdin)
+		code := Run(append([]string{"dev-vault", "--config", cfgPath, "rollback"}, args...), &out, &errBuf, deps)
+		return code, out.String(), errBuf.String()
+	}
+
+	code, out, errOut := run("y\n", "db-dev", "--to-revision", "1", "--disable-previous")
+	if code != 0 || out != "rolled back db-dev to rev=1 (new rev=3)\n" || errOut != "Create a new version of db-dev from revision 1? [y/N] " {
+		t.Fatalf("unexpected rollback: %d %q %q", code, out, errOut)
+	}
+	latest := api.versions[sec.ID][2]
+	if string(latest.data) != "good" || api.versions[sec.ID][1].enabled || *latest.description != "dev-vault rollback to rev=1 1970-01-01T00:02:03Z host" {
+		t.Fatalf("unexpected versions: %#v", api.versions[sec.ID])
+	}
+	if strings.Contains(out+errOut, "good") {
+		t.Fatal("rollback must never print payloads")
+	}
+
+	// --yes skips the prompt; an unanswered or declined prompt writes nothing.
+	if code, out, errOut := run("", "db-dev", "--to-revision", "3", "--yes", "--description", "restore"); code != 0 || out != "rolled back db-dev to rev=3 (new rev=4)\n" || errOut != "" {
+		t.Fatalf("unexpected rollback: %d %q %q", code, out, errOut)
+	}
+	for _, stdin := range []string{"", "n\n", "nope\n"} {
+		if code, _, errOut := run(stdin, "db-dev", "--to-revision", "1"); code != 2 || !strings.Contains(errOut, "rollback db-dev: not confirmed (answer y, or pass --yes)") {
+			t.Fatalf("%q: expected refusal, got %d %q", stdin, code, errOut)
+		}
+	}
+	var errBuf bytes.Buffer
+	if code := Run([]string{"dev-vault", "--config", cfgPath, "rollback", "db-dev", "--to-revision", "1"}, &bytes.Buffer{}, &errBuf, deps); code != 2 {
+		t.Fatalf("expected refusal without stdin, got %d %q", code, errBuf.String())
+	}
+	if code := runRollback(commandContext{stdout: &bytes.Buffer{}, stderr: &failingWriter{}, configPath: cfgPath, deps: deps}, []string{"db-dev", "--to-revision", "1"}); code != 2 {
+		t.Fatalf("expected refusal when the prompt cannot be shown, got %d", code)
+	}
+	if len(api.versions[sec.ID]) != 4 {
+		t.Fatalf("expected no new versions, got %#v", api.versions[sec.ID])
+	}
+
+	for _, tc := range []struct {
+		args    []string
+		code    int
+		wantErr string
+	}{
+		{[]string{"--to-revision", "1"}, 2, "rollback takes exactly one secret name"},
+		{[]string{"db-dev"}, 2, `rollback needs --to-revision with a positive revision number, got ""`},
+		{[]string{"db-dev", "--to-revision", "0"}, 2, "positive revision number"},
+		{[]string{"db", "--to-revision", "1"}, 2, "refusing non-dev secret name"},
+		{[]string{"pull-dev", "--to-revision", "1"}, 2, "not allowed in push mode"},
+		{[]string{"db-dev", "--to-revision", "2", "--yes"}, 1, "rollback db-dev: revision 2 is disabled"},
+	} {
+		if code, _, errOut := run("", tc.args...); code != tc.code || !strings.Contains(errOut, tc.wantErr) {
+			t.Fatalf("%v: expected %d %q, got %d %q", tc.args, tc.code, tc.wantErr, code, errOut)
+		}
+	}
+
+	if code := runRollback(commandContext{stdout: &failingWriter{}, stderr: &bytes.Buffer{}, configPath: cfgPath, deps: deps}, []string{"db-dev", "--to-revision", "1", "--yes"}); code != 1 {
+		t.Fatalf("expected output error, got %d", code)
+	}
+	api.createVerErr = errors.New("create boom")
+	if code, _, errOut := run("", "db-dev", "--to-revision", "1", "--yes"); code != 1 || !strings.Contains(errOut, "rollback db-dev: create version: create boom") {
+		t.Fatalf("expected create error, got %d %q", code, errOut)
+	}
+}
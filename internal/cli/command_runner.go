@@ -3,17 +3,36 @@ package cli
 import (
 	"errors"
 	"flag"
+	"fmt"
+
+	"github.com/bsmartlabs/dev-vault/internal/config"
+	"github.com/bsmartlabs/dev-vault/internal/fsx"
+	"github.com/bsmartlabs/dev-vault/internal/i18n"
 )
 
 type parsedCommand struct {
 	fs              *flag.FlagSet
 	configPath      string
 	profileOverride string
+	environment     string
+	msg             i18n.Localizer
+	plain           bool
+	explainConfig   bool
+	enforceVersion  bool
+	ignoreUnknown   bool
+	logFile         string
+	noFsync         bool
+	warnings        *warningSink
 	boolValues      map[string]bool
 	stringValues    map[string]string
 	sliceValues     map[string][]string
 }
 
+// loadOptions are the global flags that change how the manifest loads.
+func (p *parsedCommand) loadOptions() config.LoadOptions {
+	return config.LoadOptions{IgnoreUnknown: p.ignoreUnknown, Environment: p.environment}
+}
+
 func (p *parsedCommand) Bool(name string) bool {
 	return p.boolValues[name]
 }
@@ -62,15 +81,23 @@ func parseCommandExitCode(err error) (code int, terminal bool) {
 func parseCommand(ctx commandContext, argv []string, def commandDef) (*parsedCommand, error) {
 	fs := flag.NewFlagSet(def.Name, flag.ContinueOnError)
 	fs.SetOutput(ctx.stderr)
-	var usageWriteErr error
-	fs.Usage = func() {
-		usageWriteErr = printCommandUsage(ctx.stderr, def)
+	// Usage is printed below: requested help is a result, a parse error is not.
+	fs.Usage = func() {}
+
+	opts := globalOptions{
+		configPath:      ctx.configPath,
+		profileOverride: ctx.profileOverride,
+		environment:     ctx.environment,
+		lang:            ctx.lang,
+		plain:           ctx.plain,
+		explainConfig:   ctx.explainConfig,
+		enforceVersion:  ctx.enforceVersion,
+		ignoreUnknown:   ctx.ignoreUnknown,
+		strictWarnings:  ctx.strictWarnings,
+		logFile:         ctx.logFile,
+		noFsync:         ctx.noFsync,
 	}
-
-	configPath := ctx.configPath
-	profileOverride := ctx.profileOverride
-
-	bindGlobalOptionFlags(fs, &configPath, &profileOverride)
+	bindGlobalOptionFlags(fs, &opts)
 
 	boolHolders := make(map[string]*bool, len(def.Flags))
 	stringHolders := make(map[string]*string, len(def.Flags))
@@ -93,17 +120,36 @@ func parseCommand(ctx commandContext, argv []string, def commandDef) (*parsedCom
 		}
 	}
 
-	reordered := reorderFlags(argv, withGlobalFlagSpecs(takesValueMap(def)))
+	takesValue := withGlobalFlagSpecs(takesValueMap(def))
+	if def.PassThrough {
+		argv = passThroughArgs(argv, takesValue)
+	}
+	reordered := reorderFlags(argv, takesValue)
 	if err := fs.Parse(reordered); err != nil {
 		if errors.Is(err, flag.ErrHelp) {
-			if usageWriteErr != nil {
-				return nil, &parseCommandError{code: 1, err: outputError(usageWriteErr)}
+			if err := printCommandUsage(ctx.stdout, ctx.msg, def); err != nil {
+				return nil, &parseCommandError{code: 1, err: outputError(err)}
 			}
 			return nil, &parseCommandError{code: 0, err: err}
 		}
+		_ = printCommandUsage(ctx.stderr, ctx.msg, def)
+		return nil, &parseCommandError{code: 2, err: err}
+	}
+	if err := checkFlagConstraints(fs, def.Constraints); err != nil {
+		_, _ = fmt.Fprintln(ctx.stderr, ctx.msg.Error(err))
 		return nil, &parseCommandError{code: 2, err: err}
 	}
 
+	msg := ctx.msg
+	if opts.lang != ctx.lang {
+		lang, err := i18n.ParseLang(opts.lang)
+		if err != nil {
+			_, _ = fmt.Fprintln(ctx.stderr, ctx.msg.Error(err))
+			return nil, &parseCommandError{code: 2, err: err}
+		}
+		msg = i18n.New(lang)
+	}
+
 	boolValues := make(map[string]bool, len(boolHolders))
 	for name, value := range boolHolders {
 		boolValues[name] = *value
@@ -124,24 +170,47 @@ func parseCommand(ctx commandContext, argv []string, def commandDef) (*parsedCom
 
 	return &parsedCommand{
 		fs:              fs,
-		configPath:      configPath,
-		profileOverride: profileOverride,
+		configPath:      opts.configPath,
+		profileOverride: opts.profileOverride,
+		environment:     opts.environment,
+		msg:             msg,
+		plain:           opts.plain,
+		explainConfig:   opts.explainConfig,
+		enforceVersion:  opts.enforceVersion,
+		ignoreUnknown:   opts.ignoreUnknown,
+		logFile:         opts.logFile,
+		noFsync:         opts.noFsync,
+		warnings:        &warningSink{w: ctx.stderr, msg: msg, json: boolValues["json"], strict: opts.strictWarnings},
 		boolValues:      boolValues,
 		stringValues:    stringValues,
 		sliceValues:     sliceValues,
 	}, nil
 }
 
-func runParsedCommand(ctx commandContext, argv []string, def commandDef, run func(parsed *parsedCommand) int) int {
+func runCommand(ctx commandContext, argv []string, def commandDef) int {
 	parsed, parseErr := parseCommand(ctx, argv, def)
 	if code, terminal := parseCommandExitCode(parseErr); terminal {
 		return code
 	}
-	return run(parsed)
-}
-
-func runCommand(ctx commandContext, argv []string, def commandDef) int {
-	return runParsedCommand(ctx, argv, def, func(parsed *parsedCommand) int {
-		return def.RunParsed(ctx, parsed)
-	})
+	if parsed.logFile != ctx.logFile {
+		stderr, closeLog, err := openLogFile(ctx.stderr, parsed.logFile, ctx.deps.Now(), "dev-vault "+def.Name, ctx.invocation)
+		if err != nil {
+			_, _ = fmt.Fprintln(ctx.stderr, parsed.msg.Error(err))
+			return exitCodeForError(err)
+		}
+		defer closeLog()
+		ctx.stderr = stderr
+		ctx.logFile = parsed.logFile
+		parsed.warnings.w = stderr
+	}
+	fsx.SetSync(!parsed.noFsync)
+	if parsed.explainConfig {
+		return runExplainConfig(ctx, parsed)
+	}
+	code := def.RunParsed(ctx, parsed)
+	if code == 1 && ctx.invocation != "" {
+		// Support matches the ID with the provider's request logs.
+		_, _ = fmt.Fprintf(ctx.stderr, "invocation: %s\n", ctx.invocation)
+	}
+	return code
 }
@@ -0,0 +1,137 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/bsmartlabs/dev-vault/internal/config"
+	"github.com/bsmartlabs/dev-vault/internal/i18n"
+	"github.com/bsmartlabs/dev-vault/internal/secretsync"
+)
+
+var searchCommandDef = commandDef{
+	Name:    "search",
+	Summary: "Find -dev secrets by name across every path and type",
+	Flags: []commandFlagDef{
+		{Name: "regex", Kind: commandFlagBool, Help: "Treat <query> as a Go regular expression instead of a substring"},
+		{Name: "json", Kind: commandFlagBool, Help: "Output JSON, with the byte offsets of each match"},
+	},
+	Doc: commandDoc{
+		Synopsis: "dev-vault [--config <path>] [--profile <name>] search <query> [--regex] [--json]",
+		Description: []string{
+			"Lists the -dev secrets of the project whose name contains <query>, ignoring case, in",
+			"one listing across every path and type. With --regex, names matching the expression",
+			"are listed instead; prefix it with (?i) to ignore case.",
+			"On a terminal, the matched parts of each name are highlighted.",
+		},
+		Notes: []string{
+			"Highlighting is off with --plain, when NO_COLOR is set, or when stdout is not a terminal.",
+			"--json prints the records of list --json, each with \"matches\": [[start, end], ...].",
+		},
+		Examples: []string{
+			"dev-vault search billing",
+			"dev-vault search --regex '^api-.*-env-dev$'",
+			"dev-vault search db --json",
+		},
+	},
+	RunParsed: runSearchParsed,
+}
+
+type searchRecord struct {
+	secretsync.ListRecord
+	Matches [][]int `json:"matches"`
+}
+
+func runSearch(ctx commandContext, argv []string) int {
+	return runCommand(ctx, argv, searchCommandDef)
+}
+
+func runSearchParsed(ctx commandContext, parsed *parsedCommand) int {
+	return newCommandRuntime(ctx, parsed).execute(func(loaded *config.Loaded, service secretsync.Service) error {
+		args := parsed.fs.Args()
+		if len(args) != 1 || args[0] == "" {
+			return usageError(i18n.Errorf(i18n.MsgExpectedUsage, "search <query>"))
+		}
+		expr := "(?i)" + regexp.QuoteMeta(args[0])
+		if parsed.Bool("regex") {
+			expr = args[0]
+		}
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return usageError(i18n.Errorf(i18n.MsgInvalidQuery, err))
+		}
+		found, err := service.List(secretsync.ListQuery{NameRegex: re})
+		if err != nil {
+			return err
+		}
+		records := make([]searchRecord, 0, len(found))
+		for _, record := range found {
+			records = append(records, searchRecord{ListRecord: record, Matches: re.FindAllStringIndex(record.Name, -1)})
+		}
+
+		if parsed.Bool("json") {
+			enc := json.NewEncoder(ctx.stdout)
+			enc.SetIndent("", "  ")
+			if err := enc.Encode(records); err != nil {
+				return outputError(err)
+			}
+			return nil
+		}
+		// The table is aligned on the plain names, then the name starting
+		// each row is styled: escape codes would count as width.
+		var buf bytes.Buffer
+		tbl := newTable(&buf, parsed.plain, "NAME", "TYPE", "PATH", "ID")
+		for _, record := range records {
+			tbl.row(record.Name, record.Type, record.Path, record.ID)
+		}
+		_ = tbl.flush() // a bytes.Buffer never fails
+		lines := strings.SplitAfter(buf.String(), "\n")
+		if useColor(ctx, parsed) {
+			for i, record := range records {
+				lines[i+1] = highlightMatches(record.Name, record.Matches) + strings.TrimPrefix(lines[i+1], record.Name)
+			}
+		}
+		if _, err := io.WriteString(ctx.stdout, strings.Join(lines, "")); err != nil {
+			return outputError(err)
+		}
+		return nil
+	})
+}
+
+const (
+	styleMatch = "\x1b[1;31m"
+	styleReset = "\x1b[0m"
+)
+
+// highlightMatches styles the spans of s that matches holds, as returned by
+// FindAllStringIndex.
+func highlightMatches(s string, matches [][]int) string {
+	var b strings.Builder
+	last := 0
+	for _, m := range matches {
+		if m[0] == m[1] {
+			continue // an empty match has nothing to show
+		}
+		b.WriteString(s[last:m[0]] + styleMatch + s[m[0]:m[1]] + styleReset)
+		last = m[1]
+	}
+	b.WriteString(s[last:])
+	return b.String()
+}
+
+// useColor reports whether stdout may carry ANSI styles: it is a terminal,
+// and neither --plain nor NO_COLOR asks for plain text.
+func useColor(ctx commandContext, parsed *parsedCommand) bool {
+	f, ok := ctx.stdout.(*os.File)
+	return ok && !parsed.plain && ctx.deps.Getenv("NO_COLOR") == "" && isTerminal(f)
+}
+
+// isTerminal reports whether f is a character device such as a terminal.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,106 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/bsmartlabs/dev-vault/internal/config"
+	secret "github.com/scaleway/scaleway-sdk-go/api/secret/v1beta1"
+)
+
+func TestRunSearch(t *testing.T) {
+	root := t.TempDir()
+	cfgPath := writeConfig(t, root, `{"organization_id":"org","project_id":"proj","region":"fr-par","mapping":{"x-dev":{"file":"x"}}}`)
+	api := newFakeSecretAPI()
+	api.AddSecret("proj", "billing-env-dev", "/", secret.SecretTypeKeyValue)
+	api.AddSecret("proj", "api-bill-db-dev", "/db", secret.SecretTypeDatabaseCredentials)
+	api.AddSecret("proj", "billing-env-prod", "/", secret.SecretTypeKeyValue)
+	api.AddSecret("proj", "tls-dev", "/certs", secret.SecretTypeCertificate)
+	deps := baseDeps(func(config.Config, string) (SecretAPI, error) { return api, nil })
+	run := func(args ...string) (int, string, string) {
+		var out, errBuf bytes.Buffer
+		code := Run(append([]string{"dev-vault", "--config", cfgPath, "search"}, args...), &out, &errBuf, deps)
+		return code, out.String(), errBuf.String()
+	}
+
+	// Only -dev names match, across paths and types, ignoring case.
+	code, out, errOut := run("BILL")
+	if code != 0 || !strings.HasPrefix(out, "NAME") || !strings.Contains(out, "billing-env-dev  ") || !strings.Contains(out, "api-bill-db-dev  database_credentials  /db") ||
+		strings.Contains(out, "prod") || strings.Contains(out, "tls-dev") || strings.Contains(out, "\x1b") {
+		t.Fatalf("unexpected search: %d %q %q", code, out, errOut)
+	}
+	code, out, errOut = run("--regex", "^b.*-dev$", "--json")
+	var records []searchRecord
+	if code != 0 || json.Unmarshal([]byte(out), &records) != nil || len(records) != 1 || records[0].Name != "billing-env-dev" || records[0].Path != "/" ||
+		len(records[0].Matches) != 1 || records[0].Matches[0][0] != 0 || records[0].Matches[0][1] != len("billing-env-dev") {
+		t.Fatalf("unexpected json search: %d %q %q", code, out, errOut)
+	}
+
+	for _, tc := range []struct {
+		args    []string
+		code    int
+		wantErr string
+	}{
+		{nil, 2, "expected: search <query>"},
+		{[]string{"a", "b"}, 2, "expected: search <query>"},
+		{[]string{"--regex", "("}, 2, "invalid <query>: error parsing regexp"},
+	} {
+		if code, _, errOut := run(tc.args...); code != tc.code || !strings.Contains(errOut, tc.wantErr) {
+			t.Fatalf("%v: expected %d %q, got %d %q", tc.args, tc.code, tc.wantErr, code, errOut)
+		}
+	}
+	api.listErr = errors.New("list boom")
+	if code, _, errOut := run("bill"); code != 1 || !strings.Contains(errOut, "list boom") {
+		t.Fatalf("expected the list error, got %d %q", code, errOut)
+	}
+	api.listErr = nil
+
+	for _, args := range [][]string{{"bill"}, {"bill", "--json"}} {
+		if code := runSearch(commandContext{stdout: &failingWriter{}, stderr: &bytes.Buffer{}, configPath: cfgPath, deps: deps}, args); code != 1 {
+			t.Fatalf("%v: expected output failure, got %d", args, code)
+		}
+	}
+
+	// A character device stands in for a terminal; plain output and NO_COLOR
+	// turn highlighting off.
+	devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatalf("open %s: %v", os.DevNull, err)
+	}
+	defer devNull.Close()
+	parsed := &parsedCommand{}
+	if !useColor(commandContext{stdout: devNull, deps: deps}, parsed) {
+		t.Fatal("expected color on a character device")
+	}
+	if code := runSearch(commandContext{stdout: devNull, stderr: &bytes.Buffer{}, configPath: cfgPath, deps: deps}, []string{"bill"}); code != 0 {
+		t.Fatalf("expected a highlighted search, got %d", code)
+	}
+	noColor := deps
+	noColor.Getenv = func(key string) string { return map[string]string{"NO_COLOR": "1"}[key] }
+	if useColor(commandContext{stdout: devNull, deps: noColor}, parsed) || useColor(commandContext{stdout: devNull, deps: deps}, &parsedCommand{plain: true}) {
+		t.Fatal("expected NO_COLOR and --plain to turn color off")
+	}
+	closed, _ := os.CreateTemp(t.TempDir(), "closed")
+	closed.Close()
+	if isTerminal(closed) {
+		t.Fatal("expected a closed file not to be a terminal")
+	}
+
+	for _, tc := range []struct {
+		name    string
+		matches [][]int
+		want    string
+	}{
+		{"api-bill-db-dev", [][]int{{4, 8}}, "api-\x1b[1;31mbill\x1b[0m-db-dev"},
+		{"a-a-dev", [][]int{{0, 1}, {2, 3}}, "\x1b[1;31ma\x1b[0m-\x1b[1;31ma\x1b[0m-dev"},
+		{"x-dev", [][]int{{0, 0}}, "x-dev"},
+	} {
+		if got := highlightMatches(tc.name, tc.matches); got != tc.want {
+			t.Fatalf("highlight %s: got %q, want %q", tc.name, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/bsmartlabs/dev-vault/internal/config"
+	"github.com/bsmartlabs/dev-vault/internal/i18n"
+	"github.com/bsmartlabs/dev-vault/internal/secretcontract"
+	"github.com/bsmartlabs/dev-vault/internal/secretsync"
+	"github.com/bsmartlabs/dev-vault/internal/sshkey"
+)
+
+var sshCommandDef = commandDef{
+	Name:    "ssh",
+	Summary: "Load an ssh_key secret into ssh-agent without writing it to disk",
+	Doc: commandDoc{
+		Synopsis: "dev-vault [--config <path>] [--profile <name>] ssh add <secret-dev>",
+		Description: []string{
+			"Reads the latest enabled version of a mapped ssh_key secret and pipes the",
+			"private key into 'ssh-add -'. The key never touches disk.",
+		},
+		Notes: []string{
+			"ssh-add must be on PATH and SSH_AUTH_SOCK must point at a running agent.",
+			"Passphrase-protected keys are prompted for by ssh-add itself.",
+			"When a file is needed, pull writes the key with mode 0600 and its .pub next to it.",
+		},
+		Examples: []string{
+			"dev-vault ssh add deploy-key-dev",
+		},
+	},
+	RunParsed: runSSHParsed,
+}
+
+func runSSH(ctx commandContext, argv []string) int {
+	return runCommand(ctx, argv, sshCommandDef)
+}
+
+func runSSHParsed(ctx commandContext, parsed *parsedCommand) int {
+	return newCommandRuntime(ctx, parsed).execute(func(loaded *config.Loaded, service secretsync.Service) error {
+		args := parsed.fs.Args()
+		if len(args) != 2 || args[0] != "add" {
+			return usageError(i18n.Errorf(i18n.MsgExpectedUsage, "ssh add <secret-dev>"))
+		}
+		targets, err := selectMappingTargetsForMode(loaded.Cfg.Mapping, false, args[1:], commandModePull)
+		if err != nil {
+			return err
+		}
+		target := targets[0]
+		access, err := service.Access(target)
+		if err != nil {
+			return runtimeError(err)
+		}
+		if string(access.Type) != secretcontract.TypeSSHKey {
+			return usageError(i18n.Errorf(i18n.MsgWrongSecretType, "ssh add", target.Name, access.Type, secretcontract.TypeSSHKey))
+		}
+		key, err := sshkey.FromPayload(access.Data)
+		if err != nil {
+			return runtimeError(i18n.Errorf(i18n.MsgTargetError, "ssh add", target.Name, err))
+		}
+		if err := sshkey.AddToAgent(key, ctx.stderr); err != nil {
+			return runtimeError(i18n.Errorf(i18n.MsgTargetError, "ssh add", target.Name, err))
+		}
+		if _, err := fmt.Fprintf(ctx.stdout, "added %s (rev=%d) to ssh-agent\n", target.Name, access.Revision); err != nil {
+			return outputError(err)
+		}
+		return nil
+	})
+}
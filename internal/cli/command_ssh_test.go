@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/bsmartlabs/dev-vault/internal/config"
+	secret "github.com/scaleway/scaleway-sdk-go/api/secret/v1beta1"
+)
+
+func TestRunSSHAdd(t *testing.T) {
+	binDir := t.TempDir()
+	t.Setenv("PATH", binDir)
+	dir := t.TempDir()
+	cfgPath := writeConfig(t, dir, `{"organization_id":"org","project_id":"proj","region":"fr-par","mapping":{
+		"deploy-key-dev":{"file":"id_deploy"},
+		"broken-key-dev":{"file":"id_broken"},
+		"plain-dev":{"file":"plain"},
+		"push-key-dev":{"file":"id_push","mode":"push"}
+	}}`)
+	_, priv, _ := ed25519.GenerateKey(rand.Reader)
+	der, _ := x509.MarshalPKCS8PrivateKey(priv)
+	key := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+	api := newFakeSecretAPI()
+	deploy := api.AddSecret("proj", "deploy-key-dev", "/", secret.SecretTypeSSHKey)
+	api.AddEnabledVersion(deploy.ID, []byte(`{"ssh_private_key":`+strconv.Quote(string(key))+`}`))
+	broken := api.AddSecret("proj", "broken-key-dev", "/", secret.SecretTypeSSHKey)
+	api.AddEnabledVersion(broken.ID, []byte(`{}`))
+	plain := api.AddSecret("proj", "plain-dev", "/", secret.SecretTypeOpaque)
+	api.AddEnabledVersion(plain.ID, []byte(`x`))
+	deps := baseDeps(func(config.Config, string) (SecretAPI, error) { return api, nil })
+	run := func(args ...string) (int, string, string) {
+		var out, errBuf bytes.Buffer
+		code := Run(append([]string{"dev-vault", "--config", cfgPath, "ssh"}, args...), &out, &errBuf, deps)
+		return code, out.String(), errBuf.String()
+	}
+
+	if code, _, errOut := run("add", "deploy-key-dev"); code != 1 || !strings.Contains(errOut, "ssh add deploy-key-dev: ssh-add is not on PATH") {
+		t.Fatalf("expected missing ssh-add, got %d %q", code, errOut)
+	}
+
+	// The fake ssh-add keeps what it read on stdin so the test can compare it.
+	received := filepath.Join(t.TempDir(), "received")
+	script := "#!/bin/sh\n[ \"$1\" = - ] || exit 1\nwhile IFS= read -r line; do printf '%s\\n' \"$line\"; done > " + received + "\n"
+	if err := os.WriteFile(filepath.Join(binDir, "ssh-add"), []byte(script), 0o700); err != nil {
+		t.Fatalf("write fake ssh-add: %v", err)
+	}
+	code, out, errOut := run("add", "deploy-key-dev")
+	if code != 0 || out != "added deploy-key-dev (rev=1) to ssh-agent\n" {
+		t.Fatalf("unexpected ssh add: %d %q %q", code, out, errOut)
+	}
+	if got, _ := os.ReadFile(received); !bytes.Equal(got, key) {
+		t.Fatal("ssh-add did not receive the private key on stdin")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "id_deploy")); !os.IsNotExist(err) {
+		t.Fatal("ssh add must not write the key to disk")
+	}
+
+	for _, tc := range []struct {
+		args    []string
+		code    int
+		wantErr string
+	}{
+		{[]string{"add"}, 2, "expected: ssh add <secret-dev>"},
+		{[]string{"list", "deploy-key-dev"}, 2, "expected: ssh add <secret-dev>"},
+		{[]string{"add", "deploy-key"}, 2, "refusing non-dev secret name"},
+		{[]string{"add", "push-key-dev"}, 2, "not allowed in pull mode"},
+		{[]string{"add", "plain-dev"}, 2, "secret type is opaque, expected ssh_key"},
+		{[]string{"add", "broken-key-dev"}, 1, "ssh add broken-key-dev: ssh_key payload: missing ssh_private_key"},
+	} {
+		if code, _, errOut := run(tc.args...); code != tc.code || !strings.Contains(errOut, tc.wantErr) {
+			t.Fatalf("%v: expected %d %q, got %d %q", tc.args, tc.code, tc.wantErr, code, errOut)
+		}
+	}
+
+	var errBuf bytes.Buffer
+	if code := runSSH(commandContext{stdout: &failingWriter{}, stderr: &errBuf, configPath: cfgPath, deps: deps}, []string{"add", "deploy-key-dev"}); code != 1 {
+		t.Fatalf("expected output error, got %d", code)
+	}
+	api.accessErr = errors.New("access boom")
+	if code, _, errOut := run("add", "deploy-key-dev"); code != 1 || !strings.Contains(errOut, "access boom") {
+		t.Fatalf("expected access error, got %d %q", code, errOut)
+	}
+}
@@ -0,0 +1,139 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/bsmartlabs/dev-vault/internal/config"
+	"github.com/bsmartlabs/dev-vault/internal/i18n"
+	"github.com/bsmartlabs/dev-vault/internal/secretsync"
+)
+
+var stateCommandDef = commandDef{
+	Name:    "state",
+	Summary: "Compare local files with the revisions teammates pushed, from the shared state secret",
+	Flags: []commandFlagDef{
+		{Name: "json", Kind: commandFlagBool, Help: "Output JSON"},
+	},
+	Doc: commandDoc{
+		Synopsis: "dev-vault [--config <path>] [--profile <name>] state [--json]",
+		Description: []string{
+			"Reads the state_secret named in .scw.json, where push, sync, and rollback record each",
+			"secret's new revision, when it was pushed, and from which host. Every enabled mapping",
+			"entry is listed with that record and the revision in its local file's marker.",
+			"Only the state secret is read; mapped payloads are never fetched or printed.",
+		},
+		Notes: []string{
+			"DRIFT is current, behind (a teammate pushed a newer revision; pull it), ahead (the",
+			"record is older than the file), unknown (the file has no revision marker), missing",
+			"(no local file), or unrecorded (no push recorded yet).",
+			"Each write merges into the latest state version, so teammates pushing at the same",
+			"time keep each other's records.",
+		},
+		Examples: []string{
+			"dev-vault state",
+			"dev-vault state --json",
+		},
+	},
+	RunParsed: runStateParsed,
+}
+
+type stateRecord struct {
+	Name             string  `json:"name"`
+	File             string  `json:"file"`
+	ExpectedRevision *uint32 `json:"expected_revision"`
+	PushedAt         *string `json:"pushed_at"`
+	PushedBy         string  `json:"pushed_by,omitempty"`
+	LocalExists      bool    `json:"local_exists"`
+	LocalRevision    *uint32 `json:"local_revision"`
+	Drift            string  `json:"drift"`
+}
+
+func runState(ctx commandContext, argv []string) int {
+	return runCommand(ctx, argv, stateCommandDef)
+}
+
+func runStateParsed(ctx commandContext, parsed *parsedCommand) int {
+	return newCommandRuntime(ctx, parsed).execute(func(loaded *config.Loaded, service secretsync.Service) error {
+		if loaded.Cfg.StateSecret == "" {
+			return runtimeError(i18n.Errorf(i18n.MsgNoStateSecret, loaded.Path))
+		}
+		drifts, err := service.StateDrifts()
+		if err != nil {
+			return runtimeError(err)
+		}
+		records := make([]stateRecord, 0, len(drifts))
+		for _, drift := range drifts {
+			record := stateRecord{Name: drift.Name, File: drift.File, LocalExists: drift.Local, Drift: stateDriftLabel(drift)}
+			if drift.Recorded {
+				revision, pushedAt := drift.Entry.Revision, drift.Entry.PushedAt.UTC().Format(time.RFC3339)
+				record.ExpectedRevision, record.PushedAt, record.PushedBy = &revision, &pushedAt, drift.Entry.PushedBy
+			}
+			if drift.LocalRevision != 0 {
+				revision := drift.LocalRevision
+				record.LocalRevision = &revision
+			}
+			records = append(records, record)
+		}
+
+		if parsed.Bool("json") {
+			enc := json.NewEncoder(ctx.stdout)
+			enc.SetIndent("", "  ")
+			if err := enc.Encode(records); err != nil {
+				return outputError(err)
+			}
+			return nil
+		}
+
+		tbl := newTable(ctx.stdout, parsed.plain, "NAME", "FILE", "EXPECTED", "PUSHED", "BY", "LOCAL", "DRIFT")
+		for _, record := range records {
+			expected, pushed, by, local := "-", "-", "-", "missing"
+			if record.ExpectedRevision != nil {
+				expected, pushed = fmt.Sprintf("rev=%d", *record.ExpectedRevision), *record.PushedAt
+			}
+			if record.PushedBy != "" {
+				by = record.PushedBy
+			}
+			if record.LocalExists {
+				local = "exists"
+			}
+			if record.LocalRevision != nil {
+				local = fmt.Sprintf("rev=%d", *record.LocalRevision)
+			}
+			tbl.row(record.Name, record.File, expected, pushed, by, local, record.Drift)
+		}
+		if err := tbl.flush(); err != nil {
+			return outputError(err)
+		}
+		return nil
+	})
+}
+
+func stateDriftLabel(drift secretsync.StateDrift) string {
+	switch {
+	case !drift.Recorded:
+		return "unrecorded"
+	case !drift.Local:
+		return "missing"
+	case drift.LocalRevision == 0:
+		return "unknown"
+	case drift.LocalRevision < drift.Entry.Revision:
+		return "behind"
+	case drift.LocalRevision > drift.Entry.Revision:
+		return "ahead"
+	}
+	return "current"
+}
+
+// recordSharedState merges pushed revisions into the manifest's
+// state_secret. The versions exist by then, so a failed record is reported as
+// a warning instead of failing the command.
+func recordSharedState(parsed *parsedCommand, service secretsync.Service, results []secretsync.PushResult) error {
+	if err := service.RecordState(results); err != nil {
+		if err := parsed.warnings.warnError(warningState, err); err != nil {
+			return outputError(err)
+		}
+	}
+	return nil
+}
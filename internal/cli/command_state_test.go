@@ -0,0 +1,133 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bsmartlabs/dev-vault/internal/config"
+	"github.com/bsmartlabs/dev-vault/internal/secretsync"
+	secret "github.com/scaleway/scaleway-sdk-go/api/secret/v1beta1"
+)
+
+func TestRunState(t *testing.T) {
+	root := t.TempDir()
+	writeConfig(t, root, `{"organization_id":"org","project_id":"proj","region":"fr-par","state_secret":"team-state-dev","mapping":{
+		"app-dev":{"file":"app.env","format":"dotenv"},
+		"conf-dev":{"file":"conf"},
+		"ro-dev":{"file":"ro","mode":"pull"}}}`)
+	api := newFakeSecretAPI()
+	app := api.AddSecret("proj", "app-dev", "/", secret.SecretTypeKeyValue)
+	api.AddEnabledVersion(app.ID, []byte(`{"A":"1"}`))
+	conf := api.AddSecret("proj", "conf-dev", "/", secret.SecretTypeOpaque)
+	api.AddEnabledVersion(conf.ID, []byte("x=1\n"))
+	deps := baseDeps(func(config.Config, string) (SecretAPI, error) { return api, nil })
+	deps.Getwd = func() (string, error) { return root, nil }
+	run := func(args ...string) (int, string, string) {
+		var out, errBuf bytes.Buffer
+		code := Run(append([]string{"dev-vault", "--plain"}, args...), &out, &errBuf, deps)
+		return code, out.String(), errBuf.String()
+	}
+	edit := func(t *testing.T, file, body string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(root, file), []byte(body), 0o600); err != nil {
+			t.Fatalf("write %s: %v", file, err)
+		}
+	}
+
+	if code, _, errOut := run("pull", "app-dev", "conf-dev"); code != 0 {
+		t.Fatalf("pull: %d %q", code, errOut)
+	}
+	code, out, _ := run("state")
+	want := "NAME\tFILE\tEXPECTED\tPUSHED\tBY\tLOCAL\tDRIFT\n" +
+		"app-dev\tapp.env\t-\t-\t-\trev=1\tunrecorded\n" +
+		"conf-dev\tconf\t-\t-\t-\trev=1\tunrecorded\n" +
+		"ro-dev\tro\t-\t-\t-\tmissing\tunrecorded\n"
+	if code != 0 || out != want {
+		t.Fatalf("unexpected state before any push: %d %q", code, out)
+	}
+
+	// push, sync and rollback all record the revisions they create.
+	edit(t, "app.env", "A=\"2\"\n")
+	if code, out, errOut := run("push", "app-dev"); code != 0 || out != "pushed app-dev (rev=2)\n" {
+		t.Fatalf("push: %d %q %q", code, out, errOut)
+	}
+	edit(t, "conf", "x=2\n")
+	if code, out, errOut := run("sync", "conf-dev"); code != 0 || out != "pushed conf-dev <- conf (rev=2)\n" {
+		t.Fatalf("sync: %d %q %q", code, out, errOut)
+	}
+	if code, _, errOut := run("rollback", "app-dev", "--to-revision", "1", "--yes"); code != 0 {
+		t.Fatalf("rollback: %d %q", code, errOut)
+	}
+	edit(t, "ro", "ro")
+	code, out, _ = run("state")
+	want = "NAME\tFILE\tEXPECTED\tPUSHED\tBY\tLOCAL\tDRIFT\n" +
+		"app-dev\tapp.env\trev=3\t1970-01-01T00:02:03Z\thost\trev=2\tbehind\n" +
+		"conf-dev\tconf\trev=2\t1970-01-01T00:02:03Z\thost\trev=2\tcurrent\n" +
+		"ro-dev\tro\t-\t-\t-\texists\tunrecorded\n"
+	if code != 0 || out != want {
+		t.Fatalf("unexpected state after pushes: %d %q", code, out)
+	}
+
+	code, out, _ = run("state", "--json")
+	var records []map[string]any
+	if err := json.Unmarshal([]byte(out), &records); code != 0 || err != nil {
+		t.Fatalf("unexpected state --json: %d %v %q", code, err, out)
+	}
+	if len(records) != 3 || records[0]["expected_revision"] != 3.0 || records[0]["local_revision"] != 2.0 || records[0]["drift"] != "behind" ||
+		records[0]["pushed_by"] != "host" || records[2]["expected_revision"] != nil || records[2]["local_revision"] != nil || records[2]["local_exists"] != true {
+		t.Fatalf("unexpected records: %#v", records)
+	}
+
+	for _, args := range [][]string{{}, {"--json"}} {
+		if code := runState(commandContext{stdout: &failingWriter{}, stderr: &bytes.Buffer{}, deps: deps}, args); code != 1 {
+			t.Fatalf("%v: expected output error, got %d", args, code)
+		}
+	}
+
+	// A state record that cannot be updated only warns: the version exists.
+	stateSecret := api.secrets[len(api.secrets)-1]
+	api.AddEnabledVersion(stateSecret.ID, []byte("garbage"))
+	edit(t, "app.env", "A=\"3\"\n")
+	code, out, errOut := run("push", "app-dev")
+	if code != 0 || out != "pushed app-dev (rev=4)\n" || !strings.Contains(errOut, "warning: state team-state-dev: payload is not a dev-vault state record") || strings.Contains(errOut, "garbage") {
+		t.Fatalf("expected state warning, got %d %q %q", code, out, errOut)
+	}
+	if code, _, errOut := run("state"); code != 1 || !strings.Contains(errOut, "state team-state-dev: payload is not a dev-vault state record") {
+		t.Fatalf("expected state read error, got %d %q", code, errOut)
+	}
+	edit(t, "app.env", "A=\"4\"\n")
+	if code := runPush(commandContext{stdout: &bytes.Buffer{}, stderr: &failingWriter{}, deps: deps}, []string{"app-dev"}); code != 1 {
+		t.Fatalf("expected warning output error, got %d", code)
+	}
+	edit(t, "conf", "x=3\n")
+	if code := runSync(commandContext{stdout: &bytes.Buffer{}, stderr: &failingWriter{}, deps: deps}, []string{"conf-dev"}); code != 1 {
+		t.Fatalf("expected sync warning output error, got %d", code)
+	}
+
+	plain := t.TempDir()
+	writeConfig(t, plain, `{"organization_id":"org","project_id":"proj","region":"fr-par","mapping":{"app-dev":{"file":"app.env"}}}`)
+	deps.Getwd = func() (string, error) { return plain, nil }
+	if code, _, errOut := run("state"); code != 1 || !strings.Contains(errOut, "has no state_secret") {
+		t.Fatalf("expected missing state_secret error, got %d %q", code, errOut)
+	}
+}
+
+func TestStateDriftLabel(t *testing.T) {
+	recorded := secretsync.StateEntry{Revision: 3}
+	for want, drift := range map[string]secretsync.StateDrift{
+		"unrecorded": {Local: true, LocalRevision: 3},
+		"missing":    {Recorded: true, Entry: recorded},
+		"unknown":    {Recorded: true, Entry: recorded, Local: true},
+		"behind":     {Recorded: true, Entry: recorded, Local: true, LocalRevision: 2},
+		"ahead":      {Recorded: true, Entry: recorded, Local: true, LocalRevision: 4},
+		"current":    {Recorded: true, Entry: recorded, Local: true, LocalRevision: 3},
+	} {
+		if got := stateDriftLabel(drift); got != want {
+			t.Fatalf("%#v: got %q, want %q", drift, got, want)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/bsmartlabs/dev-vault/internal/config"
+	"github.com/bsmartlabs/dev-vault/internal/i18n"
+	"github.com/bsmartlabs/dev-vault/internal/secretsync"
+)
+
+var statusCommandDef = commandDef{
+	Name:    "status",
+	Summary: "Show whether each mapping's remote secret and local file exist and match",
+	Flags: []commandFlagDef{
+		{Name: "json", Kind: commandFlagBool, Help: "Output JSON"},
+		{Name: "policy-file", Kind: commandFlagString, ValueName: "<path>", Help: policyFileFlagHelp},
+	},
+	Doc: commandDoc{
+		Synopsis: "dev-vault [--config <path>] [--profile <name>] status [--json] [--policy-file <path>]",
+		Description: []string{
+			"Reports every enabled mapping entry: whether the remote secret exists and its latest",
+			"enabled revision, whether the local file exists and when it was last written, and",
+			"whether the file matches what pull would write now (compared by SHA-256).",
+			"MANAGED tells whether the file carries the marker pull writes for the secret (the",
+			"user.dev-vault extended attribute, or the first-line comment of dotenv files), which",
+			"sets files dev-vault wrote apart from hand-made ones.",
+			"Payloads and digests are never printed.",
+		},
+		Notes: []string{
+			"MATCH is '-' when either side is missing or the secret cannot be rendered for the",
+			"mapping; the reason for the latter is printed on stderr.",
+			"With a policy, remote secrets over max_secret_age or missing required_tags are",
+			"reported as warnings, whatever the policy level.",
+			"The outcome is saved in the user state dir for prompt: whether the provider answered,",
+			"and which files do not match.",
+		},
+		Examples: []string{
+			"dev-vault status",
+			"dev-vault status --json",
+		},
+	},
+	RunParsed: runStatusParsed,
+}
+
+type statusRecord struct {
+	Name           string  `json:"name"`
+	File           string  `json:"file"`
+	RemoteExists   bool    `json:"remote_exists"`
+	RemoteRevision *uint32 `json:"remote_revision"`
+	LocalExists    bool    `json:"local_exists"`
+	LocalModified  *string `json:"local_modified"`
+	Managed        bool    `json:"managed"`
+	InSync         *bool   `json:"in_sync"`
+	Problem        string  `json:"problem,omitempty"`
+}
+
+func runStatus(ctx commandContext, argv []string) int {
+	return runCommand(ctx, argv, statusCommandDef)
+}
+
+func runStatusParsed(ctx commandContext, parsed *parsedCommand) int {
+	rt := newCommandRuntime(ctx, parsed)
+	return rt.execute(func(loaded *config.Loaded, service secretsync.Service) error {
+		statuses, err := service.MappingStatuses()
+		if err != nil {
+			recordRemoteCheck(ctx.deps, loaded, false, nil)
+			return runtimeError(err)
+		}
+		records := statusRecords(statuses)
+		recordRemoteCheck(ctx.deps, loaded, true, driftedRecords(records))
+		violations, err := rt.remotePolicyViolations(loaded, service)
+		if err != nil {
+			return err
+		}
+		for _, violation := range violations {
+			if err := parsed.warnings.warnError(warningPolicy, i18n.Errorf(i18n.MsgWarnPolicy, violation)); err != nil {
+				return outputError(err)
+			}
+		}
+
+		if parsed.Bool("json") {
+			enc := json.NewEncoder(ctx.stdout)
+			enc.SetIndent("", "  ")
+			if err := enc.Encode(records); err != nil {
+				return outputError(err)
+			}
+			return nil
+		}
+
+		tbl := newTable(ctx.stdout, parsed.plain, "NAME", "FILE", "REMOTE", "LOCAL", "MODIFIED", "MANAGED", "MATCH")
+		for _, record := range records {
+			remote, local, modified, managed, match := "missing", "missing", "-", "-", "-"
+			if record.RemoteExists {
+				remote = "exists"
+			}
+			if record.RemoteRevision != nil {
+				remote = fmt.Sprintf("rev=%d", *record.RemoteRevision)
+			}
+			if record.LocalExists {
+				local, modified, managed = "exists", *record.LocalModified, "no"
+			}
+			if record.Managed {
+				managed = "yes"
+			}
+			if record.InSync != nil {
+				match = "no"
+				if *record.InSync {
+					match = "yes"
+				}
+			}
+			tbl.row(record.Name, record.File, remote, local, modified, managed, match)
+		}
+		if err := tbl.flush(); err != nil {
+			return outputError(err)
+		}
+		for _, record := range records {
+			if record.Problem == "" {
+				continue
+			}
+			if _, err := fmt.Fprintf(ctx.stderr, "%s: %s\n", record.Name, record.Problem); err != nil {
+				return outputError(err)
+			}
+		}
+		return nil
+	})
+}
+
+// driftedRecords names the records whose local file does not match the
+// remote secret.
+func driftedRecords(records []statusRecord) []string {
+	var drifted []string
+	for _, record := range records {
+		if record.InSync != nil && !*record.InSync {
+			drifted = append(drifted, record.Name)
+		}
+	}
+	return drifted
+}
+
+// statusRecords turns statuses into the records status --json prints.
+func statusRecords(statuses []secretsync.MappingStatus) []statusRecord {
+	records := make([]statusRecord, 0, len(statuses))
+	for _, status := range statuses {
+		record := statusRecord{
+			Name:         status.Name,
+			File:         status.File,
+			RemoteExists: status.Remote,
+			LocalExists:  status.Local,
+			Managed:      status.Managed,
+			InSync:       status.InSync,
+			Problem:      status.Problem,
+		}
+		if status.Remote && status.Revision != 0 {
+			revision := status.Revision
+			record.RemoteRevision = &revision
+		}
+		if status.Local {
+			modified := status.LocalModTime.UTC().Format(time.RFC3339)
+			record.LocalModified = &modified
+		}
+		records = append(records, record)
+	}
+	return records
+}
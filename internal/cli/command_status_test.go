@@ -0,0 +1,149 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bsmartlabs/dev-vault/internal/config"
+	secret "github.com/scaleway/scaleway-sdk-go/api/secret/v1beta1"
+)
+
+func TestRunStatus(t *testing.T) {
+	root := t.TempDir()
+	writeConfig(t, root, `{"organization_id":"org","project_id":"proj","region":"fr-par","mapping":{
+		"same-dev":{"file":"same.env","format":"dotenv"},
+		"drift-dev":{"file":"drift.env","format":"dotenv"},
+		"gone-dev":{"file":"gone.env","format":"dotenv"},
+		"new-dev":{"file":"same.env","format":"dotenv","type":"key_value"}}}`)
+	modTime := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	for name, body := range map[string]string{"same.env": "# managed by dev-vault: same-dev\nA=\"1\"\n", "drift.env": "A=\"2\"\n"} {
+		path := filepath.Join(root, name)
+		if err := os.WriteFile(path, []byte(body), 0o600); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatalf("chtimes: %v", err)
+		}
+	}
+	api := newFakeSecretAPI()
+	for _, name := range []string{"same-dev", "drift-dev", "gone-dev"} {
+		s := api.AddSecret("proj", name, "/", secret.SecretTypeKeyValue)
+		api.AddEnabledVersion(s.ID, []byte(`{"A":"1"}`))
+	}
+	// new-dev exists as another type: reported, not fatal.
+	api.AddSecret("proj", "new-dev", "/", secret.SecretTypeOpaque)
+	deps := baseDeps(func(config.Config, string) (SecretAPI, error) { return api, nil })
+	deps.Getwd = func() (string, error) { return root, nil }
+
+	t.Run("Table", func(t *testing.T) {
+		var out, errBuf bytes.Buffer
+		code := Run([]string{"dev-vault", "--plain", "status"}, &out, &errBuf, deps)
+		want := "NAME\tFILE\tREMOTE\tLOCAL\tMODIFIED\tMANAGED\tMATCH\n" +
+			"drift-dev\tdrift.env\trev=1\texists\t2026-01-02T03:04:05Z\tno\tno\n" +
+			"gone-dev\tgone.env\trev=1\tmissing\t-\t-\t-\n" +
+			"new-dev\tsame.env\texists\texists\t2026-01-02T03:04:05Z\tno\t-\n" +
+			"same-dev\tsame.env\trev=1\texists\t2026-01-02T03:04:05Z\tyes\tyes\n"
+		if code != 0 || out.String() != want || errBuf.String() != "new-dev: secret new-dev is opaque but mapping.type is key_value (use --coerce to convert where safe)\n" {
+			t.Fatalf("unexpected status: %d %q %q", code, out.String(), errBuf.String())
+		}
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		var out, errBuf bytes.Buffer
+		if code := Run([]string{"dev-vault", "status", "--json"}, &out, &errBuf, deps); code != 0 {
+			t.Fatalf("unexpected exit: %d %q", code, errBuf.String())
+		}
+		var records []map[string]any
+		if err := json.Unmarshal(out.Bytes(), &records); err != nil {
+			t.Fatalf("unmarshal: %v", err)
+		}
+		if len(records) != 4 || records[1]["remote_revision"] != 1.0 || records[1]["local_modified"] != nil || records[1]["in_sync"] != nil ||
+			records[3]["in_sync"] != true || records[3]["managed"] != true || records[0]["managed"] != false || records[3]["local_modified"] != "2026-01-02T03:04:05Z" || records[2]["remote_revision"] != nil || records[2]["problem"] == nil {
+			t.Fatalf("unexpected records: %#v", records)
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		var errBuf bytes.Buffer
+		for _, args := range [][]string{{}, {"--json"}} {
+			if code := runStatus(commandContext{stdout: &failingWriter{}, stderr: &errBuf, deps: deps}, args); code != 1 {
+				t.Fatalf("%v: expected output error, got %d", args, code)
+			}
+		}
+		if code := runStatus(commandContext{stdout: &bytes.Buffer{}, stderr: &failingWriter{}, deps: deps}, nil); code != 1 {
+			t.Fatalf("expected stderr error, got %d", code)
+		}
+		api.listErr = errors.New("boom")
+		defer func() { api.listErr = nil }()
+		errBuf.Reset()
+		if code := Run([]string{"dev-vault", "status"}, &bytes.Buffer{}, &errBuf, deps); code != 1 || !strings.Contains(errBuf.String(), "list secrets: boom") {
+			t.Fatalf("expected list error, got %d %q", code, errBuf.String())
+		}
+	})
+	t.Run("RemoteCheck", func(t *testing.T) {
+		state := t.TempDir()
+		stateDeps := deps
+		stateDeps.UserConfigDir = func() (string, error) { return state, nil }
+		loaded := &config.Loaded{Path: filepath.Join(root, config.DefaultConfigName)}
+		if code := Run([]string{"dev-vault", "status"}, &bytes.Buffer{}, &bytes.Buffer{}, stateDeps); code != 0 {
+			t.Fatalf("unexpected exit: %d", code)
+		}
+		if check, ok := lastRemoteCheck(stateDeps, loaded); !ok || !check.Reachable || strings.Join(check.Drifted, ",") != "drift-dev" {
+			t.Fatalf("expected the check saved with its drift, got %+v %v", check, ok)
+		}
+		api.listErr = errors.New("offline")
+		defer func() { api.listErr = nil }()
+		if code := Run([]string{"dev-vault", "status"}, &bytes.Buffer{}, &bytes.Buffer{}, stateDeps); code != 1 {
+			t.Fatalf("expected list error, got %d", code)
+		}
+		if check, ok := lastRemoteCheck(stateDeps, loaded); !ok || check.Reachable || len(check.Drifted) != 0 {
+			t.Fatalf("expected an unreachable check saved, got %+v %v", check, ok)
+		}
+	})
+
+	t.Run("Policy", func(t *testing.T) {
+		policyPath := filepath.Join(t.TempDir(), "policy.json")
+		if err := os.WriteFile(policyPath, []byte(`{"level":"enforce","required_tags":["owner"]}`), 0o600); err != nil {
+			t.Fatalf("write policy: %v", err)
+		}
+		var out, errBuf bytes.Buffer
+		code := Run([]string{"dev-vault", "status", "--policy-file", policyPath}, &out, &errBuf, deps)
+		if code != 0 || !strings.Contains(errBuf.String(), "warning: policy: drift-dev: required_tags: missing tags: owner\n") || strings.Count(errBuf.String(), "warning: policy:") != 4 {
+			t.Fatalf("expected policy warnings at any level, got %d %q", code, errBuf.String())
+		}
+		if code := Run([]string{"dev-vault", "--strict-warnings", "status", "--policy-file", policyPath}, &bytes.Buffer{}, &bytes.Buffer{}, deps); code != 1 {
+			t.Fatalf("expected --strict-warnings to fail, got %d", code)
+		}
+		if code := runStatus(commandContext{stdout: &bytes.Buffer{}, stderr: &failingWriter{}, deps: deps}, []string{"--policy-file", policyPath}); code != 1 {
+			t.Fatalf("expected warning write error, got %d", code)
+		}
+		errBuf.Reset()
+		if code := Run([]string{"dev-vault", "status", "--policy-file", "missing.json"}, &bytes.Buffer{}, &errBuf, deps); code != 1 || !strings.Contains(errBuf.String(), "read policy file") {
+			t.Fatalf("expected policy file error, got %d %q", code, errBuf.String())
+		}
+
+		// The lookups and the index listing succeed, the inventory listing
+		// fails.
+		lists := 0
+		stub := &stubSecretAPI{listFn: func(req ListSecretsInput) ([]SecretRecord, error) {
+			if req.Name == "" {
+				if lists++; lists > 1 {
+					return nil, errors.New("inventory boom")
+				}
+			}
+			return nil, nil
+		}}
+		stubDeps := baseDeps(func(config.Config, string) (SecretAPI, error) { return stub, nil })
+		stubDeps.Getwd = deps.Getwd
+		errBuf.Reset()
+		if code := Run([]string{"dev-vault", "status", "--policy-file", policyPath}, &bytes.Buffer{}, &errBuf, stubDeps); code != 1 || !strings.Contains(errBuf.String(), "list secrets: inventory boom") {
+			t.Fatalf("expected inventory error, got %d %q", code, errBuf.String())
+		}
+	})
+}
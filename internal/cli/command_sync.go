@@ -0,0 +1,135 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/bsmartlabs/dev-vault/internal/i18n"
+	"github.com/bsmartlabs/dev-vault/internal/secretsync"
+)
+
+var syncCommandDef = commandDef{
+	Name:    "sync",
+	Summary: "Pull or push each mapped secret depending on which side changed",
+	Flags: []commandFlagDef{
+		{Name: "all", Kind: commandFlagBool, Help: "Sync all mapping entries with mode both (mode defaults to both)"},
+		{Name: "dry-run", Kind: commandFlagBool, Help: "Print the decisions without pulling or pushing"},
+		{Name: "yes", Kind: commandFlagBool, Help: "Confirm pushing more than one secret"},
+		{Name: "description", Kind: commandFlagString, ValueName: "<text>", Help: "Description for pushed versions (optional)"},
+		{Name: "policy-file", Kind: commandFlagString, ValueName: "<path>", Help: policyFileFlagHelp},
+	},
+	Doc: commandDoc{
+		Synopsis: "dev-vault [--config <path>] [--profile <name>] sync (--all | <secret-dev> ...) [options]",
+		Description: []string{
+			"Compares each mapping entry with the revision its local file was last pulled or pushed at,",
+			"as recorded in the file's user.dev-vault extended attribute, and then:",
+			"  - pulls when only the remote secret changed, or the local file is missing,",
+			"  - pushes when only the local file changed,",
+			"  - reports a conflict when both changed, or the file has no recorded revision.",
+			"Conflicts are never resolved automatically: neither side is overwritten. Resolve them",
+			"with pull --overwrite or push, and sync again.",
+			"Never prints secret payloads.",
+		},
+		Notes: []string{
+			"Only entries with mapping.mode=both take part.",
+			"Pushes are checked against the push policy first. If more than one secret would be",
+			"pushed, you must pass --yes.",
+			"The command exits with code 1 when it reports a conflict.",
+		},
+		Examples: []string{
+			"dev-vault sync --all --dry-run",
+			"dev-vault sync bweb-env-bsmart-dev",
+			"dev-vault sync --all --yes",
+		},
+	},
+	RunParsed: runSyncParsed,
+}
+
+func runSync(ctx commandContext, argv []string) int {
+	return runCommand(ctx, argv, syncCommandDef)
+}
+
+func runSyncParsed(ctx commandContext, parsed *parsedCommand) int {
+	var plan []secretsync.SyncDecision
+	dryRun := parsed.Bool("dry-run")
+	return newCommandRuntime(ctx, parsed).executeMapping(mappingCommandSpec{
+		mode:        commandModeSync,
+		all:         parsed.Bool("all"),
+		checkPolicy: !dryRun,
+		pushTargets: func(service secretsync.Service, targets []secretsync.MappingTarget) ([]secretsync.MappingTarget, error) {
+			var err error
+			if plan, err = service.PlanSync(targets); err != nil {
+				return nil, err
+			}
+			pushed := syncTargets(plan, secretsync.SyncPush)
+			if len(pushed) > 1 && !parsed.Bool("yes") {
+				return nil, usageError(i18n.Errorf(i18n.MsgPushNeedsYes))
+			}
+			return pushed, nil
+		},
+		execute: func(service secretsync.Service, targets []secretsync.MappingTarget) error {
+			decisions := plan
+			var err error
+			if dryRun {
+				if decisions, err = service.PlanSync(targets); err != nil {
+					return err
+				}
+			} else {
+				decisions, err = service.ApplySync(plan, secretsync.PushOptions{Description: parsed.String("description")})
+			}
+			for _, decision := range decisions {
+				if _, err := fmt.Fprintln(ctx.stdout, syncLine(decision, dryRun)); err != nil {
+					return outputError(err)
+				}
+			}
+			if !dryRun {
+				var pushed []secretsync.PushResult
+				for _, decision := range decisions {
+					if decision.Action == secretsync.SyncPush {
+						pushed = append(pushed, secretsync.PushResult{Name: decision.Target.Name, Revision: decision.Revision})
+					}
+				}
+				if stateErr := recordSharedState(parsed, service, pushed); stateErr != nil {
+					return stateErr
+				}
+			}
+			if err != nil {
+				return err
+			}
+			if conflicts := len(syncTargets(decisions, secretsync.SyncConflict)); conflicts > 0 {
+				return runtimeError(i18n.Errorf(i18n.MsgSyncConflicts, conflicts))
+			}
+			return nil
+		},
+	})
+}
+
+// syncLine describes one decision; a dry run reports what would be done.
+func syncLine(decision secretsync.SyncDecision, dryRun bool) string {
+	name, file := decision.Target.Name, decision.Target.Entry.File
+	switch decision.Action {
+	case secretsync.SyncPull:
+		if dryRun {
+			return fmt.Sprintf("would pull %s -> %s (rev=%d)", name, file, decision.Revision)
+		}
+		return fmt.Sprintf("pulled %s -> %s (rev=%d)", name, file, decision.Revision)
+	case secretsync.SyncPush:
+		if dryRun {
+			return fmt.Sprintf("would push %s <- %s", name, file)
+		}
+		return fmt.Sprintf("pushed %s <- %s (rev=%d)", name, file, decision.Revision)
+	case secretsync.SyncConflict:
+		return fmt.Sprintf("conflict %s: %s", name, decision.Reason)
+	default:
+		return fmt.Sprintf("in sync %s (rev=%d)", name, decision.Revision)
+	}
+}
+
+func syncTargets(decisions []secretsync.SyncDecision, action secretsync.SyncAction) []secretsync.MappingTarget {
+	var targets []secretsync.MappingTarget
+	for _, decision := range decisions {
+		if decision.Action == action {
+			targets = append(targets, decision.Target)
+		}
+	}
+	return targets
+}
@@ -0,0 +1,145 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bsmartlabs/dev-vault/internal/config"
+	"github.com/bsmartlabs/dev-vault/internal/syncbase"
+	secret "github.com/scaleway/scaleway-sdk-go/api/secret/v1beta1"
+)
+
+func TestRunSync(t *testing.T) {
+	root := t.TempDir()
+	writeConfig(t, root, `{"organization_id":"org","project_id":"proj","region":"fr-par","mapping":{
+		"app-dev":{"file":"app.env","format":"dotenv"},
+		"conf-dev":{"file":"conf","mode":"both"},
+		"read-only-dev":{"file":"ro","mode":"pull"}}}`)
+	api := newFakeSecretAPI()
+	app := api.AddSecret("proj", "app-dev", "/", secret.SecretTypeKeyValue)
+	api.AddEnabledVersion(app.ID, []byte(`{"A":"1"}`))
+	conf := api.AddSecret("proj", "conf-dev", "/", secret.SecretTypeOpaque)
+	api.AddEnabledVersion(conf.ID, []byte("x=1\n"))
+	deps := baseDeps(func(config.Config, string) (SecretAPI, error) { return api, nil })
+	deps.Getwd = func() (string, error) { return root, nil }
+	run := func(args ...string) (int, string, string) {
+		var out, errBuf bytes.Buffer
+		code := Run(append([]string{"dev-vault", "sync"}, args...), &out, &errBuf, deps)
+		return code, out.String(), errBuf.String()
+	}
+	edit := func(t *testing.T, file, body string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(root, file), []byte(body), 0o600); err != nil {
+			t.Fatalf("write %s: %v", file, err)
+		}
+	}
+	expect := func(t *testing.T, wantCode int, wantOut string, args ...string) string {
+		t.Helper()
+		code, out, errOut := run(args...)
+		if code != wantCode || out != wantOut {
+			t.Fatalf("%v: unexpected sync: %d %q %q", args, code, out, errOut)
+		}
+		return errOut
+	}
+
+	expect(t, 0, "would pull app-dev -> app.env (rev=1)\nwould pull conf-dev -> conf (rev=1)\n", "--all", "--dry-run")
+	if _, err := os.Stat(filepath.Join(root, "conf")); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("dry run must not write, got %v", err)
+	}
+	expect(t, 0, "pulled app-dev -> app.env (rev=1)\npulled conf-dev -> conf (rev=1)\n", "--all")
+	if _, err := os.Stat(filepath.Join(root, "ro")); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("pull-only entries must be left out, got %v", err)
+	}
+	if probe, err := os.ReadFile(filepath.Join(root, "conf")); err != nil || string(probe) != "x=1\n" {
+		t.Fatalf("unexpected pulled file: %q %v", probe, err)
+	}
+	expect(t, 0, "in sync app-dev (rev=1)\nin sync conf-dev (rev=1)\n", "--all")
+
+	edit(t, "app.env", "A=\"2\"\n")
+	edit(t, "conf", "x=2\n")
+	expect(t, 0, "would push app-dev <- app.env\nwould push conf-dev <- conf\n", "--all", "--dry-run")
+	if errOut := expect(t, 2, "", "--all"); !strings.Contains(errOut, "refusing to push multiple secrets without --yes") {
+		t.Fatalf("expected --yes error, got %q", errOut)
+	}
+	expect(t, 0, "pushed app-dev <- app.env (rev=2)\npushed conf-dev <- conf (rev=2)\n", "--all", "--yes", "--description", "sync")
+	if v := api.versions[app.ID][1]; string(v.data) != `{"A":"2"}` || *v.description != "sync" {
+		t.Fatalf("unexpected pushed version: %s", v.data)
+	}
+
+	// Both sides changed: nothing is overwritten and the command fails.
+	api.AddEnabledVersion(conf.ID, []byte("x=3\n"))
+	edit(t, "conf", "x=4\n")
+	if errOut := expect(t, 1, "conflict conf-dev: both sides changed since rev=2\n", "conf-dev"); !strings.Contains(errOut, "sync: 1 conflict(s); resolve them with pull --overwrite or push, then sync again") {
+		t.Fatalf("unexpected conflict error: %q", errOut)
+	}
+	if probe, _ := os.ReadFile(filepath.Join(root, "conf")); string(probe) != "x=4\n" || len(api.versions[conf.ID]) != 3 {
+		t.Fatalf("conflict must leave both sides alone: %q", probe)
+	}
+
+	t.Run("Errors", func(t *testing.T) {
+		if errOut := expect(t, 2, "", "read-only-dev"); !strings.Contains(errOut, "secret read-only-dev not allowed in sync mode (mapping.mode=pull)") {
+			t.Fatalf("unexpected mode error: %q", errOut)
+		}
+		var errBuf bytes.Buffer
+		if code := runSync(commandContext{stdout: &failingWriter{}, stderr: &errBuf, deps: deps}, []string{"app-dev"}); code != 1 {
+			t.Fatalf("expected output error, got %d", code)
+		}
+		api.listErr = errors.New("boom")
+		defer func() { api.listErr = nil }()
+		for _, args := range [][]string{{"app-dev"}, {"app-dev", "--dry-run"}} {
+			if errOut := expect(t, 1, "", args...); !strings.Contains(errOut, "list secrets: boom") {
+				t.Fatalf("%v: expected list error, got %q", args, errOut)
+			}
+		}
+	})
+
+	t.Run("ApplyError", func(t *testing.T) {
+		edit(t, "app.env", "A=\"5\"\n")
+		api.createVerErr = errors.New("create boom")
+		defer func() { api.createVerErr = nil }()
+		if errOut := expect(t, 1, "", "app-dev"); !strings.Contains(errOut, "create boom") {
+			t.Fatalf("expected push error, got %q", errOut)
+		}
+	})
+}
+
+func TestRunSync_RecordsBases(t *testing.T) {
+	root := t.TempDir()
+	cfgPath := writeConfig(t, root, `{"organization_id":"org","project_id":"proj","region":"fr-par","mapping":{"app-dev":{"file":"app.env","format":"dotenv"}}}`)
+	api := newFakeSecretAPI()
+	app := api.AddSecret("proj", "app-dev", "/", secret.SecretTypeKeyValue)
+	api.AddEnabledVersion(app.ID, []byte(`{"A":"1"}`))
+	state := t.TempDir()
+	deps := baseDeps(func(config.Config, string) (SecretAPI, error) { return api, nil })
+	deps.Getwd = func() (string, error) { return root, nil }
+	deps.UserConfigDir = func() (string, error) { return state, nil }
+	run := func(args ...string) (int, string) {
+		var out, errBuf bytes.Buffer
+		code := Run(append([]string{"dev-vault"}, args...), &out, &errBuf, deps)
+		return code, out.String() + errBuf.String()
+	}
+
+	if code, out := run("sync", "--all"); code != 0 {
+		t.Fatalf("sync: %d %q", code, out)
+	}
+	tracker, err := syncbase.NewStore(filepath.Join(state, stateDirName)).Open(cfgPath)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	if name, revision, ok := tracker.Base(filepath.Join(root, "app.env")); !ok || name != "app-dev" || revision != 1 {
+		t.Fatalf("expected the pull recorded, got %q %d %v", name, revision, ok)
+	}
+
+	// An unreadable record leaves the bases to the files' attributes.
+	record := syncbase.NewStore(filepath.Join(state, stateDirName)).Path(cfgPath)
+	if err := os.WriteFile(record, []byte("{"), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if code, out := run("pull", "--all", "--overwrite"); code != 0 {
+		t.Fatalf("pull: %d %q", code, out)
+	}
+}
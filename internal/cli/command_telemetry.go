@@ -0,0 +1,116 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/bsmartlabs/dev-vault/internal/i18n"
+	"github.com/bsmartlabs/dev-vault/internal/telemetry"
+)
+
+var telemetryCommandDef = commandDef{
+	Name:    "telemetry",
+	Summary: "Manage opt-in anonymous usage telemetry",
+	Doc: commandDoc{
+		Synopsis: "dev-vault telemetry (on|off|status)",
+		Description: []string{
+			"Telemetry is off unless you run 'dev-vault telemetry on'.",
+			"When on, each command records only its name, duration, and error kind",
+			"(none, usage, or failure): no arguments, paths, project IDs, or secret names.",
+		},
+		Notes: []string{
+			"Events are appended to a local file under your user config directory; nothing is sent over the network.",
+			"DO_NOT_TRACK=1 suppresses recording even when telemetry is on.",
+		},
+		Examples: []string{
+			"dev-vault telemetry status",
+			"dev-vault telemetry on",
+			"dev-vault telemetry off",
+		},
+	},
+	RunParsed: runTelemetryParsed,
+}
+
+func runTelemetry(ctx commandContext, argv []string) int {
+	return runCommand(ctx, argv, telemetryCommandDef)
+}
+
+func runTelemetryParsed(ctx commandContext, parsed *parsedCommand) int {
+	return newCommandRuntime(ctx, parsed).executeStandalone(func() error {
+		args := parsed.fs.Args()
+		if len(args) != 1 {
+			return usageError(i18n.Errorf(i18n.MsgTelemetryExpected))
+		}
+		store, err := telemetryStore(ctx.deps)
+		if err != nil {
+			return runtimeError(err)
+		}
+		doNotTrack := telemetry.DoNotTrack(ctx.deps.Getenv)
+
+		var lines []string
+		switch args[0] {
+		case "on":
+			if err := store.SetEnabled(true); err != nil {
+				return runtimeError(err)
+			}
+			lines = append(lines, "telemetry enabled; events are recorded locally in "+store.EventsPath())
+		case "off":
+			if err := store.SetEnabled(false); err != nil {
+				return runtimeError(err)
+			}
+			lines = append(lines, "telemetry disabled")
+		case "status":
+			enabled, err := store.Enabled()
+			if err != nil {
+				return runtimeError(err)
+			}
+			count, err := store.Count()
+			if err != nil {
+				return runtimeError(err)
+			}
+			state := "disabled"
+			if enabled {
+				state = "enabled"
+			}
+			lines = append(lines,
+				"telemetry: "+state,
+				fmt.Sprintf("events: %d (%s)", count, store.EventsPath()),
+			)
+		default:
+			return usageError(i18n.Errorf(i18n.MsgTelemetryUnknown, args[0]))
+		}
+		if doNotTrack {
+			lines = append(lines, "DO_NOT_TRACK is set: nothing is recorded")
+		}
+		for _, line := range lines {
+			if _, err := fmt.Fprintln(ctx.stdout, line); err != nil {
+				return outputError(err)
+			}
+		}
+		return nil
+	})
+}
+
+func telemetryStore(deps Dependencies) (telemetry.Store, error) {
+	dir, err := stateDir(deps)
+	if err != nil {
+		return telemetry.Store{}, err
+	}
+	return telemetry.NewStore(dir), nil
+}
+
+// recordTelemetry never changes a command's outcome: without consent, with
+// DO_NOT_TRACK set, or on any storage error, the event is silently dropped.
+func recordTelemetry(deps Dependencies, command string, duration time.Duration, exitCode int) {
+	if telemetry.DoNotTrack(deps.Getenv) {
+		return
+	}
+	store, err := telemetryStore(deps)
+	if err != nil {
+		return
+	}
+	if enabled, err := store.Enabled(); err != nil || !enabled {
+		return
+	}
+	_ = store.Record(command, duration, exitCode)
+}
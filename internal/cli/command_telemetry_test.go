@@ -0,0 +1,162 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bsmartlabs/dev-vault/internal/config"
+)
+
+func telemetryDeps(t *testing.T) (Dependencies, string) {
+	t.Helper()
+	dir := t.TempDir()
+	deps := baseDeps(func(cfg config.Config, s string) (SecretAPI, error) { return newFakeSecretAPI(), nil })
+	deps.UserConfigDir = func() (string, error) { return dir, nil }
+	return deps, filepath.Join(dir, "dev-vault", "telemetry-events.jsonl")
+}
+
+func TestRunTelemetry(t *testing.T) {
+	deps, eventsPath := telemetryDeps(t)
+	run := func(args ...string) (int, string, string) {
+		var out, errBuf bytes.Buffer
+		code := Run(append([]string{"dev-vault"}, args...), &out, &errBuf, deps)
+		return code, out.String(), errBuf.String()
+	}
+
+	code, out, _ := run("telemetry", "status")
+	if code != 0 || !strings.Contains(out, "telemetry: disabled") || !strings.Contains(out, "events: 0") {
+		t.Fatalf("unexpected default status: %d %q", code, out)
+	}
+	if _, err := os.Stat(eventsPath); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected nothing recorded without consent, got %v", err)
+	}
+
+	code, out, _ = run("telemetry", "on")
+	if code != 0 || !strings.Contains(out, "telemetry enabled") || !strings.Contains(out, eventsPath) {
+		t.Fatalf("unexpected enable output: %d %q", code, out)
+	}
+
+	if code, _, _ := run("version"); code != 0 {
+		t.Fatalf("version failed: %d", code)
+	}
+	if code, _, _ := run("version", "--nope"); code != 2 {
+		t.Fatalf("expected usage error, got %d", code)
+	}
+	raw, err := os.ReadFile(eventsPath)
+	if err != nil {
+		t.Fatalf("read events: %v", err)
+	}
+	if !strings.Contains(string(raw), `{"command":"version","duration_ms":0,"error_kind":"none"}`) ||
+		!strings.Contains(string(raw), `{"command":"version","duration_ms":0,"error_kind":"usage"}`) {
+		t.Fatalf("unexpected events:\n%s", raw)
+	}
+
+	code, out, _ = run("telemetry", "status")
+	if code != 0 || !strings.Contains(out, "telemetry: enabled") || !strings.Contains(out, "events: 3") {
+		t.Fatalf("unexpected enabled status: %d %q", code, out)
+	}
+
+	deps.Getenv = func(key string) string {
+		if key == "DO_NOT_TRACK" {
+			return "1"
+		}
+		return ""
+	}
+	before, _ := os.ReadFile(eventsPath)
+	code, out, _ = run("telemetry", "status")
+	if code != 0 || !strings.Contains(out, "DO_NOT_TRACK is set") {
+		t.Fatalf("unexpected DO_NOT_TRACK status: %d %q", code, out)
+	}
+	after, _ := os.ReadFile(eventsPath)
+	if !bytes.Equal(before, after) {
+		t.Fatal("expected DO_NOT_TRACK to suppress recording")
+	}
+	deps.Getenv = func(string) string { return "" }
+
+	code, out, _ = run("telemetry", "off")
+	if code != 0 || strings.TrimSpace(out) != "telemetry disabled" {
+		t.Fatalf("unexpected disable output: %d %q", code, out)
+	}
+	before, _ = os.ReadFile(eventsPath)
+	run("version")
+	after, _ = os.ReadFile(eventsPath)
+	if !bytes.Equal(before, after) {
+		t.Fatal("expected nothing recorded after opting out")
+	}
+}
+
+func TestRunTelemetry_Errors(t *testing.T) {
+	deps, _ := telemetryDeps(t)
+
+	for _, args := range [][]string{{}, {"maybe"}, {"on", "off"}} {
+		var out, errBuf bytes.Buffer
+		code := runTelemetry(commandContext{stdout: &out, stderr: &errBuf, deps: deps}, args)
+		if code != 2 {
+			t.Fatalf("args %v: expected 2, got %d stderr=%s", args, code, errBuf.String())
+		}
+	}
+
+	var errBuf bytes.Buffer
+	if code := runTelemetry(commandContext{stdout: &failingWriter{}, stderr: &errBuf, deps: deps}, []string{"status"}); code != 1 {
+		t.Fatalf("expected output error, got %d", code)
+	}
+
+	noDir := deps
+	noDir.UserConfigDir = func() (string, error) { return "", errors.New("no home") }
+	errBuf.Reset()
+	if code := runTelemetry(commandContext{stdout: &bytes.Buffer{}, stderr: &errBuf, deps: noDir}, []string{"status"}); code != 1 || !strings.Contains(errBuf.String(), "locate user config dir: no home") {
+		t.Fatalf("expected config dir error, got %d stderr=%s", code, errBuf.String())
+	}
+
+	// A regular file where the dev-vault directory should be breaks every action.
+	blocked := deps
+	base := t.TempDir()
+	if err := os.WriteFile(filepath.Join(base, "dev-vault"), nil, 0o600); err != nil {
+		t.Fatalf("write blocker: %v", err)
+	}
+	blocked.UserConfigDir = func() (string, error) { return base, nil }
+	for _, action := range []string{"on", "off", "status"} {
+		errBuf.Reset()
+		if code := runTelemetry(commandContext{stdout: &bytes.Buffer{}, stderr: &errBuf, deps: blocked}, []string{action}); code != 1 {
+			t.Fatalf("%s: expected 1, got %d stderr=%s", action, code, errBuf.String())
+		}
+	}
+
+	// Unreadable events file: status reports it, recording silently skips it.
+	countDeps, eventsPath := telemetryDeps(t)
+	var out bytes.Buffer
+	if code := runTelemetry(commandContext{stdout: &out, stderr: &errBuf, deps: countDeps}, []string{"on"}); code != 0 {
+		t.Fatalf("enable failed: %d", code)
+	}
+	if err := os.Mkdir(eventsPath, 0o700); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	errBuf.Reset()
+	if code := runTelemetry(commandContext{stdout: &out, stderr: &errBuf, deps: countDeps}, []string{"status"}); code != 1 || !strings.Contains(errBuf.String(), "read telemetry events") {
+		t.Fatalf("expected count error, got %d stderr=%s", code, errBuf.String())
+	}
+	recordTelemetry(countDeps, "version", time.Second, 0)
+	recordTelemetry(noDir, "version", time.Second, 0)
+
+	// Corrupt consent state: status fails loudly, recording stays silent.
+	stateDeps, eventsPath := telemetryDeps(t)
+	if err := os.MkdirAll(filepath.Dir(eventsPath), 0o700); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(filepath.Dir(eventsPath), "telemetry.json"), []byte("{"), 0o600); err != nil {
+		t.Fatalf("write state: %v", err)
+	}
+	errBuf.Reset()
+	if code := runTelemetry(commandContext{stdout: &out, stderr: &errBuf, deps: stateDeps}, []string{"status"}); code != 1 || !strings.Contains(errBuf.String(), "decode telemetry state") {
+		t.Fatalf("expected state error, got %d stderr=%s", code, errBuf.String())
+	}
+	recordTelemetry(stateDeps, "version", time.Second, 0)
+	if _, err := os.Stat(eventsPath); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected no event with unreadable consent, got %v", err)
+	}
+}
@@ -0,0 +1,159 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/bsmartlabs/dev-vault/internal/config"
+	"github.com/bsmartlabs/dev-vault/internal/i18n"
+	"github.com/bsmartlabs/dev-vault/internal/secretprovider"
+	"github.com/bsmartlabs/dev-vault/internal/secretsync"
+)
+
+var validateCommandDef = commandDef{
+	Name:    "validate",
+	Summary: "Lint .scw.json and the files it maps, optionally against the remote secrets",
+	Flags: []commandFlagDef{
+		{Name: "remote", Kind: commandFlagBool, Help: "Also check each mapped secret exists and matches the declared type"},
+		{Name: "json", Kind: commandFlagBool, Help: "Output findings as JSON"},
+	},
+	Doc: commandDoc{
+		Synopsis: "dev-vault [--config <path>] [--profile <name>] validate [--remote] [--json]",
+		Description: []string{
+			"Loads and validates the manifest, then checks that every file, files entry, and",
+			"template_file of the mapping resolves inside the project root. Nothing is read",
+			"from the provider unless --remote is given, which runs the doctor checks: each",
+			"mapped secret exists, and its type fits mapping.type and mapping.format.",
+		},
+		Notes: []string{
+			"Exit codes: 0 no findings, 3 the manifest does not load, 4 a mapped path escapes",
+			"the project root, 5 a remote check failed; 1 and 2 keep their usual meaning.",
+		},
+		Examples: []string{
+			"dev-vault validate",
+			"dev-vault validate --remote --json",
+		},
+	},
+	RunParsed: runValidateParsed,
+}
+
+const (
+	validateCheckConfig = "config"
+	validateCheckPath   = "path"
+	validateCheckRemote = "remote"
+)
+
+// validateExitCodes are the exit codes of each check, so CI can tell a broken
+// manifest from a missing secret.
+var validateExitCodes = map[string]int{
+	validateCheckConfig: 3,
+	validateCheckPath:   4,
+	validateCheckRemote: 5,
+}
+
+type validateFinding struct {
+	Check   string `json:"check"`
+	Name    string `json:"name,omitempty"`
+	Problem string `json:"problem"`
+}
+
+func runValidate(ctx commandContext, argv []string) int {
+	return runCommand(ctx, argv, validateCommandDef)
+}
+
+func runValidateParsed(ctx commandContext, parsed *parsedCommand) int {
+	r := newCommandRuntime(ctx, parsed)
+	loaded, err := loadConfig(parsed.configPath, parsed.loadOptions(), ctx.deps)
+	if err != nil {
+		return r.executeStandalone(func() error {
+			return writeValidateFindings(ctx, parsed, []validateFinding{{Check: validateCheckConfig, Problem: err.Error()}}, 0)
+		})
+	}
+	var api secretprovider.SecretAPI
+	if parsed.Bool("remote") {
+		if api, err = openSecretAPI(loaded.Cfg, parsed.profileOverride, ctx.deps); err != nil {
+			runErr := runtimeError(err)
+			_, _ = fmt.Fprintln(ctx.stderr, parsed.msg.Error(runErr))
+			return exitCodeForError(runErr)
+		}
+	}
+	return r.run(loaded, api, func(loaded *config.Loaded, service secretsync.Service) error {
+		findings := validatePaths(loaded)
+		if api != nil {
+			remote, _, err := service.FormatFindings()
+			if err != nil {
+				return runtimeError(err)
+			}
+			for _, f := range remote {
+				findings = append(findings, validateFinding{Check: validateCheckRemote, Name: f.Name, Problem: f.Problem + "; " + f.Suggestion})
+			}
+		}
+		return writeValidateFindings(ctx, parsed, findings, len(loaded.Cfg.Mapping))
+	})
+}
+
+// validatePaths reports the mapped paths that resolve outside the project
+// root, which pull and push would refuse one mapping at a time.
+func validatePaths(loaded *config.Loaded) []validateFinding {
+	names := make([]string, 0, len(loaded.Cfg.Mapping))
+	for name := range loaded.Cfg.Mapping {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	findings := make([]validateFinding, 0)
+	for _, name := range names {
+		entry := loaded.Cfg.Mapping[name]
+		paths := []string{entry.File, entry.TemplateFile}
+		keys := make([]string, 0, len(entry.Files))
+		for key := range entry.Files {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			paths = append(paths, entry.Files[key])
+		}
+		for _, path := range paths {
+			if path == "" {
+				continue
+			}
+			if _, err := config.ResolveFile(loaded.Root, path); err != nil {
+				findings = append(findings, validateFinding{Check: validateCheckPath, Name: name, Problem: err.Error()})
+			}
+		}
+	}
+	return findings
+}
+
+// writeValidateFindings prints findings and returns an error carrying the
+// exit code of the first check that failed, in check order.
+func writeValidateFindings(ctx commandContext, parsed *parsedCommand, findings []validateFinding, checked int) error {
+	if parsed.Bool("json") {
+		enc := json.NewEncoder(ctx.stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(findings); err != nil {
+			return outputError(err)
+		}
+	} else if len(findings) == 0 {
+		if _, err := fmt.Fprintf(ctx.stdout, "%d mappings checked, no problems\n", checked); err != nil {
+			return outputError(err)
+		}
+	} else {
+		table := newTable(ctx.stdout, parsed.plain, "CHECK", "NAME", "PROBLEM")
+		for _, f := range findings {
+			name := f.Name
+			if name == "" {
+				name = "-"
+			}
+			table.row(f.Check, name, f.Problem)
+		}
+		if err := table.flush(); err != nil {
+			return outputError(err)
+		}
+	}
+	if len(findings) == 0 {
+		return nil
+	}
+	return exitError(i18n.Errorf(i18n.MsgValidationProblems, len(findings)), validateExitCodes[findings[0].Check])
+}
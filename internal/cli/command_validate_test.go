@@ -0,0 +1,88 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/bsmartlabs/dev-vault/internal/config"
+	secret "github.com/scaleway/scaleway-sdk-go/api/secret/v1beta1"
+)
+
+func TestRunValidate(t *testing.T) {
+	cleanPath := writeConfig(t, t.TempDir(), `{"organization_id":"org","project_id":"proj","region":"fr-par","mapping":{
+		"kv-dev":{"file":"kv.env","format":"dotenv"}
+	}}`)
+	escapingPath := writeConfig(t, t.TempDir(), `{"organization_id":"org","project_id":"proj","region":"fr-par","mapping":{
+		"kv-dev":{"file":"kv.env","format":"dotenv"},
+		"split-dev":{"files":{"B":"ok.txt","A":"../a.txt"}},
+		"tpl-dev":{"file":"../out.conf","format":"template","template_file":"../tpl.tmpl"}
+	}}`)
+	mismatchPath := writeConfig(t, t.TempDir(), `{"organization_id":"org","project_id":"proj","region":"fr-par","mapping":{
+		"kv-dev":{"file":"kv.env","format":"dotenv"},
+		"tls-dev":{"file":"tls.env","format":"dotenv"}
+	}}`)
+	brokenPath := writeConfig(t, t.TempDir(), `{"organization_id":"org","project_id":"proj","region":"fr-par","mapping":{"prod":{"file":"x"}}}`)
+	api := newFakeSecretAPI()
+	api.AddSecret("proj", "tls-dev", "/", secret.SecretTypeCertificate)
+	api.AddSecret("proj", "kv-dev", "/", secret.SecretTypeKeyValue)
+	opened := 0
+	deps := baseDeps(func(config.Config, string) (SecretAPI, error) { opened++; return api, nil })
+	run := func(cfg string, args ...string) (int, string, string) {
+		var out, errBuf bytes.Buffer
+		code := Run(append([]string{"dev-vault", "--plain", "--config", cfg, "validate"}, args...), &out, &errBuf, deps)
+		return code, out.String(), errBuf.String()
+	}
+
+	if code, out, errOut := run(cleanPath); code != 0 || out != "1 mappings checked, no problems\n" || opened != 0 {
+		t.Fatalf("unexpected clean run: %d %q %q (opened %d)", code, out, errOut, opened)
+	}
+	if code, out, errOut := run(cleanPath, "--remote"); code != 0 || out != "1 mappings checked, no problems\n" || opened != 1 {
+		t.Fatalf("unexpected clean remote run: %d %q %q (opened %d)", code, out, errOut, opened)
+	}
+
+	code, out, errOut := run(brokenPath)
+	if code != 3 || !strings.HasPrefix(out, "CHECK\tNAME\tPROBLEM\nconfig\t-\tload config: ") || !strings.Contains(out, "prod") || !strings.Contains(errOut, "1 validation problem(s)") {
+		t.Fatalf("unexpected broken-config output: %d\n%s\n%s", code, out, errOut)
+	}
+
+	code, out, _ = run(escapingPath)
+	want := "CHECK\tNAME\tPROBLEM\n" +
+		"path\tsplit-dev\tpath escapes project root: \"../a.txt\"\n" +
+		"path\ttpl-dev\tpath escapes project root: \"../out.conf\"\n" +
+		"path\ttpl-dev\tpath escapes project root: \"../tpl.tmpl\"\n"
+	if code != 4 || out != want {
+		t.Fatalf("unexpected escaping output: %d\n%s", code, out)
+	}
+
+	if code, out, _ := run(mismatchPath); code != 0 || !strings.Contains(out, "2 mappings checked") {
+		t.Fatalf("expected no remote check without --remote: %d %s", code, out)
+	}
+	code, out, _ = run(mismatchPath, "--remote", "--json")
+	var findings []validateFinding
+	if code != 5 || json.Unmarshal([]byte(out), &findings) != nil || len(findings) != 1 ||
+		findings[0] != (validateFinding{Check: "remote", Name: "tls-dev", Problem: "certificate payloads are PEM text, not a JSON object, so pull fails as dotenv; use format raw"}) {
+		t.Fatalf("unexpected remote json output: %d %s", code, out)
+	}
+
+	for _, tc := range []struct {
+		cfg  string
+		args []string
+	}{{cleanPath, nil}, {escapingPath, nil}, {escapingPath, []string{"--json"}}} {
+		var errBuf bytes.Buffer
+		if code := runValidate(commandContext{stdout: &failingWriter{}, stderr: &errBuf, configPath: tc.cfg, deps: deps}, tc.args); code != 1 {
+			t.Fatalf("%s %v: expected output error, got %d", tc.cfg, tc.args, code)
+		}
+	}
+
+	api.listErr = errors.New("list boom")
+	if code, _, errOut := run(mismatchPath, "--remote"); code != 1 || !strings.Contains(errOut, "list boom") {
+		t.Fatalf("expected lookup error, got %d %q", code, errOut)
+	}
+	deps.OpenSecretAPI = func(config.Config, string) (SecretAPI, error) { return nil, errors.New("open boom") }
+	if code, _, errOut := run(mismatchPath, "--remote"); code != 1 || !strings.Contains(errOut, "open boom") {
+		t.Fatalf("expected open error, got %d %q", code, errOut)
+	}
+}
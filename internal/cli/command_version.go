@@ -1,20 +1,38 @@
 package cli
 
-import "fmt"
+import (
+	"encoding/json"
+	"fmt"
+)
 
 var versionCommandDef = commandDef{
 	Name:    "version",
 	Summary: "Print build version information",
+	Flags: []commandFlagDef{
+		{Name: "json", Kind: commandFlagBool, Help: "Output JSON"},
+	},
 	Doc: commandDoc{
-		Synopsis: "dev-vault version",
+		Synopsis: "dev-vault version [--json]",
 		Description: []string{
 			"Prints the build version/commit/date.",
+			"With --json, also reports the Go version, platform, and provider SDK versions.",
+		},
+		Examples: []string{
+			"dev-vault version --json",
 		},
 	},
 	RunParsed: runVersionParsed,
 }
 
-func runVersionParsed(ctx commandContext, _ *parsedCommand) int {
+func runVersionParsed(ctx commandContext, parsed *parsedCommand) int {
+	if parsed.Bool("json") {
+		enc := json.NewEncoder(ctx.stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(currentBuildInfo(ctx.deps)); err != nil {
+			return exitCodeForError(outputError(err))
+		}
+		return 0
+	}
 	if _, err := fmt.Fprintf(ctx.stdout, "dev-vault %s (commit=%s date=%s)\n", ctx.deps.Version, ctx.deps.Commit, ctx.deps.Date); err != nil {
 		return exitCodeForError(outputError(err))
 	}
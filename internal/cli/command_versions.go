@@ -0,0 +1,95 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/bsmartlabs/dev-vault/internal/config"
+	"github.com/bsmartlabs/dev-vault/internal/i18n"
+	"github.com/bsmartlabs/dev-vault/internal/secretsync"
+)
+
+var versionsCommandDef = commandDef{
+	Name:    "versions",
+	Summary: "List the version history of a mapped secret",
+	Flags: []commandFlagDef{
+		{Name: "json", Kind: commandFlagBool, Help: "Output JSON"},
+	},
+	Doc: commandDoc{
+		Synopsis: "dev-vault [--config <path>] [--profile <name>] versions <secret-dev> [--json]",
+		Description: []string{
+			"Lists every version of a mapped secret, newest first, with its revision, status",
+			"(enabled, disabled, or destroyed where the provider has it), creation time, and",
+			"description. Payloads are never read.",
+		},
+		Notes: []string{
+			"Providers without version descriptions (aws, vault) leave DESCRIPTION empty.",
+			"aws versions written by other tools show as revision 0.",
+		},
+		Examples: []string{
+			"dev-vault versions db-dev",
+			"dev-vault versions db-dev --json",
+		},
+	},
+	RunParsed: runVersionsParsed,
+}
+
+type versionRecord struct {
+	Revision    uint32  `json:"revision"`
+	Status      string  `json:"status"`
+	CreatedAt   *string `json:"created_at"`
+	Description string  `json:"description,omitempty"`
+}
+
+func runVersions(ctx commandContext, argv []string) int {
+	return runCommand(ctx, argv, versionsCommandDef)
+}
+
+func runVersionsParsed(ctx commandContext, parsed *parsedCommand) int {
+	return newCommandRuntime(ctx, parsed).execute(func(loaded *config.Loaded, service secretsync.Service) error {
+		args := parsed.fs.Args()
+		if len(args) != 1 {
+			return usageError(i18n.Errorf(i18n.MsgTakesOneSecretName, "versions"))
+		}
+		targets, err := selectMappingTargetsForMode(loaded.Cfg.Mapping, false, args, commandModePull)
+		if err != nil {
+			return err
+		}
+		versions, err := service.Versions(targets[0])
+		if err != nil {
+			return runtimeError(err)
+		}
+		records := make([]versionRecord, 0, len(versions))
+		for _, version := range versions {
+			record := versionRecord{Revision: version.Revision, Status: version.Status, Description: version.Description}
+			if !version.CreatedAt.IsZero() {
+				created := version.CreatedAt.UTC().Format(time.RFC3339)
+				record.CreatedAt = &created
+			}
+			records = append(records, record)
+		}
+
+		if parsed.Bool("json") {
+			enc := json.NewEncoder(ctx.stdout)
+			enc.SetIndent("", "  ")
+			if err := enc.Encode(records); err != nil {
+				return outputError(err)
+			}
+			return nil
+		}
+
+		tbl := newTable(ctx.stdout, parsed.plain, "REVISION", "STATUS", "CREATED", "DESCRIPTION")
+		for _, record := range records {
+			created := "-"
+			if record.CreatedAt != nil {
+				created = *record.CreatedAt
+			}
+			tbl.row(fmt.Sprintf("%d", record.Revision), record.Status, created, record.Description)
+		}
+		if err := tbl.flush(); err != nil {
+			return outputError(err)
+		}
+		return nil
+	})
+}
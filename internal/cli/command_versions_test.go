@@ -0,0 +1,91 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/bsmartlabs/dev-vault/internal/config"
+	secret "github.com/scaleway/scaleway-sdk-go/api/secret/v1beta1"
+)
+
+func TestRunVersions(t *testing.T) {
+	dir := t.TempDir()
+	cfgPath := writeConfig(t, dir, `{"organization_id":"org","project_id":"proj","region":"fr-par","mapping":{
+		"db-dev":{"file":"db.json"},
+		"push-dev":{"file":"push.json","mode":"push"}
+	}}`)
+	api := newFakeSecretAPI()
+	sec := api.AddSecret("proj", "db-dev", "/", secret.SecretTypeOpaque)
+	api.AddEnabledVersion(sec.ID, []byte("first"))
+	description, disable := "rotated", true
+	if _, err := api.CreateSecretVersion(CreateSecretVersionInput{SecretID: sec.ID, Data: []byte("second"), Description: &description, DisablePrevious: &disable}); err != nil {
+		t.Fatalf("create version: %v", err)
+	}
+	deps := baseDeps(func(config.Config, string) (SecretAPI, error) { return api, nil })
+	run := func(args ...string) (int, string, string) {
+		var out, errBuf bytes.Buffer
+		code := Run(append([]string{"dev-vault", "--config", cfgPath}, args...), &out, &errBuf, deps)
+		return code, out.String(), errBuf.String()
+	}
+
+	code, out, errOut := run("--plain", "versions", "db-dev")
+	want := "REVISION\tSTATUS\tCREATED\tDESCRIPTION\n" +
+		"2\tenabled\t2026-01-01T02:00:00Z\trotated\n" +
+		"1\tdisabled\t2026-01-01T01:00:00Z\t\n"
+	if code != 0 || out != want {
+		t.Fatalf("unexpected output: %d %q\n%s", code, errOut, out)
+	}
+	if strings.Contains(out, "first") || strings.Contains(out, "second") {
+		t.Fatal("versions must never print payloads")
+	}
+
+	code, out, _ = run("versions", "db-dev", "--json")
+	var records []versionRecord
+	if code != 0 || json.Unmarshal([]byte(out), &records) != nil || len(records) != 2 || records[0].Revision != 2 || *records[1].CreatedAt != "2026-01-01T01:00:00Z" {
+		t.Fatalf("unexpected json output: %d %s", code, out)
+	}
+
+	for _, tc := range []struct {
+		args    []string
+		code    int
+		wantErr string
+	}{
+		{[]string{"versions"}, 2, "versions takes exactly one secret name"},
+		{[]string{"versions", "db"}, 2, "refusing non-dev secret name"},
+		{[]string{"versions", "push-dev"}, 2, "not allowed in pull mode"},
+		{[]string{"versions", "other-dev"}, 2, "not found in mapping"},
+	} {
+		if code, _, errOut := run(tc.args...); code != tc.code || !strings.Contains(errOut, tc.wantErr) {
+			t.Fatalf("%v: expected %d %q, got %d %q", tc.args, tc.code, tc.wantErr, code, errOut)
+		}
+	}
+
+	for _, args := range [][]string{{"db-dev"}, {"db-dev", "--json"}} {
+		var errBuf bytes.Buffer
+		if code := runVersions(commandContext{stdout: &failingWriter{}, stderr: &errBuf, configPath: cfgPath, deps: deps}, args); code != 1 {
+			t.Fatalf("%v: expected output error, got %d", args, code)
+		}
+	}
+
+	t.Run("UnknownCreationTime", func(t *testing.T) {
+		stub := &stubSecretAPI{
+			listFn: api.ListSecrets,
+			listVersions: func(req ListSecretVersionsInput) ([]SecretVersionRecord, error) {
+				return []SecretVersionRecord{{SecretID: req.SecretID, Status: "enabled"}}, nil
+			},
+		}
+		var out, errBuf bytes.Buffer
+		code := Run([]string{"dev-vault", "--config", cfgPath, "--plain", "versions", "db-dev"}, &out, &errBuf, baseDeps(func(config.Config, string) (SecretAPI, error) { return stub, nil }))
+		if code != 0 || !strings.HasSuffix(out.String(), "0\tenabled\t-\t\n") {
+			t.Fatalf("unexpected output: %d %q\n%s", code, errBuf.String(), out.String())
+		}
+	})
+
+	api.versionsErr = errors.New("versions boom")
+	if code, _, errOut := run("versions", "db-dev"); code != 1 || !strings.Contains(errOut, "list versions db-dev: versions boom") {
+		t.Fatalf("expected list error, got %d %q", code, errOut)
+	}
+}
@@ -0,0 +1,161 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/bsmartlabs/dev-vault/internal/checkouts"
+	"github.com/bsmartlabs/dev-vault/internal/config"
+	"github.com/bsmartlabs/dev-vault/internal/i18n"
+	"github.com/bsmartlabs/dev-vault/internal/secretsync"
+)
+
+var whereCommandDef = commandDef{
+	Name:    "where",
+	Summary: "List the project checkouts on this machine that use dev-vault, with their local drift",
+	Flags: []commandFlagDef{
+		{Name: "json", Kind: commandFlagBool, Help: "Output JSON"},
+		{Name: "prune", Kind: commandFlagBool, Help: "Forget checkouts whose config file no longer exists"},
+	},
+	Doc: commandDoc{
+		Synopsis: "dev-vault where [--json] [--prune]",
+		Description: []string{
+			"Every command that loads a config records its path in the user state dir; where lists",
+			"those checkouts with the local status of their mapped files, like prompt does for one.",
+			"Only manifests and local files are read: the provider is never contacted.",
+		},
+		Notes: []string{
+			"status is ok, drift (files missing or expired, see pull --expire-after), gone (the",
+			"config file no longer exists), or invalid (the config does not load).",
+			"Checkouts are recorded at most once an hour, so last_used is accurate to the hour.",
+			"--prune forgets the gone checkouts, for example deleted clones.",
+		},
+		Examples: []string{
+			"dev-vault where",
+			"dev-vault where --json",
+			"dev-vault where --prune",
+		},
+	},
+	RunParsed: runWhereParsed,
+}
+
+const (
+	checkoutStatusOK      = "ok"
+	checkoutStatusDrift   = "drift"
+	checkoutStatusGone    = "gone"
+	checkoutStatusInvalid = "invalid"
+)
+
+type whereRecord struct {
+	Dir      string `json:"dir"`
+	Config   string `json:"config"`
+	Status   string `json:"status"`
+	Mapped   int    `json:"mapped"`
+	Present  int    `json:"present"`
+	Missing  int    `json:"missing"`
+	Expired  int    `json:"expired"`
+	LastUsed string `json:"last_used"`
+	Error    string `json:"error,omitempty"`
+}
+
+func runWhere(ctx commandContext, argv []string) int {
+	return runCommand(ctx, argv, whereCommandDef)
+}
+
+func runWhereParsed(ctx commandContext, parsed *parsedCommand) int {
+	return newCommandRuntime(ctx, parsed).executeStandalone(func() error {
+		if len(parsed.fs.Args()) > 0 {
+			return usageError(i18n.Errorf(i18n.MsgTakesNoArguments, "where"))
+		}
+		dir, err := stateDir(ctx.deps)
+		if err != nil {
+			return runtimeError(err)
+		}
+		store := checkouts.NewStore(dir)
+		listed, err := store.List()
+		if err != nil {
+			return runtimeError(err)
+		}
+		records := make([]whereRecord, 0, len(listed))
+		var gone []string
+		for _, checkout := range listed {
+			record := checkoutRecord(ctx.deps, checkout)
+			if record.Status == checkoutStatusGone && parsed.Bool("prune") {
+				gone = append(gone, checkout.Config)
+				continue
+			}
+			records = append(records, record)
+		}
+		if len(gone) > 0 {
+			if err := store.Forget(gone...); err != nil {
+				return runtimeError(err)
+			}
+		}
+
+		if parsed.Bool("json") {
+			enc := json.NewEncoder(ctx.stdout)
+			enc.SetIndent("", "  ")
+			if err := enc.Encode(records); err != nil {
+				return outputError(err)
+			}
+			return nil
+		}
+		tbl := newTable(ctx.stdout, parsed.plain, "DIR", "STATUS", "FILES", "MISSING", "EXPIRED", "LAST_USED")
+		for _, record := range records {
+			files, missing, expired := "-", "-", "-"
+			if record.Status == checkoutStatusOK || record.Status == checkoutStatusDrift {
+				files = fmt.Sprintf("%d/%d", record.Present, record.Mapped)
+				missing, expired = strconv.Itoa(record.Missing), strconv.Itoa(record.Expired)
+			}
+			tbl.row(record.Dir, record.Status, files, missing, expired, record.LastUsed)
+		}
+		if err := tbl.flush(); err != nil {
+			return outputError(err)
+		}
+		return nil
+	})
+}
+
+// checkoutRecord reads the manifest and local files of one checkout. Unknown
+// fields are ignored, since another checkout may need a newer dev-vault.
+func checkoutRecord(deps Dependencies, checkout checkouts.Checkout) whereRecord {
+	record := whereRecord{
+		Dir:      filepath.Dir(checkout.Config),
+		Config:   checkout.Config,
+		LastUsed: checkout.LastUsed.UTC().Format(time.RFC3339),
+	}
+	if _, err := os.Stat(checkout.Config); errors.Is(err, os.ErrNotExist) {
+		record.Status = checkoutStatusGone
+		return record
+	}
+	loaded, err := config.LoadWithOptions(record.Dir, checkout.Config, config.LoadOptions{IgnoreUnknown: true})
+	if err != nil {
+		record.Status, record.Error = checkoutStatusInvalid, err.Error()
+		return record
+	}
+	record.Dir = loaded.Root
+	service := secretsync.NewFromLoaded(loaded, nil, secretsync.Dependencies{Now: deps.Now})
+	local := service.LocalStatus(pullTimes(deps, loaded))
+	record.Mapped, record.Present, record.Missing = local.Mapped, local.Present, local.Missing
+	record.Expired = len(expiredFiles(deps, loaded, service))
+	record.Status = checkoutStatusOK
+	if record.Missing > 0 || record.Expired > 0 {
+		record.Status = checkoutStatusDrift
+	}
+	return record
+}
+
+// recordCheckout notes that the command runs in loaded's checkout, for
+// where. Like telemetry it is best effort and never fails the command.
+func recordCheckout(deps Dependencies, loaded *config.Loaded) {
+	dir, err := stateDir(deps)
+	if err != nil {
+		return
+	}
+	_ = checkouts.NewStore(dir).Record(loaded.Path, deps.Now())
+}
@@ -0,0 +1,130 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bsmartlabs/dev-vault/internal/config"
+)
+
+func TestRunWhere(t *testing.T) {
+	userDir := t.TempDir()
+	deps := baseDeps(func(config.Config, string) (SecretAPI, error) { return newFakeSecretAPI(), nil })
+	deps.UserConfigDir = func() (string, error) { return userDir, nil }
+	deps.Now = func() time.Time { return time.Date(2026, 5, 6, 7, 0, 0, 0, time.UTC) }
+	run := func(args ...string) (int, string, string) {
+		var out, errBuf bytes.Buffer
+		code := Run(append([]string{"dev-vault"}, args...), &out, &errBuf, deps)
+		return code, out.String(), errBuf.String()
+	}
+
+	// Checkouts are recorded by the commands that load their config.
+	projects := map[string]string{}
+	for _, name := range []string{"clean", "drifted", "deleted", "broken"} {
+		root := filepath.Join(t.TempDir(), name)
+		if err := os.Mkdir(root, 0o700); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		projects[name] = root
+		cfgPath := writeConfig(t, root, `{"organization_id":"org","project_id":"proj","region":"fr-par","mapping":{"a-dev":{"file":"a.env"},"b-dev":{"file":"b.env"}}}`)
+		for _, file := range []string{"a.env", "b.env"} {
+			if err := os.WriteFile(filepath.Join(root, file), []byte("A=1\n"), 0o600); err != nil {
+				t.Fatalf("write %s: %v", file, err)
+			}
+		}
+		if code, _, errOut := run("--config", cfgPath, "prompt"); code != 0 {
+			t.Fatalf("prompt in %s: %d %q", name, code, errOut)
+		}
+	}
+	if err := os.Remove(filepath.Join(projects["drifted"], "b.env")); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	if err := os.RemoveAll(projects["deleted"]); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	writeConfig(t, projects["broken"], "{")
+
+	code, out, errOut := run("where", "--json")
+	if code != 0 {
+		t.Fatalf("where --json: %d %q", code, errOut)
+	}
+	var records []whereRecord
+	if err := json.Unmarshal([]byte(out), &records); err != nil || len(records) != 4 {
+		t.Fatalf("expected 4 checkouts, got %q %v", out, err)
+	}
+	byDir := map[string]whereRecord{}
+	for _, record := range records {
+		byDir[record.Dir] = record
+	}
+	if got := byDir[projects["clean"]]; got.Status != "ok" || got.Mapped != 2 || got.Present != 2 || got.LastUsed != "2026-05-06T07:00:00Z" {
+		t.Fatalf("unexpected clean checkout: %+v", got)
+	}
+	if got := byDir[projects["drifted"]]; got.Status != "drift" || got.Missing != 1 {
+		t.Fatalf("unexpected drifted checkout: %+v", got)
+	}
+	if got := byDir[projects["deleted"]]; got.Status != "gone" || got.Config != filepath.Join(projects["deleted"], config.DefaultConfigName) {
+		t.Fatalf("unexpected deleted checkout: %+v", got)
+	}
+	if got := byDir[projects["broken"]]; got.Status != "invalid" || !strings.Contains(got.Error, "decode config json") {
+		t.Fatalf("unexpected broken checkout: %+v", got)
+	}
+
+	code, out, errOut = run("where", "--prune")
+	if code != 0 || strings.Contains(out, "gone") || !strings.Contains(out, "ok") || !strings.Contains(out, "2/2") || !strings.Contains(out, "invalid") {
+		t.Fatalf("unexpected table: %d %q %q", code, out, errOut)
+	}
+	if _, out, _ := run("where", "--json"); strings.Contains(out, `"gone"`) {
+		t.Fatalf("expected the gone checkout forgotten, got %q", out)
+	}
+
+	for _, tc := range []struct {
+		args    []string
+		code    int
+		wantErr string
+	}{
+		{[]string{"where", "extra"}, 2, "where takes no arguments"},
+	} {
+		if code, _, errOut := run(tc.args...); code != tc.code || !strings.Contains(errOut, tc.wantErr) {
+			t.Fatalf("%v: expected %d %q, got %d %q", tc.args, tc.code, tc.wantErr, code, errOut)
+		}
+	}
+	for _, args := range [][]string{{}, {"--json"}} {
+		var errBuf bytes.Buffer
+		if code := runWhere(commandContext{stdout: &failingWriter{}, stderr: &errBuf, deps: deps}, args); code != 1 {
+			t.Fatalf("%v: expected output failure, got %d", args, code)
+		}
+	}
+
+	// Reading the other checkouts asks for the state dir again; the list
+	// turns unreadable then, before the deleted checkout is forgotten.
+	if err := os.RemoveAll(projects["clean"]); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	checkoutsPath := filepath.Join(userDir, stateDirName, "checkouts.json")
+	deps.UserConfigDir = func() func() (string, error) {
+		calls := 0
+		return func() (string, error) {
+			if calls++; calls > 1 {
+				_ = os.WriteFile(checkoutsPath, []byte("{"), 0o600)
+			}
+			return userDir, nil
+		}
+	}()
+	if code, _, errOut := run("where", "--prune"); code != 1 || !strings.Contains(errOut, "decode checkouts") {
+		t.Fatalf("expected the forget error, got %d %q", code, errOut)
+	}
+	// The list is now unreadable, and without a state dir there is none.
+	if code, _, errOut := run("where"); code != 1 || !strings.Contains(errOut, "decode checkouts") {
+		t.Fatalf("expected the list error, got %d %q", code, errOut)
+	}
+	deps.UserConfigDir = func() (string, error) { return "", errors.New("no config dir") }
+	if code, _, errOut := run("where"); code != 1 || !strings.Contains(errOut, "locate user config dir") {
+		t.Fatalf("expected the state dir error, got %d %q", code, errOut)
+	}
+}
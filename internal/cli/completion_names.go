@@ -0,0 +1,106 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/bsmartlabs/dev-vault/internal/config"
+	"github.com/bsmartlabs/dev-vault/internal/namecache"
+	"github.com/bsmartlabs/dev-vault/internal/secretsync"
+)
+
+const (
+	// completionRemoteEnvVar opts shell completion into remote -dev names.
+	completionRemoteEnvVar = "DEV_VAULT_COMPLETE_REMOTE"
+	// completionNamesTTL is how long cached remote names are used before a
+	// refresh is tried.
+	completionNamesTTL = 10 * time.Minute
+	// completionNamesWait bounds how long a refresh may hold up the shell.
+	completionNamesWait = 100 * time.Millisecond
+)
+
+// writeCompletionNames prints the names completing a secret argument: the
+// mapping names and, with DEV_VAULT_COMPLETE_REMOTE=1, the cached remote -dev
+// names. Completion must never get in the way, so a missing config or an
+// offline provider prints what is known and no error.
+func writeCompletionNames(ctx commandContext, parsed *parsedCommand) error {
+	loaded, err := loadConfig(parsed.configPath, parsed.loadOptions(), ctx.deps)
+	if err != nil {
+		return nil
+	}
+	seen := make(map[string]bool, len(loaded.Cfg.Mapping))
+	for name := range loaded.Cfg.Mapping {
+		seen[name] = true
+	}
+	if ctx.deps.Getenv(completionRemoteEnvVar) == "1" {
+		for _, name := range remoteCompletionNames(ctx, parsed, loaded) {
+			seen[name] = true
+		}
+	}
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if _, err := fmt.Fprintln(ctx.stdout, name); err != nil {
+			return outputError(err)
+		}
+	}
+	return nil
+}
+
+// remoteCompletionNames returns the cached remote names. A missing or stale
+// cache is refreshed in the background for at most completionNamesWait; when
+// the provider fails or is slower, the stale names are used.
+func remoteCompletionNames(ctx commandContext, parsed *parsedCommand, loaded *config.Loaded) []string {
+	dir, err := stateDir(ctx.deps)
+	if err != nil {
+		return nil
+	}
+	store := namecache.NewStore(dir)
+	entry, ok, _ := store.Load(loaded.Path, parsed.profileOverride)
+	if ok && ctx.deps.Now().Sub(entry.UpdatedAt) < completionNamesTTL {
+		return entry.Names
+	}
+	refreshed := make(chan []string, 1)
+	go func() {
+		records, err := listCompletionNames(ctx.deps, loaded, parsed.profileOverride)
+		if err != nil {
+			refreshed <- entry.Names
+			return
+		}
+		refreshed <- saveCompletionNames(ctx.deps, loaded, parsed.profileOverride, records)
+	}()
+	select {
+	case names := <-refreshed:
+		return names
+	case <-time.After(completionNamesWait):
+		return entry.Names
+	}
+}
+
+func listCompletionNames(deps Dependencies, loaded *config.Loaded, profileOverride string) ([]secretsync.ListRecord, error) {
+	api, err := openSecretAPI(loaded.Cfg, profileOverride, deps)
+	if err != nil {
+		return nil, err
+	}
+	service := secretsync.NewFromLoaded(loaded, api, secretsync.Dependencies{Now: deps.Now, Hostname: deps.Hostname, Getenv: deps.Getenv})
+	return service.List(secretsync.ListQuery{})
+}
+
+// saveCompletionNames caches the names of a full listing for completion. It
+// is best effort: without a state dir, or when the write fails, nothing is
+// cached.
+func saveCompletionNames(deps Dependencies, loaded *config.Loaded, profileOverride string, records []secretsync.ListRecord) []string {
+	names := make([]string, 0, len(records))
+	for _, record := range records {
+		names = append(names, record.Name)
+	}
+	if dir, err := stateDir(deps); err == nil {
+		entry := namecache.Entry{Config: loaded.Path, Profile: profileOverride, UpdatedAt: deps.Now(), Names: names}
+		_ = namecache.NewStore(dir).Save(entry)
+	}
+	return names
+}
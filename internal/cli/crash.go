@@ -0,0 +1,102 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/bsmartlabs/dev-vault/internal/fsx"
+)
+
+const (
+	crashDirName    = "crashes"
+	issueTrackerURL = "https://github.com/bsmartlabs/dev-vault/issues"
+	redacted        = "<redacted>"
+)
+
+// reportCrash writes a crash log under the state dir and tells the user how
+// to share it. Nothing is uploaded. The log holds the build info, the stack,
+// and the command line with every value (flag values, secret names) redacted.
+func reportCrash(stderr io.Writer, deps Dependencies, args []string, recovered any, stack []byte) int {
+	report := crashReport(deps, args, recovered, stack)
+	path, err := writeCrashLog(deps, report)
+	if err != nil {
+		_, _ = fmt.Fprintf(stderr, "dev-vault crashed: %s (could not write crash log: %v)\n", panicSummary(recovered), err)
+		return 1
+	}
+	_, _ = fmt.Fprintf(stderr, "dev-vault crashed: %s\n", panicSummary(recovered))
+	_, _ = fmt.Fprintf(stderr, "A crash log was written to %s\n", path)
+	_, _ = fmt.Fprintf(stderr, "It contains no secret values; review it, then attach it to an issue at %s\n", issueTrackerURL)
+	return 1
+}
+
+func crashReport(deps Dependencies, args []string, recovered any, stack []byte) string {
+	var b strings.Builder
+	b.WriteString("dev-vault crash report\n")
+	info := currentBuildInfo(deps)
+	fmt.Fprintf(&b, "version: %s (commit=%s date=%s)\n", info.Version, info.Commit, info.Date)
+	fmt.Fprintf(&b, "go: %s %s\n", info.GoVersion, info.Platform)
+	for _, sdk := range info.ProviderSDKs {
+		fmt.Fprintf(&b, "sdk: %s %s\n", sdk.Module, sdk.Version)
+	}
+	fmt.Fprintf(&b, "command line: %s\n", redactCommandLine(args))
+	fmt.Fprintf(&b, "panic: %s\n\n", panicSummary(recovered))
+	b.Write(stack)
+	return b.String()
+}
+
+func writeCrashLog(deps Dependencies, report string) (string, error) {
+	dir, err := stateDir(deps)
+	if err != nil {
+		return "", err
+	}
+	name := fmt.Sprintf("crash-%s.log", deps.Now().UTC().Format("20060102T150405Z"))
+	path := filepath.Join(dir, crashDirName, name)
+	if err := fsx.AtomicWriteFile(path, []byte(report), 0o600, true); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// panicSummary keeps runtime error messages (index out of range, nil
+// dereference), which never embed user data, and reduces any other panic
+// value to its type so ad-hoc panic messages cannot leak payloads.
+func panicSummary(recovered any) string {
+	if err, ok := recovered.(runtime.Error); ok {
+		return err.Error()
+	}
+	return fmt.Sprintf("%T value", recovered)
+}
+
+// redactCommandLine keeps the program name, command names, and flag names;
+// flag values and positional arguments (secret names, paths) are replaced.
+func redactCommandLine(args []string) string {
+	out := make([]string, 0, len(args))
+	for i, arg := range args {
+		switch {
+		case i == 0:
+			out = append(out, filepath.Base(arg))
+		case strings.HasPrefix(arg, "-"):
+			if name, _, hasValue := strings.Cut(arg, "="); hasValue {
+				out = append(out, name+"="+redacted)
+			} else {
+				out = append(out, arg)
+			}
+		case isCommandName(arg):
+			out = append(out, arg)
+		default:
+			out = append(out, redacted)
+		}
+	}
+	return strings.Join(out, " ")
+}
+
+func isCommandName(arg string) bool {
+	if arg == "help" {
+		return true
+	}
+	_, ok := commandForName(arg)
+	return ok
+}
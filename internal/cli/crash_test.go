@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bsmartlabs/dev-vault/internal/config"
+)
+
+func TestRun_RecoversPanicAndWritesCrashLog(t *testing.T) {
+	root := t.TempDir()
+	cfgPath := writeConfig(t, root, `{"organization_id":"org","project_id":"proj","region":"fr-par","mapping":{"app-dev":{"file":"app.env"}}}`)
+	stateRoot := t.TempDir()
+	deps := baseDeps(func(cfg config.Config, s string) (SecretAPI, error) {
+		var api map[string]SecretAPI
+		api["boom"] = nil // nil map write: a runtime error panic
+		return nil, nil
+	})
+	deps.UserConfigDir = func() (string, error) { return stateRoot, nil }
+	deps.Now = func() time.Time { return time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC) }
+
+	var out, errBuf bytes.Buffer
+	code := Run([]string{"/usr/local/bin/dev-vault", "--config", cfgPath, "pull", "app-dev", "--profile=work"}, &out, &errBuf, deps)
+	if code != 1 {
+		t.Fatalf("expected 1, got %d", code)
+	}
+	logPath := filepath.Join(stateRoot, "dev-vault", "crashes", "crash-20240506T070809Z.log")
+	if !strings.Contains(errBuf.String(), "dev-vault crashed: assignment to entry in nil map") ||
+		!strings.Contains(errBuf.String(), logPath) ||
+		!strings.Contains(errBuf.String(), "https://github.com/bsmartlabs/dev-vault/issues") {
+		t.Fatalf("unexpected stderr: %q", errBuf.String())
+	}
+
+	raw, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("read crash log: %v", err)
+	}
+	log := string(raw)
+	for _, want := range []string{
+		"version: v (commit=c date=d)",
+		"command line: dev-vault --config <redacted> pull <redacted> --profile=<redacted>",
+		"panic: assignment to entry in nil map",
+		"goroutine ",
+	} {
+		if !strings.Contains(log, want) {
+			t.Fatalf("missing %q in crash log:\n%s", want, log)
+		}
+	}
+	for _, leaked := range []string{cfgPath, "app-dev", "work"} {
+		if strings.Contains(strings.SplitN(log, "\n\n", 2)[0], leaked) {
+			t.Fatalf("crash log header leaked %q:\n%s", leaked, log)
+		}
+	}
+}
+
+func TestRun_PanicWithoutStateDir(t *testing.T) {
+	deps := baseDeps(func(cfg config.Config, s string) (SecretAPI, error) { return newFakeSecretAPI(), nil })
+	deps.Getwd = func() (string, error) { panic("secret-looking message") }
+
+	var out, errBuf bytes.Buffer
+	code := Run([]string{"dev-vault", "list"}, &out, &errBuf, deps)
+	if code != 1 {
+		t.Fatalf("expected 1, got %d", code)
+	}
+	if !strings.Contains(errBuf.String(), "dev-vault crashed: string value (could not write crash log: locate user config dir") ||
+		strings.Contains(errBuf.String(), "secret-looking") {
+		t.Fatalf("unexpected stderr: %q", errBuf.String())
+	}
+}
+
+func TestWriteCrashLog_WriteError(t *testing.T) {
+	base := t.TempDir()
+	if err := os.WriteFile(filepath.Join(base, "dev-vault"), nil, 0o600); err != nil {
+		t.Fatalf("write blocker: %v", err)
+	}
+	deps := baseDeps(nil)
+	deps.UserConfigDir = func() (string, error) { return base, nil }
+	if _, err := writeCrashLog(deps, "report"); err == nil {
+		t.Fatal("expected write error")
+	}
+}
+
+func TestRedactCommandLine(t *testing.T) {
+	got := redactCommandLine([]string{"dev-vault", "--plain", "help", "push", "--description", "note", "a-dev", "--yes"})
+	want := "dev-vault --plain help push --description <redacted> <redacted> --yes"
+	if got != want {
+		t.Fatalf("redactCommandLine = %q, want %q", got, want)
+	}
+	if got := panicSummary(errors.New("x")); got != "*errors.errorString value" {
+		t.Fatalf("unexpected summary: %q", got)
+	}
+}
@@ -0,0 +1,35 @@
+package cli
+
+import (
+	"github.com/bsmartlabs/dev-vault/internal/features"
+	"github.com/bsmartlabs/dev-vault/internal/i18n"
+)
+
+// requireFeature refuses an experimental command unless the user enabled its
+// feature.
+func requireFeature(deps Dependencies, def commandDef) error {
+	dir, err := stateDir(deps)
+	if err != nil {
+		return runtimeError(err)
+	}
+	set, err := features.Load(dir, deps.Getenv)
+	if err != nil {
+		return runtimeError(i18n.Errorf(i18n.MsgLoadFeatures, err))
+	}
+	if set.Enabled(def.Experimental) {
+		return nil
+	}
+	return usageError(i18n.Errorf(i18n.MsgExperimental, def.Name, features.EnvVar, def.Experimental, def.Experimental, features.UserConfigPath(dir)))
+}
+
+// listedCommandDefs are the commands shown in the main usage and searched by
+// help; experimental ones stay out until they are stable.
+func listedCommandDefs() []commandDef {
+	listed := make([]commandDef, 0, len(commandDefs))
+	for _, def := range commandDefs {
+		if def.Experimental == "" {
+			listed = append(listed, def)
+		}
+	}
+	return listed
+}
@@ -0,0 +1,81 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bsmartlabs/dev-vault/internal/config"
+)
+
+func TestRun_ExperimentalCommand(t *testing.T) {
+	ran := false
+	original := commandDefs
+	commandDefs = append(append([]commandDef{}, original...), commandDef{
+		Name:         "labs",
+		Summary:      "Experimental test command",
+		Doc:          commandDoc{Synopsis: "dev-vault labs"},
+		Experimental: "labs",
+		RunParsed: func(commandContext, *parsedCommand) int {
+			ran = true
+			return 0
+		},
+	})
+	t.Cleanup(func() { commandDefs = original })
+
+	dir := t.TempDir()
+	env := map[string]string{}
+	deps := baseDeps(func(config.Config, string) (SecretAPI, error) { return nil, nil })
+	deps.UserConfigDir = func() (string, error) { return dir, nil }
+	deps.Getenv = func(key string) string { return env[key] }
+	run := func() (int, string) {
+		var errBuf bytes.Buffer
+		code := Run([]string{"dev-vault", "labs"}, &bytes.Buffer{}, &errBuf, deps)
+		return code, errBuf.String()
+	}
+
+	userConfig := filepath.Join(dir, "dev-vault", "config.json")
+	if code, errOut := run(); code != 2 || ran || errOut != `labs is experimental; enable it with DEV_VAULT_EXPERIMENTAL=labs or "features": ["labs"] in `+userConfig+"\n" {
+		t.Fatalf("expected refusal, got %d %q", code, errOut)
+	}
+
+	env["DEV_VAULT_EXPERIMENTAL"] = "agent,labs"
+	if code, errOut := run(); code != 0 || !ran {
+		t.Fatalf("expected the command to run, got %d %q", code, errOut)
+	}
+	delete(env, "DEV_VAULT_EXPERIMENTAL")
+
+	if err := os.MkdirAll(filepath.Dir(userConfig), 0o700); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	ran = false
+	if err := os.WriteFile(userConfig, []byte(`{"features":["labs"]}`), 0o600); err != nil {
+		t.Fatalf("write user config: %v", err)
+	}
+	if code, errOut := run(); code != 0 || !ran {
+		t.Fatalf("expected the command to run, got %d %q", code, errOut)
+	}
+
+	if err := os.WriteFile(userConfig, []byte(`{`), 0o600); err != nil {
+		t.Fatalf("write user config: %v", err)
+	}
+	if code, errOut := run(); code != 1 || !strings.HasPrefix(errOut, "load experimental features: parse ") {
+		t.Fatalf("expected a parse failure, got %d %q", code, errOut)
+	}
+	deps.UserConfigDir = func() (string, error) { return "", errors.New("no home") }
+	if code, errOut := run(); code != 1 || !strings.Contains(errOut, "locate user config dir: no home") {
+		t.Fatalf("expected a config dir failure, got %d %q", code, errOut)
+	}
+
+	// Experimental commands stay out of the command list and help search.
+	var out bytes.Buffer
+	if code := Run([]string{"dev-vault", "help", "--all"}, &out, &bytes.Buffer{}, deps); code != 0 || strings.Contains(out.String(), "labs") {
+		t.Fatalf("expected labs to be unlisted, got %d %q", code, out.String())
+	}
+	if code := Run([]string{"dev-vault", "help", "search", "labs"}, &bytes.Buffer{}, &bytes.Buffer{}, deps); code != 1 {
+		t.Fatalf("expected no search match, got %d", code)
+	}
+}
@@ -0,0 +1,98 @@
+package cli
+
+import (
+	"time"
+
+	"github.com/bsmartlabs/dev-vault/internal/config"
+	"github.com/bsmartlabs/dev-vault/internal/expiry"
+	"github.com/bsmartlabs/dev-vault/internal/i18n"
+	"github.com/bsmartlabs/dev-vault/internal/secretsync"
+)
+
+// loadExpiry reads the pull times and expiries recorded for the files of
+// loaded's config.
+func loadExpiry(deps Dependencies, loaded *config.Loaded) (expiry.Store, expiry.Record, error) {
+	dir, err := stateDir(deps)
+	if err != nil {
+		return expiry.Store{}, expiry.Record{}, err
+	}
+	store := expiry.NewStore(dir)
+	record, err := store.Load(loaded.Path)
+	return store, record, err
+}
+
+// recordPull records that the files of results were pulled now and expire
+// ttl from now, or drops their expiries when ttl is zero, since a fresh pull
+// replaced them. Without an expiry to record, a record that cannot be read is
+// not reported: list --stale and prompt then fall back to the files'
+// modification times.
+func recordPull(deps Dependencies, loaded *config.Loaded, ttl time.Duration, results []secretsync.PullResult) error {
+	if len(results) == 0 {
+		return nil
+	}
+	store, record, err := loadExpiry(deps, loaded)
+	if err != nil {
+		if ttl == 0 {
+			return nil
+		}
+		return err
+	}
+	now := deps.Now()
+	var expires time.Time
+	if ttl > 0 {
+		expires = now.Add(ttl)
+	}
+	for _, item := range results {
+		record.Set(item.Name, expires)
+		record.SetPulled(item.Name, now)
+	}
+	return store.Save(record)
+}
+
+// pullTimes returns when the files of loaded's mappings were last pulled, by
+// secret name. Without a readable record there are none, and the files'
+// modification times stand in.
+func pullTimes(deps Dependencies, loaded *config.Loaded) map[string]time.Time {
+	_, record, _ := loadExpiry(deps, loaded)
+	return record.Pulled
+}
+
+// expiredFiles returns the present files of loaded's enabled mappings whose
+// expiry has passed. Without a readable record nothing has expired.
+func expiredFiles(deps Dependencies, loaded *config.Loaded, service secretsync.Service) []secretsync.LocalFile {
+	_, record, err := loadExpiry(deps, loaded)
+	if err != nil {
+		return nil
+	}
+	return service.ExpiredFiles(record.Expires)
+}
+
+// pullExpireAfter is how long pulled files stay fresh: --expire-after, else
+// the manifest's expire_after, else zero for never.
+func pullExpireAfter(parsed *parsedCommand, loaded *config.Loaded) (time.Duration, error) {
+	value := parsed.String("expire-after")
+	if value == "" {
+		if loaded.Cfg.ExpireAfter == "" {
+			return 0, nil
+		}
+		age, _ := config.ParseAge(loaded.Cfg.ExpireAfter) // validated on load
+		return age, nil
+	}
+	age, err := config.ParseAge(value)
+	if err != nil {
+		return 0, usageError(i18n.Errorf(i18n.MsgInvalidFlag, "expire-after", err))
+	}
+	return age, nil
+}
+
+// warnExpiryNotRecorded reports a failed recordPull; the files are written,
+// so the pull itself still succeeds.
+func warnExpiryNotRecorded(parsed *parsedCommand, err error) error {
+	if err == nil {
+		return nil
+	}
+	if err := parsed.warnings.warnError(warningExpiry, i18n.Errorf(i18n.MsgWarnExpiryNotRecorded, err)); err != nil {
+		return outputError(err)
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package cli
+
+import (
+	"github.com/bsmartlabs/dev-vault/internal/config"
+	"github.com/bsmartlabs/dev-vault/internal/i18n"
+)
+
+// runExplainConfig serves the global --explain-config flag: it prints every
+// effective manifest value with the file line, flag, or default it came from
+// and exits without running the command it was given with.
+func runExplainConfig(ctx commandContext, parsed *parsedCommand) int {
+	return newCommandRuntime(ctx, parsed).executeStandalone(func() error {
+		origins, err := explainConfig(parsed.configPath, parsed.profileOverride, parsed.environment, ctx.deps)
+		if err != nil {
+			return runtimeError(err)
+		}
+		table := newTable(ctx.stdout, parsed.plain, "FIELD", "VALUE", "SOURCE")
+		for _, origin := range origins {
+			value := origin.Value
+			if value == "" {
+				value = "(none)"
+			}
+			table.row(origin.Field, value, origin.Source)
+		}
+		if err := table.flush(); err != nil {
+			return outputError(err)
+		}
+		return nil
+	})
+}
+
+// explainConfig adds the command-line layers to the manifest provenance: how
+// the manifest was found and which Scaleway profile the provider will use.
+func explainConfig(configPath, profileOverride, environment string, deps Dependencies) ([]config.Origin, error) {
+	wd, err := deps.Getwd()
+	if err != nil {
+		return nil, i18n.Errorf(i18n.MsgGetwd, err)
+	}
+	path, err := config.LocatePath(wd, configPath)
+	if err != nil {
+		return nil, err
+	}
+	manifest, err := config.Explain(path, environment)
+	if err != nil {
+		return nil, err
+	}
+
+	located := "found searching upward from " + wd
+	if configPath != "" {
+		located = "--config flag"
+	}
+	profile := config.Origin{Field: "profile", Source: "unset: credentials come from SCW_* environment variables"}
+	for _, origin := range manifest {
+		if origin.Field == "profile" {
+			profile = origin
+		}
+	}
+	if profileOverride != "" {
+		source := "--profile flag"
+		if profile.Value != "" {
+			source += " (overrides " + profile.Source + ")"
+		}
+		profile = config.Origin{Field: "profile", Value: profileOverride, Source: source}
+	}
+
+	// Manifest order puts region right before the optional profile.
+	origins := []config.Origin{{Field: "config", Value: path, Source: located}}
+	for _, origin := range manifest {
+		switch origin.Field {
+		case "profile":
+		case "region":
+			origins = append(origins, origin, profile)
+		default:
+			origins = append(origins, origin)
+		}
+	}
+	return origins, nil
+}
@@ -0,0 +1,89 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/bsmartlabs/dev-vault/internal/config"
+)
+
+func TestRun_ExplainConfig(t *testing.T) {
+	dir := t.TempDir()
+	cfgPath := writeConfig(t, dir, `{
+  "organization_id": "org",
+  "project_id": "proj",
+  "region": "fr-par",
+  "profile": "team",
+  "mapping": {"a-dev": {"file": "a.env", "format": "dotenv"}}
+}`)
+	deps := baseDeps(func(config.Config, string) (SecretAPI, error) {
+		t.Fatal("--explain-config must not open the provider")
+		return nil, nil
+	})
+	deps.Getwd = func() (string, error) { return dir, nil }
+	run := func(args ...string) (int, string, string) {
+		var out, errBuf bytes.Buffer
+		code := Run(append([]string{"dev-vault"}, args...), &out, &errBuf, deps)
+		return code, out.String(), errBuf.String()
+	}
+
+	code, out, errOut := run("--explain-config", "--plain", "pull", "--all")
+	if code != 0 {
+		t.Fatalf("expected 0, got %d %q", code, errOut)
+	}
+	want := strings.Join([]string{
+		"FIELD\tVALUE\tSOURCE",
+		"config\t" + cfgPath + "\tfound searching upward from " + dir,
+		"organization_id\torg\t" + cfgPath + ":2",
+		"project_id\tproj\t" + cfgPath + ":3",
+		"region\tfr-par\t" + cfgPath + ":4",
+		"profile\tteam\t" + cfgPath + ":5",
+		"mapping.a-dev.file\ta.env\t" + cfgPath + ":6",
+		"mapping.a-dev.format\tdotenv\t" + cfgPath + ":6",
+		"mapping.a-dev.path\t/\tdefault",
+		"mapping.a-dev.mode\tboth\tdefault",
+	}, "\n") + "\n"
+	if out != want {
+		t.Fatalf("unexpected output:\n%s\nwant:\n%s", out, want)
+	}
+	if code, out, _ := run("--explain-config"); code != 0 || !strings.HasPrefix(out, "FIELD ") {
+		t.Fatalf("expected aligned table, got %d\n%s", code, out)
+	}
+
+	code, out, _ = run("--plain", "list", "--explain-config", "--profile", "ci", "--config", cfgPath)
+	if code != 0 || !strings.Contains(out, "config\t"+cfgPath+"\t--config flag\n") ||
+		!strings.Contains(out, "profile\tci\t--profile flag (overrides "+cfgPath+":5)\n") {
+		t.Fatalf("unexpected override output: %d\n%s", code, out)
+	}
+
+	noProfile := writeConfig(t, t.TempDir(), `{"organization_id":"org","project_id":"proj","region":"fr-par","mapping":{"a-dev":{"file":"a"}}}`)
+	code, out, _ = run("--plain", "--explain-config", "--config", noProfile)
+	if code != 0 || !strings.Contains(out, "profile\t(none)\tunset: credentials come from SCW_* environment variables\n") {
+		t.Fatalf("unexpected unset profile output: %d\n%s", code, out)
+	}
+	code, out, _ = run("--plain", "--explain-config", "--config", noProfile, "--profile", "ci")
+	if code != 0 || !strings.Contains(out, "profile\tci\t--profile flag\n") {
+		t.Fatalf("unexpected flag-only profile output: %d\n%s", code, out)
+	}
+
+	if code, _, errOut := run("--explain-config", "--config", "missing.json"); code != 1 || !strings.Contains(errOut, "read config") {
+		t.Fatalf("expected read error, got %d %q", code, errOut)
+	}
+	emptyDir := t.TempDir()
+	deps.Getwd = func() (string, error) { return emptyDir, nil }
+	if code, _, errOut := run("--explain-config"); code != 1 || !strings.Contains(errOut, "not found") {
+		t.Fatalf("expected not found error, got %d %q", code, errOut)
+	}
+	deps.Getwd = func() (string, error) { return "", errors.New("no cwd") }
+	if code, _, errOut := run("--explain-config"); code != 1 || !strings.Contains(errOut, "getwd: no cwd") {
+		t.Fatalf("expected getwd error, got %d %q", code, errOut)
+	}
+
+	deps.Getwd = func() (string, error) { return dir, nil }
+	var errBuf bytes.Buffer
+	if code := Run([]string{"dev-vault", "--explain-config"}, &failingWriter{}, &errBuf, deps); code != 1 {
+		t.Fatalf("expected output error, got %d", code)
+	}
+}
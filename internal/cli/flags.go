@@ -3,14 +3,38 @@ package cli
 import (
 	"flag"
 	"strings"
+
+	"github.com/bsmartlabs/dev-vault/internal/i18n"
 )
 
 const (
-	globalConfigFlagUsage      = "Path to .scw.json (default: search upward from cwd)"
-	globalProfileFlagUsage     = "Scaleway config profile override"
-	explicitModePolicySentence = "Explicit pull/push names must satisfy mapping.mode for that command."
+	globalConfigFlagUsage     = "Path to .scw.json (default: search upward from cwd)"
+	globalProfileFlagUsage    = "Scaleway config profile override; a comma-separated list runs against several profiles"
+	globalEnvFlagUsage        = "Environment of the manifest's environments section to use instead of its top-level project"
+	globalLangFlagUsage       = "Message language (en|fr|it)"
+	globalPlainFlagUsage      = "Plain output: no color or control codes, tab-separated columns"
+	globalExplainFlagUsage    = "Print where each effective config value comes from instead of running the command"
+	globalEnforceVersionUsage = "Refuse to run when this binary is outside the manifest's required_version"
+	globalIgnoreUnknownUsage  = "Skip manifest fields this release does not know, with a warning, instead of refusing the manifest"
+	globalStrictWarningsUsage = "Fail with exit code 1 when any warning is reported"
+	globalLogFileUsage        = "Also append diagnostics (stderr) to this file"
+	globalNoFsyncUsage        = "Skip flushing written files to disk: faster, but a crash can lose them"
 )
 
+type globalOptions struct {
+	configPath      string
+	profileOverride string
+	environment     string
+	lang            string
+	plain           bool
+	explainConfig   bool
+	enforceVersion  bool
+	ignoreUnknown   bool
+	strictWarnings  bool
+	logFile         string
+	noFsync         bool
+}
+
 type stringSliceFlag []string
 
 func (s *stringSliceFlag) String() string { return strings.Join(*s, ",") }
@@ -26,14 +50,6 @@ func reorderFlags(argv []string, takesValue map[string]bool) []string {
 	var flags []string
 	var positional []string
 
-	normalize := func(tok string) string {
-		tok = strings.TrimLeft(tok, "-")
-		if i := strings.IndexByte(tok, '='); i >= 0 {
-			tok = tok[:i]
-		}
-		return tok
-	}
-
 	for i := 0; i < len(argv); i++ {
 		tok := argv[i]
 		if tok == "--" {
@@ -42,7 +58,7 @@ func reorderFlags(argv []string, takesValue map[string]bool) []string {
 		}
 		if strings.HasPrefix(tok, "-") && tok != "-" {
 			flags = append(flags, tok)
-			name := normalize(tok)
+			name := flagName(tok)
 			if takesValue[name] && !strings.Contains(tok, "=") && i+1 < len(argv) {
 				flags = append(flags, argv[i+1])
 				i++
@@ -55,17 +71,101 @@ func reorderFlags(argv []string, takesValue map[string]bool) []string {
 	return append(flags, positional...)
 }
 
-func bindGlobalOptionFlags(fs *flag.FlagSet, configPath *string, profileOverride *string) {
-	fs.StringVar(configPath, "config", *configPath, globalConfigFlagUsage)
-	fs.StringVar(profileOverride, "profile", *profileOverride, globalProfileFlagUsage)
+// passThroughArgs ends the flags after the first positional argument of
+// argv, so reorderFlags leaves the arguments that follow it in place.
+func passThroughArgs(argv []string, takesValue map[string]bool) []string {
+	for i := 0; i < len(argv); i++ {
+		tok := argv[i]
+		if tok == "--" {
+			return argv
+		}
+		if strings.HasPrefix(tok, "-") && tok != "-" {
+			if takesValue[flagName(tok)] && !strings.Contains(tok, "=") {
+				i++
+			}
+			continue
+		}
+		if i+1 < len(argv) && argv[i+1] == "--" {
+			return argv
+		}
+		return append(append(append([]string{}, argv[:i+1]...), "--"), argv[i+1:]...)
+	}
+	return argv
+}
+
+// flagName is the name of the flag tok sets: no leading dashes or value.
+func flagName(tok string) string {
+	tok = strings.TrimLeft(tok, "-")
+	if i := strings.IndexByte(tok, '='); i >= 0 {
+		tok = tok[:i]
+	}
+	return tok
+}
+
+func bindGlobalOptionFlags(fs *flag.FlagSet, opts *globalOptions) {
+	fs.StringVar(&opts.configPath, "config", opts.configPath, globalConfigFlagUsage)
+	fs.StringVar(&opts.profileOverride, "profile", opts.profileOverride, globalProfileFlagUsage)
+	fs.StringVar(&opts.environment, "env", opts.environment, globalEnvFlagUsage)
+	fs.StringVar(&opts.lang, "lang", opts.lang, globalLangFlagUsage)
+	fs.BoolVar(&opts.plain, "plain", opts.plain, globalPlainFlagUsage)
+	fs.BoolVar(&opts.explainConfig, "explain-config", opts.explainConfig, globalExplainFlagUsage)
+	fs.BoolVar(&opts.enforceVersion, "enforce-version", opts.enforceVersion, globalEnforceVersionUsage)
+	fs.BoolVar(&opts.ignoreUnknown, "ignore-unknown", opts.ignoreUnknown, globalIgnoreUnknownUsage)
+	fs.BoolVar(&opts.strictWarnings, "strict-warnings", opts.strictWarnings, globalStrictWarningsUsage)
+	fs.StringVar(&opts.logFile, "log-file", opts.logFile, globalLogFileUsage)
+	fs.BoolVar(&opts.noFsync, "no-fsync", opts.noFsync, globalNoFsyncUsage)
 }
 
 func withGlobalFlagSpecs(spec map[string]bool) map[string]bool {
-	out := make(map[string]bool, len(spec)+2)
+	out := make(map[string]bool, len(spec)+9)
 	out["config"] = true
 	out["profile"] = true
+	out["env"] = true
+	out["lang"] = true
+	out["plain"] = false
+	out["explain-config"] = false
+	out["enforce-version"] = false
+	out["ignore-unknown"] = false
+	out["strict-warnings"] = false
+	out["log-file"] = true
+	out["no-fsync"] = false
 	for key, value := range spec {
 		out[key] = value
 	}
 	return out
 }
+
+// flagConstraint declares how one flag of a command relates to the others.
+// It only applies when Flag is set on the command line.
+type flagConstraint struct {
+	Flag string
+	// Excludes are the flags that cannot be set together with Flag.
+	Excludes []string
+	// Requires are the flags Flag needs.
+	Requires []string
+	// Reason completes the usage error, saying why.
+	Reason i18n.MessageID
+}
+
+// checkFlagConstraints reports the first constraint, in declaration order,
+// that the flags set in fs break.
+func checkFlagConstraints(fs *flag.FlagSet, constraints []flagConstraint) error {
+	set := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
+	for _, c := range constraints {
+		if !set[c.Flag] {
+			continue
+		}
+		for _, other := range c.Excludes {
+			if set[other] {
+				return usageError(i18n.Errorf(i18n.MsgFlagExcludes, c.Flag, other, c.Reason))
+			}
+		}
+		for _, other := range c.Requires {
+			if !set[other] {
+				return usageError(i18n.Errorf(i18n.MsgFlagRequires, c.Flag, other, c.Reason))
+			}
+		}
+	}
+	return nil
+}
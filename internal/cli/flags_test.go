@@ -2,24 +2,24 @@ package cli
 
 import (
 	"flag"
+	"fmt"
 	"reflect"
 	"testing"
 )
 
 func TestFlagsModule_Smoke(t *testing.T) {
 	takes := withGlobalFlagSpecs(map[string]bool{"json": false})
-	if !takes["config"] || !takes["profile"] {
+	if !takes["config"] || !takes["profile"] || !takes["lang"] {
 		t.Fatalf("expected global keys in spec: %#v", takes)
 	}
 	fs := flag.NewFlagSet("x", flag.ContinueOnError)
-	cfg := ""
-	prof := ""
-	bindGlobalOptionFlags(fs, &cfg, &prof)
-	if err := fs.Parse([]string{"--config", "c", "--profile", "p"}); err != nil {
+	opts := globalOptions{}
+	bindGlobalOptionFlags(fs, &opts)
+	if err := fs.Parse([]string{"--config", "c", "--profile", "p", "--lang", "fr"}); err != nil {
 		t.Fatalf("parse: %v", err)
 	}
-	if cfg != "c" || prof != "p" {
-		t.Fatalf("unexpected parsed globals: config=%q profile=%q", cfg, prof)
+	if opts.configPath != "c" || opts.profileOverride != "p" || opts.lang != "fr" {
+		t.Fatalf("unexpected parsed globals: %#v", opts)
 	}
 
 	got := reorderFlags([]string{"name-dev", "--json"}, map[string]bool{"json": false})
@@ -27,4 +27,41 @@ func TestFlagsModule_Smoke(t *testing.T) {
 	if !reflect.DeepEqual(got, want) {
 		t.Fatalf("reorder mismatch: got %#v want %#v", got, want)
 	}
+
+	takes = map[string]bool{"profile": true, "json": false}
+	for _, tc := range []struct{ in, want []string }{
+		{[]string{"--profile", "p", "--json=1", "name-dev", "cmd", "-x"}, []string{"--profile", "p", "--json=1", "name-dev", "--", "cmd", "-x"}},
+		{[]string{"-", "cmd"}, []string{"-", "--", "cmd"}},
+		{[]string{"name-dev", "--", "cmd", "-x"}, []string{"name-dev", "--", "cmd", "-x"}},
+		{[]string{"--", "name-dev", "cmd"}, []string{"--", "name-dev", "cmd"}},
+		{[]string{"--json"}, []string{"--json"}},
+	} {
+		if got := passThroughArgs(tc.in, takes); !reflect.DeepEqual(got, tc.want) {
+			t.Fatalf("pass-through mismatch for %#v: got %#v want %#v", tc.in, got, tc.want)
+		}
+	}
+}
+
+func TestCheckFlagConstraints(t *testing.T) {
+	constraints := []flagConstraint{
+		{Flag: "a", Excludes: []string{"b", "c"}, Reason: "a stands alone"},
+		{Flag: "d", Requires: []string{"e"}, Reason: "d tunes e"},
+	}
+	for want, args := range map[string][]string{
+		"": {"--b", "--c", "--d", "--e"},
+		"--a cannot be combined with --c: a stands alone": {"--c", "--a"},
+		"--d requires --e: d tunes e":                     {"--d"},
+	} {
+		fs := flag.NewFlagSet("x", flag.ContinueOnError)
+		for _, name := range []string{"a", "b", "c", "d", "e"} {
+			fs.Bool(name, false, "")
+		}
+		if err := fs.Parse(args); err != nil {
+			t.Fatalf("parse: %v", err)
+		}
+		err := checkFlagConstraints(fs, constraints)
+		if got := fmt.Sprint(err); want == "" && err != nil || want != "" && (got != want || exitCodeForError(err) != 2) {
+			t.Fatalf("%v: expected %q, got %v", args, want, err)
+		}
+	}
 }
@@ -0,0 +1,137 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/bsmartlabs/dev-vault/internal/i18n"
+)
+
+// runHelp serves "dev-vault help [command | --all | search <term>...]".
+func runHelp(args []string, stdout, stderr io.Writer, msg i18n.Localizer) int {
+	switch {
+	case len(args) == 0:
+		if err := printMainUsage(stdout, msg); err != nil {
+			return 1
+		}
+		return 0
+	case args[0] == "--all":
+		if err := printFullHelp(stdout, msg); err != nil {
+			return 1
+		}
+		return 0
+	case args[0] == "search" && len(args) > 1:
+		return runHelpSearch(args[1:], stdout, stderr, msg)
+	}
+	usagePrinter, ok := usageForCommand(args[0], msg)
+	if !ok {
+		if _, err := fmt.Fprintln(stderr, msg.Sprintf(i18n.MsgUnknownHelpCommand, args[0])); err != nil {
+			return 1
+		}
+		if err := printMainUsage(stderr, msg); err != nil {
+			return 1
+		}
+		return 2
+	}
+	if err := usagePrinter(stdout); err != nil {
+		return 1
+	}
+	return 0
+}
+
+// printFullHelp prints the main usage followed by every command's help, as a
+// single reference to read or grep.
+func printFullHelp(w io.Writer, msg i18n.Localizer) error {
+	if err := printMainUsage(w, msg); err != nil {
+		return err
+	}
+	for _, def := range listedCommandDefs() {
+		out := usageWriter{w: w}
+		out.line()
+		out.f("== dev-vault %s: %s\n", def.Name, commandSummary(msg, def))
+		if out.err != nil {
+			return out.err
+		}
+		if err := printCommandUsage(w, msg, def); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// helpTopic is a block of help text that help search matches against: the
+// global options, or one command's synopsis, description, options, notes,
+// and examples.
+type helpTopic struct {
+	title string
+	lines []string
+}
+
+func helpTopics(msg i18n.Localizer) []helpTopic {
+	topics := []helpTopic{{title: msg.Text(i18n.MsgHeadingGlobalOptions), lines: globalOptionLines(msg)}}
+	for _, def := range listedCommandDefs() {
+		lines := append([]string{def.Doc.Synopsis}, commandDescription(msg, def)...)
+		for _, flagDef := range commandFlagDefs(msg, def) {
+			lines = append(lines, "--"+formatFlagUsage(flagDef))
+		}
+		lines = append(lines, commandNotes(msg, def)...)
+		lines = append(lines, def.Doc.Examples...)
+		topics = append(topics, helpTopic{title: def.Name + ": " + commandSummary(msg, def), lines: lines})
+	}
+	return topics
+}
+
+// runHelpSearch lists the topics whose help contains every term, ignoring
+// case, with the lines that mention one of them.
+func runHelpSearch(terms []string, stdout, stderr io.Writer, msg i18n.Localizer) int {
+	for i, term := range terms {
+		terms[i] = strings.ToLower(term)
+	}
+	out := usageWriter{w: stdout}
+	matched := 0
+	for _, topic := range helpTopics(msg) {
+		text := strings.ToLower(topic.title + "\n" + strings.Join(topic.lines, "\n"))
+		if !containsAll(text, terms) {
+			continue
+		}
+		if matched > 0 {
+			out.line()
+		}
+		matched++
+		out.line(topic.title)
+		for _, line := range topic.lines {
+			if containsAny(strings.ToLower(line), terms) {
+				out.line("  " + strings.TrimSpace(line))
+			}
+		}
+	}
+	if out.err != nil {
+		return 1
+	}
+	if matched == 0 {
+		if _, err := fmt.Fprintln(stderr, msg.Sprintf(i18n.MsgHelpSearchNoMatch, strings.Join(terms, " "))); err != nil {
+			return 1
+		}
+		return 1
+	}
+	return 0
+}
+
+func containsAll(text string, terms []string) bool {
+	for _, term := range terms {
+		if !strings.Contains(text, term) {
+			return false
+		}
+	}
+	return true
+}
+
+func containsAny(text string, terms []string) bool {
+	for _, term := range terms {
+		if strings.Contains(text, term) {
+			return true
+		}
+	}
+	return false
+}
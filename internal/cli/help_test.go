@@ -0,0 +1,76 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/bsmartlabs/dev-vault/internal/config"
+	"github.com/bsmartlabs/dev-vault/internal/i18n"
+)
+
+func helpDeps() Dependencies {
+	return baseDeps(func(config.Config, string) (SecretAPI, error) { return nil, nil })
+}
+
+func TestRunHelp_All(t *testing.T) {
+	var out bytes.Buffer
+	if code := Run([]string{"dev-vault", "help", "--all"}, &out, &bytes.Buffer{}, helpDeps()); code != 0 {
+		t.Fatalf("expected 0, got %d", code)
+	}
+	if !strings.HasPrefix(out.String(), "dev-vault\n") {
+		t.Fatalf("expected the main usage first, got %q", out.String())
+	}
+	for _, def := range listedCommandDefs() {
+		if !strings.Contains(out.String(), "== dev-vault "+def.Name+": "+def.Summary+"\nUsage:\n  "+def.Doc.Synopsis+"\n") {
+			t.Fatalf("missing %s help in %q", def.Name, out.String())
+		}
+	}
+
+	main := &failAfterWriter{okWrites: 1 << 30}
+	if err := printMainUsage(main, i18n.Localizer{}); err != nil {
+		t.Fatalf("printMainUsage: %v", err)
+	}
+	for _, okWrites := range []int{0, main.writes + 1, main.writes + 2} {
+		if code := Run([]string{"dev-vault", "help", "--all"}, &failAfterWriter{okWrites: okWrites}, &bytes.Buffer{}, helpDeps()); code != 1 {
+			t.Fatalf("expected write failure after %d writes to return 1, got %d", okWrites, code)
+		}
+	}
+}
+
+func TestRunHelp_Search(t *testing.T) {
+	run := func(stdout, stderr *bytes.Buffer, args ...string) int {
+		return Run(append([]string{"dev-vault", "help", "search"}, args...), stdout, stderr, helpDeps())
+	}
+
+	var out, errBuf bytes.Buffer
+	if code := run(&out, &errBuf, "OVERWRITE"); code != 0 {
+		t.Fatalf("expected 0, got %d (%s)", code, errBuf.String())
+	}
+	if !strings.Contains(out.String(), "pull: Pull mapped -dev secrets to local files\n  --overwrite  Overwrite existing files\n") || strings.Contains(out.String(), "push:") {
+		t.Fatalf("unexpected matches: %q", out.String())
+	}
+
+	// Every term must appear in a topic; lines mentioning any of them are shown.
+	out.Reset()
+	if code := run(&out, &errBuf, "strict", "warnings"); code != 0 || !strings.HasPrefix(out.String(), "Global options:\n  --strict-warnings") {
+		t.Fatalf("unexpected global option match: %d %q", code, out.String())
+	}
+
+	errBuf.Reset()
+	if code := run(&out, &errBuf, "no-such-topic"); code != 1 || errBuf.String() != "no help matches \"no-such-topic\"\n" {
+		t.Fatalf("unexpected no match: %d %q", code, errBuf.String())
+	}
+	errBuf.Reset()
+	// Without a term, help search is the help of the search command.
+	out.Reset()
+	if code := run(&out, &errBuf); code != 0 || !strings.Contains(out.String(), "search <query> [--regex] [--json]") {
+		t.Fatalf("unexpected search command help: %d %q", code, out.String())
+	}
+
+	for _, args := range [][]string{{"search", "overwrite"}, {"search", "no-such-topic"}, {"search"}} {
+		if code := Run(append([]string{"dev-vault", "help"}, args...), &failingWriter{}, &failingWriter{}, helpDeps()); code != 1 {
+			t.Fatalf("%v: expected write failure to return 1, got %d", args, code)
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package cli
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bsmartlabs/dev-vault/internal/config"
+	"github.com/bsmartlabs/dev-vault/internal/i18n"
+	secret "github.com/scaleway/scaleway-sdk-go/api/secret/v1beta1"
+)
+
+func TestCommandSummariesAreTranslated(t *testing.T) {
+	for _, lang := range i18n.Supported() {
+		if lang == i18n.English {
+			continue
+		}
+		msg := i18n.New(lang)
+		for _, def := range commandDefs {
+			if got := msg.TextOr(i18n.CommandSummaryID(def.Name), ""); got == "" {
+				t.Fatalf("missing %s summary for command %q", lang, def.Name)
+			}
+		}
+	}
+}
+
+func TestCommandHelpIsTranslated(t *testing.T) {
+	for _, lang := range i18n.Supported() {
+		if lang == i18n.English {
+			continue
+		}
+		msg := i18n.New(lang)
+		for _, def := range commandDefs {
+			if len(def.Doc.Description) > 0 && msg.TextOr(i18n.CommandDescriptionID(def.Name), "") == "" {
+				t.Fatalf("missing %s description for command %q", lang, def.Name)
+			}
+			if len(def.Doc.Notes) > 0 && msg.TextOr(i18n.CommandNotesID(def.Name), "") == "" {
+				t.Fatalf("missing %s notes for command %q", lang, def.Name)
+			}
+			for _, flagDef := range def.Flags {
+				if msg.TextOr(i18n.CommandFlagHelpID(def.Name, flagDef.Name), "") == "" {
+					t.Fatalf("missing %s help for command %q option --%s", lang, def.Name, flagDef.Name)
+				}
+			}
+		}
+	}
+}
+
+func TestRun_Localization(t *testing.T) {
+	root := t.TempDir()
+	cfgPath := writeConfig(t, root, `{"organization_id":"org","project_id":"proj","region":"fr-par","mapping":{"a-dev":{"file":"a.env","mode":"sync"}}}`)
+	deps := baseDeps(func(cfg config.Config, s string) (SecretAPI, error) {
+		return newFakeSecretAPI(), nil
+	})
+
+	t.Run("GlobalFlag", func(t *testing.T) {
+		var out, errBuf bytes.Buffer
+		code := Run([]string{"dev-vault", "--lang", "fr", "help"}, &out, &errBuf, deps)
+		if code != 0 {
+			t.Fatalf("expected 0, got %d", code)
+		}
+		if !strings.Contains(out.String(), "Commandes :") || !strings.Contains(out.String(), "Récupère les secrets -dev") {
+			t.Fatalf("expected french usage, got %q", out.String())
+		}
+	})
+
+	t.Run("EnvLocale", func(t *testing.T) {
+		envDeps := deps
+		envDeps.Getenv = func(key string) string {
+			if key == "LANG" {
+				return "it_IT.UTF-8"
+			}
+			return ""
+		}
+		var out, errBuf bytes.Buffer
+		code := Run([]string{"dev-vault", "nope"}, &out, &errBuf, envDeps)
+		if code != 2 {
+			t.Fatalf("expected 2, got %d", code)
+		}
+		if !strings.Contains(errBuf.String(), "comando sconosciuto: nope") {
+			t.Fatalf("expected italian error, got %q", errBuf.String())
+		}
+	})
+
+	t.Run("CommandFlagLocalizesWarnings", func(t *testing.T) {
+		var out, errBuf bytes.Buffer
+		code := Run([]string{"dev-vault", "--config", cfgPath, "prompt", "--lang", "fr"}, &out, &errBuf, deps)
+		if code != 0 {
+			t.Fatalf("expected 0, got %d stderr=%s", code, errBuf.String())
+		}
+		if !strings.HasPrefix(errBuf.String(), "avertissement : ") {
+			t.Fatalf("expected french warning, got %q", errBuf.String())
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		var out, errBuf bytes.Buffer
+		code := Run([]string{"dev-vault", "--lang", "fr", "--config", cfgPath, "pull"}, &out, &errBuf, deps)
+		if code != 2 || !strings.HasSuffix(errBuf.String(), "\naucun secret indiqué (utilisez --all ou passez des noms de secrets)\n") {
+			t.Fatalf("expected french error, got code=%d stderr=%q", code, errBuf.String())
+		}
+		errBuf.Reset()
+		code = Run([]string{"dev-vault", "--lang", "it", "pull", "--watch", "--revision", "2", "a-dev"}, &out, &errBuf, deps)
+		if code != 2 || errBuf.String() != "--watch non può essere combinato con --revision: --watch segue l'ultima revisione abilitata\n" {
+			t.Fatalf("expected italian flag constraint error, got code=%d stderr=%q", code, errBuf.String())
+		}
+	})
+
+	t.Run("Warnings", func(t *testing.T) {
+		versioned := writeConfig(t, t.TempDir(), `{"organization_id":"org","project_id":"proj","region":"fr-par","required_version":"<2.0.0","mapping":{"a-dev":{"file":"a.env"}}}`)
+		versionDeps := deps
+		versionDeps.Version = "v2.1.0"
+		var out, errBuf bytes.Buffer
+		code := Run([]string{"dev-vault", "--lang", "fr", "--config", versioned, "list"}, &out, &errBuf, versionDeps)
+		if code != 0 || !strings.HasPrefix(errBuf.String(), `avertissement : dev-vault v2.1.0 est hors de required_version "<2.0.0"`) {
+			t.Fatalf("expected french warning, got code=%d stderr=%q", code, errBuf.String())
+		}
+		errBuf.Reset()
+		code = Run([]string{"dev-vault", "--lang", "fr", "--config", versioned, "list", "--json"}, &out, &errBuf, versionDeps)
+		if code != 0 || !strings.HasPrefix(errBuf.String(), `{"warning":"dev-vault v2.1.0 is outside required_version`) {
+			t.Fatalf("expected english json warning, got code=%d stderr=%q", code, errBuf.String())
+		}
+	})
+
+	t.Run("CommandOutput", func(t *testing.T) {
+		api := newFakeSecretAPI()
+		sec := api.AddSecret("proj", "creds-dev", "/", secret.SecretTypeBasicCredentials)
+		api.AddEnabledVersion(sec.ID, []byte(`{"username":"ci","password":"s3cret"}`))
+		credsCfg := writeConfig(t, t.TempDir(), `{"organization_id":"org","project_id":"proj","region":"fr-par","mapping":{"creds-dev":{"file":"c.json"}}}`)
+		credsDeps := baseDeps(func(config.Config, string) (SecretAPI, error) { return api, nil })
+		output := filepath.Join(t.TempDir(), "auth")
+		var out, errBuf bytes.Buffer
+		code := Run([]string{"dev-vault", "--lang", "fr", "--config", credsCfg, "get", "creds-dev", "--as-header", "--output", output}, &out, &errBuf, credsDeps)
+		if code != 0 || out.String() != "en-tête Authorization écrit depuis creds-dev (rev=1) -> "+output+"\n" {
+			t.Fatalf("expected french get output, got code=%d out=%q stderr=%q", code, out.String(), errBuf.String())
+		}
+		out.Reset()
+		code = Run([]string{"dev-vault", "--lang", "it", "--config", credsCfg, "get", "creds-dev", "--as-netrc", "a.test", "--output", filepath.Join(output, "x")}, &out, &errBuf, credsDeps)
+		if code != 1 || !strings.Contains(errBuf.String(), "get creds-dev: lettura di "+filepath.Join(output, "x")+": ") {
+			t.Fatalf("expected italian get error, got code=%d stderr=%q", code, errBuf.String())
+		}
+	})
+
+	t.Run("CommandHelp", func(t *testing.T) {
+		var out, errBuf bytes.Buffer
+		code := Run([]string{"dev-vault", "--lang", "it", "help", "pull"}, &out, &errBuf, deps)
+		if code != 0 || !strings.HasPrefix(out.String(), "Uso:") || !strings.Contains(out.String(), "Opzioni:") {
+			t.Fatalf("expected italian command help, got code=%d out=%q", code, out.String())
+		}
+		for _, want := range []string{
+			"Scarica uno o più segreti su disco",
+			"--overwrite  Sovrascrive i file esistenti",
+			"  - --dry-run fa tutto tranne scrivere",
+		} {
+			if !strings.Contains(out.String(), want) {
+				t.Fatalf("expected %q in italian command help, got %q", want, out.String())
+			}
+		}
+	})
+
+	t.Run("HelpSearch", func(t *testing.T) {
+		var out, errBuf bytes.Buffer
+		code := Run([]string{"dev-vault", "--lang", "fr", "help", "search", "écrase"}, &out, &errBuf, deps)
+		if code != 0 || !strings.Contains(out.String(), "--overwrite  Écrase les fichiers existants") {
+			t.Fatalf("expected french help search, got code=%d out=%q stderr=%q", code, out.String(), errBuf.String())
+		}
+	})
+
+	t.Run("InvalidGlobalLang", func(t *testing.T) {
+		var out, errBuf bytes.Buffer
+		code := Run([]string{"dev-vault", "--lang", "de", "help"}, &out, &errBuf, deps)
+		if code != 2 || !strings.Contains(errBuf.String(), "unsupported language") {
+			t.Fatalf("expected usage error, got code=%d stderr=%q", code, errBuf.String())
+		}
+		if code := Run([]string{"dev-vault", "--lang", "de", "help"}, &out, &failingWriter{}, deps); code != 1 {
+			t.Fatalf("expected write failure to return 1, got %d", code)
+		}
+	})
+
+	t.Run("InvalidCommandLang", func(t *testing.T) {
+		var out, errBuf bytes.Buffer
+		code := Run([]string{"dev-vault", "--config", cfgPath, "prompt", "--lang", "de"}, &out, &errBuf, deps)
+		if code != 2 || !strings.Contains(errBuf.String(), "unsupported language") {
+			t.Fatalf("expected usage error, got code=%d stderr=%q", code, errBuf.String())
+		}
+	})
+}
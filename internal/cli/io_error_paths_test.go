@@ -75,12 +75,6 @@ func TestRun_WriteFailureBranches(t *testing.T) {
 	}
 }
 
-func TestPrintConfigWarnings_WriteFailureStops(t *testing.T) {
-	if err := printConfigWarnings(&failingWriter{}, []string{"one", "two"}); err == nil {
-		t.Fatal("expected warning write error")
-	}
-}
-
 func TestRunVersionParsed_WriteFailure(t *testing.T) {
 	code := runVersionParsed(commandContext{
 		stdout: &failingWriter{},
@@ -146,7 +140,7 @@ func TestRunList_ConfigWarningWriteFailure(t *testing.T) {
 	api := newFakeSecretAPI()
 	api.AddSecret("proj", "x-dev", "/", secret.SecretTypeOpaque)
 	deps := baseDeps(func(cfg config.Config, s string) (SecretAPI, error) { return api, nil })
-	loaded, _, err := loadAndOpenAPI(cfgPath, "", deps)
+	loaded, _, err := loadAndOpenAPI(cfgPath, "", config.LoadOptions{}, deps)
 	if err != nil {
 		t.Fatalf("loadAndOpenAPI: %v", err)
 	}
@@ -167,8 +161,8 @@ func TestRunList_ConfigWarningWriteFailure(t *testing.T) {
 
 func TestRunList_HelpUsageWriteFailure(t *testing.T) {
 	code := runList(commandContext{
-		stdout: &bytes.Buffer{},
-		stderr: &failingWriter{},
+		stdout: &failingWriter{},
+		stderr: &bytes.Buffer{},
 		deps: baseDeps(func(cfg config.Config, s string) (SecretAPI, error) {
 			return newFakeSecretAPI(), nil
 		}),
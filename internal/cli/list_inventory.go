@@ -0,0 +1,157 @@
+package cli
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bsmartlabs/dev-vault/internal/i18n"
+	"github.com/bsmartlabs/dev-vault/internal/secretsync"
+)
+
+const (
+	listOutputTable = "table"
+	listOutputJSON  = "json"
+	listOutputCSV   = "csv"
+)
+
+// inventoryRow is one listed secret with the context its columns need.
+type inventoryRow struct {
+	Project string
+	Record  secretsync.ListRecord
+}
+
+// inventoryColumns are the metadata --columns can select. None of them reads
+// a secret version, so an inventory never contains payloads.
+var inventoryColumns = map[string]func(row inventoryRow, now time.Time) string{
+	"name":    func(row inventoryRow, _ time.Time) string { return row.Record.Name },
+	"project": func(row inventoryRow, _ time.Time) string { return row.Project },
+	"path":    func(row inventoryRow, _ time.Time) string { return row.Record.Path },
+	"type":    func(row inventoryRow, _ time.Time) string { return row.Record.Type },
+	"id":      func(row inventoryRow, _ time.Time) string { return row.Record.ID },
+	"profile": func(row inventoryRow, _ time.Time) string { return row.Record.Profile },
+	"owner":   func(row inventoryRow, _ time.Time) string { return ownerTag(row.Record.Tags) },
+	"tags":    func(row inventoryRow, _ time.Time) string { return strings.Join(row.Record.Tags, " ") },
+	"updated": func(row inventoryRow, _ time.Time) string {
+		if row.Record.UpdatedAt.IsZero() {
+			return ""
+		}
+		return row.Record.UpdatedAt.UTC().Format(time.RFC3339)
+	},
+	"age": func(row inventoryRow, now time.Time) string {
+		if row.Record.UpdatedAt.IsZero() {
+			return ""
+		}
+		return fmt.Sprintf("%dd", int(now.Sub(row.Record.UpdatedAt).Hours()/24))
+	},
+}
+
+func inventoryColumnNames() []string {
+	names := make([]string, 0, len(inventoryColumns))
+	for name := range inventoryColumns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ownerTag reads the owner from an "owner:<team>" or "owner=<team>" tag, the
+// form policy required_tags accepts for "owner".
+func ownerTag(tags []string) string {
+	for _, tag := range tags {
+		for _, sep := range []string{":", "="} {
+			if value, ok := strings.CutPrefix(tag, "owner"+sep); ok {
+				return value
+			}
+		}
+	}
+	return ""
+}
+
+// listOutput resolves --output and its --json shorthand.
+func listOutput(parsed *parsedCommand) (string, error) {
+	output := parsed.String("output")
+	switch output {
+	case "":
+		if parsed.Bool("json") {
+			return listOutputJSON, nil
+		}
+		return listOutputTable, nil
+	case listOutputTable, listOutputJSON, listOutputCSV:
+		if parsed.Bool("json") && output != listOutputJSON {
+			return "", usageError(i18n.Errorf(i18n.MsgJSONConflictsWithOutput, output))
+		}
+		return output, nil
+	default:
+		return "", usageError(i18n.Errorf(i18n.MsgInvalidListOutput, output))
+	}
+}
+
+func parseInventoryColumns(value string) ([]string, error) {
+	if value == "" {
+		return nil, nil
+	}
+	columns := strings.Split(value, ",")
+	for i, column := range columns {
+		column = strings.ToLower(strings.TrimSpace(column))
+		if _, ok := inventoryColumns[column]; !ok {
+			return nil, usageError(i18n.Errorf(i18n.MsgUnknownColumn, column, strings.Join(inventoryColumnNames(), ",")))
+		}
+		columns[i] = column
+	}
+	return columns, nil
+}
+
+// writeInventory renders rows restricted to columns: a table, a CSV with a
+// header line, or a JSON array of objects keyed by column name.
+func writeInventory(ctx commandContext, parsed *parsedCommand, output string, columns []string, rows []inventoryRow) error {
+	now := ctx.deps.Now()
+	cells := make([][]string, 0, len(rows))
+	for _, row := range rows {
+		line := make([]string, len(columns))
+		for i, column := range columns {
+			line[i] = inventoryColumns[column](row, now)
+		}
+		cells = append(cells, line)
+	}
+
+	switch output {
+	case listOutputJSON:
+		objects := make([]map[string]string, 0, len(cells))
+		for _, line := range cells {
+			object := make(map[string]string, len(columns))
+			for i, column := range columns {
+				object[column] = line[i]
+			}
+			objects = append(objects, object)
+		}
+		enc := json.NewEncoder(ctx.stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(objects); err != nil {
+			return outputError(err)
+		}
+	case listOutputCSV:
+		w := csv.NewWriter(ctx.stdout)
+		_ = w.Write(columns)
+		_ = w.WriteAll(cells)
+		if err := w.Error(); err != nil {
+			return outputError(err)
+		}
+	default:
+		header := make([]string, len(columns))
+		for i, column := range columns {
+			header[i] = strings.ToUpper(column)
+		}
+		tbl := newTable(ctx.stdout, parsed.plain, header...)
+		for _, line := range cells {
+			tbl.row(line...)
+		}
+		if err := tbl.flush(); err != nil {
+			return outputError(err)
+		}
+	}
+	return nil
+}
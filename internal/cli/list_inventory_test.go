@@ -0,0 +1,112 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bsmartlabs/dev-vault/internal/config"
+	"github.com/bsmartlabs/dev-vault/internal/secretprovider"
+	secret "github.com/scaleway/scaleway-sdk-go/api/secret/v1beta1"
+)
+
+func TestRunList_Inventory(t *testing.T) {
+	root := t.TempDir()
+	cfgPath := writeConfig(t, root, `{"organization_id":"org","project_id":"p1","region":"fr-par","mapping":{"x-dev":{"file":"x"}}}`)
+	api := newFakeSecretAPI()
+	apiSecret := api.AddSecret("p1", "api-env-dev", "/", secret.SecretTypeKeyValue)
+	apiSecret.Tags = []string{"team", "owner=backend, core"}
+	apiSecret.UpdatedAt = time.Unix(123, 0).Add(-50 * time.Hour)
+	web := api.AddSecret("p2", "web-env-dev", "/web", secret.SecretTypeKeyValue)
+	web.Tags = []string{"web"}
+	account := &fakeAccountAPI{projects: []secretprovider.ProjectRecord{
+		{ID: "p1", Name: "api", OrganizationID: "org"},
+		{ID: "p2", Name: "web", OrganizationID: "org"},
+	}}
+	deps := accountDeps(account, nil)
+	deps.OpenSecretAPI = func(cfg config.Config, s string) (SecretAPI, error) { return api, nil }
+	run := func(t *testing.T, args ...string) (int, string, string) {
+		t.Helper()
+		var out, errBuf bytes.Buffer
+		code := Run(append([]string{"dev-vault", "--config", cfgPath, "list"}, args...), &out, &errBuf, deps)
+		return code, out.String(), errBuf.String()
+	}
+
+	t.Run("CSV", func(t *testing.T) {
+		code, out, errOut := run(t, "--all-projects", "--output", "csv", "--columns", "name,project,path,type,age,owner")
+		want := "name,project,path,type,age,owner\n" +
+			"api-env-dev,api,/,key_value,2d,\"backend, core\"\n" +
+			"web-env-dev,web,/web,key_value,,\n"
+		if code != 0 || out != want {
+			t.Fatalf("unexpected csv: %d %q %q", code, out, errOut)
+		}
+	})
+
+	t.Run("DefaultColumns", func(t *testing.T) {
+		code, out, errOut := run(t, "--output", "csv")
+		if code != 0 || out != "name,type,path,id\napi-env-dev,key_value,/,sec-1\nweb-env-dev,key_value,/web,sec-2\n" {
+			t.Fatalf("unexpected csv: %d %q %q", code, out, errOut)
+		}
+		code, out, errOut = run(t, "--all-projects", "--output", "csv")
+		if code != 0 || !strings.HasPrefix(out, "project,name,type,path,id\napi,") {
+			t.Fatalf("unexpected csv: %d %q %q", code, out, errOut)
+		}
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		code, out, errOut := run(t, "--json", "--columns", "Name, project ,updated,tags")
+		var records []map[string]string
+		if err := json.Unmarshal([]byte(out), &records); code != 0 || err != nil {
+			t.Fatalf("unexpected json: %d %v %q %q", code, err, out, errOut)
+		}
+		if len(records) != 2 || len(records[0]) != 4 || records[0]["project"] != "p1" || records[0]["updated"] != "1969-12-29T22:02:03Z" || records[0]["tags"] != "team owner=backend, core" || records[1]["updated"] != "" {
+			t.Fatalf("unexpected records: %#v", records)
+		}
+	})
+
+	t.Run("Table", func(t *testing.T) {
+		code, out, errOut := run(t, "--output", "table", "--columns", "name,owner,id")
+		if code != 0 || out != "NAME         OWNER          ID\napi-env-dev  backend, core  sec-1\nweb-env-dev                 sec-2\n" {
+			t.Fatalf("unexpected table: %d %q %q", code, out, errOut)
+		}
+		code, out, _ = run(t, "--profile", "a,b", "--output", "csv")
+		if code != 0 || !strings.HasPrefix(out, "profile,name,type,path,id\n") {
+			t.Fatalf("unexpected multi-profile csv: %d %q", code, out)
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		for _, tc := range []struct {
+			args []string
+			want string
+		}{
+			{[]string{"--output", "xml"}, `invalid --output "xml" (expected table, json or csv)`},
+			{[]string{"--json", "--output", "csv"}, "--json conflicts with --output csv"},
+			{[]string{"--columns", "name,secret"}, `unknown column "secret" (expected: age,id,name,owner,path,profile,project,tags,type,updated)`},
+			{[]string{"--stale", "--output", "csv"}, "--stale supports --output table or json, without --columns"},
+			{[]string{"--stale", "--columns", "name"}, "--stale supports --output table or json, without --columns"},
+			{[]string{"--stale", "--output", "yaml"}, `invalid --output "yaml"`},
+		} {
+			if code, _, errOut := run(t, tc.args...); code != 2 || !strings.Contains(errOut, tc.want) {
+				t.Fatalf("%v: expected %q, got %d %q", tc.args, tc.want, code, errOut)
+			}
+		}
+		if code, out, _ := run(t, "--stale", "--output", "json"); code != 0 || !strings.HasPrefix(out, "[") {
+			t.Fatalf("expected stale json, got %d %q", code, out)
+		}
+	})
+
+	t.Run("OutputErrors", func(t *testing.T) {
+		for _, args := range [][]string{
+			{"--config", cfgPath, "list", "--output", "csv"},
+			{"--config", cfgPath, "list", "--output", "json", "--columns", "name"},
+		} {
+			var errBuf bytes.Buffer
+			if code := Run(append([]string{"dev-vault"}, args...), &failingWriter{}, &errBuf, deps); code != 1 {
+				t.Fatalf("%v: expected output error, got %d %q", args, code, errBuf.String())
+			}
+		}
+	})
+}
@@ -0,0 +1,36 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/bsmartlabs/dev-vault/internal/i18n"
+)
+
+// logTee copies diagnostics into the --log-file on top of stderr. Results
+// stay on stdout only.
+type logTee struct {
+	stderr io.Writer
+	file   io.Writer
+}
+
+func (t logTee) Write(p []byte) (int, error) {
+	if n, err := t.stderr.Write(p); err != nil {
+		return n, err
+	}
+	return t.file.Write(p)
+}
+
+// openLogFile appends to path, starting with a header line so successive runs
+// can be told apart and matched with provider logs by their invocation ID,
+// and returns stderr teed into it.
+func openLogFile(stderr io.Writer, path string, now time.Time, command, invocation string) (io.Writer, func(), error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, nil, runtimeError(i18n.Errorf(i18n.MsgCommandError, "--log-file", err))
+	}
+	_, _ = fmt.Fprintf(file, "--- %s %s invocation=%s\n", now.UTC().Format(time.RFC3339), command, invocation)
+	return logTee{stderr: stderr, file: file}, func() { _ = file.Close() }, nil
+}
@@ -0,0 +1,124 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bsmartlabs/dev-vault/internal/config"
+	"github.com/bsmartlabs/dev-vault/internal/fsx"
+	secret "github.com/scaleway/scaleway-sdk-go/api/secret/v1beta1"
+)
+
+func TestRun_LogFile(t *testing.T) {
+	root := t.TempDir()
+	cfgPath := writeConfig(t, root, `{"organization_id":"org","project_id":"proj","region":"fr-par","mapping":{
+		"app-env-dev":{"file":".env","format":"dotenv","type":"key_value"},
+		"old-dev":{"file":"old.bin","mode":"sync"}
+	}}`)
+	api := newFakeSecretAPI()
+	sec := api.AddSecret("proj", "app-env-dev", "/", secret.SecretTypeOpaque)
+	api.AddEnabledVersion(sec.ID, []byte(`{"A":"1"}`))
+	deps := baseDeps(func(config.Config, string) (SecretAPI, error) { return api, nil })
+	logPath := filepath.Join(root, "dev-vault.log")
+	readLog := func() string {
+		t.Helper()
+		raw, err := os.ReadFile(logPath)
+		if err != nil {
+			t.Fatalf("read log: %v", err)
+		}
+		return string(raw)
+	}
+
+	// Before the command: diagnostics are teed, results stay on stdout.
+	var out, errBuf bytes.Buffer
+	code := Run([]string{"dev-vault", "--config", cfgPath, "--log-file", logPath, "pull", "--coerce", "app-env-dev"}, &out, &errBuf, deps)
+	logged := readLog()
+	if code != 0 || !strings.Contains(out.String(), "pulled app-env-dev") || !strings.HasPrefix(logged, "--- 1970-01-01T00:02:03Z dev-vault pull invocation=TESTINVOCATION\n") ||
+		!strings.HasSuffix(logged, errBuf.String()) || !strings.Contains(logged, "warning: coerced app-env-dev") || strings.Contains(logged, "pulled") {
+		t.Fatalf("unexpected pull: %d %q %q %q", code, out.String(), errBuf.String(), logged)
+	}
+	if info, err := os.Stat(logPath); err != nil || info.Mode().Perm() != 0o600 {
+		t.Fatalf("expected a private log file: %v %v", info, err)
+	}
+
+	// After the command, the log is appended to, warnings included.
+	out.Reset()
+	errBuf.Reset()
+	code = Run([]string{"dev-vault", "--config", cfgPath, "list", "--log-file", logPath}, &out, &errBuf, deps)
+	if logged := readLog(); code != 0 || !strings.Contains(logged, "--- 1970-01-01T00:02:03Z dev-vault list invocation=TESTINVOCATION\n"+errBuf.String()) || !strings.Contains(errBuf.String(), "legacy mode=sync") {
+		t.Fatalf("unexpected list: %d %q %q", code, errBuf.String(), logged)
+	}
+
+	// Without a command the header names the binary alone.
+	out.Reset()
+	errBuf.Reset()
+	if code := Run([]string{"dev-vault", "--log-file", logPath}, &out, &errBuf, deps); code != 2 || !strings.Contains(readLog(), "--- 1970-01-01T00:02:03Z dev-vault invocation=TESTINVOCATION\ndev-vault\n") {
+		t.Fatalf("unexpected bare run: %d %q", code, readLog())
+	}
+
+	missing := filepath.Join(root, "missing", "dev-vault.log")
+	for _, args := range [][]string{
+		{"dev-vault", "--log-file", missing, "list"},
+		{"dev-vault", "--config", cfgPath, "list", "--log-file", missing},
+	} {
+		errBuf.Reset()
+		if code := Run(args, &out, &errBuf, deps); code != 1 || !strings.HasPrefix(errBuf.String(), "--log-file: open ") {
+			t.Fatalf("unexpected open failure for %v: %d %q", args, code, errBuf.String())
+		}
+	}
+	if code := Run([]string{"dev-vault", "--log-file", missing, "list"}, &out, &failingWriter{}, deps); code != 1 {
+		t.Fatalf("expected 1 when the open error cannot be written, got %d", code)
+	}
+}
+
+func TestLogTee_WriteFailures(t *testing.T) {
+	var buf bytes.Buffer
+	if _, err := (logTee{stderr: &failingWriter{}, file: &buf}).Write([]byte("x")); err == nil || buf.Len() != 0 {
+		t.Fatalf("expected stderr failure before the log write, got %v %q", err, buf.String())
+	}
+	if _, err := (logTee{stderr: &buf, file: &failingWriter{}}).Write([]byte("x")); err == nil || buf.String() != "x" {
+		t.Fatalf("expected log write failure, got %v %q", err, buf.String())
+	}
+}
+
+func TestRun_HelpGoesToStdout(t *testing.T) {
+	deps := baseDeps(func(config.Config, string) (SecretAPI, error) { return nil, nil })
+	if code := Run([]string{"dev-vault", "--plain", "-h"}, &failingWriter{}, &bytes.Buffer{}, deps); code != 1 {
+		t.Fatalf("expected help write failure to return 1, got %d", code)
+	}
+
+	// A parse error is a diagnostic: usage goes to stderr.
+	for _, args := range [][]string{{"dev-vault", "--nope"}, {"dev-vault", "list", "--nope"}} {
+		var out, errBuf bytes.Buffer
+		if code := Run(args, &out, &errBuf, deps); code != 2 || out.Len() != 0 || !strings.Contains(errBuf.String(), "Usage:") {
+			t.Fatalf("unexpected parse error output for %v: %d %q %q", args, code, out.String(), errBuf.String())
+		}
+	}
+}
+
+func TestRun_NoFsync(t *testing.T) {
+	t.Cleanup(func() { fsx.SetSync(true) })
+	root := t.TempDir()
+	cfgPath := writeConfig(t, root, `{"organization_id":"org","project_id":"proj","region":"fr-par","mapping":{"app-dev":{"file":"app.bin"}}}`)
+	api := newFakeSecretAPI()
+	sec := api.AddSecret("proj", "app-dev", "/", secret.SecretTypeOpaque)
+	api.AddEnabledVersion(sec.ID, []byte("x"))
+	deps := baseDeps(func(config.Config, string) (SecretAPI, error) { return api, nil })
+
+	for _, argv := range [][]string{
+		{"dev-vault", "--no-fsync", "--config", cfgPath, "pull", "app-dev"},
+		{"dev-vault", "--config", cfgPath, "pull", "app-dev", "--overwrite", "--no-fsync"},
+	} {
+		var out, errBuf bytes.Buffer
+		if code := Run(argv, &out, &errBuf, deps); code != 0 || fsx.SyncEnabled() {
+			t.Fatalf("%v: expected an unsynced pull, got code %d sync=%v stderr=%s", argv, code, fsx.SyncEnabled(), errBuf.String())
+		}
+	}
+	var out, errBuf bytes.Buffer
+	if code := Run([]string{"dev-vault", "--config", cfgPath, "pull", "app-dev", "--overwrite"}, &out, &errBuf, deps); code != 0 || !fsx.SyncEnabled() {
+		t.Fatalf("expected the next command to sync again, got code %d stderr=%s", code, errBuf.String())
+	}
+}
@@ -1,11 +1,10 @@
 package cli
 
 import (
-	"errors"
-	"fmt"
 	"sort"
 
 	"github.com/bsmartlabs/dev-vault/internal/config"
+	"github.com/bsmartlabs/dev-vault/internal/i18n"
 	"github.com/bsmartlabs/dev-vault/internal/secretsync"
 )
 
@@ -14,6 +13,7 @@ type commandMode int
 const (
 	commandModePull commandMode = iota + 1
 	commandModePush
+	commandModeSync
 )
 
 func (m commandMode) String() string {
@@ -22,6 +22,8 @@ func (m commandMode) String() string {
 		return "pull"
 	case commandModePush:
 		return "push"
+	case commandModeSync:
+		return "sync"
 	default:
 		return "unknown"
 	}
@@ -33,6 +35,8 @@ func (m commandMode) allows(entry config.MappingEntry) bool {
 		return entry.Mode.AllowsPull()
 	case commandModePush:
 		return entry.Mode.AllowsPush()
+	case commandModeSync:
+		return entry.Mode.AllowsPull() && entry.Mode.AllowsPush()
 	default:
 		return false
 	}
@@ -40,28 +44,20 @@ func (m commandMode) allows(entry config.MappingEntry) bool {
 
 func selectMappingTargetsForMode(mapping map[string]config.MappingEntry, all bool, positional []string, mode commandMode) ([]secretsync.MappingTarget, error) {
 	if all && len(positional) > 0 {
-		return nil, usageError(errors.New("cannot use --all with explicit secret names"))
+		return nil, usageError(i18n.Errorf(i18n.MsgAllWithNames))
 	}
 	if !all && len(positional) == 0 {
-		return nil, usageError(errors.New("no secrets specified (use --all or pass secret names)"))
+		return nil, usageError(i18n.Errorf(i18n.MsgNoSecretsSpecified))
 	}
 
-	if mode != commandModePull && mode != commandModePush {
-		return nil, usageError(fmt.Errorf("unsupported command mode: %s", mode.String()))
+	if mode != commandModePull && mode != commandModePush && mode != commandModeSync {
+		return nil, usageError(i18n.Errorf(i18n.MsgUnsupportedMode, mode.String()))
 	}
 
 	if all {
-		targets := make([]secretsync.MappingTarget, 0, len(mapping))
-		for name, entry := range mapping {
-			if mode.allows(entry) {
-				targets = append(targets, secretsync.MappingTarget{Name: name, Entry: secretsync.MappingEntryFromConfig(entry)})
-			}
-		}
-		sort.Slice(targets, func(i, j int) bool {
-			return targets[i].Name < targets[j].Name
-		})
+		targets := mappingTargetsForMode(mapping, mode)
 		if len(targets) == 0 {
-			return nil, usageError(fmt.Errorf("no mapping entries selected for %s", mode.String()))
+			return nil, usageError(i18n.Errorf(i18n.MsgNoMappingSelected, mode.String()))
 		}
 		return targets, nil
 	}
@@ -75,18 +71,31 @@ func selectMappingTargetsForMode(mapping map[string]config.MappingEntry, all boo
 		seen[name] = struct{}{}
 
 		if !config.IsDevSecretName(name) {
-			return nil, usageError(fmt.Errorf("refusing non-dev secret name: %s", name))
+			return nil, usageError(i18n.Errorf(i18n.MsgRefusingNonDev, name))
 		}
 
 		entry, ok := mapping[name]
 		if !ok {
-			return nil, usageError(fmt.Errorf("secret not found in mapping: %s", name))
+			return nil, usageError(i18n.Errorf(i18n.MsgSecretNotMapped, name))
 		}
 		if !mode.allows(entry) {
-			return nil, usageError(fmt.Errorf("secret %s not allowed in %s mode (mapping.mode=%s)", name, mode.String(), entry.Mode))
+			return nil, usageError(i18n.Errorf(i18n.MsgModeNotAllowed, name, mode.String(), entry.Mode))
 		}
 		targets = append(targets, secretsync.MappingTarget{Name: name, Entry: secretsync.MappingEntryFromConfig(entry)})
 	}
 
 	return targets, nil
 }
+
+func mappingTargetsForMode(mapping map[string]config.MappingEntry, mode commandMode) []secretsync.MappingTarget {
+	targets := make([]secretsync.MappingTarget, 0, len(mapping))
+	for name, entry := range mapping {
+		if !entry.Disabled && mode.allows(entry) {
+			targets = append(targets, secretsync.MappingTarget{Name: name, Entry: secretsync.MappingEntryFromConfig(entry)})
+		}
+	}
+	sort.Slice(targets, func(i, j int) bool {
+		return targets[i].Name < targets[j].Name
+	})
+	return targets
+}
@@ -0,0 +1,42 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+// table renders aligned columns for terminals, or a single tab between cells
+// in plain mode so screen readers and dumb terminals get stable separators.
+type table struct {
+	w     io.Writer
+	plain bool
+	rows  [][]string
+}
+
+func newTable(w io.Writer, plain bool, header ...string) *table {
+	t := &table{w: w, plain: plain}
+	t.row(header...)
+	return t
+}
+
+func (t *table) row(cells ...string) {
+	t.rows = append(t.rows, cells)
+}
+
+func (t *table) flush() error {
+	if t.plain {
+		for _, cells := range t.rows {
+			if _, err := fmt.Fprintln(t.w, strings.Join(cells, "\t")); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	tw := tabwriter.NewWriter(t.w, 0, 0, 2, ' ', 0)
+	for _, cells := range t.rows {
+		_, _ = fmt.Fprintln(tw, strings.Join(cells, "\t"))
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,58 @@
+package cli
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/bsmartlabs/dev-vault/internal/config"
+	secret "github.com/scaleway/scaleway-sdk-go/api/secret/v1beta1"
+)
+
+func TestTable_AlignedAndPlain(t *testing.T) {
+	var aligned bytes.Buffer
+	tbl := newTable(&aligned, false, "NAME", "ID")
+	tbl.row("long-name-dev", "1")
+	if err := tbl.flush(); err != nil {
+		t.Fatalf("flush aligned: %v", err)
+	}
+	if got := aligned.String(); got != "NAME           ID\nlong-name-dev  1\n" {
+		t.Fatalf("unexpected aligned output: %q", got)
+	}
+
+	var plain bytes.Buffer
+	tbl = newTable(&plain, true, "NAME", "ID")
+	tbl.row("long-name-dev", "1")
+	if err := tbl.flush(); err != nil {
+		t.Fatalf("flush plain: %v", err)
+	}
+	if got := plain.String(); got != "NAME\tID\nlong-name-dev\t1\n" {
+		t.Fatalf("unexpected plain output: %q", got)
+	}
+
+	if err := newTable(&failingWriter{}, true, "NAME").flush(); err == nil {
+		t.Fatal("expected plain write error")
+	}
+}
+
+func TestRunList_Plain(t *testing.T) {
+	root := t.TempDir()
+	cfgPath := writeConfig(t, root, `{"organization_id":"org","project_id":"proj","region":"fr-par","mapping":{"x-dev":{"file":"x"}}}`)
+	api := newFakeSecretAPI()
+	api.AddSecret("proj", "x-dev", "/", secret.SecretTypeOpaque)
+	deps := baseDeps(func(cfg config.Config, s string) (SecretAPI, error) { return api, nil })
+
+	want := "NAME\tTYPE\tPATH\tID\nx-dev\topaque\t/\tsec-1\n"
+	for _, args := range [][]string{
+		{"dev-vault", "--plain", "--config", cfgPath, "list"},
+		{"dev-vault", "--config", cfgPath, "list", "--plain"},
+	} {
+		var out, errBuf bytes.Buffer
+		code := Run(args, &out, &errBuf, deps)
+		if code != 0 {
+			t.Fatalf("%v: expected 0, got %d stderr=%s", args, code, errBuf.String())
+		}
+		if out.String() != want {
+			t.Fatalf("%v: unexpected plain list output: %q", args, out.String())
+		}
+	}
+}
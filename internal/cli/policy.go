@@ -0,0 +1,95 @@
+package cli
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/bsmartlabs/dev-vault/internal/config"
+	"github.com/bsmartlabs/dev-vault/internal/i18n"
+	"github.com/bsmartlabs/dev-vault/internal/policy"
+	"github.com/bsmartlabs/dev-vault/internal/secretsync"
+)
+
+const policyFileFlagHelp = "Policy JSON file overriding the policy section of .scw.json"
+
+// policyRules resolves the active policy: --policy-file wins over the
+// manifest's "policy" section so org-wide rules can be applied uniformly.
+func (r commandRuntime) policyRules(loaded *config.Loaded) (*config.Policy, policy.Rules, error) {
+	active := loaded.Cfg.Policy
+	if path := r.parsed.String("policy-file"); path != "" {
+		if !filepath.IsAbs(path) {
+			wd, err := r.ctx.deps.Getwd()
+			if err != nil {
+				return nil, policy.Rules{}, runtimeError(i18n.Errorf(i18n.MsgGetwd, err))
+			}
+			path = filepath.Join(wd, path)
+		}
+		loadedPolicy, err := config.LoadPolicyFile(path)
+		if err != nil {
+			return nil, policy.Rules{}, runtimeError(err)
+		}
+		active = loadedPolicy
+	}
+	rules, err := policy.Compile(active)
+	if err != nil {
+		return nil, policy.Rules{}, runtimeError(err)
+	}
+	return active, rules, nil
+}
+
+func (r commandRuntime) checkPushPolicy(loaded *config.Loaded, service secretsync.Service, targets []secretsync.MappingTarget) error {
+	_, rules, err := r.policyRules(loaded)
+	if err != nil {
+		return err
+	}
+	violations, err := service.PushPolicyViolations(targets, rules)
+	if err != nil {
+		return err
+	}
+	blocking := 0
+	for _, violation := range violations {
+		if !rules.Blocking(violation) {
+			if err := r.parsed.warnings.warnError(warningPolicy, i18n.Errorf(i18n.MsgWarnPolicy, violation)); err != nil {
+				return outputError(err)
+			}
+			continue
+		}
+		blocking++
+		if _, err := fmt.Fprintln(r.ctx.stderr, r.parsed.msg.Sprintf(i18n.MsgPolicyViolation, violation)); err != nil {
+			return outputError(err)
+		}
+	}
+	if blocking > 0 {
+		return runtimeError(i18n.Errorf(i18n.MsgPushBlocked, blocking))
+	}
+	// Under --strict-warnings, level=warn violations block the push too.
+	return r.parsed.warnings.strictError()
+}
+
+// remotePolicyViolations checks the remote secrets of every enabled mapping
+// against the metadata rules, max_secret_age and required_tags. It lists
+// nothing when no policy is active.
+func (r commandRuntime) remotePolicyViolations(loaded *config.Loaded, service secretsync.Service) ([]policy.Violation, error) {
+	active, rules, err := r.policyRules(loaded)
+	if err != nil || active == nil {
+		return nil, err
+	}
+	inv, err := service.Inventory()
+	if err != nil {
+		return nil, runtimeError(err)
+	}
+	return inventoryViolations(inv, rules, r.ctx.deps.Now()), nil
+}
+
+func inventoryViolations(inv secretsync.Inventory, rules policy.Rules, now time.Time) []policy.Violation {
+	var violations []policy.Violation
+	for _, entry := range inv.Entries {
+		if !entry.Remote {
+			continue
+		}
+		violations = append(violations, rules.CheckAge(entry.Name, entry.UpdatedAt, now)...)
+		violations = append(violations, rules.CheckTags(entry.Name, entry.Tags)...)
+	}
+	return violations
+}
@@ -0,0 +1,177 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bsmartlabs/dev-vault/internal/config"
+	secret "github.com/scaleway/scaleway-sdk-go/api/secret/v1beta1"
+)
+
+func TestRunPush_Policy(t *testing.T) {
+	setup := func(t *testing.T, policyJSON string) (string, *fakeSecretAPI, Dependencies) {
+		t.Helper()
+		root := t.TempDir()
+		cfgPath := writeConfig(t, root, `{"organization_id":"org","project_id":"proj","region":"fr-par","mapping":{
+			"app-dev":{"file":"app.env","format":"dotenv"}
+		}`+policyJSON+`}`)
+		if err := os.WriteFile(filepath.Join(root, "app.env"), []byte("PROD_TOKEN=x\n"), 0o600); err != nil {
+			t.Fatalf("write app.env: %v", err)
+		}
+		api := newFakeSecretAPI()
+		api.AddSecret("proj", "app-dev", "/", secret.SecretTypeKeyValue)
+		deps := baseDeps(func(cfg config.Config, s string) (SecretAPI, error) { return api, nil })
+		return cfgPath, api, deps
+	}
+
+	t.Run("WarnPushesAnyway", func(t *testing.T) {
+		cfgPath, api, deps := setup(t, `,"policy":{"forbidden_key_patterns":["^PROD_"]}`)
+		var out, errBuf bytes.Buffer
+		code := Run([]string{"dev-vault", "--config", cfgPath, "push", "app-dev"}, &out, &errBuf, deps)
+		if code != 0 {
+			t.Fatalf("expected 0, got %d stderr=%s", code, errBuf.String())
+		}
+		if !strings.Contains(errBuf.String(), "warning: policy: app-dev: forbidden_key_patterns: key PROD_TOKEN") {
+			t.Fatalf("expected warning, got %q", errBuf.String())
+		}
+		if len(api.versions["sec-1"]) != 1 {
+			t.Fatalf("expected a version to be created")
+		}
+	})
+
+	t.Run("StrictWarningsBlock", func(t *testing.T) {
+		cfgPath, api, deps := setup(t, `,"policy":{"forbidden_key_patterns":["^PROD_"]}`)
+		var out, errBuf bytes.Buffer
+		code := Run([]string{"dev-vault", "--config", cfgPath, "--strict-warnings", "push", "app-dev"}, &out, &errBuf, deps)
+		if code != 1 || !strings.Contains(errBuf.String(), "1 warning(s) treated as errors (--strict-warnings)") {
+			t.Fatalf("expected 1, got %d stderr=%s", code, errBuf.String())
+		}
+		if len(api.versions["sec-1"]) != 0 {
+			t.Fatalf("expected no version to be created")
+		}
+	})
+
+	t.Run("EnforceBlocks", func(t *testing.T) {
+		cfgPath, api, deps := setup(t, `,"policy":{"level":"enforce","required_tags":["owner"]}`)
+		var out, errBuf bytes.Buffer
+		code := Run([]string{"dev-vault", "--config", cfgPath, "push", "app-dev"}, &out, &errBuf, deps)
+		if code != 1 {
+			t.Fatalf("expected 1, got %d stderr=%s", code, errBuf.String())
+		}
+		if !strings.Contains(errBuf.String(), "policy violation: app-dev: required_tags: missing tags: owner") ||
+			!strings.Contains(errBuf.String(), "push blocked by policy: 1 violation(s)") {
+			t.Fatalf("unexpected stderr: %q", errBuf.String())
+		}
+		if len(api.versions["sec-1"]) != 0 {
+			t.Fatalf("expected no version to be created")
+		}
+		if code := Run([]string{"dev-vault", "--config", cfgPath, "push", "app-dev"}, &out, &failingWriter{}, deps); code != 1 {
+			t.Fatalf("expected violation write failure to return 1, got %d", code)
+		}
+	})
+
+	t.Run("PolicyFileOverridesConfig", func(t *testing.T) {
+		cfgPath, api, deps := setup(t, `,"policy":{"level":"enforce","required_tags":["owner"]}`)
+		dir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(dir, "org-policy.json"), []byte(`{"level":"warn","max_payload_bytes":4}`), 0o600); err != nil {
+			t.Fatalf("write policy: %v", err)
+		}
+		deps.Getwd = func() (string, error) { return dir, nil }
+		var out, errBuf bytes.Buffer
+		code := Run([]string{"dev-vault", "--config", cfgPath, "push", "app-dev", "--policy-file", "org-policy.json"}, &out, &errBuf, deps)
+		if code != 0 {
+			t.Fatalf("expected 0, got %d stderr=%s", code, errBuf.String())
+		}
+		if !strings.Contains(errBuf.String(), "warning: policy: app-dev: max_payload_bytes") || strings.Contains(errBuf.String(), "required_tags") {
+			t.Fatalf("unexpected stderr: %q", errBuf.String())
+		}
+		if len(api.versions["sec-1"]) != 1 {
+			t.Fatalf("expected a version to be created")
+		}
+	})
+
+	t.Run("RegoDenyBlocksAtWarnLevel", func(t *testing.T) {
+		cfgPath, api, deps := setup(t, `,"policy":{"rego":{"bundle":"policies"}}`)
+		bin := t.TempDir()
+		script := "#!/bin/sh\ncat > \"$(dirname \"$0\")/input.json\"\necho '{\"result\":[{\"expressions\":[{\"value\":[\"PROD keys are not allowed in dev secrets\"]}]}]}'\n"
+		if err := os.WriteFile(filepath.Join(bin, "opa"), []byte(script), 0o755); err != nil {
+			t.Fatalf("write fake opa: %v", err)
+		}
+		t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+		var out, errBuf bytes.Buffer
+		code := Run([]string{"dev-vault", "--config", cfgPath, "push", "app-dev"}, &out, &errBuf, deps)
+		if code != 1 {
+			t.Fatalf("expected 1, got %d stderr=%s", code, errBuf.String())
+		}
+		if !strings.Contains(errBuf.String(), "policy violation: rego: PROD keys are not allowed in dev secrets") {
+			t.Fatalf("unexpected stderr: %q", errBuf.String())
+		}
+		if len(api.versions["sec-1"]) != 0 {
+			t.Fatalf("expected no version to be created")
+		}
+		input, err := os.ReadFile(filepath.Join(bin, "input.json"))
+		if err != nil {
+			t.Fatalf("read opa input: %v", err)
+		}
+		if !strings.Contains(string(input), `"keys":["PROD_TOKEN"]`) || strings.Contains(string(input), `"x"`) {
+			t.Fatalf("unexpected opa input: %s", input)
+		}
+	})
+
+	t.Run("PolicyFileErrors", func(t *testing.T) {
+		cfgPath, _, deps := setup(t, "")
+		var out, errBuf bytes.Buffer
+		code := Run([]string{"dev-vault", "--config", cfgPath, "push", "app-dev", "--policy-file", filepath.Join(t.TempDir(), "missing.json")}, &out, &errBuf, deps)
+		if code != 1 || !strings.Contains(errBuf.String(), "read policy file") {
+			t.Fatalf("expected read error, got %d stderr=%s", code, errBuf.String())
+		}
+
+		// Getwd is consulted once to load .scw.json and again to resolve the
+		// relative policy path; fail only the second call.
+		calls := 0
+		deps.Getwd = func() (string, error) {
+			calls++
+			if calls > 1 {
+				return "", errors.New("no cwd")
+			}
+			return os.Getwd()
+		}
+		errBuf.Reset()
+		code = Run([]string{"dev-vault", "--config", cfgPath, "push", "app-dev", "--policy-file", "policy.json"}, &out, &errBuf, deps)
+		if code != 1 || !strings.Contains(errBuf.String(), "getwd: no cwd") {
+			t.Fatalf("expected getwd error, got %d stderr=%s", code, errBuf.String())
+		}
+	})
+
+	t.Run("LookupError", func(t *testing.T) {
+		cfgPath, api, deps := setup(t, `,"policy":{"required_tags":["owner"]}`)
+		api.listErr = errors.New("boom")
+		var out, errBuf bytes.Buffer
+		code := Run([]string{"dev-vault", "--config", cfgPath, "push", "app-dev"}, &out, &errBuf, deps)
+		if code != 1 || !strings.Contains(errBuf.String(), "resolve app-dev") {
+			t.Fatalf("expected resolve error, got %d stderr=%s", code, errBuf.String())
+		}
+	})
+
+	t.Run("WarningWriteError", func(t *testing.T) {
+		cfgPath, _, deps := setup(t, `,"policy":{"required_tags":["owner"]}`)
+		var out bytes.Buffer
+		code := Run([]string{"dev-vault", "--config", cfgPath, "push", "app-dev"}, &out, &failingWriter{}, deps)
+		if code != 1 {
+			t.Fatalf("expected 1, got %d", code)
+		}
+	})
+}
+
+func TestPolicyRules_CompileError(t *testing.T) {
+	rt := newCommandRuntime(commandContext{deps: baseDeps(nil)}, &parsedCommand{})
+	loaded := &config.Loaded{Cfg: config.Config{Policy: &config.Policy{MaxSecretAge: "soon"}}}
+	if _, _, err := rt.policyRules(loaded); err == nil || !strings.Contains(err.Error(), "max_secret_age") {
+		t.Fatalf("expected compile error, got %v", err)
+	}
+}
@@ -0,0 +1,83 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/bsmartlabs/dev-vault/internal/config"
+	"github.com/bsmartlabs/dev-vault/internal/i18n"
+	"github.com/bsmartlabs/dev-vault/internal/progress"
+	"github.com/bsmartlabs/dev-vault/internal/secretsync"
+)
+
+const resumeFlagHelp = "Skip targets completed by the last interrupted or failed run for this config"
+
+// batchProgress records completed targets in the state dir while a batch runs.
+// Recording is best effort: without a state dir, or when a write fails, the
+// batch itself still proceeds.
+type batchProgress struct {
+	store   progress.Store
+	record  progress.Record
+	enabled bool
+}
+
+// startBatchProgress opens a fresh progress record for this batch. With
+// resume set it first drops the targets the previous record marks completed.
+// A dry run reads the previous record but writes none.
+func (r commandRuntime) startBatchProgress(loaded *config.Loaded, mode commandMode, resume, dryRun bool, targets []secretsync.MappingTarget) (*batchProgress, []secretsync.MappingTarget, error) {
+	operation := mode.String()
+	dir, err := stateDir(r.ctx.deps)
+	if err != nil {
+		if resume {
+			return nil, nil, runtimeError(i18n.Errorf(i18n.MsgResume, operation, err))
+		}
+		return &batchProgress{}, targets, nil
+	}
+	p := &batchProgress{
+		store:   progress.NewStore(dir),
+		record:  progress.Record{Operation: operation, Config: loaded.Path},
+		enabled: !dryRun,
+	}
+	if resume {
+		previous, ok, err := p.store.Load(operation, loaded.Path)
+		if err != nil {
+			return nil, nil, runtimeError(i18n.Errorf(i18n.MsgResume, operation, err))
+		}
+		if !ok {
+			if _, err := fmt.Fprintln(r.ctx.stderr, r.parsed.msg.Sprintf(i18n.MsgNoRecordedProgress, operation)); err != nil {
+				return nil, nil, outputError(err)
+			}
+		} else {
+			remaining := make([]secretsync.MappingTarget, 0, len(targets))
+			for _, target := range targets {
+				if !previous.IsCompleted(target.Name) {
+					remaining = append(remaining, target)
+				}
+			}
+			if _, err := fmt.Fprintln(r.ctx.stderr, r.parsed.msg.Sprintf(i18n.MsgResuming, operation, len(targets)-len(remaining))); err != nil {
+				return nil, nil, outputError(err)
+			}
+			p.record.Completed = previous.Completed
+			targets = remaining
+		}
+	}
+	p.save()
+	return p, targets, nil
+}
+
+func (p *batchProgress) done(name string) {
+	p.record.Completed = append(p.record.Completed, name)
+	p.save()
+}
+
+func (p *batchProgress) save() {
+	if p.enabled {
+		_ = p.store.Save(p.record)
+	}
+}
+
+// finish drops the record once every selected target has completed.
+func (p *batchProgress) finish() {
+	if p.enabled {
+		_ = p.store.Clear(p.record.Operation, p.record.Config)
+	}
+}
@@ -0,0 +1,129 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bsmartlabs/dev-vault/internal/config"
+	"github.com/bsmartlabs/dev-vault/internal/progress"
+	secret "github.com/scaleway/scaleway-sdk-go/api/secret/v1beta1"
+)
+
+func TestRunPush_Resume(t *testing.T) {
+	setup := func(t *testing.T) (string, *fakeSecretAPI, Dependencies) {
+		t.Helper()
+		root := t.TempDir()
+		cfgPath := writeConfig(t, root, `{"organization_id":"org","project_id":"proj","region":"fr-par","mapping":{
+			"a-dev":{"file":"a.bin","format":"raw"},
+			"b-dev":{"file":"b.bin","format":"raw"}
+		}}`)
+		for _, name := range []string{"a.bin", "b.bin"} {
+			if err := os.WriteFile(filepath.Join(root, name), []byte("DATA"), 0o600); err != nil {
+				t.Fatalf("write %s: %v", name, err)
+			}
+		}
+		api := newFakeSecretAPI()
+		api.AddSecret("proj", "a-dev", "/", secret.SecretTypeOpaque)
+		deps := baseDeps(func(cfg config.Config, s string) (SecretAPI, error) { return api, nil })
+		stateRoot := t.TempDir()
+		deps.UserConfigDir = func() (string, error) { return stateRoot, nil }
+		return cfgPath, api, deps
+	}
+
+	t.Run("SkipsCompletedTargets", func(t *testing.T) {
+		cfgPath, api, deps := setup(t)
+		var out, errBuf bytes.Buffer
+		// b-dev does not exist yet, so the batch fails after a-dev was pushed.
+		code := Run([]string{"dev-vault", "--config", cfgPath, "push", "--all", "--yes"}, &out, &errBuf, deps)
+		if code != 1 || len(api.versions["sec-1"]) != 1 {
+			t.Fatalf("expected partial failure, got %d stderr=%s", code, errBuf.String())
+		}
+		api.AddSecret("proj", "b-dev", "/", secret.SecretTypeOpaque)
+
+		out.Reset()
+		errBuf.Reset()
+		code = Run([]string{"dev-vault", "--config", cfgPath, "push", "--all", "--yes", "--resume"}, &out, &errBuf, deps)
+		if code != 0 {
+			t.Fatalf("expected 0, got %d stderr=%s", code, errBuf.String())
+		}
+		if !strings.Contains(errBuf.String(), "resuming push: skipping 1 completed target(s)") || out.String() != "pushed b-dev (rev=1)\n" {
+			t.Fatalf("unexpected output: stdout=%q stderr=%q", out.String(), errBuf.String())
+		}
+		if len(api.versions["sec-1"]) != 1 || len(api.versions["sec-2"]) != 1 {
+			t.Fatalf("expected a-dev to be skipped and b-dev pushed")
+		}
+
+		// A fully successful run clears the record.
+		errBuf.Reset()
+		code = Run([]string{"dev-vault", "--config", cfgPath, "push", "--all", "--yes", "--resume"}, &out, &errBuf, deps)
+		if code != 0 || !strings.Contains(errBuf.String(), "no recorded push progress; running all targets") {
+			t.Fatalf("expected fresh run, got %d stderr=%s", code, errBuf.String())
+		}
+	})
+
+	t.Run("NothingLeft", func(t *testing.T) {
+		cfgPath, api, deps := setup(t)
+		dir, _ := stateDir(deps)
+		loaded, err := config.Load(filepath.Dir(cfgPath), cfgPath)
+		if err != nil {
+			t.Fatalf("load config: %v", err)
+		}
+		store := progress.NewStore(dir)
+		if err := store.Save(progress.Record{Operation: "push", Config: loaded.Path, Completed: []string{"a-dev", "b-dev"}}); err != nil {
+			t.Fatalf("save progress: %v", err)
+		}
+		var out, errBuf bytes.Buffer
+		code := Run([]string{"dev-vault", "--config", cfgPath, "push", "--all", "--yes", "--resume"}, &out, &errBuf, deps)
+		if code != 0 || out.Len() != 0 || len(api.versions["sec-1"]) != 0 {
+			t.Fatalf("expected nothing to push, got %d stdout=%s stderr=%s", code, out.String(), errBuf.String())
+		}
+		if _, ok, _ := store.Load("push", loaded.Path); ok {
+			t.Fatal("expected record to be cleared")
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		cfgPath, _, deps := setup(t)
+		var out, errBuf bytes.Buffer
+
+		noState := deps
+		noState.UserConfigDir = baseDeps(nil).UserConfigDir
+		code := Run([]string{"dev-vault", "--config", cfgPath, "push", "a-dev", "--resume"}, &out, &errBuf, noState)
+		if code != 1 || !strings.Contains(errBuf.String(), "resume push: locate user config dir") {
+			t.Fatalf("expected state dir error, got %d stderr=%s", code, errBuf.String())
+		}
+
+		dir, _ := stateDir(deps)
+		loaded, _ := config.Load(filepath.Dir(cfgPath), cfgPath)
+		path := progress.NewStore(dir).Path("push", loaded.Path)
+		if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte("{"), 0o600); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+		errBuf.Reset()
+		code = Run([]string{"dev-vault", "--config", cfgPath, "push", "a-dev", "--resume"}, &out, &errBuf, deps)
+		if code != 1 || !strings.Contains(errBuf.String(), "resume push: decode progress") {
+			t.Fatalf("expected decode error, got %d stderr=%s", code, errBuf.String())
+		}
+
+		if err := progress.NewStore(dir).Save(progress.Record{Operation: "push", Config: loaded.Path}); err != nil {
+			t.Fatalf("save progress: %v", err)
+		}
+		code = Run([]string{"dev-vault", "--config", cfgPath, "push", "a-dev", "--resume"}, &out, &failingWriter{}, deps)
+		if code != 1 {
+			t.Fatalf("expected resume notice write error, got %d", code)
+		}
+		if err := os.Remove(path); err != nil {
+			t.Fatalf("remove: %v", err)
+		}
+		code = Run([]string{"dev-vault", "--config", cfgPath, "push", "a-dev", "--resume"}, &out, &failingWriter{}, deps)
+		if code != 1 {
+			t.Fatalf("expected fresh-run notice write error, got %d", code)
+		}
+	})
+}
@@ -22,14 +22,22 @@ type SecretRecord = secretprovider.SecretRecord
 type ListSecretsInput = secretprovider.ListSecretsInput
 type AccessSecretVersionInput = secretprovider.AccessSecretVersionInput
 type SecretVersionRecord = secretprovider.SecretVersionRecord
+type ListSecretVersionsInput = secretprovider.ListSecretVersionsInput
 type CreateSecretInput = secretprovider.CreateSecretInput
 type CreateSecretVersionInput = secretprovider.CreateSecretVersionInput
+type DeleteSecretInput = secretprovider.DeleteSecretInput
+type DeleteSecretVersionInput = secretprovider.DeleteSecretVersionInput
 
 type SecretLister = secretprovider.SecretLister
 type SecretVersionAccessor = secretprovider.SecretVersionAccessor
+type SecretVersionLister = secretprovider.SecretVersionLister
 type SecretCreator = secretprovider.SecretCreator
 type SecretVersionCreator = secretprovider.SecretVersionCreator
+type SecretDeleter = secretprovider.SecretDeleter
+type SecretVersionDeleter = secretprovider.SecretVersionDeleter
+type DisableSecretVersionInput = secretprovider.DisableSecretVersionInput
 type SecretAPI = secretprovider.SecretAPI
+type AccountAPI = secretprovider.AccountAPI
 
 func OpenScalewaySecretAPI(cfg config.Config, profileOverride string) (SecretAPI, error) {
 	return scwprovider.Open(cfg, profileOverride)
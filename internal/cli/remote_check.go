@@ -0,0 +1,29 @@
+package cli
+
+import (
+	"github.com/bsmartlabs/dev-vault/internal/config"
+	"github.com/bsmartlabs/dev-vault/internal/remotecheck"
+)
+
+// recordRemoteCheck saves the outcome of a remote check of loaded's config for
+// prompt. It is best effort: a check that cannot be saved only leaves prompt
+// with an older one.
+func recordRemoteCheck(deps Dependencies, loaded *config.Loaded, reachable bool, drifted []string) {
+	dir, err := stateDir(deps)
+	if err != nil {
+		return
+	}
+	entry := remotecheck.Entry{Config: loaded.Path, CheckedAt: deps.Now(), Reachable: reachable, Drifted: drifted}
+	_ = remotecheck.NewStore(dir).Save(entry)
+}
+
+// lastRemoteCheck returns the last recorded remote check of loaded's config,
+// or ok=false when none can be read.
+func lastRemoteCheck(deps Dependencies, loaded *config.Loaded) (remotecheck.Entry, bool) {
+	dir, err := stateDir(deps)
+	if err != nil {
+		return remotecheck.Entry{}, false
+	}
+	entry, ok, _ := remotecheck.NewStore(dir).Load(loaded.Path)
+	return entry, ok
+}
@@ -0,0 +1,66 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/bsmartlabs/dev-vault/internal/config"
+	"github.com/bsmartlabs/dev-vault/internal/i18n"
+)
+
+// checkRequiredVersion compares this binary with the manifest's
+// required_version. Outside the range it returns a warning, or an error with
+// --enforce-version. Development builds cannot be placed in a range, so they
+// are only refused when enforcing.
+func checkRequiredVersion(loaded *config.Loaded, version string, enforce bool) (warning, err error) {
+	if loaded.Cfg.RequiredVersion == "" {
+		return nil, nil
+	}
+	// Validated at load time.
+	versionRange, _ := config.ParseVersionRange(loaded.Cfg.RequiredVersion)
+	allowed, ok := versionRange.Allows(version)
+	if !ok {
+		if enforce {
+			return nil, runtimeError(i18n.Errorf(i18n.MsgCannotEnforceVersion, loaded.Cfg.RequiredVersion, version))
+		}
+		return nil, nil
+	}
+	if allowed {
+		return nil, nil
+	}
+	if enforce {
+		return nil, runtimeError(i18n.Errorf(i18n.MsgOutsideRequiredVersion, version, loaded.Cfg.RequiredVersion, loaded.Path))
+	}
+	return i18n.Errorf(i18n.MsgWarnOutsideRequiredVersion, version, loaded.Cfg.RequiredVersion, loaded.Path), nil
+}
+
+// unknownFieldsGuidance explains manifest fields this binary does not know.
+// When this release is older than the manifest's required_version allows, the
+// fields come from a newer one, so the error names the release they need.
+func unknownFieldsGuidance(err error, version string) error {
+	var unknown *config.UnknownFieldsError
+	if !errors.As(err, &unknown) {
+		return err
+	}
+	// An empty or invalid range has no minimum.
+	versionRange, _ := config.ParseVersionRange(unknown.RequiredVersion)
+	if minimum, ok := versionRange.Minimum(); ok {
+		minimumRange, _ := config.ParseVersionRange(minimum)
+		if allowed, ok := minimumRange.Allows(version); ok && !allowed {
+			quoted := make([]string, len(unknown.Fields))
+			for i, field := range unknown.Fields {
+				quoted[i] = fmt.Sprintf("%q", field)
+			}
+			id := i18n.MsgFieldRequiresVersion
+			if len(quoted) > 1 {
+				id = i18n.MsgFieldsRequireVersion
+			}
+			return i18n.Errorf(id, strings.Join(quoted, ", "), minimum, version, unknown.RequiredVersion)
+		}
+	}
+	if len(unknown.Fields) > 1 {
+		return i18n.Errorf(i18n.MsgUnknownFieldsNewer, err)
+	}
+	return i18n.Errorf(i18n.MsgUnknownFieldNewer, err)
+}
@@ -0,0 +1,88 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/bsmartlabs/dev-vault/internal/config"
+)
+
+func TestRun_RequiredVersion(t *testing.T) {
+	dir := t.TempDir()
+	cfgPath := writeConfig(t, dir, `{"organization_id":"org","project_id":"proj","region":"fr-par","required_version":">=1.4.0, <2.0.0","mapping":{"app-dev":{"file":"app.env"}}}`)
+	api := newFakeSecretAPI()
+	run := func(version string, stderr *bytes.Buffer, args ...string) int {
+		deps := baseDeps(func(config.Config, string) (SecretAPI, error) { return api, nil })
+		deps.Version = version
+		var out bytes.Buffer
+		return Run(append([]string{"dev-vault", "--config", cfgPath}, args...), &out, stderr, deps)
+	}
+
+	var errBuf bytes.Buffer
+	if code := run("v1.5.0", &errBuf, "--enforce-version", "list"); code != 0 || errBuf.Len() != 0 {
+		t.Fatalf("in-range version: %d %q", code, errBuf.String())
+	}
+
+	errBuf.Reset()
+	if code := run("v2.1.0", &errBuf, "list"); code != 0 ||
+		!strings.Contains(errBuf.String(), `warning: dev-vault v2.1.0 is outside required_version ">=1.4.0, <2.0.0" in `+cfgPath) {
+		t.Fatalf("expected warning: %d %q", code, errBuf.String())
+	}
+
+	deps := baseDeps(func(config.Config, string) (SecretAPI, error) { return api, nil })
+	deps.Version = "v2.1.0"
+	if code := Run([]string{"dev-vault", "--config", cfgPath, "list"}, &bytes.Buffer{}, &failingWriter{}, deps); code != 1 {
+		t.Fatalf("expected warning write failure to return 1, got %d", code)
+	}
+
+	errBuf.Reset()
+	if code := run("v2.1.0", &errBuf, "list", "--enforce-version"); code != 1 ||
+		!strings.Contains(errBuf.String(), "install a matching release") || strings.Contains(errBuf.String(), "warning:") {
+		t.Fatalf("expected refusal: %d %q", code, errBuf.String())
+	}
+
+	errBuf.Reset()
+	if code := run("dev", &errBuf, "list"); code != 0 || errBuf.Len() != 0 {
+		t.Fatalf("dev build should not warn: %d %q", code, errBuf.String())
+	}
+	if code := run("dev", &errBuf, "--enforce-version", "list"); code != 1 || !strings.Contains(errBuf.String(), "is not a release build") {
+		t.Fatalf("dev build should be refused when enforcing: %d %q", code, errBuf.String())
+	}
+}
+
+func TestRun_UnknownFields(t *testing.T) {
+	dir := t.TempDir()
+	api := newFakeSecretAPI()
+	run := func(manifest, version string, args ...string) (int, string) {
+		cfgPath := writeConfig(t, dir, manifest)
+		deps := baseDeps(func(config.Config, string) (SecretAPI, error) { return api, nil })
+		deps.Version = version
+		var out, errBuf bytes.Buffer
+		code := Run(append([]string{"dev-vault", "--config", cfgPath}, args...), &out, &errBuf, deps)
+		return code, errBuf.String()
+	}
+	one := `{"organization_id":"org","project_id":"proj","region":"fr-par","required_version":">=1.4.0","mapping":{"app-dev":{"file":"app.env","chmod":"0600"}}}`
+	two := `{"organization_id":"org","project_id":"proj","region":"fr-par","required_version":">=1.4.0","hooks":{},"mapping":{"app-dev":{"file":"app.env","chmod":"0600"}}}`
+
+	for _, tc := range []struct {
+		manifest, version, want string
+	}{
+		{one, "v1.3.0", `load config: field "mapping.app-dev.chmod" requires dev-vault >= 1.4.0 (this is v1.3.0, required_version ">=1.4.0"); upgrade, or pass --ignore-unknown to skip it`},
+		{two, "v1.3.0", `load config: fields "hooks", "mapping.app-dev.chmod" require dev-vault >= 1.4.0 (this is v1.3.0, required_version ">=1.4.0"); upgrade, or pass --ignore-unknown to skip them`},
+		{one, "v1.5.0", `load config: decode config json: unknown field "mapping.app-dev.chmod"; a newer dev-vault may know it: upgrade, or pass --ignore-unknown to skip it`},
+		{two, "dev", `unknown fields "hooks", "mapping.app-dev.chmod"; a newer dev-vault may know them`},
+	} {
+		if code, errOut := run(tc.manifest, tc.version, "list"); code != 1 || !strings.Contains(errOut, tc.want) {
+			t.Fatalf("%s: expected %q, got %d %q", tc.version, tc.want, code, errOut)
+		}
+	}
+
+	code, errOut := run(one, "v1.5.0", "--ignore-unknown", "list")
+	if code != 0 || !strings.Contains(errOut, `warning: ignoring unknown field "mapping.app-dev.chmod" in `) {
+		t.Fatalf("expected a warning, got %d %q", code, errOut)
+	}
+	if code, errOut := run(one, "v1.5.0", "status", "--ignore-unknown", "--strict-warnings"); code != 1 || !strings.Contains(errOut, "ignoring unknown field") {
+		t.Fatalf("expected strict warnings to refuse, got %d %q", code, errOut)
+	}
+}
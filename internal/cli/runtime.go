@@ -1,18 +1,35 @@
 package cli
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"strings"
+	"sync/atomic"
 
 	"github.com/bsmartlabs/dev-vault/internal/config"
+	"github.com/bsmartlabs/dev-vault/internal/i18n"
 	"github.com/bsmartlabs/dev-vault/internal/secretprovider"
 	"github.com/bsmartlabs/dev-vault/internal/secretsync"
 )
 
+const coerceFlagHelp = "Use a secret whose type differs from mapping.type when its payload converts safely, with a warning"
+
 type mappingCommandSpec struct {
-	mode      commandMode
-	all       bool
-	preflight func(targets []secretsync.MappingTarget) error
-	execute   func(service secretsync.Service, targets []secretsync.MappingTarget) error
+	mode   commandMode
+	all    bool
+	resume bool
+	// onLoad, when set, receives the manifest before preflight runs.
+	onLoad      func(loaded *config.Loaded)
+	preflight   func(targets []secretsync.MappingTarget) error
+	checkPolicy bool
+	// pushTargets narrows the targets checked against the push policy to the
+	// ones the command will push; all of them when nil.
+	pushTargets func(service secretsync.Service, targets []secretsync.MappingTarget) ([]secretsync.MappingTarget, error)
+	coerce      bool
+	// dryRun runs the batch on a dry-run service and records no progress.
+	dryRun  bool
+	execute func(service secretsync.Service, targets []secretsync.MappingTarget) error
 }
 
 type commandRuntime struct {
@@ -25,24 +42,112 @@ func newCommandRuntime(ctx commandContext, parsed *parsedCommand) commandRuntime
 }
 
 func (r commandRuntime) execute(run func(loaded *config.Loaded, service secretsync.Service) error) int {
-	loaded, api, err := loadAndOpenAPI(r.parsed.configPath, r.parsed.profileOverride, r.ctx.deps)
+	loaded, api, err := loadAndOpenAPI(r.parsed.configPath, r.parsed.profileOverride, r.parsed.loadOptions(), r.ctx.deps)
 	if err != nil {
 		runErr := runtimeError(err)
-		_, _ = fmt.Fprintln(r.ctx.stderr, runErr.Error())
+		_, _ = fmt.Fprintln(r.ctx.stderr, r.parsed.msg.Error(runErr))
+		return exitCodeForError(runErr)
+	}
+	return r.run(loaded, api, run)
+}
+
+// executeLocal runs commands that only need the manifest and local files; the
+// provider is never opened, so the service must not be used for API calls.
+func (r commandRuntime) executeLocal(run func(loaded *config.Loaded, service secretsync.Service) error) int {
+	loaded, err := loadConfig(r.parsed.configPath, r.parsed.loadOptions(), r.ctx.deps)
+	if err != nil {
+		runErr := runtimeError(err)
+		_, _ = fmt.Fprintln(r.ctx.stderr, r.parsed.msg.Error(runErr))
+		return exitCodeForError(runErr)
+	}
+	return r.run(loaded, nil, run)
+}
+
+// executeAccount runs account-level discovery commands, which work without a
+// .scw.json so they can help write one.
+func (r commandRuntime) executeAccount(run func(api secretprovider.AccountAPI) error) int {
+	if err := requireSingleProfile(r.parsed.profileOverride, r.parsed.fs.Name()); err != nil {
+		_, _ = fmt.Fprintln(r.ctx.stderr, r.parsed.msg.Error(err))
+		return exitCodeForError(err)
+	}
+	api, err := r.ctx.deps.OpenAccountAPI(r.parsed.profileOverride)
+	if err != nil {
+		runErr := runtimeError(i18n.Errorf(i18n.MsgOpenAccountAPI, err))
+		_, _ = fmt.Fprintln(r.ctx.stderr, r.parsed.msg.Error(runErr))
 		return exitCodeForError(runErr)
 	}
+	if err := run(api); err != nil {
+		_, _ = fmt.Fprintln(r.ctx.stderr, r.parsed.msg.Error(err))
+		return exitCodeForError(err)
+	}
+	return 0
+}
 
-	if err := printConfigWarnings(r.ctx.stderr, loaded.Warnings); err != nil {
+// executeStandalone runs commands that need neither .scw.json nor a provider.
+func (r commandRuntime) executeStandalone(run func() error) int {
+	if err := run(); err != nil {
+		_, _ = fmt.Fprintln(r.ctx.stderr, r.parsed.msg.Error(err))
+		return exitCodeForError(err)
+	}
+	return 0
+}
+
+func (r commandRuntime) run(loaded *config.Loaded, api secretprovider.SecretAPI, run func(loaded *config.Loaded, service secretsync.Service) error) int {
+	recordCheckout(r.ctx.deps, loaded)
+	versionWarning, err := checkRequiredVersion(loaded, r.ctx.deps.Version, r.parsed.enforceVersion)
+	if err != nil {
+		_, _ = fmt.Fprintln(r.ctx.stderr, r.parsed.msg.Error(err))
+		return exitCodeForError(err)
+	}
+	if versionWarning != nil {
+		if err := r.parsed.warnings.warnError(warningRequiredVersion, versionWarning); err != nil {
+			runErr := outputError(err)
+			_, _ = fmt.Fprintln(r.ctx.stderr, r.parsed.msg.Error(runErr))
+			return exitCodeForError(runErr)
+		}
+	}
+	if err := r.parsed.warnings.warnAll(warningConfig, configWarnings(loaded.Warnings)); err != nil {
 		runErr := outputError(err)
-		_, _ = fmt.Fprintln(r.ctx.stderr, runErr.Error())
+		_, _ = fmt.Fprintln(r.ctx.stderr, r.parsed.msg.Error(runErr))
 		return exitCodeForError(runErr)
 	}
-	service := secretsync.NewFromLoaded(loaded, api, secretsync.Dependencies{
+	// Manifest warnings stop a strict run before it changes anything.
+	if err := r.parsed.warnings.strictError(); err != nil {
+		_, _ = fmt.Fprintln(r.ctx.stderr, r.parsed.msg.Error(err))
+		return exitCodeForError(err)
+	}
+	serviceDeps := secretsync.Dependencies{
 		Now:      r.ctx.deps.Now,
 		Hostname: r.ctx.deps.Hostname,
-	})
-	if err := run(loaded, service); err != nil {
-		_, _ = fmt.Fprintln(r.ctx.stderr, err.Error())
+		Getenv:   r.ctx.deps.Getenv,
+		Bases:    openSyncBases(r.ctx.deps, loaded),
+	}
+	start := r.ctx.deps.Now()
+	calls := new(atomic.Int64)
+	if api != nil {
+		// Payloads are fetched once per invocation, however many mappings
+		// read them.
+		api = secretprovider.NewAccessCache(countingAPI{SecretAPI: api, calls: calls})
+		serviceDeps.OpenProfile = func(profile string) (secretprovider.SecretAPI, error) {
+			profileAPI, err := r.ctx.deps.OpenSecretAPI(ownProjectConfig(loaded.Cfg), profile)
+			if err != nil {
+				return nil, err
+			}
+			return secretprovider.NewAccessCache(countingAPI{SecretAPI: profileAPI, calls: calls}), nil
+		}
+	}
+	service := secretsync.NewFromLoaded(loaded, api, serviceDeps)
+	err = run(loaded, service)
+	if err == nil {
+		err = r.checkBudget(loaded.Cfg.Budget, calls.Load(), r.ctx.deps.Now().Sub(start))
+	}
+	if err == nil {
+		err = r.parsed.warnings.strictError()
+	}
+	if err != nil {
+		err = readOnlyGuidance(err, loaded, r.parsed.profileOverride)
+		err = accessGuidance(err, loaded.Cfg.AccessContact)
+		_, _ = fmt.Fprintln(r.ctx.stderr, r.parsed.msg.Error(err))
 		return exitCodeForError(err)
 	}
 	return 0
@@ -50,31 +155,191 @@ func (r commandRuntime) execute(run func(loaded *config.Loaded, service secretsy
 
 func (r commandRuntime) executeMapping(spec mappingCommandSpec) int {
 	return r.execute(func(loaded *config.Loaded, service secretsync.Service) error {
+		if spec.onLoad != nil {
+			spec.onLoad(loaded)
+		}
 		targets, err := selectMappingTargetsForMode(loaded.Cfg.Mapping, spec.all, r.parsed.fs.Args(), spec.mode)
 		if err != nil {
 			return err
 		}
+		batch, targets, err := r.startBatchProgress(loaded, spec.mode, spec.resume, spec.dryRun, targets)
+		if err != nil {
+			return err
+		}
+		if len(targets) == 0 {
+			batch.finish()
+			return nil
+		}
+		var coerced []error
+		if spec.coerce {
+			service = service.WithCoerce(func(mismatch *secretsync.SecretTypeMismatchError) {
+				coerced = append(coerced, i18n.Errorf(i18n.MsgWarnCoerced, mismatch.Name, mismatch.Record.Type, mismatch.Want))
+			})
+		}
+		if spec.dryRun {
+			service = service.WithDryRun()
+		}
 		if spec.preflight != nil {
 			if err := spec.preflight(targets); err != nil {
 				return err
 			}
 		}
-		return spec.execute(service, targets)
+		if spec.checkPolicy {
+			pushed := targets
+			if spec.pushTargets != nil {
+				if pushed, err = spec.pushTargets(service, targets); err != nil {
+					return err
+				}
+			}
+			if len(pushed) > 0 {
+				if err := r.checkPushPolicy(loaded, service, pushed); err != nil {
+					return err
+				}
+			}
+		}
+		// The first signal stops the batch after the current target; restoring
+		// default handling right away lets a second one kill the process.
+		signalCtx, stop := r.ctx.deps.SignalContext()
+		defer stop()
+		context.AfterFunc(signalCtx, stop)
+		err = spec.execute(service.WithInterrupt(signalCtx.Done()).WithProgress(batch.done), targets)
+		if warnErr := r.parsed.warnings.warnAll(warningCoerced, coerced); warnErr != nil && err == nil {
+			err = outputError(warnErr)
+		}
+		if errors.Is(err, secretsync.ErrInterrupted) {
+			return interruptedError(err)
+		}
+		if err == nil {
+			batch.finish()
+		}
+		return err
 	})
 }
 
-func loadAndOpenAPI(configPath, profileOverride string, deps Dependencies) (*config.Loaded, secretprovider.SecretAPI, error) {
+// readOnlyGuidance tells the user which credentials to replace when a write
+// was refused as read-only.
+func readOnlyGuidance(err error, loaded *config.Loaded, profileOverride string) error {
+	var readOnly *secretsync.ReadOnlyCredentialsError
+	if !errors.As(err, &readOnly) {
+		return err
+	}
+	if readOnly.Profile != "" {
+		return runtimeError(i18n.Errorf(i18n.MsgReadOnlyMappingProfile, err, readOnly.Profile))
+	}
+	profile := strings.TrimSpace(profileOverride)
+	if profile == "" {
+		profile = strings.TrimSpace(loaded.Cfg.Profile)
+	}
+	if profile == "" {
+		return runtimeError(i18n.Errorf(i18n.MsgReadOnlyEnv, err, loaded.Cfg.ProjectID))
+	}
+	return runtimeError(i18n.Errorf(i18n.MsgReadOnlyProfile, err, profile, loaded.Cfg.ProjectID))
+}
+
+// accessGuidance turns a denied read into an onboarding step: who grants
+// access to the project's secrets and where to request it, from the
+// manifest's access_contact.
+func accessGuidance(err error, contact *config.AccessContact) error {
+	var readOnly *secretsync.ReadOnlyCredentialsError
+	if contact == nil || !errors.Is(err, secretprovider.ErrPermissionDenied) || errors.As(err, &readOnly) {
+		return err
+	}
+	// The optional parts are empty strings when the contact lacks them.
+	var ask, request any = "", ""
+	switch {
+	case contact.Owner != "" && contact.Contact != "":
+		ask = i18n.Errorf(i18n.MsgAccessAskOwnerContact, contact.Owner, contact.Contact)
+	case contact.Owner != "" || contact.Contact != "":
+		ask = i18n.Errorf(i18n.MsgAccessAsk, contact.Owner+contact.Contact)
+	}
+	if contact.RequestURL != "" {
+		request = i18n.Errorf(i18n.MsgAccessRequestAt, contact.RequestURL)
+	}
+	return runtimeError(i18n.Errorf(i18n.MsgAccessHint, err, ask, request))
+}
+
+func loadConfig(configPath string, opts config.LoadOptions, deps Dependencies) (*config.Loaded, error) {
 	wd, err := deps.Getwd()
 	if err != nil {
-		return nil, nil, fmt.Errorf("getwd: %w", err)
+		return nil, i18n.Errorf(i18n.MsgGetwd, err)
 	}
-	loaded, err := config.Load(wd, configPath)
+	loaded, err := config.LoadWithOptions(wd, configPath, opts)
 	if err != nil {
-		return nil, nil, fmt.Errorf("load config: %w", err)
+		return nil, i18n.Errorf(i18n.MsgLoadConfig, unknownFieldsGuidance(err, deps.Version))
 	}
-	api, err := deps.OpenSecretAPI(loaded.Cfg, profileOverride)
+	return loaded, nil
+}
+
+func loadAndOpenAPI(configPath, profileOverride string, opts config.LoadOptions, deps Dependencies) (*config.Loaded, secretprovider.SecretAPI, error) {
+	loaded, err := loadConfig(configPath, opts, deps)
 	if err != nil {
-		return nil, nil, fmt.Errorf("open secret api: %w", err)
+		return nil, nil, err
+	}
+	api, err := openSecretAPI(loaded.Cfg, profileOverride, deps)
+	if err != nil {
+		return nil, nil, err
 	}
 	return loaded, api, nil
 }
+
+func openSecretAPI(cfg config.Config, profileOverride string, deps Dependencies) (secretprovider.SecretAPI, error) {
+	if profiles := splitProfiles(profileOverride); len(profiles) > 1 {
+		return openMultiProfileAPI(cfg, profiles, deps)
+	}
+	api, err := deps.OpenSecretAPI(cfg, profileOverride)
+	if err != nil {
+		return nil, i18n.Errorf(i18n.MsgOpenSecretAPI, err)
+	}
+	return api, nil
+}
+
+// splitProfiles parses --profile, which takes a comma-separated list to run
+// against several profiles at once.
+func splitProfiles(value string) []string {
+	var out []string
+	seen := make(map[string]bool)
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(name)
+		if name != "" && !seen[name] {
+			seen[name] = true
+			out = append(out, name)
+		}
+	}
+	return out
+}
+
+// openMultiProfileAPI opens one provider client per profile. The first
+// profile uses the manifest's organization and project like a single
+// --profile would; the others use the defaults of their own profile.
+func openMultiProfileAPI(cfg config.Config, profiles []string, deps Dependencies) (secretprovider.SecretAPI, error) {
+	apis := make([]secretprovider.ProfileAPI, 0, len(profiles))
+	for i, profile := range profiles {
+		profileCfg := cfg
+		if i > 0 {
+			profileCfg = ownProjectConfig(cfg)
+		}
+		api, err := deps.OpenSecretAPI(profileCfg, profile)
+		if err != nil {
+			return nil, i18n.Errorf(i18n.MsgOpenSecretAPIProfile, profile, err)
+		}
+		apis = append(apis, secretprovider.ProfileAPI{Profile: profile, API: api})
+	}
+	return secretprovider.NewMultiProfileAPI(apis), nil
+}
+
+// ownProjectConfig is cfg for a profile other than the manifest's, which
+// usually belongs to another organization: the provider falls back to the
+// profile's own default organization and project.
+func ownProjectConfig(cfg config.Config) config.Config {
+	cfg.OrganizationID, cfg.ProjectID = "", ""
+	return cfg
+}
+
+// requireSingleProfile rejects a --profile list for commands that talk to a
+// single account.
+func requireSingleProfile(profileOverride, what string) error {
+	if len(splitProfiles(profileOverride)) > 1 {
+		return usageError(i18n.Errorf(i18n.MsgSingleProfile, what))
+	}
+	return nil
+}
@@ -0,0 +1,20 @@
+package cli
+
+import (
+	"path/filepath"
+
+	"github.com/bsmartlabs/dev-vault/internal/i18n"
+)
+
+const stateDirName = "dev-vault"
+
+// stateDir is the per-user directory for local-only state such as telemetry
+// consent, crash logs, and the user config. It lives under the OS user config
+// directory.
+func stateDir(deps Dependencies) (string, error) {
+	dir, err := deps.UserConfigDir()
+	if err != nil {
+		return "", i18n.Errorf(i18n.MsgLocateConfigDir, err)
+	}
+	return filepath.Join(dir, stateDirName), nil
+}
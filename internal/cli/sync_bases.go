@@ -0,0 +1,23 @@
+package cli
+
+import (
+	"github.com/bsmartlabs/dev-vault/internal/config"
+	"github.com/bsmartlabs/dev-vault/internal/secretsync"
+	"github.com/bsmartlabs/dev-vault/internal/syncbase"
+)
+
+// openSyncBases returns the record of the revisions the files of loaded's
+// mappings were last pulled or pushed at, which sync compares both sides
+// with. Without a state directory or a readable record, the revisions live
+// only in the files' user.dev-vault attributes.
+func openSyncBases(deps Dependencies, loaded *config.Loaded) secretsync.BaseRevisions {
+	dir, err := stateDir(deps)
+	if err != nil {
+		return nil
+	}
+	tracker, err := syncbase.NewStore(dir).Open(loaded.Path)
+	if err != nil {
+		return nil
+	}
+	return tracker
+}
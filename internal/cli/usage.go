@@ -6,6 +6,7 @@ import (
 	"sort"
 
 	"github.com/bsmartlabs/dev-vault/internal/config"
+	"github.com/bsmartlabs/dev-vault/internal/i18n"
 )
 
 type usageWriter struct {
@@ -27,79 +28,124 @@ func (u *usageWriter) f(format string, a ...any) {
 	_, u.err = fmt.Fprintf(u.w, format, a...)
 }
 
-func printMainUsage(w io.Writer) error {
+func printMainUsage(w io.Writer, msg i18n.Localizer) error {
 	out := usageWriter{w: w}
 	out.line("dev-vault")
-	out.line("  Pull/push Scaleway Secret Manager secrets to disk for local development.")
+	out.line("  " + msg.Text(i18n.MsgMainTagline))
 	out.line()
-	out.line("Usage:")
+	out.line(msg.Text(i18n.MsgHeadingUsage))
 	out.line("  dev-vault [global options] <command> [command options] [args...]")
-	out.line("  dev-vault help [command]")
+	out.line("  dev-vault help [command | --all | search <term>...]")
 	out.line()
-	out.line("Global options:")
-	out.f("  --config <path>   Path to %s. If omitted: search upward from cwd.\n", config.DefaultConfigName)
-	out.line("  --profile <name>  Scaleway profile override (uses ~/.config/scw/config.yaml)")
+	out.line(msg.Text(i18n.MsgHeadingGlobalOptions))
+	for _, line := range globalOptionLines(msg) {
+		out.line("  " + line)
+	}
 	out.line()
-	out.line("Commands:")
-	for _, def := range commandDefs {
-		out.f("  %-8s %s\n", def.Name, def.Summary)
+	out.line(msg.Text(i18n.MsgHeadingCommands))
+	// Names pad to the longest listed one, so every summary lines up.
+	defs := listedCommandDefs()
+	width := 0
+	for _, def := range defs {
+		width = max(width, len(def.Name))
+	}
+	for _, def := range defs {
+		out.f("  %-*s %s\n", width, def.Name, commandSummary(msg, def))
 	}
 	out.line()
-	out.line("Hard safety constraints:")
-	out.line("  - Refuses to operate on secret names that do not end with '-dev'.")
-	out.line("  - Never prints secret payloads.")
-	out.line("  - Pull writes files atomically and chmods them to 0600 (on Unix).")
+	out.line(msg.Text(i18n.MsgHeadingSafety))
+	out.line("  - " + msg.Text(i18n.MsgSafetyDevSuffix))
+	out.line("  - " + msg.Text(i18n.MsgSafetyNoPayloads))
+	out.line("  - " + msg.Text(i18n.MsgSafetyAtomicWrite))
 	out.line()
-	out.line("Batch behavior:")
-	out.line("  - mapping.mode defaults to both.")
-	out.line("  - pull --all includes mapping entries with mapping.mode in {pull, both}.")
-	out.line("  - push --all includes mapping entries with mapping.mode in {push, both}.")
-	out.f("  - %s\n", explicitModePolicySentence)
-	out.line("  - Note: mapping.mode='sync' is accepted as a legacy alias for 'both'.")
+	out.line(msg.Text(i18n.MsgHeadingBatch))
+	out.line("  - " + msg.Text(i18n.MsgBatchModeDefault))
+	out.line("  - " + msg.Text(i18n.MsgBatchPullAll))
+	out.line("  - " + msg.Text(i18n.MsgBatchPushAll))
+	out.line("  - " + msg.Text(i18n.MsgBatchExplicitMode))
+	out.line("  - " + msg.Text(i18n.MsgBatchLegacySync))
 	out.line()
-	out.line("Examples:")
+	out.line(msg.Text(i18n.MsgHeadingExamples))
 	out.line("  dev-vault list --json")
 	out.line("  dev-vault pull bweb-env-bsmart-dev --overwrite")
 	out.line("  dev-vault push bweb-env-bsmart-dev")
 	out.line("  dev-vault pull --config .scw.json bweb-env-bsmart-dev --overwrite")
 	out.line()
-	out.line("Notes for automation/LLMs:")
-	out.line("  - Global options can be passed either before the command or as command options (e.g. 'pull --config ...').")
-	out.line("  - Exit codes: 0=success, 1=runtime error, 2=usage error.")
+	out.line(msg.Text(i18n.MsgHeadingAutomation))
+	out.line("  - " + msg.Text(i18n.MsgAutomationGlobalPlacement))
+	out.line("  - " + msg.Text(i18n.MsgAutomationExitCodes))
 	return out.err
 }
 
-func printCommandUsage(w io.Writer, def commandDef) error {
+func globalOptionLines(msg i18n.Localizer) []string {
+	return []string{
+		"--config <path>    " + msg.Sprintf(i18n.MsgGlobalConfigHelp, config.DefaultConfigName),
+		"--profile <name>   " + msg.Text(i18n.MsgGlobalProfileHelp),
+		"--env <name>       " + msg.Text(i18n.MsgGlobalEnvHelp),
+		"--lang <lang>      " + msg.Text(i18n.MsgGlobalLangHelp),
+		"--plain            " + msg.Text(i18n.MsgGlobalPlainHelp),
+		"--explain-config   " + msg.Text(i18n.MsgGlobalExplainConfigHelp),
+		"--enforce-version  " + msg.Text(i18n.MsgGlobalEnforceVersionHelp),
+		"--ignore-unknown   " + msg.Text(i18n.MsgGlobalIgnoreUnknownHelp),
+		"--strict-warnings  " + msg.Text(i18n.MsgGlobalStrictWarningsHelp),
+		"--log-file <path>  " + msg.Text(i18n.MsgGlobalLogFileHelp),
+		"--no-fsync         " + msg.Text(i18n.MsgGlobalNoFsyncHelp),
+	}
+}
+
+func commandSummary(msg i18n.Localizer, def commandDef) string {
+	return msg.TextOr(i18n.CommandSummaryID(def.Name), def.Summary)
+}
+
+func commandDescription(msg i18n.Localizer, def commandDef) []string {
+	return msg.LinesOr(i18n.CommandDescriptionID(def.Name), def.Doc.Description)
+}
+
+func commandNotes(msg i18n.Localizer, def commandDef) []string {
+	return msg.LinesOr(i18n.CommandNotesID(def.Name), def.Doc.Notes)
+}
+
+// commandFlagDefs returns the command's flags sorted by name, with their help
+// in the language of msg.
+func commandFlagDefs(msg i18n.Localizer, def commandDef) []commandFlagDef {
+	flags := sortedFlagDefs(def.Flags)
+	for i := range flags {
+		flags[i].Help = msg.TextOr(i18n.CommandFlagHelpID(def.Name, flags[i].Name), flags[i].Help)
+	}
+	return flags
+}
+
+func printCommandUsage(w io.Writer, msg i18n.Localizer, def commandDef) error {
 	out := usageWriter{w: w}
-	out.line("Usage:")
+	out.line(msg.Text(i18n.MsgHeadingUsage))
 	out.f("  %s\n", def.Doc.Synopsis)
 
-	if len(def.Doc.Description) > 0 {
+	if description := commandDescription(msg, def); len(description) > 0 {
 		out.line()
-		for _, line := range def.Doc.Description {
+		for _, line := range description {
 			out.line(line)
 		}
 	}
 
 	if len(def.Flags) > 0 {
 		out.line()
-		out.line("Options:")
-		for _, flagDef := range sortedFlagDefs(def.Flags) {
+		out.line(msg.Text(i18n.MsgHeadingOptions))
+		for _, flagDef := range commandFlagDefs(msg, def) {
 			out.f("  --%s\n", formatFlagUsage(flagDef))
 		}
 	}
 
-	if len(def.Doc.Notes) > 0 {
+	if notes := commandNotes(msg, def); len(notes) > 0 {
 		out.line()
-		out.line("Notes:")
-		for _, note := range def.Doc.Notes {
+		out.line(msg.Text(i18n.MsgHeadingNotes))
+		for _, note := range notes {
 			out.line("  - " + note)
 		}
 	}
 
 	if len(def.Doc.Examples) > 0 {
 		out.line()
-		out.line("Examples:")
+		out.line(msg.Text(i18n.MsgHeadingExamples))
 		for _, example := range def.Doc.Examples {
 			out.f("  %s\n", example)
 		}
@@ -129,17 +175,21 @@ func formatFlagUsage(flagDef commandFlagDef) string {
 }
 
 func printVersionUsage(w io.Writer) error {
-	return printCommandUsage(w, versionCommandDef)
+	return printCommandUsage(w, i18n.Localizer{}, versionCommandDef)
 }
 
 func printListUsage(w io.Writer) error {
-	return printCommandUsage(w, listCommandDef)
+	return printCommandUsage(w, i18n.Localizer{}, listCommandDef)
 }
 
 func printPullUsage(w io.Writer) error {
-	return printCommandUsage(w, pullCommandDef)
+	return printCommandUsage(w, i18n.Localizer{}, pullCommandDef)
 }
 
 func printPushUsage(w io.Writer) error {
-	return printCommandUsage(w, pushCommandDef)
+	return printCommandUsage(w, i18n.Localizer{}, pushCommandDef)
+}
+
+func printPromptUsage(w io.Writer) error {
+	return printCommandUsage(w, i18n.Localizer{}, promptCommandDef)
 }
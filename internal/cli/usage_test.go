@@ -5,6 +5,8 @@ import (
 	"io"
 	"strings"
 	"testing"
+
+	"github.com/bsmartlabs/dev-vault/internal/i18n"
 )
 
 func TestUsageFunctions_BasicSmoke(t *testing.T) {
@@ -13,11 +15,12 @@ func TestUsageFunctions_BasicSmoke(t *testing.T) {
 		fn       func(io.Writer) error
 		contains string
 	}{
-		{name: "main", fn: printMainUsage, contains: "dev-vault"},
+		{name: "main", fn: func(w io.Writer) error { return printMainUsage(w, i18n.Localizer{}) }, contains: "dev-vault"},
 		{name: "version", fn: printVersionUsage, contains: "version"},
 		{name: "list", fn: printListUsage, contains: "list [options]"},
 		{name: "pull", fn: printPullUsage, contains: "pull (--all | <secret-dev> ...)"},
 		{name: "push", fn: printPushUsage, contains: "push (--all | <secret-dev> ...)"},
+		{name: "prompt", fn: printPromptUsage, contains: "prompt [--json]"},
 	}
 
 	for _, tc := range tests {
@@ -39,7 +42,7 @@ func TestUsageFunctions_BasicSmoke(t *testing.T) {
 
 func TestPrintMainUsage_ExplicitNamesMustRespectMode(t *testing.T) {
 	var buf bytes.Buffer
-	if err := printMainUsage(&buf); err != nil {
+	if err := printMainUsage(&buf, i18n.Localizer{}); err != nil {
 		t.Fatalf("printMainUsage: %v", err)
 	}
 	out := buf.String()
@@ -48,6 +51,26 @@ func TestPrintMainUsage_ExplicitNamesMustRespectMode(t *testing.T) {
 	}
 }
 
+func TestPrintMainUsage_CommandSummariesAlign(t *testing.T) {
+	var buf bytes.Buffer
+	if err := printMainUsage(&buf, i18n.Localizer{}); err != nil {
+		t.Fatalf("printMainUsage: %v", err)
+	}
+	section := strings.SplitN(strings.SplitN(buf.String(), "Commands:\n", 2)[1], "\n\n", 2)[0]
+	column := -1
+	for _, line := range strings.Split(section, "\n") {
+		name := strings.Fields(line)[0]
+		start := strings.Index(line, name) + len(name)
+		summary := start + len(line[start:]) - len(strings.TrimLeft(line[start:], " "))
+		if column == -1 {
+			column = summary
+		}
+		if summary != column || summary-start < 1 {
+			t.Fatalf("summary of %s starts at column %d, want %d:\n%s", name, summary, column, section)
+		}
+	}
+}
+
 func TestUsageWriter_ShortCircuitOnError(t *testing.T) {
 	w := &usageWriter{w: &failingWriter{}}
 	w.line("first")
@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/bsmartlabs/dev-vault/internal/config"
+	"github.com/bsmartlabs/dev-vault/internal/i18n"
+)
+
+// Warning kinds, the "kind" field of a --json warning.
+const (
+	warningConfig          = "config"
+	warningRequiredVersion = "required_version"
+	warningPolicy          = "policy"
+	warningCoerced         = "coerced"
+	warningCertExpiry      = "certificate_expiry"
+	warningBudget          = "budget"
+	warningState           = "state"
+	warningWatch           = "watch"
+	warningExpiry          = "expiry"
+)
+
+// warningRecord is one warning as printed with --json.
+type warningRecord struct {
+	Warning string `json:"warning"`
+	Kind    string `json:"kind"`
+}
+
+// warningSink reports the warnings of one command. They go to stderr as
+// localized "warning: ..." lines, or with --json as one JSON object per line,
+// so stdout stays a single document. With --strict-warnings any warning fails
+// the command.
+type warningSink struct {
+	w      io.Writer
+	msg    i18n.Localizer
+	json   bool
+	strict bool
+	count  int
+}
+
+// warnError reports a warning built from catalog messages: localized on
+// stderr, in English with --json.
+func (s *warningSink) warnError(kind string, message error) error {
+	return s.report(kind, message.Error(), s.msg.Error(message))
+}
+
+func (s *warningSink) report(kind, english, localized string) error {
+	s.count++
+	if s.json {
+		line, _ := json.Marshal(warningRecord{Warning: english, Kind: kind}) // strings always encode
+		_, err := fmt.Fprintln(s.w, string(line))
+		return err
+	}
+	_, err := fmt.Fprintln(s.w, s.msg.Sprintf(i18n.MsgWarning, localized))
+	return err
+}
+
+func (s *warningSink) warnAll(kind string, messages []error) error {
+	for _, message := range messages {
+		if err := s.warnError(kind, message); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// configWarnings turns the warnings config.Load tolerated into catalog
+// messages.
+func configWarnings(warnings []error) []error {
+	messages := make([]error, len(warnings))
+	for i, warning := range warnings {
+		switch w := warning.(type) {
+		case *config.IgnoredFieldWarning:
+			messages[i] = i18n.Errorf(i18n.MsgWarnIgnoredField, w.Field, w.Path)
+		case *config.LegacyModeWarning:
+			messages[i] = i18n.Errorf(i18n.MsgWarnLegacySyncMode, w.Mapping)
+		default:
+			messages[i] = warning
+		}
+	}
+	return messages
+}
+
+// strictError fails the command under --strict-warnings once any warning was
+// reported.
+func (s *warningSink) strictError() error {
+	if !s.strict || s.count == 0 {
+		return nil
+	}
+	return runtimeError(i18n.Errorf(i18n.MsgStrictWarnings, s.count))
+}
@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bsmartlabs/dev-vault/internal/config"
+	"github.com/bsmartlabs/dev-vault/internal/i18n"
+	secret "github.com/scaleway/scaleway-sdk-go/api/secret/v1beta1"
+)
+
+func TestRun_Warnings(t *testing.T) {
+	root := t.TempDir()
+	cfgPath := writeConfig(t, root, `{"organization_id":"org","project_id":"proj","region":"fr-par","mapping":{
+		"app-env-dev":{"file":".env","format":"dotenv","type":"key_value"},
+		"old-dev":{"file":"old.bin","mode":"sync"}
+	}}`)
+	api := newFakeSecretAPI()
+	sec := api.AddSecret("proj", "app-env-dev", "/", secret.SecretTypeOpaque)
+	api.AddEnabledVersion(sec.ID, []byte(`{"A":"1"}`))
+	deps := baseDeps(func(config.Config, string) (SecretAPI, error) { return api, nil })
+	run := func(args ...string) (int, string, string) {
+		var out, errBuf bytes.Buffer
+		code := Run(append([]string{"dev-vault", "--config", cfgPath}, args...), &out, &errBuf, deps)
+		return code, out.String(), errBuf.String()
+	}
+
+	// --json reports each warning as a JSON line on stderr.
+	code, out, errOut := run("list", "--json")
+	var record warningRecord
+	if err := json.Unmarshal([]byte(errOut), &record); code != 0 || err != nil || record.Kind != warningConfig || !strings.Contains(record.Warning, `mapping "old-dev" uses legacy mode=sync`) || !json.Valid([]byte(out)) {
+		t.Fatalf("unexpected json warning: %d %q %q", code, out, errOut)
+	}
+
+	// Manifest warnings stop a strict run before the command starts.
+	code, out, errOut = run("--strict-warnings", "list")
+	if code != 1 || out != "" || !strings.HasSuffix(errOut, "1 warning(s) treated as errors (--strict-warnings)\ninvocation: TESTINVOCATION\n") {
+		t.Fatalf("unexpected strict list: %d %q %q", code, out, errOut)
+	}
+
+	// Later warnings fail the command once it has finished.
+	cfgPath = writeConfig(t, root, `{"organization_id":"org","project_id":"proj","region":"fr-par","mapping":{
+		"app-env-dev":{"file":".env","format":"dotenv","type":"key_value"}
+	}}`)
+	code, out, errOut = run("pull", "--coerce", "app-env-dev", "--strict-warnings")
+	if code != 1 || !strings.Contains(out, "pulled app-env-dev") || errOut != "warning: coerced app-env-dev: secret is opaque, mapping.type is key_value\n1 warning(s) treated as errors (--strict-warnings)\ninvocation: TESTINVOCATION\n" {
+		t.Fatalf("unexpected strict pull: %d %q %q", code, out, errOut)
+	}
+	if _, err := os.Stat(filepath.Join(root, ".env")); err != nil {
+		t.Fatalf("expected the pull to complete: %v", err)
+	}
+}
+
+func TestWarningSink_WriteFailureStops(t *testing.T) {
+	for _, sink := range []*warningSink{
+		{w: &failingWriter{}, msg: i18n.Localizer{}},
+		{w: &failingWriter{}, msg: i18n.Localizer{}, json: true},
+	} {
+		if err := sink.warnAll(warningConfig, []error{errors.New("one"), errors.New("two")}); err == nil || sink.count != 1 {
+			t.Fatalf("expected warning write error after one warning, got %v (%d)", err, sink.count)
+		}
+	}
+}
+
+func TestConfigWarnings(t *testing.T) {
+	other := errors.New("other")
+	got := configWarnings([]error{
+		&config.IgnoredFieldWarning{Field: "hooks", Path: "/repo/.scw.json"},
+		&config.LegacyModeWarning{Mapping: "old-dev"},
+		other,
+	})
+	fr := i18n.New(i18n.French)
+	if len(got) != 3 || fr.Error(got[0]) != `champ inconnu "hooks" ignoré dans /repo/.scw.json` || got[0].Error() != `ignoring unknown field "hooks" in /repo/.scw.json` ||
+		!strings.HasPrefix(fr.Error(got[1]), `le mapping "old-dev" utilise l'ancien mode=sync ;`) || got[2] != other {
+		t.Fatalf("unexpected warnings: %v", got)
+	}
+}
@@ -0,0 +1,32 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// AccessContact tells a new contributor who grants access to the project's
+// secrets, printed when the provider denies a read.
+type AccessContact struct {
+	Owner      string `json:"owner,omitempty"`       // team or person who grants access
+	Contact    string `json:"contact,omitempty"`     // how to reach them, e.g. an email or chat channel
+	RequestURL string `json:"request_url,omitempty"` // page to request access on
+}
+
+func (a *AccessContact) normalizeAndValidate() error {
+	a.Owner = strings.TrimSpace(a.Owner)
+	a.Contact = strings.TrimSpace(a.Contact)
+	a.RequestURL = strings.TrimSpace(a.RequestURL)
+	if a.Owner == "" && a.Contact == "" && a.RequestURL == "" {
+		return errors.New("set at least one of owner, contact, request_url")
+	}
+	if a.RequestURL != "" {
+		u, err := url.Parse(a.RequestURL)
+		if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			return fmt.Errorf("request_url must be an http(s) URL, got %q", a.RequestURL)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseAge extends time.ParseDuration with a whole-day unit ("14d"), which is
+// how file freshness and secret age are usually expressed.
+func ParseAge(value string) (time.Duration, error) {
+	var age time.Duration
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, fmt.Errorf("invalid age %q", value)
+		}
+		age = time.Duration(n) * 24 * time.Hour
+	} else {
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			return 0, fmt.Errorf("invalid age %q", value)
+		}
+		age = parsed
+	}
+	if age <= 0 {
+		return 0, fmt.Errorf("age must be positive, got %q", value)
+	}
+	return age, nil
+}
@@ -0,0 +1,47 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	DefaultMaxAPICalls = 100
+	DefaultMaxDuration = time.Minute
+)
+
+// Budget bounds the cost of a single command. A command over budget still
+// completes, then warns with a hint toward a cheaper invocation.
+type Budget struct {
+	MaxAPICalls int    `json:"max_api_calls,omitempty"` // provider calls per command (default 100)
+	MaxDuration string `json:"max_duration,omitempty"`  // Go duration, e.g. "20s" (default 1m)
+}
+
+func (b *Budget) normalizeAndValidate() error {
+	if b.MaxAPICalls < 0 {
+		return fmt.Errorf("max_api_calls must not be negative, got %d", b.MaxAPICalls)
+	}
+	if b.MaxDuration != "" {
+		duration, err := time.ParseDuration(b.MaxDuration)
+		if err != nil || duration <= 0 {
+			return fmt.Errorf("max_duration must be a positive duration such as \"20s\", got %q", b.MaxDuration)
+		}
+	}
+	return nil
+}
+
+// Limits returns the budget with defaults for unset fields; a nil budget is
+// all defaults.
+func (b *Budget) Limits() (int, time.Duration) {
+	calls, duration := DefaultMaxAPICalls, DefaultMaxDuration
+	if b == nil {
+		return calls, duration
+	}
+	if b.MaxAPICalls > 0 {
+		calls = b.MaxAPICalls
+	}
+	if b.MaxDuration != "" {
+		duration, _ = time.ParseDuration(b.MaxDuration) // validated on load
+	}
+	return calls, duration
+}
@@ -0,0 +1,44 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// checkComposeCycles rejects compose references that lead back to the entry
+// they start from, reporting the first cycle in sorted name order.
+func checkComposeCycles(mapping map[string]MappingEntry) error {
+	names := make([]string, 0, len(mapping))
+	for name := range mapping {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	done := make(map[string]bool, len(mapping))
+	var visit func(path []string) error
+	visit = func(path []string) error {
+		name := path[len(path)-1]
+		for i, seen := range path[:len(path)-1] {
+			if seen == name {
+				return fmt.Errorf("mapping %q: compose cycle: %s", name, strings.Join(path[i:], " -> "))
+			}
+		}
+		if done[name] {
+			return nil
+		}
+		for _, ref := range mapping[name].Compose {
+			if err := visit(append(path, ref)); err != nil {
+				return err
+			}
+		}
+		done[name] = true
+		return nil
+	}
+	for _, name := range names {
+		if err := visit([]string{name}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
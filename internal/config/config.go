@@ -7,35 +7,51 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path"
 	"path/filepath"
+	"regexp"
 	"strings"
 
+	"github.com/bsmartlabs/dev-vault/internal/fsx"
 	"github.com/bsmartlabs/dev-vault/internal/secrettype"
 )
 
 const DefaultConfigName = ".scw.json"
 
+var (
+	keyAffixPattern = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+	varNamePattern  = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+)
+
 var (
 	defaultConfigDeps = configDeps{
 		abs:      filepath.Abs,
 		rel:      filepath.Rel,
 		statFile: os.Stat,
 		readFile: os.ReadFile,
+		writeFile: func(path string, data []byte, perm os.FileMode) error {
+			return fsx.AtomicWriteFile(path, data, perm, true)
+		},
 	}
 )
 
 type configDeps struct {
-	abs      func(string) (string, error)
-	rel      func(string, string) (string, error)
-	statFile func(string) (os.FileInfo, error)
-	readFile func(string) ([]byte, error)
+	abs       func(string) (string, error)
+	rel       func(string, string) (string, error)
+	statFile  func(string) (os.FileInfo, error)
+	readFile  func(string) ([]byte, error)
+	writeFile func(path string, data []byte, perm os.FileMode) error
 }
 
 type MappingFormat string
 
 const (
-	MappingFormatRaw    MappingFormat = "raw"
-	MappingFormatDotenv MappingFormat = "dotenv"
+	MappingFormatRaw      MappingFormat = "raw"
+	MappingFormatDotenv   MappingFormat = "dotenv"
+	MappingFormatYAML     MappingFormat = "yaml"
+	MappingFormatJSON     MappingFormat = "json"
+	MappingFormatTOML     MappingFormat = "toml"
+	MappingFormatTemplate MappingFormat = "template"
 )
 
 type MappingMode string
@@ -56,26 +72,137 @@ func (m MappingMode) AllowsPush() bool {
 }
 
 type MappingEntry struct {
-	File   string        `json:"file"`
-	Format MappingFormat `json:"format,omitempty"` // raw|dotenv
-	Path   string        `json:"path,omitempty"`   // default "/"
-	Mode   MappingMode   `json:"mode,omitempty"`   // pull|push|both (default: both). "sync" is accepted as legacy alias for "both".
-	Type   string        `json:"type,omitempty"`   // expected secret type
+	File     string        `json:"file,omitempty"`
+	Format   MappingFormat `json:"format,omitempty"`   // raw|dotenv|yaml|json|toml|template
+	Path     string        `json:"path,omitempty"`     // default "/"
+	Mode     MappingMode   `json:"mode,omitempty"`     // pull|push|both (default: both). "sync" is accepted as legacy alias for "both".
+	Type     string        `json:"type,omitempty"`     // expected secret type
+	Disabled bool          `json:"disabled,omitempty"` // excluded from --all and drift checks
+	Compose  []string      `json:"compose,omitempty"`  // mapped secrets layered under this one on pull
+
+	KeyPrefix string `json:"key_prefix,omitempty"` // added to every key on pull, stripped on push
+	KeySuffix string `json:"key_suffix,omitempty"` // added to every key on pull, stripped on push
+
+	Keys        *KeyFilter `json:"keys,omitempty"`         // subset of secret keys this mapping reads and writes
+	ExcludeKeys []string   `json:"exclude_keys,omitempty"` // secret keys this mapping leaves alone; added to keys.exclude
+
+	FakeKeys []string `json:"fake_keys,omitempty"` // secret keys pull --fake writes placeholders for
+
+	Substitute bool `json:"substitute,omitempty"` // expand ${NAME} placeholders in values on pull
+	Provenance bool `json:"provenance,omitempty"` // comment each dotenv key with the secret and revision it came from
+
+	PreserveLayout bool `json:"preserve_layout,omitempty"` // overwrite a dotenv file by updating its changed values in place
+
+	TemplateFile string `json:"template_file,omitempty"` // Go text/template rendered with the secret's keys (format template)
+
+	Files map[string]string `json:"files,omitempty"` // secret key -> file holding its value, instead of file (key_value secrets)
+
+	Revision uint32 `json:"revision,omitempty"` // pin reads to this secret revision (default: latest enabled)
+
+	Profile string `json:"profile,omitempty"` // credentials profile holding this secret (default: the manifest's)
+}
+
+// KeyFilter selects secret keys by glob (path.Match syntax). An empty include
+// list selects every key; exclude wins over include.
+type KeyFilter struct {
+	Include []string `json:"include,omitempty"`
+	Exclude []string `json:"exclude,omitempty"`
+}
+
+// UnmarshalJSON also accepts a list of patterns, short for an include list.
+func (f *KeyFilter) UnmarshalJSON(data []byte) error {
+	var include []string
+	if err := json.Unmarshal(data, &include); err == nil {
+		*f = KeyFilter{Include: include}
+		return nil
+	}
+	// The manifest decoder's strictness does not reach custom decoders.
+	type plain KeyFilter
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	return dec.Decode((*plain)(f))
 }
 
+// Secret providers a manifest can select; ProviderScaleway is the default.
+const (
+	ProviderScaleway = "scaleway"
+	ProviderAWS      = "aws"
+	ProviderVault    = "vault"
+)
+
+// Modification times pull can give the files it writes; MtimeNow is the
+// default.
+const (
+	MtimeNow      = "now"
+	MtimePreserve = "preserve"
+	MtimeRemote   = "remote"
+)
+
 type Config struct {
-	OrganizationID string                  `json:"organization_id"`
-	ProjectID      string                  `json:"project_id"`
+	// Provider selects the secret store: "scaleway" (default), "aws" (AWS
+	// Secrets Manager) or "vault" (HashiCorp Vault KV v2). Only scaleway needs
+	// organization_id and project_id, and vault needs no region either.
+	Provider       string                  `json:"provider,omitempty"`
+	OrganizationID string                  `json:"organization_id,omitempty"`
+	ProjectID      string                  `json:"project_id,omitempty"`
 	Region         string                  `json:"region"`
 	Profile        string                  `json:"profile,omitempty"`
+	Vars           map[string]string       `json:"vars,omitempty"` // {{name}} values in mapping keys and ${NAME} placeholders in substitute mappings
 	Mapping        map[string]MappingEntry `json:"mapping"`
+	Policy         *Policy                 `json:"policy,omitempty"`
+	Naming         *Naming                 `json:"naming,omitempty"`
+	Budget         *Budget                 `json:"budget,omitempty"`
+	AccessContact  *AccessContact          `json:"access_contact,omitempty"`
+	// StateSecret names a key_value -dev secret, outside the mapping, where
+	// push records each secret's revision and push time for the whole team.
+	StateSecret string `json:"state_secret,omitempty"`
+	// RequiredVersion pins the dev-vault releases allowed to use this
+	// manifest, e.g. ">=1.4.0, <2.0.0".
+	RequiredVersion string `json:"required_version,omitempty"`
+	// Mtime is the modification time pull gives the files it writes: "now"
+	// (default), "preserve" to keep the replaced file's, or "remote" for the
+	// creation time of the pulled version, so build tools only see files
+	// change when the payload did.
+	Mtime string `json:"mtime,omitempty"`
+	// ExpireAfter is how long pulled files stay fresh, an age such as "8h";
+	// once it has passed, prompt, list --stale, and pull --watch flag them.
+	// Empty means pulled files never expire.
+	ExpireAfter string `json:"expire_after,omitempty"`
+	// MappingDefaults fills the fields mapping entries leave unset.
+	MappingDefaults *MappingDefaults `json:"mapping_defaults,omitempty"`
+	// Environments are the named targets --env selects, each with its own
+	// project, region, and mapping entries.
+	Environments map[string]Environment `json:"environments,omitempty"`
+}
+
+// ValidateMtime checks a pull mtime strategy.
+func ValidateMtime(value string) error {
+	switch value {
+	case MtimeNow, MtimePreserve, MtimeRemote:
+		return nil
+	default:
+		return fmt.Errorf("invalid mtime %q (expected %s, %s or %s)", value, MtimeNow, MtimePreserve, MtimeRemote)
+	}
 }
 
 type Loaded struct {
-	Path     string
-	Root     string
-	Cfg      Config
-	Warnings []string
+	Path      string
+	LocalPath string // per-user overlay merged into Cfg; empty when absent
+	Root      string
+	Cfg       Config
+	// Warnings are the problems Load tolerated: *IgnoredFieldWarning and
+	// *LegacyModeWarning.
+	Warnings []error
+}
+
+// LegacyModeWarning reports a mapping entry that still uses mode=sync, which
+// Load reads as mode=both.
+type LegacyModeWarning struct {
+	Mapping string
+}
+
+func (w *LegacyModeWarning) Error() string {
+	return fmt.Sprintf("mapping %q uses legacy mode=sync; use mode=both (sync will be removed in a future major release)", w.Mapping)
 }
 
 func IsDevSecretName(name string) bool {
@@ -104,9 +231,18 @@ func findConfigPath(startDir string, deps configDeps) (string, error) {
 	}
 
 	for {
-		candidate := filepath.Join(dir, DefaultConfigName)
-		if info, err := deps.statFile(candidate); err == nil && !info.IsDir() {
-			return candidate, nil
+		var found []string
+		for _, name := range configNames {
+			candidate := filepath.Join(dir, name)
+			if info, err := deps.statFile(candidate); err == nil && !info.IsDir() {
+				found = append(found, candidate)
+			}
+		}
+		if len(found) > 1 {
+			return "", fmt.Errorf("%s and %s are both manifests; keep one", found[0], found[1])
+		}
+		if len(found) == 1 {
+			return found[0], nil
 		}
 
 		parent := filepath.Dir(dir)
@@ -116,16 +252,39 @@ func findConfigPath(startDir string, deps configDeps) (string, error) {
 		dir = parent
 	}
 
-	return "", fmt.Errorf("%s not found from %s upward", DefaultConfigName, startDir)
+	return "", fmt.Errorf("%s not found from %s upward", strings.Join(configNames, " or "), startDir)
 }
 
 func Load(startDir, explicitPath string) (*Loaded, error) {
-	return loadWithDeps(startDir, explicitPath, defaultConfigDeps)
+	return loadWithDeps(startDir, explicitPath, LoadOptions{}, defaultConfigDeps)
+}
+
+// LoadOptions adjusts how LoadWithOptions reads the manifest.
+type LoadOptions struct {
+	// IgnoreUnknown drops the manifest fields this release does not know,
+	// with a warning for each, instead of refusing the manifest. The local
+	// overlay is personal and stays strict.
+	IgnoreUnknown bool
+	// Environment selects one of the manifest's environments; empty keeps the
+	// top-level values.
+	Environment string
 }
 
-func loadWithDeps(startDir, explicitPath string, deps configDeps) (*Loaded, error) {
+// LoadWithOptions is Load with opts applied.
+func LoadWithOptions(startDir, explicitPath string, opts LoadOptions) (*Loaded, error) {
+	return loadWithDeps(startDir, explicitPath, opts, defaultConfigDeps)
+}
+
+// LocatePath returns the absolute manifest path Load would read: explicitPath
+// relative to startDir when set, otherwise the nearest .scw.json, .scw.yaml,
+// or .scw.yml upward.
+func LocatePath(startDir, explicitPath string) (string, error) {
+	return locatePath(startDir, explicitPath, defaultConfigDeps)
+}
+
+func locatePath(startDir, explicitPath string, deps configDeps) (string, error) {
 	if startDir == "" {
-		return nil, errors.New("startDir is empty")
+		return "", errors.New("startDir is empty")
 	}
 
 	var path string
@@ -138,71 +297,146 @@ func loadWithDeps(startDir, explicitPath string, deps configDeps) (*Loaded, erro
 	} else {
 		found, err := findConfigPath(startDir, deps)
 		if err != nil {
-			return nil, err
+			return "", err
 		}
 		path = found
 	}
 
 	absPath, err := deps.abs(path)
 	if err != nil {
-		return nil, fmt.Errorf("abs config path: %w", err)
+		return "", fmt.Errorf("abs config path: %w", err)
+	}
+	return absPath, nil
+}
+
+func loadWithDeps(startDir, explicitPath string, opts LoadOptions, deps configDeps) (*Loaded, error) {
+	absPath, err := locatePath(startDir, explicitPath, deps)
+	if err != nil {
+		return nil, err
 	}
 
 	raw, err := deps.readFile(absPath)
 	if err != nil {
 		return nil, fmt.Errorf("read config: %w", err)
 	}
+	if raw, err = manifestJSON(absPath, raw); err != nil {
+		return nil, err
+	}
 
+	var cfg Config
+	var ignored []string
+	if opts.IgnoreUnknown {
+		cfg, ignored, err = decodeConfigIgnoringUnknown(raw)
+	} else {
+		cfg, err = decodeConfig(raw)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := cfg.selectEnvironment(opts.Environment); err != nil {
+		return nil, err
+	}
+	localPath, _, overlay, err := readLocalOverlay(absPath, deps)
+	if err != nil {
+		return nil, err
+	}
+	cfg.applyOverlay(overlay)
+
+	warnings, err := cfg.normalizeAndValidate()
+	if err != nil {
+		return nil, err
+	}
+	for _, field := range ignored {
+		warnings = append(warnings, &IgnoredFieldWarning{Field: field, Path: absPath})
+	}
+
+	root := filepath.Dir(absPath)
+	if cfg.Policy != nil {
+		cfg.Policy.resolveBundle(root)
+	}
+	return &Loaded{Path: absPath, LocalPath: localPath, Root: root, Cfg: cfg, Warnings: warnings}, nil
+}
+
+// decodeConfig strictly decodes a manifest without normalizing it, so callers
+// that rewrite the file do not persist defaults the user never wrote.
+func decodeConfig(raw []byte) (Config, error) {
 	var cfg Config
 	dec := json.NewDecoder(bytes.NewReader(raw))
 	dec.DisallowUnknownFields()
 	if err := dec.Decode(&cfg); err != nil {
-		return nil, fmt.Errorf("decode config json: %w", err)
+		if stripped, fields, ok := stripUnknownFields(raw); ok {
+			return Config{}, &UnknownFieldsError{Fields: fields, RequiredVersion: strings.TrimSpace(stripped.RequiredVersion)}
+		}
+		return Config{}, fmt.Errorf("decode config json: %w", err)
 	}
 	// Reject trailing JSON tokens after the single top-level config object.
 	var trailing any
 	if err := dec.Decode(&trailing); !errors.Is(err, io.EOF) {
 		if err == nil {
-			return nil, errors.New("decode config json: trailing data after top-level JSON object")
+			return Config{}, errors.New("decode config json: trailing data after top-level JSON object")
 		}
-		return nil, fmt.Errorf("decode config json: trailing data after top-level JSON object: %w", err)
+		return Config{}, fmt.Errorf("decode config json: trailing data after top-level JSON object: %w", err)
 	}
-
-	warnings, err := cfg.normalizeAndValidate()
-	if err != nil {
-		return nil, err
-	}
-
-	root := filepath.Dir(absPath)
-	return &Loaded{Path: absPath, Root: root, Cfg: cfg, Warnings: warnings}, nil
+	return cfg, nil
 }
 
-func (c *Config) normalizeAndValidate() ([]string, error) {
-	warnings := []string{}
+func (c *Config) normalizeAndValidate() ([]error, error) {
+	warnings := []error{}
 
-	if strings.TrimSpace(c.OrganizationID) == "" {
-		return nil, errors.New("missing required field: organization_id")
-	}
-	if strings.TrimSpace(c.ProjectID) == "" {
-		return nil, errors.New("missing required field: project_id")
+	switch c.Provider {
+	case "", ProviderScaleway:
+		if strings.TrimSpace(c.OrganizationID) == "" {
+			return nil, errors.New("missing required field: organization_id")
+		}
+		if strings.TrimSpace(c.ProjectID) == "" {
+			return nil, errors.New("missing required field: project_id")
+		}
+	case ProviderAWS, ProviderVault:
+	default:
+		return nil, fmt.Errorf("invalid provider %q (expected %s, %s or %s)", c.Provider, ProviderScaleway, ProviderAWS, ProviderVault)
 	}
-	if strings.TrimSpace(c.Region) == "" {
+	if strings.TrimSpace(c.Region) == "" && c.Provider != ProviderVault {
 		return nil, errors.New("missing required field: region")
 	}
 	if c.Mapping == nil {
 		return nil, errors.New("missing required field: mapping")
 	}
+	if len(c.Mapping) == 0 && len(c.Environments) > 0 {
+		return nil, errors.New("mapping is empty; select one of the environments with --env")
+	}
 	if len(c.Mapping) == 0 {
 		return nil, errors.New("mapping is empty")
 	}
+	for name := range c.Vars {
+		if !varNamePattern.MatchString(name) {
+			return nil, fmt.Errorf("vars: invalid name %q (expected letters, digits, and underscores)", name)
+		}
+	}
+	defaults := &MappingDefaults{}
+	if c.MappingDefaults != nil {
+		if err := c.MappingDefaults.validate(); err != nil {
+			return nil, err
+		}
+		defaults = c.MappingDefaults
+	}
+	mapping, err := expandNameTemplates(c.Mapping, c.Vars)
+	if err != nil {
+		return nil, err
+	}
+	c.Mapping = mapping
 
 	for name, entry := range c.Mapping {
 		if err := ValidateDevSecretName(name); err != nil {
 			return nil, err
 		}
 
+		defaults.apply(&entry)
 		entry.File = strings.TrimSpace(entry.File)
-		if entry.File == "" {
+		if len(entry.Files) > 0 {
+			if err := normalizeFiles(name, &entry); err != nil {
+				return nil, err
+			}
+		} else if entry.File == "" {
 			return nil, fmt.Errorf("mapping %q: missing required field: file", name)
 		}
 		if filepath.IsAbs(entry.File) {
@@ -213,11 +447,19 @@ func (c *Config) normalizeAndValidate() ([]string, error) {
 			entry.Format = MappingFormatRaw
 		}
 		switch entry.Format {
-		case MappingFormatRaw, MappingFormatDotenv:
+		case MappingFormatRaw, MappingFormatDotenv, MappingFormatYAML, MappingFormatJSON, MappingFormatTOML, MappingFormatTemplate:
 		default:
 			return nil, fmt.Errorf("mapping %q: invalid format %q", name, entry.Format)
 		}
 
+		entry.TemplateFile = strings.TrimSpace(entry.TemplateFile)
+		if (entry.Format == MappingFormatTemplate) != (entry.TemplateFile != "") {
+			return nil, fmt.Errorf("mapping %q: format template requires template_file, and template_file requires format template", name)
+		}
+		if filepath.IsAbs(entry.TemplateFile) {
+			return nil, fmt.Errorf("mapping %q: template_file must be relative, got %q", name, entry.TemplateFile)
+		}
+
 		if entry.Path == "" {
 			entry.Path = "/"
 		}
@@ -227,10 +469,16 @@ func (c *Config) normalizeAndValidate() ([]string, error) {
 
 		if entry.Mode == "" {
 			entry.Mode = MappingModeBoth
+			if defaults.Mode != "" {
+				entry.Mode = defaults.Mode
+			}
+			if len(entry.Compose) > 0 || entry.Substitute || entry.Provenance || entry.Revision != 0 || entry.Format == MappingFormatTemplate {
+				entry.Mode = MappingModePull
+			}
 		}
 		if entry.Mode == MappingModeLegacy {
 			// Back-compat: older manifests used "sync" to mean "both".
-			warnings = append(warnings, fmt.Sprintf("mapping %q uses legacy mode=sync; use mode=both (sync will be removed in a future major release)", name))
+			warnings = append(warnings, &LegacyModeWarning{Mapping: name})
 			entry.Mode = MappingModeBoth
 		}
 		switch entry.Mode {
@@ -239,6 +487,8 @@ func (c *Config) normalizeAndValidate() ([]string, error) {
 			return nil, fmt.Errorf("mapping %q: invalid mode %q", name, entry.Mode)
 		}
 
+		entry.Profile = strings.TrimSpace(entry.Profile)
+
 		entry.Type = strings.TrimSpace(entry.Type)
 		if entry.Type != "" {
 			if !secrettype.IsValid(entry.Type) {
@@ -246,8 +496,143 @@ func (c *Config) normalizeAndValidate() ([]string, error) {
 			}
 		}
 
+		if entry.KeyPrefix != "" || entry.KeySuffix != "" {
+			if entry.Format != MappingFormatDotenv {
+				return nil, fmt.Errorf("mapping %q: key_prefix and key_suffix require format dotenv", name)
+			}
+			for _, affix := range []struct{ field, value string }{{"key_prefix", entry.KeyPrefix}, {"key_suffix", entry.KeySuffix}} {
+				if affix.value != "" && !keyAffixPattern.MatchString(affix.value) {
+					return nil, fmt.Errorf("mapping %q: %s %q must be letters, digits, and underscores", name, affix.field, affix.value)
+				}
+			}
+		}
+
+		if len(entry.ExcludeKeys) > 0 {
+			keys := KeyFilter{}
+			if entry.Keys != nil {
+				keys = *entry.Keys
+			}
+			keys.Exclude = append(append([]string{}, keys.Exclude...), entry.ExcludeKeys...)
+			entry.Keys, entry.ExcludeKeys = &keys, nil
+		}
+		if entry.Keys != nil {
+			switch entry.Format {
+			case MappingFormatDotenv, MappingFormatYAML, MappingFormatTOML, MappingFormatJSON:
+			default:
+				return nil, fmt.Errorf("mapping %q: keys and exclude_keys require format dotenv, yaml, toml, or json", name)
+			}
+			for _, pattern := range append(append([]string{}, entry.Keys.Include...), entry.Keys.Exclude...) {
+				if _, err := path.Match(pattern, ""); err != nil {
+					return nil, fmt.Errorf("mapping %q: keys: invalid pattern %q", name, pattern)
+				}
+			}
+		}
+
+		if len(entry.FakeKeys) > 0 {
+			if entry.Format != MappingFormatDotenv {
+				return nil, fmt.Errorf("mapping %q: fake_keys requires format dotenv", name)
+			}
+			for _, key := range entry.FakeKeys {
+				if !keyAffixPattern.MatchString(key) {
+					return nil, fmt.Errorf("mapping %q: fake_keys: %q must be letters, digits, and underscores", name, key)
+				}
+			}
+		}
+
+		// Pushing a merged or substituted file would copy lower layers or one
+		// machine's values into the shared secret.
+		if (len(entry.Compose) > 0 || entry.Substitute) && entry.Mode != MappingModePull {
+			return nil, fmt.Errorf("mapping %q: mappings with compose or substitute are pull-only, got mode %q", name, entry.Mode)
+		}
+		if entry.Provenance {
+			if entry.Format != MappingFormatDotenv {
+				return nil, fmt.Errorf("mapping %q: provenance requires format dotenv", name)
+			}
+			// The comments carry the revisions pulled; after a push they would
+			// be stale, and sync would push the file again.
+			if entry.Mode != MappingModePull {
+				return nil, fmt.Errorf("mapping %q: provenance mappings are pull-only, got mode %q", name, entry.Mode)
+			}
+		}
+		if entry.PreserveLayout {
+			if entry.Format != MappingFormatDotenv {
+				return nil, fmt.Errorf("mapping %q: preserve_layout requires format dotenv", name)
+			}
+			// The kept comments would name the revisions of an earlier pull.
+			if entry.Provenance {
+				return nil, fmt.Errorf("mapping %q: preserve_layout cannot be combined with provenance", name)
+			}
+		}
+		// A rendered template cannot be parsed back into the secret's keys.
+		if entry.Format == MappingFormatTemplate && entry.Mode != MappingModePull {
+			return nil, fmt.Errorf("mapping %q: template mappings are pull-only, got mode %q", name, entry.Mode)
+		}
+		// A push would create a version the pinned mapping never reads back.
+		if entry.Revision != 0 && entry.Mode != MappingModePull {
+			return nil, fmt.Errorf("mapping %q: mappings pinned to a revision are pull-only, got mode %q", name, entry.Mode)
+		}
+		if len(entry.Compose) > 0 {
+			if entry.Format != MappingFormatDotenv {
+				return nil, fmt.Errorf("mapping %q: compose requires format dotenv", name)
+			}
+			for _, ref := range entry.Compose {
+				if _, ok := c.Mapping[ref]; !ok {
+					return nil, fmt.Errorf("mapping %q: compose reference %q is not mapped", name, ref)
+				}
+			}
+		}
+
 		c.Mapping[name] = entry
 	}
+	if err := checkComposeCycles(c.Mapping); err != nil {
+		return nil, err
+	}
+
+	if c.Policy != nil {
+		if err := c.Policy.normalizeAndValidate(); err != nil {
+			return nil, fmt.Errorf("policy: %w", err)
+		}
+	}
+	if c.Naming != nil {
+		if err := c.Naming.normalizeAndValidate(); err != nil {
+			return nil, fmt.Errorf("naming: %w", err)
+		}
+	}
+	if c.Budget != nil {
+		if err := c.Budget.normalizeAndValidate(); err != nil {
+			return nil, fmt.Errorf("budget: %w", err)
+		}
+	}
+	if c.AccessContact != nil {
+		if err := c.AccessContact.normalizeAndValidate(); err != nil {
+			return nil, fmt.Errorf("access_contact: %w", err)
+		}
+	}
+	c.StateSecret = strings.TrimSpace(c.StateSecret)
+	if c.StateSecret != "" {
+		if !IsDevSecretName(c.StateSecret) {
+			return nil, fmt.Errorf("state_secret %q must end with -dev", c.StateSecret)
+		}
+		if _, ok := c.Mapping[c.StateSecret]; ok {
+			return nil, fmt.Errorf("state_secret %q is also a mapping key; give the state its own secret", c.StateSecret)
+		}
+	}
+	if c.Mtime != "" {
+		if err := ValidateMtime(c.Mtime); err != nil {
+			return nil, err
+		}
+	}
+	if c.ExpireAfter != "" {
+		if _, err := ParseAge(c.ExpireAfter); err != nil {
+			return nil, fmt.Errorf("expire_after: %w", err)
+		}
+	}
+	c.RequiredVersion = strings.TrimSpace(c.RequiredVersion)
+	if c.RequiredVersion != "" {
+		if _, err := ParseVersionRange(c.RequiredVersion); err != nil {
+			return nil, fmt.Errorf("required_version: %w", err)
+		}
+	}
 
 	return warnings, nil
 }
@@ -2,10 +2,13 @@ package config
 
 import (
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestFindConfigPath(t *testing.T) {
@@ -138,6 +141,32 @@ func TestLoad(t *testing.T) {
 		}
 	})
 
+	t.Run("AWSProviderNeedsNoOrganization", func(t *testing.T) {
+		dir := t.TempDir()
+		cfgPath := filepath.Join(dir, DefaultConfigName)
+		if err := os.WriteFile(cfgPath, []byte(`{"provider":"aws","region":"eu-west-3","mapping":{"a-dev":{"file":"x"}}}`), 0o644); err != nil {
+			t.Fatalf("write config: %v", err)
+		}
+		loaded, err := Load(dir, cfgPath)
+		if err != nil {
+			t.Fatalf("load: %v", err)
+		}
+		if loaded.Cfg.Provider != ProviderAWS || loaded.Cfg.OrganizationID != "" {
+			t.Fatalf("unexpected config: %#v", loaded.Cfg)
+		}
+	})
+
+	t.Run("VaultProviderNeedsNoRegion", func(t *testing.T) {
+		dir := t.TempDir()
+		cfgPath := filepath.Join(dir, DefaultConfigName)
+		if err := os.WriteFile(cfgPath, []byte(`{"provider":"vault","mapping":{"a-dev":{"file":"x"}}}`), 0o644); err != nil {
+			t.Fatalf("write config: %v", err)
+		}
+		if loaded, err := Load(dir, cfgPath); err != nil || loaded.Cfg.Provider != ProviderVault {
+			t.Fatalf("unexpected load: %#v %v", loaded, err)
+		}
+	})
+
 	t.Run("ValidationErrors", func(t *testing.T) {
 		cases := []struct {
 			name    string
@@ -156,6 +185,9 @@ func TestLoad(t *testing.T) {
 			{"BadPath", `{"organization_id":"o","project_id":"p","region":"fr-par","mapping":{"a-dev":{"file":"x","path":"nope"}}}`, "path must start"},
 			{"BadMode", `{"organization_id":"o","project_id":"p","region":"fr-par","mapping":{"a-dev":{"file":"x","mode":"nope"}}}`, "invalid mode"},
 			{"BadType", `{"organization_id":"o","project_id":"p","region":"fr-par","mapping":{"a-dev":{"file":"x","type":"nope"}}}`, "invalid type"},
+			{"BadProvider", `{"provider":"gcp","region":"europe-west1","mapping":{"a-dev":{"file":"x"}}}`, `invalid provider "gcp"`},
+			{"AWSMissingRegion", `{"provider":"aws","mapping":{"a-dev":{"file":"x"}}}`, "missing required field: region"},
+			{"ScalewayMissingOrg", `{"provider":"scaleway","project_id":"p","region":"fr-par","mapping":{"a-dev":{"file":"x"}}}`, "organization_id"},
 		}
 		for _, tc := range cases {
 			t.Run(tc.name, func(t *testing.T) {
@@ -205,7 +237,8 @@ func TestLoad(t *testing.T) {
 		if ent.Mode != MappingModeBoth {
 			t.Fatalf("expected mode both, got: %+v", ent)
 		}
-		if len(loaded.Warnings) == 0 || !strings.Contains(loaded.Warnings[0], "mode=sync") {
+		var legacy *LegacyModeWarning
+		if len(loaded.Warnings) == 0 || !errors.As(loaded.Warnings[0], &legacy) || legacy.Mapping != "a-dev" || !strings.Contains(legacy.Error(), "mode=sync") {
 			t.Fatalf("expected legacy sync warning, got: %#v", loaded.Warnings)
 		}
 	})
@@ -254,7 +287,7 @@ func TestLoad(t *testing.T) {
 	t.Run("AbsConfigPathErrorViaMissingCwd", func(t *testing.T) {
 		deps := defaultConfigDeps
 		deps.abs = func(string) (string, error) { return "", errors.New("boom") }
-		_, err := loadWithDeps(".", DefaultConfigName, deps)
+		_, err := loadWithDeps(".", DefaultConfigName, LoadOptions{}, deps)
 		if err == nil {
 			t.Fatalf("expected error")
 		}
@@ -363,3 +396,1393 @@ func TestMappingMode_Allows(t *testing.T) {
 		}
 	}
 }
+
+func TestParseAge(t *testing.T) {
+	cases := map[string]time.Duration{
+		"14d": 14 * 24 * time.Hour,
+		"36h": 36 * time.Hour,
+		"90m": 90 * time.Minute,
+	}
+	for input, want := range cases {
+		got, err := ParseAge(input)
+		if err != nil || got != want {
+			t.Fatalf("ParseAge(%q) = %v, %v; want %v", input, got, err, want)
+		}
+	}
+	for _, input := range []string{"xd", "soon", "0d", "-1h"} {
+		if _, err := ParseAge(input); err == nil {
+			t.Fatalf("expected ParseAge(%q) to fail", input)
+		}
+	}
+}
+
+func TestPolicy(t *testing.T) {
+	writePolicy := func(t *testing.T, payload string) string {
+		t.Helper()
+		path := filepath.Join(t.TempDir(), "policy.json")
+		if err := os.WriteFile(path, []byte(payload), 0o644); err != nil {
+			t.Fatalf("write policy: %v", err)
+		}
+		return path
+	}
+
+	t.Run("LoadPolicyFileDefaultsLevel", func(t *testing.T) {
+		policy, err := LoadPolicyFile(writePolicy(t, `{"max_secret_age":" 90d ","forbidden_key_patterns":["^PROD_"],"required_tags":["owner"],"max_payload_bytes":10}`))
+		if err != nil {
+			t.Fatalf("LoadPolicyFile: %v", err)
+		}
+		if policy.Level != PolicyLevelWarn || policy.MaxSecretAge != "90d" || policy.MaxPayloadBytes != 10 {
+			t.Fatalf("unexpected policy: %#v", policy)
+		}
+	})
+
+	t.Run("RegoBundleRelativeToPolicyFile", func(t *testing.T) {
+		path := writePolicy(t, `{"rego":{"bundle":"policies"}}`)
+		policy, err := LoadPolicyFile(path)
+		if err != nil {
+			t.Fatalf("LoadPolicyFile: %v", err)
+		}
+		if policy.Rego.Bundle != filepath.Join(filepath.Dir(path), "policies") || policy.Rego.Query != DefaultRegoQuery {
+			t.Fatalf("unexpected rego: %#v", policy.Rego)
+		}
+
+		policy, err = LoadPolicyFile(writePolicy(t, `{"rego":{"bundle":"/abs/push.rego","query":"data.org.deny"}}`))
+		if err != nil {
+			t.Fatalf("LoadPolicyFile: %v", err)
+		}
+		if policy.Rego.Bundle != "/abs/push.rego" || policy.Rego.Query != "data.org.deny" {
+			t.Fatalf("unexpected rego: %#v", policy.Rego)
+		}
+	})
+
+	t.Run("LoadPolicyFileErrors", func(t *testing.T) {
+		cases := map[string]string{
+			"decode policy json": `{"nope":1}`,
+			"trailing data":      `{}{}`,
+			"invalid level":      `{"level":"block"}`,
+			"max_secret_age":     `{"max_secret_age":"soon"}`,
+			"forbidden_key":      `{"forbidden_key_patterns":["("]}`,
+			"empty tag":          `{"required_tags":[" "]}`,
+			"must not be neg":    `{"max_payload_bytes":-1}`,
+			"bundle":             `{"rego":{"bundle":" "}}`,
+		}
+		for want, payload := range cases {
+			if _, err := LoadPolicyFile(writePolicy(t, payload)); err == nil || !strings.Contains(err.Error(), want) {
+				t.Fatalf("payload %s: expected error containing %q, got %v", payload, want, err)
+			}
+		}
+		if _, err := LoadPolicyFile(filepath.Join(t.TempDir(), "missing.json")); err == nil || !strings.Contains(err.Error(), "read policy file") {
+			t.Fatalf("expected read error, got %v", err)
+		}
+	})
+
+	t.Run("ConfigSection", func(t *testing.T) {
+		dir := t.TempDir()
+		cfgPath := filepath.Join(dir, DefaultConfigName)
+		if err := os.WriteFile(cfgPath, []byte(`{"organization_id":"o","project_id":"p","region":"fr-par","mapping":{"a-dev":{"file":"x"}},"policy":{"level":"enforce","rego":{"bundle":"policy"}}}`), 0o644); err != nil {
+			t.Fatalf("write config: %v", err)
+		}
+		loaded, err := Load(dir, cfgPath)
+		if err != nil {
+			t.Fatalf("load: %v", err)
+		}
+		if loaded.Cfg.Policy == nil || loaded.Cfg.Policy.Level != PolicyLevelEnforce || loaded.Cfg.Policy.Rego.Bundle != filepath.Join(dir, "policy") {
+			t.Fatalf("unexpected policy: %#v", loaded.Cfg.Policy)
+		}
+
+		if err := os.WriteFile(cfgPath, []byte(`{"organization_id":"o","project_id":"p","region":"fr-par","mapping":{"a-dev":{"file":"x"}},"policy":{"level":"nope"}}`), 0o644); err != nil {
+			t.Fatalf("write config: %v", err)
+		}
+		if _, err := Load(dir, cfgPath); err == nil || !strings.Contains(err.Error(), "policy: invalid level") {
+			t.Fatalf("expected policy error, got %v", err)
+		}
+	})
+}
+
+func TestSetMappingDisabled(t *testing.T) {
+	write := func(t *testing.T) string {
+		t.Helper()
+		path := filepath.Join(t.TempDir(), DefaultConfigName)
+		body := `{"organization_id":"org","project_id":"proj","region":"fr-par","mapping":{"b-dev":{"file":"b.env","format":"dotenv"},"a-dev":{"file":"a.env","mode":"sync"}}}`
+		if err := os.WriteFile(path, []byte(body), 0o640); err != nil {
+			t.Fatalf("write config: %v", err)
+		}
+		return path
+	}
+
+	t.Run("Toggle", func(t *testing.T) {
+		path := write(t)
+		changed, err := SetMappingDisabled(path, "a-dev", true)
+		if err != nil || !changed {
+			t.Fatalf("expected change, got %v %v", changed, err)
+		}
+		loaded, err := Load(filepath.Dir(path), path)
+		if err != nil {
+			t.Fatalf("Load: %v", err)
+		}
+		if !loaded.Cfg.Mapping["a-dev"].Disabled || loaded.Cfg.Mapping["b-dev"].Disabled {
+			t.Fatalf("unexpected mapping: %#v", loaded.Cfg.Mapping)
+		}
+		raw, _ := os.ReadFile(path)
+		// Defaults are not persisted and the legacy mode is kept as written.
+		if strings.Contains(string(raw), `"path"`) || !strings.Contains(string(raw), `"mode": "sync"`) || !strings.Contains(string(raw), `"disabled": true`) {
+			t.Fatalf("unexpected rewrite: %s", raw)
+		}
+		if info, _ := os.Stat(path); info.Mode().Perm() != 0o640 {
+			t.Fatalf("expected mode to be preserved, got %v", info.Mode())
+		}
+
+		if changed, err := SetMappingDisabled(path, "a-dev", true); err != nil || changed {
+			t.Fatalf("expected no change, got %v %v", changed, err)
+		}
+		if changed, err := SetMappingDisabled(path, "a-dev", false); err != nil || !changed {
+			t.Fatalf("expected change, got %v %v", changed, err)
+		}
+		raw, _ = os.ReadFile(path)
+		if strings.Contains(string(raw), "disabled") {
+			t.Fatalf("expected disabled to be dropped: %s", raw)
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		path := write(t)
+		if _, err := SetMappingDisabled(path, "c-dev", true); err == nil || !strings.Contains(err.Error(), "secret not found in mapping: c-dev") {
+			t.Fatalf("expected not found error, got %v", err)
+		}
+		if _, err := SetMappingDisabled(filepath.Join(t.TempDir(), "missing.json"), "a-dev", true); err == nil || !strings.Contains(err.Error(), "read config") {
+			t.Fatalf("expected stat error, got %v", err)
+		}
+		if _, err := SetMappingDisabled(t.TempDir(), "a-dev", true); err == nil || !strings.Contains(err.Error(), "read config") {
+			t.Fatalf("expected read error, got %v", err)
+		}
+		bad := filepath.Join(t.TempDir(), "bad.json")
+		if err := os.WriteFile(bad, []byte(`{"unknown":1}`), 0o600); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+		if _, err := SetMappingDisabled(bad, "a-dev", true); err == nil || !strings.Contains(err.Error(), "decode config json") {
+			t.Fatalf("expected decode error, got %v", err)
+		}
+
+		invalid := filepath.Join(t.TempDir(), "invalid.json")
+		if err := os.WriteFile(invalid, []byte(`{"organization_id":"org","project_id":"proj","mapping":{"a-dev":{"file":"a"}}}`), 0o600); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+		if _, err := SetMappingDisabled(invalid, "a-dev", true); err == nil || !strings.Contains(err.Error(), "missing required field: region") {
+			t.Fatalf("expected validation error, got %v", err)
+		}
+
+		deps := defaultConfigDeps
+		deps.writeFile = func(string, []byte, os.FileMode) error { return errors.New("disk full") }
+		if _, err := setMappingDisabled(path, "a-dev", true, deps); err == nil || !strings.Contains(err.Error(), "write config: disk full") {
+			t.Fatalf("expected write error, got %v", err)
+		}
+	})
+}
+
+func TestAddMapping(t *testing.T) {
+	path := filepath.Join(t.TempDir(), DefaultConfigName)
+	if err := os.WriteFile(path, []byte(`{"organization_id":"org","project_id":"proj","region":"fr-par"}`), 0o640); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	if err := AddMapping(path, "api-env-dev", MappingEntry{File: "api/.env", Format: MappingFormatDotenv, Type: "key_value"}); err != nil {
+		t.Fatalf("AddMapping: %v", err)
+	}
+	loaded, err := Load(filepath.Dir(path), path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if entry := loaded.Cfg.Mapping["api-env-dev"]; entry.File != "api/.env" || entry.Format != MappingFormatDotenv || entry.Type != "key_value" {
+		t.Fatalf("unexpected mapping: %#v", loaded.Cfg.Mapping)
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0o640 {
+		t.Fatalf("expected mode to be preserved, got %v", info.Mode())
+	}
+
+	if err := AddMapping(path, "api-env-dev", MappingEntry{File: "other.env"}); err == nil || !strings.Contains(err.Error(), `mapping "api-env-dev" already exists`) {
+		t.Fatalf("expected duplicate error, got %v", err)
+	}
+	if err := AddMapping(path, "b-dev", MappingEntry{}); err == nil || !strings.Contains(err.Error(), "b-dev") {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+	if err := AddMapping(t.TempDir(), "b-dev", MappingEntry{File: "b"}); err == nil || !strings.Contains(err.Error(), "read config") {
+		t.Fatalf("expected read error, got %v", err)
+	}
+	deps := defaultConfigDeps
+	deps.writeFile = func(string, []byte, os.FileMode) error { return errors.New("disk full") }
+	if err := addMapping(path, "b-dev", MappingEntry{File: "b"}, deps); err == nil || !strings.Contains(err.Error(), "write config: disk full") {
+		t.Fatalf("expected write error, got %v", err)
+	}
+}
+
+func TestDecodeEncode(t *testing.T) {
+	cfg, err := Decode([]byte(`{"organization_id":"org","project_id":"proj","region":"fr-par","mapping":{"a-dev":{"file":"a"}},"policy":{"rego":{"bundle":"p"}}}`))
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	out, err := Encode(cfg)
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	// Defaults filled in during validation are not written back.
+	if strings.Contains(string(out), "query") || strings.Contains(string(out), `"level"`) || !strings.HasSuffix(string(out), "}\n") {
+		t.Fatalf("unexpected encoding: %s", out)
+	}
+	if cfg.Policy.Rego.Query != "" {
+		t.Fatalf("Encode mutated its input: %#v", cfg.Policy.Rego)
+	}
+	if _, err := Decode([]byte(`{"mapping":{}} {}`)); err == nil {
+		t.Fatal("expected trailing data error")
+	}
+}
+
+func TestNaming(t *testing.T) {
+	base := `{"organization_id":"org","project_id":"proj","region":"fr-par","mapping":{"a-dev":{"file":"a"}},"naming":`
+	load := func(t *testing.T, naming string) (*Loaded, error) {
+		t.Helper()
+		path := filepath.Join(t.TempDir(), DefaultConfigName)
+		if err := os.WriteFile(path, []byte(base+naming+`}`), 0o600); err != nil {
+			t.Fatalf("write config: %v", err)
+		}
+		return Load(filepath.Dir(path), path)
+	}
+
+	loaded, err := load(t, `{"patterns":["^a-"],"required_suffix":" -env-dev ","kebab_case":true}`)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if loaded.Cfg.Naming.RequiredSuffix != "-env-dev" || !loaded.Cfg.Naming.KebabCase {
+		t.Fatalf("unexpected naming: %#v", loaded.Cfg.Naming)
+	}
+
+	if _, err := load(t, `{"patterns":["("]}`); err == nil || !strings.Contains(err.Error(), "naming: patterns") {
+		t.Fatalf("expected pattern error, got %v", err)
+	}
+	if _, err := load(t, `{"required_suffix":"-env"}`); err == nil || !strings.Contains(err.Error(), "must end with -dev") {
+		t.Fatalf("expected suffix error, got %v", err)
+	}
+}
+
+func TestBudget(t *testing.T) {
+	base := `{"organization_id":"org","project_id":"proj","region":"fr-par","mapping":{"a-dev":{"file":"a"}},"budget":`
+	load := func(t *testing.T, budget string) (*Loaded, error) {
+		t.Helper()
+		path := filepath.Join(t.TempDir(), DefaultConfigName)
+		if err := os.WriteFile(path, []byte(base+budget+`}`), 0o600); err != nil {
+			t.Fatalf("write config: %v", err)
+		}
+		return Load(filepath.Dir(path), path)
+	}
+
+	if calls, duration := (*Budget)(nil).Limits(); calls != DefaultMaxAPICalls || duration != DefaultMaxDuration {
+		t.Fatalf("unexpected default limits: %d %s", calls, duration)
+	}
+	loaded, err := load(t, `{"max_api_calls":10,"max_duration":"20s"}`)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if calls, duration := loaded.Cfg.Budget.Limits(); calls != 10 || duration != 20*time.Second {
+		t.Fatalf("unexpected limits: %d %s", calls, duration)
+	}
+	loaded, err = load(t, `{}`)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if calls, duration := loaded.Cfg.Budget.Limits(); calls != DefaultMaxAPICalls || duration != DefaultMaxDuration {
+		t.Fatalf("unexpected limits for an empty budget: %d %s", calls, duration)
+	}
+
+	for budget, wantErr := range map[string]string{
+		`{"max_api_calls":-1}`:    "budget: max_api_calls must not be negative",
+		`{"max_duration":"soon"}`: `budget: max_duration must be a positive duration such as "20s", got "soon"`,
+		`{"max_duration":"-5s"}`:  "budget: max_duration must be a positive duration",
+	} {
+		if _, err := load(t, budget); err == nil || !strings.Contains(err.Error(), wantErr) {
+			t.Fatalf("%s: expected %q, got %v", budget, wantErr, err)
+		}
+	}
+}
+
+func TestAccessContact(t *testing.T) {
+	base := `{"organization_id":"org","project_id":"proj","region":"fr-par","mapping":{"a-dev":{"file":"a"}},"access_contact":`
+	load := func(t *testing.T, contact string) (*Loaded, error) {
+		t.Helper()
+		path := filepath.Join(t.TempDir(), DefaultConfigName)
+		if err := os.WriteFile(path, []byte(base+contact+`}`), 0o600); err != nil {
+			t.Fatalf("write config: %v", err)
+		}
+		return Load(filepath.Dir(path), path)
+	}
+
+	loaded, err := load(t, `{"owner":" Platform team ","contact":"#platform-access","request_url":"https://access.example.test/dev-vault"}`)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if got := *loaded.Cfg.AccessContact; got != (AccessContact{Owner: "Platform team", Contact: "#platform-access", RequestURL: "https://access.example.test/dev-vault"}) {
+		t.Fatalf("unexpected access_contact: %#v", got)
+	}
+	for contact, wantErr := range map[string]string{
+		`{}`:                                    "access_contact: set at least one of owner, contact, request_url",
+		`{"request_url":"access.example.test"}`: `access_contact: request_url must be an http(s) URL, got "access.example.test"`,
+		`{"request_url":"mailto:a@b.test"}`:     "request_url must be an http(s) URL",
+		`{"request_url":"https://%zz"}`:         "request_url must be an http(s) URL",
+	} {
+		if _, err := load(t, contact); err == nil || !strings.Contains(err.Error(), wantErr) {
+			t.Fatalf("%s: expected %q, got %v", contact, wantErr, err)
+		}
+	}
+}
+
+func TestStateSecret(t *testing.T) {
+	load := func(t *testing.T, state string) (*Loaded, error) {
+		t.Helper()
+		path := filepath.Join(t.TempDir(), DefaultConfigName)
+		raw := `{"organization_id":"org","project_id":"proj","region":"fr-par","mapping":{"a-dev":{"file":"a"}},"state_secret":` + state + `}`
+		if err := os.WriteFile(path, []byte(raw), 0o600); err != nil {
+			t.Fatalf("write config: %v", err)
+		}
+		return Load(filepath.Dir(path), path)
+	}
+
+	loaded, err := load(t, `" project-devvault-state-dev "`)
+	if err != nil || loaded.Cfg.StateSecret != "project-devvault-state-dev" {
+		t.Fatalf("unexpected state_secret: %v %v", loaded, err)
+	}
+	for state, wantErr := range map[string]string{
+		`"project-state"`: `state_secret "project-state" must end with -dev`,
+		`"a-dev"`:         `state_secret "a-dev" is also a mapping key`,
+	} {
+		if _, err := load(t, state); err == nil || !strings.Contains(err.Error(), wantErr) {
+			t.Fatalf("%s: expected %q, got %v", state, wantErr, err)
+		}
+	}
+}
+
+func TestFormatFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), DefaultConfigName)
+	messy := `{"mapping":{"b-dev":{"file":"b"},"a-dev":{"mode":"pull","file":"a"}},"region":"fr-par","project_id":"proj","organization_id":"org"}`
+	if err := os.WriteFile(path, []byte(messy), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	if located, err := LocatePath(filepath.Dir(path), ""); err != nil || located != path {
+		t.Fatalf("expected located path %s, got %q %v", path, located, err)
+	}
+	if _, err := LocatePath("", ""); err == nil {
+		t.Fatal("expected error for empty startDir")
+	}
+
+	changed, err := FormatFile(path, false)
+	if err != nil || !changed {
+		t.Fatalf("expected check to report a change, got %v %v", changed, err)
+	}
+	if raw, _ := os.ReadFile(path); string(raw) != messy {
+		t.Fatalf("check must not write: %s", raw)
+	}
+
+	if changed, err := FormatFile(path, true); err != nil || !changed {
+		t.Fatalf("expected rewrite, got %v %v", changed, err)
+	}
+	raw, _ := os.ReadFile(path)
+	want := `{
+  "organization_id": "org",
+  "project_id": "proj",
+  "region": "fr-par",
+  "mapping": {
+    "a-dev": {
+      "file": "a",
+      "mode": "pull"
+    },
+    "b-dev": {
+      "file": "b"
+    }
+  }
+}
+`
+	if string(raw) != want {
+		t.Fatalf("unexpected canonical form:\n%s", raw)
+	}
+	if changed, err := FormatFile(path, false); err != nil || changed {
+		t.Fatalf("expected canonical file to pass, got %v %v", changed, err)
+	}
+
+	if _, err := FormatFile(filepath.Join(t.TempDir(), "missing.json"), true); err == nil || !strings.Contains(err.Error(), "read config") {
+		t.Fatalf("expected read error, got %v", err)
+	}
+	invalid := filepath.Join(t.TempDir(), "invalid.json")
+	if err := os.WriteFile(invalid, []byte(`{"organization_id":"org"}`), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if _, err := FormatFile(invalid, true); err == nil || !strings.Contains(err.Error(), "missing required field") {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+	deps := defaultConfigDeps
+	deps.writeFile = func(string, []byte, os.FileMode) error { return errors.New("disk full") }
+	if err := os.WriteFile(path, []byte(messy), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if _, err := formatFile(path, true, deps); err == nil || !strings.Contains(err.Error(), "write config: disk full") {
+		t.Fatalf("expected write error, got %v", err)
+	}
+}
+
+func TestExplain(t *testing.T) {
+	path := filepath.Join(t.TempDir(), DefaultConfigName)
+	manifest := `{
+  "organization_id": "org",
+  "project_id": "proj",
+  "region": "fr-par",
+  "mapping": {
+    "a-dev": {"file": "a", "mode": "sync"}, "b-dev": {"file": "b", "revision": 2}
+  },
+  "policy": {"required_tags": ["team"]},
+  "mapping_defaults": {"format": "dotenv", "mode": "push"}
+}`
+	if err := os.WriteFile(path, []byte(manifest), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	origins, err := Explain(path, "")
+	if err != nil {
+		t.Fatalf("Explain: %v", err)
+	}
+	got := make(map[string]Origin, len(origins))
+	for _, o := range origins {
+		got[o.Field] = o
+	}
+	want := map[string]Origin{
+		"region":             {Field: "region", Value: "fr-par", Source: path + ":4"},
+		"mapping.a-dev.file": {Field: "mapping.a-dev.file", Value: "a", Source: path + ":6"},
+		// Normalized values keep the line that set them.
+		"mapping.a-dev.mode":      {Field: "mapping.a-dev.mode", Value: "both", Source: path + ":6"},
+		"mapping.a-dev.path":      {Field: "mapping.a-dev.path", Value: "/", Source: SourceDefault},
+		"policy.level":            {Field: "policy.level", Value: "warn", Source: SourceDefault},
+		"policy.required_tags[0]": {Field: "policy.required_tags[0]", Value: "team", Source: path + ":8"},
+		// Unset entry fields name the mapping_defaults line, unless
+		// normalization picked another value.
+		"mapping.a-dev.format": {Field: "mapping.a-dev.format", Value: "dotenv", Source: path + ":9 (mapping_defaults)"},
+		"mapping.b-dev.mode":   {Field: "mapping.b-dev.mode", Value: "pull", Source: SourceDefault},
+	}
+	for field, origin := range want {
+		if got[field] != origin {
+			t.Fatalf("%s: got %#v want %#v", field, got[field], origin)
+		}
+	}
+	if origins[0].Field != "organization_id" {
+		t.Fatalf("expected manifest field order, got %#v", origins)
+	}
+
+	if _, err := Explain(filepath.Join(t.TempDir(), "missing.json"), ""); err == nil {
+		t.Fatal("expected read error")
+	}
+	if err := os.WriteFile(path, []byte(`{"organization_id":"org"}`), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if _, err := Explain(path, ""); err == nil || !strings.Contains(err.Error(), "missing required field") {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+}
+
+func TestMappingDefaults(t *testing.T) {
+	load := func(t *testing.T, defaults, mapping string) (*Loaded, error) {
+		t.Helper()
+		path := filepath.Join(t.TempDir(), DefaultConfigName)
+		raw := `{"organization_id":"org","project_id":"proj","region":"fr-par","mapping_defaults":` + defaults + `,"mapping":` + mapping + `}`
+		if err := os.WriteFile(path, []byte(raw), 0o600); err != nil {
+			t.Fatalf("write config: %v", err)
+		}
+		return Load(filepath.Dir(path), path)
+	}
+
+	loaded, err := load(t, `{"format":"dotenv","path":"/app","mode":"push","type":" key_value "}`, `{
+		"a-dev":{"file":"a.env"},
+		"b-dev":{"file":"b.bin","format":"raw","path":"/","mode":"both","type":"opaque"},
+		"c-dev":{"file":"c.env","compose":["a-dev"]},
+		"d-dev":{"files":{"K":"k.pem"}}
+	}`)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	want := map[string]MappingEntry{
+		"a-dev": {File: "a.env", Format: MappingFormatDotenv, Path: "/app", Mode: MappingModePush, Type: "key_value"},
+		"b-dev": {File: "b.bin", Format: MappingFormatRaw, Path: "/", Mode: MappingModeBoth, Type: "opaque"},
+		// Pull-only entries stay pull, and split files take no format or type.
+		"c-dev": {File: "c.env", Format: MappingFormatDotenv, Path: "/app", Mode: MappingModePull, Type: "key_value", Compose: []string{"a-dev"}},
+		"d-dev": {Files: map[string]string{"K": "k.pem"}, Format: MappingFormatRaw, Path: "/app", Mode: MappingModePush},
+	}
+	for name, entry := range want {
+		if got := loaded.Cfg.Mapping[name]; !reflect.DeepEqual(got, entry) {
+			t.Fatalf("%s: got %#v want %#v", name, got, entry)
+		}
+	}
+
+	for defaults, wantErr := range map[string]string{
+		`{"format":"template"}`: `mapping_defaults: invalid format "template"`,
+		`{"path":"app"}`:        `mapping_defaults: path must start with '/', got "app"`,
+		`{"mode":"sync"}`:       `mapping_defaults: invalid mode "sync"`,
+		`{"type":"nope"}`:       `mapping_defaults: invalid type "nope"`,
+		`{"chmod":"0600"}`:      `unknown field "mapping_defaults.chmod"`,
+	} {
+		if _, err := load(t, defaults, `{"a-dev":{"file":"a"}}`); err == nil || !strings.Contains(err.Error(), wantErr) {
+			t.Fatalf("%s: expected %q, got %v", defaults, wantErr, err)
+		}
+	}
+}
+
+func TestUnknownFields(t *testing.T) {
+	dir := t.TempDir()
+	cfgPath := filepath.Join(dir, DefaultConfigName)
+	load := func(t *testing.T, raw string, opts LoadOptions) (*Loaded, error) {
+		t.Helper()
+		if err := os.WriteFile(cfgPath, []byte(raw), 0o600); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+		return LoadWithOptions(dir, "", opts)
+	}
+	raw := `{"organization_id":"org","project_id":"proj","Region":"fr-par","required_version":" >=9.0.0 ","hooks":{"post_pull":"make"},
+		"policy":{"required_tags":["team"],"rego":{"bundle":"p.rego","strict":true}},
+		"mapping":{"a-dev":{"file":"a.env","format":"dotenv","chmod":"0600","keys":{"include":["A"],"only":["B"]}},"b-dev":{"file":"b.env","format":"dotenv","keys":["B"]}}}`
+
+	_, err := load(t, raw, LoadOptions{})
+	var unknown *UnknownFieldsError
+	if !errors.As(err, &unknown) || unknown.RequiredVersion != ">=9.0.0" {
+		t.Fatalf("expected unknown fields error, got %v", err)
+	}
+	want := []string{"hooks", "mapping.a-dev.chmod", "mapping.a-dev.keys.only", "policy.rego.strict"}
+	if !reflect.DeepEqual(unknown.Fields, want) {
+		t.Fatalf("unexpected fields: %v", unknown.Fields)
+	}
+	if err.Error() != `decode config json: unknown fields "hooks", "mapping.a-dev.chmod", "mapping.a-dev.keys.only", "policy.rego.strict"` {
+		t.Fatalf("unexpected message: %v", err)
+	}
+
+	loaded, err := load(t, raw, LoadOptions{IgnoreUnknown: true})
+	if err != nil {
+		t.Fatalf("LoadWithOptions: %v", err)
+	}
+	if loaded.Cfg.Region != "fr-par" || loaded.Cfg.Mapping["a-dev"].Keys.Include[0] != "A" || loaded.Cfg.Policy.Rego.Bundle == "" {
+		t.Fatalf("known fields lost: %#v", loaded.Cfg)
+	}
+	if len(loaded.Warnings) != 4 || loaded.Warnings[1].Error() != fmt.Sprintf(`ignoring unknown field "mapping.a-dev.chmod" in %s`, cfgPath) {
+		t.Fatalf("unexpected warnings: %v", loaded.Warnings)
+	}
+
+	// Other decode errors stay as they are, with or without unknown fields.
+	for _, raw := range []string{
+		`{"region":1,"mapping":{}}`,
+		`{"region":1,"chmod":"0600","mapping":{}}`,
+		`{"chmod":"0600","mapping":[]} {}`,
+		`{"mapping":{}} {}`,
+	} {
+		for _, opts := range []LoadOptions{{}, {IgnoreUnknown: true}} {
+			if _, err := load(t, raw, opts); err == nil || errors.As(err, &unknown) {
+				t.Fatalf("%s: expected a plain decode error, got %v", raw, err)
+			}
+		}
+	}
+	if _, err := load(t, `{"region":"fr-par","chmod":"0600","mapping":{}}`, LoadOptions{}); err == nil || err.Error() != `decode config json: unknown field "chmod"` {
+		t.Fatalf("unexpected single-field error: %v", err)
+	}
+}
+
+func TestWalkScalars(t *testing.T) {
+	var fields []string
+	if err := walkScalars([]byte(`{"a":{"b":[1,true]},"c":null}`), func(field string, _ any, _ int64) {
+		fields = append(fields, field)
+	}); err != nil {
+		t.Fatalf("walkScalars: %v", err)
+	}
+	if strings.Join(fields, " ") != "a.b[0] a.b[1] c" {
+		t.Fatalf("unexpected fields: %v", fields)
+	}
+	for _, raw := range []string{``, `{"a":`, `{"a":1,`, `{"a":1`, `[1,`, `[1`} {
+		if err := walkScalars([]byte(raw), func(string, any, int64) {}); err == nil {
+			t.Fatalf("expected error for %q", raw)
+		}
+	}
+}
+
+func TestLocalOverlay(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, DefaultConfigName)
+	shared := `{"organization_id":"org","project_id":"proj","region":"fr-par","mapping":{"a-dev":{"file":"a","mode":"pull"},"b-dev":{"file":"b"},"t-dev":{"file":"t","format":"template","template_file":"t.tmpl"},"f-dev":{"files":{"K":"k.pem"}},"g-dev":{"file":"g"}}}`
+	if err := os.WriteFile(path, []byte(shared), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	local := filepath.Join(dir, LocalConfigName)
+	if LocalPath(path) != local || LocalPath("/x/team.json") != "/x/team.local.json" {
+		t.Fatalf("unexpected local paths: %s", LocalPath(path))
+	}
+	writeLocal := func(t *testing.T, raw string) {
+		t.Helper()
+		if err := os.WriteFile(local, []byte(raw), 0o600); err != nil {
+			t.Fatalf("write local: %v", err)
+		}
+	}
+
+	loaded, err := Load(dir, "")
+	if err != nil || loaded.LocalPath != "" {
+		t.Fatalf("expected no overlay, got %#v %v", loaded, err)
+	}
+
+	writeLocal(t, `{
+  "profile": "me", "budget": {"max_api_calls": 5}, "mtime": "remote", "expire_after": "8h", "mapping_defaults": {"type": "opaque"},
+  "vars": {"PORT": "9000"},
+  "mapping": {
+    "a-dev": {"file": "mine/a", "format": "dotenv", "path": "/me", "mode": "both", "type": "opaque"},
+    "b-dev": {"disabled": true, "format": "dotenv", "mode": "pull", "compose": ["a-dev"], "key_prefix": "P_", "key_suffix": "_S", "keys": {"include": ["A*"]}, "exclude_keys": ["AX"], "substitute": true, "profile": " client ", "revision": 3},
+    "c-dev": {"file": "c"},
+    "t-dev": {"template_file": "mine/t.tmpl"},
+    "f-dev": {"file": "f"},
+    "g-dev": {"files": {"K": "mine/k.pem"}}
+  }
+}`)
+	loaded, err = Load(dir, "")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if loaded.LocalPath != local || loaded.Cfg.Profile != "me" || loaded.Cfg.Budget == nil || loaded.Cfg.Budget.MaxAPICalls != 5 || loaded.Cfg.ProjectID != "proj" || loaded.Cfg.Vars["PORT"] != "9000" || loaded.Cfg.Mtime != MtimeRemote || loaded.Cfg.ExpireAfter != "8h" {
+		t.Fatalf("unexpected merge: %#v", loaded)
+	}
+	want := MappingEntry{File: "mine/a", Format: MappingFormatDotenv, Path: "/me", Mode: MappingModeBoth, Type: "opaque"}
+	if got := loaded.Cfg.Mapping["a-dev"]; !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected a-dev: %#v", got)
+	}
+	if c := loaded.Cfg.Mapping["c-dev"]; c.Type != "opaque" {
+		t.Fatalf("expected overlay mapping_defaults, got %#v", c)
+	}
+	if b := loaded.Cfg.Mapping["b-dev"]; !b.Disabled || b.File != "b" || len(b.Compose) != 1 || b.KeyPrefix != "P_" || b.KeySuffix != "_S" || b.Keys == nil || b.Keys.Exclude[0] != "AX" || !b.Substitute || b.Profile != "client" || b.Revision != 3 {
+		t.Fatalf("unexpected b-dev: %#v", b)
+	}
+	if tmpl := loaded.Cfg.Mapping["t-dev"]; tmpl.TemplateFile != "mine/t.tmpl" || tmpl.Format != MappingFormatTemplate {
+		t.Fatalf("unexpected t-dev: %#v", tmpl)
+	}
+	if f, g := loaded.Cfg.Mapping["f-dev"], loaded.Cfg.Mapping["g-dev"]; f.File != "f" || f.Files != nil || g.File != "" || g.Files["K"] != "mine/k.pem" {
+		t.Fatalf("unexpected f-dev/g-dev: %#v %#v", f, g)
+	}
+	if c, ok := loaded.Cfg.Mapping["c-dev"]; !ok || c.Mode != MappingModeBoth {
+		t.Fatalf("expected added c-dev, got %#v", loaded.Cfg.Mapping)
+	}
+
+	origins, err := Explain(path, "")
+	if err != nil {
+		t.Fatalf("Explain: %v", err)
+	}
+	sources := make(map[string]string, len(origins))
+	for _, o := range origins {
+		sources[o.Field] = o.Source
+	}
+	if sources["profile"] != local+":2" || sources["mapping.a-dev.file"] != local+":5" ||
+		sources["mapping.b-dev.file"] != path+":1" || sources["region"] != path+":1" {
+		t.Fatalf("unexpected sources: %#v", sources)
+	}
+
+	for raw, wantErr := range map[string]string{
+		`{"mapping":{"c":{"file":"c"}}}`:        "must end with -dev",
+		`{"policy":{"level":"warn"}}`:           "cannot be overridden locally",
+		`{"naming":{}}`:                         "cannot be overridden locally",
+		`{"required_version":">=1.0.0"}`:        "cannot be overridden locally",
+		`{"unknown":1}`:                         LocalConfigName,
+		`{"region":"nl-ams","mapping":{"x":1}}`: "decode config json",
+	} {
+		writeLocal(t, raw)
+		if _, err := Load(dir, ""); err == nil || !strings.Contains(err.Error(), wantErr) {
+			t.Fatalf("%s: expected %q, got %v", raw, wantErr, err)
+		}
+		if _, err := Explain(path, ""); err == nil {
+			t.Fatalf("%s: expected Explain error", raw)
+		}
+	}
+
+	if err := os.Remove(local); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	if err := os.Mkdir(local, 0o700); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if _, err := Load(dir, ""); err == nil || !strings.Contains(err.Error(), "read local config") {
+		t.Fatalf("expected read error, got %v", err)
+	}
+
+	// An overlay may supply the mapping a bare shared manifest leaves out.
+	bare := filepath.Join(t.TempDir(), DefaultConfigName)
+	if err := os.WriteFile(bare, []byte(`{"organization_id":"org","project_id":"proj","region":"fr-par"}`), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := os.WriteFile(LocalPath(bare), []byte(`{"organization_id":"org2","project_id":"proj2","region":"nl-ams","mapping":{"a-dev":{"file":"a"}}}`), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	loaded, err = Load(filepath.Dir(bare), "")
+	if err != nil || len(loaded.Cfg.Mapping) != 1 || loaded.Cfg.OrganizationID != "org2" || loaded.Cfg.ProjectID != "proj2" || loaded.Cfg.Region != "nl-ams" {
+		t.Fatalf("unexpected bare merge: %#v %v", loaded, err)
+	}
+
+	// A personal overlay can point the checkout at another provider.
+	if err := os.WriteFile(LocalPath(bare), []byte(`{"provider":"aws","region":"eu-west-3","mapping":{"a-dev":{"file":"a"}}}`), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	loaded, err = Load(filepath.Dir(bare), "")
+	if err != nil || loaded.Cfg.Provider != ProviderAWS || loaded.Cfg.Region != "eu-west-3" {
+		t.Fatalf("unexpected provider merge: %#v %v", loaded, err)
+	}
+}
+
+func TestCompose(t *testing.T) {
+	load := func(t *testing.T, mapping string) (*Loaded, error) {
+		t.Helper()
+		path := filepath.Join(t.TempDir(), DefaultConfigName)
+		raw := `{"organization_id":"org","project_id":"proj","region":"fr-par","mapping":` + mapping + `}`
+		if err := os.WriteFile(path, []byte(raw), 0o600); err != nil {
+			t.Fatalf("write config: %v", err)
+		}
+		return Load(filepath.Dir(path), path)
+	}
+
+	loaded, err := load(t, `{
+		"base-env-dev": {"file": "base.env", "mode": "push"},
+		"db-env-dev": {"file": "db.env", "format": "dotenv", "compose": ["base-env-dev"]},
+		"app-env-dev": {"file": ".env", "format": "dotenv", "compose": ["base-env-dev", "db-env-dev"]}
+	}`)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if app := loaded.Cfg.Mapping["app-env-dev"]; app.Mode != MappingModePull || !reflect.DeepEqual(app.Compose, []string{"base-env-dev", "db-env-dev"}) {
+		t.Fatalf("unexpected composed entry: %#v", app)
+	}
+	if loaded, err := load(t, `{"a-dev":{"file":"a","format":"dotenv","provenance":true}}`); err != nil || loaded.Cfg.Mapping["a-dev"].Mode != MappingModePull {
+		t.Fatalf("expected provenance to default to pull: %v", err)
+	}
+	if loaded, err := load(t, `{"a-dev":{"file":"a","format":"dotenv","preserve_layout":true}}`); err != nil || !loaded.Cfg.Mapping["a-dev"].PreserveLayout || loaded.Cfg.Mapping["a-dev"].Mode != MappingModeBoth {
+		t.Fatalf("expected preserve_layout to keep mode both: %v", err)
+	}
+
+	for mapping, wantErr := range map[string]string{
+		`{"a-dev":{"file":"a","compose":["b-dev"]},"b-dev":{"file":"b"}}`:                                 "compose requires format dotenv",
+		`{"a-dev":{"file":"a","provenance":true}}`:                                                        "provenance requires format dotenv",
+		`{"a-dev":{"file":"a","format":"dotenv","mode":"both","provenance":true}}`:                        "provenance mappings are pull-only",
+		`{"a-dev":{"file":"a","preserve_layout":true}}`:                                                   "preserve_layout requires format dotenv",
+		`{"a-dev":{"file":"a","format":"dotenv","provenance":true,"preserve_layout":true}}`:               "preserve_layout cannot be combined with provenance",
+		`{"a-dev":{"file":"a","format":"dotenv","mode":"both","compose":["b-dev"]},"b-dev":{"file":"b"}}`: "pull-only",
+		`{"a-dev":{"file":"a","format":"dotenv","compose":["c-dev"]}}`:                                    `compose reference "c-dev" is not mapped`,
+		`{"a-dev":{"file":"a","format":"dotenv","compose":["a-dev"]}}`:                                    "compose cycle: a-dev -> a-dev",
+		`{"a-dev":{"file":"a","format":"dotenv","compose":["b-dev"]},"b-dev":{"file":"b","format":"dotenv","compose":["c-dev"]},"c-dev":{"file":"c","format":"dotenv","compose":["b-dev"]}}`: "compose cycle: b-dev -> c-dev -> b-dev",
+	} {
+		if _, err := load(t, mapping); err == nil || !strings.Contains(err.Error(), wantErr) {
+			t.Fatalf("%s: expected %q, got %v", mapping, wantErr, err)
+		}
+	}
+}
+
+func TestKeyAffixes(t *testing.T) {
+	load := func(t *testing.T, entry string) (*Loaded, error) {
+		t.Helper()
+		path := filepath.Join(t.TempDir(), DefaultConfigName)
+		raw := `{"organization_id":"org","project_id":"proj","region":"fr-par","mapping":{"web-env-dev":` + entry + `}}`
+		if err := os.WriteFile(path, []byte(raw), 0o600); err != nil {
+			t.Fatalf("write config: %v", err)
+		}
+		return Load(filepath.Dir(path), path)
+	}
+
+	loaded, err := load(t, `{"file":".env","format":"dotenv","key_prefix":"VITE_","key_suffix":"_DEV"}`)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if e := loaded.Cfg.Mapping["web-env-dev"]; e.KeyPrefix != "VITE_" || e.KeySuffix != "_DEV" {
+		t.Fatalf("unexpected entry: %#v", e)
+	}
+	for entry, wantErr := range map[string]string{
+		`{"file":".env","key_prefix":"VITE_"}`:                    "require format dotenv",
+		`{"file":".env","format":"dotenv","key_prefix":"VITE-"}`:  `key_prefix "VITE-" must be`,
+		`{"file":".env","format":"dotenv","key_suffix":"_DEV X"}`: `key_suffix "_DEV X" must be`,
+	} {
+		if _, err := load(t, entry); err == nil || !strings.Contains(err.Error(), wantErr) {
+			t.Fatalf("%s: expected %q, got %v", entry, wantErr, err)
+		}
+	}
+}
+
+func TestKeyFilterConfig(t *testing.T) {
+	load := func(t *testing.T, entry string) (*Loaded, error) {
+		t.Helper()
+		path := filepath.Join(t.TempDir(), DefaultConfigName)
+		raw := `{"organization_id":"org","project_id":"proj","region":"fr-par","mapping":{"shared-env-dev":` + entry + `}}`
+		if err := os.WriteFile(path, []byte(raw), 0o600); err != nil {
+			t.Fatalf("write config: %v", err)
+		}
+		return Load(filepath.Dir(path), path)
+	}
+
+	loaded, err := load(t, `{"file":".env","format":"dotenv","keys":{"include":["DB_*","REDIS_URL"],"exclude":["*_PROD"]}}`)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if keys := loaded.Cfg.Mapping["shared-env-dev"].Keys; keys == nil || len(keys.Include) != 2 || keys.Exclude[0] != "*_PROD" {
+		t.Fatalf("unexpected keys: %#v", keys)
+	}
+	// A list is short for include, and exclude_keys adds to exclude.
+	loaded, err = load(t, `{"file":"app.yaml","format":"yaml","keys":["DB_*"],"exclude_keys":["DB_ROOT_*"]}`)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if entry := loaded.Cfg.Mapping["shared-env-dev"]; !reflect.DeepEqual(*entry.Keys, KeyFilter{Include: []string{"DB_*"}, Exclude: []string{"DB_ROOT_*"}}) || entry.ExcludeKeys != nil {
+		t.Fatalf("unexpected keys: %#v", entry)
+	}
+	loaded, err = load(t, `{"file":"app.json","format":"json","keys":{"exclude":["A"]},"exclude_keys":["B"]}`)
+	if err != nil || !reflect.DeepEqual(loaded.Cfg.Mapping["shared-env-dev"].Keys.Exclude, []string{"A", "B"}) {
+		t.Fatalf("unexpected merged exclude: %v", err)
+	}
+	for entry, wantErr := range map[string]string{
+		`{"file":".env","keys":{"include":["A"]}}`:                "keys and exclude_keys require format dotenv, yaml, toml, or json",
+		`{"file":".env","exclude_keys":["A"]}`:                    "keys and exclude_keys require format dotenv, yaml, toml, or json",
+		`{"file":".env","format":"dotenv","keys":{"only":["A"]}}`: `unknown field "mapping.shared-env-dev.keys.only"`,
+		`{"file":".env","format":"dotenv","keys":"A"}`:            "cannot unmarshal string",
+	} {
+		if _, err := load(t, entry); err == nil || !strings.Contains(err.Error(), wantErr) {
+			t.Fatalf("%s: expected %q, got %v", entry, wantErr, err)
+		}
+	}
+	if _, err := load(t, `{"file":".env","format":"dotenv","keys":{"exclude":["["]}}`); err == nil || !strings.Contains(err.Error(), `invalid pattern "["`) {
+		t.Fatalf("expected pattern error, got %v", err)
+	}
+}
+
+func TestFakeKeysConfig(t *testing.T) {
+	load := func(t *testing.T, entry string) (*Loaded, error) {
+		t.Helper()
+		path := filepath.Join(t.TempDir(), DefaultConfigName)
+		raw := `{"organization_id":"org","project_id":"proj","region":"fr-par","mapping":{"app-env-dev":` + entry + `}}`
+		if err := os.WriteFile(path, []byte(raw), 0o600); err != nil {
+			t.Fatalf("write config: %v", err)
+		}
+		return Load(filepath.Dir(path), path)
+	}
+
+	loaded, err := load(t, `{"file":".env","format":"dotenv","fake_keys":["DATABASE_URL","DB_PORT"]}`)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if keys := loaded.Cfg.Mapping["app-env-dev"].FakeKeys; len(keys) != 2 || keys[1] != "DB_PORT" {
+		t.Fatalf("unexpected fake_keys: %#v", keys)
+	}
+	for entry, wantErr := range map[string]string{
+		`{"file":".env","fake_keys":["A"]}`:                     "fake_keys requires format dotenv",
+		`{"file":".env","format":"dotenv","fake_keys":["A-B"]}`: `fake_keys: "A-B" must be`,
+	} {
+		if _, err := load(t, entry); err == nil || !strings.Contains(err.Error(), wantErr) {
+			t.Fatalf("%s: expected %q, got %v", entry, wantErr, err)
+		}
+	}
+}
+
+func TestTemplateConfig(t *testing.T) {
+	load := func(t *testing.T, entry string) (*Loaded, error) {
+		t.Helper()
+		path := filepath.Join(t.TempDir(), DefaultConfigName)
+		raw := `{"organization_id":"org","project_id":"proj","region":"fr-par","mapping":{"app-settings-dev":` + entry + `}}`
+		if err := os.WriteFile(path, []byte(raw), 0o600); err != nil {
+			t.Fatalf("write config: %v", err)
+		}
+		return Load(filepath.Dir(path), path)
+	}
+
+	loaded, err := load(t, `{"file":"appsettings.json","format":"template","template_file":" templates/appsettings.json.tmpl "}`)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if entry := loaded.Cfg.Mapping["app-settings-dev"]; entry.TemplateFile != "templates/appsettings.json.tmpl" || entry.Mode != MappingModePull {
+		t.Fatalf("unexpected template entry: %#v", entry)
+	}
+	for entry, wantErr := range map[string]string{
+		`{"file":"a","format":"template"}`:                                        "format template requires template_file",
+		`{"file":"a","template_file":"a.tmpl"}`:                                   "template_file requires format template",
+		`{"file":"a","format":"template","template_file":"/etc/a.tmpl"}`:          `template_file must be relative, got "/etc/a.tmpl"`,
+		`{"file":"a","format":"template","template_file":"a.tmpl","mode":"both"}`: `template mappings are pull-only, got mode "both"`,
+	} {
+		if _, err := load(t, entry); err == nil || !strings.Contains(err.Error(), wantErr) {
+			t.Fatalf("%s: expected %q, got %v", entry, wantErr, err)
+		}
+	}
+}
+
+func TestFilesConfig(t *testing.T) {
+	load := func(t *testing.T, entry string) (*Loaded, error) {
+		t.Helper()
+		path := filepath.Join(t.TempDir(), DefaultConfigName)
+		raw := `{"organization_id":"org","project_id":"proj","region":"fr-par","mapping":{"db-tls-dev":` + entry + `}}`
+		if err := os.WriteFile(path, []byte(raw), 0o600); err != nil {
+			t.Fatalf("write config: %v", err)
+		}
+		return Load(filepath.Dir(path), path)
+	}
+
+	loaded, err := load(t, `{"files":{"DB_CA_CERT":" certs/ca.pem ","DB_KEY":"certs/key.pem"},"type":"key_value"}`)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	entry := loaded.Cfg.Mapping["db-tls-dev"]
+	if !reflect.DeepEqual(entry.Files, map[string]string{"DB_CA_CERT": "certs/ca.pem", "DB_KEY": "certs/key.pem"}) || entry.File != "" || entry.Format != MappingFormatRaw || entry.Mode != MappingModeBoth {
+		t.Fatalf("unexpected files entry: %#v", entry)
+	}
+	for entry, wantErr := range map[string]string{
+		`{"file":"a","files":{"K":"k"}}`:                    "file and files are mutually exclusive",
+		`{"files":{"K":"k"},"format":"dotenv"}`:             `files writes each value as-is and takes no format, got "dotenv"`,
+		`{"files":{"K":"k"},"type":"opaque"}`:               `files requires type key_value, got "opaque"`,
+		`{"files":{"K":"k"},"substitute":true}`:             "files cannot be combined with substitute",
+		`{"files":{" K":"k"}}`:                              `files: invalid key " K"`,
+		`{"files":{"K":" "}}`:                               "files: key K has no file",
+		`{"files":{"K":"/etc/k"}}`:                          `files: K must be relative, got "/etc/k"`,
+		`{"files":{"A":"certs/k.pem","B":"certs/./k.pem"}}`: `files: A and B both write "certs/./k.pem"`,
+		`{"files":{}}`:                                      "missing required field: file",
+	} {
+		if _, err := load(t, entry); err == nil || !strings.Contains(err.Error(), wantErr) {
+			t.Fatalf("%s: expected %q, got %v", entry, wantErr, err)
+		}
+	}
+}
+
+func TestSubstituteConfig(t *testing.T) {
+	load := func(t *testing.T, extra, entry string) (*Loaded, error) {
+		t.Helper()
+		path := filepath.Join(t.TempDir(), DefaultConfigName)
+		raw := `{"organization_id":"org","project_id":"proj","region":"fr-par",` + extra + `"mapping":{"app-env-dev":` + entry + `}}`
+		if err := os.WriteFile(path, []byte(raw), 0o600); err != nil {
+			t.Fatalf("write config: %v", err)
+		}
+		return Load(filepath.Dir(path), path)
+	}
+
+	loaded, err := load(t, `"vars":{"PORT_OFFSET":"10"},`, `{"file":".env","format":"dotenv","substitute":true}`)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if e := loaded.Cfg.Mapping["app-env-dev"]; !e.Substitute || e.Mode != MappingModePull || loaded.Cfg.Vars["PORT_OFFSET"] != "10" {
+		t.Fatalf("unexpected config: %#v", loaded.Cfg)
+	}
+	if _, err := load(t, "", `{"file":".env","substitute":true,"mode":"both"}`); err == nil || !strings.Contains(err.Error(), "pull-only") {
+		t.Fatalf("expected pull-only error, got %v", err)
+	}
+	if _, err := load(t, `"vars":{"BAD-NAME":"x"},`, `{"file":".env"}`); err == nil || !strings.Contains(err.Error(), `vars: invalid name "BAD-NAME"`) {
+		t.Fatalf("expected var name error, got %v", err)
+	}
+}
+
+func TestRevisionConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), DefaultConfigName)
+	write := func(entry string) {
+		t.Helper()
+		raw := `{"organization_id":"org","project_id":"proj","region":"fr-par","mapping":{"app-env-dev":` + entry + `}}`
+		if err := os.WriteFile(path, []byte(raw), 0o600); err != nil {
+			t.Fatalf("write config: %v", err)
+		}
+	}
+
+	write(`{"file":".env","revision":4}`)
+	loaded, err := Load(filepath.Dir(path), path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if e := loaded.Cfg.Mapping["app-env-dev"]; e.Revision != 4 || e.Mode != MappingModePull {
+		t.Fatalf("unexpected entry: %#v", e)
+	}
+	write(`{"file":".env","revision":4,"mode":"both"}`)
+	if _, err := Load(filepath.Dir(path), path); err == nil || !strings.Contains(err.Error(), `mapping "app-env-dev": mappings pinned to a revision are pull-only, got mode "both"`) {
+		t.Fatalf("expected pull-only error, got %v", err)
+	}
+}
+
+func TestNameTemplates(t *testing.T) {
+	write := func(t *testing.T, raw, local string) string {
+		t.Helper()
+		path := filepath.Join(t.TempDir(), DefaultConfigName)
+		if err := os.WriteFile(path, []byte(raw), 0o600); err != nil {
+			t.Fatalf("write config: %v", err)
+		}
+		if local != "" {
+			if err := os.WriteFile(LocalPath(path), []byte(local), 0o600); err != nil {
+				t.Fatalf("write local config: %v", err)
+			}
+		}
+		return path
+	}
+	shared := `{"organization_id":"org","project_id":"proj","region":"fr-par","vars":{"service":"bweb"},
+"mapping":{
+  "{{service}}-base-env-dev":{"file":"base.env","format":"dotenv","mode":"pull"},
+  "{{ service }}-env-dev":{"file":".env","format":"dotenv","compose":["{{service}}-base-env-dev"]}
+}}`
+
+	path := write(t, shared, "")
+	loaded, err := Load(filepath.Dir(path), path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if app, ok := loaded.Cfg.Mapping["bweb-env-dev"]; !ok || len(loaded.Cfg.Mapping) != 2 || !reflect.DeepEqual(app.Compose, []string{"bweb-base-env-dev"}) {
+		t.Fatalf("unexpected mapping: %#v", loaded.Cfg.Mapping)
+	}
+	origins, err := Explain(path, "")
+	if err != nil {
+		t.Fatalf("Explain: %v", err)
+	}
+	found := false
+	for _, origin := range origins {
+		if origin.Field == "mapping.bweb-env-dev.file" {
+			found = origin.Source == path+":4"
+		}
+	}
+	if !found {
+		t.Fatalf("expected the templated key's line in %#v", origins)
+	}
+
+	// The local overlay can change a var; disable-mapping takes the resolved name.
+	path = write(t, shared, `{"vars":{"service":"api"}}`)
+	if loaded, err := Load(filepath.Dir(path), path); err != nil || loaded.Cfg.Mapping["api-env-dev"].File != ".env" {
+		t.Fatalf("expected the overlay var to apply: %v", err)
+	}
+	if changed, err := SetMappingDisabled(path, "api-env-dev", true); err != nil || !changed {
+		t.Fatalf("expected change, got %v %v", changed, err)
+	}
+	if raw, _ := os.ReadFile(path); !strings.Contains(string(raw), `"{{ service }}-env-dev": {
+      "file": ".env",
+      "format": "dotenv",
+      "disabled": true,`) || strings.Contains(string(raw), "api-") {
+		t.Fatalf("expected the templated key to be kept: %s", raw)
+	}
+	if _, err := SetMappingDisabled(write(t, shared, `{"unknown":1}`), "api-env-dev", true); err == nil || !strings.Contains(err.Error(), "decode config json") {
+		t.Fatalf("expected local overlay error, got %v", err)
+	}
+
+	for raw, wantErr := range map[string]string{
+		`{"{{app}}-dev":{"file":"a"}}`:                                         `mapping "{{app}}-dev": unknown var "app"`,
+		`{"{{service}}-dev":{"file":"a"},"bweb-dev":{"file":"b"}}`:             `mapping keys "bweb-dev" and "{{service}}-dev" both resolve to "bweb-dev"`,
+		`{"a-dev":{"file":"a","format":"dotenv","compose":["{{other}}-dev"]}}`: `mapping "a-dev": compose reference "{{other}}-dev": unknown var "other"`,
+		`{"{{service}}":{"file":"a"}}`:                                         `mapping key "bweb" must end with -dev`,
+	} {
+		path := write(t, `{"organization_id":"org","project_id":"proj","region":"fr-par","vars":{"service":"bweb"},"mapping":`+raw+`}`, "")
+		if _, err := Load(filepath.Dir(path), path); err == nil || !strings.Contains(err.Error(), wantErr) {
+			t.Fatalf("%s: expected %q, got %v", raw, wantErr, err)
+		}
+	}
+}
+
+func TestVersionRange(t *testing.T) {
+	r, err := ParseVersionRange(">=1.4.0, <2.0.0, != 1.5.0")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	for version, want := range map[string]bool{
+		"v1.4.0":        true,
+		"1.9.9+build.7": true,
+		"v1.5.0":        false,
+		"v1.3.9":        false,
+		"v2.0.0":        false,
+		"v2.0.0-rc.1":   true,
+		"v1.4.0-rc.1":   false,
+	} {
+		allowed, ok := r.Allows(version)
+		if !ok || allowed != want {
+			t.Fatalf("%s: got allowed=%v ok=%v, want %v", version, allowed, ok, want)
+		}
+	}
+	for _, version := range []string{"dev", "v1.2", "v1.x.0", "v01.2.3", "v-1.2.3"} {
+		if _, ok := r.Allows(version); ok || IsReleaseVersion(version) {
+			t.Fatalf("%s: expected not a release version", version)
+		}
+	}
+
+	exact, _ := ParseVersionRange("1.2.3")
+	le, _ := ParseVersionRange("<=1.2.3-b")
+	gt, _ := ParseVersionRange(">1.2.3-a")
+	for _, tc := range []struct {
+		r       VersionRange
+		version string
+		want    bool
+	}{
+		{exact, "1.2.3", true},
+		{exact, "1.2.4", false},
+		{le, "1.2.3-a", true},
+		{le, "1.2.3", false},
+		{gt, "1.2.3-b", true},
+		{gt, "1.2.3-a", false},
+	} {
+		if allowed, _ := tc.r.Allows(tc.version); allowed != tc.want {
+			t.Fatalf("%v %s: got %v", tc.r, tc.version, allowed)
+		}
+	}
+
+	for raw, want := range map[string]string{
+		">=1.4.0, <2.0.0, != 1.5.0": ">= 1.4.0",
+		"1.2.3-rc.1":                ">= 1.2.3-rc.1",
+		">1.2.0, >=1.2.0, >=1.1.0":  "> 1.2.0",
+		">=1.2.0, >1.1.0":           ">= 1.2.0",
+		"<2.0.0":                    "",
+	} {
+		versionRange, _ := ParseVersionRange(raw)
+		if minimum, ok := versionRange.Minimum(); minimum != want || ok != (want != "") {
+			t.Fatalf("%q: got minimum %q %v, want %q", raw, minimum, ok, want)
+		}
+	}
+
+	for _, bad := range []string{"", ">=1.0.0,", ">=1.0", "~1.0.0"} {
+		if _, err := ParseVersionRange(bad); err == nil {
+			t.Fatalf("%q: expected error", bad)
+		}
+	}
+
+	dir := t.TempDir()
+	cfgPath := filepath.Join(dir, DefaultConfigName)
+	if err := os.WriteFile(cfgPath, []byte(`{"organization_id":"o","project_id":"p","region":"fr-par","required_version":" >=1.0.0 ","mapping":{"a-dev":{"file":"x"}}}`), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	loaded, err := Load(dir, "")
+	if err != nil || loaded.Cfg.RequiredVersion != ">=1.0.0" {
+		t.Fatalf("unexpected load: %#v %v", loaded, err)
+	}
+	if err := os.WriteFile(cfgPath, []byte(`{"organization_id":"o","project_id":"p","region":"fr-par","required_version":"latest","mapping":{"a-dev":{"file":"x"}}}`), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if _, err := Load(dir, ""); err == nil || !strings.Contains(err.Error(), "required_version: invalid version range") {
+		t.Fatalf("expected required_version error, got %v", err)
+	}
+}
+
+func TestLoad_Mtime(t *testing.T) {
+	dir := t.TempDir()
+	cfgPath := filepath.Join(dir, DefaultConfigName)
+	for _, strategy := range []string{MtimeNow, MtimePreserve, MtimeRemote} {
+		raw := `{"organization_id":"o","project_id":"p","region":"fr-par","mtime":"` + strategy + `","mapping":{"a-dev":{"file":"x"}}}`
+		if err := os.WriteFile(cfgPath, []byte(raw), 0o644); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+		if loaded, err := Load(dir, ""); err != nil || loaded.Cfg.Mtime != strategy {
+			t.Fatalf("%s: unexpected load: %#v %v", strategy, loaded, err)
+		}
+	}
+	if err := os.WriteFile(cfgPath, []byte(`{"organization_id":"o","project_id":"p","region":"fr-par","mtime":"touch","mapping":{"a-dev":{"file":"x"}}}`), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if _, err := Load(dir, ""); err == nil || !strings.Contains(err.Error(), `invalid mtime "touch" (expected now, preserve or remote)`) {
+		t.Fatalf("expected mtime error, got %v", err)
+	}
+}
+
+func TestLoad_ExpireAfter(t *testing.T) {
+	dir := t.TempDir()
+	cfgPath := filepath.Join(dir, DefaultConfigName)
+	for value, wantErr := range map[string]string{"8h": "", "1d": "", "soon": `expire_after: invalid age "soon"`, "0h": "expire_after: age must be positive"} {
+		raw := `{"organization_id":"o","project_id":"p","region":"fr-par","expire_after":"` + value + `","mapping":{"a-dev":{"file":"x"}}}`
+		if err := os.WriteFile(cfgPath, []byte(raw), 0o644); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+		loaded, err := Load(dir, "")
+		if wantErr == "" && (err != nil || loaded.Cfg.ExpireAfter != value) || wantErr != "" && (err == nil || !strings.Contains(err.Error(), wantErr)) {
+			t.Fatalf("%s: expected %q, got %v", value, wantErr, err)
+		}
+	}
+}
+
+func TestEnvironments(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, DefaultConfigName)
+	manifest := `{
+  "organization_id": "org",
+  "project_id": "proj",
+  "region": "fr-par",
+  "vars": {"stage": "dev"},
+  "mapping": {"app-{{stage}}-dev": {"file": ".env", "format": "dotenv"}, "db-dev": {"file": "db"}},
+  "environments": {
+    "staging-sandbox": {
+      "project_id": "sandbox",
+      "region": "nl-ams",
+      "profile": "sandbox",
+      "vars": {"stage": "staging"},
+      "mapping": {"db-dev": {"path": "/sandbox"}, "extra-dev": {"file": "extra"}}
+    }
+  }
+}`
+	if err := os.WriteFile(path, []byte(manifest), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	load := func(env string) (*Loaded, error) {
+		return LoadWithOptions(dir, "", LoadOptions{Environment: env})
+	}
+
+	loaded, err := load("")
+	if err != nil || loaded.Cfg.ProjectID != "proj" || loaded.Cfg.Mapping["db-dev"].Path != "/" || len(loaded.Cfg.Mapping) != 2 {
+		t.Fatalf("unexpected top-level load: %#v %v", loaded, err)
+	}
+	loaded, err = load("staging-sandbox")
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	cfg := loaded.Cfg
+	if cfg.OrganizationID != "org" || cfg.ProjectID != "sandbox" || cfg.Region != "nl-ams" || cfg.Profile != "sandbox" {
+		t.Fatalf("unexpected environment scope: %#v", cfg)
+	}
+	if len(cfg.Mapping) != 3 || cfg.Mapping["app-staging-dev"].File != ".env" || cfg.Mapping["db-dev"].Path != "/sandbox" || cfg.Mapping["db-dev"].File != "db" || cfg.Mapping["extra-dev"].File != "extra" {
+		t.Fatalf("unexpected environment mapping: %#v", cfg.Mapping)
+	}
+
+	if _, err := load("prod"); err == nil || err.Error() != `unknown environment "prod" (expected staging-sandbox)` {
+		t.Fatalf("expected unknown environment error, got %v", err)
+	}
+	if err := (&Config{}).selectEnvironment("dev"); err == nil || err.Error() != `unknown environment "dev": the manifest defines no environments` {
+		t.Fatalf("expected no environments error, got %v", err)
+	}
+
+	// The selected environment is validated like the top level.
+	bad := `{"organization_id":"org","project_id":"proj","region":"fr-par","mapping":{},"environments":{"x":{"mapping":{"prod":{"file":"p"}}}}}`
+	if err := os.WriteFile(path, []byte(bad), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if _, err := load(""); err == nil || err.Error() != "mapping is empty; select one of the environments with --env" {
+		t.Fatalf("expected empty mapping hint, got %v", err)
+	}
+	if _, err := load("x"); err == nil || !strings.Contains(err.Error(), `mapping key "prod" must end with -dev`) {
+		t.Fatalf("expected -dev error, got %v", err)
+	}
+
+	if err := os.WriteFile(path, []byte(manifest), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, LocalConfigName), []byte(`{"environments":{"mine":{}}}`), 0o600); err != nil {
+		t.Fatalf("write local: %v", err)
+	}
+	if _, err := load(""); err == nil || !strings.Contains(err.Error(), "environments belong in the shared manifest") {
+		t.Fatalf("expected local environments error, got %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, LocalConfigName), []byte(`{"region":"pl-waw"}`), 0o600); err != nil {
+		t.Fatalf("write local: %v", err)
+	}
+	if loaded, err := load("staging-sandbox"); err != nil || loaded.Cfg.Region != "pl-waw" || loaded.Cfg.ProjectID != "sandbox" {
+		t.Fatalf("expected the local overlay to win over the environment: %#v %v", loaded, err)
+	}
+
+	origins, err := Explain(path, "staging-sandbox")
+	if err != nil {
+		t.Fatalf("Explain: %v", err)
+	}
+	got := make(map[string]Origin, len(origins))
+	for _, o := range origins {
+		got[o.Field] = o
+	}
+	local := filepath.Join(dir, LocalConfigName)
+	for field, origin := range map[string]Origin{
+		"organization_id":              {Field: "organization_id", Value: "org", Source: path + ":2"},
+		"project_id":                   {Field: "project_id", Value: "sandbox", Source: path + ":9 (environment staging-sandbox)"},
+		"region":                       {Field: "region", Value: "pl-waw", Source: local + ":1"},
+		"mapping.db-dev.path":          {Field: "mapping.db-dev.path", Value: "/sandbox", Source: path + ":13 (environment staging-sandbox)"},
+		"mapping.app-staging-dev.file": {Field: "mapping.app-staging-dev.file", Value: ".env", Source: path + ":6"},
+	} {
+		if got[field] != origin {
+			t.Fatalf("%s: got %#v want %#v", field, got[field], origin)
+		}
+	}
+	if _, err := Explain(path, "prod"); err == nil {
+		t.Fatal("expected unknown environment error")
+	}
+	if err := os.WriteFile(filepath.Join(dir, LocalConfigName), []byte(`{"environments":{}}`), 0o600); err != nil {
+		t.Fatalf("write local: %v", err)
+	}
+	if _, err := Explain(path, "staging-sandbox"); err == nil {
+		t.Fatal("expected local environments error")
+	}
+}
+
+func TestYAMLConfig(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".scw.yaml")
+	manifest := `# shared by the team
+organization_id: org
+project_id: proj
+region: fr-par
+mapping:
+  app-dev:
+    file: .env
+    format: dotenv
+  db-dev:
+    file: db
+    format: dotenv
+    exclude_keys:
+      - DEBUG
+`
+	if err := os.WriteFile(path, []byte(manifest), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	nested := filepath.Join(dir, "sub")
+	if err := os.MkdirAll(nested, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	loaded, err := Load(nested, "")
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if loaded.Path != path || loaded.Cfg.ProjectID != "proj" || loaded.Cfg.Mapping["app-dev"].Format != "dotenv" || loaded.Cfg.Mapping["db-dev"].Keys == nil {
+		t.Fatalf("unexpected yaml load: %#v", loaded)
+	}
+
+	local := filepath.Join(dir, ".scw.local.yaml")
+	if err := os.WriteFile(local, []byte("region: nl-ams\n"), 0o600); err != nil {
+		t.Fatalf("write local: %v", err)
+	}
+	if loaded, err := Load(dir, ""); err != nil || loaded.LocalPath != local || loaded.Cfg.Region != "nl-ams" {
+		t.Fatalf("expected the yaml overlay: %#v %v", loaded, err)
+	}
+	origins, err := Explain(path, "")
+	if err != nil {
+		t.Fatalf("Explain: %v", err)
+	}
+	for _, o := range origins {
+		if (o.Field == "project_id" && o.Source != path) || (o.Field == "region" && o.Source != local) {
+			t.Fatalf("unexpected yaml source: %#v", o)
+		}
+	}
+	if err := os.WriteFile(local, []byte("\tregion: nl-ams\n"), 0o600); err != nil {
+		t.Fatalf("write local: %v", err)
+	}
+	if _, err := Load(dir, ""); err == nil || !strings.Contains(err.Error(), local+": decode config yaml: line 1") {
+		t.Fatalf("expected local yaml error, got %v", err)
+	}
+	if err := os.Remove(local); err != nil {
+		t.Fatalf("remove local: %v", err)
+	}
+
+	// The same strict validation applies to YAML manifests.
+	for body, want := range map[string]string{
+		"region: fr-par\n  bad: indent\n": "decode config yaml: line 2",
+		"organization_id: org\nproject_id: proj\nregion: fr-par\nunknown: 1\nmapping:\n  a-dev:\n    file: x\n": `unknown field "unknown"`,
+		"organization_id: org\nproject_id: proj\nregion: fr-par\nmapping:\n  prod:\n    file: x\n":              `mapping key "prod" must end with -dev`,
+	} {
+		if err := os.WriteFile(path, []byte(body), 0o600); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+		if _, err := Load(dir, ""); err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("%q: expected %q, got %v", body, want, err)
+		}
+		if _, err := Explain(path, ""); err == nil {
+			t.Fatalf("%q: expected Explain error", body)
+		}
+	}
+	if err := os.WriteFile(path, []byte(manifest), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	// dev-vault writes JSON, so it leaves YAML manifests to the user.
+	if _, err := SetMappingDisabled(path, "app-dev", true); err == nil || !strings.Contains(err.Error(), "only rewrites JSON manifests") {
+		t.Fatalf("expected disable to refuse yaml, got %v", err)
+	}
+	if err := AddMapping(path, "new-dev", MappingEntry{File: "n"}); err == nil {
+		t.Fatal("expected add to refuse yaml")
+	}
+	if _, err := FormatFile(path, false); err == nil {
+		t.Fatal("expected fmt to refuse yaml")
+	}
+
+	// Two manifests in one directory are ambiguous.
+	if err := os.WriteFile(filepath.Join(dir, ".scw.yml"), []byte(manifest), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if _, err := Load(nested, ""); err == nil || !strings.Contains(err.Error(), "are both manifests; keep one") {
+		t.Fatalf("expected ambiguity error, got %v", err)
+	}
+	if _, err := FindConfigPath(t.TempDir()); err == nil || !strings.HasPrefix(err.Error(), ".scw.json or .scw.yaml or .scw.yml not found from ") {
+		t.Fatalf("unexpected not-found error: %v", err)
+	}
+}
@@ -0,0 +1,57 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bsmartlabs/dev-vault/internal/secrettype"
+)
+
+// MappingDefaults holds the values mapping entries take for the fields they
+// leave unset, so a manifest need not repeat them on every entry.
+type MappingDefaults struct {
+	Format MappingFormat `json:"format,omitempty"`
+	Path   string        `json:"path,omitempty"`
+	Mode   MappingMode   `json:"mode,omitempty"` // for entries that are not pull-only by nature
+	Type   string        `json:"type,omitempty"`
+}
+
+// validate checks the defaults like the entry fields they fill in.
+func (d *MappingDefaults) validate() error {
+	switch d.Format {
+	case "", MappingFormatRaw, MappingFormatDotenv, MappingFormatYAML, MappingFormatJSON, MappingFormatTOML:
+	default:
+		return fmt.Errorf("mapping_defaults: invalid format %q", d.Format)
+	}
+	if d.Path != "" && !strings.HasPrefix(d.Path, "/") {
+		return fmt.Errorf("mapping_defaults: path must start with '/', got %q", d.Path)
+	}
+	switch d.Mode {
+	case "", MappingModePull, MappingModePush, MappingModeBoth:
+	default:
+		return fmt.Errorf("mapping_defaults: invalid mode %q", d.Mode)
+	}
+	if d.Type = strings.TrimSpace(d.Type); d.Type != "" && !secrettype.IsValid(d.Type) {
+		return fmt.Errorf("mapping_defaults: invalid type %q", d.Type)
+	}
+	return nil
+}
+
+// apply fills the fields entry leaves unset. Split-file entries keep their
+// own format and type, which files fixes, and the mode is left for
+// normalization to pick, since entries that cannot be pushed default to pull
+// whatever the defaults say.
+func (d *MappingDefaults) apply(entry *MappingEntry) {
+	if entry.Path == "" {
+		entry.Path = d.Path
+	}
+	if len(entry.Files) > 0 {
+		return
+	}
+	if entry.Format == "" {
+		entry.Format = d.Format
+	}
+	if strings.TrimSpace(entry.Type) == "" {
+		entry.Type = d.Type
+	}
+}
@@ -0,0 +1,153 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// SetMappingDisabled toggles mapping[name].disabled in the manifest at path
+// and reports whether the file changed. The manifest is rewritten with
+// two-space indentation and sorted mapping keys; values the user wrote are
+// kept as written, without normalized defaults. YAML manifests are refused
+// rather than rewritten as JSON.
+func SetMappingDisabled(path, name string, disabled bool) (bool, error) {
+	return setMappingDisabled(path, name, disabled, defaultConfigDeps)
+}
+
+func setMappingDisabled(path, name string, disabled bool, deps configDeps) (bool, error) {
+	if err := checkRewritable(path); err != nil {
+		return false, err
+	}
+	info, _, cfg, err := readConfigFile(path, deps)
+	if err != nil {
+		return false, err
+	}
+	key := name
+	entry, ok := cfg.Mapping[key]
+	if !ok {
+		_, _, overlay, err := readLocalOverlay(path, deps)
+		if err != nil {
+			return false, err
+		}
+		if key, ok = mappingKey(cfg, overlay, name); !ok {
+			return false, fmt.Errorf("secret not found in mapping: %s", name)
+		}
+		entry = cfg.Mapping[key]
+	}
+	if entry.Disabled == disabled {
+		return false, nil
+	}
+	entry.Disabled = disabled
+	cfg.Mapping[key] = entry
+
+	out, err := Encode(cfg)
+	if err != nil {
+		return false, err
+	}
+	if err := deps.writeFile(path, out, info.Mode().Perm()); err != nil {
+		return false, fmt.Errorf("write config: %w", err)
+	}
+	return true, nil
+}
+
+// AddMapping adds mapping[name] to the manifest at path, rewriting it like
+// SetMappingDisabled. It refuses a name the manifest already maps and an entry
+// that would leave the manifest invalid.
+func AddMapping(path, name string, entry MappingEntry) error {
+	return addMapping(path, name, entry, defaultConfigDeps)
+}
+
+func addMapping(path, name string, entry MappingEntry, deps configDeps) error {
+	if err := checkRewritable(path); err != nil {
+		return err
+	}
+	info, _, cfg, err := readConfigFile(path, deps)
+	if err != nil {
+		return err
+	}
+	if _, ok := cfg.Mapping[name]; ok {
+		return fmt.Errorf("mapping %q already exists", name)
+	}
+	if cfg.Mapping == nil {
+		cfg.Mapping = make(map[string]MappingEntry, 1)
+	}
+	cfg.Mapping[name] = entry
+
+	out, err := Encode(cfg)
+	if err != nil {
+		return err
+	}
+	if err := deps.writeFile(path, out, info.Mode().Perm()); err != nil {
+		return fmt.Errorf("write config: %w", err)
+	}
+	return nil
+}
+
+// FormatFile rewrites the manifest at path in canonical form (see Encode)
+// and reports whether it was not canonical. With write=false the file is
+// only checked.
+func FormatFile(path string, write bool) (bool, error) {
+	return formatFile(path, write, defaultConfigDeps)
+}
+
+func formatFile(path string, write bool, deps configDeps) (bool, error) {
+	if err := checkRewritable(path); err != nil {
+		return false, err
+	}
+	info, raw, cfg, err := readConfigFile(path, deps)
+	if err != nil {
+		return false, err
+	}
+	out, err := Encode(cfg)
+	if err != nil {
+		return false, err
+	}
+	if bytes.Equal(raw, out) {
+		return false, nil
+	}
+	if write {
+		if err := deps.writeFile(path, out, info.Mode().Perm()); err != nil {
+			return false, fmt.Errorf("write config: %w", err)
+		}
+	}
+	return true, nil
+}
+
+func readConfigFile(path string, deps configDeps) (os.FileInfo, []byte, Config, error) {
+	info, err := deps.statFile(path)
+	if err != nil {
+		return nil, nil, Config{}, fmt.Errorf("read config: %w", err)
+	}
+	raw, err := deps.readFile(path)
+	if err != nil {
+		return nil, nil, Config{}, fmt.Errorf("read config: %w", err)
+	}
+	if raw, err = manifestJSON(path, raw); err != nil {
+		return nil, nil, Config{}, err
+	}
+	cfg, err := decodeConfig(raw)
+	if err != nil {
+		return nil, nil, Config{}, err
+	}
+	return info, raw, cfg, nil
+}
+
+// Decode strictly decodes a manifest or template without normalizing it.
+func Decode(raw []byte) (Config, error) {
+	return decodeConfig(raw)
+}
+
+// Encode validates cfg and renders it the way dev-vault writes manifests:
+// two-space indentation, sorted mapping keys, and a trailing newline. The
+// encoded form keeps the values as given, without normalized defaults.
+func Encode(cfg Config) ([]byte, error) {
+	out, _ := json.MarshalIndent(cfg, "", "  ") // plain strings, ints, bools, slices, and maps always encode
+	// Validate a decoded copy: normalization fills defaults in place.
+	check, _ := decodeConfig(out) // Config always round-trips through its own encoding
+	if _, err := check.normalizeAndValidate(); err != nil {
+		return nil, err
+	}
+	return append(out, '\n'), nil
+}
@@ -0,0 +1,49 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Environment is one named target of a manifest shared by several projects,
+// such as "dev" or "staging-sandbox". Selected with --env, its non-empty
+// fields replace the top-level ones, its vars are merged by name, and its
+// mapping entries are merged into the top-level mapping like a local overlay.
+type Environment struct {
+	OrganizationID string                  `json:"organization_id,omitempty"`
+	ProjectID      string                  `json:"project_id,omitempty"`
+	Region         string                  `json:"region,omitempty"`
+	Profile        string                  `json:"profile,omitempty"`
+	Vars           map[string]string       `json:"vars,omitempty"`
+	Mapping        map[string]MappingEntry `json:"mapping,omitempty"`
+}
+
+// selectEnvironment applies the environment called name to c. An empty name
+// keeps the top-level values.
+func (c *Config) selectEnvironment(name string) error {
+	if name == "" {
+		return nil
+	}
+	env, ok := c.Environments[name]
+	if !ok {
+		if len(c.Environments) == 0 {
+			return fmt.Errorf("unknown environment %q: the manifest defines no environments", name)
+		}
+		names := make([]string, 0, len(c.Environments))
+		for defined := range c.Environments {
+			names = append(names, defined)
+		}
+		sort.Strings(names)
+		return fmt.Errorf("unknown environment %q (expected %s)", name, strings.Join(names, ", "))
+	}
+	c.applyOverlay(Config{
+		OrganizationID: env.OrganizationID,
+		ProjectID:      env.ProjectID,
+		Region:         env.Region,
+		Profile:        env.Profile,
+		Vars:           env.Vars,
+		Mapping:        env.Mapping,
+	})
+	return nil
+}
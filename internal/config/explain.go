@@ -0,0 +1,140 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// SourceDefault marks a value filled in by normalization rather than written
+// in the manifest.
+const SourceDefault = "default"
+
+// Origin is one effective manifest value and where it came from: the
+// "<file>:<line>" that sets it, or SourceDefault.
+type Origin struct {
+	Field  string `json:"field"`
+	Value  string `json:"value"`
+	Source string `json:"source"`
+}
+
+// Explain loads the manifest at path the way Load does, including the
+// selected environment and the local overlay, and lists every effective value
+// in manifest field order. Fields are dotted paths such as
+// mapping.app-env-dev.mode or policy.required_tags[0].
+func Explain(path, environment string) ([]Origin, error) {
+	return explain(path, environment, defaultConfigDeps)
+}
+
+func explain(path, environment string, deps configDeps) ([]Origin, error) {
+	_, raw, cfg, err := readConfigFile(path, deps)
+	if err != nil {
+		return nil, err
+	}
+	if err := cfg.selectEnvironment(environment); err != nil {
+		return nil, err
+	}
+	localPath, localRaw, overlay, err := readLocalOverlay(path, deps)
+	if err != nil {
+		return nil, err
+	}
+	cfg.applyOverlay(overlay)
+	if _, err := cfg.normalizeAndValidate(); err != nil {
+		return nil, err
+	}
+	if cfg.Policy != nil {
+		cfg.Policy.resolveBundle(filepath.Dir(path))
+	}
+
+	sources := make(map[string]string)
+	// Both files already decoded strictly above, so the walks cannot fail.
+	// Templated mapping keys are resolved to match the effective fields.
+	// The selected environment's values replace the top-level ones wherever
+	// either appears in the file.
+	selected := make(map[string]string)
+	_ = walkScalars(raw, func(field string, _ any, end int64) {
+		source := sourceLine(path, raw, end)
+		if rest, ok := strings.CutPrefix(field, "environments."+environment+"."); ok && environment != "" {
+			rest, _ = expandNameRefs(rest, cfg.Vars)
+			selected[rest] = source + " (environment " + environment + ")"
+		}
+		field, _ = expandNameRefs(field, cfg.Vars)
+		sources[field] = source
+	})
+	for field, source := range selected {
+		sources[field] = source
+	}
+	_ = walkScalars(localRaw, func(field string, value any, end int64) {
+		// Empty strings and false leave the shared value in place.
+		if value != "" && value != false {
+			field, _ = expandNameRefs(field, cfg.Vars)
+			sources[field] = sourceLine(localPath, localRaw, end)
+		}
+	})
+	var defaulted map[string]string
+	if d := cfg.MappingDefaults; d != nil {
+		defaulted = map[string]string{"format": string(d.Format), "path": d.Path, "mode": string(d.Mode), "type": d.Type}
+	}
+	effective, _ := json.Marshal(cfg) // Config always encodes; see Encode
+	var origins []Origin
+	_ = walkScalars(effective, func(field string, value any, _ int64) {
+		source, ok := sources[field]
+		if !ok {
+			source = SourceDefault
+			// An entry field left unset may hold the mapping_defaults value.
+			if rest, isEntry := strings.CutPrefix(field, "mapping."); isEntry {
+				name := rest[strings.LastIndex(rest, ".")+1:]
+				if from, set := sources["mapping_defaults."+name]; set && defaulted[name] == fmt.Sprint(value) {
+					source = from + " (mapping_defaults)"
+				}
+			}
+		}
+		origins = append(origins, Origin{Field: field, Value: fmt.Sprint(value), Source: source})
+	})
+	return origins, nil
+}
+
+// walkScalars visits every scalar in the JSON document in document order with
+// its dotted field path and the input offset just past the value.
+func walkScalars(raw []byte, visit func(field string, value any, end int64)) error {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var walk func(field string) error
+	walk = func(field string) error {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		switch tok {
+		case json.Delim('{'):
+			for dec.More() {
+				key, err := dec.Token()
+				if err != nil {
+					return err
+				}
+				child := key.(string)
+				if field != "" {
+					child = field + "." + child
+				}
+				if err := walk(child); err != nil {
+					return err
+				}
+			}
+			_, err = dec.Token()
+			return err
+		case json.Delim('['):
+			for i := 0; dec.More(); i++ {
+				if err := walk(fmt.Sprintf("%s[%d]", field, i)); err != nil {
+					return err
+				}
+			}
+			_, err = dec.Token()
+			return err
+		}
+		visit(field, tok, dec.InputOffset())
+		return nil
+	}
+	return walk("")
+}
@@ -0,0 +1,53 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// normalizeFiles checks a mapping that splits a key_value secret across
+// files. Each value is written as-is, so the entry takes no format and none
+// of the options that rewrite a single payload.
+func normalizeFiles(name string, entry *MappingEntry) error {
+	if entry.File != "" {
+		return fmt.Errorf("mapping %q: file and files are mutually exclusive", name)
+	}
+	if entry.Format != "" && entry.Format != MappingFormatRaw {
+		return fmt.Errorf("mapping %q: files writes each value as-is and takes no format, got %q", name, entry.Format)
+	}
+	if entry.Type = strings.TrimSpace(entry.Type); entry.Type != "" && entry.Type != "key_value" {
+		return fmt.Errorf("mapping %q: files requires type key_value, got %q", name, entry.Type)
+	}
+	if entry.Substitute {
+		return fmt.Errorf("mapping %q: files cannot be combined with substitute", name)
+	}
+
+	files := make(map[string]string, len(entry.Files))
+	owners := make(map[string]string, len(entry.Files))
+	keys := make([]string, 0, len(entry.Files))
+	for key := range entry.Files {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		file := strings.TrimSpace(entry.Files[key])
+		switch {
+		case key == "" || strings.TrimSpace(key) != key:
+			return fmt.Errorf("mapping %q: files: invalid key %q", name, key)
+		case file == "":
+			return fmt.Errorf("mapping %q: files: key %s has no file", name, key)
+		case filepath.IsAbs(file):
+			return fmt.Errorf("mapping %q: files: %s must be relative, got %q", name, key, file)
+		}
+		clean := filepath.Clean(file)
+		if owner, ok := owners[clean]; ok {
+			return fmt.Errorf("mapping %q: files: %s and %s both write %q", name, owner, key, file)
+		}
+		owners[clean] = key
+		files[key] = file
+	}
+	entry.Files = files
+	return nil
+}
@@ -0,0 +1,146 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strings"
+)
+
+// LocalConfigName is the per-user overlay paired with DefaultConfigName.
+const LocalConfigName = ".scw.local.json"
+
+// LocalPath returns the per-user overlay for the manifest at path: the same
+// name with ".local" before the extension, so .scw.json pairs with
+// .scw.local.json and .scw.yaml with .scw.local.yaml.
+func LocalPath(path string) string {
+	ext := filepath.Ext(path)
+	return strings.TrimSuffix(path, ext) + ".local" + ext
+}
+
+// readLocalOverlay decodes the overlay next to the manifest at path. It
+// returns an empty overlay path when the user has none.
+func readLocalOverlay(path string, deps configDeps) (string, []byte, Config, error) {
+	local := LocalPath(path)
+	raw, err := deps.readFile(local)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", nil, Config{}, nil
+	}
+	if err != nil {
+		return "", nil, Config{}, fmt.Errorf("read local config: %w", err)
+	}
+	if raw, err = manifestJSON(local, raw); err != nil {
+		return "", nil, Config{}, fmt.Errorf("%s: %w", local, err)
+	}
+	overlay, err := decodeConfig(raw)
+	if err != nil {
+		return "", nil, Config{}, fmt.Errorf("%s: %w", local, err)
+	}
+	// Shared rules stay shared: a personal file must not loosen them.
+	if overlay.Policy != nil || overlay.Naming != nil || overlay.RequiredVersion != "" {
+		return "", nil, Config{}, fmt.Errorf("%s: policy, naming, and required_version cannot be overridden locally", local)
+	}
+	if overlay.Environments != nil {
+		return "", nil, Config{}, fmt.Errorf("%s: environments belong in the shared manifest", local)
+	}
+	return local, raw, overlay, nil
+}
+
+// applyOverlay merges a local overlay into c before validation. Non-empty
+// scalar fields replace the shared ones, mapping entries are merged field by
+// field, names the shared manifest lacks are added, and vars are merged by
+// name. An overlay can disable a shared entry but not re-enable one.
+func (c *Config) applyOverlay(overlay Config) {
+	if overlay.Provider != "" {
+		c.Provider = overlay.Provider
+	}
+	if overlay.OrganizationID != "" {
+		c.OrganizationID = overlay.OrganizationID
+	}
+	if overlay.ProjectID != "" {
+		c.ProjectID = overlay.ProjectID
+	}
+	if overlay.Region != "" {
+		c.Region = overlay.Region
+	}
+	if overlay.Profile != "" {
+		c.Profile = overlay.Profile
+	}
+	if overlay.Mtime != "" {
+		c.Mtime = overlay.Mtime
+	}
+	if overlay.ExpireAfter != "" {
+		c.ExpireAfter = overlay.ExpireAfter
+	}
+	if overlay.Budget != nil {
+		c.Budget = overlay.Budget
+	}
+	if overlay.MappingDefaults != nil {
+		c.MappingDefaults = overlay.MappingDefaults
+	}
+	if len(overlay.Vars) > 0 && c.Vars == nil {
+		c.Vars = make(map[string]string, len(overlay.Vars))
+	}
+	for name, value := range overlay.Vars {
+		c.Vars[name] = value
+	}
+	if len(overlay.Mapping) > 0 && c.Mapping == nil {
+		c.Mapping = make(map[string]MappingEntry, len(overlay.Mapping))
+	}
+	for name, entry := range overlay.Mapping {
+		shared, ok := c.Mapping[name]
+		if !ok {
+			c.Mapping[name] = entry
+			continue
+		}
+		// file and files replace each other.
+		if entry.File != "" {
+			shared.File, shared.Files = entry.File, nil
+		}
+		if len(entry.Files) > 0 {
+			shared.File, shared.Files = "", entry.Files
+		}
+		if entry.Format != "" {
+			shared.Format = entry.Format
+		}
+		if entry.Path != "" {
+			shared.Path = entry.Path
+		}
+		if entry.Mode != "" {
+			shared.Mode = entry.Mode
+		}
+		if entry.Type != "" {
+			shared.Type = entry.Type
+		}
+		if len(entry.Compose) > 0 {
+			shared.Compose = entry.Compose
+		}
+		if entry.KeyPrefix != "" {
+			shared.KeyPrefix = entry.KeyPrefix
+		}
+		if entry.KeySuffix != "" {
+			shared.KeySuffix = entry.KeySuffix
+		}
+		if entry.Keys != nil {
+			shared.Keys = entry.Keys
+		}
+		if len(entry.ExcludeKeys) > 0 {
+			shared.ExcludeKeys = entry.ExcludeKeys
+		}
+		if entry.Profile != "" {
+			shared.Profile = entry.Profile
+		}
+		if entry.Revision != 0 {
+			shared.Revision = entry.Revision
+		}
+		if entry.TemplateFile != "" {
+			shared.TemplateFile = entry.TemplateFile
+		}
+		shared.Disabled = shared.Disabled || entry.Disabled
+		shared.Substitute = shared.Substitute || entry.Substitute
+		shared.Provenance = shared.Provenance || entry.Provenance
+		shared.PreserveLayout = shared.PreserveLayout || entry.PreserveLayout
+		c.Mapping[name] = shared
+	}
+}
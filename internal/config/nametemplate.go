@@ -0,0 +1,82 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// nameRefPattern matches a {{var}} reference in a mapping key, so a manifest
+// shared across repositories can write "{{service}}-env-dev" and change only
+// its vars block.
+var nameRefPattern = regexp.MustCompile(`\{\{([^{}]*)\}\}`)
+
+// expandNameRefs resolves the {{var}} references in s from vars. It returns
+// the first unknown var name, or "" when every reference resolved.
+func expandNameRefs(s string, vars map[string]string) (string, string) {
+	missing := ""
+	out := nameRefPattern.ReplaceAllStringFunc(s, func(ref string) string {
+		name := strings.TrimSpace(ref[2 : len(ref)-2])
+		value, ok := vars[name]
+		if !ok && missing == "" {
+			missing = name
+		}
+		return value
+	})
+	return out, missing
+}
+
+// expandNameTemplates rekeys mapping by resolved secret name and resolves
+// compose references the same way, so nothing past validation sees a
+// template.
+func expandNameTemplates(mapping map[string]MappingEntry, vars map[string]string) (map[string]MappingEntry, error) {
+	keys := make([]string, 0, len(mapping))
+	for key := range mapping {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	out := make(map[string]MappingEntry, len(mapping))
+	written := make(map[string]string, len(mapping))
+	for _, key := range keys {
+		name, missing := expandNameRefs(key, vars)
+		if missing != "" {
+			return nil, fmt.Errorf("mapping %q: unknown var %q", key, missing)
+		}
+		if other, ok := written[name]; ok {
+			return nil, fmt.Errorf("mapping keys %q and %q both resolve to %q", other, key, name)
+		}
+		written[name] = key
+
+		entry := mapping[key]
+		if len(entry.Compose) > 0 {
+			refs := make([]string, len(entry.Compose))
+			for i, ref := range entry.Compose {
+				if refs[i], missing = expandNameRefs(ref, vars); missing != "" {
+					return nil, fmt.Errorf("mapping %q: compose reference %q: unknown var %q", key, ref, missing)
+				}
+			}
+			entry.Compose = refs
+		}
+		out[name] = entry
+	}
+	return out, nil
+}
+
+// mappingKey finds the key written in the manifest for a resolved secret
+// name, with vars from the manifest and its local overlay.
+func mappingKey(cfg, overlay Config, name string) (string, bool) {
+	vars := make(map[string]string, len(cfg.Vars)+len(overlay.Vars))
+	for _, source := range []map[string]string{cfg.Vars, overlay.Vars} {
+		for key, value := range source {
+			vars[key] = value
+		}
+	}
+	for key := range cfg.Mapping {
+		if resolved, missing := expandNameRefs(key, vars); missing == "" && resolved == name {
+			return key, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,28 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Naming is the secret naming convention checked by lint-names. Without a
+// naming section the convention is kebab-case only.
+type Naming struct {
+	Patterns       []string `json:"patterns,omitempty"`        // regexps; a name must match at least one
+	RequiredSuffix string   `json:"required_suffix,omitempty"` // e.g. "-env-dev"; must end with -dev
+	KebabCase      bool     `json:"kebab_case,omitempty"`      // lowercase words separated by single dashes
+}
+
+func (n *Naming) normalizeAndValidate() error {
+	for _, pattern := range n.Patterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("patterns: %w", err)
+		}
+	}
+	n.RequiredSuffix = strings.TrimSpace(n.RequiredSuffix)
+	if n.RequiredSuffix != "" && !IsDevSecretName(n.RequiredSuffix) {
+		return fmt.Errorf("required_suffix %q must end with -dev", n.RequiredSuffix)
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+type PolicyLevel string
+
+const (
+	PolicyLevelWarn    PolicyLevel = "warn"
+	PolicyLevelEnforce PolicyLevel = "enforce"
+)
+
+type Policy struct {
+	Level                PolicyLevel `json:"level,omitempty"`                  // warn|enforce (default: warn)
+	MaxSecretAge         string      `json:"max_secret_age,omitempty"`         // e.g. "90d" or "720h"
+	ForbiddenKeyPatterns []string    `json:"forbidden_key_patterns,omitempty"` // regexps matched against payload key names
+	RequiredTags         []string    `json:"required_tags,omitempty"`          // tags every mapped secret must carry
+	MaxPayloadBytes      int         `json:"max_payload_bytes,omitempty"`      // 0 disables the check
+	Rego                 *RegoPolicy `json:"rego,omitempty"`
+}
+
+// DefaultRegoQuery is evaluated when a rego policy does not set "query".
+const DefaultRegoQuery = "data.devvault.push.deny"
+
+type RegoPolicy struct {
+	Bundle string `json:"bundle"`          // .rego file or bundle directory, relative to the file declaring it
+	Query  string `json:"query,omitempty"` // default: data.devvault.push.deny
+}
+
+// LoadPolicyFile reads a standalone policy document (the same shape as the
+// "policy" section of .scw.json) so org-wide rules can be shared across repos.
+func LoadPolicyFile(path string) (*Policy, error) {
+	raw, err := defaultConfigDeps.readFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read policy file: %w", err)
+	}
+
+	var policy Policy
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&policy); err != nil {
+		return nil, fmt.Errorf("decode policy json: %w", err)
+	}
+	var trailing any
+	if err := dec.Decode(&trailing); !errors.Is(err, io.EOF) {
+		return nil, errors.New("decode policy json: trailing data after top-level JSON object")
+	}
+
+	if err := policy.normalizeAndValidate(); err != nil {
+		return nil, fmt.Errorf("policy file %s: %w", path, err)
+	}
+	policy.resolveBundle(filepath.Dir(path))
+	return &policy, nil
+}
+
+func (p *Policy) normalizeAndValidate() error {
+	if p.Level == "" {
+		p.Level = PolicyLevelWarn
+	}
+	switch p.Level {
+	case PolicyLevelWarn, PolicyLevelEnforce:
+	default:
+		return fmt.Errorf("invalid level %q (expected warn or enforce)", p.Level)
+	}
+
+	p.MaxSecretAge = strings.TrimSpace(p.MaxSecretAge)
+	if p.MaxSecretAge != "" {
+		if _, err := ParseAge(p.MaxSecretAge); err != nil {
+			return fmt.Errorf("max_secret_age: %w", err)
+		}
+	}
+
+	for _, pattern := range p.ForbiddenKeyPatterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("forbidden_key_patterns: %w", err)
+		}
+	}
+
+	for _, tag := range p.RequiredTags {
+		if strings.TrimSpace(tag) == "" {
+			return errors.New("required_tags: empty tag")
+		}
+	}
+
+	if p.MaxPayloadBytes < 0 {
+		return fmt.Errorf("max_payload_bytes must not be negative, got %d", p.MaxPayloadBytes)
+	}
+
+	if p.Rego != nil {
+		p.Rego.Bundle = strings.TrimSpace(p.Rego.Bundle)
+		if p.Rego.Bundle == "" {
+			return errors.New("rego: missing required field: bundle")
+		}
+		p.Rego.Query = strings.TrimSpace(p.Rego.Query)
+		if p.Rego.Query == "" {
+			p.Rego.Query = DefaultRegoQuery
+		}
+	}
+	return nil
+}
+
+func (p *Policy) resolveBundle(baseDir string) {
+	if p.Rego != nil && !filepath.IsAbs(p.Rego.Bundle) {
+		p.Rego.Bundle = filepath.Join(baseDir, p.Rego.Bundle)
+	}
+}
@@ -0,0 +1,144 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// UnknownFieldsError reports manifest fields this dev-vault does not know,
+// most likely added by a newer release.
+type UnknownFieldsError struct {
+	Fields []string // dotted paths such as mapping.app-env-dev.chmod
+	// RequiredVersion is the manifest's required_version, which tells which
+	// release the fields need; empty when the manifest sets none.
+	RequiredVersion string
+}
+
+func (e *UnknownFieldsError) Error() string {
+	quoted := make([]string, len(e.Fields))
+	for i, field := range e.Fields {
+		quoted[i] = fmt.Sprintf("%q", field)
+	}
+	if len(quoted) == 1 {
+		return "decode config json: unknown field " + quoted[0]
+	}
+	return "decode config json: unknown fields " + strings.Join(quoted, ", ")
+}
+
+// IgnoredFieldWarning reports an unknown field Load dropped from the manifest
+// at Path under LoadOptions.IgnoreUnknown.
+type IgnoredFieldWarning struct {
+	Field string
+	Path  string
+}
+
+func (w *IgnoredFieldWarning) Error() string {
+	return fmt.Sprintf("ignoring unknown field %q in %s", w.Field, w.Path)
+}
+
+// decodeConfigIgnoringUnknown decodes a manifest like decodeConfig, but drops
+// the fields Config does not have instead of failing, and returns their
+// paths.
+func decodeConfigIgnoringUnknown(raw []byte) (Config, []string, error) {
+	cfg, err := decodeConfig(raw)
+	var unknown *UnknownFieldsError
+	if !errors.As(err, &unknown) {
+		return cfg, nil, err
+	}
+	cfg, _, _ = stripUnknownFields(raw) // decodeConfig already stripped raw once
+	return cfg, unknown.Fields, nil
+}
+
+// stripUnknownFields removes the fields Config does not have from raw and
+// decodes what is left. ok is false when raw has no such fields, or is not a
+// single JSON object to strip them from.
+func stripUnknownFields(raw []byte) (Config, []string, bool) {
+	var tree any
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	if err := dec.Decode(&tree); err != nil || dec.More() {
+		return Config{}, nil, false
+	}
+	fields := pruneUnknown(tree, reflect.TypeOf(Config{}), "")
+	if len(fields) == 0 {
+		return Config{}, nil, false
+	}
+	stripped, _ := json.Marshal(tree) // decoded JSON always encodes
+	var cfg Config
+	dec = json.NewDecoder(bytes.NewReader(stripped))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&cfg); err != nil {
+		return Config{}, nil, false
+	}
+	return cfg, fields, true
+}
+
+// pruneUnknown deletes the object keys of value that type t has no field
+// for, matching names case-insensitively like encoding/json, and returns
+// their dotted paths in order.
+func pruneUnknown(value any, t reflect.Type, path string) []string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	var fields []string
+	switch t.Kind() {
+	case reflect.Struct:
+		object, ok := value.(map[string]any)
+		if !ok {
+			return nil
+		}
+		for _, key := range sortedKeys(object) {
+			field, ok := jsonField(t, key)
+			if !ok {
+				delete(object, key)
+				fields = append(fields, joinField(path, key))
+				continue
+			}
+			fields = append(fields, pruneUnknown(object[key], field.Type, joinField(path, key))...)
+		}
+	case reflect.Map:
+		object, _ := value.(map[string]any)
+		for _, key := range sortedKeys(object) {
+			fields = append(fields, pruneUnknown(object[key], t.Elem(), joinField(path, key))...)
+		}
+	case reflect.Slice:
+		items, _ := value.([]any)
+		for i, item := range items {
+			fields = append(fields, pruneUnknown(item, t.Elem(), fmt.Sprintf("%s[%d]", path, i))...)
+		}
+	}
+	return fields
+}
+
+// jsonField finds the field of t decoded from key. Every manifest field has
+// a json tag naming it.
+func jsonField(t reflect.Type, key string) (reflect.StructField, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if name, _, _ := strings.Cut(field.Tag.Get("json"), ","); strings.EqualFold(name, key) {
+			return field, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+func sortedKeys(object map[string]any) []string {
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func joinField(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
@@ -0,0 +1,161 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// VersionRange is a parsed required_version: comma-separated constraints
+// such as ">=1.4.0, <2.0.0", all of which must hold.
+type VersionRange []versionConstraint
+
+type versionConstraint struct {
+	op      string // one of = != > >= < <=
+	version semver
+}
+
+type semver struct {
+	major, minor, patch int
+	pre                 string
+}
+
+var versionOps = []string{">=", "<=", "!=", ">", "<", "="}
+
+// ParseVersionRange parses a required_version value.
+func ParseVersionRange(s string) (VersionRange, error) {
+	var out VersionRange
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			return nil, fmt.Errorf("invalid version range %q: empty constraint", s)
+		}
+		op := "="
+		for _, candidate := range versionOps {
+			if strings.HasPrefix(part, candidate) {
+				op = candidate
+				part = strings.TrimSpace(part[len(candidate):])
+				break
+			}
+		}
+		v, ok := parseSemver(part)
+		if !ok {
+			return nil, fmt.Errorf("invalid version range %q: %q is not a MAJOR.MINOR.PATCH version", s, part)
+		}
+		out = append(out, versionConstraint{op: op, version: v})
+	}
+	return out, nil
+}
+
+// Allows reports whether version satisfies every constraint. ok is false when
+// version is not a release version (a development build), which no range can
+// be checked against.
+func (r VersionRange) Allows(version string) (allowed bool, ok bool) {
+	v, ok := parseSemver(version)
+	if !ok {
+		return false, false
+	}
+	for _, c := range r {
+		cmp := v.compare(c.version)
+		var hold bool
+		switch c.op {
+		case "=":
+			hold = cmp == 0
+		case "!=":
+			hold = cmp != 0
+		case ">":
+			hold = cmp > 0
+		case ">=":
+			hold = cmp >= 0
+		case "<":
+			hold = cmp < 0
+		default: // "<="
+			hold = cmp <= 0
+		}
+		if !hold {
+			return false, true
+		}
+	}
+	return true, true
+}
+
+// Minimum returns the lowest release the range allows as ">= X.Y.Z" or
+// "> X.Y.Z", from its tightest lower bound; ok is false when it has none.
+func (r VersionRange) Minimum() (minimum string, ok bool) {
+	var best *versionConstraint
+	for i, c := range r {
+		if c.op != "=" && c.op != ">=" && c.op != ">" {
+			continue
+		}
+		if best != nil {
+			// At the same version, > is the tighter bound.
+			if cmp := c.version.compare(best.version); cmp < 0 || (cmp == 0 && c.op != ">") {
+				continue
+			}
+		}
+		best = &r[i]
+	}
+	if best == nil {
+		return "", false
+	}
+	op := ">="
+	if best.op == ">" {
+		op = ">"
+	}
+	return op + " " + best.version.String(), true
+}
+
+// IsReleaseVersion reports whether version is a release build rather than a
+// development build such as "dev".
+func IsReleaseVersion(version string) bool {
+	_, ok := parseSemver(version)
+	return ok
+}
+
+// parseSemver accepts MAJOR.MINOR.PATCH with an optional leading "v", an
+// optional -prerelease, and ignored +build metadata.
+func parseSemver(s string) (semver, bool) {
+	s = strings.TrimPrefix(s, "v")
+	s, _, _ = strings.Cut(s, "+")
+	core, pre, _ := strings.Cut(s, "-")
+	parts := strings.Split(core, ".")
+	if len(parts) != 3 {
+		return semver{}, false
+	}
+	var nums [3]int
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 || p == "" || (len(p) > 1 && p[0] == '0') {
+			return semver{}, false
+		}
+		nums[i] = n
+	}
+	return semver{major: nums[0], minor: nums[1], patch: nums[2], pre: pre}, true
+}
+
+func (v semver) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.major, v.minor, v.patch)
+	if v.pre != "" {
+		s += "-" + v.pre
+	}
+	return s
+}
+
+// compare orders by MAJOR.MINOR.PATCH, then puts a prerelease before its
+// release; prereleases of the same version compare as strings.
+func (v semver) compare(o semver) int {
+	for _, d := range []int{v.major - o.major, v.minor - o.minor, v.patch - o.patch} {
+		if d != 0 {
+			return d
+		}
+	}
+	switch {
+	case v.pre == o.pre:
+		return 0
+	case v.pre == "":
+		return 1
+	case o.pre == "":
+		return -1
+	}
+	return strings.Compare(v.pre, o.pre)
+}
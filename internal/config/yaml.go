@@ -0,0 +1,52 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/bsmartlabs/dev-vault/internal/yamlmap"
+)
+
+// configNames are the manifest names findConfigPath looks for in each
+// directory. A YAML manifest is converted to its JSON form before decoding,
+// so it gets the same strict validation as .scw.json.
+var configNames = []string{DefaultConfigName, ".scw.yaml", ".scw.yml"}
+
+func isYAMLPath(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	return ext == ".yaml" || ext == ".yml"
+}
+
+// manifestJSON returns the manifest read from path as JSON, converting it
+// when path names a YAML file.
+func manifestJSON(path string, raw []byte) ([]byte, error) {
+	if !isYAMLPath(path) {
+		return raw, nil
+	}
+	out, err := yamlmap.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("decode config yaml: %w", err)
+	}
+	return out, nil
+}
+
+// checkRewritable refuses to rewrite a YAML manifest: dev-vault writes
+// manifests as JSON, which would drop the file's comments and layout.
+func checkRewritable(path string) error {
+	if isYAMLPath(path) {
+		return fmt.Errorf("%s is YAML; dev-vault only rewrites JSON manifests, so edit it by hand", path)
+	}
+	return nil
+}
+
+// sourceLine formats the "<file>:<line>" of the value ending at offset end of
+// raw. A YAML manifest is decoded through its JSON form, whose lines do not
+// match the file's, so only the file is given.
+func sourceLine(path string, raw []byte, end int64) string {
+	if isYAMLPath(path) {
+		return path
+	}
+	return fmt.Sprintf("%s:%d", path, 1+bytes.Count(raw[:end], []byte("\n")))
+}
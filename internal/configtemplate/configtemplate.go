@@ -0,0 +1,167 @@
+// Package configtemplate loads organization-published .scw.json templates
+// from a local file, a template catalog directory, or a git repository, and
+// renders them for a new project.
+package configtemplate
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/bsmartlabs/dev-vault/internal/config"
+)
+
+const (
+	// CatalogEnv names the directory or git URL bare template names are
+	// looked up in.
+	CatalogEnv = "DEV_VAULT_TEMPLATES"
+	// DefaultFile is read when a template reference points at a directory or
+	// at a git repository without a #file fragment.
+	DefaultFile = "template.json"
+	// NamePlaceholder is replaced with the project name everywhere in the
+	// template, so mapping keys and files can follow a naming convention.
+	NamePlaceholder = "{{name}}"
+)
+
+var (
+	templateNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]*$`)
+	projectNamePattern  = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+)
+
+type Options struct {
+	Catalog string // directory or git URL for bare template names
+	Name    string // replaces NamePlaceholder
+	BaseDir string // relative paths resolve against this directory
+}
+
+type fetchDeps struct {
+	lookPath  func(string) (string, error)
+	run       func(name string, args []string) error
+	mkdirTemp func(dir, pattern string) (string, error)
+}
+
+var defaultFetchDeps = fetchDeps{
+	lookPath:  exec.LookPath,
+	run:       runCommand,
+	mkdirTemp: os.MkdirTemp,
+}
+
+func runCommand(name string, args []string) error {
+	cmd := exec.Command(name, args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("%w: %s", err, msg)
+		}
+		return err
+	}
+	return nil
+}
+
+// Load fetches the template ref and decodes it as a (possibly partial)
+// manifest. ref is a git URL (optionally ending in #<file>), a path to a
+// template file or directory, or a bare name looked up in opts.Catalog.
+func Load(ref string, opts Options) (config.Config, error) {
+	return load(ref, opts, defaultFetchDeps)
+}
+
+func load(ref string, opts Options, deps fetchDeps) (config.Config, error) {
+	raw, err := fetch(ref, opts, deps)
+	if err != nil {
+		return config.Config{}, fmt.Errorf("template %s: %w", ref, err)
+	}
+	if bytes.Contains(raw, []byte(NamePlaceholder)) && !projectNamePattern.MatchString(opts.Name) {
+		return config.Config{}, fmt.Errorf("template %s: invalid project name %q for %s (expected lowercase letters, digits, and dashes)", ref, opts.Name, NamePlaceholder)
+	}
+	raw = bytes.ReplaceAll(raw, []byte(NamePlaceholder), []byte(opts.Name))
+	cfg, err := config.Decode(raw)
+	if err != nil {
+		return config.Config{}, fmt.Errorf("template %s: %w", ref, err)
+	}
+	return cfg, nil
+}
+
+func fetch(ref string, opts Options, deps fetchDeps) ([]byte, error) {
+	if url, ok := gitURL(ref); ok {
+		url, file, _ := strings.Cut(url, "#")
+		if file == "" {
+			file = DefaultFile
+		}
+		return fetchGit(url, file, deps)
+	}
+	if isPath(ref) {
+		return readLocal(resolve(opts.BaseDir, ref))
+	}
+	if !templateNamePattern.MatchString(ref) {
+		return nil, errors.New("invalid template name")
+	}
+	if opts.Catalog == "" {
+		return nil, fmt.Errorf("set %s to a template directory or git URL, or pass a path or git URL", CatalogEnv)
+	}
+	if url, ok := gitURL(opts.Catalog); ok {
+		return fetchGit(url, ref+".json", deps)
+	}
+	return readLocal(filepath.Join(resolve(opts.BaseDir, opts.Catalog), ref+".json"))
+}
+
+// gitURL recognizes clone URLs; a "git+" prefix forces git for any scheme.
+func gitURL(ref string) (string, bool) {
+	if rest, ok := strings.CutPrefix(ref, "git+"); ok {
+		return rest, true
+	}
+	for _, prefix := range []string{"https://", "http://", "ssh://", "git://", "git@"} {
+		if strings.HasPrefix(ref, prefix) {
+			return ref, true
+		}
+	}
+	url, _, _ := strings.Cut(ref, "#")
+	return ref, strings.HasSuffix(url, ".git")
+}
+
+func isPath(ref string) bool {
+	return strings.ContainsRune(ref, '/') || strings.ContainsRune(ref, filepath.Separator) ||
+		strings.HasPrefix(ref, ".") || strings.HasSuffix(ref, ".json")
+}
+
+func resolve(baseDir, path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(baseDir, path)
+}
+
+func readLocal(path string) ([]byte, error) {
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		path = filepath.Join(path, DefaultFile)
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read: %w", err)
+	}
+	return raw, nil
+}
+
+func fetchGit(url, file string, deps fetchDeps) ([]byte, error) {
+	if !filepath.IsLocal(file) {
+		return nil, fmt.Errorf("template file %q must be a relative path inside the repository", file)
+	}
+	git, err := deps.lookPath("git")
+	if err != nil {
+		return nil, fmt.Errorf("git templates require the git binary on PATH: %w", err)
+	}
+	dir, err := deps.mkdirTemp("", "dev-vault-template-*")
+	if err != nil {
+		return nil, fmt.Errorf("create temp dir: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+	if err := deps.run(git, []string{"clone", "--depth", "1", "--quiet", "--", url, dir}); err != nil {
+		return nil, fmt.Errorf("git clone: %w", err)
+	}
+	return readLocal(filepath.Join(dir, file))
+}
@@ -0,0 +1,163 @@
+package configtemplate
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const backendTemplate = `{"organization_id":"org","project_id":"","region":"fr-par","mapping":{
+	"{{name}}-env-dev":{"file":".env","format":"dotenv","path":"/{{name}}"}
+},"policy":{"required_tags":["owner"]}}`
+
+func writeFile(t *testing.T, path, body string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(body), 0o600); err != nil {
+		t.Fatalf("write %s: %v", path, err)
+	}
+}
+
+// fakeGit "clones" by copying files into the destination directory.
+func fakeGit(files map[string]string, calls *[][]string) fetchDeps {
+	return fetchDeps{
+		lookPath:  func(string) (string, error) { return "/usr/bin/git", nil },
+		mkdirTemp: os.MkdirTemp,
+		run: func(name string, args []string) error {
+			*calls = append(*calls, args)
+			dest := args[len(args)-1]
+			for rel, body := range files {
+				if err := os.WriteFile(filepath.Join(dest, rel), []byte(body), 0o600); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+}
+
+func TestLoad_Local(t *testing.T) {
+	base := t.TempDir()
+	writeFile(t, filepath.Join(base, "tpl", "backend.json"), backendTemplate)
+	writeFile(t, filepath.Join(base, "tpl", "template.json"), backendTemplate)
+
+	for _, ref := range []string{"tpl/backend.json", filepath.Join(base, "tpl", "backend.json"), "./tpl"} {
+		cfg, err := Load(ref, Options{Name: "billing", BaseDir: base})
+		if err != nil {
+			t.Fatalf("Load(%s): %v", ref, err)
+		}
+		entry, ok := cfg.Mapping["billing-env-dev"]
+		if !ok || entry.Path != "/billing" || cfg.Policy == nil || cfg.OrganizationID != "org" {
+			t.Fatalf("Load(%s): unexpected config %#v", ref, cfg)
+		}
+	}
+
+	cfg, err := Load("backend", Options{Name: "billing", BaseDir: base, Catalog: "tpl"})
+	if err != nil || len(cfg.Mapping) != 1 {
+		t.Fatalf("catalog lookup: %#v %v", cfg, err)
+	}
+}
+
+func TestLoad_Git(t *testing.T) {
+	var calls [][]string
+	deps := fakeGit(map[string]string{"template.json": backendTemplate, "backend.json": backendTemplate}, &calls)
+
+	if _, err := load("https://git.example.com/t.git", Options{Name: "api"}, deps); err != nil {
+		t.Fatalf("direct git: %v", err)
+	}
+	if _, err := load("git+file:///srv/templates#backend.json", Options{Name: "api"}, deps); err != nil {
+		t.Fatalf("git+ fragment: %v", err)
+	}
+	if _, err := load("backend", Options{Name: "api", Catalog: "git@example.com:platform/templates.git"}, deps); err != nil {
+		t.Fatalf("git catalog: %v", err)
+	}
+	want := [][]string{
+		{"clone", "--depth", "1", "--quiet", "--", "https://git.example.com/t.git"},
+		{"clone", "--depth", "1", "--quiet", "--", "file:///srv/templates"},
+		{"clone", "--depth", "1", "--quiet", "--", "git@example.com:platform/templates.git"},
+	}
+	for i, call := range calls {
+		if strings.Join(call[:len(call)-1], " ") != strings.Join(want[i], " ") {
+			t.Fatalf("call %d: got %v", i, call)
+		}
+		if _, err := os.Stat(call[len(call)-1]); !os.IsNotExist(err) {
+			t.Fatalf("expected clone dir to be removed, got %v", err)
+		}
+	}
+}
+
+func TestLoad_Errors(t *testing.T) {
+	base := t.TempDir()
+	writeFile(t, filepath.Join(base, "bad.json"), `{"unknown":true}`)
+	writeFile(t, filepath.Join(base, "named.json"), backendTemplate)
+	var calls [][]string
+	gitOK := fakeGit(map[string]string{"template.json": backendTemplate}, &calls)
+
+	cases := []struct {
+		name string
+		ref  string
+		opts Options
+		deps fetchDeps
+		want string
+	}{
+		{"InvalidName", "Backend", Options{Catalog: base}, gitOK, "invalid template name"},
+		{"NoCatalog", "backend", Options{}, gitOK, "set DEV_VAULT_TEMPLATES"},
+		{"Missing", "missing.json", Options{BaseDir: base}, gitOK, "read:"},
+		{"Decode", "bad.json", Options{BaseDir: base}, gitOK, "decode config json"},
+		{"ProjectName", "named.json", Options{BaseDir: base, Name: "My Repo"}, gitOK, `invalid project name "My Repo"`},
+		{"EscapingFile", "https://x/t.git#../etc/passwd", Options{}, gitOK, "must be a relative path"},
+		{"NoGit", "https://x/t.git", Options{}, fetchDeps{lookPath: func(string) (string, error) { return "", errors.New("not found") }}, "require the git binary"},
+		{"TempDir", "https://x/t.git", Options{}, fetchDeps{
+			lookPath:  gitOK.lookPath,
+			mkdirTemp: func(string, string) (string, error) { return "", errors.New("no tmp") },
+		}, "create temp dir: no tmp"},
+		{"Clone", "https://x/t.git", Options{}, fetchDeps{
+			lookPath:  gitOK.lookPath,
+			mkdirTemp: os.MkdirTemp,
+			run:       func(string, []string) error { return errors.New("auth failed") },
+		}, "git clone: auth failed"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := load(tc.ref, tc.opts, tc.deps); err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("expected %q, got %v", tc.want, err)
+			}
+		})
+	}
+}
+
+func TestGitURL(t *testing.T) {
+	cases := map[string]bool{
+		"https://h/r":        true,
+		"ssh://h/r":          true,
+		"git@h:r.git":        true,
+		"git+file:///r":      true,
+		"../r.git#a.json":    true,
+		"templates/a.json":   false,
+		"backend-service":    false,
+		"http://h/r#t.json":  true,
+		"git://h/r":          true,
+		"/abs/template.json": false,
+	}
+	for ref, want := range cases {
+		if _, got := gitURL(ref); got != want {
+			t.Fatalf("gitURL(%q) = %v, want %v", ref, got, want)
+		}
+	}
+}
+
+func TestRunCommand(t *testing.T) {
+	if err := runCommand("sh", []string{"-c", "exit 0"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := runCommand("sh", []string{"-c", "echo oops >&2; exit 3"}); err == nil || !strings.Contains(err.Error(), "oops") {
+		t.Fatalf("expected stderr in error, got %v", err)
+	}
+	if err := runCommand("sh", []string{"-c", "exit 3"}); err == nil || err.Error() != "exit status 3" {
+		t.Fatalf("expected bare exit error, got %v", err)
+	}
+}
@@ -0,0 +1,165 @@
+// Package dbcreds turns a database_credentials payload into a client
+// invocation whose password travels only through the client's environment.
+package dbcreds
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Credentials is the database_credentials payload. port is a string in the
+// provider's format, so it is decoded as a json.Number to accept both forms.
+type Credentials struct {
+	Engine   string      `json:"engine"`
+	Username string      `json:"username"`
+	Password string      `json:"password"`
+	Host     string      `json:"host"`
+	Port     json.Number `json:"port"`
+	DBName   string      `json:"dbname"`
+}
+
+// Client is one command line for an interactive database client. Env holds
+// the password and must never be printed.
+type Client struct {
+	Program string
+	Args    []string
+	Env     []string
+}
+
+// Parse decodes a database_credentials payload.
+func Parse(payload []byte) (Credentials, error) {
+	var c Credentials
+	if err := json.Unmarshal(payload, &c); err != nil {
+		return Credentials{}, fmt.Errorf("decode database credentials: %w", err)
+	}
+	var missing []string
+	for _, field := range []struct{ name, value string }{
+		{"engine", c.Engine},
+		{"host", c.Host},
+		{"username", c.Username},
+	} {
+		if field.value == "" {
+			missing = append(missing, field.name)
+		}
+	}
+	if len(missing) > 0 {
+		return Credentials{}, fmt.Errorf("database credentials: missing %s", strings.Join(missing, ", "))
+	}
+	return c, nil
+}
+
+func (c Credentials) scheme() (string, error) {
+	switch strings.ToLower(c.Engine) {
+	case "postgres", "postgresql":
+		return "postgresql", nil
+	case "mysql", "mariadb":
+		return "mysql", nil
+	}
+	return "", fmt.Errorf("unsupported database engine %q (expected postgres or mysql)", c.Engine)
+}
+
+// URL renders a connection string without the password.
+func (c Credentials) URL() (string, error) {
+	scheme, err := c.scheme()
+	if err != nil {
+		return "", err
+	}
+	host := c.Host
+	if c.Port != "" {
+		host += ":" + c.Port.String()
+	}
+	u := url.URL{Scheme: scheme, User: url.User(c.Username), Host: host}
+	if c.DBName != "" {
+		u.Path = "/" + c.DBName
+	}
+	return u.String(), nil
+}
+
+// Client returns the psql or mysql invocation for the credentials.
+func (c Credentials) Client() (Client, error) {
+	scheme, err := c.scheme()
+	if err != nil {
+		return Client{}, err
+	}
+	if scheme == "postgresql" {
+		args := []string{"--host", c.Host, "--username", c.Username}
+		if c.Port != "" {
+			args = append(args, "--port", c.Port.String())
+		}
+		if c.DBName != "" {
+			args = append(args, "--dbname", c.DBName)
+		}
+		return Client{Program: "psql", Args: args, Env: []string{"PGPASSWORD=" + c.Password}}, nil
+	}
+	args := []string{"--host", c.Host, "--user", c.Username}
+	if c.Port != "" {
+		args = append(args, "--port", c.Port.String())
+	}
+	if c.DBName != "" {
+		args = append(args, c.DBName)
+	}
+	return Client{Program: "mysql", Args: args, Env: []string{"MYSQL_PWD=" + c.Password}}, nil
+}
+
+// String renders the command line for display; the password variables are
+// named but their values are elided.
+func (c Client) String() string {
+	parts := make([]string, 0, len(c.Env)+1+len(c.Args))
+	for _, env := range c.Env {
+		name, _, _ := strings.Cut(env, "=")
+		parts = append(parts, name+"=***")
+	}
+	parts = append(parts, c.Program)
+	for _, arg := range c.Args {
+		if arg == "" || strings.ContainsAny(arg, " \t'\"$\\") {
+			arg = "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+		}
+		parts = append(parts, arg)
+	}
+	return strings.Join(parts, " ")
+}
+
+type runDeps struct {
+	lookPath func(string) (string, error)
+	run      func(path string, args, env []string, stdin io.Reader, stdout, stderr io.Writer) error
+}
+
+var defaultRunDeps = runDeps{
+	lookPath: exec.LookPath,
+	run:      runProgram,
+}
+
+func runProgram(path string, args, env []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	cmd := exec.Command(path, args...)
+	cmd.Env = append(os.Environ(), env...)
+	cmd.Stdin = stdin
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	return cmd.Run()
+}
+
+// Run starts the client attached to the given streams and waits for it.
+func (c Client) Run(stdin io.Reader, stdout, stderr io.Writer) error {
+	return c.run(stdin, stdout, stderr, defaultRunDeps)
+}
+
+func (c Client) run(stdin io.Reader, stdout, stderr io.Writer, deps runDeps) error {
+	path, err := deps.lookPath(c.Program)
+	if err != nil {
+		return fmt.Errorf("%s is not on PATH: %w", c.Program, err)
+	}
+	if err := deps.run(path, c.Args, c.Env, stdin, stdout, stderr); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return fmt.Errorf("%s exited with code %d", c.Program, exitErr.ExitCode())
+		}
+		return fmt.Errorf("%s: %w", c.Program, err)
+	}
+	return nil
+}
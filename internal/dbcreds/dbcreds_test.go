@@ -0,0 +1,117 @@
+package dbcreds
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	creds, err := Parse([]byte(`{"engine":"postgres","username":"app","password":"pw","host":"db.local","port":"5432","dbname":"app"}`))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if creds.Port.String() != "5432" || creds.Password != "pw" {
+		t.Fatalf("unexpected credentials: %#v", creds)
+	}
+	if creds, err := Parse([]byte(`{"engine":"mysql","username":"app","host":"db","port":3306}`)); err != nil || creds.Port != "3306" {
+		t.Fatalf("expected numeric port, got %#v %v", creds, err)
+	}
+	if _, err := Parse([]byte(`not-json`)); err == nil || !strings.Contains(err.Error(), "decode database credentials") {
+		t.Fatalf("expected decode error, got %v", err)
+	}
+	if _, err := Parse([]byte(`{"password":"pw"}`)); err == nil || err.Error() != "database credentials: missing engine, host, username" {
+		t.Fatalf("expected missing fields error, got %v", err)
+	}
+}
+
+func TestURLAndClient(t *testing.T) {
+	pg := Credentials{Engine: "PostgreSQL", Username: "app", Password: "s3cret", Host: "db.local", Port: "5432", DBName: "app"}
+	if got, err := pg.URL(); err != nil || got != "postgresql://app@db.local:5432/app" {
+		t.Fatalf("unexpected postgres url: %q %v", got, err)
+	}
+	client, err := pg.Client()
+	if err != nil {
+		t.Fatalf("Client: %v", err)
+	}
+	want := Client{Program: "psql", Args: []string{"--host", "db.local", "--username", "app", "--port", "5432", "--dbname", "app"}, Env: []string{"PGPASSWORD=s3cret"}}
+	if !reflect.DeepEqual(client, want) {
+		t.Fatalf("unexpected postgres client: %#v", client)
+	}
+	if got := client.String(); got != "PGPASSWORD=*** psql --host db.local --username app --port 5432 --dbname app" || strings.Contains(got, "s3cret") {
+		t.Fatalf("unexpected postgres command: %q", got)
+	}
+
+	my := Credentials{Engine: "mariadb", Username: "it's me", Password: "s3cret", Host: "db"}
+	if got, err := my.URL(); err != nil || got != "mysql://it%27s%20me@db" {
+		t.Fatalf("unexpected mysql url: %q %v", got, err)
+	}
+	client, err = my.Client()
+	if err != nil {
+		t.Fatalf("Client: %v", err)
+	}
+	if got := client.String(); got != `MYSQL_PWD=*** mysql --host db --user 'it'\''s me'` {
+		t.Fatalf("unexpected mysql command: %q", got)
+	}
+	my.Port, my.DBName = "3306", "app"
+	if client, _ := my.Client(); !reflect.DeepEqual(client.Args, []string{"--host", "db", "--user", "it's me", "--port", "3306", "app"}) {
+		t.Fatalf("unexpected mysql args: %#v", client.Args)
+	}
+	if got := (Client{Program: "psql", Args: []string{""}}).String(); got != "psql ''" {
+		t.Fatalf("unexpected empty-arg rendering: %q", got)
+	}
+
+	oracle := Credentials{Engine: "oracle", Username: "app", Host: "db"}
+	if _, err := oracle.URL(); err == nil || !strings.Contains(err.Error(), `unsupported database engine "oracle"`) {
+		t.Fatalf("expected engine error, got %v", err)
+	}
+	if _, err := oracle.Client(); err == nil {
+		t.Fatal("expected engine error from Client")
+	}
+}
+
+func TestRun(t *testing.T) {
+	client := Client{Program: "psql", Args: []string{"--host", "db"}, Env: []string{"PGPASSWORD=pw"}}
+	var gotPath string
+	var gotEnv []string
+	deps := runDeps{
+		lookPath: func(name string) (string, error) { return "/usr/bin/" + name, nil },
+		run: func(path string, args, env []string, _ io.Reader, _, _ io.Writer) error {
+			gotPath, gotEnv = path, env
+			return nil
+		},
+	}
+	if err := client.run(nil, io.Discard, io.Discard, deps); err != nil || gotPath != "/usr/bin/psql" || !reflect.DeepEqual(gotEnv, client.Env) {
+		t.Fatalf("unexpected run: %q %v %v", gotPath, gotEnv, err)
+	}
+
+	missing := deps
+	missing.lookPath = func(string) (string, error) { return "", errors.New("not found") }
+	if err := client.run(nil, io.Discard, io.Discard, missing); err == nil || !strings.Contains(err.Error(), "psql is not on PATH") {
+		t.Fatalf("expected lookPath error, got %v", err)
+	}
+	failing := deps
+	failing.run = func(string, []string, []string, io.Reader, io.Writer, io.Writer) error { return errors.New("boom") }
+	if err := client.run(nil, io.Discard, io.Discard, failing); err == nil || err.Error() != "psql: boom" {
+		t.Fatalf("expected run error, got %v", err)
+	}
+
+	// A real child process exercises the default runner and exit codes.
+	sh, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skip("sh not available")
+	}
+	var out bytes.Buffer
+	shClient := Client{Program: sh, Args: []string{"-c", `printf %s "$DB_TEST_PW"; exit 3`}, Env: []string{"DB_TEST_PW=pw"}}
+	if err := shClient.Run(nil, &out, io.Discard); err == nil || err.Error() != sh+" exited with code 3" || out.String() != "pw" {
+		t.Fatalf("expected exit code error, got %v (out %q)", err, out.String())
+	}
+	if err := runProgram(filepath.Join(t.TempDir(), "missing"), nil, nil, nil, io.Discard, io.Discard); err == nil {
+		t.Fatal("expected exec error")
+	}
+}
@@ -10,6 +10,17 @@ import (
 	"unicode"
 )
 
+// SyntaxError reports the line of data Parse could not read. Msg never
+// quotes a value.
+type SyntaxError struct {
+	Line int
+	Msg  string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Msg)
+}
+
 func Parse(data []byte) (map[string]string, error) {
 	out := make(map[string]string)
 	sc := bufio.NewScanner(bytes.NewReader(data))
@@ -26,11 +37,11 @@ func Parse(data []byte) (map[string]string, error) {
 
 		eq := strings.IndexByte(line, '=')
 		if eq < 0 {
-			return nil, fmt.Errorf("line %d: missing '='", lineNum)
+			return nil, &SyntaxError{Line: lineNum, Msg: "missing '='"}
 		}
 		key := strings.TrimSpace(line[:eq])
 		if !isValidKey(key) {
-			return nil, fmt.Errorf("line %d: invalid key %q", lineNum, key)
+			return nil, &SyntaxError{Line: lineNum, Msg: fmt.Sprintf("invalid key %q", key)}
 		}
 
 		rawVal := strings.TrimSpace(line[eq+1:])
@@ -41,7 +52,7 @@ func Parse(data []byte) (map[string]string, error) {
 
 		val, err := parseValue(rawVal)
 		if err != nil {
-			return nil, fmt.Errorf("line %d: %w", lineNum, err)
+			return nil, &SyntaxError{Line: lineNum, Msg: err.Error()}
 		}
 		out[key] = val
 	}
@@ -52,6 +63,12 @@ func Parse(data []byte) (map[string]string, error) {
 }
 
 func Render(env map[string]string) []byte {
+	return RenderCommented(env, nil)
+}
+
+// RenderCommented renders env like Render, preceding each key with the
+// comment line comment returns for it; nil or "" adds none.
+func RenderCommented(env map[string]string, comment func(key string) string) []byte {
 	keys := make([]string, 0, len(env))
 	for k := range env {
 		keys = append(keys, k)
@@ -60,6 +77,13 @@ func Render(env map[string]string) []byte {
 
 	var b strings.Builder
 	for _, k := range keys {
+		if comment != nil {
+			if text := comment(k); text != "" {
+				b.WriteString("# ")
+				b.WriteString(text)
+				b.WriteByte('\n')
+			}
+		}
 		b.WriteString(k)
 		b.WriteByte('=')
 		b.WriteByte('"')
@@ -70,6 +94,44 @@ func Render(env map[string]string) []byte {
 	return []byte(b.String())
 }
 
+// Update returns data with its assignments set to the values of env, keeping
+// every other byte: key order, comments, blank lines, line endings, and the
+// quoting of values that did not change. ok is false when data does not parse
+// or does not assign exactly the keys of env; such a file is rendered again.
+func Update(data []byte, env map[string]string) ([]byte, bool) {
+	current, err := Parse(data)
+	if err != nil || len(current) != len(env) {
+		return nil, false
+	}
+	for key := range current {
+		if _, ok := env[key]; !ok {
+			return nil, false
+		}
+	}
+	var b strings.Builder
+	for _, line := range strings.SplitAfter(string(data), "\n") {
+		b.WriteString(updateLine(line, env))
+	}
+	return []byte(b.String()), true
+}
+
+// updateLine rewrites the value of an assignment line Parse accepted when it
+// differs from env.
+func updateLine(line string, env map[string]string) string {
+	body := strings.TrimRight(line, "\r\n")
+	trimmed := strings.TrimSpace(body)
+	if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+		return line
+	}
+	eq := strings.IndexByte(body, '=')
+	key := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(body[:eq]), "export "))
+	value := env[key]
+	if current, _ := parseValue(strings.TrimSpace(body[eq+1:])); current == value {
+		return line
+	}
+	return body[:eq+1] + `"` + escapeDoubleQuoted(value) + `"` + line[len(body):]
+}
+
 func isValidKey(s string) bool {
 	if s == "" {
 		return false
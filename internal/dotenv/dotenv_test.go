@@ -2,6 +2,7 @@ package dotenv
 
 import (
 	"bytes"
+	"errors"
 	"reflect"
 	"strings"
 	"testing"
@@ -57,6 +58,11 @@ func TestParse_Errors(t *testing.T) {
 			}
 		})
 	}
+
+	var syntaxErr *SyntaxError
+	if _, err := Parse([]byte("# comment\nA=1\n\nNOPE\n")); !errors.As(err, &syntaxErr) || syntaxErr.Line != 4 || err.Error() != "line 4: missing '='" {
+		t.Fatalf("expected a syntax error on line 4, got %v", err)
+	}
 }
 
 func TestRender(t *testing.T) {
@@ -73,6 +79,44 @@ func TestRender(t *testing.T) {
 	}
 }
 
+func TestRenderCommented(t *testing.T) {
+	out := RenderCommented(map[string]string{"A": "1", "B": "2"}, func(key string) string {
+		if key == "A" {
+			return "from a-dev rev 3"
+		}
+		return ""
+	})
+	if want := "# from a-dev rev 3\nA=\"1\"\nB=\"2\"\n"; string(out) != want {
+		t.Fatalf("unexpected render:\n%s", out)
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	in := "# managed by dev-vault: app-env-dev\r\n\n# the database\nexport DB_HOST=db  \r\nDB_PASSWORD='old'\n  PORT = \"8080\"\nEMPTY=\n"
+	got, ok := Update([]byte(in), map[string]string{"DB_HOST": "db", "DB_PASSWORD": "new \"one\"", "PORT": "8080", "EMPTY": "set"})
+	want := "# managed by dev-vault: app-env-dev\r\n\n# the database\nexport DB_HOST=db  \r\nDB_PASSWORD=\"new \\\"one\\\"\"\n  PORT = \"8080\"\nEMPTY=\"set\"\n"
+	if !ok || string(got) != want {
+		t.Fatalf("unexpected update %v\nwant=%q\ngot =%q", ok, want, got)
+	}
+	if got, ok := Update([]byte("A=1"), map[string]string{"A": "2"}); !ok || string(got) != `A="2"` {
+		t.Fatalf("expected the last line updated, got %v %q", ok, got)
+	}
+
+	for _, tc := range []struct {
+		in  string
+		env map[string]string
+	}{
+		{"A=1\nB=2\n", map[string]string{"A": "1"}},
+		{"A=1\n", map[string]string{"A": "1", "B": "2"}},
+		{"A=1\n", map[string]string{"B": "1"}},
+		{"A\n", map[string]string{"A": "1"}},
+	} {
+		if _, ok := Update([]byte(tc.in), tc.env); ok {
+			t.Fatalf("%q: expected no in-place update", tc.in)
+		}
+	}
+}
+
 func TestRoundTrip(t *testing.T) {
 	env := map[string]string{
 		"A": "x",
@@ -0,0 +1,103 @@
+// Package expiry records when the plaintext files pulled for a config were
+// last pulled and when they expire, so prompt, list --stale, and pull --watch
+// can flag the ones to pull again. The pull time is kept apart from the
+// files' modification times, which the mtime strategies can set to older
+// times. Records hold secret names, the config path, and times only, never
+// payloads.
+package expiry
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/bsmartlabs/dev-vault/internal/fsx"
+)
+
+const dirName = "expiry"
+
+type Record struct {
+	Config string `json:"config"`
+	// Expires maps a secret name to the time its pulled files expire.
+	Expires map[string]time.Time `json:"expires"`
+	// Pulled maps a secret name to the time pull last wrote its files.
+	Pulled map[string]time.Time `json:"pulled,omitempty"`
+}
+
+// Set records that the files of name expire at expires, or drops their expiry
+// when expires is zero.
+func (r *Record) Set(name string, expires time.Time) {
+	if expires.IsZero() {
+		delete(r.Expires, name)
+		return
+	}
+	if r.Expires == nil {
+		r.Expires = make(map[string]time.Time)
+	}
+	r.Expires[name] = expires
+}
+
+// SetPulled records that the files of name were pulled at.
+func (r *Record) SetPulled(name string, at time.Time) {
+	if r.Pulled == nil {
+		r.Pulled = make(map[string]time.Time)
+	}
+	r.Pulled[name] = at
+}
+
+// Store keeps one record per config file under a per-user directory.
+type Store struct {
+	dir string
+}
+
+func NewStore(dir string) Store {
+	return Store{dir: filepath.Join(dir, dirName)}
+}
+
+// Path names the record file after a hash of the config path, so files of
+// different repositories never share expiries.
+func (s Store) Path(configPath string) string {
+	sum := sha256.Sum256([]byte(configPath))
+	return filepath.Join(s.dir, hex.EncodeToString(sum[:8])+".json")
+}
+
+// Load returns the record of configPath, empty when there is none.
+func (s Store) Load(configPath string) (Record, error) {
+	empty := Record{Config: configPath}
+	raw, err := os.ReadFile(s.Path(configPath))
+	if errors.Is(err, os.ErrNotExist) {
+		return empty, nil
+	}
+	if err != nil {
+		return empty, fmt.Errorf("read expiry: %w", err)
+	}
+	var record Record
+	if err := json.Unmarshal(raw, &record); err != nil {
+		return empty, fmt.Errorf("decode expiry: %w", err)
+	}
+	if record.Config != configPath {
+		return empty, nil
+	}
+	return record, nil
+}
+
+// Save writes record, or removes the file when it holds no time.
+func (s Store) Save(record Record) error {
+	path := s.Path(record.Config)
+	if len(record.Expires) == 0 && len(record.Pulled) == 0 {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("clear expiry: %w", err)
+		}
+		return nil
+	}
+	raw, _ := json.Marshal(record) // strings and times only; always encodes
+	if err := fsx.AtomicWriteFile(path, append(raw, '\n'), 0o600, true); err != nil {
+		return fmt.Errorf("write expiry: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package expiry
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStoreRoundTrip(t *testing.T) {
+	store := NewStore(t.TempDir())
+	record, err := store.Load("/repo/.scw.json")
+	if err != nil || record.Config != "/repo/.scw.json" || len(record.Expires) != 0 {
+		t.Fatalf("expected an empty record, got %#v %v", record, err)
+	}
+	at := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	record.Set("a-dev", at)
+	record.Set("b-dev", at)
+	record.Set("b-dev", time.Time{})
+	record.SetPulled("b-dev", at)
+	if err := store.Save(record); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	got, err := store.Load("/repo/.scw.json")
+	if err != nil || len(got.Expires) != 1 || !got.Expires["a-dev"].Equal(at) || len(got.Pulled) != 1 || !got.Pulled["b-dev"].Equal(at) {
+		t.Fatalf("unexpected record: %#v %v", got, err)
+	}
+	if other, _ := store.Load("/other/.scw.json"); len(other.Expires) != 0 {
+		t.Fatal("expected another config to have no record")
+	}
+
+	// A record copied under another key must not be trusted.
+	if err := os.Rename(store.Path("/repo/.scw.json"), store.Path("/b")); err != nil {
+		t.Fatalf("rename: %v", err)
+	}
+	if foreign, err := store.Load("/b"); err != nil || len(foreign.Expires) != 0 {
+		t.Fatalf("expected the foreign record ignored, got %#v %v", foreign, err)
+	}
+
+	got.Config = "/b"
+	got.Set("a-dev", time.Time{})
+	got.Pulled = nil
+	if err := store.Save(got); err != nil {
+		t.Fatalf("Save of an empty record: %v", err)
+	}
+	if _, err := os.Stat(store.Path("/b")); !os.IsNotExist(err) {
+		t.Fatalf("expected the record removed, got %v", err)
+	}
+	if err := store.Save(got); err != nil {
+		t.Fatalf("Save of a missing empty record: %v", err)
+	}
+}
+
+func TestStoreErrors(t *testing.T) {
+	dir := t.TempDir()
+	store := NewStore(dir)
+	path := store.Path("/a")
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte("{"), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if _, err := store.Load("/a"); err == nil || !strings.Contains(err.Error(), "decode expiry") {
+		t.Fatalf("expected decode error, got %v", err)
+	}
+
+	if err := os.Remove(path); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(path, "child"), 0o700); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if _, err := store.Load("/a"); err == nil || !strings.Contains(err.Error(), "read expiry") {
+		t.Fatalf("expected read error, got %v", err)
+	}
+	if err := store.Save(Record{Config: "/a"}); err == nil || !strings.Contains(err.Error(), "clear expiry") {
+		t.Fatalf("expected clear error, got %v", err)
+	}
+
+	blocked := NewStore(filepath.Join(dir, "file"))
+	if err := os.WriteFile(filepath.Join(dir, "file"), []byte("x"), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	record := Record{Config: "/a"}
+	record.Set("a-dev", time.Now())
+	if err := blocked.Save(record); err == nil || !strings.Contains(err.Error(), "write expiry") {
+		t.Fatalf("expected write error, got %v", err)
+	}
+}
@@ -0,0 +1,80 @@
+// Package features gates experimental subsystems. A feature ships dark and is
+// enabled per user, from the "features" list of the user config file or the
+// DEV_VAULT_EXPERIMENTAL environment variable, until it is stable.
+package features
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const (
+	// UserConfigFileName is the user config file in the per-user state
+	// directory.
+	UserConfigFileName = "config.json"
+	// EnvVar enables features for one shell or CI job, comma-separated.
+	EnvVar = "DEV_VAULT_EXPERIMENTAL"
+)
+
+type userConfig struct {
+	Features []string `json:"features"`
+}
+
+// Set is the experimental features enabled for the current user.
+type Set map[string]bool
+
+func (s Set) Enabled(name string) bool {
+	return s[name]
+}
+
+// Names returns the enabled features in sorted order.
+func (s Set) Names() []string {
+	names := make([]string, 0, len(s))
+	for name := range s {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// UserConfigPath is the user config file under the per-user state directory.
+func UserConfigPath(dir string) string {
+	return filepath.Join(dir, UserConfigFileName)
+}
+
+// Load merges the features of the user config file under dir with those of
+// DEV_VAULT_EXPERIMENTAL. A missing file enables nothing.
+func Load(dir string, getenv func(string) string) (Set, error) {
+	set := make(Set)
+	for _, name := range strings.Split(getenv(EnvVar), ",") {
+		set.add(name)
+	}
+	path := UserConfigPath(dir)
+	raw, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return set, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
+	var cfg userConfig
+	if err := json.Unmarshal(raw, &cfg); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	for _, name := range cfg.Features {
+		set.add(name)
+	}
+	return set, nil
+}
+
+func (s Set) add(name string) {
+	if name = strings.TrimSpace(name); name != "" {
+		s[name] = true
+	}
+}
@@ -0,0 +1,40 @@
+package features
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	env := map[string]string{}
+	getenv := func(key string) string { return env[key] }
+
+	set, err := Load(dir, getenv)
+	if err != nil || len(set.Names()) != 0 || set.Enabled("agent") {
+		t.Fatalf("expected nothing enabled, got %v %v", set, err)
+	}
+
+	env[EnvVar] = " agent, ,workspaces"
+	if err := os.WriteFile(UserConfigPath(dir), []byte(`{"features":["agent","ide-api"],"other":1}`), 0o600); err != nil {
+		t.Fatalf("write user config: %v", err)
+	}
+	set, err = Load(dir, getenv)
+	if err != nil || !reflect.DeepEqual(set.Names(), []string{"agent", "ide-api", "workspaces"}) || !set.Enabled("workspaces") {
+		t.Fatalf("unexpected features: %v %v", set.Names(), err)
+	}
+
+	if err := os.WriteFile(UserConfigPath(dir), []byte(`{"features":"agent"}`), 0o600); err != nil {
+		t.Fatalf("write user config: %v", err)
+	}
+	if _, err := Load(dir, getenv); err == nil || !strings.HasPrefix(err.Error(), "parse "+UserConfigPath(dir)) {
+		t.Fatalf("expected parse error, got %v", err)
+	}
+
+	if _, err := Load(filepath.Join(UserConfigPath(dir), "nested"), getenv); err == nil || !strings.HasPrefix(err.Error(), "read ") {
+		t.Fatalf("expected read error, got %v", err)
+	}
+}
@@ -0,0 +1,69 @@
+// Package fixtures turns the variables of a dotenv secret into stand-ins for
+// application test suites. Keys are kept; each value becomes a fake of the
+// same kind (boolean, integer, URL, text) or a fixed mask. A stand-in depends
+// only on its key and that kind, never on the value itself, so fixtures are
+// deterministic and carry nothing of the real credentials.
+package fixtures
+
+import (
+	"fmt"
+	"hash/fnv"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Mask replaces every non-empty value when masking.
+const Mask = "********"
+
+// Build returns the stand-ins for values, masked or faked. Empty values stay
+// empty so optional settings keep looking unset.
+func Build(values map[string]string, mask bool) map[string]string {
+	out := make(map[string]string, len(values))
+	for key, value := range values {
+		switch {
+		case value == "":
+			out[key] = ""
+		case mask:
+			out[key] = Mask
+		default:
+			out[key] = Fake(key, value)
+		}
+	}
+	return out
+}
+
+// Fake returns a stand-in for the value of key shaped like value:
+//   - true/false becomes false,
+//   - an integer becomes a number in [1000, 9999] derived from key,
+//   - a URL keeps its scheme and points at fixture.invalid, with fixture
+//     credentials when it had some,
+//   - anything else becomes fixture-<key>.
+func Fake(key, value string) string {
+	slug := strings.ReplaceAll(strings.ToLower(key), "_", "-")
+	if strings.EqualFold(value, "true") || strings.EqualFold(value, "false") {
+		return "false"
+	}
+	if _, err := strconv.ParseInt(value, 10, 64); err == nil {
+		sum := fnv.New32a()
+		_, _ = sum.Write([]byte(key))
+		return strconv.Itoa(1000 + int(sum.Sum32()%9000))
+	}
+	if u, err := url.Parse(value); err == nil && u.Scheme != "" && u.Host != "" {
+		fake := url.URL{Scheme: u.Scheme, Host: "fixture.invalid", Path: "/" + slug}
+		if u.User != nil {
+			fake.User = url.UserPassword("fixture", "fixture")
+		}
+		return fake.String()
+	}
+	return "fixture-" + slug
+}
+
+// Placeholder returns the value pull --fake writes for key when no value is
+// readable: fake-<key> with a hash of key, so it is stable across runs and
+// unique per key.
+func Placeholder(key string) string {
+	sum := fnv.New32a()
+	_, _ = sum.Write([]byte(key))
+	return fmt.Sprintf("fake-%s-%08x", strings.ReplaceAll(strings.ToLower(key), "_", "-"), sum.Sum32())
+}
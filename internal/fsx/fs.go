@@ -0,0 +1,194 @@
+package fsx
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// FS is the filesystem mapped files are read from and written to. OS is the
+// real one; MemFS keeps everything in memory, for tests and for targets that
+// must never reach the disk.
+type FS interface {
+	ReadFile(name string) ([]byte, error)
+	Stat(name string) (fs.FileInfo, error)
+	Lstat(name string) (fs.FileInfo, error)
+	// WriteFileAtomic replaces name in one step, as AtomicWriteFile does; it
+	// returns ErrExists when name exists and overwrite is false.
+	WriteFileAtomic(name string, data []byte, perm fs.FileMode, overwrite bool) error
+	// Chtimes sets the access and modification times of name, as os.Chtimes
+	// does; a zero time leaves that time unchanged.
+	Chtimes(name string, atime, mtime time.Time) error
+	// GetXattr and SetXattr read and write an extended attribute of name.
+	// GetXattr fails when the attribute is missing, and both fail with an
+	// error matching errors.ErrUnsupported where there are no attributes.
+	GetXattr(name, attr string) ([]byte, error)
+	SetXattr(name, attr string, value []byte) error
+}
+
+// OS is the FS of the host operating system.
+var OS FS = osFS{}
+
+type osFS struct{}
+
+func (osFS) ReadFile(name string) ([]byte, error)   { return os.ReadFile(name) }
+func (osFS) Stat(name string) (fs.FileInfo, error)  { return os.Stat(name) }
+func (osFS) Lstat(name string) (fs.FileInfo, error) { return os.Lstat(name) }
+func (osFS) WriteFileAtomic(name string, data []byte, perm fs.FileMode, overwrite bool) error {
+	return AtomicWriteFile(name, data, perm, overwrite)
+}
+
+func (osFS) Chtimes(name string, atime, mtime time.Time) error {
+	return os.Chtimes(name, atime, mtime)
+}
+
+func (osFS) GetXattr(name, attr string) ([]byte, error) { return getXattr(name, attr) }
+func (osFS) SetXattr(name, attr string, value []byte) error {
+	return setXattr(name, attr, value)
+}
+
+// MemFS is an in-memory FS holding regular files only; directories exist
+// implicitly. It is safe for concurrent use.
+type MemFS struct {
+	now func() time.Time
+
+	mu    sync.Mutex
+	files map[string]memFile
+}
+
+type memFile struct {
+	data    []byte
+	mode    fs.FileMode
+	modTime time.Time
+	xattrs  map[string][]byte
+}
+
+// NewMemFS returns an empty MemFS that stamps writes with now, or with
+// time.Now when now is nil.
+func NewMemFS(now func() time.Time) *MemFS {
+	if now == nil {
+		now = time.Now
+	}
+	return &MemFS{now: now, files: make(map[string]memFile)}
+}
+
+func (m *MemFS) ReadFile(name string) ([]byte, error) {
+	file, err := m.lookup("open", name)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(nil), file.data...), nil
+}
+
+func (m *MemFS) Stat(name string) (fs.FileInfo, error) {
+	return m.info("stat", name)
+}
+
+// Lstat is Stat: MemFS has no symbolic links.
+func (m *MemFS) Lstat(name string) (fs.FileInfo, error) {
+	return m.info("lstat", name)
+}
+
+func (m *MemFS) WriteFileAtomic(name string, data []byte, perm fs.FileMode, overwrite bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	name = filepath.Clean(name)
+	previous, ok := m.files[name]
+	if ok && !overwrite {
+		return ErrExists
+	}
+	// Like AtomicWriteFile, an overwrite keeps the extended attributes.
+	m.files[name] = memFile{data: append([]byte(nil), data...), mode: perm.Perm(), modTime: m.now(), xattrs: previous.xattrs}
+	return nil
+}
+
+// Chtimes sets the modification time of name; MemFS keeps no access time.
+func (m *MemFS) Chtimes(name string, _, mtime time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	name = filepath.Clean(name)
+	file, ok := m.files[name]
+	if !ok {
+		return &fs.PathError{Op: "chtimes", Path: name, Err: fs.ErrNotExist}
+	}
+	if !mtime.IsZero() {
+		file.modTime = mtime
+		m.files[name] = file
+	}
+	return nil
+}
+
+func (m *MemFS) GetXattr(name, attr string) ([]byte, error) {
+	file, err := m.lookup("getxattr", name)
+	if err != nil {
+		return nil, err
+	}
+	value, ok := file.xattrs[attr]
+	if !ok {
+		return nil, &fs.PathError{Op: "getxattr", Path: name, Err: fs.ErrNotExist}
+	}
+	return append([]byte(nil), value...), nil
+}
+
+func (m *MemFS) SetXattr(name, attr string, value []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	name = filepath.Clean(name)
+	file, ok := m.files[name]
+	if !ok {
+		return &fs.PathError{Op: "setxattr", Path: name, Err: fs.ErrNotExist}
+	}
+	xattrs := make(map[string][]byte, len(file.xattrs)+1)
+	for key, v := range file.xattrs {
+		xattrs[key] = v
+	}
+	xattrs[attr] = append([]byte(nil), value...)
+	file.xattrs = xattrs
+	m.files[name] = file
+	return nil
+}
+
+// Names lists the files written so far, sorted.
+func (m *MemFS) Names() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	names := make([]string, 0, len(m.files))
+	for name := range m.files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (m *MemFS) lookup(op, name string) (memFile, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	file, ok := m.files[filepath.Clean(name)]
+	if !ok {
+		return memFile{}, &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+	}
+	return file, nil
+}
+
+func (m *MemFS) info(op, name string) (fs.FileInfo, error) {
+	file, err := m.lookup(op, name)
+	if err != nil {
+		return nil, err
+	}
+	return memFileInfo{name: filepath.Base(name), file: file}, nil
+}
+
+type memFileInfo struct {
+	name string
+	file memFile
+}
+
+func (i memFileInfo) Name() string       { return i.name }
+func (i memFileInfo) Size() int64        { return int64(len(i.file.data)) }
+func (i memFileInfo) Mode() fs.FileMode  { return i.file.mode }
+func (i memFileInfo) ModTime() time.Time { return i.file.modTime }
+func (i memFileInfo) IsDir() bool        { return false }
+func (i memFileInfo) Sys() any           { return nil }
@@ -5,19 +5,39 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
+	"sync/atomic"
 )
 
 var ErrExists = errors.New("file exists")
 
+// skipSync is set by SetSync(false).
+var skipSync atomic.Bool
+
+// SetSync controls whether AtomicWriteFile flushes the file and its parent
+// directory to stable storage. It is on by default; turning it off trades
+// crash safety for speed.
+func SetSync(enabled bool) {
+	skipSync.Store(!enabled)
+}
+
+// SyncEnabled reports the setting of SetSync.
+func SyncEnabled() bool {
+	return !skipSync.Load()
+}
+
 type fsDeps struct {
 	mkdirAll   func(string, os.FileMode) error
 	stat       func(string) (os.FileInfo, error)
 	createTemp func(string, string) (*os.File, error)
 	chmod      func(string, os.FileMode) error
+	xattrs     func(from, to string) error
 	rename     func(string, string) error
 	remove     func(string) error
 	write      func(*os.File, []byte) (int, error)
+	sync       func(*os.File) error
 	close      func(*os.File) error
+	syncDir    func(string) error
 }
 
 func defaultFSDeps() fsDeps {
@@ -26,18 +46,41 @@ func defaultFSDeps() fsDeps {
 		stat:       os.Stat,
 		createTemp: os.CreateTemp,
 		chmod:      os.Chmod,
+		xattrs:     copyUserXattrs,
 		rename:     os.Rename,
 		remove:     os.Remove,
 		write:      func(f *os.File, data []byte) (int, error) { return f.Write(data) },
+		sync:       func(f *os.File) error { return f.Sync() },
 		close:      func(f *os.File) error { return f.Close() },
+		syncDir:    func(dir string) error { return syncDir(runtime.GOOS, dir) },
+	}
+}
+
+// syncDir makes a rename in dir durable. Windows cannot sync directories and
+// persists renames without it.
+func syncDir(goos, dir string) error {
+	if goos == "windows" {
+		return nil
+	}
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
 	}
+	defer d.Close()
+	return d.Sync()
 }
 
+// AtomicWriteFile replaces path with data in one rename, so readers see the
+// old content or the new one. Unless SetSync(false) was called, the data is
+// flushed before the rename and the directory after it, so a crash cannot
+// leave an empty or partial file behind. When overwriting, the user extended
+// attributes of the replaced file are carried over to the new one.
 func AtomicWriteFile(path string, data []byte, perm os.FileMode, overwrite bool) error {
 	return atomicWriteFileWithDeps(path, data, perm, overwrite, defaultFSDeps())
 }
 
 func atomicWriteFileWithDeps(path string, data []byte, perm os.FileMode, overwrite bool, deps fsDeps) error {
+	durable := !skipSync.Load()
 	dir := filepath.Dir(path)
 	if err := deps.mkdirAll(dir, 0o755); err != nil {
 		return fmt.Errorf("mkdirall %s: %w", dir, err)
@@ -68,17 +111,28 @@ func atomicWriteFileWithDeps(path string, data []byte, perm os.FileMode, overwri
 		_ = deps.close(f)
 		return fmt.Errorf("write temp: %w", err)
 	}
+	if durable {
+		if err := deps.sync(f); err != nil {
+			_ = deps.close(f)
+			return fmt.Errorf("sync temp: %w", err)
+		}
+	}
 	if err := deps.close(f); err != nil {
 		return fmt.Errorf("close temp: %w", err)
 	}
 	if err := deps.chmod(tmpName, perm); err != nil {
 		return fmt.Errorf("chmod temp: %w", err)
 	}
+	if overwrite {
+		if err := deps.xattrs(path, tmpName); err != nil {
+			return fmt.Errorf("copy xattrs: %w", err)
+		}
+	}
 
 	renameErr := deps.rename(tmpName, path)
 	if renameErr == nil {
 		cleanup = false
-		return nil
+		return syncParent(dir, durable, deps)
 	}
 
 	if overwrite {
@@ -87,7 +141,7 @@ func atomicWriteFileWithDeps(path string, data []byte, perm os.FileMode, overwri
 		}
 		if retryErr := deps.rename(tmpName, path); retryErr == nil {
 			cleanup = false
-			return nil
+			return syncParent(dir, durable, deps)
 		} else {
 			return fmt.Errorf("rename temp to dest after overwrite (first attempt: %v): %w", renameErr, retryErr)
 		}
@@ -95,3 +149,13 @@ func atomicWriteFileWithDeps(path string, data []byte, perm os.FileMode, overwri
 
 	return fmt.Errorf("rename temp to dest: %w", renameErr)
 }
+
+func syncParent(dir string, durable bool, deps fsDeps) error {
+	if !durable {
+		return nil
+	}
+	if err := deps.syncDir(dir); err != nil {
+		return fmt.Errorf("sync dir %s: %w", dir, err)
+	}
+	return nil
+}
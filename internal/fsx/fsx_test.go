@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestAtomicWriteFile_Success(t *testing.T) {
@@ -149,6 +150,17 @@ func TestAtomicWriteFile_ErrorsViaInjection(t *testing.T) {
 		}
 	})
 
+	t.Run("XattrsError", func(t *testing.T) {
+		dir := t.TempDir()
+		dest := filepath.Join(dir, "out.txt")
+		deps := defaultFSDeps()
+		deps.xattrs = func(string, string) error { return errors.New("boom") }
+
+		if err := atomicWriteFileWithDeps(dest, []byte("x"), 0o600, true, deps); err == nil || !strings.Contains(err.Error(), "copy xattrs: boom") {
+			t.Fatalf("expected xattrs error, got %v", err)
+		}
+	})
+
 	t.Run("RenameErrorOverwriteFalse", func(t *testing.T) {
 		dir := t.TempDir()
 		dest := filepath.Join(dir, "out.txt")
@@ -171,6 +183,43 @@ func TestAtomicWriteFile_ErrorsViaInjection(t *testing.T) {
 		}
 	})
 
+	t.Run("SyncError", func(t *testing.T) {
+		dir := t.TempDir()
+		dest := filepath.Join(dir, "out.txt")
+		deps := defaultFSDeps()
+		deps.sync = func(*os.File) error { return errors.New("boom") }
+		if err := atomicWriteFileWithDeps(dest, []byte("x"), 0o600, true, deps); err == nil || err.Error() != "sync temp: boom" {
+			t.Fatalf("expected sync error, got %v", err)
+		}
+		if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+			t.Fatalf("expected the temp file to be removed, got %v", entries)
+		}
+	})
+
+	t.Run("SyncDirError", func(t *testing.T) {
+		dir := t.TempDir()
+		dest := filepath.Join(dir, "out.txt")
+		for _, overwrite := range []bool{false, true} {
+			deps := defaultFSDeps()
+			deps.syncDir = func(string) error { return errors.New("boom") }
+			if overwrite {
+				renames := 0
+				deps.rename = func(from, to string) error {
+					if renames++; renames == 1 {
+						return errors.New("busy")
+					}
+					return os.Rename(from, to)
+				}
+			}
+			if err := atomicWriteFileWithDeps(dest, []byte("x"), 0o600, overwrite, deps); err == nil || err.Error() != "sync dir "+dir+": boom" {
+				t.Fatalf("expected sync dir error, got %v", err)
+			}
+		}
+		if got, err := os.ReadFile(dest); err != nil || string(got) != "x" {
+			t.Fatalf("expected the file in place, got %q %v", got, err)
+		}
+	})
+
 	t.Run("RenameFallbackReturnsSecondRenameError", func(t *testing.T) {
 		dir := t.TempDir()
 		dest := filepath.Join(dir, "out.txt")
@@ -195,3 +244,172 @@ func TestAtomicWriteFile_ErrorsViaInjection(t *testing.T) {
 		}
 	})
 }
+
+func TestAtomicWriteFile_Sync(t *testing.T) {
+	t.Cleanup(func() { SetSync(true) })
+	write := func(t *testing.T) (files, dirs []string) {
+		t.Helper()
+		dir := t.TempDir()
+		deps := defaultFSDeps()
+		deps.sync = func(f *os.File) error {
+			files = append(files, filepath.Base(f.Name()))
+			return f.Sync()
+		}
+		deps.syncDir = func(dir string) error {
+			dirs = append(dirs, dir)
+			return nil
+		}
+		if err := atomicWriteFileWithDeps(filepath.Join(dir, "out.txt"), []byte("x"), 0o600, false, deps); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+		if len(dirs) > 0 && dirs[0] != dir {
+			t.Fatalf("expected the parent to be synced, got %v", dirs)
+		}
+		return files, dirs
+	}
+
+	files, dirs := write(t)
+	if len(files) != 1 || !strings.HasPrefix(files[0], "out.txt.tmp.") || len(dirs) != 1 {
+		t.Fatalf("expected one file and one dir sync, got %v %v", files, dirs)
+	}
+	SetSync(false)
+	if SyncEnabled() {
+		t.Fatal("expected sync to be off")
+	}
+	if files, dirs := write(t); len(files) != 0 || len(dirs) != 0 {
+		t.Fatalf("expected no sync, got %v %v", files, dirs)
+	}
+}
+
+func TestSyncDir(t *testing.T) {
+	if err := syncDir("linux", t.TempDir()); err != nil {
+		t.Fatalf("sync dir: %v", err)
+	}
+	missing := filepath.Join(t.TempDir(), "missing")
+	if err := syncDir("linux", missing); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected not exist, got %v", err)
+	}
+	if err := syncDir("windows", missing); err != nil {
+		t.Fatalf("expected windows to skip the sync, got %v", err)
+	}
+}
+
+func TestOSFS(t *testing.T) {
+	dest := filepath.Join(t.TempDir(), "sub", "out.txt")
+	if err := OS.WriteFileAtomic(dest, []byte("hello"), 0o600, false); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := OS.WriteFileAtomic(dest, []byte("again"), 0o600, false); !errors.Is(err, ErrExists) {
+		t.Fatalf("expected ErrExists, got %v", err)
+	}
+	got, err := OS.ReadFile(dest)
+	if err != nil || string(got) != "hello" {
+		t.Fatalf("read: %q %v", got, err)
+	}
+	if info, err := OS.Stat(dest); err != nil || info.Size() != 5 {
+		t.Fatalf("stat: %v %v", info, err)
+	}
+	if _, err := OS.Lstat(dest + ".missing"); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected not exist, got %v", err)
+	}
+	stamp := time.Unix(1700000000, 0)
+	if err := OS.Chtimes(dest, time.Time{}, stamp); err != nil {
+		t.Fatalf("chtimes: %v", err)
+	}
+	if info, err := OS.Stat(dest); err != nil || !info.ModTime().Equal(stamp) {
+		t.Fatalf("unexpected mtime: %v %v", info, err)
+	}
+}
+
+func TestMemFS(t *testing.T) {
+	stamp := time.Unix(42, 0)
+	mem := NewMemFS(func() time.Time { return stamp })
+	data := []byte("hello")
+	if err := mem.WriteFileAtomic("/p/./out.txt", data, 0o600|os.ModeSetuid, false); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	data[0] = 'j'
+	if err := mem.WriteFileAtomic("/p/out.txt", []byte("again"), 0o600, false); !errors.Is(err, ErrExists) {
+		t.Fatalf("expected ErrExists, got %v", err)
+	}
+	got, err := mem.ReadFile("/p/out.txt")
+	if err != nil || string(got) != "hello" {
+		t.Fatalf("read: %q %v", got, err)
+	}
+	got[0] = 'j'
+	info, err := mem.Stat("/p/out.txt")
+	if err != nil || info.Name() != "out.txt" || info.Size() != 5 || info.Mode() != 0o600 || !info.ModTime().Equal(stamp) || info.IsDir() || info.Sys() != nil {
+		t.Fatalf("stat: %#v %v", info, err)
+	}
+	if err := mem.WriteFileAtomic("/p/out.txt", []byte("bye"), 0o644, true); err != nil {
+		t.Fatalf("overwrite: %v", err)
+	}
+	if info, err := mem.Lstat("/p/out.txt"); err != nil || info.Size() != 3 || info.Mode() != 0o644 {
+		t.Fatalf("lstat: %#v %v", info, err)
+	}
+	for _, err := range []error{
+		func() error { _, err := mem.ReadFile("/p"); return err }(),
+		func() error { _, err := mem.Stat("/missing"); return err }(),
+		func() error { _, err := mem.Lstat("/missing"); return err }(),
+	} {
+		if !errors.Is(err, os.ErrNotExist) {
+			t.Fatalf("expected not exist, got %v", err)
+		}
+	}
+	if err := mem.Chtimes("/p/out.txt", time.Time{}, time.Time{}); err != nil {
+		t.Fatalf("chtimes: %v", err)
+	}
+	if err := mem.Chtimes("/p/out.txt", time.Time{}, time.Unix(7, 0)); err != nil {
+		t.Fatalf("chtimes: %v", err)
+	}
+	if info, _ := mem.Stat("/p/out.txt"); !info.ModTime().Equal(time.Unix(7, 0)) {
+		t.Fatalf("unexpected mtime: %v", info.ModTime())
+	}
+	if err := mem.Chtimes("/missing", time.Time{}, time.Unix(7, 0)); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected not exist, got %v", err)
+	}
+	if err := mem.SetXattr("/p/out.txt", "user.a", []byte("1")); err != nil {
+		t.Fatalf("setxattr: %v", err)
+	}
+	if err := mem.SetXattr("/p/out.txt", "user.b", []byte("2")); err != nil {
+		t.Fatalf("setxattr: %v", err)
+	}
+	if err := mem.WriteFileAtomic("/p/out.txt", []byte("bye"), 0o644, true); err != nil {
+		t.Fatalf("overwrite: %v", err)
+	}
+	if value, err := mem.GetXattr("/p/out.txt", "user.a"); err != nil || string(value) != "1" {
+		t.Fatalf("expected kept xattr, got %q %v", value, err)
+	}
+	for _, err := range []error{
+		func() error { _, err := mem.GetXattr("/p/out.txt", "user.missing"); return err }(),
+		func() error { _, err := mem.GetXattr("/missing", "user.a"); return err }(),
+		mem.SetXattr("/missing", "user.a", nil),
+	} {
+		if !errors.Is(err, os.ErrNotExist) {
+			t.Fatalf("expected not exist, got %v", err)
+		}
+	}
+	if names := mem.Names(); len(names) != 1 || names[0] != filepath.Clean("/p/out.txt") {
+		t.Fatalf("unexpected names: %v", names)
+	}
+	if NewMemFS(nil).now == nil {
+		t.Fatalf("expected default clock")
+	}
+}
+
+func TestFileKind(t *testing.T) {
+	cases := map[os.FileMode]string{
+		os.ModeDir:        "directory",
+		os.ModeSymlink:    "symbolic link",
+		os.ModeNamedPipe:  "named pipe",
+		os.ModeSocket:     "socket",
+		os.ModeDevice:     "device",
+		os.ModeIrregular:  "special file",
+		os.ModeCharDevice: "special file",
+	}
+	for mode, want := range cases {
+		if got := fileKind(mode); got != want {
+			t.Fatalf("fileKind(%v) = %q, want %q", mode, got, want)
+		}
+	}
+}
@@ -0,0 +1,10 @@
+//go:build !unix
+
+package fsx
+
+import "io/fs"
+
+// linkCount is 1 where dev-vault does not read link counts.
+func linkCount(fs.FileInfo) uint64 {
+	return 1
+}
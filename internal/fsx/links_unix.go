@@ -0,0 +1,17 @@
+//go:build unix
+
+package fsx
+
+import (
+	"io/fs"
+	"syscall"
+)
+
+// linkCount is the number of hard links of the file described by info, or 1
+// when info does not come from the operating system.
+func linkCount(info fs.FileInfo) uint64 {
+	if st, ok := info.Sys().(*syscall.Stat_t); ok {
+		return uint64(st.Nlink)
+	}
+	return 1
+}
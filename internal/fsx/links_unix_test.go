@@ -0,0 +1,67 @@
+//go:build unix
+
+package fsx
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestCheckReplaceable(t *testing.T) {
+	dir := t.TempDir()
+	regular := filepath.Join(dir, "regular")
+	if err := os.WriteFile(regular, []byte("x"), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	linked := filepath.Join(dir, "linked")
+	if err := os.WriteFile(linked, []byte("x"), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := os.Link(linked, filepath.Join(dir, "other-name")); err != nil {
+		t.Fatalf("link: %v", err)
+	}
+	fifo := filepath.Join(dir, "fifo")
+	if err := syscall.Mkfifo(fifo, 0o600); err != nil {
+		t.Fatalf("mkfifo: %v", err)
+	}
+	symlink := filepath.Join(dir, "symlink")
+	if err := os.Symlink(regular, symlink); err != nil {
+		t.Fatalf("symlink: %v", err)
+	}
+
+	cases := []struct {
+		path    string
+		wantErr error
+		wantMsg string
+	}{
+		{regular, nil, ""},
+		{linked, ErrHardLinked, "file has other hard links (2 links)"},
+		{fifo, ErrNotRegular, "not a regular file (named pipe)"},
+		{symlink, ErrNotRegular, "not a regular file (symbolic link)"},
+		{dir, ErrNotRegular, "not a regular file (directory)"},
+	}
+	for _, tc := range cases {
+		info, err := os.Lstat(tc.path)
+		if err != nil {
+			t.Fatalf("lstat %s: %v", tc.path, err)
+		}
+		err = CheckReplaceable(info)
+		if !errors.Is(err, tc.wantErr) || (err != nil && err.Error() != tc.wantMsg) {
+			t.Fatalf("%s: unexpected error %v", filepath.Base(tc.path), err)
+		}
+	}
+
+	// A MemFS file carries no link count.
+	mem := NewMemFS(func() time.Time { return time.Unix(0, 0) })
+	if err := mem.WriteFileAtomic("/a", []byte("x"), 0o600, false); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	info, _ := mem.Lstat("/a")
+	if err := CheckReplaceable(info); err != nil {
+		t.Fatalf("unexpected error for MemFS file: %v", err)
+	}
+}
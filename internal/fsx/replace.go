@@ -0,0 +1,44 @@
+package fsx
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+)
+
+var (
+	ErrNotRegular = errors.New("not a regular file")
+	ErrHardLinked = errors.New("file has other hard links")
+)
+
+// CheckReplaceable reports whether the file described by info, as returned
+// by Lstat, may be replaced by a rename. Renaming over a FIFO, device,
+// socket, directory or symbolic link replaces the node itself rather than
+// writing into it, and renaming over a hard link silently detaches the other
+// names from the new content.
+func CheckReplaceable(info fs.FileInfo) error {
+	if !info.Mode().IsRegular() {
+		return fmt.Errorf("%w (%s)", ErrNotRegular, fileKind(info.Mode()))
+	}
+	if links := linkCount(info); links > 1 {
+		return fmt.Errorf("%w (%d links)", ErrHardLinked, links)
+	}
+	return nil
+}
+
+func fileKind(mode fs.FileMode) string {
+	switch {
+	case mode.IsDir():
+		return "directory"
+	case mode&fs.ModeSymlink != 0:
+		return "symbolic link"
+	case mode&fs.ModeNamedPipe != 0:
+		return "named pipe"
+	case mode&fs.ModeSocket != 0:
+		return "socket"
+	case mode&fs.ModeDevice != 0:
+		return "device"
+	default:
+		return "special file"
+	}
+}
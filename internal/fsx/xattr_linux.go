@@ -0,0 +1,68 @@
+package fsx
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"syscall"
+)
+
+// The xattr system calls, replaceable in tests.
+var (
+	listxattr = syscall.Listxattr
+	getxattr  = syscall.Getxattr
+	setxattr  = syscall.Setxattr
+)
+
+// copyUserXattrs copies the user.* extended attributes of from onto to. A
+// missing from, or a filesystem without xattr support, copies nothing;
+// security and trusted attributes are left to the kernel.
+func copyUserXattrs(from, to string) error {
+	names, err := readXattr(func(buf []byte) (int, error) { return listxattr(from, buf) })
+	if err != nil {
+		if errors.Is(err, syscall.ENOENT) || errors.Is(err, syscall.ENOTSUP) {
+			return nil
+		}
+		return fmt.Errorf("list %s: %w", from, err)
+	}
+	for _, name := range bytes.Split(names, []byte{0}) {
+		attr := string(name)
+		if !strings.HasPrefix(attr, "user.") {
+			continue
+		}
+		value, err := readXattr(func(buf []byte) (int, error) { return getxattr(from, attr, buf) })
+		if errors.Is(err, syscall.ENODATA) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("get %s: %w", attr, err)
+		}
+		if err := setxattr(to, attr, value, 0); err != nil {
+			return fmt.Errorf("set %s: %w", attr, err)
+		}
+	}
+	return nil
+}
+
+func getXattr(path, attr string) ([]byte, error) {
+	return readXattr(func(buf []byte) (int, error) { return getxattr(path, attr, buf) })
+}
+
+func setXattr(path, attr string, value []byte) error {
+	return setxattr(path, attr, value, 0)
+}
+
+// readXattr calls read once to size the buffer and again to fill it.
+func readXattr(read func([]byte) (int, error)) ([]byte, error) {
+	size, err := read(nil)
+	if err != nil || size == 0 {
+		return nil, err
+	}
+	buf := make([]byte, size)
+	n, err := read(buf)
+	if err != nil {
+		return nil, err
+	}
+	return buf[:n], nil
+}
@@ -0,0 +1,131 @@
+package fsx
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+)
+
+func TestAtomicWriteFile_KeepsUserXattrs(t *testing.T) {
+	dest := filepath.Join(t.TempDir(), "out.txt")
+	if err := os.WriteFile(dest, []byte("old"), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := syscall.Setxattr(dest, "user.origin", []byte("dev"), 0); err != nil {
+		t.Skipf("filesystem without user xattrs: %v", err)
+	}
+	if err := AtomicWriteFile(dest, []byte("new"), 0o600, true); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	buf := make([]byte, 16)
+	n, err := syscall.Getxattr(dest, "user.origin", buf)
+	if err != nil || string(buf[:n]) != "dev" {
+		t.Fatalf("expected xattr to survive, got %q %v", buf[:n], err)
+	}
+	if got, _ := os.ReadFile(dest); string(got) != "new" {
+		t.Fatalf("unexpected content %q", got)
+	}
+}
+
+func TestOSFS_Xattrs(t *testing.T) {
+	dest := filepath.Join(t.TempDir(), "out.txt")
+	if err := os.WriteFile(dest, []byte("x"), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := OS.SetXattr(dest, "user.origin", []byte("dev")); err != nil {
+		if errors.Is(err, errors.ErrUnsupported) {
+			t.Skipf("filesystem without user xattrs: %v", err)
+		}
+		t.Fatalf("setxattr: %v", err)
+	}
+	if value, err := OS.GetXattr(dest, "user.origin"); err != nil || string(value) != "dev" {
+		t.Fatalf("getxattr: %q %v", value, err)
+	}
+	if _, err := OS.GetXattr(dest, "user.missing"); !errors.Is(err, syscall.ENODATA) {
+		t.Fatalf("expected ENODATA, got %v", err)
+	}
+}
+
+func TestCopyUserXattrs(t *testing.T) {
+	stub := func(t *testing.T, list func(string, []byte) (int, error), get func(string, string, []byte) (int, error), set func(string, string, []byte, int) error) {
+		t.Helper()
+		origList, origGet, origSet := listxattr, getxattr, setxattr
+		t.Cleanup(func() { listxattr, getxattr, setxattr = origList, origGet, origSet })
+		listxattr, getxattr, setxattr = list, get, set
+	}
+	fill := func(value string) func([]byte) (int, error) {
+		return func(buf []byte) (int, error) {
+			if buf == nil {
+				return len(value), nil
+			}
+			return copy(buf, value), nil
+		}
+	}
+	names := "security.selinux\x00user.a\x00user.gone\x00"
+	list := func(_ string, buf []byte) (int, error) { return fill(names)(buf) }
+	get := func(_, attr string, buf []byte) (int, error) {
+		if attr == "user.gone" {
+			return 0, syscall.ENODATA
+		}
+		return fill("value")(buf)
+	}
+
+	t.Run("CopiesUserAttributesOnly", func(t *testing.T) {
+		set := map[string]string{}
+		stub(t, list, get, func(path, attr string, value []byte, _ int) error {
+			set[path+" "+attr] = string(value)
+			return nil
+		})
+		if err := copyUserXattrs("from", "to"); err != nil {
+			t.Fatalf("copy: %v", err)
+		}
+		if len(set) != 1 || set["to user.a"] != "value" {
+			t.Fatalf("unexpected attributes: %v", set)
+		}
+	})
+
+	t.Run("NothingToCopy", func(t *testing.T) {
+		for _, err := range []error{syscall.ENOENT, syscall.ENOTSUP} {
+			stub(t, func(string, []byte) (int, error) { return 0, err }, nil, nil)
+			if err := copyUserXattrs("from", "to"); err != nil {
+				t.Fatalf("expected nothing to copy, got %v", err)
+			}
+		}
+		stub(t, func(string, []byte) (int, error) { return 0, nil }, nil, nil)
+		if err := copyUserXattrs("from", "to"); err != nil {
+			t.Fatalf("expected nothing to copy, got %v", err)
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		boom := errors.New("boom")
+		cases := []struct {
+			name    string
+			list    func(string, []byte) (int, error)
+			get     func(string, string, []byte) (int, error)
+			set     func(string, string, []byte, int) error
+			wantSub string
+		}{
+			{"List", func(string, []byte) (int, error) { return 0, syscall.EIO }, nil, nil, "list from: input/output error"},
+			{"ListShrunk", func(_ string, buf []byte) (int, error) {
+				if buf == nil {
+					return 4, nil
+				}
+				return 0, syscall.ERANGE
+			}, nil, nil, "list from"},
+			{"Get", list, func(string, string, []byte) (int, error) { return 0, boom }, nil, "get user.a: boom"},
+			{"Set", list, get, func(string, string, []byte, int) error { return boom }, "set user.a: boom"},
+		}
+		for _, tc := range cases {
+			t.Run(tc.name, func(t *testing.T) {
+				stub(t, tc.list, tc.get, tc.set)
+				if err := copyUserXattrs("from", "to"); err == nil || !strings.Contains(err.Error(), tc.wantSub) {
+					t.Fatalf("expected error containing %q, got %v", tc.wantSub, err)
+				}
+			})
+		}
+	})
+}
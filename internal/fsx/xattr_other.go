@@ -0,0 +1,19 @@
+//go:build !linux
+
+package fsx
+
+import "errors"
+
+// copyUserXattrs is a no-op where dev-vault does not manage extended
+// attributes.
+func copyUserXattrs(from, to string) error {
+	return nil
+}
+
+func getXattr(path, attr string) ([]byte, error) {
+	return nil, errors.ErrUnsupported
+}
+
+func setXattr(path, attr string, value []byte) error {
+	return errors.ErrUnsupported
+}
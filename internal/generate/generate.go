@@ -0,0 +1,190 @@
+// Package generate creates fresh secret values from a template of key
+// generator specs, for bootstrapping local credentials of a new service.
+package generate
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+)
+
+const (
+	GeneratorHex    = "hex"
+	GeneratorUUID   = "uuid"
+	GeneratorBcrypt = "bcrypt"
+
+	defaultHexBytes   = 32
+	maxHexBytes       = 1024
+	defaultBcryptCost = 12
+	minBcryptCost     = 4
+	maxBcryptCost     = 31
+
+	// htpasswdUser is the throwaway user name htpasswd requires; only the
+	// hash after "user:" is kept.
+	htpasswdUser = "dev-vault"
+)
+
+// Spec describes how one key gets its value: a generator, or a literal value.
+type Spec struct {
+	Generator string `json:"generator,omitempty"` // hex|uuid|bcrypt
+	Bytes     int    `json:"bytes,omitempty"`     // hex: random bytes (default 32)
+	Cost      int    `json:"cost,omitempty"`      // bcrypt: cost factor (default 12)
+	Value     string `json:"value,omitempty"`     // literal value, without a generator
+}
+
+type Template struct {
+	Type string          `json:"type,omitempty"` // secret type for the new secret (default: key_value)
+	Keys map[string]Spec `json:"keys"`
+}
+
+type generateDeps struct {
+	random   io.Reader
+	lookPath func(string) (string, error)
+	// hashPrompt runs htpasswd attached to the terminal, which prompts for
+	// the value without echo, and returns its stdout.
+	hashPrompt func(path string, args []string) ([]byte, error)
+}
+
+var defaultGenerateDeps = generateDeps{
+	random:     rand.Reader,
+	lookPath:   exec.LookPath,
+	hashPrompt: runHtpasswd,
+}
+
+func runHtpasswd(path string, args []string) ([]byte, error) {
+	cmd := exec.Command(path, args...)
+	cmd.Stdin = os.Stdin
+	cmd.Stderr = os.Stderr
+	return cmd.Output()
+}
+
+// Load strictly decodes and validates a template file.
+func Load(path string) (Template, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return Template{}, fmt.Errorf("read template: %w", err)
+	}
+	var t Template
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&t); err != nil {
+		return Template{}, fmt.Errorf("decode template %s: %w", path, err)
+	}
+	if err := t.validate(); err != nil {
+		return Template{}, fmt.Errorf("template %s: %w", path, err)
+	}
+	return t, nil
+}
+
+func (t Template) validate() error {
+	if len(t.Keys) == 0 {
+		return errors.New("keys is empty")
+	}
+	for _, key := range t.sortedKeys() {
+		spec := t.Keys[key]
+		switch spec.Generator {
+		case "":
+			if spec.Value == "" {
+				return fmt.Errorf("key %s: set generator or value", key)
+			}
+		case GeneratorHex, GeneratorUUID, GeneratorBcrypt:
+			if spec.Value != "" {
+				return fmt.Errorf("key %s: value cannot be combined with a generator", key)
+			}
+		default:
+			return fmt.Errorf("key %s: unknown generator %q (expected hex, uuid, or bcrypt)", key, spec.Generator)
+		}
+		if spec.Bytes < 0 || spec.Bytes > maxHexBytes {
+			return fmt.Errorf("key %s: bytes must be between 1 and %d", key, maxHexBytes)
+		}
+		if spec.Cost != 0 && (spec.Cost < minBcryptCost || spec.Cost > maxBcryptCost) {
+			return fmt.Errorf("key %s: cost must be between %d and %d", key, minBcryptCost, maxBcryptCost)
+		}
+	}
+	return nil
+}
+
+func (t Template) sortedKeys() []string {
+	keys := make([]string, 0, len(t.Keys))
+	for key := range t.Keys {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Generate produces a value per key in sorted key order. bcrypt keys prompt
+// on the terminal; prompts announces which key is being asked for.
+func (t Template) Generate(prompts io.Writer) (map[string]string, error) {
+	return t.generate(prompts, defaultGenerateDeps)
+}
+
+func (t Template) generate(prompts io.Writer, deps generateDeps) (map[string]string, error) {
+	values := make(map[string]string, len(t.Keys))
+	for _, key := range t.sortedKeys() {
+		value, err := t.Keys[key].generate(key, prompts, deps)
+		if err != nil {
+			return nil, fmt.Errorf("key %s: %w", key, err)
+		}
+		values[key] = value
+	}
+	return values, nil
+}
+
+func (s Spec) generate(key string, prompts io.Writer, deps generateDeps) (string, error) {
+	switch s.Generator {
+	case GeneratorHex:
+		n := s.Bytes
+		if n == 0 {
+			n = defaultHexBytes
+		}
+		buf := make([]byte, n)
+		if _, err := io.ReadFull(deps.random, buf); err != nil {
+			return "", fmt.Errorf("random: %w", err)
+		}
+		return hex.EncodeToString(buf), nil
+	case GeneratorUUID:
+		buf := make([]byte, 16)
+		if _, err := io.ReadFull(deps.random, buf); err != nil {
+			return "", fmt.Errorf("random: %w", err)
+		}
+		buf[6] = buf[6]&0x0f | 0x40 // version 4
+		buf[8] = buf[8]&0x3f | 0x80 // RFC 4122 variant
+		return fmt.Sprintf("%x-%x-%x-%x-%x", buf[0:4], buf[4:6], buf[6:8], buf[8:10], buf[10:16]), nil
+	case GeneratorBcrypt:
+		return s.bcrypt(key, prompts, deps)
+	default:
+		return s.Value, nil
+	}
+}
+
+func (s Spec) bcrypt(key string, prompts io.Writer, deps generateDeps) (string, error) {
+	htpasswd, err := deps.lookPath("htpasswd")
+	if err != nil {
+		return "", fmt.Errorf("bcrypt requires htpasswd on PATH: %w", err)
+	}
+	cost := s.Cost
+	if cost == 0 {
+		cost = defaultBcryptCost
+	}
+	if _, err := fmt.Fprintf(prompts, "Enter the value to hash for %s:\n", key); err != nil {
+		return "", err
+	}
+	out, err := deps.hashPrompt(htpasswd, []string{"-nBC", fmt.Sprint(cost), htpasswdUser})
+	if err != nil {
+		return "", fmt.Errorf("htpasswd: %w", err)
+	}
+	hash, ok := strings.CutPrefix(strings.TrimSpace(string(out)), htpasswdUser+":")
+	if !ok || !strings.HasPrefix(hash, "$2") {
+		return "", errors.New("htpasswd: unexpected output")
+	}
+	return hash, nil
+}
@@ -0,0 +1,120 @@
+package generate
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func writeTemplate(t *testing.T, raw string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "template.json")
+	if err := os.WriteFile(path, []byte(raw), 0o600); err != nil {
+		t.Fatalf("write template: %v", err)
+	}
+	return path
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) { return 0, errors.New("write failed") }
+
+func TestLoad(t *testing.T) {
+	tpl, err := Load(writeTemplate(t, `{"type":"opaque","keys":{"A":{"generator":"hex","bytes":4},"B":{"value":"b"}}}`))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if tpl.Type != "opaque" || tpl.Keys["A"].Bytes != 4 {
+		t.Fatalf("unexpected template: %#v", tpl)
+	}
+
+	if _, err := Load(filepath.Join(t.TempDir(), "missing.json")); err == nil || !strings.Contains(err.Error(), "read template") {
+		t.Fatalf("expected read error, got %v", err)
+	}
+	for raw, wantErr := range map[string]string{
+		`{"keys":{},"extra":1}`: "decode template",
+		`{"keys":{}}`:           "keys is empty",
+		`{"keys":{"A":{}}}`:     "key A: set generator or value",
+		`{"keys":{"A":{"generator":"hex","value":"x"}}}`: "value cannot be combined",
+		`{"keys":{"A":{"generator":"rot13"}}}`:           `unknown generator "rot13"`,
+		`{"keys":{"A":{"generator":"hex","bytes":-1}}}`:  "bytes must be between",
+		`{"keys":{"A":{"generator":"bcrypt","cost":3}}}`: "cost must be between",
+	} {
+		if _, err := Load(writeTemplate(t, raw)); err == nil || !strings.Contains(err.Error(), wantErr) {
+			t.Fatalf("%s: expected %q, got %v", raw, wantErr, err)
+		}
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	tpl := Template{Keys: map[string]Spec{
+		"HASH":    {Generator: GeneratorBcrypt},
+		"ID":      {Generator: GeneratorUUID},
+		"SECRET":  {Generator: GeneratorHex},
+		"SHORT":   {Generator: GeneratorHex, Bytes: 2},
+		"DB_USER": {Value: "app"},
+	}}
+	var gotArgs []string
+	deps := generateDeps{
+		random:   bytes.NewReader(bytes.Repeat([]byte{0xff}, 64)),
+		lookPath: func(string) (string, error) { return "/usr/bin/htpasswd", nil },
+		hashPrompt: func(path string, args []string) ([]byte, error) {
+			gotArgs = append([]string{path}, args...)
+			return []byte("dev-vault:$2y$12$abc\n"), nil
+		},
+	}
+	var prompts bytes.Buffer
+	values, err := tpl.generate(&prompts, deps)
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	if values["DB_USER"] != "app" || values["HASH"] != "$2y$12$abc" || values["SHORT"] != "ffff" || len(values["SECRET"]) != 64 {
+		t.Fatalf("unexpected values: %#v", values)
+	}
+	if !regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`).MatchString(values["ID"]) {
+		t.Fatalf("unexpected uuid: %s", values["ID"])
+	}
+	if strings.Join(gotArgs, " ") != "/usr/bin/htpasswd -nBC 12 dev-vault" || prompts.String() != "Enter the value to hash for HASH:\n" {
+		t.Fatalf("unexpected htpasswd call %v / prompts %q", gotArgs, prompts.String())
+	}
+
+	generated, err := Template{Keys: map[string]Spec{"A": {Generator: GeneratorHex}}}.Generate(&prompts)
+	if err != nil || len(generated["A"]) != 64 {
+		t.Fatalf("Generate: %#v %v", generated, err)
+	}
+
+	fail := func(spec Spec, deps generateDeps, prompts *bytes.Buffer) error {
+		_, err := Template{Keys: map[string]Spec{"K": spec}}.generate(prompts, deps)
+		return err
+	}
+	empty := generateDeps{random: bytes.NewReader(nil)}
+	for _, generator := range []string{GeneratorHex, GeneratorUUID} {
+		if err := fail(Spec{Generator: generator}, empty, &prompts); err == nil || !strings.Contains(err.Error(), "key K: random") {
+			t.Fatalf("%s: expected random error, got %v", generator, err)
+		}
+	}
+	noHtpasswd := generateDeps{lookPath: func(string) (string, error) { return "", errors.New("not found") }}
+	if err := fail(Spec{Generator: GeneratorBcrypt}, noHtpasswd, &prompts); err == nil || !strings.Contains(err.Error(), "requires htpasswd") {
+		t.Fatalf("expected lookPath error, got %v", err)
+	}
+	htpasswdFails := deps
+	htpasswdFails.hashPrompt = func(string, []string) ([]byte, error) { return nil, errors.New("exit 1") }
+	if err := fail(Spec{Generator: GeneratorBcrypt, Cost: 4}, htpasswdFails, &prompts); err == nil || !strings.Contains(err.Error(), "htpasswd: exit 1") {
+		t.Fatalf("expected htpasswd error, got %v", err)
+	}
+	garbage := deps
+	garbage.hashPrompt = func(string, []string) ([]byte, error) { return []byte("oops"), nil }
+	if err := fail(Spec{Generator: GeneratorBcrypt}, garbage, &prompts); err == nil || !strings.Contains(err.Error(), "unexpected output") {
+		t.Fatalf("expected output error, got %v", err)
+	}
+	if _, err := (Spec{Generator: GeneratorBcrypt}).generate("K", failingWriter{}, deps); err == nil {
+		t.Fatal("expected prompt write error")
+	}
+	if _, err := runHtpasswd(filepath.Join(t.TempDir(), "htpasswd"), nil); err == nil {
+		t.Fatal("expected exec error for a missing htpasswd")
+	}
+}
@@ -0,0 +1,268 @@
+package i18n
+
+type MessageID string
+
+const (
+	MsgMainTagline          MessageID = "main.tagline"
+	MsgHeadingUsage         MessageID = "heading.usage"
+	MsgHeadingGlobalOptions MessageID = "heading.global_options"
+	MsgHeadingCommands      MessageID = "heading.commands"
+	MsgHeadingOptions       MessageID = "heading.options"
+	MsgHeadingNotes         MessageID = "heading.notes"
+	MsgHeadingExamples      MessageID = "heading.examples"
+	MsgHeadingSafety        MessageID = "heading.safety"
+	MsgHeadingBatch         MessageID = "heading.batch"
+	MsgHeadingAutomation    MessageID = "heading.automation"
+
+	MsgGlobalConfigHelp         MessageID = "global.config.help"
+	MsgGlobalProfileHelp        MessageID = "global.profile.help"
+	MsgGlobalEnvHelp            MessageID = "global.env.help"
+	MsgGlobalLangHelp           MessageID = "global.lang.help"
+	MsgGlobalPlainHelp          MessageID = "global.plain.help"
+	MsgGlobalExplainConfigHelp  MessageID = "global.explain_config.help"
+	MsgGlobalEnforceVersionHelp MessageID = "global.enforce_version.help"
+	MsgGlobalIgnoreUnknownHelp  MessageID = "global.ignore_unknown.help"
+	MsgGlobalStrictWarningsHelp MessageID = "global.strict_warnings.help"
+	MsgGlobalLogFileHelp        MessageID = "global.log_file.help"
+	MsgGlobalNoFsyncHelp        MessageID = "global.no_fsync.help"
+
+	MsgSafetyDevSuffix   MessageID = "safety.dev_suffix"
+	MsgSafetyNoPayloads  MessageID = "safety.no_payloads"
+	MsgSafetyAtomicWrite MessageID = "safety.atomic_write"
+
+	MsgBatchModeDefault  MessageID = "batch.mode_default"
+	MsgBatchPullAll      MessageID = "batch.pull_all"
+	MsgBatchPushAll      MessageID = "batch.push_all"
+	MsgBatchExplicitMode MessageID = "batch.explicit_mode"
+	MsgBatchLegacySync   MessageID = "batch.legacy_sync"
+
+	MsgAutomationGlobalPlacement MessageID = "automation.global_placement"
+	MsgAutomationExitCodes       MessageID = "automation.exit_codes"
+
+	MsgWarning            MessageID = "warning"
+	MsgUnknownCommand     MessageID = "error.unknown_command"
+	MsgUnknownHelpCommand MessageID = "error.unknown_help_command"
+	MsgHelpSearchNoMatch  MessageID = "error.help_search_no_match"
+)
+
+// English is the source catalog; other catalogs may omit entries and fall
+// back to it. Command summaries and help are only keyed here for
+// translations, the English text lives on the command definition.
+var catalogs = map[Lang]map[MessageID]string{
+	English: withEntries(map[MessageID]string{
+		MsgMainTagline:          "Pull/push Scaleway Secret Manager secrets to disk for local development.",
+		MsgHeadingUsage:         "Usage:",
+		MsgHeadingGlobalOptions: "Global options:",
+		MsgHeadingCommands:      "Commands:",
+		MsgHeadingOptions:       "Options:",
+		MsgHeadingNotes:         "Notes:",
+		MsgHeadingExamples:      "Examples:",
+		MsgHeadingSafety:        "Hard safety constraints:",
+		MsgHeadingBatch:         "Batch behavior:",
+		MsgHeadingAutomation:    "Notes for automation/LLMs:",
+
+		MsgGlobalConfigHelp:         "Path to %s. If omitted: search upward from cwd.",
+		MsgGlobalProfileHelp:        "Scaleway profile override (uses ~/.config/scw/config.yaml); a,b runs against several",
+		MsgGlobalEnvHelp:            "Use an environment of the manifest's environments section (project, region, mapping)",
+		MsgGlobalLangHelp:           "Message language (en|fr|it). Default: from LC_ALL/LC_MESSAGES/LANG.",
+		MsgGlobalPlainHelp:          "Accessible output: no color or control codes, tab-separated columns.",
+		MsgGlobalExplainConfigHelp:  "Print where each effective config value comes from, then exit.",
+		MsgGlobalEnforceVersionHelp: "Refuse to run when this binary is outside the manifest's required_version.",
+		MsgGlobalIgnoreUnknownHelp:  "Skip manifest fields this release does not know, with a warning.",
+		MsgGlobalStrictWarningsHelp: "Treat warnings as errors (exit code 1).",
+		MsgGlobalLogFileHelp:        "Also append diagnostics (everything sent to stderr) to this file.",
+		MsgGlobalNoFsyncHelp:        "Do not flush written files to disk: faster, but a crash can leave them empty.",
+
+		MsgSafetyDevSuffix:   "Refuses to operate on secret names that do not end with '-dev'.",
+		MsgSafetyNoPayloads:  "Never prints secret payloads.",
+		MsgSafetyAtomicWrite: "Pull writes files atomically and chmods them to 0600 (on Unix).",
+
+		MsgBatchModeDefault:  "mapping.mode defaults to both.",
+		MsgBatchPullAll:      "pull --all includes mapping entries with mapping.mode in {pull, both}.",
+		MsgBatchPushAll:      "push --all includes mapping entries with mapping.mode in {push, both}.",
+		MsgBatchExplicitMode: "Explicit pull/push names must satisfy mapping.mode for that command.",
+		MsgBatchLegacySync:   "Note: mapping.mode='sync' is accepted as a legacy alias for 'both'.",
+
+		MsgAutomationGlobalPlacement: "Global options can be passed either before the command or as command options (e.g. 'pull --config ...').",
+		MsgAutomationExitCodes:       "Exit codes: 0=success, 1=runtime error, 2=usage error.",
+
+		MsgWarning:            "warning: %s",
+		MsgUnknownCommand:     "unknown command: %s",
+		MsgUnknownHelpCommand: "unknown command for help: %s",
+		MsgHelpSearchNoMatch:  "no help matches %q",
+	}, englishErrors),
+	French: withEntries(map[MessageID]string{
+		MsgMainTagline:          "Récupère/envoie les secrets Scaleway Secret Manager sur disque pour le développement local.",
+		MsgHeadingUsage:         "Utilisation :",
+		MsgHeadingGlobalOptions: "Options globales :",
+		MsgHeadingCommands:      "Commandes :",
+		MsgHeadingOptions:       "Options :",
+		MsgHeadingNotes:         "Remarques :",
+		MsgHeadingExamples:      "Exemples :",
+		MsgHeadingSafety:        "Contraintes de sécurité strictes :",
+		MsgHeadingBatch:         "Comportement par lot :",
+		MsgHeadingAutomation:    "Notes pour l'automatisation/les LLM :",
+
+		MsgGlobalConfigHelp:         "Chemin vers %s. Si omis : recherche vers le haut depuis le répertoire courant.",
+		MsgGlobalProfileHelp:        "Profil Scaleway à utiliser (lit ~/.config/scw/config.yaml) ; a,b en utilise plusieurs",
+		MsgGlobalEnvHelp:            "Utilise un environnement de la section environments du manifeste (projet, région, mapping)",
+		MsgGlobalLangHelp:           "Langue des messages (en|fr|it). Par défaut : LC_ALL/LC_MESSAGES/LANG.",
+		MsgGlobalPlainHelp:          "Sortie accessible : ni couleur ni codes de contrôle, colonnes séparées par des tabulations.",
+		MsgGlobalExplainConfigHelp:  "Affiche l'origine de chaque valeur de configuration effective, puis quitte.",
+		MsgGlobalEnforceVersionHelp: "Refuse de s'exécuter si ce binaire est hors de la plage required_version du manifeste.",
+		MsgGlobalIgnoreUnknownHelp:  "Ignore, avec un avertissement, les champs du manifeste inconnus de cette version.",
+		MsgGlobalStrictWarningsHelp: "Traite les avertissements comme des erreurs (code de sortie 1).",
+		MsgGlobalLogFileHelp:        "Ajoute aussi les diagnostics (tout ce qui va sur stderr) à ce fichier.",
+		MsgGlobalNoFsyncHelp:        "Ne force pas l'écriture des fichiers sur disque : plus rapide, mais un plantage peut les laisser vides.",
+
+		MsgSafetyDevSuffix:   "Refuse d'opérer sur les secrets dont le nom ne se termine pas par '-dev'.",
+		MsgSafetyNoPayloads:  "N'affiche jamais le contenu des secrets.",
+		MsgSafetyAtomicWrite: "pull écrit les fichiers de manière atomique avec les permissions 0600 (sous Unix).",
+
+		MsgBatchModeDefault:  "mapping.mode vaut both par défaut.",
+		MsgBatchPullAll:      "pull --all inclut les entrées dont mapping.mode vaut pull ou both.",
+		MsgBatchPushAll:      "push --all inclut les entrées dont mapping.mode vaut push ou both.",
+		MsgBatchExplicitMode: "Les noms passés explicitement à pull/push doivent respecter mapping.mode pour cette commande.",
+		MsgBatchLegacySync:   "Remarque : mapping.mode='sync' est accepté comme alias historique de 'both'.",
+
+		MsgAutomationGlobalPlacement: "Les options globales peuvent être passées avant la commande ou comme options de commande (ex. 'pull --config ...').",
+		MsgAutomationExitCodes:       "Codes de sortie : 0=succès, 1=erreur d'exécution, 2=erreur d'utilisation.",
+
+		MsgWarning:            "avertissement : %s",
+		MsgUnknownCommand:     "commande inconnue : %s",
+		MsgUnknownHelpCommand: "commande inconnue pour l'aide : %s",
+		MsgHelpSearchNoMatch:  "aucune aide ne correspond à %q",
+
+		CommandSummaryID("version"):         "Affiche les informations de version",
+		CommandSummaryID("list"):            "Liste les métadonnées des secrets -dev",
+		CommandSummaryID("pull"):            "Récupère les secrets -dev mappés dans des fichiers locaux",
+		CommandSummaryID("push"):            "Envoie les fichiers locaux comme nouvelles versions de secrets",
+		CommandSummaryID("sync"):            "Récupère ou envoie chaque secret mappé selon le côté qui a changé",
+		CommandSummaryID("ci"):              "Affiche un job CI qui installe dev-vault et récupère les secrets mappés",
+		CommandSummaryID("prompt"):          "Affiche l'état local du mapping pour les prompts shell",
+		CommandSummaryID("projects"):        "Liste les projets Scaleway visibles avec vos identifiants",
+		CommandSummaryID("regions"):         "Liste les régions où Secret Manager est disponible",
+		CommandSummaryID("config"):          "Formate le manifeste .scw.json",
+		CommandSummaryID("init"):            "Crée .scw.json à partir d'un modèle de l'organisation",
+		CommandSummaryID("generate"):        "Crée un nouveau secret et son fichier local à partir d'un modèle de générateurs",
+		CommandSummaryID("import-env"):      "Transforme un fichier .env existant en nouveau secret key_value et son entrée de mapping",
+		CommandSummaryID("import-env-dir"):  "Transforme chaque fichier .env correspondant d'un répertoire en nouveau secret et entrée de mapping",
+		CommandSummaryID("db"):              "Ouvre un client de base de données avec un secret database_credentials",
+		CommandSummaryID("ssh"):             "Charge un secret ssh_key dans ssh-agent sans l'écrire sur le disque",
+		CommandSummaryID("cert"):            "Affiche le sujet, les SAN et l'expiration d'un secret certificat",
+		CommandSummaryID("get"):             "Écrit un secret basic_credentials en entrée .netrc, authentification docker ou fichier d'en-tête curl",
+		CommandSummaryID("fixtures"):        "Écrit les clés des secrets dotenv avec des valeurs factices pour les tests applicatifs",
+		CommandSummaryID("versions"):        "Liste l'historique des versions d'un secret mappé",
+		CommandSummaryID("rollback"):        "Restaure une version précédente d'un secret mappé comme dernière version",
+		CommandSummaryID("completion"):      "Affiche ou installe la complétion du shell",
+		CommandSummaryID("delete"):          "Supprime un secret mappé avec toutes ses versions",
+		CommandSummaryID("delete-version"):  "Supprime une version d'un secret mappé",
+		CommandSummaryID("gc"):              "Désactive ou supprime les anciennes versions des secrets mappés",
+		CommandSummaryID("exec"):            "Lance un programme avec un secret key_value dans son environnement",
+		CommandSummaryID("agent"):           "Sert une API locale pour que les extensions d'éditeur listent les mappings, vérifient l'état et tirent les secrets",
+		CommandSummaryID("where"):           "Liste les copies de projet de cette machine qui utilisent dev-vault, avec leur dérive locale",
+		CommandSummaryID("search"):          "Cherche les secrets -dev par nom dans tous les chemins et types",
+		CommandSummaryID("lint-names"):      "Vérifie les noms de secrets mappés et distants selon la convention de nommage",
+		CommandSummaryID("doctor"):          "Vérifie les mappings par rapport à leurs secrets distants avant l'échec de pull ou push",
+		CommandSummaryID("validate"):        "Contrôle .scw.json et les fichiers mappés, éventuellement par rapport aux secrets distants",
+		CommandSummaryID("access"):          "Indique qui accorde l'accès aux secrets du projet et où le demander",
+		CommandSummaryID("status"):          "Indique si le secret distant et le fichier local de chaque mapping existent et correspondent",
+		CommandSummaryID("state"):           "Compare les fichiers locaux aux révisions poussées par l'équipe, d'après le secret d'état partagé",
+		CommandSummaryID("report"):          "Génère un rapport d'inventaire en markdown ou HTML",
+		CommandSummaryID("telemetry"):       "Gère la télémétrie d'usage anonyme (sur consentement)",
+		CommandSummaryID("disable-mapping"): "Exclut une entrée de mapping de --all et des contrôles de dérive",
+		CommandSummaryID("enable-mapping"):  "Réactive une entrée de mapping désactivée",
+	}, frenchHelp, frenchErrors),
+	Italian: withEntries(map[MessageID]string{
+		MsgMainTagline:          "Scarica/carica i segreti di Scaleway Secret Manager su disco per lo sviluppo locale.",
+		MsgHeadingUsage:         "Uso:",
+		MsgHeadingGlobalOptions: "Opzioni globali:",
+		MsgHeadingCommands:      "Comandi:",
+		MsgHeadingOptions:       "Opzioni:",
+		MsgHeadingNotes:         "Note:",
+		MsgHeadingExamples:      "Esempi:",
+		MsgHeadingSafety:        "Vincoli di sicurezza:",
+		MsgHeadingBatch:         "Comportamento batch:",
+		MsgHeadingAutomation:    "Note per automazione/LLM:",
+
+		MsgGlobalConfigHelp:         "Percorso di %s. Se omesso: ricerca verso l'alto dalla directory corrente.",
+		MsgGlobalProfileHelp:        "Profilo Scaleway da usare (legge ~/.config/scw/config.yaml); a,b ne usa diversi",
+		MsgGlobalEnvHelp:            "Usa un ambiente della sezione environments del manifesto (progetto, regione, mapping)",
+		MsgGlobalLangHelp:           "Lingua dei messaggi (en|fr|it). Predefinita: da LC_ALL/LC_MESSAGES/LANG.",
+		MsgGlobalPlainHelp:          "Output accessibile: niente colori né codici di controllo, colonne separate da tabulazioni.",
+		MsgGlobalExplainConfigHelp:  "Mostra da dove proviene ogni valore di configurazione effettivo, poi esce.",
+		MsgGlobalEnforceVersionHelp: "Rifiuta di eseguire se questo binario è fuori dall'intervallo required_version del manifesto.",
+		MsgGlobalIgnoreUnknownHelp:  "Ignora, con un avviso, i campi del manifesto sconosciuti a questa versione.",
+		MsgGlobalStrictWarningsHelp: "Tratta gli avvisi come errori (codice di uscita 1).",
+		MsgGlobalLogFileHelp:        "Aggiunge anche la diagnostica (tutto ciò che va su stderr) a questo file.",
+		MsgGlobalNoFsyncHelp:        "Non forza la scrittura dei file su disco: più veloce, ma un crash può lasciarli vuoti.",
+
+		MsgSafetyDevSuffix:   "Rifiuta di operare su segreti il cui nome non termina con '-dev'.",
+		MsgSafetyNoPayloads:  "Non stampa mai il contenuto dei segreti.",
+		MsgSafetyAtomicWrite: "pull scrive i file in modo atomico con permessi 0600 (su Unix).",
+
+		MsgBatchModeDefault:  "mapping.mode è both per impostazione predefinita.",
+		MsgBatchPullAll:      "pull --all include le voci con mapping.mode pull o both.",
+		MsgBatchPushAll:      "push --all include le voci con mapping.mode push o both.",
+		MsgBatchExplicitMode: "I nomi passati esplicitamente a pull/push devono rispettare mapping.mode per quel comando.",
+		MsgBatchLegacySync:   "Nota: mapping.mode='sync' è accettato come alias storico di 'both'.",
+
+		MsgAutomationGlobalPlacement: "Le opzioni globali possono essere passate prima del comando o come opzioni del comando (es. 'pull --config ...').",
+		MsgAutomationExitCodes:       "Codici di uscita: 0=successo, 1=errore di esecuzione, 2=errore di utilizzo.",
+
+		MsgWarning:            "avviso: %s",
+		MsgUnknownCommand:     "comando sconosciuto: %s",
+		MsgUnknownHelpCommand: "comando sconosciuto per l'aiuto: %s",
+		MsgHelpSearchNoMatch:  "nessun aiuto corrisponde a %q",
+
+		CommandSummaryID("version"):         "Stampa le informazioni sulla versione",
+		CommandSummaryID("list"):            "Elenca i metadati dei segreti -dev",
+		CommandSummaryID("pull"):            "Scarica i segreti -dev mappati in file locali",
+		CommandSummaryID("push"):            "Carica i file locali come nuove versioni dei segreti",
+		CommandSummaryID("sync"):            "Scarica o carica ogni segreto mappato secondo il lato che è cambiato",
+		CommandSummaryID("ci"):              "Stampa un job CI che installa dev-vault e scarica i segreti mappati",
+		CommandSummaryID("prompt"):          "Stampa lo stato locale del mapping per i prompt della shell",
+		CommandSummaryID("projects"):        "Elenca i progetti Scaleway visibili con le tue credenziali",
+		CommandSummaryID("regions"):         "Elenca le regioni in cui Secret Manager è disponibile",
+		CommandSummaryID("config"):          "Formatta il manifest .scw.json",
+		CommandSummaryID("init"):            "Crea .scw.json da un modello dell'organizzazione",
+		CommandSummaryID("generate"):        "Crea un nuovo segreto e il suo file locale da un modello di generatori",
+		CommandSummaryID("import-env"):      "Trasforma un file .env esistente in un nuovo segreto key_value e la sua voce di mapping",
+		CommandSummaryID("import-env-dir"):  "Trasforma ogni file .env corrispondente di una directory in un nuovo segreto e voce di mapping",
+		CommandSummaryID("db"):              "Apre un client di database con un secret database_credentials",
+		CommandSummaryID("ssh"):             "Carica un secret ssh_key in ssh-agent senza scriverlo su disco",
+		CommandSummaryID("cert"):            "Mostra soggetto, SAN e scadenza di un secret certificato",
+		CommandSummaryID("get"):             "Scrive un secret basic_credentials come voce .netrc, autenticazione docker o file di intestazione curl",
+		CommandSummaryID("fixtures"):        "Scrive le chiavi dei segreti dotenv con valori fittizi per i test applicativi",
+		CommandSummaryID("versions"):        "Elenca la cronologia delle versioni di un segreto mappato",
+		CommandSummaryID("rollback"):        "Ripristina una versione precedente di un segreto mappato come ultima versione",
+		CommandSummaryID("completion"):      "Stampa o installa il completamento della shell",
+		CommandSummaryID("delete"):          "Elimina un segreto mappato con tutte le sue versioni",
+		CommandSummaryID("delete-version"):  "Elimina una versione di un segreto mappato",
+		CommandSummaryID("gc"):              "Disattiva o elimina le vecchie versioni dei segreti mappati",
+		CommandSummaryID("exec"):            "Avvia un programma con un segreto key_value nel suo ambiente",
+		CommandSummaryID("agent"):           "Espone un'API locale per elencare i mapping, controllarne lo stato ed eseguire pull dai plugin dell'editor",
+		CommandSummaryID("where"):           "Elenca le copie di progetto su questa macchina che usano dev-vault, con la loro deriva locale",
+		CommandSummaryID("search"):          "Cerca i secret -dev per nome in tutti i percorsi e tipi",
+		CommandSummaryID("lint-names"):      "Verifica i nomi dei secret mappati e remoti rispetto alla convenzione di denominazione",
+		CommandSummaryID("doctor"):          "Verifica i mapping rispetto ai secret remoti prima che pull o push falliscano",
+		CommandSummaryID("validate"):        "Controlla .scw.json e i file mappati, facoltativamente rispetto ai secret remoti",
+		CommandSummaryID("access"):          "Indica chi concede l'accesso ai secret del progetto e dove richiederlo",
+		CommandSummaryID("status"):          "Indica se il secret remoto e il file locale di ogni mapping esistono e corrispondono",
+		CommandSummaryID("state"):           "Confronta i file locali con le revisioni inviate dal team, dal secret di stato condiviso",
+		CommandSummaryID("report"):          "Genera un report di inventario in markdown o HTML",
+		CommandSummaryID("telemetry"):       "Gestisce la telemetria d'uso anonima (su consenso)",
+		CommandSummaryID("disable-mapping"): "Esclude una voce di mapping da --all e dai controlli di deriva",
+		CommandSummaryID("enable-mapping"):  "Riattiva una voce di mapping disattivata",
+	}, italianHelp, italianErrors),
+}
+
+// withEntries returns messages with the entries of every extra map added.
+func withEntries(messages map[MessageID]string, extra ...map[MessageID]string) map[MessageID]string {
+	for _, entries := range extra {
+		for id, text := range entries {
+			messages[id] = text
+		}
+	}
+	return messages
+}
@@ -0,0 +1,318 @@
+package i18n
+
+// Messages of the errors, warnings, and notices the commands report.
+const (
+	MsgTakesNoArguments        MessageID = "error.takes_no_arguments"
+	MsgTakesOneSecretName      MessageID = "error.takes_one_secret_name"
+	MsgTakesOneFile            MessageID = "error.takes_one_file"
+	MsgTakesOneDirectory       MessageID = "error.takes_one_directory"
+	MsgExpectedOneSecretName   MessageID = "error.expected_one_secret_name"
+	MsgExpectedUsage           MessageID = "error.expected_usage"
+	MsgExpectedSubcommand      MessageID = "error.expected_subcommand"
+	MsgUnexpectedArguments     MessageID = "error.unexpected_arguments"
+	MsgRequiresFlag            MessageID = "error.requires_flag"
+	MsgNeedsYes                MessageID = "error.needs_yes"
+	MsgMutuallyExclusive       MessageID = "error.mutually_exclusive"
+	MsgInvalidFlag             MessageID = "error.invalid_flag"
+	MsgJSONConflictsWithOutput MessageID = "error.json_conflicts_with_output"
+	MsgSingleProfile           MessageID = "error.single_profile"
+	MsgFlagExcludes            MessageID = "error.flag_excludes"
+	MsgFlagRequires            MessageID = "error.flag_requires"
+
+	MsgAllWithNames       MessageID = "error.all_with_names"
+	MsgNoSecretsSpecified MessageID = "error.no_secrets_specified"
+	MsgUnsupportedMode    MessageID = "error.unsupported_mode"
+	MsgNoMappingSelected  MessageID = "error.no_mapping_selected"
+	MsgRefusingNonDev     MessageID = "error.refusing_non_dev"
+	MsgRefusingNonDevFrom MessageID = "error.refusing_non_dev_from"
+	MsgSecretNotMapped    MessageID = "error.secret_not_mapped"
+	MsgModeNotAllowed     MessageID = "error.mode_not_allowed"
+	MsgAlreadyMapped      MessageID = "error.already_mapped"
+	MsgAlreadyMappedFrom  MessageID = "error.already_mapped_from"
+	MsgFormatMustBeDotenv MessageID = "error.format_must_be_dotenv"
+	MsgWrongSecretType    MessageID = "error.wrong_secret_type"
+	MsgNotConfirmedAnswer MessageID = "error.not_confirmed_answer"
+	MsgNotConfirmedName   MessageID = "error.not_confirmed_name"
+	MsgPushNeedsYes       MessageID = "error.push_needs_yes"
+
+	MsgLoadConfig             MessageID = "error.load_config"
+	MsgOpenSecretAPI          MessageID = "error.open_secret_api"
+	MsgOpenSecretAPIProfile   MessageID = "error.open_secret_api_profile"
+	MsgOpenAccountAPI         MessageID = "error.open_account_api"
+	MsgLocateHomeDir          MessageID = "error.locate_home_dir"
+	MsgLocateConfigDir        MessageID = "error.locate_config_dir"
+	MsgReadDirectory          MessageID = "error.read_directory"
+	MsgWriteFile              MessageID = "error.write_file"
+	MsgReadFile               MessageID = "error.read_file"
+	MsgFileExists             MessageID = "error.file_exists"
+	MsgRefusingReplace        MessageID = "error.refusing_replace"
+	MsgOutsideProjectRoot     MessageID = "error.outside_project_root"
+	MsgPathMustStartWithSlash MessageID = "error.path_must_start_with_slash"
+	MsgStrictWarnings         MessageID = "error.strict_warnings"
+	MsgLoadFeatures           MessageID = "error.load_features"
+	MsgExperimental           MessageID = "error.experimental"
+
+	MsgReadOnlyMappingProfile MessageID = "error.read_only_mapping_profile"
+	MsgReadOnlyProfile        MessageID = "error.read_only_profile"
+	MsgReadOnlyEnv            MessageID = "error.read_only_env"
+	MsgAccessHint             MessageID = "error.access_hint"
+	MsgAccessAskOwnerContact  MessageID = "hint.access_ask_owner_contact"
+	MsgAccessAsk              MessageID = "hint.access_ask"
+	MsgAccessRequestAt        MessageID = "hint.access_request_at"
+
+	MsgCannotEnforceVersion       MessageID = "error.cannot_enforce_version"
+	MsgOutsideRequiredVersion     MessageID = "error.outside_required_version"
+	MsgWarnOutsideRequiredVersion MessageID = "warning.outside_required_version"
+	MsgFieldRequiresVersion       MessageID = "error.field_requires_version"
+	MsgFieldsRequireVersion       MessageID = "error.fields_require_version"
+	MsgUnknownFieldNewer          MessageID = "error.unknown_field_newer"
+	MsgUnknownFieldsNewer         MessageID = "error.unknown_fields_newer"
+
+	MsgReasonAgentOneAddress     MessageID = "reason.agent_one_address"
+	MsgReasonFromRemoteNames     MessageID = "reason.from_remote_names"
+	MsgReasonOlderThanStale      MessageID = "reason.older_than_stale"
+	MsgReasonStaleLocal          MessageID = "reason.stale_local"
+	MsgReasonFakeManifestOnly    MessageID = "reason.fake_manifest_only"
+	MsgReasonRevisionOneSecret   MessageID = "reason.revision_one_secret"
+	MsgReasonDryRunWritesNothing MessageID = "reason.dry_run_writes_nothing"
+	MsgReasonWatchNeverFinishes  MessageID = "reason.watch_never_finishes"
+	MsgReasonWatchLatest         MessageID = "reason.watch_latest"
+	MsgReasonIntervalWatch       MessageID = "reason.interval_watch"
+
+	MsgAccessNoContact         MessageID = "error.access_no_contact"
+	MsgAccessNoRequestURL      MessageID = "error.access_no_request_url"
+	MsgAccessOpen              MessageID = "error.access_open"
+	MsgAgentStopped            MessageID = "error.agent_stopped"
+	MsgListenInvalid           MessageID = "error.listen_invalid"
+	MsgListenNotLoopback       MessageID = "error.listen_not_loopback"
+	MsgCompletionNamesAlone    MessageID = "error.completion_names_alone"
+	MsgCompletionOneShell      MessageID = "error.completion_one_shell"
+	MsgCompletionNameShell     MessageID = "error.completion_name_shell"
+	MsgNotFormatted            MessageID = "error.not_formatted"
+	MsgNeedsRevisionNumber     MessageID = "error.needs_revision_number"
+	MsgMappingProblems         MessageID = "error.mapping_problems"
+	MsgInvalidDoctorOutput     MessageID = "error.invalid_doctor_output"
+	MsgInvalidListOutput       MessageID = "error.invalid_list_output"
+	MsgUnknownColumn           MessageID = "error.unknown_column"
+	MsgStaleOutput             MessageID = "error.stale_output"
+	MsgExecExited              MessageID = "error.exec_exited"
+	MsgFixturesNoMappings      MessageID = "error.fixtures_no_mappings"
+	MsgNeedsKeep               MessageID = "error.needs_keep"
+	MsgGetOneFormat            MessageID = "error.get_one_format"
+	MsgGetRequiresOutput       MessageID = "error.get_requires_output"
+	MsgGetOverwriteHeaderOnly  MessageID = "error.get_overwrite_header_only"
+	MsgImportMappingNotUpdated MessageID = "error.import_mapping_not_updated"
+	MsgNoFilesMatch            MessageID = "error.no_files_match"
+	MsgInvalidPattern          MessageID = "error.invalid_pattern"
+	MsgConfigExists            MessageID = "error.config_exists"
+	MsgInitNeedsTemplate       MessageID = "error.init_needs_template"
+	MsgFromRemoteNeedsIDs      MessageID = "error.from_remote_needs_ids"
+	MsgNoDevSecrets            MessageID = "error.no_dev_secrets"
+	MsgNamingDeviations        MessageID = "error.naming_deviations"
+	MsgPromptBudget            MessageID = "error.prompt_budget"
+	MsgInvalidRevision         MessageID = "error.invalid_revision"
+	MsgRevisionOneSecret       MessageID = "error.revision_one_secret"
+	MsgCIExportNoCI            MessageID = "error.ci_export_no_ci"
+	MsgWatchStopped            MessageID = "error.watch_stopped"
+	MsgFakeNoMappings          MessageID = "error.fake_no_mappings"
+	MsgInvalidKeepEnabled      MessageID = "error.invalid_keep_enabled"
+	MsgInvalidQuery            MessageID = "error.invalid_query"
+	MsgNoStateSecret           MessageID = "error.no_state_secret"
+	MsgSyncConflicts           MessageID = "error.sync_conflicts"
+	MsgTelemetryExpected       MessageID = "error.telemetry_expected"
+	MsgTelemetryUnknown        MessageID = "error.telemetry_unknown"
+	MsgValidationProblems      MessageID = "error.validation_problems"
+	MsgPushBlocked             MessageID = "error.push_blocked"
+	MsgGetwd                   MessageID = "error.getwd"
+	MsgCommandError            MessageID = "error.command"
+	MsgTargetError             MessageID = "error.target"
+	MsgExecRun                 MessageID = "error.exec_run"
+	MsgResume                  MessageID = "error.resume"
+
+	MsgWarnPolicy            MessageID = "warning.policy"
+	MsgWarnExpired           MessageID = "warning.expired"
+	MsgWarnRetrying          MessageID = "warning.retrying"
+	MsgWarnExpiryNotRecorded MessageID = "warning.expiry_not_recorded"
+	MsgWarnIgnoredField      MessageID = "warning.ignored_field"
+	MsgWarnLegacySyncMode    MessageID = "warning.legacy_sync_mode"
+	MsgWarnCoerced           MessageID = "warning.coerced"
+	MsgWarnSkippedCopy       MessageID = "warning.skipped_copy"
+	MsgWarnCertExpired       MessageID = "warning.cert_expired"
+	MsgWarnCertExpires       MessageID = "warning.cert_expires"
+	MsgWarnBudgetCalls       MessageID = "warning.budget_calls"
+	MsgWarnBudgetDuration    MessageID = "warning.budget_duration"
+	MsgBudgetHintPull        MessageID = "hint.budget_pull"
+	MsgBudgetHintPush        MessageID = "hint.budget_push"
+	MsgBudgetHintDoctor      MessageID = "hint.budget_doctor"
+	MsgBudgetHintList        MessageID = "hint.budget_list"
+
+	MsgPolicyViolation      MessageID = "notice.policy_violation"
+	MsgNoRecordedProgress   MessageID = "notice.no_recorded_progress"
+	MsgResuming             MessageID = "notice.resuming"
+	MsgDryRunNothingChanged MessageID = "notice.dry_run_nothing_changed"
+	MsgWatching             MessageID = "notice.watching"
+	MsgWroteHeader          MessageID = "notice.wrote_header"
+	MsgWroteNetrc           MessageID = "notice.wrote_netrc"
+	MsgWroteDockerAuth      MessageID = "notice.wrote_docker_auth"
+
+	MsgConfirmDeleteAll     MessageID = "prompt.confirm_delete_all"
+	MsgConfirmDeleteVersion MessageID = "prompt.confirm_delete_version"
+)
+
+var englishErrors = map[MessageID]string{
+	MsgTakesNoArguments:        "%s takes no arguments",
+	MsgTakesOneSecretName:      "%s takes exactly one secret name",
+	MsgTakesOneFile:            "%s takes exactly one file",
+	MsgTakesOneDirectory:       "%s takes exactly one directory",
+	MsgExpectedOneSecretName:   "expected exactly one secret name",
+	MsgExpectedUsage:           "expected: %s",
+	MsgExpectedSubcommand:      "expected subcommand: %s",
+	MsgUnexpectedArguments:     "unexpected arguments: %v",
+	MsgRequiresFlag:            "%s requires %s",
+	MsgNeedsYes:                "%s needs --yes",
+	MsgMutuallyExclusive:       "%s and %s are mutually exclusive",
+	MsgInvalidFlag:             "invalid --%s: %w",
+	MsgJSONConflictsWithOutput: "--json conflicts with --output %s",
+	MsgSingleProfile:           "%s takes a single --profile",
+	MsgFlagExcludes:            "--%s cannot be combined with --%s: %s",
+	MsgFlagRequires:            "--%s requires --%s: %s",
+
+	MsgAllWithNames:       "cannot use --all with explicit secret names",
+	MsgNoSecretsSpecified: "no secrets specified (use --all or pass secret names)",
+	MsgUnsupportedMode:    "unsupported command mode: %s",
+	MsgNoMappingSelected:  "no mapping entries selected for %s",
+	MsgRefusingNonDev:     "refusing non-dev secret name: %s",
+	MsgRefusingNonDevFrom: "refusing non-dev secret name: %s (from %s)",
+	MsgSecretNotMapped:    "secret not found in mapping: %s",
+	MsgModeNotAllowed:     "secret %s not allowed in %s mode (mapping.mode=%s)",
+	MsgAlreadyMapped:      "%s is already mapped; use push",
+	MsgAlreadyMappedFrom:  "%s (from %s) is already mapped; use push",
+	MsgFormatMustBeDotenv: "%s %s: mapping format must be dotenv",
+	MsgWrongSecretType:    "%s %s: secret type is %s, expected %s",
+	MsgNotConfirmedAnswer: "%s: not confirmed (answer y, or pass --yes)",
+	MsgNotConfirmedName:   "%s %s: not confirmed (type the secret name exactly)",
+	MsgPushNeedsYes:       "refusing to push multiple secrets without --yes",
+
+	MsgLoadConfig:             "load config: %w",
+	MsgOpenSecretAPI:          "open secret api: %w",
+	MsgOpenSecretAPIProfile:   "open secret api for profile %s: %w",
+	MsgOpenAccountAPI:         "open account api: %w",
+	MsgLocateHomeDir:          "locate home dir: %w",
+	MsgLocateConfigDir:        "locate user config dir: %w",
+	MsgReadDirectory:          "read directory: %w",
+	MsgWriteFile:              "write %s: %w",
+	MsgReadFile:               "read %s: %w",
+	MsgFileExists:             "file exists (use --overwrite): %s",
+	MsgRefusingReplace:        "refusing to replace %s: %w",
+	MsgOutsideProjectRoot:     "%s is outside the project root %s",
+	MsgPathMustStartWithSlash: "--path must start with '/', got %q",
+	MsgStrictWarnings:         "%d warning(s) treated as errors (--strict-warnings)",
+	MsgLoadFeatures:           "load experimental features: %w",
+	MsgExperimental:           "%s is experimental; enable it with %s=%s or \"features\": [%q] in %s",
+
+	MsgReadOnlyMappingProfile: "%w\nhint: the credentials from profile %q (set by the mapping) can read secrets but not write them in that profile's default project; use an API key whose IAM policy grants SecretManagerFullAccess there",
+	MsgReadOnlyProfile:        "%w\nhint: the credentials from profile %q can read secrets but not write them in project %s; use an API key whose IAM policy grants SecretManagerFullAccess there (or pass --profile with one)",
+	MsgReadOnlyEnv:            "%w\nhint: the credentials from SCW_ACCESS_KEY/SCW_SECRET_KEY can read secrets but not write them in project %s; use an API key whose IAM policy grants SecretManagerFullAccess there (or pass --profile with one)",
+	MsgAccessHint:             "%w\nhint: your credentials cannot read this project's secrets yet%v%v",
+	MsgAccessAskOwnerContact:  "; ask %s (%s) for access",
+	MsgAccessAsk:              "; ask %s for access",
+	MsgAccessRequestAt:        "; request it at %s ('dev-vault access --open' opens the page)",
+
+	MsgCannotEnforceVersion:       "cannot enforce required_version %q: dev-vault %s is not a release build",
+	MsgOutsideRequiredVersion:     "dev-vault %s is outside required_version %q in %s; install a matching release",
+	MsgWarnOutsideRequiredVersion: "dev-vault %s is outside required_version %q in %s; install a matching release or pass --enforce-version to refuse",
+	MsgFieldRequiresVersion:       "field %s requires dev-vault %s (this is %s, required_version %q); upgrade, or pass --ignore-unknown to skip it",
+	MsgFieldsRequireVersion:       "fields %s require dev-vault %s (this is %s, required_version %q); upgrade, or pass --ignore-unknown to skip them",
+	MsgUnknownFieldNewer:          "%w; a newer dev-vault may know it: upgrade, or pass --ignore-unknown to skip it",
+	MsgUnknownFieldsNewer:         "%w; a newer dev-vault may know them: upgrade, or pass --ignore-unknown to skip them",
+
+	MsgReasonAgentOneAddress:     "the agent listens on one address",
+	MsgReasonFromRemoteNames:     "--from-remote names the mappings after the project's secrets",
+	MsgReasonOlderThanStale:      "it sets the age --stale reports",
+	MsgReasonStaleLocal:          "--stale lists the local files of this manifest, not remote secrets",
+	MsgReasonFakeManifestOnly:    "--fake writes placeholders from the manifest alone, without reading secrets",
+	MsgReasonRevisionOneSecret:   "a revision belongs to one secret; name it instead",
+	MsgReasonDryRunWritesNothing: "a dry run writes nothing to watch or export",
+	MsgReasonWatchNeverFinishes:  "--watch never finishes, so later CI steps would never get the variables",
+	MsgReasonWatchLatest:         "--watch follows the latest enabled revision",
+	MsgReasonIntervalWatch:       "it sets how often --watch polls",
+
+	MsgAccessNoContact:         "access: %s has no access_contact; ask the project maintainers",
+	MsgAccessNoRequestURL:      "access --open: access_contact has no request_url",
+	MsgAccessOpen:              "access: open %s: %w",
+	MsgAgentStopped:            "agent stopped: %w",
+	MsgListenInvalid:           "invalid --listen %q: %w",
+	MsgListenNotLoopback:       "invalid --listen %q: the agent only listens on a loopback address such as 127.0.0.1",
+	MsgCompletionNamesAlone:    "--names takes no shell name, --install or --uninstall",
+	MsgCompletionOneShell:      "completion takes at most one shell name",
+	MsgCompletionNameShell:     "%w; name it: dev-vault completion <%s>",
+	MsgNotFormatted:            "%s is not formatted (run 'dev-vault config fmt')",
+	MsgNeedsRevisionNumber:     "%s needs --%s with a positive revision number, got %q",
+	MsgMappingProblems:         "%d mapping problem(s)",
+	MsgInvalidDoctorOutput:     "invalid --output %q (expected table, json or vscode)",
+	MsgInvalidListOutput:       "invalid --output %q (expected table, json or csv)",
+	MsgUnknownColumn:           "unknown column %q (expected: %s)",
+	MsgStaleOutput:             "--stale supports --output table or json, without --columns",
+	MsgExecExited:              "exec %s: %s exited with code %d",
+	MsgFixturesNoMappings:      "fixtures: no dotenv mappings selected for pull",
+	MsgNeedsKeep:               "gc needs --keep with a positive number of versions, got %q",
+	MsgGetOneFormat:            "get requires exactly one of --as-netrc, --as-docker-config, --as-header",
+	MsgGetRequiresOutput:       "get requires --output; credentials are never printed",
+	MsgGetOverwriteHeaderOnly:  "--overwrite only applies to --as-header; other formats update the file in place",
+	MsgImportMappingNotUpdated: "%s was created (rev=%d) but %s was not updated: %w; add the mapping entry by hand",
+	MsgNoFilesMatch:            "no files in %s match %s",
+	MsgInvalidPattern:          "--pattern must be a file name with exactly one {name}, got %q",
+	MsgConfigExists:            "config exists (use --force): %s",
+	MsgInitNeedsTemplate:       "init requires --template, or --organization-id, --project-id, and --region ('dev-vault projects' and 'dev-vault regions' list them)",
+	MsgFromRemoteNeedsIDs:      "--from-remote requires --organization-id, --project-id, and --region ('dev-vault projects' and 'dev-vault regions' list them)",
+	MsgNoDevSecrets:            "no -dev secrets found in project %s",
+	MsgNamingDeviations:        "%d naming deviation(s)",
+	MsgPromptBudget:            "prompt: no status within %s",
+	MsgInvalidRevision:         "invalid --revision %q: want a positive revision number",
+	MsgRevisionOneSecret:       "--revision needs exactly one <secret-dev>",
+	MsgCIExportNoCI:            "--ci-export needs a CI environment (GitHub Actions, GitLab CI, or CircleCI)",
+	MsgWatchStopped:            "pull --watch stopped: %w",
+	MsgFakeNoMappings:          "pull --fake: no dotenv mappings with fake_keys selected",
+	MsgInvalidKeepEnabled:      "invalid --keep-enabled %q: want a positive number of versions",
+	MsgInvalidQuery:            "invalid <query>: %w",
+	MsgNoStateSecret:           "state: %s has no state_secret",
+	MsgSyncConflicts:           "sync: %d conflict(s); resolve them with pull --overwrite or push, then sync again",
+	MsgTelemetryExpected:       "expected exactly one of: on, off, status",
+	MsgTelemetryUnknown:        "unknown telemetry action %q (expected on, off, or status)",
+	MsgValidationProblems:      "%d validation problem(s)",
+	MsgPushBlocked:             "push blocked by policy: %d violation(s)",
+	MsgGetwd:                   "getwd: %w",
+	MsgCommandError:            "%s: %w",
+	MsgTargetError:             "%s %s: %w",
+	MsgExecRun:                 "exec %s: %s: %w",
+	MsgResume:                  "resume %s: %w",
+
+	MsgWarnPolicy:            "policy: %s",
+	MsgWarnExpired:           "%s (%s) has expired; pull it again or delete it",
+	MsgWarnRetrying:          "%v; retrying in %s",
+	MsgWarnExpiryNotRecorded: "expiry of the pulled files not recorded: %v",
+	MsgWarnIgnoredField:      "ignoring unknown field %q in %s",
+	MsgWarnLegacySyncMode:    "mapping %q uses legacy mode=sync; use mode=both (sync will be removed in a future major release)",
+	MsgWarnCoerced:           "coerced %s: secret is %s, mapping.type is %s",
+	MsgWarnSkippedCopy:       "skipped %s at path %s: already mapped from path %s",
+	MsgWarnCertExpired:       "certificate %s expired on %s",
+	MsgWarnCertExpires:       "certificate %s expires in %d days (%s)",
+	MsgWarnBudgetCalls:       "%s made %d API calls (budget %d)%v",
+	MsgWarnBudgetDuration:    "%s took %s (budget %s)%v",
+	MsgBudgetHintPull:        "; each mapping costs a read; pass secret names instead of --all, or disable mappings you do not use",
+	MsgBudgetHintPush:        "; each mapping costs a write; pass secret names instead of --all",
+	MsgBudgetHintDoctor:      "; each mapping is checked remotely; disable mappings you do not use",
+	MsgBudgetHintList:        "; narrow the listing with --type or --path",
+
+	MsgPolicyViolation:      "policy violation: %s",
+	MsgNoRecordedProgress:   "no recorded %s progress; running all targets",
+	MsgResuming:             "resuming %s: skipping %d completed target(s)",
+	MsgDryRunNothingChanged: "dry run: nothing changed; pass --yes to apply",
+	MsgWatching:             "watching %d secret(s) every %s; press Ctrl-C to stop",
+	MsgWroteHeader:          "wrote Authorization header from %s (rev=%d) -> %s",
+	MsgWroteNetrc:           "wrote netrc entry for %s from %s (rev=%d) -> %s",
+	MsgWroteDockerAuth:      "wrote docker auth for %s from %s (rev=%d) -> %s",
+
+	MsgConfirmDeleteAll:     "Delete %s (all versions)? This cannot be undone. Type the secret name to confirm: ",
+	MsgConfirmDeleteVersion: "Delete %s (revision %d)? This cannot be undone. Type the secret name to confirm: ",
+}
@@ -0,0 +1,160 @@
+package i18n
+
+// frenchErrors translates the errors, warnings, and notices to French.
+var frenchErrors = map[MessageID]string{
+	MsgTakesNoArguments:        "%s n'accepte aucun argument",
+	MsgTakesOneSecretName:      "%s accepte exactement un nom de secret",
+	MsgTakesOneFile:            "%s accepte exactement un fichier",
+	MsgTakesOneDirectory:       "%s accepte exactement un répertoire",
+	MsgExpectedOneSecretName:   "un seul nom de secret attendu",
+	MsgExpectedUsage:           "attendu : %s",
+	MsgExpectedSubcommand:      "sous-commande attendue : %s",
+	MsgUnexpectedArguments:     "arguments inattendus : %v",
+	MsgRequiresFlag:            "%s exige %s",
+	MsgNeedsYes:                "%s exige --yes",
+	MsgMutuallyExclusive:       "%s et %s s'excluent mutuellement",
+	MsgInvalidFlag:             "--%s invalide : %w",
+	MsgJSONConflictsWithOutput: "--json est incompatible avec --output %s",
+	MsgSingleProfile:           "%s n'accepte qu'un seul --profile",
+	MsgFlagExcludes:            "--%s ne peut pas être combiné avec --%s : %s",
+	MsgFlagRequires:            "--%s exige --%s : %s",
+
+	MsgAllWithNames:       "impossible d'utiliser --all avec des noms de secrets explicites",
+	MsgNoSecretsSpecified: "aucun secret indiqué (utilisez --all ou passez des noms de secrets)",
+	MsgUnsupportedMode:    "mode de commande non pris en charge : %s",
+	MsgNoMappingSelected:  "aucune entrée de mapping sélectionnée pour %s",
+	MsgRefusingNonDev:     "nom de secret non -dev refusé : %s",
+	MsgRefusingNonDevFrom: "nom de secret non -dev refusé : %s (depuis %s)",
+	MsgSecretNotMapped:    "secret introuvable dans le mapping : %s",
+	MsgModeNotAllowed:     "secret %s non autorisé en mode %s (mapping.mode=%s)",
+	MsgAlreadyMapped:      "%s est déjà mappé ; utilisez push",
+	MsgAlreadyMappedFrom:  "%s (depuis %s) est déjà mappé ; utilisez push",
+	MsgFormatMustBeDotenv: "%s %s : le format du mapping doit être dotenv",
+	MsgWrongSecretType:    "%s %s : le type du secret est %s, %s attendu",
+	MsgNotConfirmedAnswer: "%s : non confirmé (répondez y, ou passez --yes)",
+	MsgNotConfirmedName:   "%s %s : non confirmé (tapez exactement le nom du secret)",
+	MsgPushNeedsYes:       "envoi de plusieurs secrets refusé sans --yes",
+
+	MsgLoadConfig:             "chargement de la configuration : %w",
+	MsgOpenSecretAPI:          "ouverture de l'API des secrets : %w",
+	MsgOpenSecretAPIProfile:   "ouverture de l'API des secrets pour le profil %s : %w",
+	MsgOpenAccountAPI:         "ouverture de l'API du compte : %w",
+	MsgLocateHomeDir:          "localisation du répertoire personnel : %w",
+	MsgLocateConfigDir:        "localisation du répertoire de configuration utilisateur : %w",
+	MsgReadDirectory:          "lecture du répertoire : %w",
+	MsgWriteFile:              "écriture de %s : %w",
+	MsgReadFile:               "lecture de %s : %w",
+	MsgFileExists:             "le fichier existe (utilisez --overwrite) : %s",
+	MsgRefusingReplace:        "refus de remplacer %s : %w",
+	MsgOutsideProjectRoot:     "%s est hors de la racine du projet %s",
+	MsgPathMustStartWithSlash: "--path doit commencer par '/', reçu %q",
+	MsgStrictWarnings:         "%d avertissement(s) traité(s) comme des erreurs (--strict-warnings)",
+	MsgLoadFeatures:           "chargement des fonctionnalités expérimentales : %w",
+	MsgExperimental:           "%s est expérimental ; activez-le avec %s=%s ou \"features\": [%q] dans %s",
+
+	MsgReadOnlyMappingProfile: "%w\nindice : les identifiants du profil %q (défini par le mapping) peuvent lire les secrets mais pas les écrire dans le projet par défaut de ce profil ; utilisez une clé d'API dont la politique IAM accorde SecretManagerFullAccess sur ce projet",
+	MsgReadOnlyProfile:        "%w\nindice : les identifiants du profil %q peuvent lire les secrets mais pas les écrire dans le projet %s ; utilisez une clé d'API dont la politique IAM accorde SecretManagerFullAccess sur ce projet (ou passez --profile avec une telle clé)",
+	MsgReadOnlyEnv:            "%w\nindice : les identifiants de SCW_ACCESS_KEY/SCW_SECRET_KEY peuvent lire les secrets mais pas les écrire dans le projet %s ; utilisez une clé d'API dont la politique IAM accorde SecretManagerFullAccess sur ce projet (ou passez --profile avec une telle clé)",
+	MsgAccessHint:             "%w\nindice : vos identifiants ne peuvent pas encore lire les secrets de ce projet%v%v",
+	MsgAccessAskOwnerContact:  " ; demandez l'accès à %s (%s)",
+	MsgAccessAsk:              " ; demandez l'accès à %s",
+	MsgAccessRequestAt:        " ; demandez-le sur %s ('dev-vault access --open' ouvre la page)",
+
+	MsgCannotEnforceVersion:       "impossible d'appliquer required_version %q : dev-vault %s n'est pas une version publiée",
+	MsgOutsideRequiredVersion:     "dev-vault %s est hors de required_version %q dans %s ; installez une version correspondante",
+	MsgWarnOutsideRequiredVersion: "dev-vault %s est hors de required_version %q dans %s ; installez une version correspondante ou passez --enforce-version pour refuser",
+	MsgFieldRequiresVersion:       "le champ %s exige dev-vault %s (celui-ci est %s, required_version %q) ; mettez à jour, ou passez --ignore-unknown pour l'ignorer",
+	MsgFieldsRequireVersion:       "les champs %s exigent dev-vault %s (celui-ci est %s, required_version %q) ; mettez à jour, ou passez --ignore-unknown pour les ignorer",
+	MsgUnknownFieldNewer:          "%w ; une version plus récente de dev-vault le connaît peut-être : mettez à jour, ou passez --ignore-unknown pour l'ignorer",
+	MsgUnknownFieldsNewer:         "%w ; une version plus récente de dev-vault les connaît peut-être : mettez à jour, ou passez --ignore-unknown pour les ignorer",
+
+	MsgReasonAgentOneAddress:     "l'agent écoute sur une seule adresse",
+	MsgReasonFromRemoteNames:     "--from-remote nomme les mappings d'après les secrets du projet",
+	MsgReasonOlderThanStale:      "elle fixe l'âge signalé par --stale",
+	MsgReasonStaleLocal:          "--stale liste les fichiers locaux de ce manifeste, pas les secrets distants",
+	MsgReasonFakeManifestOnly:    "--fake écrit des valeurs fictives à partir du seul manifeste, sans lire les secrets",
+	MsgReasonRevisionOneSecret:   "une révision appartient à un seul secret ; nommez-le plutôt",
+	MsgReasonDryRunWritesNothing: "une simulation n'écrit rien à surveiller ni à exporter",
+	MsgReasonWatchNeverFinishes:  "--watch ne se termine jamais, les étapes de CI suivantes n'obtiendraient donc jamais les variables",
+	MsgReasonWatchLatest:         "--watch suit la dernière révision activée",
+	MsgReasonIntervalWatch:       "elle fixe la fréquence d'interrogation de --watch",
+
+	MsgAccessNoContact:         "access : %s n'a pas d'access_contact ; demandez aux responsables du projet",
+	MsgAccessNoRequestURL:      "access --open : access_contact n'a pas de request_url",
+	MsgAccessOpen:              "access : ouverture de %s : %w",
+	MsgAgentStopped:            "agent arrêté : %w",
+	MsgListenInvalid:           "--listen %q invalide : %w",
+	MsgListenNotLoopback:       "--listen %q invalide : l'agent n'écoute que sur une adresse de bouclage comme 127.0.0.1",
+	MsgCompletionNamesAlone:    "--names n'accepte ni nom de shell, ni --install, ni --uninstall",
+	MsgCompletionOneShell:      "completion accepte au plus un nom de shell",
+	MsgCompletionNameShell:     "%w ; indiquez-le : dev-vault completion <%s>",
+	MsgNotFormatted:            "%s n'est pas formaté (lancez 'dev-vault config fmt')",
+	MsgNeedsRevisionNumber:     "%s exige --%s avec un numéro de révision positif, reçu %q",
+	MsgMappingProblems:         "%d problème(s) de mapping",
+	MsgInvalidDoctorOutput:     "--output %q invalide (attendu : table, json ou vscode)",
+	MsgInvalidListOutput:       "--output %q invalide (attendu : table, json ou csv)",
+	MsgUnknownColumn:           "colonne inconnue %q (attendu : %s)",
+	MsgStaleOutput:             "--stale accepte --output table ou json, sans --columns",
+	MsgExecExited:              "exec %s : %s s'est terminé avec le code %d",
+	MsgFixturesNoMappings:      "fixtures : aucun mapping dotenv sélectionné pour le pull",
+	MsgNeedsKeep:               "gc exige --keep avec un nombre positif de versions, reçu %q",
+	MsgGetOneFormat:            "get exige exactement une option parmi --as-netrc, --as-docker-config, --as-header",
+	MsgGetRequiresOutput:       "get exige --output ; les identifiants ne sont jamais affichés",
+	MsgGetOverwriteHeaderOnly:  "--overwrite ne s'applique qu'à --as-header ; les autres formats mettent le fichier à jour sur place",
+	MsgImportMappingNotUpdated: "%s a été créé (rev=%d) mais %s n'a pas été mis à jour : %w ; ajoutez l'entrée de mapping à la main",
+	MsgNoFilesMatch:            "aucun fichier de %s ne correspond à %s",
+	MsgInvalidPattern:          "--pattern doit être un nom de fichier avec exactement un {name}, reçu %q",
+	MsgConfigExists:            "la configuration existe (utilisez --force) : %s",
+	MsgInitNeedsTemplate:       "init exige --template, ou --organization-id, --project-id et --region ('dev-vault projects' et 'dev-vault regions' les listent)",
+	MsgFromRemoteNeedsIDs:      "--from-remote exige --organization-id, --project-id et --region ('dev-vault projects' et 'dev-vault regions' les listent)",
+	MsgNoDevSecrets:            "aucun secret -dev trouvé dans le projet %s",
+	MsgNamingDeviations:        "%d écart(s) de nommage",
+	MsgPromptBudget:            "prompt : aucun état en %s",
+	MsgInvalidRevision:         "--revision %q invalide : un numéro de révision positif est attendu",
+	MsgRevisionOneSecret:       "--revision exige exactement un <secret-dev>",
+	MsgCIExportNoCI:            "--ci-export exige un environnement de CI (GitHub Actions, GitLab CI ou CircleCI)",
+	MsgWatchStopped:            "pull --watch arrêté : %w",
+	MsgFakeNoMappings:          "pull --fake : aucun mapping dotenv avec fake_keys sélectionné",
+	MsgInvalidKeepEnabled:      "--keep-enabled %q invalide : un nombre positif de versions est attendu",
+	MsgInvalidQuery:            "<query> invalide : %w",
+	MsgNoStateSecret:           "state : %s n'a pas de state_secret",
+	MsgSyncConflicts:           "sync : %d conflit(s) ; résolvez-les avec pull --overwrite ou push, puis relancez sync",
+	MsgTelemetryExpected:       "une seule valeur attendue parmi : on, off, status",
+	MsgTelemetryUnknown:        "action de télémétrie inconnue %q (attendu : on, off ou status)",
+	MsgValidationProblems:      "%d problème(s) de validation",
+	MsgPushBlocked:             "push bloqué par la politique : %d violation(s)",
+	MsgGetwd:                   "répertoire courant : %w",
+	MsgCommandError:            "%s : %w",
+	MsgTargetError:             "%s %s : %w",
+	MsgExecRun:                 "exec %s : %s : %w",
+	MsgResume:                  "reprise de %s : %w",
+
+	MsgWarnPolicy:            "politique : %s",
+	MsgWarnExpired:           "%s (%s) a expiré ; récupérez-le à nouveau ou supprimez-le",
+	MsgWarnRetrying:          "%v ; nouvel essai dans %s",
+	MsgWarnExpiryNotRecorded: "expiration des fichiers récupérés non enregistrée : %v",
+	MsgWarnIgnoredField:      "champ inconnu %q ignoré dans %s",
+	MsgWarnLegacySyncMode:    "le mapping %q utilise l'ancien mode=sync ; utilisez mode=both (sync sera supprimé dans une future version majeure)",
+	MsgWarnCoerced:           "%s converti : le secret est de type %s, mapping.type vaut %s",
+	MsgWarnSkippedCopy:       "%s ignoré au chemin %s : déjà mappé depuis le chemin %s",
+	MsgWarnCertExpired:       "le certificat %s a expiré le %s",
+	MsgWarnCertExpires:       "le certificat %s expire dans %d jours (%s)",
+	MsgWarnBudgetCalls:       "%s a effectué %d appels d'API (budget %d)%v",
+	MsgWarnBudgetDuration:    "%s a duré %s (budget %s)%v",
+	MsgBudgetHintPull:        " ; chaque mapping coûte une lecture ; passez des noms de secrets au lieu de --all, ou désactivez les mappings inutilisés",
+	MsgBudgetHintPush:        " ; chaque mapping coûte une écriture ; passez des noms de secrets au lieu de --all",
+	MsgBudgetHintDoctor:      " ; chaque mapping est vérifié à distance ; désactivez les mappings inutilisés",
+	MsgBudgetHintList:        " ; restreignez la liste avec --type ou --path",
+
+	MsgPolicyViolation:      "violation de politique : %s",
+	MsgNoRecordedProgress:   "aucune progression de %s enregistrée ; exécution de toutes les cibles",
+	MsgResuming:             "reprise de %s : %d cible(s) terminée(s) ignorée(s)",
+	MsgDryRunNothingChanged: "simulation : rien n'a changé ; passez --yes pour appliquer",
+	MsgWatching:             "surveillance de %d secret(s) toutes les %s ; appuyez sur Ctrl-C pour arrêter",
+	MsgWroteHeader:          "en-tête Authorization écrit depuis %s (rev=%d) -> %s",
+	MsgWroteNetrc:           "entrée netrc pour %s écrite depuis %s (rev=%d) -> %s",
+	MsgWroteDockerAuth:      "authentification docker pour %s écrite depuis %s (rev=%d) -> %s",
+
+	MsgConfirmDeleteAll:     "Supprimer %s (toutes les versions) ? Cette action est irréversible. Tapez le nom du secret pour confirmer : ",
+	MsgConfirmDeleteVersion: "Supprimer %s (révision %d) ? Cette action est irréversible. Tapez le nom du secret pour confirmer : ",
+}
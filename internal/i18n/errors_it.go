@@ -0,0 +1,160 @@
+package i18n
+
+// italianErrors translates the errors, warnings, and notices to Italian.
+var italianErrors = map[MessageID]string{
+	MsgTakesNoArguments:        "%s non accetta argomenti",
+	MsgTakesOneSecretName:      "%s accetta esattamente un nome di segreto",
+	MsgTakesOneFile:            "%s accetta esattamente un file",
+	MsgTakesOneDirectory:       "%s accetta esattamente una directory",
+	MsgExpectedOneSecretName:   "atteso esattamente un nome di segreto",
+	MsgExpectedUsage:           "atteso: %s",
+	MsgExpectedSubcommand:      "sottocomando atteso: %s",
+	MsgUnexpectedArguments:     "argomenti imprevisti: %v",
+	MsgRequiresFlag:            "%s richiede %s",
+	MsgNeedsYes:                "%s richiede --yes",
+	MsgMutuallyExclusive:       "%s e %s si escludono a vicenda",
+	MsgInvalidFlag:             "--%s non valido: %w",
+	MsgJSONConflictsWithOutput: "--json è in conflitto con --output %s",
+	MsgSingleProfile:           "%s accetta un solo --profile",
+	MsgFlagExcludes:            "--%s non può essere combinato con --%s: %s",
+	MsgFlagRequires:            "--%s richiede --%s: %s",
+
+	MsgAllWithNames:       "impossibile usare --all con nomi di segreti espliciti",
+	MsgNoSecretsSpecified: "nessun segreto indicato (usa --all o passa i nomi dei segreti)",
+	MsgUnsupportedMode:    "modalità di comando non supportata: %s",
+	MsgNoMappingSelected:  "nessuna voce di mapping selezionata per %s",
+	MsgRefusingNonDev:     "nome di segreto non -dev rifiutato: %s",
+	MsgRefusingNonDevFrom: "nome di segreto non -dev rifiutato: %s (da %s)",
+	MsgSecretNotMapped:    "segreto non trovato nel mapping: %s",
+	MsgModeNotAllowed:     "segreto %s non consentito in modalità %s (mapping.mode=%s)",
+	MsgAlreadyMapped:      "%s è già mappato; usa push",
+	MsgAlreadyMappedFrom:  "%s (da %s) è già mappato; usa push",
+	MsgFormatMustBeDotenv: "%s %s: il formato del mapping deve essere dotenv",
+	MsgWrongSecretType:    "%s %s: il tipo del segreto è %s, atteso %s",
+	MsgNotConfirmedAnswer: "%s: non confermato (rispondi y, o passa --yes)",
+	MsgNotConfirmedName:   "%s %s: non confermato (digita esattamente il nome del segreto)",
+	MsgPushNeedsYes:       "invio di più segreti rifiutato senza --yes",
+
+	MsgLoadConfig:             "caricamento della configurazione: %w",
+	MsgOpenSecretAPI:          "apertura dell'API dei segreti: %w",
+	MsgOpenSecretAPIProfile:   "apertura dell'API dei segreti per il profilo %s: %w",
+	MsgOpenAccountAPI:         "apertura dell'API dell'account: %w",
+	MsgLocateHomeDir:          "individuazione della directory home: %w",
+	MsgLocateConfigDir:        "individuazione della directory di configurazione utente: %w",
+	MsgReadDirectory:          "lettura della directory: %w",
+	MsgWriteFile:              "scrittura di %s: %w",
+	MsgReadFile:               "lettura di %s: %w",
+	MsgFileExists:             "il file esiste (usa --overwrite): %s",
+	MsgRefusingReplace:        "sostituzione di %s rifiutata: %w",
+	MsgOutsideProjectRoot:     "%s è fuori dalla radice del progetto %s",
+	MsgPathMustStartWithSlash: "--path deve iniziare con '/', ricevuto %q",
+	MsgStrictWarnings:         "%d avviso/i trattato/i come errori (--strict-warnings)",
+	MsgLoadFeatures:           "caricamento delle funzionalità sperimentali: %w",
+	MsgExperimental:           "%s è sperimentale; abilitalo con %s=%s o \"features\": [%q] in %s",
+
+	MsgReadOnlyMappingProfile: "%w\nsuggerimento: le credenziali del profilo %q (impostato dal mapping) possono leggere i segreti ma non scriverli nel progetto predefinito di quel profilo; usa una chiave API la cui policy IAM conceda SecretManagerFullAccess su quel progetto",
+	MsgReadOnlyProfile:        "%w\nsuggerimento: le credenziali del profilo %q possono leggere i segreti ma non scriverli nel progetto %s; usa una chiave API la cui policy IAM conceda SecretManagerFullAccess su quel progetto (o passa --profile con una di queste)",
+	MsgReadOnlyEnv:            "%w\nsuggerimento: le credenziali di SCW_ACCESS_KEY/SCW_SECRET_KEY possono leggere i segreti ma non scriverli nel progetto %s; usa una chiave API la cui policy IAM conceda SecretManagerFullAccess su quel progetto (o passa --profile con una di queste)",
+	MsgAccessHint:             "%w\nsuggerimento: le tue credenziali non possono ancora leggere i segreti di questo progetto%v%v",
+	MsgAccessAskOwnerContact:  "; chiedi l'accesso a %s (%s)",
+	MsgAccessAsk:              "; chiedi l'accesso a %s",
+	MsgAccessRequestAt:        "; richiedilo su %s ('dev-vault access --open' apre la pagina)",
+
+	MsgCannotEnforceVersion:       "impossibile applicare required_version %q: dev-vault %s non è una build di release",
+	MsgOutsideRequiredVersion:     "dev-vault %s è fuori da required_version %q in %s; installa una release compatibile",
+	MsgWarnOutsideRequiredVersion: "dev-vault %s è fuori da required_version %q in %s; installa una release compatibile o passa --enforce-version per rifiutare",
+	MsgFieldRequiresVersion:       "il campo %s richiede dev-vault %s (questo è %s, required_version %q); aggiorna, o passa --ignore-unknown per ignorarlo",
+	MsgFieldsRequireVersion:       "i campi %s richiedono dev-vault %s (questo è %s, required_version %q); aggiorna, o passa --ignore-unknown per ignorarli",
+	MsgUnknownFieldNewer:          "%w; una versione più recente di dev-vault potrebbe conoscerlo: aggiorna, o passa --ignore-unknown per ignorarlo",
+	MsgUnknownFieldsNewer:         "%w; una versione più recente di dev-vault potrebbe conoscerli: aggiorna, o passa --ignore-unknown per ignorarli",
+
+	MsgReasonAgentOneAddress:     "l'agent ascolta su un solo indirizzo",
+	MsgReasonFromRemoteNames:     "--from-remote dà ai mapping i nomi dei segreti del progetto",
+	MsgReasonOlderThanStale:      "imposta l'età segnalata da --stale",
+	MsgReasonStaleLocal:          "--stale elenca i file locali di questo manifesto, non i segreti remoti",
+	MsgReasonFakeManifestOnly:    "--fake scrive valori fittizi dal solo manifesto, senza leggere i segreti",
+	MsgReasonRevisionOneSecret:   "una revisione appartiene a un solo segreto; indicalo invece",
+	MsgReasonDryRunWritesNothing: "una prova non scrive nulla da monitorare o esportare",
+	MsgReasonWatchNeverFinishes:  "--watch non termina mai, quindi i passi di CI successivi non riceverebbero mai le variabili",
+	MsgReasonWatchLatest:         "--watch segue l'ultima revisione abilitata",
+	MsgReasonIntervalWatch:       "imposta la frequenza con cui --watch interroga",
+
+	MsgAccessNoContact:         "access: %s non ha access_contact; chiedi ai responsabili del progetto",
+	MsgAccessNoRequestURL:      "access --open: access_contact non ha request_url",
+	MsgAccessOpen:              "access: apertura di %s: %w",
+	MsgAgentStopped:            "agent fermato: %w",
+	MsgListenInvalid:           "--listen %q non valido: %w",
+	MsgListenNotLoopback:       "--listen %q non valido: l'agent ascolta solo su un indirizzo di loopback come 127.0.0.1",
+	MsgCompletionNamesAlone:    "--names non accetta un nome di shell, --install o --uninstall",
+	MsgCompletionOneShell:      "completion accetta al massimo un nome di shell",
+	MsgCompletionNameShell:     "%w; indicala: dev-vault completion <%s>",
+	MsgNotFormatted:            "%s non è formattato (esegui 'dev-vault config fmt')",
+	MsgNeedsRevisionNumber:     "%s richiede --%s con un numero di revisione positivo, ricevuto %q",
+	MsgMappingProblems:         "%d problema/i di mapping",
+	MsgInvalidDoctorOutput:     "--output %q non valido (previsto: table, json o vscode)",
+	MsgInvalidListOutput:       "--output %q non valido (previsto: table, json o csv)",
+	MsgUnknownColumn:           "colonna sconosciuta %q (previste: %s)",
+	MsgStaleOutput:             "--stale supporta --output table o json, senza --columns",
+	MsgExecExited:              "exec %s: %s è terminato con codice %d",
+	MsgFixturesNoMappings:      "fixtures: nessun mapping dotenv selezionato per il pull",
+	MsgNeedsKeep:               "gc richiede --keep con un numero positivo di versioni, ricevuto %q",
+	MsgGetOneFormat:            "get richiede esattamente una tra --as-netrc, --as-docker-config, --as-header",
+	MsgGetRequiresOutput:       "get richiede --output; le credenziali non vengono mai stampate",
+	MsgGetOverwriteHeaderOnly:  "--overwrite si applica solo a --as-header; gli altri formati aggiornano il file sul posto",
+	MsgImportMappingNotUpdated: "%s è stato creato (rev=%d) ma %s non è stato aggiornato: %w; aggiungi la voce di mapping a mano",
+	MsgNoFilesMatch:            "nessun file in %s corrisponde a %s",
+	MsgInvalidPattern:          "--pattern deve essere un nome di file con esattamente un {name}, ricevuto %q",
+	MsgConfigExists:            "la configurazione esiste (usa --force): %s",
+	MsgInitNeedsTemplate:       "init richiede --template, oppure --organization-id, --project-id e --region ('dev-vault projects' e 'dev-vault regions' li elencano)",
+	MsgFromRemoteNeedsIDs:      "--from-remote richiede --organization-id, --project-id e --region ('dev-vault projects' e 'dev-vault regions' li elencano)",
+	MsgNoDevSecrets:            "nessun segreto -dev trovato nel progetto %s",
+	MsgNamingDeviations:        "%d deviazione/i di denominazione",
+	MsgPromptBudget:            "prompt: nessuno stato entro %s",
+	MsgInvalidRevision:         "--revision %q non valido: serve un numero di revisione positivo",
+	MsgRevisionOneSecret:       "--revision richiede esattamente un <secret-dev>",
+	MsgCIExportNoCI:            "--ci-export richiede un ambiente di CI (GitHub Actions, GitLab CI o CircleCI)",
+	MsgWatchStopped:            "pull --watch fermato: %w",
+	MsgFakeNoMappings:          "pull --fake: nessun mapping dotenv con fake_keys selezionato",
+	MsgInvalidKeepEnabled:      "--keep-enabled %q non valido: serve un numero positivo di versioni",
+	MsgInvalidQuery:            "<query> non valida: %w",
+	MsgNoStateSecret:           "state: %s non ha state_secret",
+	MsgSyncConflicts:           "sync: %d conflitto/i; risolvili con pull --overwrite o push, poi rilancia sync",
+	MsgTelemetryExpected:       "atteso esattamente uno tra: on, off, status",
+	MsgTelemetryUnknown:        "azione di telemetria sconosciuta %q (previsto: on, off o status)",
+	MsgValidationProblems:      "%d problema/i di convalida",
+	MsgPushBlocked:             "push bloccato dalla policy: %d violazione/i",
+	MsgGetwd:                   "directory corrente: %w",
+	MsgCommandError:            "%s: %w",
+	MsgTargetError:             "%s %s: %w",
+	MsgExecRun:                 "exec %s: %s: %w",
+	MsgResume:                  "ripresa di %s: %w",
+
+	MsgWarnPolicy:            "policy: %s",
+	MsgWarnExpired:           "%s (%s) è scaduto; scaricalo di nuovo o eliminalo",
+	MsgWarnRetrying:          "%v; nuovo tentativo tra %s",
+	MsgWarnExpiryNotRecorded: "scadenza dei file scaricati non registrata: %v",
+	MsgWarnIgnoredField:      "campo sconosciuto %q ignorato in %s",
+	MsgWarnLegacySyncMode:    "il mapping %q usa il vecchio mode=sync; usa mode=both (sync sarà rimosso in una futura versione major)",
+	MsgWarnCoerced:           "%s convertito: il segreto è di tipo %s, mapping.type è %s",
+	MsgWarnSkippedCopy:       "%s ignorato al percorso %s: già mappato dal percorso %s",
+	MsgWarnCertExpired:       "il certificato %s è scaduto il %s",
+	MsgWarnCertExpires:       "il certificato %s scade tra %d giorni (%s)",
+	MsgWarnBudgetCalls:       "%s ha effettuato %d chiamate API (budget %d)%v",
+	MsgWarnBudgetDuration:    "%s ha impiegato %s (budget %s)%v",
+	MsgBudgetHintPull:        "; ogni mapping costa una lettura; passa i nomi dei segreti invece di --all, o disabilita i mapping che non usi",
+	MsgBudgetHintPush:        "; ogni mapping costa una scrittura; passa i nomi dei segreti invece di --all",
+	MsgBudgetHintDoctor:      "; ogni mapping viene verificato in remoto; disabilita i mapping che non usi",
+	MsgBudgetHintList:        "; restringi l'elenco con --type o --path",
+
+	MsgPolicyViolation:      "violazione della policy: %s",
+	MsgNoRecordedProgress:   "nessun avanzamento di %s registrato; eseguo tutte le destinazioni",
+	MsgResuming:             "ripresa di %s: salto %d destinazione/i completata/e",
+	MsgDryRunNothingChanged: "prova: nulla è cambiato; passa --yes per applicare",
+	MsgWatching:             "monitoraggio di %d segreto/i ogni %s; premi Ctrl-C per fermare",
+	MsgWroteHeader:          "header Authorization scritto da %s (rev=%d) -> %s",
+	MsgWroteNetrc:           "voce netrc per %s scritta da %s (rev=%d) -> %s",
+	MsgWroteDockerAuth:      "autenticazione docker per %s scritta da %s (rev=%d) -> %s",
+
+	MsgConfirmDeleteAll:     "Eliminare %s (tutte le versioni)? L'operazione è irreversibile. Digita il nome del segreto per confermare: ",
+	MsgConfirmDeleteVersion: "Eliminare %s (revisione %d)? L'operazione è irreversibile. Digita il nome del segreto per confermare: ",
+}
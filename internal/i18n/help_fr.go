@@ -0,0 +1,547 @@
+package i18n
+
+// frenchHelp translates the description, notes, and option help of every command
+// to French; like the summaries, the English text lives on the command definition.
+var frenchHelp = map[MessageID]string{
+	CommandDescriptionID("version"): "Affiche la version, le commit et la date de compilation.\n" +
+		"Avec --json, indique aussi la version de Go, la plateforme et les versions des SDK des fournisseurs.",
+	CommandFlagHelpID("version", "json"): "Sortie JSON",
+
+	CommandDescriptionID("init"): "Écrit .scw.json (ou --config) dans le répertoire courant à partir d'un modèle : un manifeste\n" +
+		"partiel avec les entrées de mapping standard, les conventions de chemin et la politique de\n" +
+		"votre organisation. Chaque {{name}} du modèle est remplacé par le nom du projet.\n" +
+		"\n" +
+		"Références de modèle :\n" +
+		"  - une URL git (https://, ssh://, git@, git+<url> ou *.git), éventuellement suivie de #<fichier>\n" +
+		"    (fichier par défaut : template.json)\n" +
+		"  - un chemin vers un fichier modèle, ou vers un répertoire contenant template.json\n" +
+		"  - un nom seul comme backend-service, lu comme <name>.json depuis $DEV_VAULT_TEMPLATES\n" +
+		"    (un répertoire ou une URL git)\n" +
+		"\n" +
+		"Avec --from-remote, le mapping est ébauché à partir des secrets -dev déjà présents dans le\n" +
+		"projet : les secrets key_value deviennent des fichiers dotenv, les autres des fichiers raw, et\n" +
+		"chaque fichier porte le nom de son secret (app-env-dev devient app.env). Relisez le résultat\n" +
+		"avant le premier pull.\n" +
+		"\n" +
+		"Sans l'un ni l'autre, init écrit un manifeste avec un mapping d'exemple, <name>-env-dev vers\n" +
+		"un fichier dotenv .env ; renommez-le et ajoutez des entrées, puis 'push --create-missing' crée\n" +
+		"les secrets. Sur un terminal, init demande l'organisation, le projet et la région que les\n" +
+		"options omettent, et propose de mapper les secrets -dev déjà présents dans le projet à la\n" +
+		"place de l'exemple.",
+	CommandNotesID("init"): "Le résultat doit être un manifeste valide : passez --organization-id, --project-id et --region\n" +
+		"quand le modèle les laisse vides ('dev-vault projects' et 'dev-vault regions' les listent).\n" +
+		"--from-remote et l'exemple exigent toujours les trois, demandés sur un terminal ; un nom\n" +
+		"trouvé sous plusieurs chemins n'est mappé qu'une fois.\n" +
+		"Les modèles git sont récupérés par un 'git clone' superficiel ; le binaire git doit être dans le PATH.",
+	CommandFlagHelpID("init", "force"):           "Écrase un fichier de configuration existant",
+	CommandFlagHelpID("init", "from-remote"):     "Mappe chaque secret -dev existant du projet au lieu d'utiliser un modèle",
+	CommandFlagHelpID("init", "name"):            "Nom du projet substitué à {{name}}, ou nom du secret d'exemple (par défaut : nom du répertoire courant)",
+	CommandFlagHelpID("init", "organization-id"): "ID de l'organisation (remplace celui du modèle)",
+	CommandFlagHelpID("init", "project-id"):      "ID du projet (remplace celui du modèle)",
+	CommandFlagHelpID("init", "region"):          "Région (remplace celle du modèle)",
+	CommandFlagHelpID("init", "template"):        "Modèle de départ",
+
+	CommandDescriptionID("config"): "Réécrit .scw.json sous forme canonique : ordre fixe des clés de premier niveau, entrées de\n" +
+		"mapping triées par nom, indentation de deux espaces et saut de ligne final.\n" +
+		"Les valeurs sont conservées telles quelles ; les valeurs par défaut ne sont pas ajoutées.",
+	CommandNotesID("config"): "--check n'écrit rien et sort avec le code 1 quand le fichier n'est pas canonique, pour la CI.\n" +
+		"Le manifeste doit être valide ; corrigez d'abord les erreurs signalées.",
+	CommandFlagHelpID("config", "check"): "Vérifie seulement le format ; sort avec 1 si le fichier changerait",
+
+	CommandDescriptionID("list"): "Liste les secrets du projet et de la région Scaleway configurés.\n" +
+		"Cette commande ne retient toujours que les noms de secrets qui se terminent par '-dev'.\n" +
+		"Elle n'affiche jamais le contenu des secrets, seulement leurs métadonnées (nom/type/chemin/id).\n" +
+		"\n" +
+		"Avec --stale, liste les entrées de mapping au lieu des secrets distants, sans jamais contacter\n" +
+		"Scaleway : une entrée est périmée quand son fichier local manque ou a été récupéré pour la\n" +
+		"dernière fois avant --older-than, ou quand son expire_after (voir pull) est dépassé. Les heures\n" +
+		"de pull sont enregistrées dans le répertoire d'état de l'utilisateur ; un fichier qui n'en a pas,\n" +
+		"comme un fichier récupéré par une version plus ancienne, est jugé sur sa date de modification.\n" +
+		"\n" +
+		"Avec --all-projects, chaque projet de organization_id est listé en parallèle avec les mêmes filtres.\n" +
+		"\n" +
+		"Avec --profile a,b, chaque profil est listé en parallèle et une colonne PROFILE est ajoutée.\n" +
+		"Le premier profil utilise le projet du manifeste ; les autres leur propre default_project_id.\n" +
+		"\n" +
+		"--columns choisit les métadonnées à afficher, pour des extractions d'inventaire ; avec\n" +
+		"--output json chaque secret devient un objet indexé par nom de colonne. age compte les jours\n" +
+		"depuis la dernière mise à jour du secret, et owner lit une étiquette owner:<équipe> ou\n" +
+		"owner=<équipe>. Le contenu des secrets n'est jamais lu.",
+	CommandFlagHelpID("list", "all-projects"):  "Liste tous les projets de l'organisation (ajoute une colonne PROJECT)",
+	CommandFlagHelpID("list", "columns"):       "Colonnes séparées par des virgules : age,id,name,owner,path,profile,project,tags,type,updated",
+	CommandFlagHelpID("list", "json"):          "Sortie JSON (comme --output json)",
+	CommandFlagHelpID("list", "name-contains"): "Filtre par sous-chaîne (répétable, toutes doivent correspondre)",
+	CommandFlagHelpID("list", "name-regex"):    "Expression régulière Go à laquelle les noms de secrets doivent correspondre",
+	CommandFlagHelpID("list", "older-than"):    "Seuil de péremption pour --stale, ex. 14d ou 36h (14d par défaut)",
+	CommandFlagHelpID("list", "output"):        "Format de sortie (table par défaut)",
+	CommandFlagHelpID("list", "path"):          "Chemin exact des secrets Scaleway à retenir",
+	CommandFlagHelpID("list", "stale"):         "Liste les entrées éligibles au pull dont le fichier local manque, a expiré ou est plus ancien que --older-than",
+	CommandFlagHelpID("list", "type"):          "Un de : basic_credentials|certificate|database_credentials|key_value|opaque|ssh_key",
+
+	CommandDescriptionID("search"): "Liste les secrets -dev du projet dont le nom contient <query>, sans tenir compte de la casse,\n" +
+		"en une seule liste couvrant tous les chemins et types. Avec --regex, ce sont les noms qui\n" +
+		"correspondent à l'expression qui sont listés ; préfixez-la de (?i) pour ignorer la casse.\n" +
+		"Sur un terminal, les parties correspondantes de chaque nom sont mises en évidence.",
+	CommandNotesID("search"): "La mise en évidence est désactivée avec --plain, quand NO_COLOR est défini, ou quand stdout n'est pas un terminal.\n" +
+		"--json affiche les enregistrements de list --json, chacun avec \"matches\": [[début, fin], ...].",
+	CommandFlagHelpID("search", "json"):  "Sortie JSON, avec les positions en octets de chaque correspondance",
+	CommandFlagHelpID("search", "regex"): "Traite <query> comme une expression régulière Go au lieu d'une sous-chaîne",
+
+	CommandDescriptionID("pull"): "Récupère un ou plusieurs secrets sur le disque d'après le mapping de .scw.json.\n" +
+		"Les secrets doivent figurer dans le mapping et leur nom doit se terminer par '-dev'.\n" +
+		"Pull lit la dernière version activée du secret (sélecteur de révision Scaleway : latest_enabled),\n" +
+		"ou la révision fixée par mapping.revision ou --revision.\n" +
+		"Pull écrit les fichiers de façon atomique et leur applique chmod 0600 (sous Unix).\n" +
+		"Les fichiers récupérés sont marqués comme gérés : les fichiers dotenv commencent par un\n" +
+		"commentaire '# managed by dev-vault', et l'attribut étendu user.dev-vault nomme le secret et la révision.\n" +
+		"N'affiche jamais le contenu des secrets.\n" +
+		"\n" +
+		"Formats :\n" +
+		"  - mapping.format=raw écrit les octets du secret tels quels.\n" +
+		"  - mapping.format=dotenv attend un objet JSON et produit un .env déterministe.\n" +
+		"  - mapping.format=yaml attend un objet JSON et le produit en YAML aux clés triées.\n" +
+		"  - mapping.format=toml attend un objet JSON et le produit en TOML aux clés triées.\n" +
+		"  - mapping.format=json indente le JSON avec les clés triées ; push le compacte.\n" +
+		"  - mapping.format=template rend mapping.template_file (text/template de Go) avec les\n" +
+		"    clés d'un objet JSON ; ces mappings ne servent qu'au pull.\n" +
+		"  - mapping.files écrit chaque clé listée d'un secret key_value, telle quelle, dans son propre fichier.\n" +
+		"\n" +
+		"Les secrets de type certificate indiquent aussi leur première expiration (expires=AAAA-MM-JJ) ;\n" +
+		"un avertissement est écrit sur stderr quand elle tombe dans --expiry-warning.",
+	CommandNotesID("pull"): "Un secret dont le type diffère de mapping.type est refusé sauf avec --coerce ; un mapping\n" +
+		"dotenv exige alors toujours un objet JSON.\n" +
+		"--ci-export détecte la plateforme de CI et, après un pull réussi, écrit les variables des\n" +
+		"mappings dotenv dans GITHUB_ENV (GitHub Actions), BASH_ENV (CircleCI) ou\n" +
+		"$CI_PROJECT_DIR/dev-vault.env pour un rapport artifacts:reports:dotenv (GitLab CI).\n" +
+		"Les valeurs ne sont jamais masquées dans les journaux des jobs ; il faudrait les afficher pour les masquer.\n" +
+		"Les cibles terminées sont enregistrées dans le répertoire d'état de l'utilisateur ; --resume\n" +
+		"les ignore après une exécution interrompue ou en échec. Une exécution entièrement réussie efface l'enregistrement.\n" +
+		"--mtime preserve conserve la date de modification d'un fichier écrasé et remote la fixe à la\n" +
+		"date de création de la version récupérée, pour que les outils de build ne recompilent que s'il\n" +
+		"a changé. Les attributs étendus utilisateur (user.*) d'un fichier écrasé sont conservés sous Linux.\n" +
+		"--revision ramène un fichier à une version plus ancienne (voir la commande versions) ; il échoue\n" +
+		"sauf si la révision existe et est activée. Il remplace mapping.revision.\n" +
+		"--fake sert aux contributeurs sans accès aux secrets : il écrit les mappings dotenv qui listent\n" +
+		"mapping.fake_keys (avec --all, seulement ceux-là) avec une valeur fictive stable par clé, filtres\n" +
+		"et affixes de clés appliqués. Il ne demande aucun identifiant, et push refuse ces fichiers.\n" +
+		"--watch récupère une fois, puis interroge le fournisseur toutes les --interval et réécrit, de façon\n" +
+		"atomique, les fichiers des secrets qui ont une nouvelle dernière révision activée, en affichant une\n" +
+		"ligne horodatée pour chacun. Les interrogations en échec sont signalées comme avertissements et\n" +
+		"retentées. Chaque interrogation est enregistrée pour prompt, qui indique si le fournisseur a\n" +
+		"répondu. Ctrl-C l'arrête avec le code de sortie 130.\n" +
+		"--dry-run fait tout sauf écrire : chaque ligne commence par 'would pull' et se termine par\n" +
+		"file=create, file=overwrite ou file=unchanged. Il échoue là où pull échouerait, par exemple sur\n" +
+		"un fichier existant sans --overwrite, et n'enregistre aucune progression --resume.\n" +
+		"--expire-after (ou expire_after dans la configuration) enregistre dans le répertoire d'état de\n" +
+		"l'utilisateur quand les fichiers récupérés expirent ; prompt, list --stale et --watch signalent\n" +
+		"ensuite ceux qui ont expiré pour que les copies en clair soient rafraîchies ou supprimées. Les\n" +
+		"fichiers ne sont jamais supprimés automatiquement. Un pull sans l'un ni l'autre efface\n" +
+		"l'expiration enregistrée des fichiers qu'il écrit.",
+	CommandFlagHelpID("pull", "all"):            "Récupère toutes les entrées de mapping en mode pull|both (mode vaut both par défaut)",
+	CommandFlagHelpID("pull", "ci-export"):      "Transmet aussi les variables dotenv aux étapes de CI suivantes (GitHub Actions, GitLab CI, CircleCI)",
+	CommandFlagHelpID("pull", "coerce"):         "Utilise un secret dont le type diffère de mapping.type quand son contenu se convertit sans risque, avec un avertissement",
+	CommandFlagHelpID("pull", "dry-run"):        "Lit et convertit les secrets et affiche ce qui serait écrit, sans rien écrire",
+	CommandFlagHelpID("pull", "expire-after"):   "Marque les fichiers récupérés comme expirés après cette durée, ex. 8h (par défaut : expire_after de la configuration)",
+	CommandFlagHelpID("pull", "expiry-warning"): "Avertit des certificats qui expirent dans ce délai, ex. 30d (30d par défaut)",
+	CommandFlagHelpID("pull", "fake"):           "Écrit des fichiers dotenv avec des valeurs fictives pour les clés de mapping.fake_keys, sans lire les secrets",
+	CommandFlagHelpID("pull", "interval"):       "Fréquence à laquelle --watch interroge le fournisseur, ex. 30s ou 5m (30s par défaut)",
+	CommandFlagHelpID("pull", "mtime"):          "Date de modification des fichiers récupérés (par défaut : mtime de la configuration, sinon maintenant)",
+	CommandFlagHelpID("pull", "overwrite"):      "Écrase les fichiers existants",
+	CommandFlagHelpID("pull", "resume"):         "Ignore les cibles terminées par la dernière exécution interrompue ou en échec pour cette configuration",
+	CommandFlagHelpID("pull", "revision"):       "Récupère cette révision du secret au lieu de la dernière activée (un seul secret)",
+	CommandFlagHelpID("pull", "watch"):          "Reste actif et récupère à nouveau chaque secret qui reçoit une nouvelle révision activée",
+
+	CommandDescriptionID("push"): "Envoie un ou plusieurs secrets du disque vers Scaleway Secret Manager sous forme de nouvelle version.\n" +
+		"Les secrets doivent figurer dans le mapping et leur nom doit se terminer par '-dev'.\n" +
+		"N'affiche jamais le contenu des secrets.\n" +
+		"\n" +
+		"Formats :\n" +
+		"  - mapping.format=raw lit les octets du fichier tels quels.\n" +
+		"  - mapping.format=dotenv lit un fichier .env et envoie un contenu JSON.\n" +
+		"  - mapping.format=yaml lit un mapping YAML et envoie un contenu JSON.\n" +
+		"  - mapping.format=toml lit un document TOML et envoie un contenu JSON.\n" +
+		"  - mapping.format=json envoie le JSON du fichier compacté.\n" +
+		"  - les mappings mapping.format=template ne servent qu'au pull et ne peuvent pas être envoyés.\n" +
+		"  - mapping.files relit chaque fichier dans sa clé ; les clés non listées sont conservées.",
+	CommandNotesID("push"): "--create-missing crée le secret s'il est absent (exige mapping.type).\n" +
+		"La création du secret utilise mapping.path ('/' par défaut).\n" +
+		"Si plusieurs secrets sont envoyés, push les liste et demande confirmation sur un terminal ;\n" +
+		"sans terminal (CI, tubes) vous devez passer --yes.\n" +
+		"Un secret dont le type diffère de mapping.type est refusé sauf avec --coerce ; le contenu doit\n" +
+		"alors convenir au type du secret (n'importe quoi pour opaque, un objet JSON pour key_value,\n" +
+		"basic_credentials et database_credentials).\n" +
+		"Les règles de politique (taille du contenu, noms de clés interdits, étiquettes requises) sont\n" +
+		"vérifiées avant la création de toute version : level=warn affiche des avertissements,\n" +
+		"level=enforce interrompt le push.\n" +
+		"Un hook policy.rego est évalué avec `opa eval` sur les seules métadonnées ; tout motif de refus interrompt le push.\n" +
+		"Les cibles terminées sont enregistrées dans le répertoire d'état de l'utilisateur ; --resume\n" +
+		"les ignore après une exécution interrompue ou en échec. Une exécution entièrement réussie efface l'enregistrement.\n" +
+		"--disable-older-than et --keep-enabled désactivent les anciennes versions de chaque secret envoyé\n" +
+		"une fois sa nouvelle version créée ; la nouvelle version reste toujours activée. Avec les deux,\n" +
+		"une version sélectionnée par l'un ou l'autre est désactivée. Les versions désactivées restent\n" +
+		"listées et peuvent être réactivées.\n" +
+		"--dry-run lit, convertit et vérifie chaque fichier, résout les secrets et affiche des lignes\n" +
+		"'would push', 'would create' et 'would disable' ; aucun secret, aucune version ni aucun état\n" +
+		"partagé n'est écrit, et aucune progression --resume n'est enregistrée.",
+	CommandFlagHelpID("push", "all"):                "Envoie toutes les entrées de mapping en mode push|both (mode vaut both par défaut)",
+	CommandFlagHelpID("push", "coerce"):             "Utilise un secret dont le type diffère de mapping.type quand son contenu se convertit sans risque, avec un avertissement",
+	CommandFlagHelpID("push", "create-missing"):     "Crée les secrets manquants (exige mapping.type)",
+	CommandFlagHelpID("push", "description"):        "Description de la nouvelle version (facultative)",
+	CommandFlagHelpID("push", "disable-older-than"): "Après l'envoi, désactive les versions activées plus anciennes que <age> (ex. 30d)",
+	CommandFlagHelpID("push", "disable-previous"):   "Désactive la version activée précédente lors de la création d'une nouvelle version",
+	CommandFlagHelpID("push", "dry-run"):            "Lit et convertit les fichiers et affiche ce qui serait envoyé, sans rien modifier",
+	CommandFlagHelpID("push", "keep-enabled"):       "Après l'envoi, désactive toutes les versions activées sauf les <n> plus récentes",
+	CommandFlagHelpID("push", "policy-file"):        "Fichier JSON de politique qui remplace la section policy de .scw.json",
+	CommandFlagHelpID("push", "resume"):             "Ignore les cibles terminées par la dernière exécution interrompue ou en échec pour cette configuration",
+	CommandFlagHelpID("push", "yes"):                "Confirme l'envoi groupé (demandé sur un terminal, requis ailleurs pour envoyer plus d'un secret)",
+
+	CommandDescriptionID("sync"): "Compare chaque entrée de mapping avec la révision à laquelle son fichier local a été récupéré\n" +
+		"ou envoyé pour la dernière fois, telle qu'enregistrée dans l'attribut étendu user.dev-vault du\n" +
+		"fichier, puis :\n" +
+		"  - récupère quand seul le secret distant a changé, ou que le fichier local manque,\n" +
+		"  - envoie quand seul le fichier local a changé,\n" +
+		"  - signale un conflit quand les deux ont changé, ou que le fichier n'a pas de révision enregistrée.\n" +
+		"Les conflits ne sont jamais résolus automatiquement : aucun des deux côtés n'est écrasé.\n" +
+		"Résolvez-les avec pull --overwrite ou push, puis relancez sync.\n" +
+		"N'affiche jamais le contenu des secrets.",
+	CommandNotesID("sync"): "Seules les entrées avec mapping.mode=both sont concernées.\n" +
+		"Les envois sont d'abord vérifiés par rapport à la politique de push. Si plus d'un secret\n" +
+		"devait être envoyé, vous devez passer --yes.\n" +
+		"La commande sort avec le code 1 quand elle signale un conflit.",
+	CommandFlagHelpID("sync", "all"):         "Synchronise toutes les entrées de mapping en mode both (mode vaut both par défaut)",
+	CommandFlagHelpID("sync", "description"): "Description des versions envoyées (facultative)",
+	CommandFlagHelpID("sync", "dry-run"):     "Affiche les décisions sans récupérer ni envoyer",
+	CommandFlagHelpID("sync", "policy-file"): "Fichier JSON de politique qui remplace la section policy de .scw.json",
+	CommandFlagHelpID("sync", "yes"):         "Confirme l'envoi de plus d'un secret",
+
+	CommandDescriptionID("ci"): "Affiche un job prêt à coller qui installe cette version de dev-vault, s'authentifie avec\n" +
+		"SCW_ACCESS_KEY et SCW_SECRET_KEY depuis le coffre à secrets de la plateforme, et exécute\n" +
+		"'pull --all --overwrite' dans l'espace de travail.\n" +
+		"Le job suit le manifeste courant : --config est passé quand ce n'est pas ./.scw.json,\n" +
+		"et --ci-export est ajouté quand un mapping récupéré utilise le format dotenv.",
+	CommandNotesID("ci"): "Lancez-la depuis la racine du dépôt ; le chemin --config du job est relatif à celle-ci.\n" +
+		"Les builds de développement installent @latest.\n" +
+		"Rien n'est écrit ; redirigez la sortie ou collez-la dans le fichier de pipeline.",
+	CommandFlagHelpID("ci", "platform"): "Plateforme de CI visée (obligatoire)",
+
+	CommandDescriptionID("generate"): "Génère de nouvelles valeurs à partir d'un modèle, crée le secret avec celles-ci comme première\n" +
+		"version, et écrit le fichier dotenv mappé. N'affiche jamais les valeurs générées.\n" +
+		"\n" +
+		"Les clés du modèle correspondent à une spécification de générateur :\n" +
+		"  - {\"generator\": \"hex\", \"bytes\": 32}   octets aléatoires, encodés en hexadécimal (32 octets par défaut)\n" +
+		"  - {\"generator\": \"uuid\"}               UUID aléatoire (version 4)\n" +
+		"  - {\"generator\": \"bcrypt\", \"cost\": 12} hachage bcrypt d'une valeur saisie à une invite masquée\n" +
+		"  - {\"value\": \"app\"}                    valeur littérale",
+	CommandNotesID("generate"): "Le secret doit être mappé avec le format dotenv et un mode qui autorise le push.\n" +
+		"generate refuse de s'exécuter quand le secret ou le fichier local existe déjà.\n" +
+		"Le type du secret vient de mapping.type, puis du \"type\" du modèle, puis key_value.\n" +
+		"bcrypt exécute 'htpasswd -nBC <cost>' pour l'invite masquée ; htpasswd doit être dans le PATH.",
+	CommandFlagHelpID("generate", "description"): "Description de la première version (facultative)",
+	CommandFlagHelpID("generate", "template"):    "Modèle de génération (obligatoire)",
+
+	CommandDescriptionID("import-env"): "Intègre en une étape un projet antérieur à Secret Manager : crée un secret key_value,\n" +
+		"envoie les variables de <file> comme première version, et ajoute une entrée de mapping\n" +
+		"dotenv pour lui dans .scw.json. N'affiche jamais le contenu des secrets.",
+	CommandNotesID("import-env"): "<file> est relatif au répertoire courant et doit se trouver dans la racine du projet.\n" +
+		"import-env refuse un nom déjà mappé ou un secret qui existe déjà.\n" +
+		"Les règles de politique sont vérifiées avant la création du secret, comme pour push.\n" +
+		".scw.json est réécrit sous forme canonique (voir 'config fmt').",
+	CommandFlagHelpID("import-env", "description"): "Description de la première version (facultative)",
+	CommandFlagHelpID("import-env", "name"):        "Nom du secret à créer (obligatoire)",
+	CommandFlagHelpID("import-env", "path"):        "Chemin du secret (/ par défaut)",
+	CommandFlagHelpID("import-env", "policy-file"): "Fichier JSON de politique qui remplace la section policy de .scw.json",
+
+	CommandDescriptionID("import-env-dir"): "Migre d'un bloc un dépôt existant : chaque fichier de <dir> correspondant à --pattern devient\n" +
+		"un secret key_value nommé {name}<suffix>, comme 'import-env' le créerait, avec une entrée\n" +
+		"de mapping dotenv dans .scw.json. N'affiche jamais le contenu des secrets.",
+	CommandNotesID("import-env-dir"): "<dir> est relatif au répertoire courant et doit se trouver dans la racine du projet.\n" +
+		"Les sous-répertoires ne sont pas parcourus. Les fichiers qui ne correspondent pas au motif sont ignorés.\n" +
+		"Chaque fichier est vérifié avant toute création : les noms de secrets doivent se terminer par -dev,\n" +
+		"ne doivent pas encore être mappés et ne doivent pas exister à distance ; les règles de\n" +
+		"politique s'appliquent comme pour push.\n" +
+		"--dry-run effectue les mêmes vérifications et affiche ce qui serait créé.",
+	CommandFlagHelpID("import-env-dir", "description"): "Description des premières versions (facultative)",
+	CommandFlagHelpID("import-env-dir", "dry-run"):     "Liste les secrets qui seraient créés sans rien modifier",
+	CommandFlagHelpID("import-env-dir", "path"):        "Chemin de chaque secret créé (/ par défaut)",
+	CommandFlagHelpID("import-env-dir", "pattern"):     "Motif de nom de fichier avec un seul emplacement {name} ({name}.env par défaut)",
+	CommandFlagHelpID("import-env-dir", "policy-file"): "Fichier JSON de politique qui remplace la section policy de .scw.json",
+	CommandFlagHelpID("import-env-dir", "suffix"):      "Ajouté à {name} pour former le nom du secret (-dev par défaut)",
+
+	CommandDescriptionID("db"): "Lit la dernière version activée d'un secret database_credentials mappé et lance psql\n" +
+		"(postgres) ou mysql (mysql, mariadb) connecté à la base.\n" +
+		"Rien n'est écrit sur le disque.",
+	CommandNotesID("db"): "Le mot de passe n'atteint le client que par PGPASSWORD ou MYSQL_PWD dans son environnement.\n" +
+		"--print affiche la chaîne de connexion et la commande sans le mot de passe.\n" +
+		"Le code de sortie du client est signalé comme un échec quand il n'est pas nul.",
+	CommandFlagHelpID("db", "print"): "Affiche la chaîne de connexion et la commande du client au lieu de l'exécuter",
+
+	CommandDescriptionID("ssh"): "Lit la dernière version activée d'un secret ssh_key mappé et transmet la clé privée à\n" +
+		"'ssh-add -' par un tube. La clé ne touche jamais le disque.",
+	CommandNotesID("ssh"): "ssh-add doit être dans le PATH et SSH_AUTH_SOCK doit désigner un agent en cours d'exécution.\n" +
+		"La phrase de passe des clés protégées est demandée par ssh-add lui-même.\n" +
+		"Quand un fichier est nécessaire, pull écrit la clé en mode 0600 avec son .pub à côté.",
+
+	CommandDescriptionID("cert"): "Lit la dernière version activée d'un secret certificate mappé et affiche le sujet,\n" +
+		"l'émetteur, les SAN, le numéro de série et la validité de chaque certificat du paquet PEM.\n" +
+		"Rien n'est écrit sur le disque.",
+	CommandNotesID("cert"):            "Une clé privée dans le paquet est signalée comme présente ; son contenu n'est jamais affiché.",
+	CommandFlagHelpID("cert", "json"): "Sortie JSON",
+
+	CommandDescriptionID("get"): "Lit la dernière version activée d'un secret basic_credentials mappé et l'écrit dans le\n" +
+		"format attendu par un autre outil. La sortie va toujours dans --output en mode 0600 ;\n" +
+		"les identifiants ne sont jamais affichés.\n" +
+		"\n" +
+		"Formats :\n" +
+		"  - --as-netrc remplace l'entrée de <host> dans un .netrc existant, ou en ajoute une.\n" +
+		"  - --as-docker-config définit auths[<registry>] et conserve le reste de config.json.\n" +
+		"  - --as-header écrit 'Authorization: Basic ...' pour curl -H @<path>.",
+	CommandNotesID("get"):                        "Les entrées netrc ne peuvent contenir ni espaces ni guillemets ; de tels identifiants sont refusés.",
+	CommandFlagHelpID("get", "as-docker-config"): "Définit auths[<registry>] dans un config.json de docker",
+	CommandFlagHelpID("get", "as-header"):        "Écrit un fichier d'en-tête Authorization pour curl -H @<file>",
+	CommandFlagHelpID("get", "as-netrc"):         "Définit l'entrée machine <host> dans un fichier .netrc",
+	CommandFlagHelpID("get", "output"):           "Fichier à écrire (obligatoire)",
+	CommandFlagHelpID("get", "overwrite"):        "Remplace un fichier --as-header existant",
+
+	CommandDescriptionID("fixtures"): "Lit des secrets dotenv (chaque mapping dotenv éligible au pull quand aucun n'est nommé) et\n" +
+		"écrit leurs clés dans --out sous forme d'objet JSON, pour que les tests applicatifs vérifient\n" +
+		"le chargement de la configuration sans vrais identifiants. Les clés sont celles que pull écrit,\n" +
+		"filtres et affixes appliqués.\n" +
+		"\n" +
+		"Les valeurs sont remplacées, jamais copiées : true/false devient false, un entier un nombre\n" +
+		"dérivé de la clé, une URL garde son schéma et pointe vers fixture.invalid, et tout le reste\n" +
+		"devient fixture-<key>. Avec --mask chaque valeur vaut ********. Les valeurs vides restent vides.\n" +
+		"La sortie ne dépend que des clés et de la nature des valeurs ; elle est donc stable d'une\n" +
+		"exécution et d'une rotation de secret à l'autre.",
+	CommandNotesID("fixtures"):                 "Seuls les mappings dotenv en mode pull|both peuvent être lus.",
+	CommandFlagHelpID("fixtures", "mask"):      "Écrit ******** pour chaque valeur au lieu de valeurs fictives typées",
+	CommandFlagHelpID("fixtures", "out"):       "Fichier JSON à écrire (obligatoire)",
+	CommandFlagHelpID("fixtures", "overwrite"): "Remplace un fichier --out existant",
+
+	CommandDescriptionID("versions"): "Liste chaque version d'un secret mappé, de la plus récente à la plus ancienne, avec sa\n" +
+		"révision, son statut (enabled, disabled, ou destroyed quand le fournisseur le connaît), sa date\n" +
+		"de création et sa description. Le contenu n'est jamais lu.",
+	CommandNotesID("versions"): "Les fournisseurs sans description de version (aws, vault) laissent DESCRIPTION vide.\n" +
+		"Les versions aws écrites par d'autres outils apparaissent avec la révision 0.",
+	CommandFlagHelpID("versions", "json"): "Sortie JSON",
+
+	CommandDescriptionID("rollback"): "Crée une nouvelle version d'un secret mappé à partir du contenu d'une révision plus ancienne,\n" +
+		"pour annuler un mauvais push. Les versions précédentes sont conservées ; voir la commande\n" +
+		"versions pour les révisions.\n" +
+		"La révision doit exister et être activée. Le fichier local n'est pas modifié ; récupérez-le\n" +
+		"ensuite avec --overwrite.\n" +
+		"N'affiche jamais le contenu des secrets.",
+	CommandNotesID("rollback"): "Demande confirmation sur stdin sauf avec --yes ; sans réponse rien n'est écrit.\n" +
+		"Seuls les mappings en mode push|both peuvent être restaurés.\n" +
+		"--disable-previous désactive la mauvaise version, qui n'est alors plus lue par défaut.",
+	CommandFlagHelpID("rollback", "description"):      "Description de la nouvelle version (facultative)",
+	CommandFlagHelpID("rollback", "disable-previous"): "Désactive la version activée précédente lors de la création de la nouvelle version",
+	CommandFlagHelpID("rollback", "to-revision"):      "Révision dont le contenu devient la nouvelle version (obligatoire)",
+	CommandFlagHelpID("rollback", "yes"):              "Ignore la demande de confirmation",
+
+	CommandDescriptionID("delete"): "Supprime du fournisseur un secret mappé et toutes ses versions. Le fichier local et\n" +
+		"l'entrée de mapping ne sont pas touchés.\n" +
+		"Exige --yes et le nom du secret retapé sur stdin ; toute autre saisie ne supprime rien.",
+	CommandNotesID("delete"): "Seuls les mappings en mode push|both peuvent être supprimés.\n" +
+		"Scaleway et Vault suppriment immédiatement ; aws programme la suppression après une période de récupération de 30 jours.",
+	CommandFlagHelpID("delete", "yes"): "Confirme la suppression (obligatoire ; le nom du secret est quand même demandé sur stdin)",
+
+	CommandDescriptionID("delete-version"): "Supprime une version d'un secret mappé ; les autres versions sont conservées. Voir la\n" +
+		"commande versions pour les révisions. Les révisions désactivées peuvent aussi être supprimées.\n" +
+		"Exige --yes et le nom du secret retapé sur stdin ; toute autre saisie ne supprime rien.",
+	CommandNotesID("delete-version"): "Seuls les mappings en mode push|both peuvent être modifiés.\n" +
+		"Vault détruit les données de la version ; aws ne peut pas supprimer une seule version.",
+	CommandFlagHelpID("delete-version", "revision"): "Révision à supprimer (obligatoire)",
+	CommandFlagHelpID("delete-version", "yes"):      "Confirme la suppression (obligatoire ; le nom du secret est quand même demandé sur stdin)",
+
+	CommandDescriptionID("gc"): "Conserve les <n> versions activées les plus récentes de chaque secret mappé et désactive les\n" +
+		"plus anciennes versions activées. Avec --delete, toutes les autres versions sont supprimées à\n" +
+		"la place, désactivées comprises. Une révision fixée par mapping.revision est toujours conservée.\n" +
+		"Sans --yes rien n'est modifié : un tableau liste, par secret, ce qui serait retiré.\n" +
+		"Le contenu n'est jamais lu.",
+	CommandNotesID("gc"): "Seuls les mappings en mode push|both sont traités.\n" +
+		"aws ne peut pas supprimer une version : avec --delete, ses anciennes versions activées sont\n" +
+		"désactivées et les versions désactivées sont laissées à leur expiration. Vault détruit les\n" +
+		"données des versions supprimées.",
+	CommandFlagHelpID("gc", "all"):    "Traite toutes les entrées de mapping en mode push|both (mode vaut both par défaut)",
+	CommandFlagHelpID("gc", "delete"): "Supprime les anciennes versions, désactivées comprises, au lieu de les désactiver",
+	CommandFlagHelpID("gc", "keep"):   "Nombre de versions activées les plus récentes à conserver (obligatoire)",
+	CommandFlagHelpID("gc", "yes"):    "Applique le plan (sans cette option, seul le plan est affiché)",
+
+	CommandDescriptionID("exec"): "Lit la dernière version activée d'un secret key_value mappé et exécute <command> avec ses\n" +
+		"clés ajoutées à l'environnement. Rien n'est écrit sur le disque.\n" +
+		"Pour un mapping dotenv, les variables sont celles que pull écrirait, avec compose, keys,\n" +
+		"substitute et key_prefix/key_suffix appliqués.",
+	CommandNotesID("exec"): "Tout ce qui suit <secret-dev> est passé tel quel à <command> ; le -- qui le précède est facultatif.\n" +
+		"Les clés du secret remplacent les variables de même nom déjà présentes dans l'environnement.\n" +
+		"dev-vault sort avec le code de sortie de la commande, et ignore SIGINT/SIGTERM pendant son\n" +
+		"exécution pour que la commande décide comment s'arrêter.\n" +
+		"Les valeurs n'apparaissent jamais dans la sortie de dev-vault.",
+
+	CommandDescriptionID("prompt"): "Affiche une ligne d'état compacte pour les frameworks d'invite (starship, oh-my-zsh).\n" +
+		"Seuls le manifeste, les fichiers locaux et le répertoire d'état de l'utilisateur sont lus : le\n" +
+		"fournisseur n'est jamais contacté, et la commande abandonne après 50ms, pour pouvoir\n" +
+		"s'exécuter à chaque affichage de l'invite.",
+	CommandNotesID("prompt"): "drift.missing compte les fichiers mappés qui n'existent pas localement.\n" +
+		"drift.expired compte les fichiers mappés dont l'expire_after (voir pull) est dépassé.\n" +
+		"drift.changed compte les fichiers mappés qui ne correspondaient pas à leur secret distant, en\n" +
+		"contenu ou en révision, lors du dernier status ou pull --watch, et n'ont pas été récupérés depuis.\n" +
+		"last_sync est le dernier pull enregistré d'un fichier mappé, ou sa date de modification quand\n" +
+		"aucun pull n'a été enregistré (null s'il n'en existe aucun).\n" +
+		"provider vaut 'reachable' ou 'unreachable' d'après le dernier status ou pull --watch, dont\n" +
+		"l'heure est checked_at, ou 'unknown' (checked_at null) avant le premier.\n" +
+		"Au-delà du budget de 50ms, rien n'est affiché et le code de sortie est 1.",
+	CommandFlagHelpID("prompt", "json"): "Sortie JSON",
+
+	CommandDescriptionID("projects"): "Liste les projets (nom et ID) visibles avec les identifiants Scaleway courants.\n" +
+		"Ne nécessite pas de .scw.json ; elle peut donc servir à renseigner organization_id/project_id.",
+	CommandFlagHelpID("projects", "json"):            "Sortie JSON",
+	CommandFlagHelpID("projects", "organization-id"): "Organisation à lister (par défaut : depuis le profil ou l'environnement Scaleway)",
+
+	CommandDescriptionID("regions"):      "Liste les valeurs valides du champ region de .scw.json.",
+	CommandFlagHelpID("regions", "json"): "Sortie JSON",
+
+	CommandDescriptionID("report"): "Écrit sur stdout un inventaire partageable des secrets de développement du projet :\n" +
+		"couverture du mapping, écarts local/distant, ancienneté de la dernière mise à jour de chaque\n" +
+		"secret, secrets de développement distants qu'aucune entrée de mapping ne couvre, et\n" +
+		"violations de politique (max_secret_age et required_tags, évalués sur les métadonnées distantes).",
+	CommandNotesID("report"): "Le contenu des secrets n'est lu que pour le comparer aux fichiers locaux et n'est jamais affiché.\n" +
+		"La sortie HTML est une page unique autonome (CSS intégré, aucune ressource externe).\n" +
+		"La dernière mise à jour est l'heure de mise à jour du secret Scaleway, qui change à chaque nouvelle version.\n" +
+		"Le propriétaire provient d'un tag owner:<équipe> ou owner=<équipe> du secret.",
+	CommandFlagHelpID("report", "format"):      "Format du rapport (markdown par défaut)",
+	CommandFlagHelpID("report", "policy-file"): "Fichier JSON de politique qui remplace la section policy de .scw.json",
+
+	CommandDescriptionID("lint-names"): "Vérifie chaque clé de mapping et chaque secret -dev distant du projet par rapport à la\n" +
+		"section naming de .scw.json et liste chaque écart.\n" +
+		"\n" +
+		"Règles de nommage :\n" +
+		"  - kebab_case : lettres minuscules et chiffres en mots séparés par un seul tiret.\n" +
+		"  - required_suffix : un suffixe par lequel chaque nom doit se terminer, comme -env-dev.\n" +
+		"  - patterns : des expressions régulières ; un nom doit correspondre à au moins l'une d'elles.",
+	CommandNotesID("lint-names"): "Sans section naming, seul le kebab-case des noms est vérifié.\n" +
+		"Sort avec le code 1 quand un écart est trouvé, pour pouvoir bloquer la CI.",
+	CommandFlagHelpID("lint-names", "json"): "Sortie JSON",
+
+	CommandDescriptionID("doctor"): "Consulte chaque entrée de mapping activée (métadonnées seulement, sans contenu) et signale\n" +
+		"les associations de type de secret et de format de mapping qui feraient échouer pull ou push,\n" +
+		"avec l'association qui convient :\n" +
+		"  - certificate ou ssh_key mappé en dotenv : le texte PEM n'est pas un objet JSON ; utilisez raw.\n" +
+		"  - key_value mappé en raw : le fichier reçoit le contenu JSON ; utilisez dotenv.\n" +
+		"  - mapping.type différent du type du secret : les recherches par type ne trouvent pas le secret.\n" +
+		"  - aucun secret au nom et au chemin mappés.\n" +
+		"  - un fichier dotenv local qui ne s'analyse pas, avec la ligne en cause.\n" +
+		"  - un secret distant plus ancien que le max_secret_age de la politique ou sans ses required_tags.",
+	CommandNotesID("doctor"): "Sort avec le code 1 quand quelque chose est signalé, pour pouvoir bloquer la CI.\n" +
+		"--output vscode affiche une ligne 'file:line:column: error: message' par problème, pour un\n" +
+		"problem matcher de tâche VS Code. Les problèmes du secret distant pointent vers l'entrée\n" +
+		"de mapping dans .scw.json.",
+	CommandFlagHelpID("doctor", "json"):        "Sortie JSON (comme --output json)",
+	CommandFlagHelpID("doctor", "output"):      "Format de sortie (table par défaut)",
+	CommandFlagHelpID("doctor", "policy-file"): "Fichier JSON de politique qui remplace la section policy de .scw.json",
+
+	CommandDescriptionID("validate"): "Charge et valide le manifeste, puis vérifie que chaque file, entrée files et template_file\n" +
+		"du mapping se résout dans la racine du projet. Rien n'est lu auprès du fournisseur sauf avec\n" +
+		"--remote, qui exécute les vérifications de doctor : chaque secret mappé existe, et son type\n" +
+		"convient à mapping.type et mapping.format.",
+	CommandNotesID("validate"): "Codes de sortie : 0 aucun constat, 3 le manifeste ne se charge pas, 4 un chemin mappé sort de\n" +
+		"la racine du projet, 5 une vérification distante a échoué ; 1 et 2 gardent leur sens habituel.",
+	CommandFlagHelpID("validate", "json"):   "Affiche les constats en JSON",
+	CommandFlagHelpID("validate", "remote"): "Vérifie aussi que chaque secret mappé existe et correspond au type déclaré",
+
+	CommandDescriptionID("access"): "Affiche le responsable, le contact et la page de demande d'accès indiqués par access_contact\n" +
+		"dans .scw.json, pour qu'un nouveau contributeur dont le pull est refusé sache à qui s'adresser.\n" +
+		"Aucun identifiant n'est nécessaire.\n" +
+		"Avec --open, la page de demande s'ouvre dans $BROWSER ou le navigateur par défaut de la plateforme.",
+	CommandNotesID("access"):            "Les commandes refusées par le fournisseur affichent le même contact en guise d'indication.",
+	CommandFlagHelpID("access", "open"): "Ouvre access_contact.request_url dans le navigateur",
+
+	CommandDescriptionID("status"): "Rend compte de chaque entrée de mapping activée : si le secret distant existe et sa dernière\n" +
+		"révision activée, si le fichier local existe et quand il a été écrit pour la dernière fois, et\n" +
+		"si le fichier correspond à ce que pull écrirait maintenant (comparaison par SHA-256).\n" +
+		"MANAGED indique si le fichier porte le marqueur que pull écrit pour le secret (l'attribut étendu\n" +
+		"user.dev-vault, ou le commentaire de première ligne des fichiers dotenv), qui distingue les\n" +
+		"fichiers écrits par dev-vault de ceux faits à la main.\n" +
+		"Le contenu et les empreintes ne sont jamais affichés.",
+	CommandNotesID("status"): "MATCH vaut '-' quand l'un des deux côtés manque ou que le secret ne peut pas être rendu pour\n" +
+		"le mapping ; la raison de ce dernier cas est affichée sur stderr.\n" +
+		"Avec une politique, les secrets distants plus anciens que max_secret_age ou sans les\n" +
+		"required_tags sont signalés comme avertissements, quel que soit le niveau de la politique.\n" +
+		"Le résultat est enregistré dans le répertoire d'état de l'utilisateur pour prompt : si le\n" +
+		"fournisseur a répondu, et quels fichiers ne correspondent pas.",
+	CommandFlagHelpID("status", "json"):        "Sortie JSON",
+	CommandFlagHelpID("status", "policy-file"): "Fichier JSON de politique qui remplace la section policy de .scw.json",
+
+	CommandDescriptionID("state"): "Lit le state_secret indiqué dans .scw.json, où push, sync et rollback enregistrent la nouvelle\n" +
+		"révision de chaque secret, quand elle a été envoyée et depuis quel hôte. Chaque entrée de\n" +
+		"mapping activée est listée avec cet enregistrement et la révision du marqueur de son fichier local.\n" +
+		"Seul le secret d'état est lu ; le contenu des secrets mappés n'est jamais récupéré ni affiché.",
+	CommandNotesID("state"): "DRIFT vaut current, behind (un coéquipier a envoyé une révision plus récente ; récupérez-la),\n" +
+		"ahead (l'enregistrement est plus ancien que le fichier), unknown (le fichier n'a pas de\n" +
+		"marqueur de révision), missing (pas de fichier local) ou unrecorded (aucun push enregistré).\n" +
+		"Chaque écriture fusionne dans la dernière version de l'état, pour que des coéquipiers qui\n" +
+		"envoient en même temps conservent les enregistrements des autres.",
+	CommandFlagHelpID("state", "json"): "Sortie JSON",
+
+	CommandDescriptionID("telemetry"): "La télémétrie est désactivée tant que vous n'exécutez pas 'dev-vault telemetry on'.\n" +
+		"Une fois activée, chaque commande n'enregistre que son nom, sa durée et son type d'erreur\n" +
+		"(none, usage ou failure) : ni arguments, ni chemins, ni ID de projet, ni noms de secrets.",
+	CommandNotesID("telemetry"): "Les événements sont ajoutés à un fichier local dans votre répertoire de configuration utilisateur ; rien n'est envoyé sur le réseau.\n" +
+		"DO_NOT_TRACK=1 empêche l'enregistrement même quand la télémétrie est activée.",
+
+	CommandDescriptionID("disable-mapping"): "Définit disabled: true sur l'entrée de mapping dans .scw.json sans la supprimer.\n" +
+		"Les entrées désactivées sont ignorées par pull --all, push --all, list --stale, prompt et report.\n" +
+		"Nommer explicitement une entrée désactivée (par exemple 'pull <secret-dev>') fonctionne toujours.",
+	CommandNotesID("disable-mapping"): "Le manifeste est réécrit avec une indentation de deux espaces et des clés de mapping triées.",
+
+	CommandDescriptionID("enable-mapping"): "Retire disabled: true de l'entrée de mapping dans .scw.json.",
+	CommandNotesID("enable-mapping"):       "Le manifeste est réécrit avec une indentation de deux espaces et des clés de mapping triées.",
+
+	CommandDescriptionID("agent"): "Sert une API JSON sur une adresse de bouclage ou un socket unix jusqu'à Ctrl-C, pour les\n" +
+		"extensions d'IDE. Au démarrage, elle affiche une ligne JSON avec l'url de base (ou le chemin\n" +
+		"du socket) et un jeton bearer créé pour cette session ; chaque requête doit envoyer\n" +
+		"'Authorization: Bearer <token>'.\n" +
+		"\n" +
+		"Points d'accès :\n" +
+		"  GET  /v1/mappings  les entrées de mapping : name, file, files, format, mode, type, disabled.\n" +
+		"  GET  /v1/status    les enregistrements de status --json.\n" +
+		"  POST /v1/pull      récupère {\"names\": [...]} ou {\"all\": true}, avec \"overwrite\": true\n" +
+		"                     pour remplacer les fichiers existants, et renvoie ce qui a été écrit.\n" +
+		"Les erreurs sont {\"error\": \"...\"} avec le statut 400 pour une requête invalide et 500 sinon.",
+	CommandNotesID("agent"): "Seules les adresses de bouclage sont acceptées. Les requêtes avec un en-tête Origin, que les\n" +
+		"navigateurs envoient, sont refusées, pour que les pages web ne puissent pas atteindre l'API.\n" +
+		"Le socket n'est lisible et inscriptible que par l'utilisateur, et il est supprimé à la sortie.\n" +
+		"Les requêtes sont servies une à une, et chacune interroge de nouveau le fournisseur. Le contenu\n" +
+		"n'est jamais renvoyé : pull écrit les fichiers comme le fait la commande pull.\n" +
+		"L'agent sort avec le code 130 quand il est arrêté.",
+	CommandFlagHelpID("agent", "listen"): "Adresse de bouclage d'écoute (127.0.0.1:0 par défaut, un port libre)",
+	CommandFlagHelpID("agent", "socket"): "Écoute plutôt sur un socket unix à ce chemin",
+
+	CommandDescriptionID("where"): "Chaque commande qui charge une configuration enregistre son chemin dans le répertoire d'état\n" +
+		"de l'utilisateur ; where liste ces copies de travail avec l'état local de leurs fichiers mappés,\n" +
+		"comme prompt le fait pour une seule.\n" +
+		"Seuls les manifestes et les fichiers locaux sont lus : le fournisseur n'est jamais contacté.",
+	CommandNotesID("where"): "status vaut ok, drift (fichiers manquants ou expirés, voir pull --expire-after), gone (le\n" +
+		"fichier de configuration n'existe plus) ou invalid (la configuration ne se charge pas).\n" +
+		"Les copies de travail sont enregistrées au plus une fois par heure ; last_used est donc précis à l'heure près.\n" +
+		"--prune oublie les copies de travail disparues, par exemple des clones supprimés.",
+	CommandFlagHelpID("where", "json"):  "Sortie JSON",
+	CommandFlagHelpID("where", "prune"): "Oublie les copies de travail dont le fichier de configuration n'existe plus",
+
+	CommandDescriptionID("completion"): "Affiche le script de complétion des commandes et options pour le shell, $SHELL par défaut.\n" +
+		"--install ajoute une ligne qui le charge à ~/.bashrc, ~/.zshrc ($ZDOTDIR) ou\n" +
+		"~/.config/fish/config.fish ($XDG_CONFIG_HOME), entre des commentaires marqueurs\n" +
+		"'# >>> dev-vault completion >>>'. Le relancer ne change rien ; --uninstall retire le bloc\n" +
+		"marqué et laisse le reste du fichier intact.\n" +
+		"\n" +
+		"Sous bash et zsh, les arguments de secret se complètent avec les noms du mapping du manifeste.\n" +
+		"Avec DEV_VAULT_COMPLETE_REMOTE=1 ils se complètent aussi avec les noms -dev distants du projet,\n" +
+		"mis en cache dans le répertoire d'état pendant 10 minutes et rafraîchis par list ; un\n" +
+		"rafraîchissement ne bloque jamais le shell plus de 100ms, et hors ligne les noms en cache sont\n" +
+		"utilisés. --names affiche ce qui serait proposé.",
+	CommandNotesID("completion"): "Un fichier de démarrage qui est un lien symbolique est modifié à travers le lien ; un fichier\n" +
+		"avec d'autres liens physiques est refusé. La ligne installée ne fait rien une fois dev-vault\n" +
+		"retiré du PATH.",
+	CommandFlagHelpID("completion", "install"):   "Ajoute la ligne qui charge la complétion au fichier de démarrage du shell",
+	CommandFlagHelpID("completion", "names"):     "Affiche les noms de secrets qui complètent un argument de secret, un par ligne",
+	CommandFlagHelpID("completion", "uninstall"): "Retire la ligne ajoutée par --install",
+}
@@ -0,0 +1,544 @@
+package i18n
+
+// italianHelp translates the description, notes, and option help of every command
+// to Italian; like the summaries, the English text lives on the command definition.
+var italianHelp = map[MessageID]string{
+	CommandDescriptionID("version"): "Stampa versione, commit e data di compilazione.\n" +
+		"Con --json, riporta anche la versione di Go, la piattaforma e le versioni degli SDK dei provider.",
+	CommandFlagHelpID("version", "json"): "Output JSON",
+
+	CommandDescriptionID("init"): "Scrive .scw.json (o --config) nella directory corrente a partire da un modello: un manifesto\n" +
+		"parziale con le voci di mapping standard, le convenzioni sui percorsi e la policy della tua\n" +
+		"organizzazione. Ogni {{name}} del modello viene sostituito con il nome del progetto.\n" +
+		"\n" +
+		"Riferimenti al modello:\n" +
+		"  - un URL git (https://, ssh://, git@, git+<url> o *.git), eventualmente seguito da #<file>\n" +
+		"    (file predefinito: template.json)\n" +
+		"  - un percorso a un file modello, o a una directory che contiene template.json\n" +
+		"  - un nome semplice come backend-service, letto come <name>.json da $DEV_VAULT_TEMPLATES\n" +
+		"    (una directory o un URL git)\n" +
+		"\n" +
+		"Con --from-remote, il mapping viene abbozzato dai segreti -dev già presenti nel progetto:\n" +
+		"i segreti key_value diventano file dotenv, gli altri file raw, e ogni file prende il nome del\n" +
+		"suo segreto (app-env-dev diventa app.env). Rivedi il risultato prima del primo pull.\n" +
+		"\n" +
+		"Senza nessuno dei due, init scrive un manifesto con un mapping di esempio, <name>-env-dev\n" +
+		"verso un file dotenv .env; rinominalo e aggiungi voci, poi 'push --create-missing' crea i\n" +
+		"segreti. Su un terminale, init chiede l'organizzazione, il progetto e la regione che le opzioni\n" +
+		"non indicano, e propone di mappare i segreti -dev già presenti nel progetto al posto\n" +
+		"dell'esempio.",
+	CommandNotesID("init"): "Il risultato deve essere un manifesto valido: passa --organization-id, --project-id e --region\n" +
+		"quando il modello li lascia vuoti ('dev-vault projects' e 'dev-vault regions' li elencano).\n" +
+		"--from-remote e l'esempio richiedono sempre tutti e tre, chiesti su un terminale; un nome\n" +
+		"trovato sotto più percorsi viene mappato una sola volta.\n" +
+		"I modelli git sono scaricati con un 'git clone' superficiale; il binario git deve essere nel PATH.",
+	CommandFlagHelpID("init", "force"):           "Sovrascrive un file di configurazione esistente",
+	CommandFlagHelpID("init", "from-remote"):     "Mappa ogni segreto -dev esistente del progetto invece di usare un modello",
+	CommandFlagHelpID("init", "name"):            "Nome del progetto sostituito a {{name}}, o nome del segreto di esempio (predefinito: nome della directory corrente)",
+	CommandFlagHelpID("init", "organization-id"): "ID dell'organizzazione (sostituisce quello del modello)",
+	CommandFlagHelpID("init", "project-id"):      "ID del progetto (sostituisce quello del modello)",
+	CommandFlagHelpID("init", "region"):          "Regione (sostituisce quella del modello)",
+	CommandFlagHelpID("init", "template"):        "Modello di partenza",
+
+	CommandDescriptionID("config"): "Riscrive .scw.json in forma canonica: ordine fisso delle chiavi di primo livello, voci di\n" +
+		"mapping ordinate per nome, indentazione di due spazi e a capo finale.\n" +
+		"I valori restano come sono scritti; i valori predefiniti non vengono aggiunti.",
+	CommandNotesID("config"): "--check non scrive nulla ed esce con codice 1 quando il file non è canonico, per la CI.\n" +
+		"Il manifesto deve essere valido; correggi prima gli errori segnalati.",
+	CommandFlagHelpID("config", "check"): "Controlla solo la formattazione; esce con 1 se il file cambierebbe",
+
+	CommandDescriptionID("list"): "Elenca i segreti del progetto e della regione Scaleway configurati.\n" +
+		"Questo comando considera sempre solo i nomi di segreti che terminano con '-dev'.\n" +
+		"Non stampa mai il contenuto dei segreti, solo i metadati (nome/tipo/percorso/id).\n" +
+		"\n" +
+		"Con --stale, elenca le voci di mapping invece dei segreti remoti, senza mai contattare\n" +
+		"Scaleway: una voce è obsoleta quando il suo file locale manca o è stato scaricato l'ultima\n" +
+		"volta prima di --older-than, o quando il suo expire_after (vedi pull) è passato. Gli orari dei\n" +
+		"pull sono registrati nella directory di stato dell'utente; un file che non ne ha, come uno\n" +
+		"scaricato da una versione precedente, è giudicato dalla sua data di modifica.\n" +
+		"\n" +
+		"Con --all-projects, ogni progetto di organization_id viene elencato in parallelo con gli stessi filtri.\n" +
+		"\n" +
+		"Con --profile a,b, ogni profilo viene elencato in parallelo e si aggiunge una colonna PROFILE.\n" +
+		"Il primo profilo usa il progetto del manifesto; gli altri il proprio default_project_id.\n" +
+		"\n" +
+		"--columns sceglie i metadati da mostrare, per estrazioni di inventario; con --output json ogni\n" +
+		"segreto diventa un oggetto con chiavi pari ai nomi delle colonne. age conta i giorni dall'ultimo\n" +
+		"aggiornamento del segreto, e owner legge un'etichetta owner:<team> o owner=<team>. Il\n" +
+		"contenuto dei segreti non viene mai letto.",
+	CommandFlagHelpID("list", "all-projects"):  "Elenca tutti i progetti dell'organizzazione (aggiunge una colonna PROJECT)",
+	CommandFlagHelpID("list", "columns"):       "Colonne separate da virgole: age,id,name,owner,path,profile,project,tags,type,updated",
+	CommandFlagHelpID("list", "json"):          "Output JSON (come --output json)",
+	CommandFlagHelpID("list", "name-contains"): "Filtro per sottostringa (ripetibile, devono corrispondere tutte)",
+	CommandFlagHelpID("list", "name-regex"):    "Espressione regolare Go a cui devono corrispondere i nomi dei segreti",
+	CommandFlagHelpID("list", "older-than"):    "Soglia di obsolescenza per --stale, es. 14d o 36h (predefinita 14d)",
+	CommandFlagHelpID("list", "output"):        "Formato di output (predefinito table)",
+	CommandFlagHelpID("list", "path"):          "Percorso esatto dei segreti Scaleway da considerare",
+	CommandFlagHelpID("list", "stale"):         "Elenca le voci idonee al pull il cui file locale manca, è scaduto o è più vecchio di --older-than",
+	CommandFlagHelpID("list", "type"):          "Uno tra: basic_credentials|certificate|database_credentials|key_value|opaque|ssh_key",
+
+	CommandDescriptionID("search"): "Elenca i segreti -dev del progetto il cui nome contiene <query>, senza distinguere maiuscole\n" +
+		"e minuscole, in un unico elenco su tutti i percorsi e tipi. Con --regex, vengono elencati\n" +
+		"invece i nomi che corrispondono all'espressione; anteponi (?i) per ignorare le maiuscole.\n" +
+		"Su un terminale, le parti corrispondenti di ogni nome vengono evidenziate.",
+	CommandNotesID("search"): "L'evidenziazione è disattivata con --plain, quando NO_COLOR è impostata, o quando stdout non è un terminale.\n" +
+		"--json stampa i record di list --json, ciascuno con \"matches\": [[inizio, fine], ...].",
+	CommandFlagHelpID("search", "json"):  "Output JSON, con le posizioni in byte di ogni corrispondenza",
+	CommandFlagHelpID("search", "regex"): "Tratta <query> come un'espressione regolare Go invece che come sottostringa",
+
+	CommandDescriptionID("pull"): "Scarica uno o più segreti su disco in base al mapping di .scw.json.\n" +
+		"I segreti devono essere nel mapping e i nomi devono terminare con '-dev'.\n" +
+		"Pull legge l'ultima versione abilitata del segreto (selettore di revisione Scaleway: latest_enabled),\n" +
+		"o la revisione fissata da mapping.revision o --revision.\n" +
+		"Pull scrive i file in modo atomico e imposta chmod 0600 (su Unix).\n" +
+		"I file scaricati sono marcati come gestiti: i file dotenv iniziano con un commento\n" +
+		"'# managed by dev-vault', e l'attributo esteso user.dev-vault indica il segreto e la revisione.\n" +
+		"Non stampa mai il contenuto dei segreti.\n" +
+		"\n" +
+		"Formati:\n" +
+		"  - mapping.format=raw scrive i byte del segreto così come sono.\n" +
+		"  - mapping.format=dotenv si aspetta un oggetto JSON e produce un .env deterministico.\n" +
+		"  - mapping.format=yaml si aspetta un oggetto JSON e lo produce come YAML con chiavi ordinate.\n" +
+		"  - mapping.format=toml si aspetta un oggetto JSON e lo produce come TOML con chiavi ordinate.\n" +
+		"  - mapping.format=json indenta il JSON con le chiavi ordinate; push lo compatta.\n" +
+		"  - mapping.format=template esegue mapping.template_file (text/template di Go) con le\n" +
+		"    chiavi di un oggetto JSON; questi mapping sono solo per il pull.\n" +
+		"  - mapping.files scrive ogni chiave elencata di un segreto key_value, così com'è, nel proprio file.\n" +
+		"\n" +
+		"I segreti di tipo certificate riportano anche la prima scadenza (expires=AAAA-MM-GG); un\n" +
+		"avviso va su stderr quando cade entro --expiry-warning.",
+	CommandNotesID("pull"): "Un segreto il cui tipo differisce da mapping.type viene rifiutato a meno di --coerce; un\n" +
+		"mapping dotenv richiede comunque un oggetto JSON.\n" +
+		"--ci-export rileva la piattaforma di CI e, dopo un pull riuscito, scrive le variabili dei\n" +
+		"mapping dotenv in GITHUB_ENV (GitHub Actions), BASH_ENV (CircleCI) o\n" +
+		"$CI_PROJECT_DIR/dev-vault.env per un report artifacts:reports:dotenv (GitLab CI).\n" +
+		"I valori non vengono mai mascherati nei log dei job; per mascherarli bisognerebbe stamparli.\n" +
+		"Le destinazioni completate sono registrate nella directory di stato dell'utente; --resume le\n" +
+		"salta dopo un'esecuzione interrotta o fallita. Un'esecuzione del tutto riuscita cancella il registro.\n" +
+		"--mtime preserve mantiene la data di modifica di un file sovrascritto e remote la imposta alla\n" +
+		"data di creazione della versione scaricata, così gli strumenti di build ricompilano solo se è\n" +
+		"cambiato. Gli attributi estesi utente (user.*) di un file sovrascritto sono mantenuti su Linux.\n" +
+		"--revision riporta un file a una versione precedente (vedi il comando versions); fallisce a meno\n" +
+		"che la revisione esista e sia abilitata. Sostituisce mapping.revision.\n" +
+		"--fake è per chi contribuisce senza accesso ai segreti: scrive i mapping dotenv che elencano\n" +
+		"mapping.fake_keys (con --all, solo quelli) con un valore fittizio stabile per chiave, applicando\n" +
+		"filtri e affissi delle chiavi. Non richiede credenziali, e push rifiuta quei file.\n" +
+		"--watch scarica una volta, poi interroga il provider ogni --interval e riscrive, in modo\n" +
+		"atomico, i file dei segreti con una nuova ultima revisione abilitata, stampando una riga con\n" +
+		"data e ora per ciascuno. Le interrogazioni fallite sono segnalate come avvisi e ritentate. Ogni\n" +
+		"interrogazione viene salvata per prompt, che mostra se il provider ha risposto. Ctrl-C lo ferma\n" +
+		"con codice di uscita 130.\n" +
+		"--dry-run fa tutto tranne scrivere: ogni riga inizia con 'would pull' e finisce con\n" +
+		"file=create, file=overwrite o file=unchanged. Fallisce dove fallirebbe pull, per esempio su\n" +
+		"un file esistente senza --overwrite, e non registra alcun avanzamento --resume.\n" +
+		"--expire-after (o expire_after nella configurazione) registra nella directory di stato\n" +
+		"dell'utente quando scadono i file scaricati; prompt, list --stale e --watch segnalano poi quelli\n" +
+		"scaduti perché le copie in chiaro vengano aggiornate o rimosse. I file non vengono mai\n" +
+		"cancellati automaticamente. Un pull senza nessuno dei due elimina la scadenza registrata dei\n" +
+		"file che scrive.",
+	CommandFlagHelpID("pull", "all"):            "Scarica tutte le voci di mapping con mode pull|both (mode è both per impostazione predefinita)",
+	CommandFlagHelpID("pull", "ci-export"):      "Passa anche le variabili dotenv ai passi di CI successivi (GitHub Actions, GitLab CI, CircleCI)",
+	CommandFlagHelpID("pull", "coerce"):         "Usa un segreto il cui tipo differisce da mapping.type quando il contenuto si converte in sicurezza, con un avviso",
+	CommandFlagHelpID("pull", "dry-run"):        "Legge e converte i segreti e stampa cosa verrebbe scritto, senza scrivere",
+	CommandFlagHelpID("pull", "expire-after"):   "Segna i file scaricati come scaduti dopo questa durata, es. 8h (predefinito: expire_after della configurazione)",
+	CommandFlagHelpID("pull", "expiry-warning"): "Avvisa dei certificati che scadono entro questa durata, es. 30d (predefinito 30d)",
+	CommandFlagHelpID("pull", "fake"):           "Scrive file dotenv con valori fittizi per le chiavi di mapping.fake_keys, senza leggere i segreti",
+	CommandFlagHelpID("pull", "interval"):       "Ogni quanto --watch interroga il provider, es. 30s o 5m (predefinito 30s)",
+	CommandFlagHelpID("pull", "mtime"):          "Data di modifica dei file scaricati (predefinito: mtime della configurazione, altrimenti adesso)",
+	CommandFlagHelpID("pull", "overwrite"):      "Sovrascrive i file esistenti",
+	CommandFlagHelpID("pull", "resume"):         "Salta le destinazioni completate dall'ultima esecuzione interrotta o fallita per questa configurazione",
+	CommandFlagHelpID("pull", "revision"):       "Scarica questa revisione del segreto invece dell'ultima abilitata (un solo segreto)",
+	CommandFlagHelpID("pull", "watch"):          "Resta attivo e scarica di nuovo ogni segreto che riceve una nuova revisione abilitata",
+
+	CommandDescriptionID("push"): "Invia uno o più segreti dal disco a Scaleway Secret Manager come nuova versione.\n" +
+		"I segreti devono essere nel mapping e i nomi devono terminare con '-dev'.\n" +
+		"Non stampa mai il contenuto dei segreti.\n" +
+		"\n" +
+		"Formati:\n" +
+		"  - mapping.format=raw legge i byte del file così come sono.\n" +
+		"  - mapping.format=dotenv legge un file .env e invia un contenuto JSON.\n" +
+		"  - mapping.format=yaml legge un mapping YAML e invia un contenuto JSON.\n" +
+		"  - mapping.format=toml legge un documento TOML e invia un contenuto JSON.\n" +
+		"  - mapping.format=json invia il JSON del file compattato.\n" +
+		"  - i mapping mapping.format=template sono solo per il pull e non possono essere inviati.\n" +
+		"  - mapping.files rilegge ogni file nella sua chiave; le chiavi non elencate vengono mantenute.",
+	CommandNotesID("push"): "--create-missing crea il segreto se manca (richiede mapping.type).\n" +
+		"La creazione del segreto usa mapping.path ('/' per impostazione predefinita).\n" +
+		"Se si invia più di un segreto, push li elenca e chiede conferma su un terminale; senza\n" +
+		"terminale (CI, pipe) devi passare --yes.\n" +
+		"Un segreto il cui tipo differisce da mapping.type viene rifiutato a meno di --coerce; il\n" +
+		"contenuto deve allora adattarsi al tipo del segreto (qualsiasi cosa per opaque, un oggetto JSON\n" +
+		"per key_value, basic_credentials e database_credentials).\n" +
+		"Le regole di policy (dimensione del contenuto, nomi di chiave vietati, etichette richieste) sono\n" +
+		"verificate prima di creare qualsiasi versione: level=warn stampa avvisi, level=enforce\n" +
+		"interrompe il push.\n" +
+		"Un hook policy.rego viene valutato con `opa eval` solo sui metadati; qualsiasi motivo di rifiuto interrompe il push.\n" +
+		"Le destinazioni completate sono registrate nella directory di stato dell'utente; --resume le\n" +
+		"salta dopo un'esecuzione interrotta o fallita. Un'esecuzione del tutto riuscita cancella il registro.\n" +
+		"--disable-older-than e --keep-enabled disabilitano le versioni precedenti di ogni segreto\n" +
+		"inviato una volta creata la nuova versione; la nuova versione resta sempre abilitata. Con\n" +
+		"entrambi, viene disabilitata una versione selezionata da uno dei due. Le versioni disabilitate\n" +
+		"restano elencate e possono essere riabilitate.\n" +
+		"--dry-run legge, converte e verifica ogni file, risolve i segreti e stampa righe 'would push',\n" +
+		"'would create' e 'would disable'; non viene scritto alcun segreto, versione o stato condiviso,\n" +
+		"e non si registra alcun avanzamento --resume.",
+	CommandFlagHelpID("push", "all"):                "Invia tutte le voci di mapping con mode push|both (mode è both per impostazione predefinita)",
+	CommandFlagHelpID("push", "coerce"):             "Usa un segreto il cui tipo differisce da mapping.type quando il contenuto si converte in sicurezza, con un avviso",
+	CommandFlagHelpID("push", "create-missing"):     "Crea i segreti mancanti (richiede mapping.type)",
+	CommandFlagHelpID("push", "description"):        "Descrizione della nuova versione (facoltativa)",
+	CommandFlagHelpID("push", "disable-older-than"): "Dopo l'invio, disabilita le versioni abilitate più vecchie di <age> (es. 30d)",
+	CommandFlagHelpID("push", "disable-previous"):   "Disabilita la versione abilitata precedente quando ne crea una nuova",
+	CommandFlagHelpID("push", "dry-run"):            "Legge e converte i file e stampa cosa verrebbe inviato, senza modificare nulla",
+	CommandFlagHelpID("push", "keep-enabled"):       "Dopo l'invio, disabilita tutte le versioni abilitate tranne le <n> più recenti",
+	CommandFlagHelpID("push", "policy-file"):        "File JSON di policy che sostituisce la sezione policy di .scw.json",
+	CommandFlagHelpID("push", "resume"):             "Salta le destinazioni completate dall'ultima esecuzione interrotta o fallita per questa configurazione",
+	CommandFlagHelpID("push", "yes"):                "Conferma l'invio multiplo (chiesto su un terminale, richiesto altrove per inviare più di un segreto)",
+
+	CommandDescriptionID("sync"): "Confronta ogni voce di mapping con la revisione a cui il suo file locale è stato scaricato o\n" +
+		"inviato l'ultima volta, come registrato nell'attributo esteso user.dev-vault del file, e poi:\n" +
+		"  - scarica quando è cambiato solo il segreto remoto, o il file locale manca,\n" +
+		"  - invia quando è cambiato solo il file locale,\n" +
+		"  - segnala un conflitto quando sono cambiati entrambi, o il file non ha una revisione registrata.\n" +
+		"I conflitti non vengono mai risolti automaticamente: nessuno dei due lati viene sovrascritto.\n" +
+		"Risolvili con pull --overwrite o push, e rilancia sync.\n" +
+		"Non stampa mai il contenuto dei segreti.",
+	CommandNotesID("sync"): "Partecipano solo le voci con mapping.mode=both.\n" +
+		"Gli invii sono prima verificati rispetto alla policy di push. Se si dovesse inviare più di un\n" +
+		"segreto, devi passare --yes.\n" +
+		"Il comando esce con codice 1 quando segnala un conflitto.",
+	CommandFlagHelpID("sync", "all"):         "Sincronizza tutte le voci di mapping con mode both (mode è both per impostazione predefinita)",
+	CommandFlagHelpID("sync", "description"): "Descrizione delle versioni inviate (facoltativa)",
+	CommandFlagHelpID("sync", "dry-run"):     "Stampa le decisioni senza scaricare né inviare",
+	CommandFlagHelpID("sync", "policy-file"): "File JSON di policy che sostituisce la sezione policy di .scw.json",
+	CommandFlagHelpID("sync", "yes"):         "Conferma l'invio di più di un segreto",
+
+	CommandDescriptionID("ci"): "Stampa un job pronto da incollare che installa questa release di dev-vault, si autentica con\n" +
+		"SCW_ACCESS_KEY e SCW_SECRET_KEY dall'archivio dei segreti della piattaforma, ed esegue\n" +
+		"'pull --all --overwrite' nell'area di lavoro.\n" +
+		"Il job segue il manifesto corrente: --config viene passato quando non è ./.scw.json,\n" +
+		"e --ci-export viene aggiunto quando un mapping scaricato usa il formato dotenv.",
+	CommandNotesID("ci"): "Eseguilo dalla radice del repository; il percorso --config nel job è relativo a essa.\n" +
+		"Le build di sviluppo installano @latest.\n" +
+		"Non viene scritto nulla; reindirizza l'output o incollalo nel file della pipeline.",
+	CommandFlagHelpID("ci", "platform"): "Piattaforma di CI per cui generare (obbligatoria)",
+
+	CommandDescriptionID("generate"): "Genera nuovi valori da un modello, crea il segreto con essi come prima versione, e scrive il\n" +
+		"file dotenv mappato. Non stampa mai i valori generati.\n" +
+		"\n" +
+		"Le chiavi del modello corrispondono a una specifica di generatore:\n" +
+		"  - {\"generator\": \"hex\", \"bytes\": 32}   byte casuali, codificati in esadecimale (predefinito 32 byte)\n" +
+		"  - {\"generator\": \"uuid\"}               UUID casuale (versione 4)\n" +
+		"  - {\"generator\": \"bcrypt\", \"cost\": 12} hash bcrypt di un valore digitato a un prompt nascosto\n" +
+		"  - {\"value\": \"app\"}                    valore letterale",
+	CommandNotesID("generate"): "Il segreto deve essere mappato con formato dotenv e un mode che consenta il push.\n" +
+		"generate si rifiuta di procedere quando il segreto o il file locale esiste già.\n" +
+		"Il tipo del segreto viene da mapping.type, poi dal \"type\" del modello, poi key_value.\n" +
+		"bcrypt esegue 'htpasswd -nBC <cost>' per il prompt nascosto; htpasswd deve essere nel PATH.",
+	CommandFlagHelpID("generate", "description"): "Descrizione della prima versione (facoltativa)",
+	CommandFlagHelpID("generate", "template"):    "Modello di generazione (obbligatorio)",
+
+	CommandDescriptionID("import-env"): "Integra in un solo passo un progetto precedente a Secret Manager: crea un segreto key_value,\n" +
+		"invia le variabili di <file> come prima versione, e aggiunge per esso una voce di mapping\n" +
+		"dotenv a .scw.json. Non stampa mai il contenuto dei segreti.",
+	CommandNotesID("import-env"): "<file> è relativo alla directory corrente e deve trovarsi nella radice del progetto.\n" +
+		"import-env rifiuta un nome già mappato o un segreto che esiste già.\n" +
+		"Le regole di policy sono verificate prima di creare il segreto, come per push.\n" +
+		".scw.json viene riscritto in forma canonica (vedi 'config fmt').",
+	CommandFlagHelpID("import-env", "description"): "Descrizione della prima versione (facoltativa)",
+	CommandFlagHelpID("import-env", "name"):        "Nome del segreto da creare (obbligatorio)",
+	CommandFlagHelpID("import-env", "path"):        "Percorso del segreto (predefinito /)",
+	CommandFlagHelpID("import-env", "policy-file"): "File JSON di policy che sostituisce la sezione policy di .scw.json",
+
+	CommandDescriptionID("import-env-dir"): "Migra in blocco un repository esistente: ogni file di <dir> che corrisponde a --pattern diventa\n" +
+		"un segreto key_value chiamato {name}<suffix>, come lo creerebbe 'import-env', con una voce di\n" +
+		"mapping dotenv in .scw.json. Non stampa mai il contenuto dei segreti.",
+	CommandNotesID("import-env-dir"): "<dir> è relativo alla directory corrente e deve trovarsi nella radice del progetto.\n" +
+		"Le sottodirectory non vengono esplorate. I file che non corrispondono al modello sono ignorati.\n" +
+		"Ogni file viene verificato prima di creare qualsiasi cosa: i nomi dei segreti devono terminare\n" +
+		"con -dev, non devono essere già mappati e non devono esistere in remoto; le regole di policy\n" +
+		"si applicano come per push.\n" +
+		"--dry-run esegue gli stessi controlli e stampa cosa verrebbe creato.",
+	CommandFlagHelpID("import-env-dir", "description"): "Descrizione delle prime versioni (facoltativa)",
+	CommandFlagHelpID("import-env-dir", "dry-run"):     "Elenca i segreti che verrebbero creati senza modificare nulla",
+	CommandFlagHelpID("import-env-dir", "path"):        "Percorso di ogni segreto creato (predefinito /)",
+	CommandFlagHelpID("import-env-dir", "pattern"):     "Modello di nome file con un solo segnaposto {name} (predefinito {name}.env)",
+	CommandFlagHelpID("import-env-dir", "policy-file"): "File JSON di policy che sostituisce la sezione policy di .scw.json",
+	CommandFlagHelpID("import-env-dir", "suffix"):      "Aggiunto a {name} per formare il nome del segreto (predefinito -dev)",
+
+	CommandDescriptionID("db"): "Legge l'ultima versione abilitata di un segreto database_credentials mappato e avvia psql\n" +
+		"(postgres) o mysql (mysql, mariadb) collegato al database.\n" +
+		"Non viene scritto nulla su disco.",
+	CommandNotesID("db"): "La password raggiunge il client solo tramite PGPASSWORD o MYSQL_PWD nel suo ambiente.\n" +
+		"--print mostra la stringa di connessione e il comando senza la password.\n" +
+		"Il codice di uscita del client viene segnalato come errore quando non è zero.",
+	CommandFlagHelpID("db", "print"): "Stampa la stringa di connessione e il comando del client invece di eseguirlo",
+
+	CommandDescriptionID("ssh"): "Legge l'ultima versione abilitata di un segreto ssh_key mappato e passa la chiave privata a\n" +
+		"'ssh-add -' tramite una pipe. La chiave non tocca mai il disco.",
+	CommandNotesID("ssh"): "ssh-add deve essere nel PATH e SSH_AUTH_SOCK deve puntare a un agent in esecuzione.\n" +
+		"La passphrase delle chiavi protette viene chiesta da ssh-add stesso.\n" +
+		"Quando serve un file, pull scrive la chiave con modalità 0600 e il suo .pub accanto.",
+
+	CommandDescriptionID("cert"): "Legge l'ultima versione abilitata di un segreto certificate mappato e stampa soggetto,\n" +
+		"emittente, SAN, numero di serie e validità di ogni certificato del bundle PEM.\n" +
+		"Non viene scritto nulla su disco.",
+	CommandNotesID("cert"):            "Una chiave privata nel bundle viene segnalata come presente; il suo contenuto non viene mai stampato.",
+	CommandFlagHelpID("cert", "json"): "Output JSON",
+
+	CommandDescriptionID("get"): "Legge l'ultima versione abilitata di un segreto basic_credentials mappato e la scrive nel\n" +
+		"formato atteso da un altro strumento. L'output va sempre in --output con modalità 0600;\n" +
+		"le credenziali non vengono mai stampate.\n" +
+		"\n" +
+		"Formati:\n" +
+		"  - --as-netrc sostituisce la voce di <host> in un .netrc esistente, o ne aggiunge una.\n" +
+		"  - --as-docker-config imposta auths[<registry>] e mantiene il resto di config.json.\n" +
+		"  - --as-header scrive 'Authorization: Basic ...' per curl -H @<path>.",
+	CommandNotesID("get"):                        "Le voci netrc non possono contenere spazi o virgolette; tali credenziali vengono rifiutate.",
+	CommandFlagHelpID("get", "as-docker-config"): "Imposta auths[<registry>] in un config.json di docker",
+	CommandFlagHelpID("get", "as-header"):        "Scrive un file di intestazione Authorization per curl -H @<file>",
+	CommandFlagHelpID("get", "as-netrc"):         "Imposta la voce machine <host> in un file .netrc",
+	CommandFlagHelpID("get", "output"):           "File da scrivere (obbligatorio)",
+	CommandFlagHelpID("get", "overwrite"):        "Sostituisce un file --as-header esistente",
+
+	CommandDescriptionID("fixtures"): "Legge segreti dotenv (ogni mapping dotenv idoneo al pull quando non se ne nomina nessuno) e\n" +
+		"scrive le loro chiavi in --out come oggetto JSON, così i test dell'applicazione possono\n" +
+		"verificare il caricamento della configurazione senza credenziali reali. Le chiavi sono quelle\n" +
+		"che scrive pull, con filtri e affissi applicati.\n" +
+		"\n" +
+		"I valori vengono sostituiti, mai copiati: true/false diventa false, un intero un numero\n" +
+		"derivato dalla chiave, un URL mantiene lo schema e punta a fixture.invalid, e tutto il resto\n" +
+		"diventa fixture-<key>. Con --mask ogni valore è ********. I valori vuoti restano vuoti.\n" +
+		"L'output dipende solo dalle chiavi e dal tipo dei valori, quindi è stabile tra esecuzioni\n" +
+		"e rotazioni dei segreti.",
+	CommandNotesID("fixtures"):                 "Possono essere letti solo i mapping dotenv con mode pull|both.",
+	CommandFlagHelpID("fixtures", "mask"):      "Scrive ******** per ogni valore invece di valori fittizi tipizzati",
+	CommandFlagHelpID("fixtures", "out"):       "File JSON da scrivere (obbligatorio)",
+	CommandFlagHelpID("fixtures", "overwrite"): "Sostituisce un file --out esistente",
+
+	CommandDescriptionID("versions"): "Elenca ogni versione di un segreto mappato, dalla più recente, con revisione, stato\n" +
+		"(enabled, disabled, o destroyed dove il provider lo prevede), data di creazione e\n" +
+		"descrizione. Il contenuto non viene mai letto.",
+	CommandNotesID("versions"): "I provider senza descrizioni di versione (aws, vault) lasciano DESCRIPTION vuota.\n" +
+		"Le versioni aws scritte da altri strumenti appaiono con revisione 0.",
+	CommandFlagHelpID("versions", "json"): "Output JSON",
+
+	CommandDescriptionID("rollback"): "Crea una nuova versione di un segreto mappato dal contenuto di una revisione precedente, così\n" +
+		"un push sbagliato può essere annullato. Le versioni precedenti vengono mantenute; vedi il\n" +
+		"comando versions per le revisioni.\n" +
+		"La revisione deve esistere ed essere abilitata. Il file locale non viene modificato; scaricalo\n" +
+		"poi con --overwrite.\n" +
+		"Non stampa mai il contenuto dei segreti.",
+	CommandNotesID("rollback"): "Chiede conferma su stdin a meno di --yes; senza risposta non viene scritto nulla.\n" +
+		"Possono essere ripristinati solo i mapping con mode push|both.\n" +
+		"--disable-previous disabilita la versione sbagliata, che non viene più letta per impostazione predefinita.",
+	CommandFlagHelpID("rollback", "description"):      "Descrizione della nuova versione (facoltativa)",
+	CommandFlagHelpID("rollback", "disable-previous"): "Disabilita la versione abilitata precedente quando crea la nuova versione",
+	CommandFlagHelpID("rollback", "to-revision"):      "Revisione il cui contenuto diventa la nuova versione (obbligatoria)",
+	CommandFlagHelpID("rollback", "yes"):              "Salta la richiesta di conferma",
+
+	CommandDescriptionID("delete"): "Elimina dal provider un segreto mappato e tutte le sue versioni. Il file locale e la voce\n" +
+		"di mapping non vengono toccati.\n" +
+		"Richiede --yes e il nome del segreto ridigitato su stdin; qualsiasi altra risposta non elimina nulla.",
+	CommandNotesID("delete"): "Possono essere eliminati solo i mapping con mode push|both.\n" +
+		"Scaleway e Vault eliminano subito; aws programma l'eliminazione dopo un periodo di recupero di 30 giorni.",
+	CommandFlagHelpID("delete", "yes"): "Conferma l'eliminazione (obbligatorio; il nome del segreto viene comunque chiesto su stdin)",
+
+	CommandDescriptionID("delete-version"): "Elimina una versione di un segreto mappato; le altre versioni vengono mantenute. Vedi il\n" +
+		"comando versions per le revisioni. Anche le revisioni disabilitate possono essere eliminate.\n" +
+		"Richiede --yes e il nome del segreto ridigitato su stdin; qualsiasi altra risposta non elimina nulla.",
+	CommandNotesID("delete-version"): "Possono essere modificati solo i mapping con mode push|both.\n" +
+		"Vault distrugge i dati della versione; aws non può eliminare una singola versione.",
+	CommandFlagHelpID("delete-version", "revision"): "Revisione da eliminare (obbligatoria)",
+	CommandFlagHelpID("delete-version", "yes"):      "Conferma l'eliminazione (obbligatorio; il nome del segreto viene comunque chiesto su stdin)",
+
+	CommandDescriptionID("gc"): "Mantiene le <n> versioni abilitate più recenti di ogni segreto mappato e disabilita le versioni\n" +
+		"abilitate più vecchie. Con --delete, tutte le altre versioni vengono invece eliminate, incluse\n" +
+		"quelle disabilitate. Una revisione fissata da mapping.revision viene sempre mantenuta.\n" +
+		"Senza --yes non cambia nulla: una tabella elenca, per segreto, cosa verrebbe rimosso.\n" +
+		"Il contenuto non viene mai letto.",
+	CommandNotesID("gc"): "Vengono trattati solo i mapping con mode push|both.\n" +
+		"aws non può eliminare una versione: con --delete, le sue vecchie versioni abilitate vengono\n" +
+		"disabilitate e quelle disabilitate lasciate scadere. Vault distrugge i dati delle versioni eliminate.",
+	CommandFlagHelpID("gc", "all"):    "Tratta tutte le voci di mapping con mode push|both (mode è both per impostazione predefinita)",
+	CommandFlagHelpID("gc", "delete"): "Elimina le vecchie versioni, incluse quelle disabilitate, invece di disabilitarle",
+	CommandFlagHelpID("gc", "keep"):   "Numero di versioni abilitate più recenti da mantenere (obbligatorio)",
+	CommandFlagHelpID("gc", "yes"):    "Applica il piano (senza, viene stampato solo il piano)",
+
+	CommandDescriptionID("exec"): "Legge l'ultima versione abilitata di un segreto key_value mappato ed esegue <command> con le\n" +
+		"sue chiavi aggiunte all'ambiente. Non viene scritto nulla su disco.\n" +
+		"Per un mapping dotenv le variabili sono quelle che scriverebbe pull, con compose, keys,\n" +
+		"substitute e key_prefix/key_suffix applicati.",
+	CommandNotesID("exec"): "Tutto ciò che segue <secret-dev> va a <command> così com'è; il -- che lo precede è facoltativo.\n" +
+		"Le chiavi del segreto sostituiscono le variabili con lo stesso nome già presenti nell'ambiente.\n" +
+		"dev-vault esce con il codice di uscita del comando, e ignora SIGINT/SIGTERM mentre è in\n" +
+		"esecuzione così è il comando a decidere come fermarsi.\n" +
+		"I valori non compaiono mai nell'output di dev-vault.",
+
+	CommandDescriptionID("prompt"): "Stampa una riga di stato compatta per i framework del prompt (starship, oh-my-zsh).\n" +
+		"Vengono letti solo il manifesto, i file locali e la directory di stato dell'utente: il provider\n" +
+		"non viene mai contattato, e il comando rinuncia dopo 50ms, così può essere eseguito a ogni\n" +
+		"visualizzazione del prompt.",
+	CommandNotesID("prompt"): "drift.missing conta i file mappati che non esistono in locale.\n" +
+		"drift.expired conta i file mappati il cui expire_after (vedi pull) è passato.\n" +
+		"drift.changed conta i file mappati che non corrispondevano al loro segreto remoto, nel\n" +
+		"contenuto o nella revisione, all'ultimo status o pull --watch, e non sono stati scaricati da allora.\n" +
+		"last_sync è l'ultimo pull registrato di un file mappato, o la sua data di modifica quando non\n" +
+		"è stato registrato alcun pull (null se non ne esiste nessuno).\n" +
+		"provider vale 'reachable' o 'unreachable' secondo l'ultimo status o pull --watch, il cui\n" +
+		"orario è checked_at, o 'unknown' (checked_at null) prima del primo.\n" +
+		"Oltre il budget di 50ms non viene stampato nulla e il codice di uscita è 1.",
+	CommandFlagHelpID("prompt", "json"): "Output JSON",
+
+	CommandDescriptionID("projects"): "Elenca i progetti (nome e ID) visibili con le credenziali Scaleway correnti.\n" +
+		"Non richiede un .scw.json, quindi può servire a compilare organization_id/project_id.",
+	CommandFlagHelpID("projects", "json"):            "Output JSON",
+	CommandFlagHelpID("projects", "organization-id"): "Organizzazione da elencare (predefinita: dal profilo o dall'ambiente Scaleway)",
+
+	CommandDescriptionID("regions"):      "Elenca i valori validi per il campo region di .scw.json.",
+	CommandFlagHelpID("regions", "json"): "Output JSON",
+
+	CommandDescriptionID("report"): "Scrive su stdout un inventario condivisibile dei segreti di sviluppo del progetto:\n" +
+		"copertura del mapping, differenze locale/remoto, età dell'ultimo aggiornamento di ogni\n" +
+		"segreto, segreti di sviluppo remoti non coperti da alcuna voce di mapping, e violazioni\n" +
+		"della policy (max_secret_age e required_tags, valutate sui metadati remoti).",
+	CommandNotesID("report"): "Il contenuto dei segreti viene letto solo per confrontarlo con i file locali e non viene mai mostrato.\n" +
+		"L'output HTML è una singola pagina autonoma (CSS in linea, nessuna risorsa esterna).\n" +
+		"L'ultimo aggiornamento è l'orario di aggiornamento del segreto Scaleway, che cambia a ogni nuova versione.\n" +
+		"Il proprietario proviene da un tag owner:<team> o owner=<team> del segreto.",
+	CommandFlagHelpID("report", "format"):      "Formato del report (predefinito markdown)",
+	CommandFlagHelpID("report", "policy-file"): "File JSON di policy che sostituisce la sezione policy di .scw.json",
+
+	CommandDescriptionID("lint-names"): "Verifica ogni chiave di mapping e ogni segreto -dev remoto del progetto rispetto alla\n" +
+		"sezione naming di .scw.json ed elenca ogni deviazione.\n" +
+		"\n" +
+		"Regole di denominazione:\n" +
+		"  - kebab_case: lettere minuscole e cifre in parole separate da un solo trattino.\n" +
+		"  - required_suffix: un suffisso con cui ogni nome deve terminare, come -env-dev.\n" +
+		"  - patterns: espressioni regolari; un nome deve corrispondere ad almeno una.",
+	CommandNotesID("lint-names"): "Senza una sezione naming, dei nomi si verifica solo il kebab-case.\n" +
+		"Esce con codice 1 quando trova una deviazione, così può bloccare la CI.",
+	CommandFlagHelpID("lint-names", "json"): "Output JSON",
+
+	CommandDescriptionID("doctor"): "Consulta ogni voce di mapping abilitata (solo metadati, nessun contenuto) e segnala gli\n" +
+		"abbinamenti di tipo di segreto e formato di mapping che farebbero fallire pull o push,\n" +
+		"con l'abbinamento adatto:\n" +
+		"  - certificate o ssh_key mappato come dotenv: il testo PEM non è un oggetto JSON; usa raw.\n" +
+		"  - key_value mappato come raw: il file riceve il contenuto JSON; usa dotenv.\n" +
+		"  - mapping.type diverso dal tipo del segreto: le ricerche per tipo non trovano il segreto.\n" +
+		"  - nessun segreto al nome e al percorso mappati.\n" +
+		"  - un file dotenv locale che non si analizza, con la riga colpevole.\n" +
+		"  - un segreto remoto oltre il max_secret_age della policy o privo delle sue required_tags.",
+	CommandNotesID("doctor"): "Esce con codice 1 quando segnala qualcosa, così può bloccare la CI.\n" +
+		"--output vscode stampa una riga 'file:line:column: error: message' per problema, per un\n" +
+		"problem matcher di un task di VS Code. I problemi del segreto remoto puntano alla voce di\n" +
+		"mapping in .scw.json.",
+	CommandFlagHelpID("doctor", "json"):        "Output JSON (come --output json)",
+	CommandFlagHelpID("doctor", "output"):      "Formato di output (predefinito table)",
+	CommandFlagHelpID("doctor", "policy-file"): "File JSON di policy che sostituisce la sezione policy di .scw.json",
+
+	CommandDescriptionID("validate"): "Carica e convalida il manifesto, poi verifica che ogni file, voce files e template_file del\n" +
+		"mapping si risolva all'interno della radice del progetto. Non viene letto nulla dal provider a\n" +
+		"meno di --remote, che esegue i controlli di doctor: ogni segreto mappato esiste, e il suo tipo\n" +
+		"si adatta a mapping.type e mapping.format.",
+	CommandNotesID("validate"): "Codici di uscita: 0 nessun rilievo, 3 il manifesto non si carica, 4 un percorso mappato esce\n" +
+		"dalla radice del progetto, 5 un controllo remoto è fallito; 1 e 2 mantengono il significato consueto.",
+	CommandFlagHelpID("validate", "json"):   "Stampa i rilievi in JSON",
+	CommandFlagHelpID("validate", "remote"): "Verifica anche che ogni segreto mappato esista e corrisponda al tipo dichiarato",
+
+	CommandDescriptionID("access"): "Stampa il responsabile, il contatto e la pagina di richiesta di accesso indicati da\n" +
+		"access_contact in .scw.json, così chi contribuisce per la prima volta e si vede negare il pull\n" +
+		"sa a chi rivolgersi. Non servono credenziali.\n" +
+		"Con --open, la pagina di richiesta si apre in $BROWSER o nel browser predefinito della piattaforma.",
+	CommandNotesID("access"):            "I comandi rifiutati dal provider stampano lo stesso contatto come suggerimento.",
+	CommandFlagHelpID("access", "open"): "Apre access_contact.request_url nel browser",
+
+	CommandDescriptionID("status"): "Riporta ogni voce di mapping abilitata: se il segreto remoto esiste e la sua ultima revisione\n" +
+		"abilitata, se il file locale esiste e quando è stato scritto l'ultima volta, e se il file\n" +
+		"corrisponde a ciò che pull scriverebbe adesso (confronto tramite SHA-256).\n" +
+		"MANAGED indica se il file porta il marcatore che pull scrive per il segreto (l'attributo esteso\n" +
+		"user.dev-vault, o il commento in prima riga dei file dotenv), che distingue i file scritti da\n" +
+		"dev-vault da quelli fatti a mano.\n" +
+		"Contenuti e digest non vengono mai stampati.",
+	CommandNotesID("status"): "MATCH è '-' quando manca uno dei due lati o il segreto non può essere reso per il mapping;\n" +
+		"il motivo di quest'ultimo caso viene stampato su stderr.\n" +
+		"Con una policy, i segreti remoti oltre max_secret_age o privi delle required_tags sono\n" +
+		"segnalati come avvisi, qualunque sia il livello della policy.\n" +
+		"L'esito viene salvato nella directory di stato dell'utente per prompt: se il provider ha\n" +
+		"risposto, e quali file non corrispondono.",
+	CommandFlagHelpID("status", "json"):        "Output JSON",
+	CommandFlagHelpID("status", "policy-file"): "File JSON di policy che sostituisce la sezione policy di .scw.json",
+
+	CommandDescriptionID("state"): "Legge lo state_secret indicato in .scw.json, dove push, sync e rollback registrano la nuova\n" +
+		"revisione di ogni segreto, quando è stata inviata e da quale host. Ogni voce di mapping\n" +
+		"abilitata viene elencata con quel record e la revisione nel marcatore del suo file locale.\n" +
+		"Viene letto solo il segreto di stato; i contenuti dei segreti mappati non vengono mai\n" +
+		"scaricati né stampati.",
+	CommandNotesID("state"): "DRIFT vale current, behind (un collega ha inviato una revisione più recente; scaricala),\n" +
+		"ahead (il record è più vecchio del file), unknown (il file non ha un marcatore di\n" +
+		"revisione), missing (nessun file locale) o unrecorded (nessun push ancora registrato).\n" +
+		"Ogni scrittura si fonde nell'ultima versione dello stato, così i colleghi che inviano\n" +
+		"nello stesso momento mantengono i record degli altri.",
+	CommandFlagHelpID("state", "json"): "Output JSON",
+
+	CommandDescriptionID("telemetry"): "La telemetria è disattivata finché non esegui 'dev-vault telemetry on'.\n" +
+		"Quando è attiva, ogni comando registra solo nome, durata e tipo di errore\n" +
+		"(none, usage o failure): nessun argomento, percorso, ID di progetto o nome di segreto.",
+	CommandNotesID("telemetry"): "Gli eventi vengono aggiunti a un file locale nella tua directory di configurazione utente; nulla viene inviato in rete.\n" +
+		"DO_NOT_TRACK=1 sopprime la registrazione anche quando la telemetria è attiva.",
+
+	CommandDescriptionID("disable-mapping"): "Imposta disabled: true sulla voce di mapping in .scw.json senza rimuoverla.\n" +
+		"Le voci disabilitate vengono saltate da pull --all, push --all, list --stale, prompt e report.\n" +
+		"Indicare esplicitamente una voce disabilitata (per esempio 'pull <secret-dev>') funziona comunque.",
+	CommandNotesID("disable-mapping"): "Il manifesto viene riscritto con indentazione di due spazi e chiavi di mapping ordinate.",
+
+	CommandDescriptionID("enable-mapping"): "Rimuove disabled: true dalla voce di mapping in .scw.json.",
+	CommandNotesID("enable-mapping"):       "Il manifesto viene riscritto con indentazione di due spazi e chiavi di mapping ordinate.",
+
+	CommandDescriptionID("agent"): "Espone un'API JSON su un indirizzo di loopback o un socket unix fino a Ctrl-C, per le\n" +
+		"estensioni degli IDE. All'avvio stampa una riga JSON con l'url di base (o il percorso del\n" +
+		"socket) e un token bearer creato per questa sessione; ogni richiesta deve inviare\n" +
+		"'Authorization: Bearer <token>'.\n" +
+		"\n" +
+		"Endpoint:\n" +
+		"  GET  /v1/mappings  le voci di mapping: name, file, files, format, mode, type, disabled.\n" +
+		"  GET  /v1/status    i record di status --json.\n" +
+		"  POST /v1/pull      scarica {\"names\": [...]} o {\"all\": true}, con \"overwrite\": true\n" +
+		"                     per sostituire i file esistenti, e restituisce ciò che ha scritto.\n" +
+		"Gli errori sono {\"error\": \"...\"} con stato 400 per una richiesta non valida e 500 altrimenti.",
+	CommandNotesID("agent"): "Sono accettati solo indirizzi di loopback. Le richieste con un'intestazione Origin, che i\n" +
+		"browser inviano, vengono rifiutate, così le pagine web non possono raggiungere l'API.\n" +
+		"Il socket è leggibile e scrivibile solo dall'utente, e viene rimosso all'uscita.\n" +
+		"Le richieste sono servite una alla volta, e ognuna interroga di nuovo il provider. I contenuti\n" +
+		"non vengono mai restituiti: pull scrive i file come fa il comando pull.\n" +
+		"L'agent esce con codice 130 quando viene fermato.",
+	CommandFlagHelpID("agent", "listen"): "Indirizzo di loopback su cui ascoltare (predefinito 127.0.0.1:0, una porta libera)",
+	CommandFlagHelpID("agent", "socket"): "Ascolta invece su un socket unix a questo percorso",
+
+	CommandDescriptionID("where"): "Ogni comando che carica una configurazione ne registra il percorso nella directory di stato\n" +
+		"dell'utente; where elenca quelle copie di lavoro con lo stato locale dei loro file mappati,\n" +
+		"come fa prompt per una sola.\n" +
+		"Vengono letti solo manifesti e file locali: il provider non viene mai contattato.",
+	CommandNotesID("where"): "status vale ok, drift (file mancanti o scaduti, vedi pull --expire-after), gone (il file di\n" +
+		"configurazione non esiste più) o invalid (la configurazione non si carica).\n" +
+		"Le copie di lavoro vengono registrate al massimo una volta all'ora, quindi last_used è preciso all'ora.\n" +
+		"--prune dimentica le copie di lavoro scomparse, per esempio cloni eliminati.",
+	CommandFlagHelpID("where", "json"):  "Output JSON",
+	CommandFlagHelpID("where", "prune"): "Dimentica le copie di lavoro il cui file di configurazione non esiste più",
+
+	CommandDescriptionID("completion"): "Stampa lo script di completamento di comandi e opzioni per la shell, $SHELL per impostazione predefinita.\n" +
+		"--install aggiunge una riga che lo carica a ~/.bashrc, ~/.zshrc ($ZDOTDIR) o\n" +
+		"~/.config/fish/config.fish ($XDG_CONFIG_HOME), tra commenti marcatori\n" +
+		"'# >>> dev-vault completion >>>'. Rieseguirlo non cambia nulla; --uninstall rimuove il blocco\n" +
+		"marcato e lascia intatto il resto del file.\n" +
+		"\n" +
+		"In bash e zsh, gli argomenti segreto si completano con i nomi del mapping del manifesto. Con\n" +
+		"DEV_VAULT_COMPLETE_REMOTE=1 si completano anche con i nomi -dev remoti del progetto, memorizzati\n" +
+		"nella directory di stato per 10 minuti e aggiornati da list; un aggiornamento non blocca mai la\n" +
+		"shell per più di 100ms, e offline vengono usati i nomi in cache. --names stampa cosa verrebbe proposto.",
+	CommandNotesID("completion"): "Un file di avvio che è un collegamento simbolico viene modificato attraverso il collegamento;\n" +
+		"uno con altri collegamenti fisici viene rifiutato. La riga installata non fa nulla una volta che\n" +
+		"dev-vault non è più nel PATH.",
+	CommandFlagHelpID("completion", "install"):   "Aggiunge la riga che carica il completamento al file di avvio della shell",
+	CommandFlagHelpID("completion", "names"):     "Stampa i nomi dei segreti che completano un argomento segreto, uno per riga",
+	CommandFlagHelpID("completion", "uninstall"): "Rimuove la riga aggiunta da --install",
+}
@@ -0,0 +1,214 @@
+package i18n
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+type Lang string
+
+const (
+	English Lang = "en"
+	French  Lang = "fr"
+	Italian Lang = "it"
+)
+
+// localeEnvVars follows POSIX precedence for selecting the message locale.
+var localeEnvVars = []string{"LC_ALL", "LC_MESSAGES", "LANG"}
+
+func Supported() []Lang {
+	out := make([]Lang, 0, len(catalogs))
+	for lang := range catalogs {
+		out = append(out, lang)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i] < out[j] })
+	return out
+}
+
+// ParseLang accepts bare language codes as well as POSIX locale names
+// such as fr_FR.UTF-8 or it-IT.
+func ParseLang(value string) (Lang, error) {
+	code := strings.ToLower(strings.TrimSpace(value))
+	if i := strings.IndexAny(code, "_-.@"); i >= 0 {
+		code = code[:i]
+	}
+	lang := Lang(code)
+	if _, ok := catalogs[lang]; !ok {
+		names := make([]string, 0, len(catalogs))
+		for _, supported := range Supported() {
+			names = append(names, string(supported))
+		}
+		return "", fmt.Errorf("unsupported language %q (supported: %s)", value, strings.Join(names, ", "))
+	}
+	return lang, nil
+}
+
+// FromEnv picks the first set locale variable; unsupported or C/POSIX locales
+// fall back to English rather than failing.
+func FromEnv(getenv func(string) string) Lang {
+	if getenv == nil {
+		return English
+	}
+	for _, key := range localeEnvVars {
+		value := getenv(key)
+		if value == "" {
+			continue
+		}
+		lang, err := ParseLang(value)
+		if err != nil {
+			return English
+		}
+		return lang
+	}
+	return English
+}
+
+// Localizer resolves message IDs for one language. The zero value is English.
+type Localizer struct {
+	lang Lang
+}
+
+func New(lang Lang) Localizer {
+	return Localizer{lang: lang}
+}
+
+func (l Localizer) Lang() Lang {
+	if l.lang == "" {
+		return English
+	}
+	return l.lang
+}
+
+func (l Localizer) Text(id MessageID) string {
+	return l.TextOr(id, string(id))
+}
+
+// TextOr returns fallback when neither the selected language nor English
+// defines id.
+func (l Localizer) TextOr(id MessageID, fallback string) string {
+	if text, ok := catalogs[l.Lang()][id]; ok {
+		return text
+	}
+	if text, ok := catalogs[English][id]; ok {
+		return text
+	}
+	return fallback
+}
+
+// LinesOr splits the text of id into lines, returning fallback when neither
+// the selected language nor English defines id.
+func (l Localizer) LinesOr(id MessageID, fallback []string) []string {
+	text := l.TextOr(id, "")
+	if text == "" {
+		return fallback
+	}
+	return strings.Split(text, "\n")
+}
+
+func (l Localizer) Sprintf(id MessageID, args ...any) string {
+	return fmt.Sprintf(l.Text(id), args...)
+}
+
+// Error is an error whose message is a catalog format. Error renders it in
+// English; Localizer.Error renders it in the localizer's language.
+type Error struct {
+	ID   MessageID
+	Args []any
+}
+
+// Errorf returns an *Error for the catalog format id. Like fmt.Errorf, it
+// wraps the arguments formatted with %w. Arguments that are errors or message
+// IDs are rendered in the language of the error.
+func Errorf(id MessageID, args ...any) error {
+	return &Error{ID: id, Args: args}
+}
+
+func (e *Error) Error() string {
+	return Localizer{}.render(e)
+}
+
+// Unwrap returns the arguments formatted with %w.
+func (e *Error) Unwrap() []error {
+	var wrapped []error
+	for i, verb := range verbs(Localizer{}.Text(e.ID)) {
+		if verb != 'w' || i >= len(e.Args) {
+			continue
+		}
+		if err, ok := e.Args[i].(error); ok {
+			wrapped = append(wrapped, err)
+		}
+	}
+	return wrapped
+}
+
+// Error renders err in the language of l: every *Error in its chain is
+// replaced by its translation, and text added by other wrappers is kept.
+func (l Localizer) Error(err error) string {
+	return l.localize(err, err.Error())
+}
+
+func (l Localizer) localize(err error, text string) string {
+	switch e := err.(type) {
+	case *Error:
+		return strings.Replace(text, e.Error(), l.render(e), 1)
+	case interface{ Unwrap() error }:
+		if inner := e.Unwrap(); inner != nil {
+			return l.localize(inner, text)
+		}
+	case interface{ Unwrap() []error }:
+		for _, inner := range e.Unwrap() {
+			text = l.localize(inner, text)
+		}
+	}
+	return text
+}
+
+func (l Localizer) render(e *Error) string {
+	args := make([]any, len(e.Args))
+	for i, arg := range e.Args {
+		switch arg := arg.(type) {
+		case error:
+			args[i] = l.Error(arg)
+		case MessageID:
+			args[i] = l.Text(arg)
+		default:
+			args[i] = arg
+		}
+	}
+	return fmt.Sprintf(strings.ReplaceAll(l.Text(e.ID), "%w", "%v"), args...)
+}
+
+// verbs lists the verb of each argument format consumes, in order.
+func verbs(format string) []byte {
+	var out []byte
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			continue
+		}
+		i++
+		for i < len(format) && strings.IndexByte("+-# 0123456789.", format[i]) >= 0 {
+			i++
+		}
+		if i < len(format) && format[i] != '%' {
+			out = append(out, format[i])
+		}
+	}
+	return out
+}
+
+func CommandSummaryID(command string) MessageID {
+	return MessageID("command." + command + ".summary")
+}
+
+func CommandDescriptionID(command string) MessageID {
+	return MessageID("command." + command + ".description")
+}
+
+func CommandNotesID(command string) MessageID {
+	return MessageID("command." + command + ".notes")
+}
+
+func CommandFlagHelpID(command, flag string) MessageID {
+	return MessageID("command." + command + ".flag." + flag)
+}
@@ -0,0 +1,163 @@
+package i18n
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSupported(t *testing.T) {
+	want := []Lang{English, French, Italian}
+	if got := Supported(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Supported() mismatch: got=%v want=%v", got, want)
+	}
+}
+
+func TestParseLang(t *testing.T) {
+	cases := map[string]Lang{
+		"fr":          French,
+		"FR":          French,
+		"fr_FR.UTF-8": French,
+		"it-IT":       Italian,
+		" en ":        English,
+		"en_US@euro":  English,
+	}
+	for input, want := range cases {
+		got, err := ParseLang(input)
+		if err != nil {
+			t.Fatalf("ParseLang(%q): %v", input, err)
+		}
+		if got != want {
+			t.Fatalf("ParseLang(%q) = %q, want %q", input, got, want)
+		}
+	}
+	if _, err := ParseLang("de"); err == nil || !strings.Contains(err.Error(), "supported: en, fr, it") {
+		t.Fatalf("expected unsupported language error, got %v", err)
+	}
+}
+
+func TestFromEnv(t *testing.T) {
+	env := func(values map[string]string) func(string) string {
+		return func(key string) string { return values[key] }
+	}
+	if got := FromEnv(nil); got != English {
+		t.Fatalf("nil getenv: got %q", got)
+	}
+	if got := FromEnv(env(nil)); got != English {
+		t.Fatalf("empty env: got %q", got)
+	}
+	if got := FromEnv(env(map[string]string{"LANG": "it_IT.UTF-8"})); got != Italian {
+		t.Fatalf("LANG: got %q", got)
+	}
+	if got := FromEnv(env(map[string]string{"LANG": "it_IT.UTF-8", "LC_ALL": "fr_FR.UTF-8"})); got != French {
+		t.Fatalf("LC_ALL precedence: got %q", got)
+	}
+	if got := FromEnv(env(map[string]string{"LC_MESSAGES": "C", "LANG": "fr_FR"})); got != English {
+		t.Fatalf("C locale: got %q", got)
+	}
+}
+
+func TestLocalizer(t *testing.T) {
+	var zero Localizer
+	if zero.Lang() != English {
+		t.Fatalf("zero localizer lang: %q", zero.Lang())
+	}
+	if got := zero.Sprintf(MsgWarning, "x"); got != "warning: x" {
+		t.Fatalf("english warning: %q", got)
+	}
+	fr := New(French)
+	if fr.Lang() != French {
+		t.Fatalf("french localizer lang: %q", fr.Lang())
+	}
+	if got := fr.Sprintf(MsgUnknownCommand, "x"); got != "commande inconnue : x" {
+		t.Fatalf("french unknown command: %q", got)
+	}
+	if got := fr.Text(MessageID("missing.id")); got != "missing.id" {
+		t.Fatalf("missing id fallback: %q", got)
+	}
+	if got := fr.TextOr(CommandSummaryID("nope"), "fallback"); got != "fallback" {
+		t.Fatalf("TextOr fallback: %q", got)
+	}
+	if got := fr.LinesOr(CommandNotesID("config"), nil); len(got) != 2 || !strings.HasPrefix(got[1], "Le manifeste doit") {
+		t.Fatalf("LinesOr: %q", got)
+	}
+	if got := fr.LinesOr(CommandDescriptionID("nope"), []string{"fallback"}); !reflect.DeepEqual(got, []string{"fallback"}) {
+		t.Fatalf("LinesOr fallback: %q", got)
+	}
+	if got := fr.TextOr(CommandFlagHelpID("pull", "overwrite"), ""); got != "Écrase les fichiers existants" {
+		t.Fatalf("flag help: %q", got)
+	}
+
+	// Swap in a copy without the entry, so the shared catalog is never edited.
+	italian := catalogs[Italian]
+	t.Cleanup(func() { catalogs[Italian] = italian })
+	partial := maps.Clone(italian)
+	delete(partial, MsgWarning)
+	catalogs[Italian] = partial
+	if got := New(Italian).Sprintf(MsgWarning, "x"); got != "warning: x" {
+		t.Fatalf("english fallback for missing translation: %q", got)
+	}
+}
+
+func TestError(t *testing.T) {
+	cause := errors.New("boom")
+	err := Errorf(MsgWriteFile, "a.env", cause)
+	if got := err.Error(); got != "write a.env: boom" {
+		t.Fatalf("english error: %q", got)
+	}
+	if !errors.Is(err, cause) {
+		t.Fatal("expected %w argument to be wrapped")
+	}
+	if got := Errorf(MsgRequiresFlag, cause, "x").(*Error).Unwrap(); len(got) != 0 {
+		t.Fatalf("only %%w arguments are wrapped: %v", got)
+	}
+	if got := (&Error{ID: MsgWriteFile}).Unwrap(); len(got) != 0 {
+		t.Fatalf("missing arguments: %v", got)
+	}
+
+	fr := New(French)
+	if got := fr.Error(err); got != "écriture de a.env : boom" {
+		t.Fatalf("french error: %q", got)
+	}
+	wrapped := fmt.Errorf("get x-dev: %w", Errorf(MsgLoadConfig, err))
+	if got := fr.Error(wrapped); got != "get x-dev: chargement de la configuration : écriture de a.env : boom" {
+		t.Fatalf("french nested error: %q", got)
+	}
+	joined := errors.Join(Errorf(MsgAllWithNames), cause)
+	if got := fr.Error(joined); got != "impossible d'utiliser --all avec des noms de secrets explicites\nboom" {
+		t.Fatalf("french joined error: %q", got)
+	}
+	if got := fr.Error(fmt.Errorf("x: %w", nil)); got != "x: %!w(<nil>)" {
+		t.Fatalf("nil wrapped error: %q", got)
+	}
+	reason := Errorf(MsgFlagExcludes, "watch", "ci-export", MsgReasonWatchLatest)
+	if got := New(Italian).Error(reason); got != "--watch non può essere combinato con --ci-export: --watch segue l'ultima revisione abilitata" {
+		t.Fatalf("italian message id argument: %q", got)
+	}
+	if got := cause.Error(); fr.Error(cause) != got {
+		t.Fatalf("plain errors stay as they are: %q", fr.Error(cause))
+	}
+}
+
+func TestVerbs(t *testing.T) {
+	if got := string(verbs("%d%% of %-8s %q, %w %")); got != "dsqw" {
+		t.Fatalf("verbs: %q", got)
+	}
+}
+
+func TestCatalogsTranslateEveryEnglishMessage(t *testing.T) {
+	for lang, catalog := range catalogs {
+		for id, english := range catalogs[English] {
+			text, ok := catalog[id]
+			if !ok {
+				t.Fatalf("catalog %q is missing %q", lang, id)
+			}
+			if string(verbs(text)) != string(verbs(english)) {
+				t.Fatalf("catalog %q: %q has mismatched format verbs", lang, id)
+			}
+		}
+	}
+}
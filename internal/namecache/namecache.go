@@ -0,0 +1,70 @@
+// Package namecache keeps the remote -dev secret names last listed for a
+// config file, so shell completion can offer them without waiting for the
+// provider. Entries hold secret names only, never payloads or secret IDs.
+package namecache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/bsmartlabs/dev-vault/internal/fsx"
+)
+
+const dirName = "names"
+
+type Entry struct {
+	Config    string    `json:"config"`
+	Profile   string    `json:"profile,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Names     []string  `json:"names"`
+}
+
+// Store keeps one entry per config file and profile under a per-user
+// directory.
+type Store struct {
+	dir string
+}
+
+func NewStore(dir string) Store {
+	return Store{dir: filepath.Join(dir, dirName)}
+}
+
+// Path names the entry file after a hash of the config path and profile, so
+// repositories and accounts never share names.
+func (s Store) Path(configPath, profile string) string {
+	sum := sha256.Sum256([]byte(configPath + "\x00" + profile))
+	return filepath.Join(s.dir, hex.EncodeToString(sum[:8])+".json")
+}
+
+// Load returns the cached names, or ok=false when there are none.
+func (s Store) Load(configPath, profile string) (Entry, bool, error) {
+	raw, err := os.ReadFile(s.Path(configPath, profile))
+	if errors.Is(err, os.ErrNotExist) {
+		return Entry{}, false, nil
+	}
+	if err != nil {
+		return Entry{}, false, fmt.Errorf("read name cache: %w", err)
+	}
+	var entry Entry
+	if err := json.Unmarshal(raw, &entry); err != nil {
+		return Entry{}, false, fmt.Errorf("decode name cache: %w", err)
+	}
+	if entry.Config != configPath || entry.Profile != profile {
+		return Entry{}, false, nil
+	}
+	return entry, true, nil
+}
+
+func (s Store) Save(entry Entry) error {
+	raw, _ := json.Marshal(entry) // strings and a time only; always encodes
+	if err := fsx.AtomicWriteFile(s.Path(entry.Config, entry.Profile), append(raw, '\n'), 0o600, true); err != nil {
+		return fmt.Errorf("write name cache: %w", err)
+	}
+	return nil
+}
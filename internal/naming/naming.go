@@ -0,0 +1,60 @@
+// Package naming checks secret names against the convention configured in
+// the "naming" section of .scw.json.
+package naming
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/bsmartlabs/dev-vault/internal/config"
+)
+
+var kebabCase = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+type Rules struct {
+	Patterns       []*regexp.Regexp
+	RequiredSuffix string
+	KebabCase      bool
+}
+
+// Compile turns a naming section into rules. A nil section yields the
+// default convention: kebab-case names.
+func Compile(n *config.Naming) (Rules, error) {
+	if n == nil {
+		return Rules{KebabCase: true}, nil
+	}
+	rules := Rules{RequiredSuffix: n.RequiredSuffix, KebabCase: n.KebabCase}
+	for _, pattern := range n.Patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return Rules{}, fmt.Errorf("naming patterns: %w", err)
+		}
+		rules.Patterns = append(rules.Patterns, re)
+	}
+	return rules, nil
+}
+
+// Check returns one problem per rule the name breaks, in a stable order.
+func (r Rules) Check(name string) []string {
+	var problems []string
+	if r.KebabCase && !kebabCase.MatchString(name) {
+		problems = append(problems, "not kebab-case")
+	}
+	if r.RequiredSuffix != "" && !strings.HasSuffix(name, r.RequiredSuffix) {
+		problems = append(problems, fmt.Sprintf("missing suffix %q", r.RequiredSuffix))
+	}
+	if len(r.Patterns) > 0 && !r.matchesAny(name) {
+		problems = append(problems, "matches no naming pattern")
+	}
+	return problems
+}
+
+func (r Rules) matchesAny(name string) bool {
+	for _, re := range r.Patterns {
+		if re.MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
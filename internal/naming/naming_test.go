@@ -0,0 +1,41 @@
+package naming
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bsmartlabs/dev-vault/internal/config"
+)
+
+func TestCompile(t *testing.T) {
+	rules, err := Compile(nil)
+	if err != nil || !rules.KebabCase || rules.RequiredSuffix != "" || len(rules.Patterns) != 0 {
+		t.Fatalf("unexpected default rules: %#v %v", rules, err)
+	}
+	rules, err = Compile(&config.Naming{Patterns: []string{"^svc-"}, RequiredSuffix: "-env-dev"})
+	if err != nil || rules.KebabCase || rules.RequiredSuffix != "-env-dev" || len(rules.Patterns) != 1 {
+		t.Fatalf("unexpected rules: %#v %v", rules, err)
+	}
+	if _, err := Compile(&config.Naming{Patterns: []string{"("}}); err == nil || !strings.Contains(err.Error(), "naming patterns") {
+		t.Fatalf("expected pattern error, got %v", err)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	rules, err := Compile(&config.Naming{Patterns: []string{"^svc-", "^lib-"}, RequiredSuffix: "-env-dev", KebabCase: true})
+	if err != nil {
+		t.Fatalf("Compile: %v", err)
+	}
+	cases := map[string]string{
+		"svc-billing-env-dev": "",
+		"lib-auth-env-dev":    "",
+		"Svc_Billing-env-dev": "not kebab-case; matches no naming pattern",
+		"svc-billing-dev":     `missing suffix "-env-dev"`,
+		"svc--double-env-dev": "not kebab-case",
+	}
+	for name, want := range cases {
+		if got := strings.Join(rules.Check(name), "; "); got != want {
+			t.Fatalf("Check(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
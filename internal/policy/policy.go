@@ -0,0 +1,165 @@
+// Package policy evaluates the rules of a config.Policy against secret
+// metadata and payload shape. Rules only ever look at sizes, key names, tags,
+// and timestamps; violation details never include secret values.
+package policy
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bsmartlabs/dev-vault/internal/config"
+)
+
+const (
+	RuleMaxSecretAge    = "max_secret_age"
+	RuleForbiddenKey    = "forbidden_key_patterns"
+	RuleRequiredTags    = "required_tags"
+	RuleMaxPayloadBytes = "max_payload_bytes"
+)
+
+type Violation struct {
+	Secret string `json:"secret"`
+	Rule   string `json:"rule"`
+	Detail string `json:"detail"`
+}
+
+func (v Violation) String() string {
+	if v.Secret == "" {
+		return fmt.Sprintf("%s: %s", v.Rule, v.Detail)
+	}
+	return fmt.Sprintf("%s: %s: %s", v.Secret, v.Rule, v.Detail)
+}
+
+type Rules struct {
+	Enforce         bool
+	MaxSecretAge    time.Duration
+	ForbiddenKeys   []*regexp.Regexp
+	RequiredTags    []string
+	MaxPayloadBytes int
+	Rego            *RegoHook
+}
+
+// Compile turns a policy into evaluable rules. A nil policy yields empty rules
+// that never report violations.
+func Compile(p *config.Policy) (Rules, error) {
+	if p == nil {
+		return Rules{}, nil
+	}
+	rules := Rules{
+		Enforce:         p.Level == config.PolicyLevelEnforce,
+		RequiredTags:    p.RequiredTags,
+		MaxPayloadBytes: p.MaxPayloadBytes,
+	}
+	if p.Rego != nil {
+		rules.Rego = &RegoHook{Bundle: p.Rego.Bundle, Query: p.Rego.Query}
+	}
+	if p.MaxSecretAge != "" {
+		age, err := config.ParseAge(p.MaxSecretAge)
+		if err != nil {
+			return Rules{}, fmt.Errorf("policy max_secret_age: %w", err)
+		}
+		rules.MaxSecretAge = age
+	}
+	for _, pattern := range p.ForbiddenKeyPatterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return Rules{}, fmt.Errorf("policy forbidden_key_patterns: %w", err)
+		}
+		rules.ForbiddenKeys = append(rules.ForbiddenKeys, re)
+	}
+	return rules, nil
+}
+
+// CheckPayload checks the size of a payload and, when it is a JSON object
+// (key_value secrets and converted dotenv files), its top-level key names.
+func (r Rules) CheckPayload(name string, payload []byte) []Violation {
+	var violations []Violation
+	if r.MaxPayloadBytes > 0 && len(payload) > r.MaxPayloadBytes {
+		violations = append(violations, Violation{
+			Secret: name,
+			Rule:   RuleMaxPayloadBytes,
+			Detail: fmt.Sprintf("payload is %d bytes, limit is %d", len(payload), r.MaxPayloadBytes),
+		})
+	}
+	for _, key := range PayloadKeys(payload) {
+		for _, re := range r.ForbiddenKeys {
+			if re.MatchString(key) {
+				violations = append(violations, Violation{
+					Secret: name,
+					Rule:   RuleForbiddenKey,
+					Detail: fmt.Sprintf("key %s matches forbidden pattern %q", key, re.String()),
+				})
+				break
+			}
+		}
+	}
+	return violations
+}
+
+// Blocking reports whether a violation must stop the operation: everything
+// does under level=enforce, and rego denials always do.
+func (r Rules) Blocking(v Violation) bool {
+	return r.Enforce || v.Rule == RuleRego
+}
+
+// PayloadKeys returns the sorted top-level keys of a JSON object payload, or
+// nil for any other payload.
+func PayloadKeys(payload []byte) []string {
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(payload, &object); err != nil {
+		return nil
+	}
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// CheckTags accepts a required tag either verbatim or as a "tag:value" /
+// "tag=value" prefix, so "owner" is satisfied by "owner:web-team".
+func (r Rules) CheckTags(name string, tags []string) []Violation {
+	var missing []string
+	for _, required := range r.RequiredTags {
+		if !hasTag(tags, required) {
+			missing = append(missing, required)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	return []Violation{{
+		Secret: name,
+		Rule:   RuleRequiredTags,
+		Detail: "missing tags: " + strings.Join(missing, ", "),
+	}}
+}
+
+func (r Rules) CheckAge(name string, updatedAt, now time.Time) []Violation {
+	if r.MaxSecretAge == 0 || updatedAt.IsZero() {
+		return nil
+	}
+	age := now.Sub(updatedAt)
+	if age <= r.MaxSecretAge {
+		return nil
+	}
+	return []Violation{{
+		Secret: name,
+		Rule:   RuleMaxSecretAge,
+		Detail: fmt.Sprintf("last updated %dd ago, limit is %dd", int(age.Hours()/24), int(r.MaxSecretAge.Hours()/24)),
+	}}
+}
+
+func hasTag(tags []string, required string) bool {
+	for _, tag := range tags {
+		if tag == required || strings.HasPrefix(tag, required+":") || strings.HasPrefix(tag, required+"=") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,91 @@
+package policy
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bsmartlabs/dev-vault/internal/config"
+)
+
+func TestCompile(t *testing.T) {
+	rules, err := Compile(nil)
+	if err != nil || rules.Enforce || len(rules.CheckPayload("a-dev", []byte(`{"PROD_KEY":"x"}`))) != 0 {
+		t.Fatalf("expected empty rules, got %#v, %v", rules, err)
+	}
+
+	rules, err = Compile(&config.Policy{
+		Level:                config.PolicyLevelEnforce,
+		MaxSecretAge:         "30d",
+		ForbiddenKeyPatterns: []string{"^PROD_"},
+		RequiredTags:         []string{"owner"},
+		MaxPayloadBytes:      64,
+		Rego:                 &config.RegoPolicy{Bundle: "/p", Query: "data.q"},
+	})
+	if err != nil {
+		t.Fatalf("Compile: %v", err)
+	}
+	if !rules.Enforce || rules.MaxSecretAge != 30*24*time.Hour || len(rules.ForbiddenKeys) != 1 || rules.MaxPayloadBytes != 64 || *rules.Rego != (RegoHook{Bundle: "/p", Query: "data.q"}) {
+		t.Fatalf("unexpected rules: %#v", rules)
+	}
+
+	if _, err := Compile(&config.Policy{MaxSecretAge: "soon"}); err == nil {
+		t.Fatal("expected invalid age error")
+	}
+	if _, err := Compile(&config.Policy{ForbiddenKeyPatterns: []string{"("}}); err == nil {
+		t.Fatal("expected invalid pattern error")
+	}
+}
+
+func TestCheckPayload(t *testing.T) {
+	rules, err := Compile(&config.Policy{ForbiddenKeyPatterns: []string{"^PROD_", "SECRET$"}, MaxPayloadBytes: 20})
+	if err != nil {
+		t.Fatalf("Compile: %v", err)
+	}
+
+	got := rules.CheckPayload("a-dev", []byte(`{"PROD_SECRET":"value","OK":"1"}`))
+	if len(got) != 2 || got[0].Rule != RuleMaxPayloadBytes || got[1].Rule != RuleForbiddenKey {
+		t.Fatalf("unexpected violations: %#v", got)
+	}
+	if strings.Contains(got[0].String()+got[1].String(), "value") {
+		t.Fatalf("violation leaked a value: %v", got)
+	}
+	if got[1].String() != `a-dev: forbidden_key_patterns: key PROD_SECRET matches forbidden pattern "^PROD_"` {
+		t.Fatalf("unexpected message: %s", got[1])
+	}
+
+	if got := rules.CheckPayload("raw-dev", []byte("PROD_")); len(got) != 0 {
+		t.Fatalf("expected non-JSON payload to skip key checks, got %#v", got)
+	}
+
+	sizeOnly := Rules{MaxPayloadBytes: 4}
+	if got := sizeOnly.CheckPayload("a-dev", []byte(`{"A":"1"}`)); len(got) != 1 {
+		t.Fatalf("expected size violation, got %#v", got)
+	}
+}
+
+func TestCheckTags(t *testing.T) {
+	rules := Rules{RequiredTags: []string{"owner", "team", "env"}}
+	got := rules.CheckTags("a-dev", []string{"owner:web", "team=core"})
+	if len(got) != 1 || got[0].Detail != "missing tags: env" {
+		t.Fatalf("unexpected violations: %#v", got)
+	}
+	if got := rules.CheckTags("a-dev", []string{"owner", "team", "env"}); got != nil {
+		t.Fatalf("expected no violations, got %#v", got)
+	}
+}
+
+func TestCheckAge(t *testing.T) {
+	now := time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC)
+	rules := Rules{MaxSecretAge: 30 * 24 * time.Hour}
+	got := rules.CheckAge("a-dev", now.Add(-45*24*time.Hour), now)
+	if len(got) != 1 || got[0].Detail != "last updated 45d ago, limit is 30d" {
+		t.Fatalf("unexpected violations: %#v", got)
+	}
+	if got := rules.CheckAge("a-dev", now.Add(-24*time.Hour), now); got != nil {
+		t.Fatalf("expected fresh secret to pass, got %#v", got)
+	}
+	if got := rules.CheckAge("a-dev", time.Time{}, now); got != nil {
+		t.Fatalf("expected unknown age to pass, got %#v", got)
+	}
+}
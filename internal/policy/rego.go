@@ -0,0 +1,126 @@
+package policy
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+const RuleRego = "rego"
+
+// PushInput is the document handed to the rego policy as `input`. It carries
+// names, key names, sizes, and metadata only, never secret values.
+type PushInput struct {
+	Operation string       `json:"operation"`
+	Secrets   []PushSecret `json:"secrets"`
+}
+
+type PushSecret struct {
+	Name      string   `json:"name"`
+	Path      string   `json:"path"`
+	Type      string   `json:"type,omitempty"`
+	Format    string   `json:"format"`
+	File      string   `json:"file"`
+	SizeBytes int      `json:"size_bytes"`
+	Keys      []string `json:"keys"`
+	Exists    bool     `json:"exists"`
+	Tags      []string `json:"tags"`
+}
+
+type RegoHook struct {
+	Bundle string
+	Query  string
+}
+
+type regoDeps struct {
+	lookPath func(string) (string, error)
+	run      func(name string, args []string, stdin []byte) ([]byte, error)
+}
+
+var defaultRegoDeps = regoDeps{
+	lookPath: exec.LookPath,
+	run:      runCommand,
+}
+
+func runCommand(name string, args []string, stdin []byte) ([]byte, error) {
+	cmd := exec.Command(name, args...)
+	cmd.Stdin = bytes.NewReader(stdin)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%w: %s", err, msg)
+		}
+		return nil, err
+	}
+	return out, nil
+}
+
+// CheckPush evaluates the rego hook, if any, through the `opa` binary. Every
+// reason returned by the query becomes a violation that always blocks.
+func (r Rules) CheckPush(input PushInput) ([]Violation, error) {
+	if r.Rego == nil {
+		return nil, nil
+	}
+	reasons, err := evaluateRego(*r.Rego, input, defaultRegoDeps)
+	if err != nil {
+		return nil, err
+	}
+	violations := make([]Violation, 0, len(reasons))
+	for _, reason := range reasons {
+		violations = append(violations, Violation{Rule: RuleRego, Detail: reason})
+	}
+	return violations, nil
+}
+
+func evaluateRego(hook RegoHook, input PushInput, deps regoDeps) ([]string, error) {
+	opa, err := deps.lookPath("opa")
+	if err != nil {
+		return nil, fmt.Errorf("rego policy requires the opa binary on PATH: %w", err)
+	}
+	stdin, _ := json.Marshal(input) // plain strings, ints, and bools always encode
+	out, err := deps.run(opa, []string{"eval", "--format", "json", "--stdin-input", "--data", hook.Bundle, hook.Query}, stdin)
+	if err != nil {
+		return nil, fmt.Errorf("opa eval: %w", err)
+	}
+	return parseRegoReasons(out)
+}
+
+type opaEvalOutput struct {
+	Result []struct {
+		Expressions []struct {
+			Value json.RawMessage `json:"value"`
+		} `json:"expressions"`
+	} `json:"result"`
+}
+
+// parseRegoReasons accepts a deny set of strings; other elements (such as
+// objects with a msg field) are reported as their JSON encoding. An undefined
+// query result means nothing was denied.
+func parseRegoReasons(out []byte) ([]string, error) {
+	var decoded opaEvalOutput
+	if err := json.Unmarshal(out, &decoded); err != nil {
+		return nil, fmt.Errorf("decode opa output: %w", err)
+	}
+	var reasons []string
+	for _, result := range decoded.Result {
+		for _, expr := range result.Expressions {
+			var values []json.RawMessage
+			if err := json.Unmarshal(expr.Value, &values); err != nil {
+				return nil, errors.New("decode opa output: query must evaluate to a set or array of reasons")
+			}
+			for _, value := range values {
+				var reason string
+				if err := json.Unmarshal(value, &reason); err != nil {
+					reason = string(value)
+				}
+				reasons = append(reasons, reason)
+			}
+		}
+	}
+	return reasons, nil
+}
@@ -0,0 +1,104 @@
+package policy
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// installFakeOPA puts an `opa` script on PATH that records its stdin and
+// arguments next to itself and prints the given eval output.
+func installFakeOPA(t *testing.T, output string) string {
+	t.Helper()
+	dir := t.TempDir()
+	script := "#!/bin/sh\ncat > \"$(dirname \"$0\")/input.json\"\necho \"$@\" > \"$(dirname \"$0\")/args.txt\"\ncat <<'OUT'\n" + output + "\nOUT\n"
+	if err := os.WriteFile(filepath.Join(dir, "opa"), []byte(script), 0o755); err != nil {
+		t.Fatalf("write fake opa: %v", err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	return dir
+}
+
+func TestCheckPush(t *testing.T) {
+	if got, err := (Rules{}).CheckPush(PushInput{}); got != nil || err != nil {
+		t.Fatalf("expected no-op without rego hook, got %#v, %v", got, err)
+	}
+
+	dir := installFakeOPA(t, `{"result":[{"expressions":[{"value":["no PROD keys",{"msg":"structured"}]}]}]}`)
+	rules := Rules{Rego: &RegoHook{Bundle: "/policies", Query: "data.devvault.push.deny"}}
+	input := PushInput{Operation: "push", Secrets: []PushSecret{{Name: "app-dev", Keys: []string{"PROD_TOKEN"}, SizeBytes: 3}}}
+	got, err := rules.CheckPush(input)
+	if err != nil {
+		t.Fatalf("CheckPush: %v", err)
+	}
+	want := []Violation{
+		{Rule: RuleRego, Detail: "no PROD keys"},
+		{Rule: RuleRego, Detail: `{"msg":"structured"}`},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected violations: %#v", got)
+	}
+	if got[0].String() != "rego: no PROD keys" || !rules.Blocking(got[0]) || rules.Blocking(Violation{Rule: RuleRequiredTags}) {
+		t.Fatalf("unexpected rendering/blocking for %#v", got[0])
+	}
+
+	args, err := os.ReadFile(filepath.Join(dir, "args.txt"))
+	if err != nil {
+		t.Fatalf("read args: %v", err)
+	}
+	if strings.TrimSpace(string(args)) != "eval --format json --stdin-input --data /policies data.devvault.push.deny" {
+		t.Fatalf("unexpected opa args: %q", args)
+	}
+	var sent PushInput
+	raw, err := os.ReadFile(filepath.Join(dir, "input.json"))
+	if err != nil {
+		t.Fatalf("read input: %v", err)
+	}
+	if err := json.Unmarshal(raw, &sent); err != nil || !reflect.DeepEqual(sent, input) {
+		t.Fatalf("unexpected opa input %s: %v", raw, err)
+	}
+
+	t.Setenv("PATH", t.TempDir())
+	if _, err := rules.CheckPush(input); err == nil || !strings.Contains(err.Error(), "requires the opa binary") {
+		t.Fatalf("expected missing opa error, got %v", err)
+	}
+}
+
+func TestEvaluateRego_Errors(t *testing.T) {
+	deps := regoDeps{
+		lookPath: func(string) (string, error) { return "opa", nil },
+		run:      func(string, []string, []byte) ([]byte, error) { return nil, errors.New("exit status 1") },
+	}
+	if _, err := evaluateRego(RegoHook{}, PushInput{}, deps); err == nil || !strings.Contains(err.Error(), "opa eval") {
+		t.Fatalf("expected eval error, got %v", err)
+	}
+}
+
+func TestParseRegoReasons(t *testing.T) {
+	if got, err := parseRegoReasons([]byte(`{}`)); err != nil || got != nil {
+		t.Fatalf("expected undefined result to deny nothing, got %#v, %v", got, err)
+	}
+	if _, err := parseRegoReasons([]byte(`nope`)); err == nil {
+		t.Fatal("expected decode error")
+	}
+	if _, err := parseRegoReasons([]byte(`{"result":[{"expressions":[{"value":true}]}]}`)); err == nil || !strings.Contains(err.Error(), "set or array") {
+		t.Fatalf("expected shape error, got %v", err)
+	}
+}
+
+func TestRunCommand(t *testing.T) {
+	out, err := runCommand("sh", []string{"-c", "cat"}, []byte("hello"))
+	if err != nil || string(out) != "hello" {
+		t.Fatalf("expected stdin echo, got %q, %v", out, err)
+	}
+	if _, err := runCommand("sh", []string{"-c", "echo oops >&2; exit 3"}, nil); err == nil || !strings.Contains(err.Error(), "oops") {
+		t.Fatalf("expected stderr in error, got %v", err)
+	}
+	if _, err := runCommand("sh", []string{"-c", "exit 3"}, nil); err == nil || err.Error() != "exit status 3" {
+		t.Fatalf("expected bare exit error, got %v", err)
+	}
+}
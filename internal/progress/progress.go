@@ -0,0 +1,85 @@
+// Package progress records which targets of a pull or push batch have
+// completed, so an interrupted or failed batch can be resumed. Records hold
+// secret names and the config path only, never payloads.
+package progress
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/bsmartlabs/dev-vault/internal/fsx"
+)
+
+const dirName = "progress"
+
+type Record struct {
+	Operation string   `json:"operation"`
+	Config    string   `json:"config"`
+	Completed []string `json:"completed"`
+}
+
+// IsCompleted reports whether name finished in the recorded batch.
+func (r Record) IsCompleted(name string) bool {
+	for _, done := range r.Completed {
+		if done == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Store keeps one record per operation and config file under a per-user
+// directory.
+type Store struct {
+	dir string
+}
+
+func NewStore(dir string) Store {
+	return Store{dir: filepath.Join(dir, dirName)}
+}
+
+// Path names the record file after a hash of the config path, so batches for
+// different repositories never share progress.
+func (s Store) Path(operation, configPath string) string {
+	sum := sha256.Sum256([]byte(configPath))
+	return filepath.Join(s.dir, operation+"-"+hex.EncodeToString(sum[:8])+".json")
+}
+
+// Load returns the recorded batch, or ok=false when there is none.
+func (s Store) Load(operation, configPath string) (Record, bool, error) {
+	raw, err := os.ReadFile(s.Path(operation, configPath))
+	if errors.Is(err, os.ErrNotExist) {
+		return Record{}, false, nil
+	}
+	if err != nil {
+		return Record{}, false, fmt.Errorf("read progress: %w", err)
+	}
+	var record Record
+	if err := json.Unmarshal(raw, &record); err != nil {
+		return Record{}, false, fmt.Errorf("decode progress: %w", err)
+	}
+	if record.Operation != operation || record.Config != configPath {
+		return Record{}, false, nil
+	}
+	return record, true, nil
+}
+
+func (s Store) Save(record Record) error {
+	raw, _ := json.Marshal(record) // strings only; always encodes
+	if err := fsx.AtomicWriteFile(s.Path(record.Operation, record.Config), append(raw, '\n'), 0o600, true); err != nil {
+		return fmt.Errorf("write progress: %w", err)
+	}
+	return nil
+}
+
+func (s Store) Clear(operation, configPath string) error {
+	if err := os.Remove(s.Path(operation, configPath)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("clear progress: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package progress
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestStoreRoundTrip(t *testing.T) {
+	store := NewStore(t.TempDir())
+	if _, ok, err := store.Load("push", "/repo/.scw.json"); ok || err != nil {
+		t.Fatalf("expected no record, got ok=%v err=%v", ok, err)
+	}
+
+	record := Record{Operation: "push", Config: "/repo/.scw.json", Completed: []string{"a-dev"}}
+	if err := store.Save(record); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	got, ok, err := store.Load("push", "/repo/.scw.json")
+	if err != nil || !ok || !got.IsCompleted("a-dev") || got.IsCompleted("b-dev") {
+		t.Fatalf("unexpected record: %#v ok=%v err=%v", got, ok, err)
+	}
+	if _, ok, _ := store.Load("pull", "/repo/.scw.json"); ok {
+		t.Fatal("expected pull to have no record")
+	}
+	if _, ok, _ := store.Load("push", "/other/.scw.json"); ok {
+		t.Fatal("expected another config to have no record")
+	}
+
+	if err := store.Clear("push", "/repo/.scw.json"); err != nil {
+		t.Fatalf("Clear: %v", err)
+	}
+	if err := store.Clear("push", "/repo/.scw.json"); err != nil {
+		t.Fatalf("Clear of a missing record: %v", err)
+	}
+}
+
+func TestStoreLoadRejectsForeignRecord(t *testing.T) {
+	store := NewStore(t.TempDir())
+	if err := store.Save(Record{Operation: "push", Config: "/a"}); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	// A record copied under another key must not be trusted.
+	if err := os.Rename(store.Path("push", "/a"), store.Path("push", "/b")); err != nil {
+		t.Fatalf("rename: %v", err)
+	}
+	if _, ok, err := store.Load("push", "/b"); ok || err != nil {
+		t.Fatalf("expected foreign record to be ignored, got ok=%v err=%v", ok, err)
+	}
+}
+
+func TestStoreErrors(t *testing.T) {
+	dir := t.TempDir()
+	store := NewStore(dir)
+	path := store.Path("push", "/a")
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+
+	if err := os.WriteFile(path, []byte("{"), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if _, _, err := store.Load("push", "/a"); err == nil || !strings.Contains(err.Error(), "decode progress") {
+		t.Fatalf("expected decode error, got %v", err)
+	}
+
+	if err := os.Remove(path); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(path, "child"), 0o700); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if _, _, err := store.Load("push", "/a"); err == nil || !strings.Contains(err.Error(), "read progress") {
+		t.Fatalf("expected read error, got %v", err)
+	}
+	if err := store.Clear("push", "/a"); err == nil || !strings.Contains(err.Error(), "clear progress") {
+		t.Fatalf("expected clear error, got %v", err)
+	}
+
+	blocked := NewStore(filepath.Join(dir, "file"))
+	if err := os.WriteFile(filepath.Join(dir, "file"), []byte("x"), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := blocked.Save(Record{Operation: "push", Config: "/a"}); err == nil || !strings.Contains(err.Error(), "write progress") {
+		t.Fatalf("expected write error, got %v", err)
+	}
+}
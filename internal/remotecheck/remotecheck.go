@@ -0,0 +1,73 @@
+// Package remotecheck keeps the outcome of the last remote check of a config
+// file, made by status or pull --watch, so prompt can report provider
+// reachability and drift without contacting the provider. Entries hold
+// secret names and a time only, never payloads or digests.
+package remotecheck
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/bsmartlabs/dev-vault/internal/fsx"
+)
+
+const dirName = "remote"
+
+type Entry struct {
+	Config    string    `json:"config"`
+	CheckedAt time.Time `json:"checked_at"`
+	// Reachable tells whether the provider answered the check.
+	Reachable bool `json:"reachable"`
+	// Drifted lists the secrets whose local files differed from the remote
+	// secret, in content or revision, when checked.
+	Drifted []string `json:"drifted,omitempty"`
+}
+
+// Store keeps one entry per config file under a per-user directory.
+type Store struct {
+	dir string
+}
+
+func NewStore(dir string) Store {
+	return Store{dir: filepath.Join(dir, dirName)}
+}
+
+// Path names the entry file after a hash of the config path, so repositories
+// never share checks.
+func (s Store) Path(configPath string) string {
+	sum := sha256.Sum256([]byte(configPath))
+	return filepath.Join(s.dir, hex.EncodeToString(sum[:8])+".json")
+}
+
+// Load returns the last check of configPath, or ok=false when there is none.
+func (s Store) Load(configPath string) (Entry, bool, error) {
+	raw, err := os.ReadFile(s.Path(configPath))
+	if errors.Is(err, os.ErrNotExist) {
+		return Entry{}, false, nil
+	}
+	if err != nil {
+		return Entry{}, false, fmt.Errorf("read remote check: %w", err)
+	}
+	var entry Entry
+	if err := json.Unmarshal(raw, &entry); err != nil {
+		return Entry{}, false, fmt.Errorf("decode remote check: %w", err)
+	}
+	if entry.Config != configPath {
+		return Entry{}, false, nil
+	}
+	return entry, true, nil
+}
+
+func (s Store) Save(entry Entry) error {
+	raw, _ := json.Marshal(entry) // strings, a bool, and a time only; always encodes
+	if err := fsx.AtomicWriteFile(s.Path(entry.Config), append(raw, '\n'), 0o600, true); err != nil {
+		return fmt.Errorf("write remote check: %w", err)
+	}
+	return nil
+}
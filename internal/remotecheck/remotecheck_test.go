@@ -0,0 +1,75 @@
+package remotecheck
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStoreRoundTrip(t *testing.T) {
+	store := NewStore(t.TempDir())
+	if _, ok, err := store.Load("/repo/.scw.json"); ok || err != nil {
+		t.Fatalf("expected no entry, got ok=%v err=%v", ok, err)
+	}
+
+	checked := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	if err := store.Save(Entry{Config: "/repo/.scw.json", CheckedAt: checked, Reachable: true, Drifted: []string{"a-dev", "b-dev"}}); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	got, ok, err := store.Load("/repo/.scw.json")
+	if err != nil || !ok || !got.CheckedAt.Equal(checked) || !got.Reachable || strings.Join(got.Drifted, ",") != "a-dev,b-dev" {
+		t.Fatalf("unexpected entry: %#v ok=%v err=%v", got, ok, err)
+	}
+	if _, ok, _ := store.Load("/other/.scw.json"); ok {
+		t.Fatal("expected another config to have no entry")
+	}
+}
+
+func TestStoreLoadRejectsForeignEntry(t *testing.T) {
+	store := NewStore(t.TempDir())
+	if err := store.Save(Entry{Config: "/a"}); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	// An entry copied under another key must not be trusted.
+	if err := os.Rename(store.Path("/a"), store.Path("/b")); err != nil {
+		t.Fatalf("rename: %v", err)
+	}
+	if _, ok, err := store.Load("/b"); ok || err != nil {
+		t.Fatalf("expected foreign entry to be ignored, got ok=%v err=%v", ok, err)
+	}
+}
+
+func TestStoreErrors(t *testing.T) {
+	dir := t.TempDir()
+	store := NewStore(dir)
+	path := store.Path("/a")
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte("{"), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if _, _, err := store.Load("/a"); err == nil || !strings.Contains(err.Error(), "decode remote check") {
+		t.Fatalf("expected decode error, got %v", err)
+	}
+
+	if err := os.Remove(path); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(path, "child"), 0o700); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if _, _, err := store.Load("/a"); err == nil || !strings.Contains(err.Error(), "read remote check") {
+		t.Fatalf("expected read error, got %v", err)
+	}
+
+	blocked := NewStore(filepath.Join(dir, "file"))
+	if err := os.WriteFile(filepath.Join(dir, "file"), []byte("x"), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := blocked.Save(Entry{Config: "/a"}); err == nil || !strings.Contains(err.Error(), "write remote check") {
+		t.Fatalf("expected write error, got %v", err)
+	}
+}
@@ -0,0 +1,194 @@
+// Package report renders the secret inventory as markdown or as a single
+// self-contained HTML page (inline CSS, no external assets).
+package report
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+)
+
+type Format string
+
+const (
+	FormatMarkdown Format = "markdown"
+	FormatHTML     Format = "html"
+)
+
+func ParseFormat(value string) (Format, error) {
+	switch Format(value) {
+	case FormatMarkdown, FormatHTML:
+		return Format(value), nil
+	default:
+		return "", fmt.Errorf("invalid report format %q (expected markdown or html)", value)
+	}
+}
+
+type Summary struct {
+	Mapped   int
+	Remote   int
+	Local    int
+	InSync   int
+	Unmapped int
+}
+
+type Row struct {
+	Name        string
+	File        string
+	Owner       string
+	Remote      string
+	Local       string
+	Drift       string
+	LastUpdated string
+}
+
+type UnmappedRow struct {
+	Name string
+	Path string
+	Type string
+}
+
+type ViolationRow struct {
+	Secret string
+	Rule   string
+	Detail string
+}
+
+type Document struct {
+	ProjectID   string
+	Region      string
+	GeneratedAt string
+	Summary     Summary
+	Rows        []Row
+	Unmapped    []UnmappedRow
+	PolicyLevel string
+	Violations  []ViolationRow
+	Notes       []string
+}
+
+func Render(w io.Writer, format Format, doc Document) error {
+	if format == FormatHTML {
+		return htmlTemplate.Execute(w, doc)
+	}
+	return renderMarkdown(w, doc)
+}
+
+func renderMarkdown(w io.Writer, doc Document) error {
+	var b strings.Builder
+	b.WriteString("# dev-vault secret report\n\n")
+	fmt.Fprintf(&b, "- Project: `%s`\n", doc.ProjectID)
+	fmt.Fprintf(&b, "- Region: `%s`\n", doc.Region)
+	fmt.Fprintf(&b, "- Generated: %s\n\n", doc.GeneratedAt)
+
+	b.WriteString("## Summary\n\n")
+	b.WriteString("| Mapped | Remote present | Local present | In sync | Unmapped remote |\n")
+	b.WriteString("| --- | --- | --- | --- | --- |\n")
+	fmt.Fprintf(&b, "| %d | %d | %d | %d | %d |\n\n", doc.Summary.Mapped, doc.Summary.Remote, doc.Summary.Local, doc.Summary.InSync, doc.Summary.Unmapped)
+
+	b.WriteString("## Mapping coverage\n\n")
+	if len(doc.Rows) == 0 {
+		b.WriteString("No mapping entries.\n\n")
+	} else {
+		b.WriteString("| Secret | File | Owner | Remote | Local | Drift | Last updated |\n")
+		b.WriteString("| --- | --- | --- | --- | --- | --- | --- |\n")
+		for _, row := range doc.Rows {
+			fmt.Fprintf(&b, "| %s | %s | %s | %s | %s | %s | %s |\n",
+				markdownCell(row.Name), markdownCell(row.File), markdownCell(row.Owner), row.Remote, row.Local, row.Drift, row.LastUpdated)
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("## Unmapped remote secrets\n\n")
+	if len(doc.Unmapped) == 0 {
+		b.WriteString("None.\n")
+	} else {
+		b.WriteString("| Secret | Path | Type |\n")
+		b.WriteString("| --- | --- | --- |\n")
+		for _, row := range doc.Unmapped {
+			fmt.Fprintf(&b, "| %s | %s | %s |\n", markdownCell(row.Name), markdownCell(row.Path), markdownCell(row.Type))
+		}
+	}
+
+	b.WriteString("\n## Policy violations\n\n")
+	switch {
+	case doc.PolicyLevel == "":
+		b.WriteString("No policy configured.\n")
+	case len(doc.Violations) == 0:
+		fmt.Fprintf(&b, "None (level: %s).\n", doc.PolicyLevel)
+	default:
+		fmt.Fprintf(&b, "Level: %s\n\n", doc.PolicyLevel)
+		b.WriteString("| Secret | Rule | Detail |\n")
+		b.WriteString("| --- | --- | --- |\n")
+		for _, row := range doc.Violations {
+			fmt.Fprintf(&b, "| %s | %s | %s |\n", markdownCell(row.Secret), row.Rule, markdownCell(row.Detail))
+		}
+	}
+
+	if len(doc.Notes) > 0 {
+		b.WriteString("\n## Notes\n\n")
+		for _, note := range doc.Notes {
+			fmt.Fprintf(&b, "- %s\n", note)
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func markdownCell(value string) string {
+	return strings.ReplaceAll(value, "|", `\|`)
+}
+
+var htmlTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>dev-vault secret report</title>
+<style>
+body { font-family: system-ui, sans-serif; margin: 2rem; color: #1f2328; }
+table { border-collapse: collapse; margin-bottom: 1.5rem; }
+th, td { border: 1px solid #d0d7de; padding: 0.3rem 0.6rem; text-align: left; }
+th { background: #f6f8fa; }
+code { font-family: ui-monospace, monospace; }
+</style>
+</head>
+<body>
+<h1>dev-vault secret report</h1>
+<ul>
+<li>Project: <code>{{.ProjectID}}</code></li>
+<li>Region: <code>{{.Region}}</code></li>
+<li>Generated: {{.GeneratedAt}}</li>
+</ul>
+<h2>Summary</h2>
+<table>
+<tr><th>Mapped</th><th>Remote present</th><th>Local present</th><th>In sync</th><th>Unmapped remote</th></tr>
+<tr><td>{{.Summary.Mapped}}</td><td>{{.Summary.Remote}}</td><td>{{.Summary.Local}}</td><td>{{.Summary.InSync}}</td><td>{{.Summary.Unmapped}}</td></tr>
+</table>
+<h2>Mapping coverage</h2>
+{{if .Rows}}<table>
+<tr><th>Secret</th><th>File</th><th>Owner</th><th>Remote</th><th>Local</th><th>Drift</th><th>Last updated</th></tr>
+{{range .Rows}}<tr><td>{{.Name}}</td><td>{{.File}}</td><td>{{.Owner}}</td><td>{{.Remote}}</td><td>{{.Local}}</td><td>{{.Drift}}</td><td>{{.LastUpdated}}</td></tr>
+{{end}}</table>
+{{else}}<p>No mapping entries.</p>
+{{end}}<h2>Unmapped remote secrets</h2>
+{{if .Unmapped}}<table>
+<tr><th>Secret</th><th>Path</th><th>Type</th></tr>
+{{range .Unmapped}}<tr><td>{{.Name}}</td><td>{{.Path}}</td><td>{{.Type}}</td></tr>
+{{end}}</table>
+{{else}}<p>None.</p>
+{{end}}<h2>Policy violations</h2>
+{{if not .PolicyLevel}}<p>No policy configured.</p>
+{{else if not .Violations}}<p>None (level: {{.PolicyLevel}}).</p>
+{{else}}<p>Level: {{.PolicyLevel}}</p>
+<table>
+<tr><th>Secret</th><th>Rule</th><th>Detail</th></tr>
+{{range .Violations}}<tr><td>{{.Secret}}</td><td>{{.Rule}}</td><td>{{.Detail}}</td></tr>
+{{end}}</table>
+{{end}}{{if .Notes}}<h2>Notes</h2>
+<ul>
+{{range .Notes}}<li>{{.}}</li>
+{{end}}</ul>
+{{end}}</body>
+</html>
+`))
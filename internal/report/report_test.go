@@ -0,0 +1,123 @@
+package report
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) { return 0, errors.New("write failed") }
+
+func sampleDocument() Document {
+	return Document{
+		ProjectID:   "proj",
+		Region:      "fr-par",
+		GeneratedAt: "2024-03-01T00:00:00Z",
+		Summary:     Summary{Mapped: 1, Remote: 1, Local: 1, InSync: 1, Unmapped: 1},
+		Rows: []Row{
+			{Name: "a-dev", File: "a|b.env", Owner: "web|core", Remote: "present", Local: "present", Drift: "in sync", LastUpdated: "3d"},
+		},
+		Unmapped:    []UnmappedRow{{Name: "orphan-dev", Path: "/", Type: "opaque"}},
+		PolicyLevel: "warn",
+		Violations:  []ViolationRow{{Secret: "a-dev", Rule: "required_tags", Detail: "missing tags: owner"}},
+		Notes:       []string{"shared with the security team"},
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	for _, value := range []string{"markdown", "html"} {
+		if got, err := ParseFormat(value); err != nil || string(got) != value {
+			t.Fatalf("ParseFormat(%q) = %q, %v", value, got, err)
+		}
+	}
+	if _, err := ParseFormat("pdf"); err == nil {
+		t.Fatal("expected invalid format error")
+	}
+}
+
+func TestRenderMarkdown(t *testing.T) {
+	var out bytes.Buffer
+	if err := Render(&out, FormatMarkdown, sampleDocument()); err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	got := out.String()
+	for _, want := range []string{
+		"# dev-vault secret report",
+		"- Project: `proj`",
+		"| 1 | 1 | 1 | 1 | 1 |",
+		`| a-dev | a\|b.env | web\|core | present | present | in sync | 3d |`,
+		"| orphan-dev | / | opaque |",
+		"Level: warn",
+		"| a-dev | required_tags | missing tags: owner |",
+		"## Notes\n\n- shared with the security team\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("missing %q in:\n%s", want, got)
+		}
+	}
+
+	out.Reset()
+	if err := Render(&out, FormatMarkdown, Document{}); err != nil {
+		t.Fatalf("Render empty: %v", err)
+	}
+	got = out.String()
+	if !strings.Contains(got, "No mapping entries.") || !strings.Contains(got, "None.") || !strings.Contains(got, "No policy configured.") || strings.Contains(got, "## Notes") {
+		t.Fatalf("unexpected empty markdown:\n%s", got)
+	}
+
+	out.Reset()
+	if err := Render(&out, FormatMarkdown, Document{PolicyLevel: "enforce"}); err != nil {
+		t.Fatalf("Render clean policy: %v", err)
+	}
+	if !strings.Contains(out.String(), "None (level: enforce).") {
+		t.Fatalf("unexpected clean policy markdown:\n%s", out.String())
+	}
+
+	if err := Render(failingWriter{}, FormatMarkdown, sampleDocument()); err == nil {
+		t.Fatal("expected write error")
+	}
+}
+
+func TestRenderHTML(t *testing.T) {
+	doc := sampleDocument()
+	doc.Rows[0].File = "<script>.env"
+	var out bytes.Buffer
+	if err := Render(&out, FormatHTML, doc); err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	got := out.String()
+	for _, want := range []string{
+		"<!DOCTYPE html>",
+		"<style>",
+		"<td>a-dev</td><td>&lt;script&gt;.env</td><td>web|core</td>",
+		"<td>orphan-dev</td>",
+		"<td>a-dev</td><td>required_tags</td><td>missing tags: owner</td>",
+		"<li>shared with the security team</li>",
+	} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("missing %q in:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "<script>") || strings.Contains(got, "http") {
+		t.Fatalf("expected escaped, self-contained html:\n%s", got)
+	}
+
+	out.Reset()
+	if err := Render(&out, FormatHTML, Document{}); err != nil {
+		t.Fatalf("Render empty: %v", err)
+	}
+	if !strings.Contains(out.String(), "<p>No mapping entries.</p>") || !strings.Contains(out.String(), "<p>No policy configured.</p>") {
+		t.Fatalf("unexpected empty html:\n%s", out.String())
+	}
+
+	out.Reset()
+	if err := Render(&out, FormatHTML, Document{PolicyLevel: "warn"}); err != nil {
+		t.Fatalf("Render clean policy: %v", err)
+	}
+	if !strings.Contains(out.String(), "<p>None (level: warn).</p>") {
+		t.Fatalf("unexpected clean policy html:\n%s", out.String())
+	}
+}
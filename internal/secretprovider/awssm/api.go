@@ -0,0 +1,601 @@
+// Package awssm implements secretprovider.SecretAPI on AWS Secrets Manager,
+// selected with "provider": "aws" in .scw.json.
+//
+// Secrets Manager has no paths, types or numbered revisions, so they are
+// encoded: a secret at path /team/api named db-dev is stored as
+// "team/api/db-dev", its dev-vault type is kept in the dev-vault:type tag
+// (untagged secrets are opaque), and every version dev-vault writes gets a
+// version ID that starts with dev-vault-rev-<revision>. Versions written by
+// other tools read as revision 0.
+package awssm
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/bsmartlabs/dev-vault/internal/config"
+	"github.com/bsmartlabs/dev-vault/internal/secretprovider"
+)
+
+const (
+	signingService  = "secretsmanager"
+	typeTag         = "dev-vault:type"
+	currentStage    = "AWSCURRENT"
+	revisionPrefix  = "dev-vault-rev-"
+	listPageSize    = 100
+	versionStatusOK = "enabled"
+)
+
+var regionPattern = regexp.MustCompile(`^[a-z0-9-]+$`)
+
+type openDeps struct {
+	getenv      func(string) string
+	userHomeDir func() (string, error)
+	readFile    func(string) ([]byte, error)
+	client      *http.Client
+	now         func() time.Time
+	token       func() string
+}
+
+func defaultOpenDeps() openDeps {
+	return openDeps{
+		getenv:      os.Getenv,
+		userHomeDir: os.UserHomeDir,
+		readFile:    os.ReadFile,
+		client:      &http.Client{Timeout: 30 * time.Second},
+		now:         time.Now,
+		token:       rand.Text,
+	}
+}
+
+func Open(cfg config.Config, profileOverride string) (secretprovider.SecretAPI, error) {
+	return openWithDeps(cfg, profileOverride, defaultOpenDeps())
+}
+
+func openWithDeps(cfg config.Config, profileOverride string, deps openDeps) (*API, error) {
+	profileName := strings.TrimSpace(profileOverride)
+	if profileName == "" {
+		profileName = strings.TrimSpace(cfg.Profile)
+	}
+	if !regionPattern.MatchString(cfg.Region) {
+		return nil, fmt.Errorf("invalid region %q", cfg.Region)
+	}
+
+	endpoint := deps.getenv("AWS_ENDPOINT_URL_SECRETS_MANAGER")
+	if endpoint == "" {
+		endpoint = deps.getenv("AWS_ENDPOINT_URL")
+	}
+	if endpoint == "" {
+		endpoint = "https://secretsmanager." + cfg.Region + ".amazonaws.com"
+	}
+	parsed, err := url.Parse(endpoint)
+	if err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid aws endpoint %q", endpoint)
+	}
+
+	creds, err := resolveCredentials(profileName, deps)
+	if err != nil {
+		return nil, err
+	}
+	return &API{
+		client:   deps.client,
+		endpoint: parsed.String(),
+		region:   cfg.Region,
+		creds:    creds,
+		now:      deps.now,
+		token:    deps.token,
+		types:    make(map[string]secretprovider.SecretType),
+	}, nil
+}
+
+type API struct {
+	client   *http.Client
+	endpoint string
+	region   string
+	creds    credentials
+	now      func() time.Time
+	token    func() string
+
+	mu    sync.Mutex
+	types map[string]secretprovider.SecretType
+}
+
+type tag struct {
+	Key   string `json:"Key"`
+	Value string `json:"Value"`
+}
+
+type secretEntry struct {
+	ARN                string              `json:"ARN"`
+	Name               string              `json:"Name"`
+	Tags               []tag               `json:"Tags"`
+	LastChangedDate    *float64            `json:"LastChangedDate"`
+	VersionIdsToStages map[string][]string `json:"VersionIdsToStages"`
+}
+
+type listSecretsRequest struct {
+	MaxResults int          `json:"MaxResults"`
+	NextToken  string       `json:"NextToken,omitempty"`
+	Filters    []listFilter `json:"Filters,omitempty"`
+}
+
+type listFilter struct {
+	Key    string   `json:"Key"`
+	Values []string `json:"Values"`
+}
+
+type listSecretsResponse struct {
+	SecretList []secretEntry `json:"SecretList"`
+	NextToken  string        `json:"NextToken"`
+}
+
+type secretIDRequest struct {
+	SecretID     string `json:"SecretId"`
+	VersionID    string `json:"VersionId,omitempty"`
+	VersionStage string `json:"VersionStage,omitempty"`
+}
+
+type secretValueResponse struct {
+	ARN          string   `json:"ARN"`
+	VersionID    string   `json:"VersionId"`
+	SecretString *string  `json:"SecretString"`
+	SecretBinary []byte   `json:"SecretBinary"`
+	CreatedDate  *float64 `json:"CreatedDate"`
+}
+
+type listVersionsRequest struct {
+	SecretID          string `json:"SecretId"`
+	IncludeDeprecated bool   `json:"IncludeDeprecated"`
+	MaxResults        int    `json:"MaxResults"`
+	NextToken         string `json:"NextToken,omitempty"`
+}
+
+type listVersionsResponse struct {
+	ARN       string         `json:"ARN"`
+	Versions  []versionEntry `json:"Versions"`
+	NextToken string         `json:"NextToken"`
+}
+
+type versionEntry struct {
+	VersionID     string   `json:"VersionId"`
+	VersionStages []string `json:"VersionStages"`
+	CreatedDate   *float64 `json:"CreatedDate"`
+}
+
+type updateStageRequest struct {
+	SecretID            string `json:"SecretId"`
+	VersionStage        string `json:"VersionStage"`
+	RemoveFromVersionID string `json:"RemoveFromVersionId"`
+}
+
+type createSecretRequest struct {
+	Name string `json:"Name"`
+	Tags []tag  `json:"Tags"`
+}
+
+type putSecretValueRequest struct {
+	SecretID           string `json:"SecretId"`
+	ClientRequestToken string `json:"ClientRequestToken"`
+	SecretString       string `json:"SecretString,omitempty"`
+	SecretBinary       []byte `json:"SecretBinary,omitempty"`
+}
+
+// ListSecrets pages through every secret of the account and region and
+// filters by name, path and type locally: Secrets Manager only filters by
+// name prefix. That filter is sent when a path is given, as the full name of
+// a secret starts with its path.
+func (a *API) ListSecrets(req secretprovider.ListSecretsInput) ([]secretprovider.SecretRecord, error) {
+	var out []secretprovider.SecretRecord
+	page := listSecretsRequest{MaxResults: listPageSize}
+	if req.NamePrefix != "" && req.Path != "" {
+		page.Filters = []listFilter{{Key: "name", Values: []string{awsName(req.Path, req.NamePrefix)}}}
+	}
+	for {
+		var resp listSecretsResponse
+		if err := a.call("ListSecrets", page, &resp); err != nil {
+			return nil, wrapError("list secrets", err)
+		}
+		for _, entry := range resp.SecretList {
+			record := a.record(entry)
+			if (req.Name != "" && record.Name != req.Name) || (req.Path != "" && record.Path != req.Path) || (req.Type != "" && record.Type != req.Type) {
+				continue
+			}
+			out = append(out, record)
+		}
+		if resp.NextToken == "" {
+			return out, nil
+		}
+		page.NextToken = resp.NextToken
+	}
+}
+
+// AccessSecretVersion reads the AWSCURRENT version, or the version numbered
+// by a numeric revision, which it finds by listing the versions first.
+func (a *API) AccessSecretVersion(req secretprovider.AccessSecretVersionInput) (*secretprovider.SecretVersionRecord, error) {
+	get := secretIDRequest{SecretID: req.SecretID, VersionStage: currentStage}
+	if req.Revision != "" && req.Revision != secretprovider.RevisionLatestEnabled {
+		revision, err := strconv.ParseUint(string(req.Revision), 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("access secret version: revision %q is not supported by the aws provider", req.Revision)
+		}
+		versionID, err := a.versionID(req.SecretID, uint32(revision))
+		if err != nil {
+			return nil, err
+		}
+		get = secretIDRequest{SecretID: req.SecretID, VersionID: versionID}
+	}
+	var resp secretValueResponse
+	if err := a.call("GetSecretValue", get, &resp); err != nil {
+		return nil, wrapError("access secret version", err)
+	}
+	secretType, err := a.secretType(resp.ARN)
+	if err != nil {
+		return nil, err
+	}
+	data := resp.SecretBinary
+	if resp.SecretString != nil {
+		data = []byte(*resp.SecretString)
+	}
+	record := &secretprovider.SecretVersionRecord{
+		SecretID: resp.ARN,
+		Revision: versionRevision(resp.VersionID),
+		Data:     data,
+		Type:     secretType,
+	}
+	if resp.CreatedDate != nil {
+		record.CreatedAt = epochTime(*resp.CreatedDate)
+	}
+	return record, nil
+}
+
+// ListSecretVersions lists every version of a secret, including the
+// deprecated ones Secrets Manager keeps without a staging label, which read
+// as disabled.
+func (a *API) ListSecretVersions(req secretprovider.ListSecretVersionsInput) ([]secretprovider.SecretVersionRecord, error) {
+	arn, versions, err := a.listVersions(req.SecretID)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]secretprovider.SecretVersionRecord, 0, len(versions))
+	for _, version := range versions {
+		record := secretprovider.SecretVersionRecord{
+			SecretID: arn,
+			Revision: versionRevision(version.VersionID),
+			Status:   secretprovider.VersionStatusDisabled,
+		}
+		if len(version.VersionStages) > 0 {
+			record.Status = versionStatusOK
+		}
+		if version.CreatedDate != nil {
+			record.CreatedAt = epochTime(*version.CreatedDate)
+		}
+		out = append(out, record)
+	}
+	return out, nil
+}
+
+// listVersions pages through ListSecretVersionIds and returns the secret ARN
+// with every version, deprecated ones included.
+func (a *API) listVersions(secretID string) (string, []versionEntry, error) {
+	var arn string
+	var out []versionEntry
+	page := listVersionsRequest{SecretID: secretID, IncludeDeprecated: true, MaxResults: listPageSize}
+	for {
+		var resp listVersionsResponse
+		if err := a.call("ListSecretVersionIds", page, &resp); err != nil {
+			return "", nil, wrapError("list secret versions", err)
+		}
+		arn = resp.ARN
+		out = append(out, resp.Versions...)
+		if resp.NextToken == "" {
+			return arn, out, nil
+		}
+		page.NextToken = resp.NextToken
+	}
+}
+
+// versionID finds the version dev-vault numbered revision.
+func (a *API) versionID(secretID string, revision uint32) (string, error) {
+	_, versions, err := a.listVersions(secretID)
+	if err != nil {
+		return "", err
+	}
+	for _, version := range versions {
+		if versionRevision(version.VersionID) == revision {
+			return version.VersionID, nil
+		}
+	}
+	return "", fmt.Errorf("access secret version: revision %d not found", revision)
+}
+
+// CreateSecret creates a secret without a value; its first version comes
+// from CreateSecretVersion. The project is ignored: an AWS account and region
+// hold a single namespace.
+func (a *API) CreateSecret(req secretprovider.CreateSecretInput) (*secretprovider.SecretRecord, error) {
+	secretType := req.Type
+	if secretType == "" {
+		secretType = secretprovider.SecretTypeOpaque
+	}
+	var resp secretEntry
+	create := createSecretRequest{
+		Name: awsName(req.Path, req.Name),
+		Tags: []tag{{Key: typeTag, Value: string(secretType)}},
+	}
+	if err := a.call("CreateSecret", create, &resp); err != nil {
+		return nil, wrapError("create secret", err)
+	}
+	resp.Tags = create.Tags
+	record := a.record(resp)
+	return &record, nil
+}
+
+// CreateSecretVersion makes data the AWSCURRENT version, numbered one past
+// the current one. Secrets Manager has no version descriptions, and it always
+// keeps the previous version as AWSPREVIOUS, so Description and
+// DisablePrevious are ignored.
+func (a *API) CreateSecretVersion(req secretprovider.CreateSecretVersionInput) (*secretprovider.SecretVersionRecord, error) {
+	var current secretEntry
+	if err := a.call("DescribeSecret", secretIDRequest{SecretID: req.SecretID}, &current); err != nil {
+		return nil, wrapError("create secret version", err)
+	}
+	var revision uint32
+	for versionID, stages := range current.VersionIdsToStages {
+		for _, stage := range stages {
+			if stage == currentStage {
+				revision = versionRevision(versionID)
+			}
+		}
+	}
+	if revision == math.MaxUint32 {
+		return nil, fmt.Errorf("create secret version: %s has no revision left", req.SecretID)
+	}
+	revision++
+
+	put := putSecretValueRequest{
+		SecretID:           req.SecretID,
+		ClientRequestToken: fmt.Sprintf("%s%010d-%s", revisionPrefix, revision, a.token()),
+	}
+	if utf8.Valid(req.Data) {
+		put.SecretString = string(req.Data)
+	} else {
+		put.SecretBinary = req.Data
+	}
+	var resp secretValueResponse
+	if err := a.call("PutSecretValue", put, &resp); err != nil {
+		return nil, wrapError("create secret version", err)
+	}
+	return &secretprovider.SecretVersionRecord{
+		SecretID: resp.ARN,
+		Revision: revision,
+		Status:   versionStatusOK,
+	}, nil
+}
+
+// DeleteSecret schedules the secret for deletion after the default recovery
+// window of 30 days, during which RestoreSecret brings it back.
+func (a *API) DeleteSecret(req secretprovider.DeleteSecretInput) error {
+	var resp secretEntry
+	if err := a.call("DeleteSecret", secretIDRequest{SecretID: req.SecretID}, &resp); err != nil {
+		return wrapError("delete secret", err)
+	}
+	a.mu.Lock()
+	delete(a.types, req.SecretID)
+	a.mu.Unlock()
+	return nil
+}
+
+// DeleteSecretVersion always fails: Secrets Manager has no call removing one
+// version, which instead ages out as newer ones are written.
+func (a *API) DeleteSecretVersion(req secretprovider.DeleteSecretVersionInput) error {
+	return fmt.Errorf("delete secret version: the aws provider cannot delete one version, they age out as new ones are written: %w", errors.ErrUnsupported)
+}
+
+// DisableSecretVersion removes every staging label from the version, which
+// Secrets Manager then lists as deprecated and drops in time. AWSCURRENT
+// cannot be removed without moving it, so the current version is refused.
+func (a *API) DisableSecretVersion(req secretprovider.DisableSecretVersionInput) error {
+	_, versions, err := a.listVersions(req.SecretID)
+	if err != nil {
+		return fmt.Errorf("disable secret version: %w", err)
+	}
+	for _, version := range versions {
+		if versionRevision(version.VersionID) != req.Revision {
+			continue
+		}
+		for _, stage := range version.VersionStages {
+			if stage == currentStage {
+				return fmt.Errorf("disable secret version: revision %d is %s; push a newer version first", req.Revision, currentStage)
+			}
+		}
+		for _, stage := range version.VersionStages {
+			update := updateStageRequest{SecretID: req.SecretID, VersionStage: stage, RemoveFromVersionID: version.VersionID}
+			if err := a.call("UpdateSecretVersionStage", update, &secretEntry{}); err != nil {
+				return wrapError("disable secret version", err)
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("disable secret version: revision %d not found", req.Revision)
+}
+
+// record converts a Secrets Manager secret and remembers its type for later
+// reads of its versions.
+func (a *API) record(entry secretEntry) secretprovider.SecretRecord {
+	record := secretprovider.SecretRecord{ID: entry.ARN, Path: "/", Type: secretprovider.SecretTypeOpaque}
+	record.Name = entry.Name
+	if dir, name, ok := cutLast(entry.Name, "/"); ok {
+		record.Path, record.Name = "/"+dir, name
+	}
+	for _, t := range entry.Tags {
+		switch {
+		case t.Key == typeTag:
+			record.Type = secretprovider.SecretType(t.Value)
+		case t.Value == "":
+			record.Tags = append(record.Tags, t.Key)
+		default:
+			record.Tags = append(record.Tags, t.Key+"="+t.Value)
+		}
+	}
+	if entry.LastChangedDate != nil {
+		record.UpdatedAt = epochTime(*entry.LastChangedDate)
+	}
+	a.mu.Lock()
+	a.types[record.ID] = record.Type
+	a.mu.Unlock()
+	return record
+}
+
+// secretType returns the type of a secret seen in a listing, or describes it.
+func (a *API) secretType(secretID string) (secretprovider.SecretType, error) {
+	a.mu.Lock()
+	secretType, ok := a.types[secretID]
+	a.mu.Unlock()
+	if ok {
+		return secretType, nil
+	}
+	var entry secretEntry
+	if err := a.call("DescribeSecret", secretIDRequest{SecretID: secretID}, &entry); err != nil {
+		return "", wrapError("describe secret", err)
+	}
+	return a.record(entry).Type, nil
+}
+
+// call sends one signed JSON request of the Secrets Manager API. Error
+// responses are reduced to their type and message, which never contain
+// secret values.
+func (a *API) call(action string, in, out any) error {
+	// Request bodies are plain structs and the endpoint was validated by Open,
+	// so neither step can fail.
+	body, _ := json.Marshal(in)
+	req, _ := http.NewRequest(http.MethodPost, a.endpoint, bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "secretsmanager."+action)
+	setInvocationHeaders(req)
+	signV4(req, body, a.creds, a.region, signingService, a.now())
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return newResponseError(resp, data)
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	return nil
+}
+
+// setInvocationHeaders names dev-vault and the running invocation, which
+// CloudTrail records as the user agent of each call.
+func setInvocationHeaders(req *http.Request) {
+	req.Header.Set("User-Agent", secretprovider.UserAgent())
+	if id := secretprovider.InvocationID(); id != "" {
+		req.Header.Set(secretprovider.InvocationHeader, id)
+	}
+}
+
+// ResponseError is a Secrets Manager error response.
+type ResponseError struct {
+	StatusCode int
+	Code       string
+	Message    string
+}
+
+func (e *ResponseError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("%s (HTTP %d)", e.Code, e.StatusCode)
+	}
+	return e.Code + ": " + e.Message
+}
+
+func newResponseError(resp *http.Response, data []byte) *ResponseError {
+	var body struct {
+		Type         string `json:"__type"`
+		Message      string `json:"message"`
+		MessageUpper string `json:"Message"`
+	}
+	_ = json.Unmarshal(data, &body)
+	code := body.Type
+	if code == "" {
+		code, _, _ = strings.Cut(resp.Header.Get("X-Amzn-ErrorType"), ":")
+	}
+	if _, suffix, ok := cutLast(code, "#"); ok {
+		code = suffix
+	}
+	if code == "" {
+		code = http.StatusText(resp.StatusCode)
+	}
+	message := body.Message
+	if message == "" {
+		message = body.MessageUpper
+	}
+	return &ResponseError{StatusCode: resp.StatusCode, Code: code, Message: message}
+}
+
+// wrapError marks errors for calls the credentials may not make with
+// secretprovider.ErrPermissionDenied.
+func wrapError(op string, err error) error {
+	var response *ResponseError
+	if errors.As(err, &response) && (response.Code == "AccessDeniedException" || response.StatusCode == http.StatusForbidden) {
+		return fmt.Errorf("%s: %w: %w", op, secretprovider.ErrPermissionDenied, err)
+	}
+	return fmt.Errorf("%s: %w", op, err)
+}
+
+// awsName stores a dev-vault path and name as one Secrets Manager name.
+func awsName(path, name string) string {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return name
+	}
+	return path + "/" + name
+}
+
+// versionRevision reads the revision from a version ID written by
+// CreateSecretVersion, or returns 0.
+func versionRevision(versionID string) uint32 {
+	rest, ok := strings.CutPrefix(versionID, revisionPrefix)
+	if !ok {
+		return 0
+	}
+	digits, _, _ := strings.Cut(rest, "-")
+	revision, err := strconv.ParseUint(digits, 10, 32)
+	if err != nil {
+		return 0
+	}
+	return uint32(revision)
+}
+
+// epochTime converts the fractional epoch seconds of Secrets Manager dates.
+func epochTime(seconds float64) time.Time {
+	sec, frac := math.Modf(seconds)
+	return time.Unix(int64(sec), int64(frac*1e9)).UTC()
+}
+
+func cutLast(s, sep string) (before, after string, found bool) {
+	i := strings.LastIndex(s, sep)
+	if i < 0 {
+		return s, "", false
+	}
+	return s[:i], s[i+len(sep):], true
+}
@@ -0,0 +1,619 @@
+package awssm
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bsmartlabs/dev-vault/internal/config"
+	"github.com/bsmartlabs/dev-vault/internal/secretprovider"
+)
+
+// fakeSecretsManager serves the subset of the Secrets Manager JSON API the
+// provider calls, keeping secrets in memory.
+type fakeSecretsManager struct {
+	t        *testing.T
+	pageSize int
+
+	mu      sync.Mutex
+	secrets []*fakeSecret
+	fail    map[string]fakeFailure
+	actions []string
+}
+
+type fakeSecret struct {
+	entry  secretEntry
+	values map[string]secretValueResponse
+}
+
+type fakeFailure struct {
+	status int
+	header string
+	body   string
+}
+
+func newFakeSecretsManager(t *testing.T) (*fakeSecretsManager, *API) {
+	t.Helper()
+	fake := &fakeSecretsManager{t: t, pageSize: 100, fail: map[string]fakeFailure{}}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+	return fake, fakeAPI(t, server.URL, server.Client())
+}
+
+func fakeAPI(t *testing.T, endpoint string, client *http.Client) *API {
+	t.Helper()
+	deps := credentialDeps(map[string]string{"AWS_ACCESS_KEY_ID": "AKID", "AWS_SECRET_ACCESS_KEY": "secret", "AWS_ENDPOINT_URL": endpoint})
+	deps.client = client
+	deps.now = func() time.Time { return time.Unix(123, 0) }
+	deps.token = func() string { return "TOKEN" }
+	api, err := openWithDeps(config.Config{Region: "eu-west-3"}, "", deps)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	return api
+}
+
+func (f *fakeSecretsManager) add(name string, tags []tag, changed float64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.secrets = append(f.secrets, &fakeSecret{
+		entry:  secretEntry{ARN: "arn:aws:secretsmanager:eu-west-3:1:secret:" + name, Name: name, Tags: tags, LastChangedDate: &changed},
+		values: map[string]secretValueResponse{},
+	})
+}
+
+func (f *fakeSecretsManager) find(id string) *fakeSecret {
+	for _, secret := range f.secrets {
+		if secret.entry.ARN == id || secret.entry.Name == id {
+			return secret
+		}
+	}
+	return nil
+}
+
+func (f *fakeSecretsManager) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	action := strings.TrimPrefix(r.Header.Get("X-Amz-Target"), "secretsmanager.")
+	f.actions = append(f.actions, action)
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKID/19700101/eu-west-3/secretsmanager/aws4_request,") {
+		f.t.Errorf("unsigned request: %v", r.Header)
+	}
+	if failure, ok := f.fail[action]; ok {
+		if failure.header != "" {
+			w.Header().Set("X-Amzn-ErrorType", failure.header)
+		}
+		w.WriteHeader(failure.status)
+		_, _ = io.WriteString(w, failure.body)
+		return
+	}
+
+	var in struct {
+		NextToken          string
+		SecretID           string `json:"SecretId"`
+		VersionID          string `json:"VersionId"`
+		VersionStage       string
+		RemoveFromVersion  string `json:"RemoveFromVersionId"`
+		Name               string
+		Tags               []tag
+		ClientRequestToken string
+		SecretString       *string
+		SecretBinary       []byte
+		Filters            []listFilter
+	}
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		f.t.Errorf("decode %s: %v", action, err)
+	}
+	var out any
+	switch action {
+	case "ListSecrets":
+		// Secrets Manager matches the name filter as a case-insensitive prefix.
+		secrets := f.secrets
+		for _, filter := range in.Filters {
+			secrets = nil
+			for _, secret := range f.secrets {
+				if filter.Key == "name" && strings.HasPrefix(strings.ToLower(secret.entry.Name), strings.ToLower(filter.Values[0])) {
+					secrets = append(secrets, secret)
+				}
+			}
+		}
+		start, _ := strconv.Atoi(in.NextToken)
+		end := min(start+f.pageSize, len(secrets))
+		page := listSecretsResponse{}
+		for _, secret := range secrets[start:end] {
+			page.SecretList = append(page.SecretList, secret.entry)
+		}
+		if end < len(secrets) {
+			page.NextToken = strconv.Itoa(end)
+		}
+		out = page
+	case "CreateSecret":
+		f.secrets = append(f.secrets, &fakeSecret{
+			entry:  secretEntry{ARN: "arn:aws:secretsmanager:eu-west-3:1:secret:" + in.Name, Name: in.Name, Tags: in.Tags},
+			values: map[string]secretValueResponse{},
+		})
+		out = secretEntry{ARN: "arn:aws:secretsmanager:eu-west-3:1:secret:" + in.Name, Name: in.Name}
+	default:
+		secret := f.find(in.SecretID)
+		if secret == nil {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = io.WriteString(w, `{"__type":"ResourceNotFoundException","message":"Secrets Manager can't find the specified secret."}`)
+			return
+		}
+		switch action {
+		case "DescribeSecret":
+			out = secret.entry
+		case "ListSecretVersionIds":
+			// Versions without a staging label are deprecated; the ones
+			// dev-vault wrote were all created at the same fixed time.
+			var ids []string
+			for id := range secret.values {
+				ids = append(ids, id)
+			}
+			sort.Strings(ids)
+			start, _ := strconv.Atoi(in.NextToken)
+			end := min(start+f.pageSize, len(ids))
+			page := listVersionsResponse{ARN: secret.entry.ARN}
+			created := 1700000000.5
+			for _, id := range ids[start:end] {
+				version := versionEntry{VersionID: id, VersionStages: secret.entry.VersionIdsToStages[id]}
+				if strings.HasPrefix(id, revisionPrefix) {
+					version.CreatedDate = &created
+				}
+				page.Versions = append(page.Versions, version)
+			}
+			if end < len(ids) {
+				page.NextToken = strconv.Itoa(end)
+			}
+			out = page
+		case "GetSecretValue":
+			if in.VersionID != "" {
+				out = secret.values[in.VersionID]
+				break
+			}
+			for id, stages := range secret.entry.VersionIdsToStages {
+				if reflect.DeepEqual(stages, []string{currentStage}) {
+					out = secret.values[id]
+				}
+			}
+		case "DeleteSecret":
+			f.secrets = slices.DeleteFunc(f.secrets, func(candidate *fakeSecret) bool { return candidate == secret })
+			out = secretEntry{ARN: secret.entry.ARN, Name: secret.entry.Name}
+		case "UpdateSecretVersionStage":
+			stages := secret.entry.VersionIdsToStages[in.RemoveFromVersion]
+			secret.entry.VersionIdsToStages[in.RemoveFromVersion] = slices.DeleteFunc(stages, func(stage string) bool { return stage == in.VersionStage })
+			out = secretEntry{ARN: secret.entry.ARN, Name: secret.entry.Name}
+		case "PutSecretValue":
+			stages := map[string][]string{}
+			for id, current := range secret.entry.VersionIdsToStages {
+				if reflect.DeepEqual(current, []string{currentStage}) {
+					stages[id] = []string{"AWSPREVIOUS"}
+				}
+			}
+			stages[in.ClientRequestToken] = []string{currentStage}
+			secret.entry.VersionIdsToStages = stages
+			created := 1700000000.5
+			secret.values[in.ClientRequestToken] = secretValueResponse{ARN: secret.entry.ARN, VersionID: in.ClientRequestToken, SecretString: in.SecretString, SecretBinary: in.SecretBinary, CreatedDate: &created}
+			out = secretValueResponse{ARN: secret.entry.ARN, VersionID: in.ClientRequestToken}
+		}
+	}
+	_ = json.NewEncoder(w).Encode(out)
+}
+
+func TestOpen(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKID")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	t.Setenv("AWS_PROFILE", "")
+	t.Setenv("AWS_ENDPOINT_URL", "")
+	t.Setenv("AWS_ENDPOINT_URL_SECRETS_MANAGER", "")
+	api, err := Open(config.Config{Provider: config.ProviderAWS, Region: "eu-west-3"}, "")
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	if got := api.(*API).endpoint; got != "https://secretsmanager.eu-west-3.amazonaws.com" {
+		t.Fatalf("unexpected endpoint %q", got)
+	}
+
+	env := map[string]string{"AWS_ACCESS_KEY_ID": "AKID", "AWS_SECRET_ACCESS_KEY": "secret", "AWS_ENDPOINT_URL": "http://generic:4566", "AWS_ENDPOINT_URL_SECRETS_MANAGER": "http://localstack:4566"}
+	opened, err := openWithDeps(config.Config{Region: "eu-west-3"}, "", credentialDeps(env))
+	if err != nil || opened.endpoint != "http://localstack:4566" {
+		t.Fatalf("expected the service endpoint to win: %#v %v", opened, err)
+	}
+
+	t.Run("Errors", func(t *testing.T) {
+		cases := []struct {
+			name    string
+			cfg     config.Config
+			profile string
+			env     map[string]string
+			wantSub string
+		}{
+			{"InvalidRegion", config.Config{Region: "eu-west-3.evil.example/"}, "", nil, `invalid region "eu-west-3.evil.example/"`},
+			{"InvalidEndpoint", config.Config{Region: "eu-west-3"}, "", map[string]string{"AWS_ENDPOINT_URL": "localhost:4566"}, `invalid aws endpoint "localhost:4566"`},
+			{"UnparsableEndpoint", config.Config{Region: "eu-west-3"}, "", map[string]string{"AWS_ENDPOINT_URL": "http://[::1"}, "invalid aws endpoint"},
+			{"ProfileFromConfig", config.Config{Region: "eu-west-3", Profile: "missing"}, "", nil, `aws profile "missing" not found`},
+			{"ProfileOverride", config.Config{Region: "eu-west-3", Profile: "team"}, " other ", nil, `aws profile "other" not found`},
+		}
+		for _, tc := range cases {
+			t.Run(tc.name, func(t *testing.T) {
+				if _, err := openWithDeps(tc.cfg, tc.profile, credentialDeps(tc.env)); err == nil || !strings.Contains(err.Error(), tc.wantSub) {
+					t.Fatalf("expected error containing %q, got %v", tc.wantSub, err)
+				}
+			})
+		}
+	})
+}
+
+func TestAPI_RoundTrip(t *testing.T) {
+	fake, api := newFakeSecretsManager(t)
+
+	created, err := api.CreateSecret(secretprovider.CreateSecretInput{Name: "db-dev", Path: "/team/api/", Type: secretprovider.SecretTypeKeyValue})
+	if err != nil {
+		t.Fatalf("create secret: %v", err)
+	}
+	want := &secretprovider.SecretRecord{ID: "arn:aws:secretsmanager:eu-west-3:1:secret:team/api/db-dev", Name: "db-dev", Path: "/team/api", Type: secretprovider.SecretTypeKeyValue}
+	if !reflect.DeepEqual(created, want) {
+		t.Fatalf("unexpected secret:\n got %#v\nwant %#v", created, want)
+	}
+
+	for i, data := range [][]byte{[]byte(`{"A":"1"}`), {0xff, 0x00}} {
+		version, err := api.CreateSecretVersion(secretprovider.CreateSecretVersionInput{SecretID: created.ID, Data: data})
+		if err != nil {
+			t.Fatalf("create version: %v", err)
+		}
+		if version.Revision != uint32(i+1) || version.SecretID != created.ID || version.Status != "enabled" {
+			t.Fatalf("unexpected version: %#v", version)
+		}
+		access, err := api.AccessSecretVersion(secretprovider.AccessSecretVersionInput{SecretID: created.ID, Revision: secretprovider.RevisionLatestEnabled})
+		if err != nil {
+			t.Fatalf("access: %v", err)
+		}
+		if string(access.Data) != string(data) || access.Revision != uint32(i+1) || access.Type != secretprovider.SecretTypeKeyValue || !access.CreatedAt.Equal(time.Unix(1700000000, 5e8)) {
+			t.Fatalf("unexpected access: %#v", access)
+		}
+	}
+	if got := fake.secrets[0].entry.VersionIdsToStages; !reflect.DeepEqual(got, map[string][]string{
+		"dev-vault-rev-0000000001-TOKEN": {"AWSPREVIOUS"},
+		"dev-vault-rev-0000000002-TOKEN": {currentStage},
+	}) {
+		t.Fatalf("unexpected versions: %v", got)
+	}
+
+	// A fresh client describes the secret to learn its type.
+	fresh := fakeAPI(t, api.endpoint, api.client)
+	access, err := fresh.AccessSecretVersion(secretprovider.AccessSecretVersionInput{SecretID: created.ID})
+	if err != nil || access.Type != secretprovider.SecretTypeKeyValue {
+		t.Fatalf("unexpected access: %#v %v", access, err)
+	}
+
+	// Versions written by another tool read as revision 0.
+	fake.add("legacy-dev", nil, 1700000000.5)
+	fake.secrets[1].entry.VersionIdsToStages = map[string][]string{"0b5d": {currentStage}}
+	value := "x"
+	fake.secrets[1].values["0b5d"] = secretValueResponse{ARN: fake.secrets[1].entry.ARN, VersionID: "0b5d", SecretString: &value}
+	access, err = fresh.AccessSecretVersion(secretprovider.AccessSecretVersionInput{SecretID: "legacy-dev"})
+	if err != nil || access.Revision != 0 || access.Type != secretprovider.SecretTypeOpaque || string(access.Data) != "x" {
+		t.Fatalf("unexpected legacy access: %#v %v", access, err)
+	}
+	version, err := fresh.CreateSecretVersion(secretprovider.CreateSecretVersionInput{SecretID: "legacy-dev", Data: []byte("y")})
+	if err != nil || version.Revision != 1 {
+		t.Fatalf("unexpected legacy version: %#v %v", version, err)
+	}
+}
+
+func TestAPI_ListSecrets(t *testing.T) {
+	fake, api := newFakeSecretsManager(t)
+	fake.pageSize = 1
+	fake.add("a-dev", []tag{{Key: "owner", Value: "payments"}, {Key: "pii"}}, 1700000000.25)
+	fake.add("team/a-dev", []tag{{Key: typeTag, Value: "key_value"}}, 1700000000)
+	fake.add("b-dev", nil, 1700000000)
+
+	all, err := api.ListSecrets(secretprovider.ListSecretsInput{})
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	want := []secretprovider.SecretRecord{
+		{ID: fake.secrets[0].entry.ARN, Name: "a-dev", Path: "/", Type: secretprovider.SecretTypeOpaque, Tags: []string{"owner=payments", "pii"}, UpdatedAt: time.Unix(1700000000, 250000000).UTC()},
+		{ID: fake.secrets[1].entry.ARN, Name: "a-dev", Path: "/team", Type: secretprovider.SecretTypeKeyValue, UpdatedAt: time.Unix(1700000000, 0).UTC()},
+		{ID: fake.secrets[2].entry.ARN, Name: "b-dev", Path: "/", Type: secretprovider.SecretTypeOpaque, UpdatedAt: time.Unix(1700000000, 0).UTC()},
+	}
+	if !reflect.DeepEqual(all, want) {
+		t.Fatalf("unexpected records:\n got %#v\nwant %#v", all, want)
+	}
+	if got := strings.Join(fake.actions, ","); got != "ListSecrets,ListSecrets,ListSecrets" {
+		t.Fatalf("expected one call per page, got %s", got)
+	}
+
+	filters := []struct {
+		req  secretprovider.ListSecretsInput
+		want []secretprovider.SecretRecord
+	}{
+		{secretprovider.ListSecretsInput{Name: "a-dev"}, want[:2]},
+		{secretprovider.ListSecretsInput{Name: "a-dev", Path: "/"}, want[:1]},
+		{secretprovider.ListSecretsInput{Type: secretprovider.SecretTypeKeyValue}, want[1:2]},
+		{secretprovider.ListSecretsInput{Name: "c-dev"}, nil},
+		{secretprovider.ListSecretsInput{NamePrefix: "a"}, want},
+		{secretprovider.ListSecretsInput{NamePrefix: "a", Path: "/team"}, want[1:2]},
+	}
+	for _, filter := range filters {
+		got, err := api.ListSecrets(filter.req)
+		if err != nil || !reflect.DeepEqual(got, filter.want) {
+			t.Fatalf("list %#v: unexpected records %#v %v", filter.req, got, err)
+		}
+	}
+	// With a path, the prefix is sent as a name filter and the other secrets
+	// are not paged through.
+	fake.actions = nil
+	if got, err := api.ListSecrets(secretprovider.ListSecretsInput{NamePrefix: "b", Path: "/"}); err != nil || !reflect.DeepEqual(got, want[2:]) {
+		t.Fatalf("unexpected prefixed records: %#v %v", got, err)
+	}
+	if got := strings.Join(fake.actions, ","); got != "ListSecrets" {
+		t.Fatalf("expected one page, got %s", got)
+	}
+}
+
+func TestAPI_ListSecretVersions(t *testing.T) {
+	fake, api := newFakeSecretsManager(t)
+	fake.pageSize = 2
+	created, err := api.CreateSecret(secretprovider.CreateSecretInput{Name: "db-dev"})
+	if err != nil {
+		t.Fatalf("create secret: %v", err)
+	}
+	for _, data := range []string{"1", "2", "3"} {
+		if _, err := api.CreateSecretVersion(secretprovider.CreateSecretVersionInput{SecretID: created.ID, Data: []byte(data)}); err != nil {
+			t.Fatalf("create version: %v", err)
+		}
+	}
+	// A version written by another tool has no stage and no known date.
+	fake.secrets[0].values["0b5d"] = secretValueResponse{}
+
+	versions, err := api.ListSecretVersions(secretprovider.ListSecretVersionsInput{SecretID: created.ID})
+	if err != nil {
+		t.Fatalf("list versions: %v", err)
+	}
+	createdAt := time.Unix(1700000000, 500000000).UTC()
+	want := []secretprovider.SecretVersionRecord{
+		{SecretID: created.ID, Revision: 0, Status: "disabled"},
+		{SecretID: created.ID, Revision: 1, Status: "disabled", CreatedAt: createdAt},
+		{SecretID: created.ID, Revision: 2, Status: "enabled", CreatedAt: createdAt},
+		{SecretID: created.ID, Revision: 3, Status: "enabled", CreatedAt: createdAt},
+	}
+	if !reflect.DeepEqual(versions, want) {
+		t.Fatalf("unexpected versions:\n got %#v\nwant %#v", versions, want)
+	}
+	if got := strings.Count(strings.Join(fake.actions, ","), "ListSecretVersionIds"); got != 2 {
+		t.Fatalf("expected one call per page, got %v", fake.actions)
+	}
+
+	if _, err := api.ListSecretVersions(secretprovider.ListSecretVersionsInput{SecretID: "missing-dev"}); err == nil || !strings.HasPrefix(err.Error(), "list secret versions: ResourceNotFoundException") {
+		t.Fatalf("expected not found, got %v", err)
+	}
+}
+
+func TestAPI_AccessRevision(t *testing.T) {
+	fake, api := newFakeSecretsManager(t)
+	created, err := api.CreateSecret(secretprovider.CreateSecretInput{Name: "db-dev"})
+	if err != nil {
+		t.Fatalf("create secret: %v", err)
+	}
+	for _, data := range []string{"one", "two", "three"} {
+		if _, err := api.CreateSecretVersion(secretprovider.CreateSecretVersionInput{SecretID: created.ID, Data: []byte(data)}); err != nil {
+			t.Fatalf("create version: %v", err)
+		}
+	}
+
+	// Deprecated versions stay readable by revision.
+	got, err := api.AccessSecretVersion(secretprovider.AccessSecretVersionInput{SecretID: created.ID, Revision: "1"})
+	if err != nil || got.Revision != 1 || string(got.Data) != "one" {
+		t.Fatalf("unexpected version: %#v %v", got, err)
+	}
+	if _, err := api.AccessSecretVersion(secretprovider.AccessSecretVersionInput{SecretID: created.ID, Revision: "7"}); err == nil || err.Error() != "access secret version: revision 7 not found" {
+		t.Fatalf("expected missing revision, got %v", err)
+	}
+	fake.fail["ListSecretVersionIds"] = fakeFailure{status: 500}
+	if _, err := api.AccessSecretVersion(secretprovider.AccessSecretVersionInput{SecretID: created.ID, Revision: "1"}); err == nil || !strings.HasPrefix(err.Error(), "list secret versions: ") {
+		t.Fatalf("expected list error, got %v", err)
+	}
+}
+
+func TestAPI_DisableSecretVersion(t *testing.T) {
+	fake, api := newFakeSecretsManager(t)
+	fake.add("db-dev", []tag{{Key: typeTag, Value: "opaque"}}, 1)
+	for _, data := range []string{"1", "2", "3"} {
+		if _, err := api.CreateSecretVersion(secretprovider.CreateSecretVersionInput{SecretID: "db-dev", Data: []byte(data)}); err != nil {
+			t.Fatalf("create version: %v", err)
+		}
+	}
+	fake.actions = nil
+
+	// Revision 1 already lost its labels; revision 2 is AWSPREVIOUS.
+	for _, revision := range []uint32{1, 2} {
+		if err := api.DisableSecretVersion(secretprovider.DisableSecretVersionInput{SecretID: "db-dev", Revision: revision}); err != nil {
+			t.Fatalf("disable rev=%d: %v", revision, err)
+		}
+	}
+	versions, err := api.ListSecretVersions(secretprovider.ListSecretVersionsInput{SecretID: "db-dev"})
+	if err != nil || len(versions) != 3 || versions[0].Status != "disabled" || versions[1].Status != "disabled" || versions[2].Status != "enabled" {
+		t.Fatalf("unexpected versions: %#v %v", versions, err)
+	}
+	if want := []string{"ListSecretVersionIds", "ListSecretVersionIds", "UpdateSecretVersionStage", "ListSecretVersionIds"}; !reflect.DeepEqual(fake.actions, want) {
+		t.Fatalf("unexpected actions: %v", fake.actions)
+	}
+
+	if err := api.DisableSecretVersion(secretprovider.DisableSecretVersionInput{SecretID: "db-dev", Revision: 3}); err == nil || err.Error() != "disable secret version: revision 3 is AWSCURRENT; push a newer version first" {
+		t.Fatalf("expected current refusal, got %v", err)
+	}
+	if err := api.DisableSecretVersion(secretprovider.DisableSecretVersionInput{SecretID: "db-dev", Revision: 9}); err == nil || err.Error() != "disable secret version: revision 9 not found" {
+		t.Fatalf("expected not found, got %v", err)
+	}
+	if err := api.DisableSecretVersion(secretprovider.DisableSecretVersionInput{SecretID: "gone-dev", Revision: 1}); err == nil || !strings.HasPrefix(err.Error(), "disable secret version: list secret versions: ResourceNotFoundException") {
+		t.Fatalf("expected list error, got %v", err)
+	}
+
+	if _, err := api.CreateSecretVersion(secretprovider.CreateSecretVersionInput{SecretID: "db-dev", Data: []byte("4")}); err != nil {
+		t.Fatalf("create version: %v", err)
+	}
+	fake.fail["UpdateSecretVersionStage"] = fakeFailure{status: http.StatusBadRequest, body: `{"__type":"AccessDeniedException","message":"no"}`}
+	if err := api.DisableSecretVersion(secretprovider.DisableSecretVersionInput{SecretID: "db-dev", Revision: 3}); !errors.Is(err, secretprovider.ErrPermissionDenied) || !strings.HasPrefix(err.Error(), "disable secret version: ") {
+		t.Fatalf("expected permission denied, got %v", err)
+	}
+}
+
+func TestAPI_Delete(t *testing.T) {
+	fake, api := newFakeSecretsManager(t)
+	fake.add("db-dev", []tag{{Key: typeTag, Value: "key_value"}}, 1)
+	records, err := api.ListSecrets(secretprovider.ListSecretsInput{})
+	if err != nil || len(records) != 1 {
+		t.Fatalf("list: %#v %v", records, err)
+	}
+	if err := api.DeleteSecret(secretprovider.DeleteSecretInput{SecretID: records[0].ID}); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if len(fake.secrets) != 0 || len(api.types) != 0 {
+		t.Fatalf("secret not deleted: %#v %v", fake.secrets, api.types)
+	}
+	if err := api.DeleteSecret(secretprovider.DeleteSecretInput{SecretID: records[0].ID}); err == nil || !strings.HasPrefix(err.Error(), "delete secret: ResourceNotFoundException") {
+		t.Fatalf("expected not found, got %v", err)
+	}
+	err = api.DeleteSecretVersion(secretprovider.DeleteSecretVersionInput{SecretID: records[0].ID, Revision: 1})
+	if !errors.Is(err, errors.ErrUnsupported) || err.Error() != "delete secret version: the aws provider cannot delete one version, they age out as new ones are written: unsupported operation" {
+		t.Fatalf("expected unsupported, got %v", err)
+	}
+}
+
+func TestAPI_Errors(t *testing.T) {
+	t.Run("ResponseErrors", func(t *testing.T) {
+		cases := []struct {
+			name       string
+			failure    fakeFailure
+			wantSub    string
+			wantDenied bool
+		}{
+			{"AccessDenied", fakeFailure{status: 400, body: `{"__type":"com.amazonaws.secretsmanager#AccessDeniedException","Message":"not authorized"}`}, "list secrets: permission denied: AccessDeniedException: not authorized", true},
+			{"Forbidden", fakeFailure{status: 403, header: "UnrecognizedClientException:http://internal"}, "list secrets: permission denied: UnrecognizedClientException (HTTP 403)", true},
+			{"Throttled", fakeFailure{status: 400, body: `{"__type":"ThrottlingException","message":"slow down"}`}, "list secrets: ThrottlingException: slow down", false},
+			{"NoDetails", fakeFailure{status: 500, body: "oops"}, "list secrets: Internal Server Error (HTTP 500)", false},
+			{"BadBody", fakeFailure{status: 200, body: "{"}, "list secrets: decode response: unexpected end of JSON input", false},
+		}
+		for _, tc := range cases {
+			t.Run(tc.name, func(t *testing.T) {
+				fake, api := newFakeSecretsManager(t)
+				fake.fail["ListSecrets"] = tc.failure
+				_, err := api.ListSecrets(secretprovider.ListSecretsInput{})
+				if err == nil || err.Error() != tc.wantSub || errors.Is(err, secretprovider.ErrPermissionDenied) != tc.wantDenied {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			})
+		}
+	})
+
+	t.Run("Transport", func(t *testing.T) {
+		server := httptest.NewServer(http.NotFoundHandler())
+		api := fakeAPI(t, server.URL, server.Client())
+		server.Close()
+		if _, err := api.ListSecrets(secretprovider.ListSecretsInput{}); err == nil || !strings.HasPrefix(err.Error(), "list secrets: Post ") {
+			t.Fatalf("expected transport error, got %v", err)
+		}
+
+		broken := fakeAPI(t, "http://aws.test", &http.Client{Transport: roundTripFunc(func(*http.Request) (*http.Response, error) {
+			return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(failingReader{})}, nil
+		})})
+		if _, err := broken.ListSecrets(secretprovider.ListSecretsInput{}); err == nil || err.Error() != "list secrets: read response: read boom" {
+			t.Fatalf("expected read error, got %v", err)
+		}
+	})
+
+	t.Run("Calls", func(t *testing.T) {
+		fake, api := newFakeSecretsManager(t)
+		if _, err := api.AccessSecretVersion(secretprovider.AccessSecretVersionInput{SecretID: "x", Revision: "latest"}); err == nil || err.Error() != `access secret version: revision "latest" is not supported by the aws provider` {
+			t.Fatalf("expected revision error, got %v", err)
+		}
+		if _, err := api.AccessSecretVersion(secretprovider.AccessSecretVersionInput{SecretID: "missing-dev"}); err == nil || !strings.Contains(err.Error(), "access secret version: ResourceNotFoundException") {
+			t.Fatalf("expected not found, got %v", err)
+		}
+		if _, err := api.CreateSecretVersion(secretprovider.CreateSecretVersionInput{SecretID: "missing-dev"}); err == nil || !strings.Contains(err.Error(), "create secret version: ResourceNotFoundException") {
+			t.Fatalf("expected not found, got %v", err)
+		}
+
+		fake.fail["CreateSecret"] = fakeFailure{status: 400, body: `{"__type":"ResourceExistsException","message":"exists"}`}
+		if _, err := api.CreateSecret(secretprovider.CreateSecretInput{Name: "a-dev"}); err == nil || err.Error() != "create secret: ResourceExistsException: exists" {
+			t.Fatalf("expected create error, got %v", err)
+		}
+		delete(fake.fail, "CreateSecret")
+		created, err := api.CreateSecret(secretprovider.CreateSecretInput{Name: "a-dev"})
+		if err != nil || created.Type != secretprovider.SecretTypeOpaque || created.Path != "/" {
+			t.Fatalf("unexpected secret: %#v %v", created, err)
+		}
+
+		fake.fail["PutSecretValue"] = fakeFailure{status: 400, body: `{"__type":"LimitExceededException"}`}
+		if _, err := api.CreateSecretVersion(secretprovider.CreateSecretVersionInput{SecretID: created.ID, Data: []byte("x")}); err == nil || err.Error() != "create secret version: LimitExceededException (HTTP 400)" {
+			t.Fatalf("expected put error, got %v", err)
+		}
+
+		fake.secrets[0].entry.VersionIdsToStages = map[string][]string{"dev-vault-rev-4294967295-TOKEN": {currentStage}}
+		if _, err := api.CreateSecretVersion(secretprovider.CreateSecretVersionInput{SecretID: created.ID}); err == nil || !strings.Contains(err.Error(), "has no revision left") {
+			t.Fatalf("expected overflow error, got %v", err)
+		}
+
+		// Reading a secret no listing returned describes it first.
+		fresh := fakeAPI(t, api.endpoint, api.client)
+		fake.fail["DescribeSecret"] = fakeFailure{status: 400, body: `{"__type":"AccessDeniedException"}`}
+		fake.secrets[0].values["dev-vault-rev-4294967295-TOKEN"] = secretValueResponse{ARN: created.ID}
+		if _, err := fresh.AccessSecretVersion(secretprovider.AccessSecretVersionInput{SecretID: created.ID}); !errors.Is(err, secretprovider.ErrPermissionDenied) || !strings.HasPrefix(err.Error(), "describe secret: ") {
+			t.Fatalf("expected describe error, got %v", err)
+		}
+	})
+}
+
+func TestAPI_InvocationHeaders(t *testing.T) {
+	t.Cleanup(func() { secretprovider.SetInvocationID("") })
+	var got []http.Header
+	api := fakeAPI(t, "http://aws.test", &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		got = append(got, req.Header.Clone())
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader("{}"))}, nil
+	})})
+	for _, id := range []string{"", "ABC123"} {
+		secretprovider.SetInvocationID(id)
+		if _, err := api.ListSecrets(secretprovider.ListSecretsInput{}); err != nil {
+			t.Fatalf("list: %v", err)
+		}
+	}
+	if ua := got[0].Get("User-Agent"); !strings.HasPrefix(ua, "dev-vault/") || strings.Contains(ua, "invocation/") || got[0].Get(secretprovider.InvocationHeader) != "" {
+		t.Fatalf("unexpected headers without invocation: %v", got[0])
+	}
+	if !strings.HasSuffix(got[1].Get("User-Agent"), ") invocation/ABC123") || got[1].Get(secretprovider.InvocationHeader) != "ABC123" ||
+		!strings.Contains(got[1].Get("Authorization"), ";user-agent;x-amz-date;x-amz-target;x-dev-vault-invocation,") {
+		t.Fatalf("unexpected headers with invocation: %v", got[1])
+	}
+}
+
+func TestVersionRevision(t *testing.T) {
+	cases := map[string]uint32{
+		"dev-vault-rev-0000000042-TOKEN": 42,
+		"dev-vault-rev-x-TOKEN":          0,
+		"EXAMPLE1-90ab-cdef-fedc-ba987":  0,
+	}
+	for versionID, want := range cases {
+		if got := versionRevision(versionID); got != want {
+			t.Fatalf("versionRevision(%q) = %d, want %d", versionID, got, want)
+		}
+	}
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) { return 0, errors.New("read boom") }
@@ -0,0 +1,90 @@
+package awssm
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+type credentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+}
+
+// resolveCredentials follows the AWS CLI precedence for the subset dev-vault
+// supports: a named profile (--profile, then the manifest, then AWS_PROFILE)
+// from the shared credentials file, else the AWS_ACCESS_KEY_ID and
+// AWS_SECRET_ACCESS_KEY environment variables, else the default profile.
+func resolveCredentials(profileName string, deps openDeps) (credentials, error) {
+	if profileName == "" {
+		profileName = strings.TrimSpace(deps.getenv("AWS_PROFILE"))
+	}
+	if profileName == "" {
+		creds := credentials{
+			AccessKeyID:     deps.getenv("AWS_ACCESS_KEY_ID"),
+			SecretAccessKey: deps.getenv("AWS_SECRET_ACCESS_KEY"),
+			SessionToken:    deps.getenv("AWS_SESSION_TOKEN"),
+		}
+		if creds.AccessKeyID != "" && creds.SecretAccessKey != "" {
+			return creds, nil
+		}
+		profileName = "default"
+	}
+
+	path := deps.getenv("AWS_SHARED_CREDENTIALS_FILE")
+	if path == "" {
+		home, err := deps.userHomeDir()
+		if err != nil {
+			return credentials{}, fmt.Errorf("locate aws credentials file: %w", err)
+		}
+		path = filepath.Join(home, ".aws", "credentials")
+	}
+	raw, err := deps.readFile(path)
+	if err != nil {
+		return credentials{}, fmt.Errorf("read aws credentials file: %w", err)
+	}
+	values, ok := iniSection(raw, profileName)
+	if !ok {
+		return credentials{}, fmt.Errorf("aws profile %q not found in %s", profileName, path)
+	}
+	creds := credentials{
+		AccessKeyID:     values["aws_access_key_id"],
+		SecretAccessKey: values["aws_secret_access_key"],
+		SessionToken:    values["aws_session_token"],
+	}
+	if creds.AccessKeyID == "" || creds.SecretAccessKey == "" {
+		return credentials{}, fmt.Errorf("aws profile %q sets no aws_access_key_id and aws_secret_access_key", profileName)
+	}
+	return creds, nil
+}
+
+// iniSection returns the key/value pairs of one [section] of an AWS
+// credentials file. Comments start with # or ;.
+func iniSection(raw []byte, section string) (map[string]string, bool) {
+	var values map[string]string
+	current := ""
+	scanner := bufio.NewScanner(bytes.NewReader(raw))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			current = strings.TrimSpace(line[1 : len(line)-1])
+			if current == section && values == nil {
+				values = map[string]string{}
+			}
+			continue
+		}
+		if current != section {
+			continue
+		}
+		if key, value, ok := strings.Cut(line, "="); ok {
+			values[strings.ToLower(strings.TrimSpace(key))] = strings.TrimSpace(value)
+		}
+	}
+	return values, values != nil
+}
@@ -0,0 +1,88 @@
+package awssm
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+const credentialsFile = `# shared credentials
+[default]
+aws_access_key_id = AKIDDEFAULT
+aws_secret_access_key = default-secret
+
+; a named profile
+[ team ]
+AWS_ACCESS_KEY_ID=AKIDTEAM
+aws_secret_access_key=team-secret
+aws_session_token=team-token
+not a pair
+
+[empty]
+region = eu-west-3
+`
+
+func credentialDeps(env map[string]string) openDeps {
+	return openDeps{
+		getenv:      func(key string) string { return env[key] },
+		userHomeDir: func() (string, error) { return "/home/dev", nil },
+		readFile: func(path string) ([]byte, error) {
+			if path != "/home/dev/.aws/credentials" && path != "/custom/credentials" {
+				return nil, errors.New("no such file")
+			}
+			return []byte(credentialsFile), nil
+		},
+	}
+}
+
+func TestResolveCredentials(t *testing.T) {
+	cases := []struct {
+		name    string
+		profile string
+		env     map[string]string
+		want    credentials
+	}{
+		{"Environment", "", map[string]string{"AWS_ACCESS_KEY_ID": "AKIDENV", "AWS_SECRET_ACCESS_KEY": "env-secret", "AWS_SESSION_TOKEN": "env-token"}, credentials{"AKIDENV", "env-secret", "env-token"}},
+		{"DefaultProfileWithoutEnvironment", "", map[string]string{"AWS_ACCESS_KEY_ID": "AKIDENV"}, credentials{"AKIDDEFAULT", "default-secret", ""}},
+		{"AWSProfile", "", map[string]string{"AWS_PROFILE": "team", "AWS_ACCESS_KEY_ID": "AKIDENV", "AWS_SECRET_ACCESS_KEY": "env-secret"}, credentials{"AKIDTEAM", "team-secret", "team-token"}},
+		{"ExplicitProfileWins", "default", map[string]string{"AWS_PROFILE": "team"}, credentials{"AKIDDEFAULT", "default-secret", ""}},
+		{"CustomFile", "team", map[string]string{"AWS_SHARED_CREDENTIALS_FILE": "/custom/credentials"}, credentials{"AKIDTEAM", "team-secret", "team-token"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := resolveCredentials(tc.profile, credentialDeps(tc.env))
+			if err != nil || got != tc.want {
+				t.Fatalf("unexpected credentials: %#v %v", got, err)
+			}
+		})
+	}
+
+	t.Run("Errors", func(t *testing.T) {
+		cases := []struct {
+			name    string
+			profile string
+			deps    openDeps
+			wantSub string
+		}{
+			{"MissingProfile", "nope", credentialDeps(nil), `aws profile "nope" not found in /home/dev/.aws/credentials`},
+			{"ProfileWithoutKeys", "empty", credentialDeps(nil), `aws profile "empty" sets no aws_access_key_id`},
+			{"UnreadableFile", "team", credentialDeps(map[string]string{"AWS_SHARED_CREDENTIALS_FILE": "/missing"}), "read aws credentials file: no such file"},
+		}
+		homeless := credentialDeps(nil)
+		homeless.userHomeDir = func() (string, error) { return "", errors.New("no home") }
+		cases = append(cases, struct {
+			name    string
+			profile string
+			deps    openDeps
+			wantSub string
+		}{"NoHome", "", homeless, "locate aws credentials file: no home"})
+
+		for _, tc := range cases {
+			t.Run(tc.name, func(t *testing.T) {
+				if _, err := resolveCredentials(tc.profile, tc.deps); err == nil || !strings.Contains(err.Error(), tc.wantSub) {
+					t.Fatalf("expected error containing %q, got %v", tc.wantSub, err)
+				}
+			})
+		}
+	})
+}
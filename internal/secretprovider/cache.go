@@ -0,0 +1,73 @@
+package secretprovider
+
+import "sync"
+
+// AccessCache memoizes AccessSecretVersion for one invocation, so mappings
+// that read the same secret (split files, key filters, compose layers) fetch
+// its payload once. Writing, deleting, or disabling a version evicts the
+// secret's cached revisions.
+type AccessCache struct {
+	SecretAPI
+
+	mu       sync.Mutex
+	versions map[AccessSecretVersionInput]SecretVersionRecord
+}
+
+func NewAccessCache(api SecretAPI) *AccessCache {
+	return &AccessCache{SecretAPI: api, versions: make(map[AccessSecretVersionInput]SecretVersionRecord)}
+}
+
+func (c *AccessCache) AccessSecretVersion(req AccessSecretVersionInput) (*SecretVersionRecord, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	version, ok := c.versions[req]
+	if !ok {
+		fetched, err := c.SecretAPI.AccessSecretVersion(req)
+		if err != nil {
+			return nil, err
+		}
+		version = *fetched
+		c.versions[req] = version
+	}
+	// Callers own the returned payload.
+	version.Data = append([]byte(nil), version.Data...)
+	return &version, nil
+}
+
+func (c *AccessCache) CreateSecretVersion(req CreateSecretVersionInput) (*SecretVersionRecord, error) {
+	c.evict(req.SecretID)
+	return c.SecretAPI.CreateSecretVersion(req)
+}
+
+func (c *AccessCache) DeleteSecret(req DeleteSecretInput) error {
+	c.evict(req.SecretID)
+	return c.SecretAPI.DeleteSecret(req)
+}
+
+func (c *AccessCache) DeleteSecretVersion(req DeleteSecretVersionInput) error {
+	c.evict(req.SecretID)
+	return c.SecretAPI.DeleteSecretVersion(req)
+}
+
+func (c *AccessCache) DisableSecretVersion(req DisableSecretVersionInput) error {
+	c.evict(req.SecretID)
+	return c.SecretAPI.DisableSecretVersion(req)
+}
+
+// Reset drops every cached payload, so the next reads see versions created
+// since, e.g. by another machine while a long-running command waits.
+func (c *AccessCache) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	clear(c.versions)
+}
+
+func (c *AccessCache) evict(secretID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key := range c.versions {
+		if key.SecretID == secretID {
+			delete(c.versions, key)
+		}
+	}
+}
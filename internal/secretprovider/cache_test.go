@@ -0,0 +1,85 @@
+package secretprovider
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAccessCache(t *testing.T) {
+	fake := &profileFake{}
+	cache := NewAccessCache(fake)
+	latest := AccessSecretVersionInput{SecretID: "a", Revision: RevisionLatestEnabled}
+
+	first, err := cache.AccessSecretVersion(latest)
+	if err != nil || string(first.Data) != "a" {
+		t.Fatalf("unexpected access: %#v %v", first, err)
+	}
+	first.Data[0] = 'x'
+	second, err := cache.AccessSecretVersion(latest)
+	if err != nil || string(second.Data) != "a" {
+		t.Fatalf("expected an unmodified cached payload, got %#v %v", second, err)
+	}
+	if _, err := cache.AccessSecretVersion(AccessSecretVersionInput{SecretID: "b", Revision: RevisionLatestEnabled}); err != nil {
+		t.Fatalf("access b: %v", err)
+	}
+	if !reflect.DeepEqual(fake.accessed, []string{"a", "b"}) {
+		t.Fatalf("expected one fetch per secret, got %v", fake.accessed)
+	}
+
+	// Errors are not cached.
+	for range 2 {
+		if _, err := cache.AccessSecretVersion(AccessSecretVersionInput{SecretID: "missing"}); err == nil || err.Error() != "access boom" {
+			t.Fatalf("expected access error, got %v", err)
+		}
+	}
+
+	// A new version evicts only the written secret.
+	if _, err := cache.CreateSecretVersion(CreateSecretVersionInput{SecretID: "a"}); err != nil {
+		t.Fatalf("create version: %v", err)
+	}
+	for _, id := range []string{"a", "b"} {
+		if _, err := cache.AccessSecretVersion(AccessSecretVersionInput{SecretID: id, Revision: RevisionLatestEnabled}); err != nil {
+			t.Fatalf("access %s: %v", id, err)
+		}
+	}
+	if !reflect.DeepEqual(fake.accessed, []string{"a", "b", "missing", "missing", "a"}) || !reflect.DeepEqual(fake.created, []string{"a"}) {
+		t.Fatalf("unexpected calls: %v %v", fake.accessed, fake.created)
+	}
+
+	// Deletes evict too.
+	if err := cache.DeleteSecretVersion(DeleteSecretVersionInput{SecretID: "a", Revision: 1}); err != nil {
+		t.Fatalf("delete version: %v", err)
+	}
+	if _, err := cache.AccessSecretVersion(latest); err != nil {
+		t.Fatalf("access a: %v", err)
+	}
+	if err := cache.DeleteSecret(DeleteSecretInput{SecretID: "a"}); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, err := cache.AccessSecretVersion(latest); err != nil {
+		t.Fatalf("access a: %v", err)
+	}
+	if !reflect.DeepEqual(fake.accessed[5:], []string{"a", "a"}) || !reflect.DeepEqual(fake.deleted, []string{"a@1", "a"}) {
+		t.Fatalf("unexpected calls: %v %v", fake.accessed, fake.deleted)
+	}
+
+	// And so does disabling a version.
+	if err := cache.DisableSecretVersion(DisableSecretVersionInput{SecretID: "a", Revision: 1}); err != nil {
+		t.Fatalf("disable version: %v", err)
+	}
+	if _, err := cache.AccessSecretVersion(latest); err != nil {
+		t.Fatalf("access a: %v", err)
+	}
+	if !reflect.DeepEqual(fake.accessed[7:], []string{"a"}) || !reflect.DeepEqual(fake.disabled, []string{"a@1"}) {
+		t.Fatalf("unexpected calls: %v %v", fake.accessed, fake.disabled)
+	}
+
+	// Reset forgets everything.
+	cache.Reset()
+	if _, err := cache.AccessSecretVersion(latest); err != nil {
+		t.Fatalf("access a: %v", err)
+	}
+	if !reflect.DeepEqual(fake.accessed[8:], []string{"a"}) {
+		t.Fatalf("unexpected calls after reset: %v", fake.accessed)
+	}
+}
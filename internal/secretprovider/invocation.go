@@ -0,0 +1,22 @@
+package secretprovider
+
+import "sync/atomic"
+
+// InvocationHeader carries the invocation ID on the HTTP requests of the
+// providers that allow extra headers.
+const InvocationHeader = "X-Dev-Vault-Invocation"
+
+// invocationID is set once per process by SetInvocationID.
+var invocationID atomic.Value
+
+// SetInvocationID records the ID of the running dev-vault command, so every
+// provider call can be matched with the provider's own logs.
+func SetInvocationID(id string) {
+	invocationID.Store(id)
+}
+
+// InvocationID returns the ID recorded by SetInvocationID, or "".
+func InvocationID() string {
+	id, _ := invocationID.Load().(string)
+	return id
+}
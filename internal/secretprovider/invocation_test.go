@@ -0,0 +1,32 @@
+package secretprovider
+
+import (
+	"runtime"
+	"testing"
+)
+
+func TestInvocationID(t *testing.T) {
+	t.Cleanup(func() { SetInvocationID("") })
+	SetInvocationID("ABC123")
+	if InvocationID() != "ABC123" {
+		t.Fatalf("unexpected invocation: %q", InvocationID())
+	}
+}
+
+func TestUserAgent(t *testing.T) {
+	t.Cleanup(func() {
+		SetInvocationID("")
+		SetClient(Client{})
+	})
+	platform := runtime.GOOS + "/" + runtime.GOARCH
+	SetInvocationID("")
+	SetClient(Client{})
+	if got, want := UserAgent(), "dev-vault/unknown ("+platform+"; ci=false)"; got != want {
+		t.Fatalf("unexpected default:\n got %q\nwant %q", got, want)
+	}
+	SetInvocationID("ABC123")
+	SetClient(Client{Version: "1.4.0", CI: true, Suffix: " acme-bootstrap/2.1\r\n(é) "})
+	if got, want := UserAgent(), "dev-vault/1.4.0 ("+platform+"; ci=true) invocation/ABC123 acme-bootstrap/2.1__(_)"; got != want {
+		t.Fatalf("unexpected user agent:\n got %q\nwant %q", got, want)
+	}
+}
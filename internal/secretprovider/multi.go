@@ -0,0 +1,108 @@
+package secretprovider
+
+import (
+	"fmt"
+	"sync"
+)
+
+// ProfileAPI is a SecretAPI opened with one named set of credentials.
+type ProfileAPI struct {
+	Profile string
+	API     SecretAPI
+}
+
+// MultiProfileAPI runs one invocation against several profiles, for secrets
+// split across organizations. Listings query every profile concurrently and
+// label each record with its profile; a lookup by name resolves to the first
+// profile, in the given order, that has a match. Calls by secret ID go to the
+// profile that listed the secret, and new secrets are created with the first.
+type MultiProfileAPI struct {
+	profiles []ProfileAPI
+
+	mu     sync.Mutex
+	owners map[string]int
+}
+
+func NewMultiProfileAPI(profiles []ProfileAPI) *MultiProfileAPI {
+	return &MultiProfileAPI{profiles: profiles, owners: make(map[string]int)}
+}
+
+func (m *MultiProfileAPI) ListSecrets(req ListSecretsInput) ([]SecretRecord, error) {
+	results := make([][]SecretRecord, len(m.profiles))
+	errs := make([]error, len(m.profiles))
+	var wg sync.WaitGroup
+	for i, profile := range m.profiles {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], errs[i] = profile.API.ListSecrets(req)
+		}()
+	}
+	wg.Wait()
+
+	var out []SecretRecord
+	for i, profile := range m.profiles {
+		if errs[i] != nil {
+			return nil, fmt.Errorf("profile %s: %w", profile.Profile, errs[i])
+		}
+		if req.Name != "" && len(out) > 0 {
+			continue
+		}
+		for _, record := range results[i] {
+			record.Profile = profile.Profile
+			m.own(record.ID, i)
+			out = append(out, record)
+		}
+	}
+	return out, nil
+}
+
+func (m *MultiProfileAPI) AccessSecretVersion(req AccessSecretVersionInput) (*SecretVersionRecord, error) {
+	return m.profiles[m.owner(req.SecretID)].API.AccessSecretVersion(req)
+}
+
+func (m *MultiProfileAPI) ListSecretVersions(req ListSecretVersionsInput) ([]SecretVersionRecord, error) {
+	return m.profiles[m.owner(req.SecretID)].API.ListSecretVersions(req)
+}
+
+func (m *MultiProfileAPI) CreateSecret(req CreateSecretInput) (*SecretRecord, error) {
+	primary := m.profiles[0]
+	record, err := primary.API.CreateSecret(req)
+	if err != nil {
+		return nil, err
+	}
+	record.Profile = primary.Profile
+	m.own(record.ID, 0)
+	return record, nil
+}
+
+func (m *MultiProfileAPI) CreateSecretVersion(req CreateSecretVersionInput) (*SecretVersionRecord, error) {
+	return m.profiles[m.owner(req.SecretID)].API.CreateSecretVersion(req)
+}
+
+func (m *MultiProfileAPI) DeleteSecret(req DeleteSecretInput) error {
+	return m.profiles[m.owner(req.SecretID)].API.DeleteSecret(req)
+}
+
+func (m *MultiProfileAPI) DeleteSecretVersion(req DeleteSecretVersionInput) error {
+	return m.profiles[m.owner(req.SecretID)].API.DeleteSecretVersion(req)
+}
+
+func (m *MultiProfileAPI) DisableSecretVersion(req DisableSecretVersionInput) error {
+	return m.profiles[m.owner(req.SecretID)].API.DisableSecretVersion(req)
+}
+
+func (m *MultiProfileAPI) own(secretID string, profile int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.owners[secretID]; !ok {
+		m.owners[secretID] = profile
+	}
+}
+
+// owner falls back to the first profile for secrets no listing returned.
+func (m *MultiProfileAPI) owner(secretID string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.owners[secretID]
+}
@@ -0,0 +1,124 @@
+package secretprovider
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+type profileFake struct {
+	secrets  []SecretRecord
+	listErr  error
+	accessed []string
+	created  []string
+	versions []string
+	deleted  []string
+	disabled []string
+}
+
+func (f *profileFake) ListSecrets(req ListSecretsInput) ([]SecretRecord, error) {
+	var out []SecretRecord
+	for _, record := range f.secrets {
+		if req.Name == "" || record.Name == req.Name {
+			out = append(out, record)
+		}
+	}
+	return out, f.listErr
+}
+
+func (f *profileFake) AccessSecretVersion(req AccessSecretVersionInput) (*SecretVersionRecord, error) {
+	f.accessed = append(f.accessed, req.SecretID)
+	if req.SecretID == "missing" {
+		return nil, errors.New("access boom")
+	}
+	return &SecretVersionRecord{SecretID: req.SecretID, Data: []byte(req.SecretID)}, nil
+}
+
+func (f *profileFake) ListSecretVersions(req ListSecretVersionsInput) ([]SecretVersionRecord, error) {
+	f.versions = append(f.versions, req.SecretID)
+	return []SecretVersionRecord{{SecretID: req.SecretID, Revision: 1}}, nil
+}
+
+func (f *profileFake) CreateSecret(req CreateSecretInput) (*SecretRecord, error) {
+	if req.Name == "fail-dev" {
+		return nil, errors.New("create boom")
+	}
+	return &SecretRecord{ID: "new-" + req.Name, Name: req.Name}, nil
+}
+
+func (f *profileFake) CreateSecretVersion(req CreateSecretVersionInput) (*SecretVersionRecord, error) {
+	f.created = append(f.created, req.SecretID)
+	return &SecretVersionRecord{SecretID: req.SecretID}, nil
+}
+
+func (f *profileFake) DeleteSecret(req DeleteSecretInput) error {
+	f.deleted = append(f.deleted, req.SecretID)
+	return nil
+}
+
+func (f *profileFake) DeleteSecretVersion(req DeleteSecretVersionInput) error {
+	f.deleted = append(f.deleted, fmt.Sprintf("%s@%d", req.SecretID, req.Revision))
+	return nil
+}
+
+func (f *profileFake) DisableSecretVersion(req DisableSecretVersionInput) error {
+	f.disabled = append(f.disabled, fmt.Sprintf("%s@%d", req.SecretID, req.Revision))
+	return nil
+}
+
+func TestMultiProfileAPI(t *testing.T) {
+	home := &profileFake{secrets: []SecretRecord{{ID: "h1", Name: "shared-dev"}}}
+	client := &profileFake{secrets: []SecretRecord{{ID: "c1", Name: "shared-dev"}, {ID: "c2", Name: "client-dev"}}}
+	api := NewMultiProfileAPI([]ProfileAPI{{Profile: "home", API: home}, {Profile: "client", API: client}})
+
+	all, err := api.ListSecrets(ListSecretsInput{})
+	if err != nil || len(all) != 3 || all[0].Profile != "home" || all[1].Profile != "client" || all[2].ID != "c2" {
+		t.Fatalf("unexpected listing: %#v %v", all, err)
+	}
+	// A lookup by name stops at the first profile with a match.
+	shared, err := api.ListSecrets(ListSecretsInput{Name: "shared-dev"})
+	if err != nil || len(shared) != 1 || shared[0].ID != "h1" {
+		t.Fatalf("unexpected shared lookup: %#v %v", shared, err)
+	}
+	if own, _ := api.ListSecrets(ListSecretsInput{Name: "client-dev"}); len(own) != 1 || own[0].Profile != "client" {
+		t.Fatalf("unexpected client lookup: %#v", own)
+	}
+
+	for _, id := range []string{"h1", "c2", "unlisted"} {
+		if _, err := api.AccessSecretVersion(AccessSecretVersionInput{SecretID: id}); err != nil {
+			t.Fatalf("access %s: %v", id, err)
+		}
+		if _, err := api.CreateSecretVersion(CreateSecretVersionInput{SecretID: id}); err != nil {
+			t.Fatalf("create version %s: %v", id, err)
+		}
+		if _, err := api.ListSecretVersions(ListSecretVersionsInput{SecretID: id}); err != nil {
+			t.Fatalf("list versions %s: %v", id, err)
+		}
+		if err := api.DeleteSecretVersion(DeleteSecretVersionInput{SecretID: id, Revision: 1}); err != nil {
+			t.Fatalf("delete version %s: %v", id, err)
+		}
+		if err := api.DisableSecretVersion(DisableSecretVersionInput{SecretID: id, Revision: 1}); err != nil {
+			t.Fatalf("disable version %s: %v", id, err)
+		}
+		if err := api.DeleteSecret(DeleteSecretInput{SecretID: id}); err != nil {
+			t.Fatalf("delete %s: %v", id, err)
+		}
+	}
+	if strings.Join(home.accessed, ",") != "h1,unlisted" || strings.Join(client.accessed, ",") != "c2" || strings.Join(client.created, ",") != "c2" || strings.Join(client.versions, ",") != "c2" || strings.Join(client.deleted, ",") != "c2@1,c2" || strings.Join(client.disabled, ",") != "c2@1" {
+		t.Fatalf("unexpected routing: home=%v client=%v/%v/%v/%v/%v", home.accessed, client.accessed, client.created, client.versions, client.deleted, client.disabled)
+	}
+
+	created, err := api.CreateSecret(CreateSecretInput{Name: "new-dev"})
+	if err != nil || created.Profile != "home" {
+		t.Fatalf("unexpected create: %#v %v", created, err)
+	}
+	if _, err := api.CreateSecret(CreateSecretInput{Name: "fail-dev"}); err == nil {
+		t.Fatal("expected create error")
+	}
+
+	client.listErr = errors.New("denied")
+	if _, err := api.ListSecrets(ListSecretsInput{}); err == nil || err.Error() != "profile client: denied" {
+		t.Fatalf("expected profile error, got %v", err)
+	}
+}
@@ -0,0 +1,64 @@
+package scaleway
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bsmartlabs/dev-vault/internal/secretprovider"
+	account "github.com/scaleway/scaleway-sdk-go/api/account/v3"
+	secret "github.com/scaleway/scaleway-sdk-go/api/secret/v1beta1"
+	"github.com/scaleway/scaleway-sdk-go/scw"
+)
+
+// OpenAccount builds an account-level client that does not need a .scw.json,
+// so it can be used to discover the values that go into one.
+func OpenAccount(profileOverride string) (secretprovider.AccountAPI, error) {
+	opts, err := clientOptions(strings.TrimSpace(profileOverride))
+	if err != nil {
+		return nil, err
+	}
+	client, err := scw.NewClient(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("create scaleway client: %w", err)
+	}
+	return &AccountAPI{projects: account.NewProjectAPI(client)}, nil
+}
+
+type AccountAPI struct {
+	projects scalewayProjectSDK
+}
+
+type scalewayProjectSDK interface {
+	ListProjects(req *account.ProjectAPIListProjectsRequest, opts ...scw.RequestOption) (*account.ListProjectsResponse, error)
+}
+
+func (a *AccountAPI) ListProjects(req secretprovider.ListProjectsInput) ([]secretprovider.ProjectRecord, error) {
+	resp, err := a.projects.ListProjects(&account.ProjectAPIListProjectsRequest{
+		OrganizationID: req.OrganizationID,
+	}, scw.WithAllPages())
+	if err != nil {
+		return nil, fmt.Errorf("list projects: %w", err)
+	}
+	out := make([]secretprovider.ProjectRecord, 0, len(resp.Projects))
+	for _, item := range resp.Projects {
+		if item == nil {
+			continue
+		}
+		out = append(out, secretprovider.ProjectRecord{
+			ID:             item.ID,
+			Name:           item.Name,
+			OrganizationID: item.OrganizationID,
+		})
+	}
+	return out, nil
+}
+
+// Regions reports the regions where Secret Manager is available.
+func (a *AccountAPI) Regions() []string {
+	regions := secret.NewAPI(nil).Regions()
+	out := make([]string, 0, len(regions))
+	for _, region := range regions {
+		out = append(out, string(region))
+	}
+	return out
+}
@@ -0,0 +1,75 @@
+package scaleway
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/bsmartlabs/dev-vault/internal/secretprovider"
+	account "github.com/scaleway/scaleway-sdk-go/api/account/v3"
+	"github.com/scaleway/scaleway-sdk-go/scw"
+)
+
+type fakeProjectSDK struct {
+	listFn func(*account.ProjectAPIListProjectsRequest, ...scw.RequestOption) (*account.ListProjectsResponse, error)
+}
+
+func (f *fakeProjectSDK) ListProjects(req *account.ProjectAPIListProjectsRequest, opts ...scw.RequestOption) (*account.ListProjectsResponse, error) {
+	return f.listFn(req, opts...)
+}
+
+func TestOpenAccount(t *testing.T) {
+	t.Run("Env", func(t *testing.T) {
+		t.Setenv("SCW_ACCESS_KEY", "SCW1234567890ABCDEFG")                 // gitleaks:allow
+		t.Setenv("SCW_SECRET_KEY", "00000000-0000-0000-0000-000000000000") // gitleaks:allow
+		if _, err := OpenAccount(""); err != nil {
+			t.Fatalf("expected success, got %v", err)
+		}
+	})
+
+	t.Run("NewClientError", func(t *testing.T) {
+		t.Setenv("SCW_DEFAULT_ORGANIZATION_ID", "not-a-uuid")
+		if _, err := OpenAccount(""); err == nil {
+			t.Fatal("expected client error")
+		}
+	})
+
+	t.Run("ProfileError", func(t *testing.T) {
+		t.Setenv("SCW_CONFIG_PATH", filepath.Join(t.TempDir(), "missing.yaml"))
+		if _, err := OpenAccount("p1"); err == nil {
+			t.Fatal("expected profile error")
+		}
+	})
+}
+
+func TestAccountAPI_ListProjects(t *testing.T) {
+	api := &AccountAPI{projects: &fakeProjectSDK{listFn: func(req *account.ProjectAPIListProjectsRequest, opts ...scw.RequestOption) (*account.ListProjectsResponse, error) {
+		if req.OrganizationID != "org" || len(opts) != 1 {
+			t.Fatalf("unexpected request: %#v opts=%d", req, len(opts))
+		}
+		return &account.ListProjectsResponse{Projects: []*account.Project{nil, {ID: "p1", Name: "api", OrganizationID: "org"}}}, nil
+	}}}
+	got, err := api.ListProjects(secretprovider.ListProjectsInput{OrganizationID: "org"})
+	if err != nil {
+		t.Fatalf("ListProjects: %v", err)
+	}
+	want := []secretprovider.ProjectRecord{{ID: "p1", Name: "api", OrganizationID: "org"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected projects: %#v", got)
+	}
+
+	api.projects = &fakeProjectSDK{listFn: func(*account.ProjectAPIListProjectsRequest, ...scw.RequestOption) (*account.ListProjectsResponse, error) {
+		return nil, errors.New("boom")
+	}}
+	if _, err := api.ListProjects(secretprovider.ListProjectsInput{}); err == nil {
+		t.Fatal("expected list error")
+	}
+}
+
+func TestAccountAPI_Regions(t *testing.T) {
+	got := (&AccountAPI{}).Regions()
+	if len(got) == 0 || got[0] != string(scw.RegionFrPar) {
+		t.Fatalf("unexpected regions: %#v", got)
+	}
+}
@@ -1,8 +1,12 @@
 package scaleway
 
 import (
+	"errors"
 	"fmt"
+	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/bsmartlabs/dev-vault/internal/config"
 	"github.com/bsmartlabs/dev-vault/internal/secretprovider"
@@ -22,8 +26,45 @@ func Open(cfg config.Config, profileOverride string) (secretprovider.SecretAPI,
 		return nil, fmt.Errorf("invalid region %q: %w", cfg.Region, err)
 	}
 
+	opts, err := clientOptions(profileName)
+	if err != nil {
+		return nil, err
+	}
+	// A config without organization and project (a secondary profile of a
+	// multi-profile run) keeps the ones the profile itself defaults to.
+	if cfg.OrganizationID != "" {
+		opts = append(opts, scw.WithDefaultOrganizationID(cfg.OrganizationID))
+	}
+	if cfg.ProjectID != "" {
+		opts = append(opts, scw.WithDefaultProjectID(cfg.ProjectID))
+	}
+	opts = append(opts, scw.WithDefaultRegion(region))
+
+	client, err := scw.NewClient(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("create scaleway client: %w", err)
+	}
+	projectID := cfg.ProjectID
+	if projectID == "" {
+		defaultProjectID, ok := client.GetDefaultProjectID()
+		if !ok {
+			return nil, fmt.Errorf("profile %q sets no default_project_id", profileName)
+		}
+		projectID = defaultProjectID
+	}
+
+	return &API{
+		api:              secret.NewAPI(client),
+		defaultRegion:    cfg.Region,
+		defaultProjectID: projectID,
+	}, nil
+}
+
+func clientOptions(profileName string) ([]scw.ClientOption, error) {
 	// Keep precedence explicit: env defaults first, profile override last.
-	opts := []scw.ClientOption{scw.WithEnv()}
+	// The SDK appends the user agent to its own, so Scaleway's logs can be
+	// matched with a dev-vault invocation.
+	opts := []scw.ClientOption{scw.WithEnv(), scw.WithUserAgent(secretprovider.UserAgent())}
 	if profileName != "" {
 		scwCfg, err := scw.LoadConfig()
 		if err != nil {
@@ -35,23 +76,7 @@ func Open(cfg config.Config, profileOverride string) (secretprovider.SecretAPI,
 		}
 		opts = append(opts, scw.WithProfile(prof))
 	}
-
-	opts = append(opts,
-		scw.WithDefaultOrganizationID(cfg.OrganizationID),
-		scw.WithDefaultProjectID(cfg.ProjectID),
-		scw.WithDefaultRegion(region),
-	)
-
-	client, err := scw.NewClient(opts...)
-	if err != nil {
-		return nil, fmt.Errorf("create scaleway client: %w", err)
-	}
-
-	return &API{
-		api:              secret.NewAPI(client),
-		defaultRegion:    cfg.Region,
-		defaultProjectID: cfg.ProjectID,
-	}, nil
+	return opts, nil
 }
 
 type API struct {
@@ -63,8 +88,12 @@ type API struct {
 type scalewaySecretSDK interface {
 	ListSecrets(req *secret.ListSecretsRequest, opts ...scw.RequestOption) (*secret.ListSecretsResponse, error)
 	AccessSecretVersion(req *secret.AccessSecretVersionRequest, opts ...scw.RequestOption) (*secret.AccessSecretVersionResponse, error)
+	ListSecretVersions(req *secret.ListSecretVersionsRequest, opts ...scw.RequestOption) (*secret.ListSecretVersionsResponse, error)
 	CreateSecret(req *secret.CreateSecretRequest, opts ...scw.RequestOption) (*secret.Secret, error)
 	CreateSecretVersion(req *secret.CreateSecretVersionRequest, opts ...scw.RequestOption) (*secret.SecretVersion, error)
+	DeleteSecret(req *secret.DeleteSecretRequest, opts ...scw.RequestOption) error
+	DeleteSecretVersion(req *secret.DeleteSecretVersionRequest, opts ...scw.RequestOption) error
+	DisableSecretVersion(req *secret.DisableSecretVersionRequest, opts ...scw.RequestOption) (*secret.SecretVersion, error)
 }
 
 func (s *API) ListSecrets(req secretprovider.ListSecretsInput) ([]secretprovider.SecretRecord, error) {
@@ -85,6 +114,7 @@ func (s *API) ListSecrets(req secretprovider.ListSecretsInput) ([]secretprovider
 		}
 		listReq.Type = secretType
 	}
+	// The API matches names exactly, so NamePrefix is left to the caller.
 	if req.Name != "" {
 		listReq.Name = scw.StringPtr(req.Name)
 	}
@@ -94,7 +124,7 @@ func (s *API) ListSecrets(req secretprovider.ListSecretsInput) ([]secretprovider
 
 	resp, err := s.api.ListSecrets(listReq, scw.WithAllPages())
 	if err != nil {
-		return nil, fmt.Errorf("list secrets: %w", err)
+		return nil, wrapError("list secrets", err)
 	}
 	out := make([]secretprovider.SecretRecord, 0, len(resp.Secrets))
 	for _, item := range resp.Secrets {
@@ -107,6 +137,8 @@ func (s *API) ListSecrets(req secretprovider.ListSecretsInput) ([]secretprovider
 			Name:      item.Name,
 			Path:      item.Path,
 			Type:      secretprovider.SecretType(item.Type),
+			Tags:      item.Tags,
+			UpdatedAt: timeValue(item.UpdatedAt),
 		})
 	}
 	return out, nil
@@ -123,7 +155,7 @@ func (s *API) AccessSecretVersion(req secretprovider.AccessSecretVersionInput) (
 		Revision: string(req.Revision),
 	})
 	if err != nil {
-		return nil, fmt.Errorf("access secret version: %w", err)
+		return nil, wrapError("access secret version", err)
 	}
 	return &secretprovider.SecretVersionRecord{
 		SecretID: resp.SecretID,
@@ -133,6 +165,37 @@ func (s *API) AccessSecretVersion(req secretprovider.AccessSecretVersionInput) (
 	}, nil
 }
 
+func (s *API) ListSecretVersions(req secretprovider.ListSecretVersionsInput) ([]secretprovider.SecretVersionRecord, error) {
+	region, err := scw.ParseRegion(s.resolveRegion(req.Region))
+	if err != nil {
+		return nil, fmt.Errorf("parse region %q: %w", s.resolveRegion(req.Region), err)
+	}
+	resp, err := s.api.ListSecretVersions(&secret.ListSecretVersionsRequest{
+		Region:   region,
+		SecretID: req.SecretID,
+	}, scw.WithAllPages())
+	if err != nil {
+		return nil, wrapError("list secret versions", err)
+	}
+	out := make([]secretprovider.SecretVersionRecord, 0, len(resp.Versions))
+	for _, item := range resp.Versions {
+		if item == nil {
+			continue
+		}
+		record := secretprovider.SecretVersionRecord{
+			SecretID:  item.SecretID,
+			Revision:  item.Revision,
+			Status:    string(item.Status),
+			CreatedAt: timeValue(item.CreatedAt),
+		}
+		if item.Description != nil {
+			record.Description = *item.Description
+		}
+		out = append(out, record)
+	}
+	return out, nil
+}
+
 func (s *API) CreateSecret(req secretprovider.CreateSecretInput) (*secretprovider.SecretRecord, error) {
 	region, err := scw.ParseRegion(s.resolveRegion(req.Region))
 	if err != nil {
@@ -159,7 +222,7 @@ func (s *API) CreateSecret(req secretprovider.CreateSecretInput) (*secretprovide
 		KeyID:       nil,
 	})
 	if err != nil {
-		return nil, fmt.Errorf("create secret: %w", err)
+		return nil, wrapError("create secret", err)
 	}
 	return &secretprovider.SecretRecord{
 		ID:        resp.ID,
@@ -167,6 +230,8 @@ func (s *API) CreateSecret(req secretprovider.CreateSecretInput) (*secretprovide
 		Name:      resp.Name,
 		Path:      resp.Path,
 		Type:      secretprovider.SecretType(resp.Type),
+		Tags:      resp.Tags,
+		UpdatedAt: timeValue(resp.UpdatedAt),
 	}, nil
 }
 
@@ -183,7 +248,7 @@ func (s *API) CreateSecretVersion(req secretprovider.CreateSecretVersionInput) (
 		DisablePrevious: req.DisablePrevious,
 	})
 	if err != nil {
-		return nil, fmt.Errorf("create secret version: %w", err)
+		return nil, wrapError("create secret version", err)
 	}
 	return &secretprovider.SecretVersionRecord{
 		SecretID: resp.SecretID,
@@ -192,6 +257,61 @@ func (s *API) CreateSecretVersion(req secretprovider.CreateSecretVersionInput) (
 	}, nil
 }
 
+func (s *API) DeleteSecret(req secretprovider.DeleteSecretInput) error {
+	region, err := scw.ParseRegion(s.resolveRegion(req.Region))
+	if err != nil {
+		return fmt.Errorf("parse region %q: %w", s.resolveRegion(req.Region), err)
+	}
+	if err := s.api.DeleteSecret(&secret.DeleteSecretRequest{
+		Region:   region,
+		SecretID: req.SecretID,
+	}); err != nil {
+		return wrapError("delete secret", err)
+	}
+	return nil
+}
+
+func (s *API) DeleteSecretVersion(req secretprovider.DeleteSecretVersionInput) error {
+	region, err := scw.ParseRegion(s.resolveRegion(req.Region))
+	if err != nil {
+		return fmt.Errorf("parse region %q: %w", s.resolveRegion(req.Region), err)
+	}
+	if err := s.api.DeleteSecretVersion(&secret.DeleteSecretVersionRequest{
+		Region:   region,
+		SecretID: req.SecretID,
+		Revision: strconv.FormatUint(uint64(req.Revision), 10),
+	}); err != nil {
+		return wrapError("delete secret version", err)
+	}
+	return nil
+}
+
+func (s *API) DisableSecretVersion(req secretprovider.DisableSecretVersionInput) error {
+	region, err := scw.ParseRegion(s.resolveRegion(req.Region))
+	if err != nil {
+		return fmt.Errorf("parse region %q: %w", s.resolveRegion(req.Region), err)
+	}
+	if _, err := s.api.DisableSecretVersion(&secret.DisableSecretVersionRequest{
+		Region:   region,
+		SecretID: req.SecretID,
+		Revision: strconv.FormatUint(uint64(req.Revision), 10),
+	}); err != nil {
+		return wrapError("disable secret version", err)
+	}
+	return nil
+}
+
+// wrapError marks SDK errors for calls the credentials may not make with
+// secretprovider.ErrPermissionDenied.
+func wrapError(op string, err error) error {
+	var denied *scw.PermissionsDeniedError
+	var response *scw.ResponseError
+	if errors.As(err, &denied) || (errors.As(err, &response) && response.StatusCode == http.StatusForbidden) {
+		return fmt.Errorf("%s: %w: %w", op, secretprovider.ErrPermissionDenied, err)
+	}
+	return fmt.Errorf("%s: %w", op, err)
+}
+
 func toScalewaySecretType(name secretprovider.SecretType) (secret.SecretType, error) {
 	return secrettype.ToScaleway(string(name))
 }
@@ -209,3 +329,10 @@ func (s *API) resolveProjectID(explicit string) string {
 	}
 	return s.defaultProjectID
 }
+
+func timeValue(t *time.Time) time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+	return *t
+}
@@ -2,7 +2,10 @@ package scaleway
 
 import (
 	"errors"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/bsmartlabs/dev-vault/internal/config"
 	"github.com/bsmartlabs/dev-vault/internal/secretprovider"
@@ -13,8 +16,12 @@ import (
 type fakeScalewaySDK struct {
 	listFn          func(*secret.ListSecretsRequest, ...scw.RequestOption) (*secret.ListSecretsResponse, error)
 	accessFn        func(*secret.AccessSecretVersionRequest, ...scw.RequestOption) (*secret.AccessSecretVersionResponse, error)
+	listVersionsFn  func(*secret.ListSecretVersionsRequest, ...scw.RequestOption) (*secret.ListSecretVersionsResponse, error)
 	createSecretFn  func(*secret.CreateSecretRequest, ...scw.RequestOption) (*secret.Secret, error)
 	createVersionFn func(*secret.CreateSecretVersionRequest, ...scw.RequestOption) (*secret.SecretVersion, error)
+	deleteFn        func(*secret.DeleteSecretRequest, ...scw.RequestOption) error
+	deleteVersionFn func(*secret.DeleteSecretVersionRequest, ...scw.RequestOption) error
+	disableFn       func(*secret.DisableSecretVersionRequest, ...scw.RequestOption) (*secret.SecretVersion, error)
 }
 
 func (f *fakeScalewaySDK) ListSecrets(req *secret.ListSecretsRequest, opts ...scw.RequestOption) (*secret.ListSecretsResponse, error) {
@@ -25,6 +32,10 @@ func (f *fakeScalewaySDK) AccessSecretVersion(req *secret.AccessSecretVersionReq
 	return f.accessFn(req, opts...)
 }
 
+func (f *fakeScalewaySDK) ListSecretVersions(req *secret.ListSecretVersionsRequest, opts ...scw.RequestOption) (*secret.ListSecretVersionsResponse, error) {
+	return f.listVersionsFn(req, opts...)
+}
+
 func (f *fakeScalewaySDK) CreateSecret(req *secret.CreateSecretRequest, opts ...scw.RequestOption) (*secret.Secret, error) {
 	return f.createSecretFn(req, opts...)
 }
@@ -33,6 +44,18 @@ func (f *fakeScalewaySDK) CreateSecretVersion(req *secret.CreateSecretVersionReq
 	return f.createVersionFn(req, opts...)
 }
 
+func (f *fakeScalewaySDK) DeleteSecret(req *secret.DeleteSecretRequest, opts ...scw.RequestOption) error {
+	return f.deleteFn(req, opts...)
+}
+
+func (f *fakeScalewaySDK) DeleteSecretVersion(req *secret.DeleteSecretVersionRequest, opts ...scw.RequestOption) error {
+	return f.deleteVersionFn(req, opts...)
+}
+
+func (f *fakeScalewaySDK) DisableSecretVersion(req *secret.DisableSecretVersionRequest, opts ...scw.RequestOption) (*secret.SecretVersion, error) {
+	return f.disableFn(req, opts...)
+}
+
 func TestOpen_InvalidRegionSmoke(t *testing.T) {
 	_, err := Open(config.Config{
 		OrganizationID: "00000000-0000-0000-0000-000000000000",
@@ -74,6 +97,7 @@ func TestScalewaySecretAPI_ListSecrets(t *testing.T) {
 	})
 
 	t.Run("Success", func(t *testing.T) {
+		updatedAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
 		api := &API{api: &fakeScalewaySDK{
 			listFn: func(req *secret.ListSecretsRequest, _ ...scw.RequestOption) (*secret.ListSecretsResponse, error) {
 				if req.ProjectID == nil || *req.ProjectID != "p" {
@@ -87,7 +111,7 @@ func TestScalewaySecretAPI_ListSecrets(t *testing.T) {
 				}
 				return &secret.ListSecretsResponse{Secrets: []*secret.Secret{
 					nil,
-					{ID: "s1", Name: "name-dev", Path: "/", ProjectID: "p", Type: secret.SecretTypeOpaque},
+					{ID: "s1", Name: "name-dev", Path: "/", ProjectID: "p", Type: secret.SecretTypeOpaque, Tags: []string{"owner:web"}, UpdatedAt: &updatedAt},
 				}}, nil
 			},
 		}}
@@ -101,7 +125,7 @@ func TestScalewaySecretAPI_ListSecrets(t *testing.T) {
 		if err != nil {
 			t.Fatalf("ListSecrets: %v", err)
 		}
-		if len(out) != 1 || out[0].Type != secretprovider.SecretTypeOpaque {
+		if len(out) != 1 || out[0].Type != secretprovider.SecretTypeOpaque || !out[0].UpdatedAt.Equal(updatedAt) || len(out[0].Tags) != 1 {
 			t.Fatalf("unexpected output: %#v", out)
 		}
 	})
@@ -179,6 +203,55 @@ func TestScalewaySecretAPI_AccessSecretVersion(t *testing.T) {
 	})
 }
 
+func TestScalewaySecretAPI_ListSecretVersions(t *testing.T) {
+	t.Run("InvalidRegion", func(t *testing.T) {
+		api := &API{api: &fakeScalewaySDK{}}
+		_, err := api.ListSecretVersions(secretprovider.ListSecretVersionsInput{Region: "bad"})
+		if err == nil {
+			t.Fatal("expected error")
+		}
+	})
+
+	t.Run("APIError", func(t *testing.T) {
+		api := &API{api: &fakeScalewaySDK{
+			listVersionsFn: func(*secret.ListSecretVersionsRequest, ...scw.RequestOption) (*secret.ListSecretVersionsResponse, error) {
+				return nil, errors.New("boom")
+			},
+		}}
+		_, err := api.ListSecretVersions(secretprovider.ListSecretVersionsInput{Region: "fr-par", SecretID: "s1"})
+		if err == nil || !strings.Contains(err.Error(), "list secret versions: boom") {
+			t.Fatalf("expected error, got %v", err)
+		}
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		createdAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+		api := &API{api: &fakeScalewaySDK{
+			listVersionsFn: func(req *secret.ListSecretVersionsRequest, opts ...scw.RequestOption) (*secret.ListSecretVersionsResponse, error) {
+				if req.SecretID != "s1" || req.Region != scw.RegionFrPar || len(opts) != 1 {
+					t.Fatalf("unexpected request: %#v %d", req, len(opts))
+				}
+				return &secret.ListSecretVersionsResponse{Versions: []*secret.SecretVersion{
+					{SecretID: "s1", Revision: 1, Status: secret.SecretVersionStatusDisabled, CreatedAt: &createdAt, Description: scw.StringPtr("first")},
+					nil,
+					{SecretID: "s1", Revision: 2, Status: secret.SecretVersionStatusEnabled},
+				}}, nil
+			},
+		}}
+		out, err := api.ListSecretVersions(secretprovider.ListSecretVersionsInput{Region: "fr-par", SecretID: "s1"})
+		if err != nil {
+			t.Fatalf("ListSecretVersions: %v", err)
+		}
+		want := []secretprovider.SecretVersionRecord{
+			{SecretID: "s1", Revision: 1, Status: "disabled", CreatedAt: createdAt, Description: "first"},
+			{SecretID: "s1", Revision: 2, Status: "enabled"},
+		}
+		if !reflect.DeepEqual(out, want) {
+			t.Fatalf("unexpected output: %#v", out)
+		}
+	})
+}
+
 func TestScalewaySecretAPI_CreateSecret(t *testing.T) {
 	t.Run("InvalidRegion", func(t *testing.T) {
 		api := &API{api: &fakeScalewaySDK{}}
@@ -293,6 +366,92 @@ func TestScalewaySecretAPI_CreateSecretVersion(t *testing.T) {
 	})
 }
 
+func TestScalewaySecretAPI_Delete(t *testing.T) {
+	var calls []string
+	api := &API{defaultRegion: "fr-par", api: &fakeScalewaySDK{
+		deleteFn: func(req *secret.DeleteSecretRequest, _ ...scw.RequestOption) error {
+			calls = append(calls, string(req.Region)+" "+req.SecretID)
+			if req.SecretID == "fail" {
+				return &scw.PermissionsDeniedError{}
+			}
+			return nil
+		},
+		deleteVersionFn: func(req *secret.DeleteSecretVersionRequest, _ ...scw.RequestOption) error {
+			calls = append(calls, string(req.Region)+" "+req.SecretID+"@"+req.Revision)
+			if req.SecretID == "fail" {
+				return errors.New("boom")
+			}
+			return nil
+		},
+	}}
+
+	if err := api.DeleteSecret(secretprovider.DeleteSecretInput{SecretID: "s1"}); err != nil {
+		t.Fatalf("DeleteSecret: %v", err)
+	}
+	if err := api.DeleteSecretVersion(secretprovider.DeleteSecretVersionInput{SecretID: "s1", Revision: 3}); err != nil {
+		t.Fatalf("DeleteSecretVersion: %v", err)
+	}
+	if err := api.DeleteSecret(secretprovider.DeleteSecretInput{SecretID: "fail"}); !errors.Is(err, secretprovider.ErrPermissionDenied) {
+		t.Fatalf("expected permission error, got %v", err)
+	}
+	if err := api.DeleteSecretVersion(secretprovider.DeleteSecretVersionInput{SecretID: "fail", Revision: 1}); err == nil || err.Error() != "delete secret version: boom" {
+		t.Fatalf("expected error, got %v", err)
+	}
+	if !reflect.DeepEqual(calls, []string{"fr-par s1", "fr-par s1@3", "fr-par fail", "fr-par fail@1"}) {
+		t.Fatalf("unexpected calls: %v", calls)
+	}
+
+	if err := api.DeleteSecret(secretprovider.DeleteSecretInput{Region: "bad"}); err == nil {
+		t.Fatal("expected region error")
+	}
+	if err := api.DeleteSecretVersion(secretprovider.DeleteSecretVersionInput{Region: "bad"}); err == nil {
+		t.Fatal("expected region error")
+	}
+}
+
+func TestScalewaySecretAPI_DisableSecretVersion(t *testing.T) {
+	var calls []string
+	api := &API{defaultRegion: "fr-par", api: &fakeScalewaySDK{
+		disableFn: func(req *secret.DisableSecretVersionRequest, _ ...scw.RequestOption) (*secret.SecretVersion, error) {
+			calls = append(calls, string(req.Region)+" "+req.SecretID+"@"+req.Revision)
+			if req.SecretID == "fail" {
+				return nil, &scw.PermissionsDeniedError{}
+			}
+			return &secret.SecretVersion{}, nil
+		},
+	}}
+
+	if err := api.DisableSecretVersion(secretprovider.DisableSecretVersionInput{SecretID: "s1", Revision: 2}); err != nil {
+		t.Fatalf("DisableSecretVersion: %v", err)
+	}
+	if err := api.DisableSecretVersion(secretprovider.DisableSecretVersionInput{SecretID: "fail", Revision: 1}); !errors.Is(err, secretprovider.ErrPermissionDenied) || !strings.HasPrefix(err.Error(), "disable secret version: ") {
+		t.Fatalf("expected permission error, got %v", err)
+	}
+	if !reflect.DeepEqual(calls, []string{"fr-par s1@2", "fr-par fail@1"}) {
+		t.Fatalf("unexpected calls: %v", calls)
+	}
+	if err := api.DisableSecretVersion(secretprovider.DisableSecretVersionInput{Region: "bad"}); err == nil {
+		t.Fatal("expected region error")
+	}
+}
+
+func TestWrapError(t *testing.T) {
+	for _, tc := range []struct {
+		err    error
+		denied bool
+	}{
+		{&scw.PermissionsDeniedError{}, true},
+		{&scw.ResponseError{StatusCode: 403}, true},
+		{&scw.ResponseError{StatusCode: 404}, false},
+		{errors.New("boom"), false},
+	} {
+		err := wrapError("create secret version", tc.err)
+		if errors.Is(err, secretprovider.ErrPermissionDenied) != tc.denied || !errors.Is(err, tc.err) || !strings.HasPrefix(err.Error(), "create secret version: ") {
+			t.Fatalf("%T: unexpected wrap %v", tc.err, err)
+		}
+	}
+}
+
 func TestAPI_ResolveRegion(t *testing.T) {
 	api := &API{defaultRegion: "fr-par"}
 	if got := api.resolveRegion(""); got != "fr-par" {
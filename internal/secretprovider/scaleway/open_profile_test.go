@@ -152,4 +152,35 @@ profiles:
 			t.Fatalf("expected success, got %v", err)
 		}
 	})
+
+	t.Run("SecondaryProfileUsesItsProject", func(t *testing.T) {
+		cfgPath := filepath.Join(t.TempDir(), "config.yaml")
+		yaml := strings.TrimSpace(`
+access_key: SCW1234567890ABCDEFG # gitleaks:allow
+secret_key: 00000000-0000-0000-0000-000000000000 # gitleaks:allow
+profiles:
+  client:
+    access_key: SCW234567890ABCDEFGH # gitleaks:allow
+    secret_key: 22222222-2222-2222-2222-222222222222 # gitleaks:allow
+    default_organization_id: 22222222-2222-2222-2222-222222222222
+    default_project_id: 33333333-3333-3333-3333-333333333333
+  bare:
+    access_key: SCW34567890ABCDEFGHI # gitleaks:allow
+    secret_key: 44444444-4444-4444-4444-444444444444 # gitleaks:allow
+`) + "\n"
+		if err := os.WriteFile(cfgPath, []byte(yaml), 0o644); err != nil {
+			t.Fatalf("write scw config: %v", err)
+		}
+		t.Setenv("SCW_CONFIG_PATH", cfgPath)
+		opened, err := Open(config.Config{Region: "fr-par"}, "client")
+		if err != nil {
+			t.Fatalf("expected success, got %v", err)
+		}
+		if got := opened.(*API).defaultProjectID; got != "33333333-3333-3333-3333-333333333333" {
+			t.Fatalf("expected the profile's project, got %q", got)
+		}
+		if _, err := Open(config.Config{Region: "fr-par"}, "bare"); err == nil || !strings.Contains(err.Error(), `profile "bare" sets no default_project_id`) {
+			t.Fatalf("expected missing project error, got %v", err)
+		}
+	})
 }
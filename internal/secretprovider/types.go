@@ -1,6 +1,15 @@
 package secretprovider
 
-import "github.com/bsmartlabs/dev-vault/internal/secretcontract"
+import (
+	"errors"
+	"time"
+
+	"github.com/bsmartlabs/dev-vault/internal/secretcontract"
+)
+
+// ErrPermissionDenied is wrapped by provider errors for calls the credentials
+// may not make, such as a write with a read-only API key.
+var ErrPermissionDenied = errors.New("permission denied")
 
 type SecretType string
 
@@ -17,12 +26,24 @@ type RevisionSelector string
 
 const RevisionLatestEnabled RevisionSelector = secretcontract.RevisionLatestEnabled
 
+// VersionStatusEnabled is the status of a version that can be read.
+const VersionStatusEnabled = "enabled"
+
+// VersionStatusDisabled is the status of a version kept but no longer read
+// by default; it can be enabled again.
+const VersionStatusDisabled = "disabled"
+
 type SecretRecord struct {
 	ID        string
 	ProjectID string
 	Name      string
 	Path      string
 	Type      SecretType
+	Tags      []string
+	UpdatedAt time.Time
+	// Profile names the credentials that found the record in a multi-profile
+	// run; it is empty otherwise.
+	Profile string
 }
 
 type ListSecretsInput struct {
@@ -31,6 +52,10 @@ type ListSecretsInput struct {
 	Name      string
 	Path      string
 	Type      SecretType
+	// NamePrefix lets a provider that can filter names by prefix skip the
+	// other secrets. It is a hint: providers may ignore it, so callers still
+	// match the names they get back.
+	NamePrefix string
 }
 
 type AccessSecretVersionInput struct {
@@ -45,6 +70,16 @@ type SecretVersionRecord struct {
 	Data     []byte
 	Type     SecretType
 	Status   string
+	// CreatedAt is set by ListSecretVersions, and by AccessSecretVersion
+	// when the provider returns it with the payload. Description is only set
+	// by ListSecretVersions, which leaves Data empty.
+	CreatedAt   time.Time
+	Description string
+}
+
+type ListSecretVersionsInput struct {
+	Region   string
+	SecretID string
 }
 
 type CreateSecretInput struct {
@@ -63,6 +98,27 @@ type CreateSecretVersionInput struct {
 	DisablePrevious *bool
 }
 
+// DeleteSecretInput removes a secret with all its versions.
+type DeleteSecretInput struct {
+	Region   string
+	SecretID string
+}
+
+// DeleteSecretVersionInput removes one version of a secret; the others stay.
+type DeleteSecretVersionInput struct {
+	Region   string
+	SecretID string
+	Revision uint32
+}
+
+// DisableSecretVersionInput disables one version of a secret: it stays
+// listed but can no longer be read until enabled again.
+type DisableSecretVersionInput struct {
+	Region   string
+	SecretID string
+	Revision uint32
+}
+
 type SecretLister interface {
 	ListSecrets(req ListSecretsInput) ([]SecretRecord, error)
 }
@@ -71,6 +127,10 @@ type SecretVersionAccessor interface {
 	AccessSecretVersion(req AccessSecretVersionInput) (*SecretVersionRecord, error)
 }
 
+type SecretVersionLister interface {
+	ListSecretVersions(req ListSecretVersionsInput) ([]SecretVersionRecord, error)
+}
+
 type SecretCreator interface {
 	CreateSecret(req CreateSecretInput) (*SecretRecord, error)
 }
@@ -79,9 +139,48 @@ type SecretVersionCreator interface {
 	CreateSecretVersion(req CreateSecretVersionInput) (*SecretVersionRecord, error)
 }
 
+type SecretDeleter interface {
+	DeleteSecret(req DeleteSecretInput) error
+}
+
+type SecretVersionDeleter interface {
+	DeleteSecretVersion(req DeleteSecretVersionInput) error
+}
+
+type SecretVersionDisabler interface {
+	DisableSecretVersion(req DisableSecretVersionInput) error
+}
+
 type SecretAPI interface {
 	SecretLister
 	SecretVersionAccessor
+	SecretVersionLister
 	SecretCreator
 	SecretVersionCreator
+	SecretDeleter
+	SecretVersionDeleter
+	SecretVersionDisabler
+}
+
+type ProjectRecord struct {
+	ID             string
+	Name           string
+	OrganizationID string
+}
+
+type ListProjectsInput struct {
+	OrganizationID string
+}
+
+type ProjectLister interface {
+	ListProjects(req ListProjectsInput) ([]ProjectRecord, error)
+}
+
+type RegionLister interface {
+	Regions() []string
+}
+
+type AccountAPI interface {
+	ProjectLister
+	RegionLister
 }
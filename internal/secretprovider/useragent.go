@@ -0,0 +1,57 @@
+package secretprovider
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"sync/atomic"
+)
+
+// UserAgentSuffixEnv names the environment variable whose value is appended
+// to the User-Agent, so tools wrapping dev-vault can identify themselves.
+const UserAgentSuffixEnv = "DEV_VAULT_USER_AGENT_SUFFIX"
+
+// Client describes the running dev-vault to providers.
+type Client struct {
+	Version string
+	// CI is set when the command runs in a CI job.
+	CI bool
+	// Suffix is appended verbatim, e.g. "acme-bootstrap/2.1".
+	Suffix string
+}
+
+// client is set once per process by SetClient.
+var client atomic.Value
+
+// SetClient records what UserAgent reports.
+func SetClient(c Client) {
+	client.Store(c)
+}
+
+// UserAgent identifies dev-vault to providers so platform teams can follow
+// adoption and retire old versions:
+// "dev-vault/<version> (<os>/<arch>; ci=<bool>) invocation/<id> <suffix>".
+// The invocation and suffix are left out when unset. Characters a header
+// cannot carry are replaced in the suffix.
+func UserAgent() string {
+	c, _ := client.Load().(Client)
+	version := c.Version
+	if version == "" {
+		version = "unknown"
+	}
+	agent := fmt.Sprintf("dev-vault/%s (%s/%s; ci=%t)", version, runtime.GOOS, runtime.GOARCH, c.CI)
+	if id := InvocationID(); id != "" {
+		agent += " invocation/" + id
+	}
+	if suffix := strings.TrimSpace(c.Suffix); suffix != "" {
+		agent += " " + strings.Map(headerSafe, suffix)
+	}
+	return agent
+}
+
+func headerSafe(r rune) rune {
+	if r < ' ' || r > '~' {
+		return '_'
+	}
+	return r
+}
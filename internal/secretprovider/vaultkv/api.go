@@ -0,0 +1,518 @@
+// Package vaultkv implements secretprovider.SecretAPI on a HashiCorp Vault
+// KV version 2 secrets engine, selected with "provider": "vault" in .scw.json.
+//
+// A secret at path /team/api named db-dev lives at team/api/db-dev in the
+// mount named by VAULT_KV_MOUNT ("secret" by default). Its dev-vault type is
+// kept in the dev-vault-type custom metadata; secrets without it are
+// key_value, the shape Vault stores natively. Revisions are Vault versions.
+package vaultkv
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/bsmartlabs/dev-vault/internal/config"
+	"github.com/bsmartlabs/dev-vault/internal/secretprovider"
+)
+
+const (
+	defaultMount    = "secret"
+	typeMetadata    = "dev-vault-type"
+	valueKey        = "value"
+	valueBase64Key  = "value_base64"
+	versionStatusOK = "enabled"
+)
+
+type openDeps struct {
+	getenv      func(string) string
+	userHomeDir func() (string, error)
+	readFile    func(string) ([]byte, error)
+	client      *http.Client
+}
+
+func defaultOpenDeps() openDeps {
+	return openDeps{
+		getenv:      os.Getenv,
+		userHomeDir: os.UserHomeDir,
+		readFile:    os.ReadFile,
+		client:      &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+func Open(cfg config.Config, profileOverride string) (secretprovider.SecretAPI, error) {
+	return openWithDeps(cfg, profileOverride, defaultOpenDeps())
+}
+
+// openWithDeps authenticates with VAULT_TOKEN, or with the token file the
+// vault CLI leaves in ~/.vault-token after a login.
+func openWithDeps(cfg config.Config, profileOverride string, deps openDeps) (*API, error) {
+	profileName := strings.TrimSpace(profileOverride)
+	if profileName == "" {
+		profileName = strings.TrimSpace(cfg.Profile)
+	}
+	if profileName != "" {
+		return nil, fmt.Errorf("the vault provider has no profiles (got %q); set VAULT_TOKEN instead", profileName)
+	}
+
+	addr := strings.TrimRight(deps.getenv("VAULT_ADDR"), "/")
+	if addr == "" {
+		return nil, errors.New("VAULT_ADDR is not set")
+	}
+	parsed, err := url.Parse(addr)
+	if err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid VAULT_ADDR %q", addr)
+	}
+
+	token := strings.TrimSpace(deps.getenv("VAULT_TOKEN"))
+	if token == "" {
+		home, err := deps.userHomeDir()
+		if err != nil {
+			return nil, fmt.Errorf("VAULT_TOKEN is not set: %w", err)
+		}
+		path := filepath.Join(home, ".vault-token")
+		raw, err := deps.readFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("VAULT_TOKEN is not set and %s cannot be read: %w", path, err)
+		}
+		token = strings.TrimSpace(string(raw))
+		if token == "" {
+			return nil, fmt.Errorf("VAULT_TOKEN is not set and %s is empty", path)
+		}
+	}
+
+	mount := strings.Trim(deps.getenv("VAULT_KV_MOUNT"), "/")
+	if mount == "" {
+		mount = defaultMount
+	}
+	return &API{
+		client:    deps.client,
+		addr:      addr,
+		token:     token,
+		namespace: deps.getenv("VAULT_NAMESPACE"),
+		mount:     mount,
+		types:     make(map[string]secretprovider.SecretType),
+	}, nil
+}
+
+type API struct {
+	client    *http.Client
+	addr      string
+	token     string
+	namespace string
+	mount     string
+
+	mu    sync.Mutex
+	types map[string]secretprovider.SecretType
+}
+
+type listResponse struct {
+	Data struct {
+		Keys []string `json:"keys"`
+	} `json:"data"`
+}
+
+type metadataResponse struct {
+	Data struct {
+		UpdatedTime    time.Time                  `json:"updated_time"`
+		CustomMetadata map[string]string          `json:"custom_metadata"`
+		Versions       map[string]versionMetadata `json:"versions"`
+	} `json:"data"`
+}
+
+type versionMetadata struct {
+	CreatedTime  time.Time `json:"created_time"`
+	DeletionTime string    `json:"deletion_time"`
+	Destroyed    bool      `json:"destroyed"`
+}
+
+type dataResponse struct {
+	Data struct {
+		Data     map[string]any `json:"data"`
+		Metadata struct {
+			Version     uint32    `json:"version"`
+			CreatedTime time.Time `json:"created_time"`
+		} `json:"metadata"`
+	} `json:"data"`
+}
+
+type writeResponse struct {
+	Data struct {
+		Version uint32 `json:"version"`
+	} `json:"data"`
+}
+
+// ListSecrets walks the mount from the requested path, or from its root and
+// through every folder when no path is given. Each secret costs one metadata
+// read, so the name and name prefix filters are applied first.
+func (a *API) ListSecrets(req secretprovider.ListSecretsInput) ([]secretprovider.SecretRecord, error) {
+	keys, err := a.walk(strings.Trim(req.Path, "/"), req.Path == "")
+	if err != nil {
+		return nil, wrapError("list secrets", err)
+	}
+	var out []secretprovider.SecretRecord
+	for _, key := range keys {
+		name := lastSegment(key)
+		if (req.Name != "" && name != req.Name) || !strings.HasPrefix(name, req.NamePrefix) {
+			continue
+		}
+		record, err := a.describe(key)
+		if err != nil {
+			return nil, wrapError("list secrets", err)
+		}
+		if req.Type != "" && record.Type != req.Type {
+			continue
+		}
+		out = append(out, record)
+	}
+	return out, nil
+}
+
+// walk lists the secret keys under dir, descending into folders when
+// recursive is set. A missing folder holds no secrets.
+func (a *API) walk(dir string, recursive bool) ([]string, error) {
+	var resp listResponse
+	if err := a.do("LIST", "metadata/"+dir, nil, &resp); err != nil {
+		if isNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var keys []string
+	for _, key := range resp.Data.Keys {
+		full := strings.TrimPrefix(dir+"/"+key, "/")
+		if folder, ok := strings.CutSuffix(full, "/"); ok {
+			if !recursive {
+				continue
+			}
+			nested, err := a.walk(folder, true)
+			if err != nil {
+				return nil, err
+			}
+			keys = append(keys, nested...)
+			continue
+		}
+		keys = append(keys, full)
+	}
+	return keys, nil
+}
+
+func (a *API) AccessSecretVersion(req secretprovider.AccessSecretVersionInput) (*secretprovider.SecretVersionRecord, error) {
+	path := "data/" + req.SecretID
+	if req.Revision != "" && req.Revision != secretprovider.RevisionLatestEnabled {
+		version, err := strconv.ParseUint(string(req.Revision), 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("access secret version: revision %q is not supported by the vault provider", req.Revision)
+		}
+		path += "?version=" + strconv.FormatUint(version, 10)
+	}
+	var resp dataResponse
+	if err := a.do(http.MethodGet, path, nil, &resp); err != nil {
+		return nil, wrapError("access secret version", err)
+	}
+	secretType, err := a.secretType(req.SecretID)
+	if err != nil {
+		return nil, err
+	}
+	data, err := decodePayload(secretType, resp.Data.Data)
+	if err != nil {
+		return nil, fmt.Errorf("access secret version: %w", err)
+	}
+	return &secretprovider.SecretVersionRecord{
+		SecretID:  req.SecretID,
+		Revision:  resp.Data.Metadata.Version,
+		Data:      data,
+		Type:      secretType,
+		CreatedAt: resp.Data.Metadata.CreatedTime,
+	}, nil
+}
+
+// ListSecretVersions reads every version from the secret's metadata.
+// Soft-deleted versions read as disabled and destroyed ones as destroyed.
+func (a *API) ListSecretVersions(req secretprovider.ListSecretVersionsInput) ([]secretprovider.SecretVersionRecord, error) {
+	var meta metadataResponse
+	if err := a.do(http.MethodGet, "metadata/"+req.SecretID, nil, &meta); err != nil {
+		return nil, wrapError("list secret versions", err)
+	}
+	var out []secretprovider.SecretVersionRecord
+	for raw, version := range meta.Data.Versions {
+		// Vault keys the map by version number.
+		revision, _ := strconv.ParseUint(raw, 10, 32)
+		record := secretprovider.SecretVersionRecord{
+			SecretID:  req.SecretID,
+			Revision:  uint32(revision),
+			Status:    versionStatusOK,
+			CreatedAt: version.CreatedTime,
+		}
+		switch {
+		case version.Destroyed:
+			record.Status = "destroyed"
+		case version.DeletionTime != "":
+			record.Status = secretprovider.VersionStatusDisabled
+		}
+		out = append(out, record)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Revision < out[j].Revision })
+	return out, nil
+}
+
+// CreateSecret writes the secret's metadata; its first version comes from
+// CreateSecretVersion. The project is ignored: a mount holds one namespace.
+func (a *API) CreateSecret(req secretprovider.CreateSecretInput) (*secretprovider.SecretRecord, error) {
+	secretType := req.Type
+	if secretType == "" {
+		secretType = secretprovider.SecretTypeOpaque
+	}
+	key := secretKey(req.Path, req.Name)
+	var meta metadataResponse
+	meta.Data.CustomMetadata = map[string]string{typeMetadata: string(secretType)}
+	if err := a.do(http.MethodPost, "metadata/"+key, map[string]any{"custom_metadata": meta.Data.CustomMetadata}, nil); err != nil {
+		return nil, wrapError("create secret", err)
+	}
+	record := a.record(key, meta)
+	return &record, nil
+}
+
+// CreateSecretVersion writes data as a new Vault version. DisablePrevious
+// soft-deletes the version before it, which Vault can undelete; Vault has no
+// version descriptions, so Description is ignored.
+func (a *API) CreateSecretVersion(req secretprovider.CreateSecretVersionInput) (*secretprovider.SecretVersionRecord, error) {
+	secretType, err := a.secretType(req.SecretID)
+	if err != nil {
+		return nil, err
+	}
+	data, err := encodePayload(secretType, req.Data)
+	if err != nil {
+		return nil, fmt.Errorf("create secret version: %w", err)
+	}
+	var resp writeResponse
+	if err := a.do(http.MethodPost, "data/"+req.SecretID, map[string]any{"data": data}, &resp); err != nil {
+		return nil, wrapError("create secret version", err)
+	}
+	version := resp.Data.Version
+	if req.DisablePrevious != nil && *req.DisablePrevious && version > 1 {
+		if err := a.do(http.MethodPost, "delete/"+req.SecretID, map[string]any{"versions": []uint32{version - 1}}, nil); err != nil {
+			return nil, wrapError("disable previous version", err)
+		}
+	}
+	return &secretprovider.SecretVersionRecord{
+		SecretID: req.SecretID,
+		Revision: version,
+		Status:   versionStatusOK,
+	}, nil
+}
+
+// DeleteSecret removes the secret's metadata and every version, which Vault
+// cannot undo.
+func (a *API) DeleteSecret(req secretprovider.DeleteSecretInput) error {
+	if err := a.do(http.MethodDelete, "metadata/"+req.SecretID, nil, nil); err != nil {
+		return wrapError("delete secret", err)
+	}
+	a.mu.Lock()
+	delete(a.types, req.SecretID)
+	a.mu.Unlock()
+	return nil
+}
+
+// DeleteSecretVersion destroys the version's data for good; its metadata
+// stays and lists it as destroyed.
+func (a *API) DeleteSecretVersion(req secretprovider.DeleteSecretVersionInput) error {
+	if err := a.do(http.MethodPost, "destroy/"+req.SecretID, map[string]any{"versions": []uint32{req.Revision}}, nil); err != nil {
+		return wrapError("delete secret version", err)
+	}
+	return nil
+}
+
+// DisableSecretVersion soft-deletes the version: it lists as disabled and
+// Vault's undelete endpoint restores it.
+func (a *API) DisableSecretVersion(req secretprovider.DisableSecretVersionInput) error {
+	if err := a.do(http.MethodPost, "delete/"+req.SecretID, map[string]any{"versions": []uint32{req.Revision}}, nil); err != nil {
+		return wrapError("disable secret version", err)
+	}
+	return nil
+}
+
+func (a *API) describe(key string) (secretprovider.SecretRecord, error) {
+	var meta metadataResponse
+	if err := a.do(http.MethodGet, "metadata/"+key, nil, &meta); err != nil {
+		return secretprovider.SecretRecord{}, err
+	}
+	return a.record(key, meta), nil
+}
+
+// record converts a key and its metadata and remembers the type for later
+// reads and writes of its versions.
+func (a *API) record(key string, meta metadataResponse) secretprovider.SecretRecord {
+	record := secretprovider.SecretRecord{ID: key, Name: key, Path: "/", Type: secretprovider.SecretTypeKeyValue, UpdatedAt: meta.Data.UpdatedTime}
+	if i := strings.LastIndex(key, "/"); i >= 0 {
+		record.Path, record.Name = "/"+key[:i], key[i+1:]
+	}
+	for name, value := range meta.Data.CustomMetadata {
+		if name == typeMetadata {
+			record.Type = secretprovider.SecretType(value)
+			continue
+		}
+		record.Tags = append(record.Tags, name+"="+value)
+	}
+	sort.Strings(record.Tags)
+	a.mu.Lock()
+	a.types[key] = record.Type
+	a.mu.Unlock()
+	return record
+}
+
+// secretType returns the type of a secret seen before, or reads its metadata.
+func (a *API) secretType(key string) (secretprovider.SecretType, error) {
+	a.mu.Lock()
+	secretType, ok := a.types[key]
+	a.mu.Unlock()
+	if ok {
+		return secretType, nil
+	}
+	record, err := a.describe(key)
+	if err != nil {
+		return "", wrapError("read secret metadata", err)
+	}
+	return record.Type, nil
+}
+
+// do sends one request to the mount. Error responses are reduced to Vault's
+// error strings, which never contain secret values.
+func (a *API) do(method, path string, in, out any) error {
+	var body io.Reader
+	if in != nil {
+		// Request bodies are plain maps of strings and numbers.
+		raw, _ := json.Marshal(in)
+		body = bytes.NewReader(raw)
+	}
+	// VAULT_ADDR was validated by Open.
+	req, _ := http.NewRequest(method, a.addr+"/v1/"+a.mount+"/"+path, body)
+	req.Header.Set("X-Vault-Token", a.token)
+	if a.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", a.namespace)
+	}
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	// Vault audit devices log these headers once they are listed under
+	// sys/config/auditing/request-headers.
+	req.Header.Set("User-Agent", secretprovider.UserAgent())
+	if id := secretprovider.InvocationID(); id != "" {
+		req.Header.Set(secretprovider.InvocationHeader, id)
+	}
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("read response: %w", err)
+	}
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		var errBody struct {
+			Errors []string `json:"errors"`
+		}
+		_ = json.Unmarshal(data, &errBody)
+		return &ResponseError{StatusCode: resp.StatusCode, Errors: errBody.Errors}
+	}
+	if out == nil || len(data) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	return nil
+}
+
+// ResponseError is a Vault error response.
+type ResponseError struct {
+	StatusCode int
+	Errors     []string
+}
+
+func (e *ResponseError) Error() string {
+	if len(e.Errors) == 0 {
+		return fmt.Sprintf("vault: HTTP %d", e.StatusCode)
+	}
+	return "vault: " + strings.Join(e.Errors, "; ")
+}
+
+func isNotFound(err error) bool {
+	var response *ResponseError
+	return errors.As(err, &response) && response.StatusCode == http.StatusNotFound
+}
+
+// wrapError marks errors for calls the token may not make with
+// secretprovider.ErrPermissionDenied.
+func wrapError(op string, err error) error {
+	var response *ResponseError
+	if errors.As(err, &response) && response.StatusCode == http.StatusForbidden {
+		return fmt.Errorf("%s: %w: %w", op, secretprovider.ErrPermissionDenied, err)
+	}
+	return fmt.Errorf("%s: %w", op, err)
+}
+
+// encodePayload turns a payload into the key/value map Vault stores: a
+// key_value JSON object as is, anything else under "value", or under
+// "value_base64" when it is not UTF-8.
+func encodePayload(secretType secretprovider.SecretType, payload []byte) (map[string]any, error) {
+	if secretType == secretprovider.SecretTypeKeyValue {
+		var object map[string]any
+		if err := json.Unmarshal(payload, &object); err != nil || object == nil {
+			return nil, errors.New("key_value payload is not a JSON object")
+		}
+		return object, nil
+	}
+	if utf8.Valid(payload) {
+		return map[string]any{valueKey: string(payload)}, nil
+	}
+	return map[string]any{valueBase64Key: base64.StdEncoding.EncodeToString(payload)}, nil
+}
+
+// decodePayload reverses encodePayload. Other key/value data, such as a
+// secret written by another tool, reads as its JSON object.
+func decodePayload(secretType secretprovider.SecretType, data map[string]any) ([]byte, error) {
+	if secretType != secretprovider.SecretTypeKeyValue && len(data) == 1 {
+		if value, ok := data[valueKey].(string); ok {
+			return []byte(value), nil
+		}
+		if value, ok := data[valueBase64Key].(string); ok {
+			decoded, err := base64.StdEncoding.DecodeString(value)
+			if err != nil {
+				return nil, fmt.Errorf("decode %s: %w", valueBase64Key, err)
+			}
+			return decoded, nil
+		}
+	}
+	// A map of decoded JSON values always marshals.
+	raw, _ := json.Marshal(data)
+	return raw, nil
+}
+
+// secretKey is the key of a dev-vault path and name in the mount.
+func secretKey(path, name string) string {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return name
+	}
+	return path + "/" + name
+}
+
+func lastSegment(key string) string {
+	return key[strings.LastIndex(key, "/")+1:]
+}
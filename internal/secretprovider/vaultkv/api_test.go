@@ -0,0 +1,563 @@
+package vaultkv
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bsmartlabs/dev-vault/internal/config"
+	"github.com/bsmartlabs/dev-vault/internal/secretprovider"
+)
+
+var fakeUpdated = time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+// fakeVault serves the KV v2 endpoints of one mount named "kv", keeping
+// secrets in memory.
+type fakeVault struct {
+	t *testing.T
+
+	mu       sync.Mutex
+	secrets  map[string]*fakeVaultSecret
+	fail     map[string]int
+	requests []string
+}
+
+type fakeVaultSecret struct {
+	custom    map[string]string
+	versions  []map[string]any
+	deleted   map[int]bool
+	destroyed map[int]bool
+}
+
+func newFakeVault(t *testing.T) (*fakeVault, *API) {
+	t.Helper()
+	fake := &fakeVault{t: t, secrets: map[string]*fakeVaultSecret{}, fail: map[string]int{}}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+	return fake, fakeAPI(t, server.URL, server.Client())
+}
+
+func fakeAPI(t *testing.T, addr string, client *http.Client) *API {
+	t.Helper()
+	deps := tokenDeps(map[string]string{"VAULT_ADDR": addr + "/", "VAULT_TOKEN": "s.token", "VAULT_KV_MOUNT": "/kv/", "VAULT_NAMESPACE": "team"}, "")
+	deps.client = client
+	api, err := openWithDeps(config.Config{Provider: config.ProviderVault}, "", deps)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	return api
+}
+
+func (f *fakeVault) add(key string, custom map[string]string, data map[string]any) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.secrets[key] = &fakeVaultSecret{custom: custom, versions: []map[string]any{data}, deleted: map[int]bool{}}
+}
+
+func (f *fakeVault) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if r.Header.Get("X-Vault-Token") != "s.token" || r.Header.Get("X-Vault-Namespace") != "team" {
+		f.t.Errorf("unauthenticated request: %v", r.Header)
+	}
+	rest, ok := strings.CutPrefix(r.URL.Path, "/v1/kv/")
+	if !ok {
+		f.t.Errorf("unexpected path %s", r.URL.Path)
+	}
+	request := r.Method + " " + rest
+	f.requests = append(f.requests, request)
+	if status, ok := f.fail[request]; ok {
+		w.WriteHeader(status)
+		_, _ = io.WriteString(w, `{"errors":["1 error occurred","permission denied"]}`)
+		return
+	}
+	endpoint, key, _ := strings.Cut(rest, "/")
+	var in map[string]any
+	if r.Method == http.MethodPost {
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			f.t.Errorf("decode %s: %v", request, err)
+		}
+	}
+
+	reply := func(data any) {
+		_ = json.NewEncoder(w).Encode(map[string]any{"data": data})
+	}
+	secret := f.secrets[key]
+	switch {
+	case r.Method == "LIST":
+		seen := map[string]bool{}
+		prefix := key
+		if prefix != "" {
+			prefix += "/"
+		}
+		for name := range f.secrets {
+			child, ok := strings.CutPrefix(name, prefix)
+			if !ok {
+				continue
+			}
+			if i := strings.Index(child, "/"); i >= 0 {
+				child = child[:i+1]
+			}
+			seen[child] = true
+		}
+		if len(seen) == 0 {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		keys := make([]string, 0, len(seen))
+		for child := range seen {
+			keys = append(keys, child)
+		}
+		sort.Strings(keys)
+		reply(map[string]any{"keys": keys})
+	case endpoint == "metadata" && r.Method == http.MethodPost:
+		custom := map[string]string{}
+		for name, value := range in["custom_metadata"].(map[string]any) {
+			custom[name] = value.(string)
+		}
+		f.secrets[key] = &fakeVaultSecret{custom: custom, deleted: map[int]bool{}}
+		w.WriteHeader(http.StatusNoContent)
+	case secret == nil:
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = io.WriteString(w, `{"errors":[]}`)
+	case endpoint == "metadata" && r.Method == http.MethodDelete:
+		delete(f.secrets, key)
+		w.WriteHeader(http.StatusNoContent)
+	case endpoint == "metadata":
+		versions := map[string]any{}
+		for i := range secret.versions {
+			deletion := ""
+			if secret.deleted[i+1] {
+				deletion = fakeUpdated.Format(time.RFC3339)
+			}
+			versions[strconv.Itoa(i+1)] = map[string]any{"created_time": fakeUpdated, "deletion_time": deletion, "destroyed": secret.destroyed[i+1]}
+		}
+		reply(map[string]any{"updated_time": fakeUpdated, "custom_metadata": secret.custom, "versions": versions})
+	case endpoint == "data" && r.Method == http.MethodGet:
+		version := len(secret.versions)
+		if raw := r.URL.Query().Get("version"); raw != "" {
+			version, _ = strconv.Atoi(raw)
+		}
+		if version < 1 || version > len(secret.versions) || secret.deleted[version] {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		reply(map[string]any{"data": secret.versions[version-1], "metadata": map[string]any{"version": version, "created_time": fakeUpdated}})
+	case endpoint == "data":
+		secret.versions = append(secret.versions, in["data"].(map[string]any))
+		reply(map[string]any{"version": len(secret.versions)})
+	case endpoint == "delete":
+		for _, version := range in["versions"].([]any) {
+			secret.deleted[int(version.(float64))] = true
+		}
+		w.WriteHeader(http.StatusNoContent)
+	case endpoint == "destroy":
+		if secret.destroyed == nil {
+			secret.destroyed = map[int]bool{}
+		}
+		for _, version := range in["versions"].([]any) {
+			secret.destroyed[int(version.(float64))] = true
+			secret.deleted[int(version.(float64))] = true
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func tokenDeps(env map[string]string, tokenFile string) openDeps {
+	return openDeps{
+		getenv:      func(key string) string { return env[key] },
+		userHomeDir: func() (string, error) { return "/home/dev", nil },
+		readFile: func(path string) ([]byte, error) {
+			if path != "/home/dev/.vault-token" || tokenFile == "" {
+				return nil, errors.New("no such file")
+			}
+			return []byte(tokenFile), nil
+		},
+	}
+}
+
+func TestOpen(t *testing.T) {
+	t.Setenv("VAULT_ADDR", "https://vault.example:8200")
+	t.Setenv("VAULT_TOKEN", "s.env")
+	t.Setenv("VAULT_KV_MOUNT", "")
+	t.Setenv("VAULT_NAMESPACE", "")
+	api, err := Open(config.Config{Provider: config.ProviderVault}, "")
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	if opened := api.(*API); opened.addr != "https://vault.example:8200" || opened.token != "s.env" || opened.mount != "secret" {
+		t.Fatalf("unexpected api: %#v", opened)
+	}
+
+	opened, err := openWithDeps(config.Config{}, "", tokenDeps(map[string]string{"VAULT_ADDR": "http://127.0.0.1:8200"}, "s.file\n"))
+	if err != nil || opened.token != "s.file" {
+		t.Fatalf("expected the token file, got %#v %v", opened, err)
+	}
+
+	homeless := tokenDeps(map[string]string{"VAULT_ADDR": "http://127.0.0.1:8200"}, "")
+	homeless.userHomeDir = func() (string, error) { return "", errors.New("no home") }
+	cases := []struct {
+		name    string
+		cfg     config.Config
+		profile string
+		deps    openDeps
+		wantSub string
+	}{
+		{"ProfileFromConfig", config.Config{Profile: "team"}, "", tokenDeps(nil, ""), `the vault provider has no profiles (got "team")`},
+		{"ProfileOverride", config.Config{}, " ops ", tokenDeps(nil, ""), `(got "ops")`},
+		{"NoAddress", config.Config{}, "", tokenDeps(nil, ""), "VAULT_ADDR is not set"},
+		{"BadAddress", config.Config{}, "", tokenDeps(map[string]string{"VAULT_ADDR": "vault:8200"}, ""), `invalid VAULT_ADDR "vault:8200"`},
+		{"UnparsableAddress", config.Config{}, "", tokenDeps(map[string]string{"VAULT_ADDR": "http://[::1"}, ""), "invalid VAULT_ADDR"},
+		{"NoHome", config.Config{}, "", homeless, "VAULT_TOKEN is not set: no home"},
+		{"NoTokenFile", config.Config{}, "", tokenDeps(map[string]string{"VAULT_ADDR": "http://127.0.0.1:8200"}, ""), "VAULT_TOKEN is not set and /home/dev/.vault-token cannot be read: no such file"},
+		{"EmptyTokenFile", config.Config{}, "", tokenDeps(map[string]string{"VAULT_ADDR": "http://127.0.0.1:8200"}, " \n"), "VAULT_TOKEN is not set and /home/dev/.vault-token is empty"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := openWithDeps(tc.cfg, tc.profile, tc.deps); err == nil || !strings.Contains(err.Error(), tc.wantSub) {
+				t.Fatalf("expected error containing %q, got %v", tc.wantSub, err)
+			}
+		})
+	}
+}
+
+func TestAPI_RoundTrip(t *testing.T) {
+	fake, api := newFakeVault(t)
+
+	payloads := map[secretprovider.SecretType][][]byte{
+		secretprovider.SecretTypeKeyValue: {[]byte(`{"A":"1","B":"2"}`), []byte(`{"A":"3"}`)},
+		secretprovider.SecretTypeOpaque:   {[]byte("line\n"), {0xff, 0x00}},
+	}
+	for secretType, versions := range payloads {
+		name := strings.ReplaceAll(string(secretType), "_", "-") + "-dev"
+		created, err := api.CreateSecret(secretprovider.CreateSecretInput{Name: name, Path: "/team/api/", Type: secretType})
+		if err != nil {
+			t.Fatalf("create secret: %v", err)
+		}
+		want := &secretprovider.SecretRecord{ID: "team/api/" + name, Name: name, Path: "/team/api", Type: secretType}
+		if !reflect.DeepEqual(created, want) {
+			t.Fatalf("unexpected secret:\n got %#v\nwant %#v", created, want)
+		}
+		for i, data := range versions {
+			version, err := api.CreateSecretVersion(secretprovider.CreateSecretVersionInput{SecretID: created.ID, Data: data})
+			if err != nil || version.Revision != uint32(i+1) || version.SecretID != created.ID || version.Status != "enabled" {
+				t.Fatalf("unexpected version: %#v %v", version, err)
+			}
+			access, err := api.AccessSecretVersion(secretprovider.AccessSecretVersionInput{SecretID: created.ID, Revision: secretprovider.RevisionLatestEnabled})
+			if err != nil || string(access.Data) != string(data) || access.Revision != uint32(i+1) || access.Type != secretType {
+				t.Fatalf("unexpected access: %#v %v", access, err)
+			}
+		}
+	}
+	if got := fake.secrets["team/api/key-value-dev"].versions[0]; !reflect.DeepEqual(got, map[string]any{"A": "1", "B": "2"}) {
+		t.Fatalf("expected key_value payloads stored as Vault fields, got %v", got)
+	}
+	if got := fake.secrets["team/api/opaque-dev"].versions[1]; !reflect.DeepEqual(got, map[string]any{"value_base64": "/wA="}) {
+		t.Fatalf("expected binary payloads in base64, got %v", got)
+	}
+
+	for _, payload := range []string{"not an object", "null"} {
+		if _, err := api.CreateSecretVersion(secretprovider.CreateSecretVersionInput{SecretID: "team/api/key-value-dev", Data: []byte(payload)}); err == nil || err.Error() != "create secret version: key_value payload is not a JSON object" {
+			t.Fatalf("expected %q to be refused, got %v", payload, err)
+		}
+	}
+
+	// Older versions stay readable by number.
+	access, err := api.AccessSecretVersion(secretprovider.AccessSecretVersionInput{SecretID: "team/api/opaque-dev", Revision: "1"})
+	if err != nil || string(access.Data) != "line\n" || access.Revision != 1 || !access.CreatedAt.Equal(fakeUpdated) {
+		t.Fatalf("unexpected access: %#v %v", access, err)
+	}
+
+	// Disabling the previous version soft-deletes it.
+	disable := true
+	if _, err := api.CreateSecretVersion(secretprovider.CreateSecretVersionInput{SecretID: "team/api/opaque-dev", Data: []byte("x"), DisablePrevious: &disable}); err != nil {
+		t.Fatalf("create version: %v", err)
+	}
+	if !reflect.DeepEqual(fake.secrets["team/api/opaque-dev"].deleted, map[int]bool{2: true}) {
+		t.Fatalf("expected version 2 deleted, got %v", fake.secrets["team/api/opaque-dev"].deleted)
+	}
+
+	// A secret written by another tool is key_value and is read as its fields.
+	fake.add("native-dev", nil, map[string]any{"port": 5432.0, "value": "x"})
+	fake.add("team/opaque-dev", map[string]string{typeMetadata: "opaque"}, map[string]any{"user": "u", "pass": "p"})
+	fresh := fakeAPI(t, api.addr, api.client)
+	access, err = fresh.AccessSecretVersion(secretprovider.AccessSecretVersionInput{SecretID: "native-dev"})
+	if err != nil || string(access.Data) != `{"port":5432,"value":"x"}` || access.Type != secretprovider.SecretTypeKeyValue {
+		t.Fatalf("unexpected native access: %#v %v", access, err)
+	}
+	access, err = fresh.AccessSecretVersion(secretprovider.AccessSecretVersionInput{SecretID: "team/opaque-dev"})
+	if err != nil || string(access.Data) != `{"pass":"p","user":"u"}` {
+		t.Fatalf("unexpected opaque access: %#v %v", access, err)
+	}
+	// Its first dev-vault version disables nothing.
+	if _, err := fresh.CreateSecretVersion(secretprovider.CreateSecretVersionInput{SecretID: "native-dev", Data: []byte(`{"port":"1"}`), DisablePrevious: &disable}); err != nil {
+		t.Fatalf("create version: %v", err)
+	}
+	fake.add("first-dev", nil, nil)
+	fake.secrets["first-dev"].versions = nil
+	if _, err := fresh.CreateSecretVersion(secretprovider.CreateSecretVersionInput{SecretID: "first-dev", Data: []byte(`{}`), DisablePrevious: &disable}); err != nil {
+		t.Fatalf("create first version: %v", err)
+	}
+	if len(fake.secrets["native-dev"].deleted) != 1 || len(fake.secrets["first-dev"].deleted) != 0 {
+		t.Fatalf("unexpected deletes: %v %v", fake.secrets["native-dev"].deleted, fake.secrets["first-dev"].deleted)
+	}
+}
+
+func TestAPI_ListSecrets(t *testing.T) {
+	fake, api := newFakeVault(t)
+	fake.add("a-dev", map[string]string{"owner": "payments", "tier": "1"}, map[string]any{})
+	fake.add("team/a-dev", map[string]string{typeMetadata: "opaque"}, map[string]any{})
+	fake.add("team/deep/b-dev", nil, map[string]any{})
+
+	all, err := api.ListSecrets(secretprovider.ListSecretsInput{})
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	want := []secretprovider.SecretRecord{
+		{ID: "a-dev", Name: "a-dev", Path: "/", Type: secretprovider.SecretTypeKeyValue, Tags: []string{"owner=payments", "tier=1"}, UpdatedAt: fakeUpdated},
+		{ID: "team/a-dev", Name: "a-dev", Path: "/team", Type: secretprovider.SecretTypeOpaque, UpdatedAt: fakeUpdated},
+		{ID: "team/deep/b-dev", Name: "b-dev", Path: "/team/deep", Type: secretprovider.SecretTypeKeyValue, UpdatedAt: fakeUpdated},
+	}
+	if !reflect.DeepEqual(all, want) {
+		t.Fatalf("unexpected records:\n got %#v\nwant %#v", all, want)
+	}
+
+	filters := []struct {
+		req  secretprovider.ListSecretsInput
+		want []secretprovider.SecretRecord
+	}{
+		{secretprovider.ListSecretsInput{Name: "a-dev"}, want[:2]},
+		{secretprovider.ListSecretsInput{Path: "/team"}, want[1:2]},
+		{secretprovider.ListSecretsInput{Name: "a-dev", Path: "/"}, want[:1]},
+		{secretprovider.ListSecretsInput{Type: secretprovider.SecretTypeKeyValue}, []secretprovider.SecretRecord{want[0], want[2]}},
+		{secretprovider.ListSecretsInput{Path: "/missing"}, nil},
+	}
+	for _, filter := range filters {
+		fake.requests = nil
+		got, err := api.ListSecrets(filter.req)
+		if err != nil || !reflect.DeepEqual(got, filter.want) {
+			t.Fatalf("list %#v: unexpected records %#v %v", filter.req, got, err)
+		}
+	}
+	// A name filter skips the metadata of other secrets.
+	fake.requests = nil
+	if _, err := api.ListSecrets(secretprovider.ListSecretsInput{Name: "b-dev"}); err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if got := strings.Join(fake.requests, ","); got != "LIST metadata/,LIST metadata/team,LIST metadata/team/deep,GET metadata/team/deep/b-dev" {
+		t.Fatalf("unexpected requests: %s", got)
+	}
+	// So does a name prefix.
+	fake.requests = nil
+	if got, err := api.ListSecrets(secretprovider.ListSecretsInput{NamePrefix: "a"}); err != nil || !reflect.DeepEqual(got, want[:2]) {
+		t.Fatalf("unexpected prefixed records: %#v %v", got, err)
+	}
+	if got := strings.Join(fake.requests, ","); got != "LIST metadata/,LIST metadata/team,LIST metadata/team/deep,GET metadata/a-dev,GET metadata/team/a-dev" {
+		t.Fatalf("unexpected requests: %s", got)
+	}
+}
+
+func TestAPI_ListSecretVersions(t *testing.T) {
+	fake, api := newFakeVault(t)
+	fake.add("team/db-dev", nil, map[string]any{"v": "1"})
+	secret := fake.secrets["team/db-dev"]
+	secret.versions = append(secret.versions, map[string]any{"v": "2"}, map[string]any{"v": "3"})
+	secret.deleted[1] = true
+	secret.destroyed = map[int]bool{2: true}
+
+	versions, err := api.ListSecretVersions(secretprovider.ListSecretVersionsInput{SecretID: "team/db-dev"})
+	if err != nil {
+		t.Fatalf("list versions: %v", err)
+	}
+	want := []secretprovider.SecretVersionRecord{
+		{SecretID: "team/db-dev", Revision: 1, Status: "disabled", CreatedAt: fakeUpdated},
+		{SecretID: "team/db-dev", Revision: 2, Status: "destroyed", CreatedAt: fakeUpdated},
+		{SecretID: "team/db-dev", Revision: 3, Status: "enabled", CreatedAt: fakeUpdated},
+	}
+	if !reflect.DeepEqual(versions, want) {
+		t.Fatalf("unexpected versions:\n got %#v\nwant %#v", versions, want)
+	}
+
+	if _, err := api.ListSecretVersions(secretprovider.ListSecretVersionsInput{SecretID: "missing-dev"}); err == nil || !strings.HasPrefix(err.Error(), "list secret versions: ") {
+		t.Fatalf("expected not found, got %v", err)
+	}
+}
+
+func TestAPI_DisableSecretVersion(t *testing.T) {
+	fake, api := newFakeVault(t)
+	fake.add("team/db-dev", map[string]string{typeMetadata: "opaque"}, map[string]any{"value": "1"})
+	if _, err := api.CreateSecretVersion(secretprovider.CreateSecretVersionInput{SecretID: "team/db-dev", Data: []byte("2")}); err != nil {
+		t.Fatalf("create version: %v", err)
+	}
+	if err := api.DisableSecretVersion(secretprovider.DisableSecretVersionInput{SecretID: "team/db-dev", Revision: 1}); err != nil {
+		t.Fatalf("disable version: %v", err)
+	}
+	versions, err := api.ListSecretVersions(secretprovider.ListSecretVersionsInput{SecretID: "team/db-dev"})
+	if err != nil || len(versions) != 2 || versions[0].Status != "disabled" || versions[1].Status != "enabled" {
+		t.Fatalf("unexpected versions: %#v %v", versions, err)
+	}
+
+	fake.fail["POST delete/team/db-dev"] = http.StatusForbidden
+	if err := api.DisableSecretVersion(secretprovider.DisableSecretVersionInput{SecretID: "team/db-dev", Revision: 2}); !errors.Is(err, secretprovider.ErrPermissionDenied) || !strings.HasPrefix(err.Error(), "disable secret version: ") {
+		t.Fatalf("expected permission denied, got %v", err)
+	}
+}
+
+func TestAPI_Delete(t *testing.T) {
+	fake, api := newFakeVault(t)
+	fake.add("team/db-dev", map[string]string{typeMetadata: "opaque"}, map[string]any{"value": "1"})
+	if _, err := api.CreateSecretVersion(secretprovider.CreateSecretVersionInput{SecretID: "team/db-dev", Data: []byte("2")}); err != nil {
+		t.Fatalf("create version: %v", err)
+	}
+
+	if err := api.DeleteSecretVersion(secretprovider.DeleteSecretVersionInput{SecretID: "team/db-dev", Revision: 1}); err != nil {
+		t.Fatalf("delete version: %v", err)
+	}
+	versions, err := api.ListSecretVersions(secretprovider.ListSecretVersionsInput{SecretID: "team/db-dev"})
+	if err != nil || len(versions) != 2 || versions[0].Status != "destroyed" || versions[1].Status != "enabled" {
+		t.Fatalf("unexpected versions: %#v %v", versions, err)
+	}
+
+	if err := api.DeleteSecret(secretprovider.DeleteSecretInput{SecretID: "team/db-dev"}); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, ok := fake.secrets["team/db-dev"]; ok || len(api.types) != 0 {
+		t.Fatalf("secret not deleted: %v", api.types)
+	}
+	want := []string{"GET metadata/team/db-dev", "POST data/team/db-dev", "POST destroy/team/db-dev", "GET metadata/team/db-dev", "DELETE metadata/team/db-dev"}
+	if !reflect.DeepEqual(fake.requests, want) {
+		t.Fatalf("unexpected requests: %v", fake.requests)
+	}
+
+	fake.fail["DELETE metadata/team/db-dev"] = http.StatusForbidden
+	fake.fail["POST destroy/team/db-dev"] = http.StatusForbidden
+	if err := api.DeleteSecret(secretprovider.DeleteSecretInput{SecretID: "team/db-dev"}); !errors.Is(err, secretprovider.ErrPermissionDenied) || !strings.HasPrefix(err.Error(), "delete secret: ") {
+		t.Fatalf("expected permission denied, got %v", err)
+	}
+	if err := api.DeleteSecretVersion(secretprovider.DeleteSecretVersionInput{SecretID: "team/db-dev", Revision: 2}); !errors.Is(err, secretprovider.ErrPermissionDenied) || !strings.HasPrefix(err.Error(), "delete secret version: ") {
+		t.Fatalf("expected permission denied, got %v", err)
+	}
+}
+
+func TestAPI_Errors(t *testing.T) {
+	fake, api := newFakeVault(t)
+	fake.add("a-dev", nil, map[string]any{"value_base64": "%%%"})
+	fake.add("team/b-dev", map[string]string{typeMetadata: "opaque"}, map[string]any{"value_base64": "%%%"})
+
+	fake.fail["LIST metadata/"] = http.StatusForbidden
+	if _, err := api.ListSecrets(secretprovider.ListSecretsInput{}); !errors.Is(err, secretprovider.ErrPermissionDenied) || err.Error() != "list secrets: permission denied: vault: 1 error occurred; permission denied" {
+		t.Fatalf("expected permission denied, got %v", err)
+	}
+	delete(fake.fail, "LIST metadata/")
+	fake.fail["LIST metadata/team"] = http.StatusInternalServerError
+	if _, err := api.ListSecrets(secretprovider.ListSecretsInput{}); err == nil || errors.Is(err, secretprovider.ErrPermissionDenied) || !strings.HasPrefix(err.Error(), "list secrets: vault: ") {
+		t.Fatalf("expected nested list error, got %v", err)
+	}
+	delete(fake.fail, "LIST metadata/team")
+	fake.fail["GET metadata/a-dev"] = http.StatusBadGateway
+	if _, err := api.ListSecrets(secretprovider.ListSecretsInput{}); err == nil || !strings.HasPrefix(err.Error(), "list secrets: vault: ") {
+		t.Fatalf("expected metadata error, got %v", err)
+	}
+
+	// Reading a secret no listing returned reads its metadata first.
+	if _, err := api.AccessSecretVersion(secretprovider.AccessSecretVersionInput{SecretID: "a-dev"}); err == nil || !strings.HasPrefix(err.Error(), "read secret metadata: vault: ") {
+		t.Fatalf("expected metadata error, got %v", err)
+	}
+	if _, err := api.CreateSecretVersion(secretprovider.CreateSecretVersionInput{SecretID: "a-dev"}); err == nil || !strings.HasPrefix(err.Error(), "read secret metadata: ") {
+		t.Fatalf("expected metadata error, got %v", err)
+	}
+	if _, err := api.AccessSecretVersion(secretprovider.AccessSecretVersionInput{SecretID: "team/b-dev"}); err == nil || !strings.HasPrefix(err.Error(), "access secret version: decode value_base64: ") {
+		t.Fatalf("expected decode error, got %v", err)
+	}
+	if _, err := api.AccessSecretVersion(secretprovider.AccessSecretVersionInput{SecretID: "team/b-dev", Revision: "latest"}); err == nil || err.Error() != `access secret version: revision "latest" is not supported by the vault provider` {
+		t.Fatalf("expected revision error, got %v", err)
+	}
+	if _, err := api.AccessSecretVersion(secretprovider.AccessSecretVersionInput{SecretID: "missing-dev"}); err == nil || err.Error() != "access secret version: vault: HTTP 404" {
+		t.Fatalf("expected not found, got %v", err)
+	}
+
+	fake.fail["POST metadata/c-dev"] = http.StatusBadRequest
+	if _, err := api.CreateSecret(secretprovider.CreateSecretInput{Name: "c-dev"}); err == nil || !strings.HasPrefix(err.Error(), "create secret: vault: ") {
+		t.Fatalf("expected create error, got %v", err)
+	}
+	delete(fake.fail, "POST metadata/c-dev")
+	created, err := api.CreateSecret(secretprovider.CreateSecretInput{Name: "c-dev"})
+	if err != nil || created.Type != secretprovider.SecretTypeOpaque || created.Path != "/" {
+		t.Fatalf("unexpected secret: %#v %v", created, err)
+	}
+	fake.fail["POST data/c-dev"] = http.StatusBadRequest
+	if _, err := api.CreateSecretVersion(secretprovider.CreateSecretVersionInput{SecretID: "c-dev", Data: []byte("x")}); err == nil || !strings.HasPrefix(err.Error(), "create secret version: vault: ") {
+		t.Fatalf("expected write error, got %v", err)
+	}
+	delete(fake.fail, "POST data/c-dev")
+	fake.fail["POST delete/c-dev"] = http.StatusForbidden
+	disable := true
+	for range 2 {
+		_, err = api.CreateSecretVersion(secretprovider.CreateSecretVersionInput{SecretID: "c-dev", Data: []byte("x"), DisablePrevious: &disable})
+	}
+	if !errors.Is(err, secretprovider.ErrPermissionDenied) || !strings.HasPrefix(err.Error(), "disable previous version: ") {
+		t.Fatalf("expected delete error, got %v", err)
+	}
+
+	t.Run("Transport", func(t *testing.T) {
+		server := httptest.NewServer(http.NotFoundHandler())
+		closed := fakeAPI(t, server.URL, server.Client())
+		server.Close()
+		if _, err := closed.CreateSecret(secretprovider.CreateSecretInput{Name: "a-dev"}); err == nil || !strings.HasPrefix(err.Error(), "create secret: Post ") {
+			t.Fatalf("expected transport error, got %v", err)
+		}
+
+		respond := func(body io.Reader) *API {
+			return fakeAPI(t, "http://vault.test", &http.Client{Transport: roundTripFunc(func(*http.Request) (*http.Response, error) {
+				return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(body)}, nil
+			})})
+		}
+		if _, err := respond(failingReader{}).ListSecrets(secretprovider.ListSecretsInput{}); err == nil || err.Error() != "list secrets: read response: read boom" {
+			t.Fatalf("expected read error, got %v", err)
+		}
+		if _, err := respond(strings.NewReader("{")).ListSecrets(secretprovider.ListSecretsInput{}); err == nil || err.Error() != "list secrets: decode response: unexpected end of JSON input" {
+			t.Fatalf("expected decode error, got %v", err)
+		}
+		if got, err := respond(strings.NewReader("")).ListSecrets(secretprovider.ListSecretsInput{}); err != nil || got != nil {
+			t.Fatalf("expected an empty listing, got %#v %v", got, err)
+		}
+	})
+}
+
+func TestAPI_InvocationHeaders(t *testing.T) {
+	t.Cleanup(func() { secretprovider.SetInvocationID("") })
+	var got []http.Header
+	api := fakeAPI(t, "http://vault.test", &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		got = append(got, req.Header.Clone())
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(`{"data":{}}`))}, nil
+	})})
+	for _, id := range []string{"", "ABC123"} {
+		secretprovider.SetInvocationID(id)
+		if _, err := api.ListSecretVersions(secretprovider.ListSecretVersionsInput{SecretID: "db-dev"}); err != nil {
+			t.Fatalf("list versions: %v", err)
+		}
+	}
+	if ua := got[0].Get("User-Agent"); !strings.HasPrefix(ua, "dev-vault/") || strings.Contains(ua, "invocation/") || got[0].Get(secretprovider.InvocationHeader) != "" {
+		t.Fatalf("unexpected headers without invocation: %v", got[0])
+	}
+	if !strings.HasSuffix(got[1].Get("User-Agent"), ") invocation/ABC123") || got[1].Get(secretprovider.InvocationHeader) != "ABC123" {
+		t.Fatalf("unexpected headers with invocation: %v", got[1])
+	}
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) { return 0, errors.New("read boom") }
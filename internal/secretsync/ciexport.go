@@ -0,0 +1,42 @@
+package secretsync
+
+import (
+	"fmt"
+
+	"github.com/bsmartlabs/dev-vault/internal/dotenv"
+)
+
+// DotenvValues reads back the dotenv files of targets after a pull, so the
+// exported variables match what was written, prefixes and filters included.
+// Raw mappings are skipped. A key set by two targets is an error rather than
+// a silent override.
+func (s Service) DotenvValues(targets []MappingTarget) (map[string]string, error) {
+	s = s.withIndex()
+	values := make(map[string]string)
+	owners := make(map[string]string)
+	for _, target := range targets {
+		if target.Entry.Format != MappingFormatDotenv {
+			continue
+		}
+		path, err := s.resolvePath(s.cfg.Root, target.Entry.File)
+		if err != nil {
+			return nil, fmt.Errorf("mapping %s: resolve file: %w", target.Name, err)
+		}
+		raw, err := s.fs.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("export %s: read %s: %w", target.Name, path, err)
+		}
+		env, err := dotenv.Parse(raw)
+		if err != nil {
+			return nil, fmt.Errorf("export %s: parse %s: %w", target.Name, path, err)
+		}
+		for key, value := range env {
+			if owner, ok := owners[key]; ok {
+				return nil, fmt.Errorf("export: key %s is set by both %s and %s", key, owner, target.Name)
+			}
+			owners[key] = target.Name
+			values[key] = value
+		}
+	}
+	return values, nil
+}
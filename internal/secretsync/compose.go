@@ -0,0 +1,75 @@
+package secretsync
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/bsmartlabs/dev-vault/internal/secretprovider"
+)
+
+// composePayload layers the JSON-object payloads of every secret the target
+// composes under its own payload, and returns the layer each key came from;
+// keys from later layers win.
+func (s Service) composePayload(target MappingTarget, own *secretprovider.SecretVersionRecord) ([]byte, map[string]keyOrigin, error) {
+	layers, err := s.composeLayers(target.Name, target.Entry, map[string]bool{})
+	if err != nil {
+		return nil, nil, err
+	}
+	merged := make(map[string]json.RawMessage)
+	origins := make(map[string]keyOrigin)
+	for _, name := range layers {
+		access, err := s.accessVersion(name, s.cfg.Mapping[name])
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := mergeLayer(merged, origins, keyOrigin{Secret: name, Revision: access.Revision}, access.Data); err != nil {
+			return nil, nil, err
+		}
+	}
+	if err := mergeLayer(merged, origins, keyOrigin{Secret: target.Name, Revision: own.Revision}, own.Data); err != nil {
+		return nil, nil, err
+	}
+	payload, err := json.Marshal(merged)
+	return payload, origins, err
+}
+
+// keyOrigin is the secret revision a composed key was read from.
+type keyOrigin struct {
+	Secret   string
+	Revision uint32
+}
+
+// composeLayers lists the secrets under name, bottom layer first: each
+// compose reference preceded by its own layers.
+func (s Service) composeLayers(name string, entry MappingEntry, visiting map[string]bool) ([]string, error) {
+	visiting[name] = true
+	defer delete(visiting, name)
+	var layers []string
+	for _, ref := range entry.Compose {
+		if visiting[ref] {
+			return nil, fmt.Errorf("compose cycle at %s", ref)
+		}
+		refEntry, ok := s.cfg.Mapping[ref]
+		if !ok {
+			return nil, fmt.Errorf("compose reference %s is not mapped", ref)
+		}
+		below, err := s.composeLayers(ref, refEntry, visiting)
+		if err != nil {
+			return nil, err
+		}
+		layers = append(append(layers, below...), ref)
+	}
+	return layers, nil
+}
+
+func mergeLayer(merged map[string]json.RawMessage, origins map[string]keyOrigin, origin keyOrigin, payload []byte) error {
+	var layer map[string]json.RawMessage
+	if err := json.Unmarshal(payload, &layer); err != nil {
+		return fmt.Errorf("layer %s: expected JSON object: %w", origin.Secret, err)
+	}
+	for key, value := range layer {
+		merged[key] = value
+		origins[key] = origin
+	}
+	return nil
+}
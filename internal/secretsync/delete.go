@@ -0,0 +1,61 @@
+package secretsync
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/bsmartlabs/dev-vault/internal/secretprovider"
+)
+
+// Delete removes the mapped secret with all its versions. The local file and
+// the mapping are left alone.
+func (s Service) Delete(target MappingTarget) error {
+	record, api, err := s.resolveTarget(target)
+	if err != nil {
+		return err
+	}
+	if err := api.DeleteSecret(secretprovider.DeleteSecretInput{SecretID: record.ID}); err != nil {
+		return writeRefused(fmt.Errorf("delete %s: %w", target.Name, err), target.Entry.Profile)
+	}
+	s.forgetIndex()
+	return nil
+}
+
+// DeleteVersion removes one revision of the mapped secret, which must exist;
+// disabled revisions can be deleted too.
+func (s Service) DeleteVersion(target MappingTarget, revision uint32) error {
+	record, api, err := s.resolveTarget(target)
+	if err != nil {
+		return err
+	}
+	versions, err := api.ListSecretVersions(secretprovider.ListSecretVersionsInput{SecretID: record.ID})
+	if err != nil {
+		return fmt.Errorf("list versions %s: %w", target.Name, err)
+	}
+	found := false
+	for _, version := range versions {
+		found = found || version.Revision == revision
+	}
+	if !found {
+		return fmt.Errorf("delete %s: revision %d does not exist", target.Name, revision)
+	}
+	if err := api.DeleteSecretVersion(secretprovider.DeleteSecretVersionInput{SecretID: record.ID, Revision: revision}); err != nil {
+		return writeRefused(fmt.Errorf("delete %s rev=%d: %w", target.Name, revision, err), target.Entry.Profile)
+	}
+	return nil
+}
+
+// resolveTarget resolves the secret a delete or disable changes. No payload is
+// read, so a type differing from mapping.type does not matter.
+func (s Service) resolveTarget(target MappingTarget) (*secretprovider.SecretRecord, secretprovider.SecretAPI, error) {
+	record, err := s.lookupMappedSecret(target.Name, target.Entry)
+	var mismatch *SecretTypeMismatchError
+	if errors.As(err, &mismatch) {
+		record, err = &mismatch.Record, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("resolve %s: %w", target.Name, err)
+	}
+	api, _ := s.apiFor(target.Entry) // opened by the lookup
+	return record, api, nil
+}
@@ -0,0 +1,123 @@
+package secretsync
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+
+	"github.com/bsmartlabs/dev-vault/internal/dotenv"
+	"github.com/bsmartlabs/dev-vault/internal/secretprovider"
+)
+
+// FormatFinding is an enabled mapping whose format or declared type does not
+// suit the remote secret, with the pairing that would, or whose local dotenv
+// file does not parse.
+type FormatFinding struct {
+	Name       string `json:"name"`
+	Type       string `json:"type"`
+	Format     string `json:"format"`
+	Problem    string `json:"problem"`
+	Suggestion string `json:"suggestion"`
+	// File and Line locate a problem in the local file; empty and zero for
+	// problems with the remote secret.
+	File string `json:"file,omitempty"`
+	Line int    `json:"line,omitempty"`
+}
+
+// FormatFindings looks up every enabled mapping and reports the type/format
+// pairings that pull or push would trip over, and the local dotenv files that
+// do not parse. Only metadata and local files are read.
+func (s Service) FormatFindings() ([]FormatFinding, int, error) {
+	s = s.withIndex()
+	targets := s.allTargets()
+	findings := make([]FormatFinding, 0)
+	for _, target := range targets {
+		local, err := s.localFinding(target)
+		if err != nil {
+			return nil, 0, err
+		}
+		if local != nil {
+			findings = append(findings, *local)
+		}
+		// Look up without the declared type so a mismatch shows up as such
+		// instead of as a missing secret.
+		lookup := target.Entry
+		lookup.Type = ""
+		record, err := s.lookupMappedSecret(target.Name, lookup)
+		var notFound *SecretLookupMissError
+		if errors.As(err, &notFound) {
+			findings = append(findings, FormatFinding{
+				Name:       target.Name,
+				Format:     string(target.Entry.Format),
+				Problem:    fmt.Sprintf("secret not found at path %s", target.Entry.Path),
+				Suggestion: "create it with push --create-missing or generate, or fix mapping.path",
+			})
+			continue
+		}
+		if err != nil {
+			return nil, 0, fmt.Errorf("resolve %s: %w", target.Name, err)
+		}
+		if problem, suggestion := formatProblem(record.Type, target.Entry); problem != "" {
+			findings = append(findings, FormatFinding{
+				Name:       target.Name,
+				Type:       string(record.Type),
+				Format:     string(target.Entry.Format),
+				Problem:    problem,
+				Suggestion: suggestion,
+			})
+		}
+	}
+	return findings, len(targets), nil
+}
+
+// localFinding reports the line of a local dotenv file that does not parse,
+// which push and the tools reading the file would fail on. A missing file is
+// not a problem: pull creates it.
+func (s Service) localFinding(target MappingTarget) (*FormatFinding, error) {
+	if target.Entry.Format != MappingFormatDotenv || len(target.Entry.Files) > 0 {
+		return nil, nil
+	}
+	path, err := s.resolvePath(s.cfg.Root, target.Entry.File)
+	if err != nil {
+		return nil, fmt.Errorf("mapping %s: resolve file: %w", target.Name, err)
+	}
+	raw, err := s.fs.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("doctor %s: read %s: %w", target.Name, path, err)
+	}
+	_, err = dotenv.Parse(raw)
+	if err == nil {
+		return nil, nil
+	}
+	finding := &FormatFinding{
+		Name:       target.Name,
+		Format:     string(target.Entry.Format),
+		Problem:    fmt.Sprintf("%s does not parse as dotenv: %v", target.Entry.File, err),
+		Suggestion: "fix the file, or replace it with pull --overwrite",
+		File:       path,
+	}
+	var syntaxErr *dotenv.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		finding.Line = syntaxErr.Line
+	}
+	return finding, nil
+}
+
+func formatProblem(secretType secretprovider.SecretType, entry MappingEntry) (string, string) {
+	if entry.Type != "" && entry.Type != string(secretType) {
+		return fmt.Sprintf("mapping.type is %s but the secret is %s, so lookups by type miss it", entry.Type, secretType),
+			fmt.Sprintf("set mapping.type to %s", secretType)
+	}
+	switch {
+	case entry.Format != MappingFormatRaw && (secretType == secretprovider.SecretTypeCertificate || secretType == secretprovider.SecretTypeSSHKey):
+		return fmt.Sprintf("%s payloads are PEM text, not a JSON object, so pull fails as %s", secretType, entry.Format),
+			"use format raw"
+	case entry.Format == MappingFormatRaw && secretType == secretprovider.SecretTypeKeyValue:
+		return "key_value payloads are JSON objects; raw writes the JSON as is instead of a .env file",
+			"use format dotenv"
+	}
+	return "", ""
+}
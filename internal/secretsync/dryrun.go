@@ -0,0 +1,33 @@
+package secretsync
+
+import "bytes"
+
+// FileChange is what a dry-run pull would do to the files of a target.
+type FileChange string
+
+const (
+	FileCreate    FileChange = "create"
+	FileOverwrite FileChange = "overwrite"
+	FileUnchanged FileChange = "unchanged"
+)
+
+// WithDryRun returns a copy of the service whose Pull, Push, Retain, and
+// RecordState resolve secrets and read and convert payloads as usual, but
+// write no file and make no API call that changes anything. Results then
+// describe what would happen: PullResult.Change, PushResult.Create, and the
+// revisions Retain would disable.
+func (s Service) WithDryRun() Service {
+	s.dryRun = true
+	return s
+}
+
+// plannedChange is what writing data to path would do.
+func (s Service) plannedChange(path string, data []byte) FileChange {
+	if _, err := s.fs.Lstat(path); err != nil {
+		return FileCreate
+	}
+	if existing, err := s.fs.ReadFile(path); err == nil && bytes.Equal(existing, data) {
+		return FileUnchanged
+	}
+	return FileOverwrite
+}
@@ -0,0 +1,38 @@
+package secretsync
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/bsmartlabs/dev-vault/internal/dotenv"
+	"github.com/bsmartlabs/dev-vault/internal/secretworkflow"
+)
+
+// Env returns the variables a mapped key_value secret holds, read from the
+// provider without writing anything locally. A dotenv mapping yields what
+// pull would write, with compose, key filters, placeholders, and affixes
+// applied; any other mapping yields the keys of the secret as they are.
+func (s Service) Env(target MappingTarget) (map[string]string, error) {
+	access, err := s.accessVersion(target.Name, target.Entry)
+	if err != nil {
+		return nil, err
+	}
+	var rendered []byte
+	if target.Entry.Format == MappingFormatDotenv {
+		if rendered, _, err = s.pullPayload(target, access); err != nil {
+			return nil, err
+		}
+	} else {
+		var keys map[string]json.RawMessage
+		// The decode error is dropped: it can quote the payload.
+		if err := json.Unmarshal(access.Data, &keys); err != nil || keys == nil {
+			return nil, fmt.Errorf("env %s: expected a JSON object payload", target.Name)
+		}
+		rendered, _ = secretworkflow.JSONToDotenv(access.Data) // an object always converts
+	}
+	env, err := dotenv.Parse(rendered)
+	if err != nil {
+		return nil, fmt.Errorf("env %s: %w", target.Name, err)
+	}
+	return env, nil
+}
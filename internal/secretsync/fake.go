@@ -0,0 +1,89 @@
+package secretsync
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/bsmartlabs/dev-vault/internal/dotenv"
+	"github.com/bsmartlabs/dev-vault/internal/fixtures"
+	"github.com/bsmartlabs/dev-vault/internal/fsx"
+)
+
+// fakeMarker follows the dotenv marker in files written by PullFake; push
+// refuses files that carry it so placeholders never replace real values.
+const fakeMarker = "# fake values from pull --fake; push refuses this file\n"
+
+// PullFake writes each dotenv target with the keys its mapping lists in
+// fake_keys, plus those of its compose layers, set to placeholders. Nothing is
+// read from the provider, so it works without access to the secrets. Key
+// filters and affixes apply as on a real pull.
+func (s Service) PullFake(targets []MappingTarget, overwrite bool) ([]PullResult, error) {
+	results := make([]PullResult, 0, len(targets))
+	for _, target := range targets {
+		outPath, err := s.resolvePath(s.cfg.Root, target.Entry.File)
+		if err != nil {
+			return nil, fmt.Errorf("mapping %s: resolve file: %w", target.Name, err)
+		}
+		if info, err := s.fs.Lstat(outPath); err == nil {
+			if err := fsx.CheckReplaceable(info); err != nil {
+				return nil, fmt.Errorf("pull %s: refusing to replace %s: %w", target.Name, outPath, err)
+			}
+		}
+		payload, err := s.fakePayload(target)
+		if err != nil {
+			return nil, fmt.Errorf("fake %s: %w", target.Name, err)
+		}
+		if err := s.fs.WriteFileAtomic(outPath, payload, 0o600, overwrite); err != nil {
+			if errors.Is(err, fsx.ErrExists) {
+				return nil, fmt.Errorf("pull %s: file exists (use --overwrite): %s", target.Name, outPath)
+			}
+			return nil, fmt.Errorf("pull %s: write %s: %w", target.Name, outPath, err)
+		}
+		if err := s.markFile(outPath, target.Name, 0); err != nil {
+			return nil, fmt.Errorf("pull %s: mark %s: %w", target.Name, outPath, err)
+		}
+		results = append(results, PullResult{Name: target.Name, File: target.Entry.File})
+	}
+	return results, nil
+}
+
+// HasFakeKeys reports whether PullFake has keys to write for target.
+func (s Service) HasFakeKeys(target MappingTarget) bool {
+	keys, err := s.fakeKeys(target)
+	return err == nil && len(keys) > 0
+}
+
+func (s Service) fakeKeys(target MappingTarget) ([]string, error) {
+	layers, err := s.composeLayers(target.Name, target.Entry, map[string]bool{})
+	if err != nil {
+		return nil, err
+	}
+	var keys []string
+	for _, name := range layers {
+		keys = append(keys, s.cfg.Mapping[name].FakeKeys...)
+	}
+	return append(keys, target.Entry.FakeKeys...), nil
+}
+
+func (s Service) fakePayload(target MappingTarget) ([]byte, error) {
+	keys, err := s.fakeKeys(target)
+	if err != nil {
+		return nil, err
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("no fake_keys in the mapping or its compose layers")
+	}
+	env := make(map[string]string, len(keys))
+	for _, key := range keys {
+		if target.Entry.Keys == nil || target.Entry.Keys.Allows(key) {
+			env[target.Entry.KeyPrefix+key+target.Entry.KeySuffix] = fixtures.Placeholder(key)
+		}
+	}
+	return append(append(dotenvMarker(target.Name), fakeMarker...), dotenv.Render(env)...), nil
+}
+
+// isFakeFile reports whether data was written by PullFake.
+func isFakeFile(data []byte) bool {
+	return bytes.Contains(data, []byte(fakeMarker))
+}
@@ -0,0 +1,217 @@
+package secretsync
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/bsmartlabs/dev-vault/internal/fsx"
+	"github.com/bsmartlabs/dev-vault/internal/secretprovider"
+)
+
+// A mapping with files splits a key_value secret: pull writes the value of
+// each listed key, as-is, to its own file, and push reads the files back into
+// those keys. Keys the mapping does not list are left alone on both sides.
+
+// splitFile is one key of a split secret with the file holding its value.
+type splitFile struct {
+	Key  string
+	File string
+}
+
+// splitFiles lists the files of a split secret in key order.
+func (e MappingEntry) splitFiles() []splitFile {
+	files := make([]splitFile, 0, len(e.Files))
+	for key, file := range e.Files {
+		files = append(files, splitFile{Key: key, File: file})
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Key < files[j].Key })
+	return files
+}
+
+// FileLabel names the local side of the entry in output: its file, or the
+// files of a split secret in key order.
+func (e MappingEntry) FileLabel() string {
+	if len(e.Files) == 0 {
+		return e.File
+	}
+	var names []string
+	for _, file := range e.splitFiles() {
+		names = append(names, file.File)
+	}
+	return strings.Join(names, ", ")
+}
+
+// localPaths resolves the files of entry: its file, or those of a split
+// secret in key order.
+func (s Service) localPaths(entry MappingEntry) ([]string, error) {
+	if len(entry.Files) == 0 {
+		path, err := s.resolvePath(s.cfg.Root, entry.File)
+		if err != nil {
+			return nil, err
+		}
+		return []string{path}, nil
+	}
+	var paths []string
+	for _, file := range entry.splitFiles() {
+		path, err := s.resolvePath(s.cfg.Root, file.File)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file.Key, err)
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+// splitPayload returns the value of each key of files, in order. Every key
+// must be present and hold a string.
+func splitPayload(payload []byte, files []splitFile) ([][]byte, error) {
+	var keys map[string]json.RawMessage
+	// The decode error is dropped: it can quote the payload.
+	if err := json.Unmarshal(payload, &keys); err != nil || keys == nil {
+		return nil, errors.New("expected a JSON object payload")
+	}
+	values := make([][]byte, 0, len(files))
+	for _, file := range files {
+		raw, ok := keys[file.Key]
+		if !ok {
+			return nil, fmt.Errorf("key %s is missing from the secret", file.Key)
+		}
+		var value string
+		if err := json.Unmarshal(raw, &value); err != nil {
+			return nil, fmt.Errorf("key %s does not hold a string", file.Key)
+		}
+		values = append(values, []byte(value))
+	}
+	return values, nil
+}
+
+// renderFiles is what pull writes from access to each of the entry's local
+// paths, in localPaths order.
+func (s Service) renderFiles(target MappingTarget, access *secretprovider.SecretVersionRecord) ([][]byte, error) {
+	if len(target.Entry.Files) == 0 {
+		payload, _, err := s.pullPayload(target, access)
+		if err != nil {
+			return nil, err
+		}
+		return [][]byte{payload}, nil
+	}
+	values, err := splitPayload(access.Data, target.Entry.splitFiles())
+	if err != nil {
+		return nil, fmt.Errorf("files %s: %w", target.Name, err)
+	}
+	return values, nil
+}
+
+// pullFiles writes each file of a split secret. All of them are checked
+// before the first is written, so a refused pull leaves every file as it was.
+func (s Service) pullFiles(target MappingTarget, overwrite bool) (PullResult, error) {
+	paths, err := s.localPaths(target.Entry)
+	if err != nil {
+		return PullResult{}, fmt.Errorf("mapping %s: resolve file: %w", target.Name, err)
+	}
+	for _, path := range paths {
+		info, err := s.fs.Lstat(path)
+		if err != nil {
+			continue
+		}
+		if err := fsx.CheckReplaceable(info); err != nil {
+			return PullResult{}, fmt.Errorf("pull %s: refusing to replace %s: %w", target.Name, path, err)
+		}
+		if !overwrite {
+			return PullResult{}, fmt.Errorf("pull %s: file exists (use --overwrite): %s", target.Name, path)
+		}
+	}
+
+	record, access, err := s.resolveVersion(target.Name, target.Entry)
+	if err != nil {
+		return PullResult{}, err
+	}
+	values, err := splitPayload(access.Data, target.Entry.splitFiles())
+	if err != nil {
+		return PullResult{}, fmt.Errorf("files %s: %w", target.Name, err)
+	}
+	if s.dryRun {
+		result := pullResult(target, access)
+		result.Change = FileUnchanged
+		for i, path := range paths {
+			switch s.plannedChange(path, values[i]) {
+			case FileOverwrite:
+				result.Change = FileOverwrite
+			case FileCreate:
+				if result.Change == FileUnchanged {
+					result.Change = FileCreate
+				}
+			}
+		}
+		return result, nil
+	}
+	created, err := s.versionCreatedAt(record, target.Entry, access)
+	if err != nil {
+		return PullResult{}, fmt.Errorf("pull %s: %w", target.Name, err)
+	}
+	for i, path := range paths {
+		mtime := s.pullMtime(path, created)
+		if err := s.fs.WriteFileAtomic(path, values[i], 0o600, true); err != nil {
+			return PullResult{}, fmt.Errorf("pull %s: write %s: %w", target.Name, path, err)
+		}
+		if err := s.markFile(path, target.Name, access.Revision); err != nil {
+			return PullResult{}, fmt.Errorf("pull %s: mark %s: %w", target.Name, path, err)
+		}
+		if !mtime.IsZero() {
+			if err := s.fs.Chtimes(path, time.Time{}, mtime); err != nil {
+				return PullResult{}, fmt.Errorf("pull %s: set mtime of %s: %w", target.Name, path, err)
+			}
+		}
+	}
+	return pullResult(target, access), nil
+}
+
+// readSplitFiles builds the payload a split secret pushes: the latest remote
+// payload with each listed key set to the content of its file, so keys the
+// mapping does not list survive.
+func (s Service) readSplitFiles(name string, entry MappingEntry) ([]byte, error) {
+	paths, err := s.localPaths(entry)
+	if err != nil {
+		return nil, fmt.Errorf("mapping %s: resolve file: %w", name, err)
+	}
+	local := make(map[string]string, len(paths))
+	for i, file := range entry.splitFiles() {
+		raw, err := s.fs.ReadFile(paths[i])
+		if err != nil {
+			return nil, fmt.Errorf("push %s: read %s: %w", name, paths[i], err)
+		}
+		// JSON strings cannot carry other bytes unchanged.
+		if !utf8.Valid(raw) {
+			return nil, fmt.Errorf("push %s: %s is not UTF-8 text, which a key_value secret needs", name, paths[i])
+		}
+		local[file.Key] = string(raw)
+	}
+
+	merged := make(map[string]any)
+	access, err := s.accessVersion(name, entry)
+	var notFound *SecretLookupMissError
+	switch {
+	case errors.As(err, &notFound):
+		// A secret push would create has no other keys to keep.
+	case err != nil:
+		return nil, err
+	default:
+		var remote map[string]json.RawMessage
+		if err := json.Unmarshal(access.Data, &remote); err != nil {
+			return nil, fmt.Errorf("push %s: remote payload: expected a JSON object", name)
+		}
+		for key, value := range remote {
+			merged[key] = value
+		}
+	}
+	for key, value := range local {
+		merged[key] = value
+	}
+	payload, _ := json.Marshal(merged) // strings and decoded JSON always encode
+	return payload, nil
+}
@@ -0,0 +1,42 @@
+package secretsync
+
+import (
+	"fmt"
+
+	"github.com/bsmartlabs/dev-vault/internal/dotenv"
+)
+
+// RemoteDotenvValues reads the dotenv targets from the provider and returns
+// their variables as pull would write them, compose, key filters and affixes
+// applied, without writing anything locally. Raw mappings are skipped. A key
+// set by two targets is an error rather than a silent override.
+func (s Service) RemoteDotenvValues(targets []MappingTarget) (map[string]string, error) {
+	s = s.withIndex()
+	values := make(map[string]string)
+	owners := make(map[string]string)
+	for _, target := range targets {
+		if target.Entry.Format != MappingFormatDotenv {
+			continue
+		}
+		access, err := s.Access(target)
+		if err != nil {
+			return nil, err
+		}
+		payload, _, err := s.pullPayload(target, access)
+		if err != nil {
+			return nil, err
+		}
+		env, err := dotenv.Parse(payload)
+		if err != nil {
+			return nil, fmt.Errorf("parse %s: %w", target.Name, err)
+		}
+		for key, value := range env {
+			if owner, ok := owners[key]; ok {
+				return nil, fmt.Errorf("key %s is set by both %s and %s", key, owner, target.Name)
+			}
+			owners[key] = target.Name
+			values[key] = value
+		}
+	}
+	return values, nil
+}
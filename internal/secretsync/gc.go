@@ -0,0 +1,94 @@
+package secretsync
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/bsmartlabs/dev-vault/internal/secretprovider"
+)
+
+// Actions GC takes on a version.
+const (
+	GCDisable = "disable"
+	GCDelete  = "delete"
+)
+
+// GCVersion is a version of a mapped secret GC removes, and how.
+type GCVersion struct {
+	Revision  uint32
+	Status    string
+	CreatedAt time.Time
+	Action    string
+}
+
+// PlanGC lists the versions of the mapped secret that GC would remove, newest
+// first; nothing is changed. The keep newest enabled versions stay, and so
+// does a pinned revision. Older enabled versions are disabled; with remove,
+// every other version is deleted instead, disabled ones included. Versions
+// the provider lists as neither enabled nor disabled are left alone.
+func (s Service) PlanGC(target MappingTarget, keep int, remove bool) ([]GCVersion, error) {
+	record, api, err := s.resolveTarget(target)
+	if err != nil {
+		return nil, err
+	}
+	versions, err := api.ListSecretVersions(secretprovider.ListSecretVersionsInput{SecretID: record.ID})
+	if err != nil {
+		return nil, fmt.Errorf("list versions %s: %w", target.Name, err)
+	}
+	sort.Slice(versions, func(i, j int) bool { return versions[i].Revision > versions[j].Revision })
+
+	var plan []GCVersion
+	kept := 0
+	for _, version := range versions {
+		enabled := version.Status == secretprovider.VersionStatusEnabled
+		switch {
+		case enabled && kept < keep:
+			kept++
+			continue
+		case target.Entry.Revision != 0 && version.Revision == target.Entry.Revision:
+			continue
+		case !enabled && (!remove || version.Status != secretprovider.VersionStatusDisabled):
+			continue
+		}
+		action := GCDisable
+		if remove {
+			action = GCDelete
+		}
+		plan = append(plan, GCVersion{Revision: version.Revision, Status: version.Status, CreatedAt: version.CreatedAt, Action: action})
+	}
+	return plan, nil
+}
+
+// GC applies a plan from PlanGC and returns what it did, in plan order. A
+// provider that cannot delete one version has enabled ones disabled instead
+// and disabled ones left as they are. When a change fails, what was done so
+// far is returned with the error.
+func (s Service) GC(target MappingTarget, plan []GCVersion) ([]GCVersion, error) {
+	record, api, err := s.resolveTarget(target)
+	if err != nil {
+		return nil, err
+	}
+	var done []GCVersion
+	for _, version := range plan {
+		if version.Action == GCDelete {
+			err := api.DeleteSecretVersion(secretprovider.DeleteSecretVersionInput{SecretID: record.ID, Revision: version.Revision})
+			switch {
+			case err == nil:
+				done = append(done, version)
+				continue
+			case !errors.Is(err, errors.ErrUnsupported):
+				return done, writeRefused(fmt.Errorf("delete %s rev=%d: %w", target.Name, version.Revision, err), target.Entry.Profile)
+			case version.Status != secretprovider.VersionStatusEnabled:
+				continue
+			}
+			version.Action = GCDisable
+		}
+		if err := api.DisableSecretVersion(secretprovider.DisableSecretVersionInput{SecretID: record.ID, Revision: version.Revision}); err != nil {
+			return done, writeRefused(fmt.Errorf("disable %s rev=%d: %w", target.Name, version.Revision, err), target.Entry.Profile)
+		}
+		done = append(done, version)
+	}
+	return done, nil
+}
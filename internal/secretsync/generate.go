@@ -0,0 +1,53 @@
+package secretsync
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/bsmartlabs/dev-vault/internal/secretworkflow"
+)
+
+// Generate creates a new secret holding values and writes the matching local
+// dotenv file. It refuses to replace an existing secret or local file, so
+// generated credentials never overwrite ones already in use.
+func (s Service) Generate(target MappingTarget, values map[string]string, description string) (PushResult, error) {
+	outPath, err := s.resolvePath(s.cfg.Root, target.Entry.File)
+	if err != nil {
+		return PushResult{}, fmt.Errorf("mapping %s: resolve file: %w", target.Name, err)
+	}
+	if _, err := s.fs.Stat(outPath); err == nil {
+		return PushResult{}, fmt.Errorf("generate %s: file exists: %s", target.Name, outPath)
+	}
+	var notFound *SecretLookupMissError
+	if _, err := s.lookupMappedSecret(target.Name, target.Entry); err == nil {
+		return PushResult{}, fmt.Errorf("generate %s: secret already exists (use push to add a version)", target.Name)
+	} else if !errors.As(err, &notFound) {
+		return PushResult{}, fmt.Errorf("resolve %s: %w", target.Name, err)
+	}
+
+	payload, _ := json.Marshal(values) // string maps always encode
+	local := payload
+	if target.Entry.KeyPrefix != "" || target.Entry.KeySuffix != "" {
+		local, _ = addKeyAffixes(payload, target.Entry.KeyPrefix, target.Entry.KeySuffix) // payload is a JSON object
+	}
+	rendered, _ := secretworkflow.JSONToDotenv(local) // payload is a JSON object
+	rendered = append(dotenvMarker(target.Name), rendered...)
+
+	created, err := s.ResolveMappedSecret(target.Name, target.Entry, true)
+	if err != nil {
+		return PushResult{}, err
+	}
+	api, _ := s.apiFor(target.Entry) // opened by the lookup
+	version, err := api.CreateSecretVersion(createSecretVersionInput(created.ID, payload, s.pushDescription(description), false))
+	if err != nil {
+		return PushResult{}, writeRefused(fmt.Errorf("generate %s: create version: %w", target.Name, err), target.Entry.Profile)
+	}
+	if err := s.fs.WriteFileAtomic(outPath, rendered, 0o600, false); err != nil {
+		return PushResult{}, fmt.Errorf("generate %s: write %s (secret created; run pull to retry): %w", target.Name, outPath, err)
+	}
+	if err := s.markFile(outPath, target.Name, version.Revision); err != nil {
+		return PushResult{}, fmt.Errorf("generate %s: mark %s: %w", target.Name, outPath, err)
+	}
+	return PushResult{Name: target.Name, Revision: version.Revision}, nil
+}
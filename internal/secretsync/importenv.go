@@ -0,0 +1,48 @@
+package secretsync
+
+import (
+	"errors"
+	"fmt"
+)
+
+// CheckImportable reports whether ImportEnv can create target's secret: its
+// local file must parse and the secret must not exist yet. Nothing is
+// written.
+func (s Service) CheckImportable(target MappingTarget) error {
+	_, err := s.importPayload(target)
+	return err
+}
+
+// ImportEnv creates target's secret from its existing local file, for
+// onboarding a project that predates Secret Manager. It refuses a secret that
+// already exists, so an import never adds a version to one in use.
+func (s Service) ImportEnv(target MappingTarget, description string) (PushResult, error) {
+	payload, err := s.importPayload(target)
+	if err != nil {
+		return PushResult{}, err
+	}
+	created, err := s.ResolveMappedSecret(target.Name, target.Entry, true)
+	if err != nil {
+		return PushResult{}, err
+	}
+	api, _ := s.apiFor(target.Entry) // opened by the lookup
+	version, err := api.CreateSecretVersion(createSecretVersionInput(created.ID, payload, s.pushDescription(description), false))
+	if err != nil {
+		return PushResult{}, writeRefused(fmt.Errorf("import %s: create version: %w", target.Name, err), target.Entry.Profile)
+	}
+	return PushResult{Name: target.Name, Revision: version.Revision}, nil
+}
+
+func (s Service) importPayload(target MappingTarget) ([]byte, error) {
+	payload, err := s.readPushPayload(target.Name, target.Entry)
+	if err != nil {
+		return nil, err
+	}
+	var notFound *SecretLookupMissError
+	if _, err := s.lookupMappedSecret(target.Name, target.Entry); err == nil {
+		return nil, fmt.Errorf("import %s: secret already exists (map it and use pull)", target.Name)
+	} else if !errors.As(err, &notFound) {
+		return nil, fmt.Errorf("resolve %s: %w", target.Name, err)
+	}
+	return payload, nil
+}
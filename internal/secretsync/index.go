@@ -0,0 +1,73 @@
+package secretsync
+
+import (
+	"sync"
+
+	"github.com/bsmartlabs/dev-vault/internal/config"
+	"github.com/bsmartlabs/dev-vault/internal/secretprovider"
+)
+
+// secretIndex answers the secret lookups of one batch from a single listing
+// per profile, so a command over many mappings costs one ListSecrets call
+// instead of one per mapping. The first lookup of a profile still asks the
+// provider for just that secret, which is cheaper when it is the only one.
+//
+// Only Scaleway batches use an index: its unfiltered listing of a project
+// pages like a filtered one. Vault would walk the whole mount and read the
+// metadata of every secret, and Secrets Manager would page through every
+// secret of the account, so there each lookup keeps its filtered call.
+type secretIndex struct {
+	mu      sync.Mutex
+	asked   map[string]bool
+	records map[string][]secretprovider.SecretRecord
+}
+
+// withIndex returns a copy of the service whose lookups share a new index.
+// Batch operations call it on entry, so each batch reads the provider again.
+// Other providers than Scaleway get no index; see secretIndex.
+func (s Service) withIndex() Service {
+	if s.cfg.Provider != "" && s.cfg.Provider != config.ProviderScaleway {
+		return s
+	}
+	s.index = &secretIndex{asked: make(map[string]bool), records: make(map[string][]secretprovider.SecretRecord)}
+	return s
+}
+
+// listForLookup returns the secrets of api matching the name, path, and type
+// of req, as api.ListSecrets(req) would.
+func (s Service) listForLookup(profile string, api secretprovider.SecretAPI, req secretprovider.ListSecretsInput) ([]secretprovider.SecretRecord, error) {
+	if s.index == nil {
+		return api.ListSecrets(req)
+	}
+	s.index.mu.Lock()
+	defer s.index.mu.Unlock()
+	records, ok := s.index.records[profile]
+	if !ok {
+		if !s.index.asked[profile] {
+			s.index.asked[profile] = true
+			return api.ListSecrets(req)
+		}
+		all, err := api.ListSecrets(secretprovider.ListSecretsInput{})
+		if err != nil {
+			return nil, err
+		}
+		s.index.records[profile], records = all, all
+	}
+	var matches []secretprovider.SecretRecord
+	for _, record := range records {
+		if record.Name == req.Name && record.Path == req.Path && (req.Type == "" || record.Type == req.Type) {
+			matches = append(matches, record)
+		}
+	}
+	return matches, nil
+}
+
+// forgetIndex drops the listings after a secret was created or deleted.
+func (s Service) forgetIndex() {
+	if s.index == nil {
+		return
+	}
+	s.index.mu.Lock()
+	defer s.index.mu.Unlock()
+	clear(s.index.records)
+}
@@ -0,0 +1,72 @@
+package secretsync
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInterrupted is wrapped by every *InterruptedError.
+var ErrInterrupted = errors.New("interrupted")
+
+// InterruptedError reports a batch that stopped between targets. Completed
+// targets were fully written or pushed; aborted ones were not touched.
+type InterruptedError struct {
+	Operation string
+	Completed []string
+	Aborted   []string
+}
+
+func (e *InterruptedError) Error() string {
+	total := len(e.Completed) + len(e.Aborted)
+	return fmt.Sprintf("interrupted: %s completed %d/%d; aborted: %s", e.Operation, len(e.Completed), total, strings.Join(e.Aborted, ", "))
+}
+
+func (e *InterruptedError) Unwrap() error {
+	return ErrInterrupted
+}
+
+// WithInterrupt returns a copy of the service whose batch operations stop
+// before the next target once done is closed. The target in progress always
+// finishes, so an atomic write is never left half-done.
+func (s Service) WithInterrupt(done <-chan struct{}) Service {
+	s.interrupt = done
+	return s
+}
+
+// WithProgress returns a copy of the service that calls done with each target
+// name as soon as that target has been written or pushed.
+func (s Service) WithProgress(done func(name string)) Service {
+	s.onDone = done
+	return s
+}
+
+func (s Service) targetDone(name string) {
+	if s.onDone != nil {
+		s.onDone(name)
+	}
+}
+
+func (s Service) interrupted() bool {
+	select {
+	case <-s.interrupt:
+		return true
+	default:
+		return false
+	}
+}
+
+func interruptedError(operation string, targets []MappingTarget, completed int) error {
+	names := func(items []MappingTarget) []string {
+		out := make([]string, 0, len(items))
+		for _, item := range items {
+			out = append(out, item.Name)
+		}
+		return out
+	}
+	return &InterruptedError{
+		Operation: operation,
+		Completed: names(targets[:completed]),
+		Aborted:   names(targets[completed:]),
+	}
+}
@@ -0,0 +1,98 @@
+package secretsync
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/bsmartlabs/dev-vault/internal/config"
+	"github.com/bsmartlabs/dev-vault/internal/secretprovider"
+)
+
+type InventoryEntry struct {
+	Name         string
+	File         string
+	Remote       bool
+	SecretID     string
+	Tags         []string
+	UpdatedAt    time.Time
+	Local        bool
+	LocalModTime time.Time
+}
+
+type Inventory struct {
+	Entries  []InventoryEntry
+	Unmapped []ListRecord
+}
+
+// Inventory cross-references every mapping entry with the remote project
+// (one list call per profile in use) and the local filesystem, and collects
+// remote dev secrets of the manifest's project that no mapping entry covers.
+func (s Service) Inventory() (Inventory, error) {
+	targets := s.allTargets()
+	remoteByProfile := make(map[string][]secretprovider.SecretRecord)
+	profiles := []string{""}
+	for _, target := range targets {
+		profiles = append(profiles, target.Entry.Profile)
+	}
+	for _, profile := range profiles {
+		if _, ok := remoteByProfile[profile]; ok {
+			continue
+		}
+		api, err := s.apiFor(MappingEntry{Profile: profile})
+		if err != nil {
+			return Inventory{}, fmt.Errorf("list secrets: %w", err)
+		}
+		records, err := api.ListSecrets(secretprovider.ListSecretsInput{})
+		if err != nil {
+			return Inventory{}, fmt.Errorf("list secrets: %w", err)
+		}
+		remoteByProfile[profile] = records
+	}
+	remote := remoteByProfile[""]
+
+	local := s.LocalFiles(targets)
+	inv := Inventory{Entries: make([]InventoryEntry, 0, len(targets))}
+	for i, target := range targets {
+		entry := InventoryEntry{
+			Name:         target.Name,
+			File:         target.Entry.FileLabel(),
+			Local:        local[i].Present,
+			LocalModTime: local[i].ModTime,
+		}
+		for _, record := range remoteByProfile[target.Entry.Profile] {
+			if record.Name == target.Name && record.Path == inventoryPath(target.Entry) {
+				entry.Remote = true
+				entry.SecretID = record.ID
+				entry.Tags = record.Tags
+				entry.UpdatedAt = record.UpdatedAt
+				break
+			}
+		}
+		inv.Entries = append(inv.Entries, entry)
+	}
+
+	for _, record := range remote {
+		if !config.IsDevSecretName(record.Name) {
+			continue
+		}
+		if _, mapped := s.cfg.Mapping[record.Name]; mapped {
+			continue
+		}
+		inv.Unmapped = append(inv.Unmapped, ListRecord{
+			ID:   record.ID,
+			Name: record.Name,
+			Path: record.Path,
+			Type: string(record.Type),
+		})
+	}
+	sort.Slice(inv.Unmapped, func(i, j int) bool { return inv.Unmapped[i].Name < inv.Unmapped[j].Name })
+	return inv, nil
+}
+
+func inventoryPath(entry MappingEntry) string {
+	if entry.Path == "" {
+		return "/"
+	}
+	return entry.Path
+}
@@ -0,0 +1,141 @@
+package secretsync
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+// addKeyAffixes renames every key of a JSON-object payload to
+// prefix+key+suffix, the form the local file uses.
+func addKeyAffixes(payload []byte, prefix, suffix string) ([]byte, error) {
+	var m map[string]json.RawMessage
+	if err := json.Unmarshal(payload, &m); err != nil {
+		return nil, fmt.Errorf("expected JSON object: %w", err)
+	}
+	renamed := make(map[string]json.RawMessage, len(m))
+	for key, value := range m {
+		renamed[prefix+key+suffix] = value
+	}
+	return json.Marshal(renamed)
+}
+
+// stripKeyAffixes reverses addKeyAffixes before a push. Keys without the
+// prefix and suffix are refused rather than uploaded under their local name.
+func stripKeyAffixes(payload []byte, prefix, suffix string) ([]byte, error) {
+	var m map[string]json.RawMessage
+	if err := json.Unmarshal(payload, &m); err != nil {
+		return nil, fmt.Errorf("expected JSON object: %w", err)
+	}
+	stripped := make(map[string]json.RawMessage, len(m))
+	var foreign []string
+	for key, value := range m {
+		name, ok := strings.CutPrefix(key, prefix)
+		if ok {
+			name, ok = strings.CutSuffix(name, suffix)
+		}
+		if !ok || name == "" {
+			foreign = append(foreign, key)
+			continue
+		}
+		stripped[name] = value
+	}
+	if len(foreign) > 0 {
+		sort.Strings(foreign)
+		return nil, fmt.Errorf("keys not of the form %s<KEY>%s: %s", prefix, suffix, strings.Join(foreign, ", "))
+	}
+	return json.Marshal(stripped)
+}
+
+// Allows reports whether key is one of the keys the filter selects.
+func (f KeyFilter) Allows(key string) bool {
+	included := len(f.Include) == 0
+	for _, pattern := range f.Include {
+		if ok, _ := path.Match(pattern, key); ok {
+			included = true
+			break
+		}
+	}
+	if !included {
+		return false
+	}
+	for _, pattern := range f.Exclude {
+		if ok, _ := path.Match(pattern, key); ok {
+			return false
+		}
+	}
+	return true
+}
+
+// filter drops the keys of a JSON-object payload the filter does not select.
+func (f KeyFilter) filter(payload []byte) ([]byte, error) {
+	var m map[string]json.RawMessage
+	if err := json.Unmarshal(payload, &m); err != nil {
+		return nil, fmt.Errorf("expected JSON object: %w", err)
+	}
+	for key := range m {
+		if !f.Allows(key) {
+			delete(m, key)
+		}
+	}
+	return json.Marshal(m)
+}
+
+// pushKeys returns the payload a mapping pushes from its local keys: merged
+// into the remote payload when the mapping has a key filter, as-is otherwise.
+func (s Service) pushKeys(name string, entry MappingEntry, local []byte) ([]byte, error) {
+	if entry.Keys == nil {
+		return local, nil
+	}
+	merged, err := s.mergeFilteredKeys(name, entry, local)
+	if err != nil {
+		return nil, fmt.Errorf("push %s: %w", name, err)
+	}
+	return merged, nil
+}
+
+// mergeFilteredKeys builds the payload a filtered mapping pushes: the latest
+// remote payload with the selected keys replaced by the local ones, so keys
+// outside the filter survive. Local keys outside the filter are refused.
+func (s Service) mergeFilteredKeys(name string, entry MappingEntry, local []byte) ([]byte, error) {
+	var localKeys map[string]json.RawMessage
+	if err := json.Unmarshal(local, &localKeys); err != nil {
+		return nil, fmt.Errorf("expected JSON object: %w", err)
+	}
+	var outside []string
+	for key := range localKeys {
+		if !entry.Keys.Allows(key) {
+			outside = append(outside, key)
+		}
+	}
+	if len(outside) > 0 {
+		sort.Strings(outside)
+		return nil, fmt.Errorf("keys outside the mapping key filter: %s", strings.Join(outside, ", "))
+	}
+
+	merged := make(map[string]json.RawMessage)
+	access, err := s.accessVersion(name, entry)
+	var notFound *SecretLookupMissError
+	switch {
+	case errors.As(err, &notFound):
+		// A secret push would create has no other keys to keep.
+	case err != nil:
+		return nil, err
+	default:
+		if err := json.Unmarshal(access.Data, &merged); err != nil {
+			return nil, fmt.Errorf("remote payload: expected JSON object: %w", err)
+		}
+		for key := range merged {
+			if entry.Keys.Allows(key) {
+				delete(merged, key)
+			}
+		}
+	}
+	for key, value := range localKeys {
+		merged[key] = value
+	}
+	return json.Marshal(merged)
+}
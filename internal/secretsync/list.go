@@ -2,15 +2,18 @@ package secretsync
 
 import (
 	"fmt"
+	"regexp"
+	"regexp/syntax"
 	"sort"
 	"strings"
+	"sync"
 
 	"github.com/bsmartlabs/dev-vault/internal/config"
 	"github.com/bsmartlabs/dev-vault/internal/secretprovider"
 )
 
 func (s Service) List(query ListQuery) ([]ListRecord, error) {
-	req := secretprovider.ListSecretsInput{}
+	req := secretprovider.ListSecretsInput{ProjectID: query.ProjectID, NamePrefix: regexNamePrefix(query.NameRegex)}
 	if query.Path != "" {
 		req.Path = query.Path
 	}
@@ -45,13 +48,68 @@ func (s Service) List(query ListQuery) ([]ListRecord, error) {
 			continue
 		}
 		filtered = append(filtered, ListRecord{
-			ID:   secretRecord.ID,
-			Name: secretRecord.Name,
-			Path: secretRecord.Path,
-			Type: string(secretRecord.Type),
+			ID:        secretRecord.ID,
+			ProjectID: query.ProjectID,
+			Name:      secretRecord.Name,
+			Path:      secretRecord.Path,
+			Type:      string(secretRecord.Type),
+			Profile:   secretRecord.Profile,
+			Tags:      secretRecord.Tags,
+			UpdatedAt: secretRecord.UpdatedAt,
 		})
 	}
 
 	sort.Slice(filtered, func(i, j int) bool { return filtered[i].Name < filtered[j].Name })
 	return filtered, nil
 }
+
+// regexNamePrefix returns the literal text every name matched by re starts
+// with, or "" when re is not anchored by a case-sensitive literal. It lets the
+// provider skip the other secrets instead of listing the whole project.
+func regexNamePrefix(re *regexp.Regexp) string {
+	if re == nil {
+		return ""
+	}
+	parsed, err := syntax.Parse(re.String(), syntax.Perl)
+	if err != nil || parsed.Op != syntax.OpConcat || parsed.Sub[0].Op != syntax.OpBeginText {
+		return ""
+	}
+	literal := parsed.Sub[1]
+	if literal.Op != syntax.OpLiteral || literal.Flags&syntax.FoldCase != 0 {
+		return ""
+	}
+	return string(literal.Rune)
+}
+
+const listProjectsConcurrency = 4
+
+// ListProjects runs List once per project with bounded concurrency. Results
+// are ordered by the given project order, then by name; the first failing
+// project in that order determines the returned error.
+func (s Service) ListProjects(projectIDs []string, query ListQuery) ([]ListRecord, error) {
+	results := make([][]ListRecord, len(projectIDs))
+	errs := make([]error, len(projectIDs))
+	sem := make(chan struct{}, listProjectsConcurrency)
+	var wg sync.WaitGroup
+	for i, projectID := range projectIDs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			projectQuery := query
+			projectQuery.ProjectID = projectID
+			results[i], errs[i] = s.List(projectQuery)
+		}()
+	}
+	wg.Wait()
+
+	var out []ListRecord
+	for i, projectID := range projectIDs {
+		if errs[i] != nil {
+			return nil, fmt.Errorf("project %s: %w", projectID, errs[i])
+		}
+		out = append(out, results[i]...)
+	}
+	return out, nil
+}
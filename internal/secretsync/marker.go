@@ -0,0 +1,76 @@
+package secretsync
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+)
+
+// Files written by pull and generate carry a marker naming their secret, so
+// managed files can be told apart from hand-made ones. The user.dev-vault
+// extended attribute holds "<name> rev=<revision>" where the filesystem has
+// attributes; dotenv, yaml and toml files also start with a comment naming the
+// secret. The comment leaves the revision out so that pushing the file does
+// not make it differ from what pull would write.
+const (
+	markerXattr        = "user.dev-vault"
+	dotenvMarkerPrefix = "# managed by dev-vault: "
+)
+
+// BaseRevisions keeps the secret and revision of each managed file by path,
+// for platforms and filesystems without the user.dev-vault attribute.
+type BaseRevisions interface {
+	Base(path string) (name string, revision uint32, ok bool)
+	SetBase(path, name string, revision uint32) error
+}
+
+// FileMarker identifies the secret a managed file was written from. Revision
+// is 0 when only the dotenv comment is present.
+type FileMarker struct {
+	Name     string
+	Revision uint32
+}
+
+func dotenvMarker(name string) []byte {
+	return []byte(dotenvMarkerPrefix + name + "\n")
+}
+
+// markFile records the marker of path in the base revisions and sets its
+// attribute. Without attribute support the file itself keeps only its dotenv
+// comment, if any.
+func (s Service) markFile(path, name string, revision uint32) error {
+	err := s.fs.SetXattr(path, markerXattr, fmt.Appendf(nil, "%s rev=%d", name, revision))
+	if err != nil && !errors.Is(err, errors.ErrUnsupported) {
+		return err
+	}
+	if s.bases != nil {
+		return s.bases.SetBase(path, name, revision)
+	}
+	return nil
+}
+
+// readMarker returns the marker of the file at path, preferring the base
+// revisions, then the attribute; ok is false for a file dev-vault did not
+// write.
+func (s Service) readMarker(path string) (marker FileMarker, ok bool) {
+	if s.bases != nil {
+		if name, revision, ok := s.bases.Base(path); ok {
+			return FileMarker{Name: name, Revision: revision}, true
+		}
+	}
+	if value, err := s.fs.GetXattr(path, markerXattr); err == nil {
+		if _, err := fmt.Sscanf(string(value), "%s rev=%d", &marker.Name, &marker.Revision); err == nil {
+			return marker, true
+		}
+	}
+	data, err := s.fs.ReadFile(path)
+	if err != nil {
+		return FileMarker{}, false
+	}
+	line, _, _ := bytes.Cut(data, []byte("\n"))
+	name, found := bytes.CutPrefix(line, []byte(dotenvMarkerPrefix))
+	if !found || len(name) == 0 {
+		return FileMarker{}, false
+	}
+	return FileMarker{Name: string(name)}, true
+}
@@ -0,0 +1,84 @@
+package secretsync
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// placeholderPattern matches ${NAME} and the $${ escape for a literal ${.
+var placeholderPattern = regexp.MustCompile(`\$\$\{|\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// substitutePayload expands placeholders in a raw payload, or in each
+// top-level string value of a dotenv, yaml, toml, json or template
+// (JSON-object) payload so keys and quoting stay intact.
+func (s Service) substitutePayload(payload []byte, format MappingFormat) ([]byte, error) {
+	if format == MappingFormatRaw {
+		expanded, err := s.expandPlaceholders(string(payload))
+		if err != nil {
+			return nil, err
+		}
+		return []byte(expanded), nil
+	}
+	var m map[string]json.RawMessage
+	if err := json.Unmarshal(payload, &m); err != nil {
+		return nil, fmt.Errorf("expected JSON object: %w", err)
+	}
+	for key, raw := range m {
+		var value string
+		if err := json.Unmarshal(raw, &value); err != nil {
+			continue // numbers, booleans, and nested values carry no placeholders
+		}
+		expanded, err := s.expandPlaceholders(value)
+		if err != nil {
+			return nil, fmt.Errorf("key %s: %w", key, err)
+		}
+		m[key], _ = json.Marshal(expanded) // strings always encode
+	}
+	return json.Marshal(m)
+}
+
+// expandPlaceholders replaces every ${NAME} with the environment variable
+// NAME, then the manifest var NAME, then the built-in HOSTNAME. Unresolved
+// placeholders are an error rather than an empty value.
+func (s Service) expandPlaceholders(text string) (string, error) {
+	missing := map[string]bool{}
+	out := placeholderPattern.ReplaceAllStringFunc(text, func(match string) string {
+		if match == "$${" {
+			return "${"
+		}
+		name := match[2 : len(match)-1]
+		value, ok := s.placeholderValue(name)
+		if !ok {
+			missing["${"+name+"}"] = true
+			return match
+		}
+		return value
+	})
+	if len(missing) > 0 {
+		names := make([]string, 0, len(missing))
+		for name := range missing {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return "", fmt.Errorf("unresolved placeholders: %s", strings.Join(names, ", "))
+	}
+	return out, nil
+}
+
+func (s Service) placeholderValue(name string) (string, bool) {
+	if value := s.getenv(name); value != "" {
+		return value, true
+	}
+	if value, ok := s.cfg.Vars[name]; ok {
+		return value, true
+	}
+	if name == "HOSTNAME" {
+		if host, err := s.hostname(); err == nil && host != "" {
+			return host, true
+		}
+	}
+	return "", false
+}
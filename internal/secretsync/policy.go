@@ -0,0 +1,55 @@
+package secretsync
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/bsmartlabs/dev-vault/internal/policy"
+)
+
+// PushPolicyViolations evaluates rules (and the rego hook, if configured)
+// against what Push would upload for the given targets. It only reads local
+// files and secret metadata, so it can run as a preflight before any version
+// is created.
+func (s Service) PushPolicyViolations(targets []MappingTarget, rules policy.Rules) ([]policy.Violation, error) {
+	s = s.withIndex()
+	var violations []policy.Violation
+	input := policy.PushInput{Operation: "push", Secrets: make([]policy.PushSecret, 0, len(targets))}
+	for _, target := range targets {
+		payload, err := s.readPushPayload(target.Name, target.Entry)
+		if err != nil {
+			return nil, err
+		}
+		violations = append(violations, rules.CheckPayload(target.Name, payload)...)
+
+		// A secret that push would create has no tags yet.
+		var tags []string
+		var notFound *SecretLookupMissError
+		existing, _, err := s.lookupCoerced(target.Name, target.Entry)
+		switch {
+		case err == nil:
+			tags = existing.Tags
+		case !errors.As(err, &notFound):
+			return nil, fmt.Errorf("resolve %s: %w", target.Name, err)
+		}
+		violations = append(violations, rules.CheckTags(target.Name, tags)...)
+
+		input.Secrets = append(input.Secrets, policy.PushSecret{
+			Name:      target.Name,
+			Path:      target.Entry.Path,
+			Type:      target.Entry.Type,
+			Format:    string(target.Entry.Format),
+			File:      target.Entry.FileLabel(),
+			SizeBytes: len(payload),
+			Keys:      policy.PayloadKeys(payload),
+			Exists:    err == nil,
+			Tags:      tags,
+		})
+	}
+
+	denials, err := rules.CheckPush(input)
+	if err != nil {
+		return nil, err
+	}
+	return append(violations, denials...), nil
+}
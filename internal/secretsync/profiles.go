@@ -0,0 +1,38 @@
+package secretsync
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/bsmartlabs/dev-vault/internal/secretprovider"
+)
+
+// profileClients holds the provider clients of mappings that set their own
+// profile. Each is opened the first time a mapping needs it, so commands that
+// never touch such a mapping never load its credentials. Copies of a Service
+// share one profileClients.
+type profileClients struct {
+	open func(profile string) (secretprovider.SecretAPI, error)
+
+	mu   sync.Mutex
+	apis map[string]secretprovider.SecretAPI
+}
+
+// apiFor returns the client for entry: the service's own unless the mapping
+// sets a profile and the service was created with Dependencies.OpenProfile.
+func (s Service) apiFor(entry MappingEntry) (secretprovider.SecretAPI, error) {
+	if entry.Profile == "" || s.profiles.open == nil {
+		return s.api, nil
+	}
+	s.profiles.mu.Lock()
+	defer s.profiles.mu.Unlock()
+	if api, ok := s.profiles.apis[entry.Profile]; ok {
+		return api, nil
+	}
+	api, err := s.profiles.open(entry.Profile)
+	if err != nil {
+		return nil, fmt.Errorf("open profile %s: %w", entry.Profile, err)
+	}
+	s.profiles.apis[entry.Profile] = api
+	return api, nil
+}
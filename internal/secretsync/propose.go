@@ -0,0 +1,89 @@
+package secretsync
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/bsmartlabs/dev-vault/internal/config"
+	"github.com/bsmartlabs/dev-vault/internal/secretprovider"
+)
+
+// SkippedSecret is a copy of a secret at Path that ProposeMapping left out,
+// because the name was already mapped from MappedPath.
+type SkippedSecret struct {
+	Name       string
+	Path       string
+	MappedPath string
+}
+
+// ProposeMapping drafts manifest entries for every -dev secret of a project,
+// for review after `init --from-remote`. key_value secrets become dotenv
+// files and the others raw files, named after the secret. A name found under
+// several paths is mapped once, from the first path in sorted order; the
+// copies that were skipped are returned too.
+func (s Service) ProposeMapping(projectID string) (map[string]config.MappingEntry, []SkippedSecret, error) {
+	records, err := s.List(ListQuery{ProjectID: projectID})
+	if err != nil {
+		return nil, nil, err
+	}
+	sort.SliceStable(records, func(i, j int) bool {
+		if records[i].Name != records[j].Name {
+			return records[i].Name < records[j].Name
+		}
+		return records[i].Path < records[j].Path
+	})
+
+	mapping := make(map[string]config.MappingEntry, len(records))
+	mappedPaths := make(map[string]string, len(records))
+	files := make(map[string]bool, len(records))
+	var skipped []SkippedSecret
+	for _, record := range records {
+		if path, ok := mappedPaths[record.Name]; ok {
+			skipped = append(skipped, SkippedSecret{Name: record.Name, Path: record.Path, MappedPath: path})
+			continue
+		}
+		mappedPaths[record.Name] = record.Path
+
+		secretType := secretprovider.SecretType(record.Type)
+		entry := config.MappingEntry{Format: config.MappingFormatRaw, Type: record.Type}
+		if secretType == secretprovider.SecretTypeKeyValue {
+			entry.Format = config.MappingFormatDotenv
+		}
+		if record.Path != "/" {
+			entry.Path = record.Path
+		}
+		entry.File = uniqueFile(proposedFile(record.Name, secretType), files)
+		mapping[record.Name] = entry
+	}
+	return mapping, skipped, nil
+}
+
+// proposedFile names the local file after the secret, with an extension that
+// matches its payload: app-env-dev (key_value) becomes app.env.
+func proposedFile(name string, secretType secretprovider.SecretType) string {
+	base := strings.TrimSuffix(name, "-dev")
+	switch secretType {
+	case secretprovider.SecretTypeKeyValue:
+		return strings.TrimSuffix(base, "-env") + ".env"
+	case secretprovider.SecretTypeCertificate:
+		return base + ".pem"
+	case secretprovider.SecretTypeBasicCredentials, secretprovider.SecretTypeDatabaseCredentials:
+		return base + ".json"
+	default:
+		return base
+	}
+}
+
+// uniqueFile numbers file until it is not in used, then claims it.
+func uniqueFile(file string, used map[string]bool) string {
+	ext := filepath.Ext(file)
+	stem := strings.TrimSuffix(file, ext)
+	candidate := file
+	for i := 2; used[candidate]; i++ {
+		candidate = fmt.Sprintf("%s-%d%s", stem, i, ext)
+	}
+	used[candidate] = true
+	return candidate
+}
@@ -1,57 +1,346 @@
 package secretsync
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/bsmartlabs/dev-vault/internal/certinfo"
+	"github.com/bsmartlabs/dev-vault/internal/config"
+	"github.com/bsmartlabs/dev-vault/internal/dotenv"
 	"github.com/bsmartlabs/dev-vault/internal/fsx"
 	"github.com/bsmartlabs/dev-vault/internal/secretprovider"
 	"github.com/bsmartlabs/dev-vault/internal/secretworkflow"
+	"github.com/bsmartlabs/dev-vault/internal/tomlmap"
+	"github.com/bsmartlabs/dev-vault/internal/yamlmap"
 )
 
+// Pull writes each target in order. On failure it returns the results
+// completed so far together with the error, an *InterruptedError when the
+// service is interrupted, so callers can still report and record the files
+// written before it.
 func (s Service) Pull(targets []MappingTarget, overwrite bool) ([]PullResult, error) {
+	s = s.withIndex()
 	results := make([]PullResult, 0, len(targets))
-	for _, target := range targets {
+	for i, target := range targets {
+		if s.interrupted() {
+			return results, interruptedError("pull", targets, i)
+		}
+		if len(target.Entry.Files) > 0 {
+			result, err := s.pullFiles(target, overwrite)
+			if err != nil {
+				return results, err
+			}
+			results = append(results, result)
+			s.targetDone(target.Name)
+			continue
+		}
 		outPath, err := s.resolvePath(s.cfg.Root, target.Entry.File)
 		if err != nil {
-			return nil, fmt.Errorf("mapping %s: resolve file: %w", target.Name, err)
+			return results, fmt.Errorf("mapping %s: resolve file: %w", target.Name, err)
+		}
+		if info, err := s.fs.Lstat(outPath); err == nil {
+			if err := fsx.CheckReplaceable(info); err != nil {
+				return results, fmt.Errorf("pull %s: refusing to replace %s: %w", target.Name, outPath, err)
+			}
 		}
 
-		resolvedSecret, err := s.lookupMappedSecret(target.Name, target.Entry)
+		record, access, err := s.resolveVersion(target.Name, target.Entry)
 		if err != nil {
-			return nil, fmt.Errorf("resolve %s: %w", target.Name, err)
+			return results, err
 		}
-
-		access, err := s.api.AccessSecretVersion(secretprovider.AccessSecretVersionInput{
-			SecretID: resolvedSecret.ID,
-			Revision: secretprovider.RevisionLatestEnabled,
-		})
+		created, err := s.versionCreatedAt(record, target.Entry, access)
 		if err != nil {
-			return nil, fmt.Errorf("access %s: %w", target.Name, err)
+			return results, fmt.Errorf("pull %s: %w", target.Name, err)
 		}
+		mtime := s.pullMtime(outPath, created)
 
-		payload := access.Data
-		if target.Entry.Format == MappingFormatDotenv {
-			converted, err := secretworkflow.JSONToDotenv(payload)
-			if err != nil {
-				return nil, fmt.Errorf("format dotenv %s: %w", target.Name, err)
+		payload, publicKey, err := s.pullPayload(target, access)
+		if err != nil {
+			return results, err
+		}
+		if target.Entry.PreserveLayout && overwrite {
+			payload = s.keepLayout(target.Name, outPath, payload)
+		}
+		if publicKey != nil && !overwrite {
+			if err := s.checkPublicKeyAbsent(target.Name, outPath); err != nil {
+				return results, err
 			}
-			payload = converted
 		}
 
-		if err := fsx.AtomicWriteFile(outPath, payload, 0o600, overwrite); err != nil {
+		if s.dryRun {
+			change := s.plannedChange(outPath, payload)
+			if change != FileCreate && !overwrite {
+				return results, fmt.Errorf("pull %s: file exists (use --overwrite): %s", target.Name, outPath)
+			}
+			result := pullResult(target, access)
+			result.Change = change
+			results = append(results, result)
+			continue
+		}
+		if err := s.fs.WriteFileAtomic(outPath, payload, 0o600, overwrite); err != nil {
 			if errors.Is(err, fsx.ErrExists) {
-				return nil, fmt.Errorf("pull %s: file exists (use --overwrite): %s", target.Name, outPath)
+				return results, fmt.Errorf("pull %s: file exists (use --overwrite): %s", target.Name, outPath)
+			}
+			return results, fmt.Errorf("pull %s: write %s: %w", target.Name, outPath, err)
+		}
+		if err := s.markFile(outPath, target.Name, access.Revision); err != nil {
+			return results, fmt.Errorf("pull %s: mark %s: %w", target.Name, outPath, err)
+		}
+		if !mtime.IsZero() {
+			if err := s.fs.Chtimes(outPath, time.Time{}, mtime); err != nil {
+				return results, fmt.Errorf("pull %s: set mtime of %s: %w", target.Name, outPath, err)
+			}
+		}
+		if publicKey != nil {
+			if err := s.writePublicKey(target.Name, outPath, publicKey); err != nil {
+				return results, err
 			}
-			return nil, fmt.Errorf("pull %s: write %s: %w", target.Name, outPath, err)
 		}
 
-		results = append(results, PullResult{
-			Name:     target.Name,
-			File:     target.Entry.File,
-			Revision: access.Revision,
-			Type:     string(access.Type),
-		})
+		results = append(results, pullResult(target, access))
+		s.targetDone(target.Name)
 	}
 	return results, nil
 }
+
+// pullResult reports the version pulled for target.
+func pullResult(target MappingTarget, access *secretprovider.SecretVersionRecord) PullResult {
+	result := PullResult{
+		Name:     target.Name,
+		File:     target.Entry.FileLabel(),
+		Revision: access.Revision,
+		Type:     string(access.Type),
+	}
+	if access.Type == secretprovider.SecretTypeCertificate {
+		if bundle, err := certinfo.Parse(access.Data); err == nil {
+			result.Expires = bundle.Expiry()
+		}
+	}
+	return result
+}
+
+// keepLayout returns the dotenv file at outPath with the values of payload set
+// in place, so an overwrite only changes the lines whose values changed. A
+// file that is missing, does not parse, or assigns other keys than payload is
+// replaced by payload as rendered.
+func (s Service) keepLayout(name, outPath string, payload []byte) []byte {
+	existing, err := s.fs.ReadFile(outPath)
+	if err != nil {
+		return payload
+	}
+	env, _ := dotenv.Parse(payload) // rendered by pullPayload, so it parses
+	updated, ok := dotenv.Update(existing, env)
+	if !ok {
+		return payload
+	}
+	if first, _, _ := bytes.Cut(updated, []byte("\n")); string(bytes.TrimSuffix(first, []byte("\r"))) != dotenvMarkerPrefix+name {
+		updated = append(dotenvMarker(name), updated...)
+	}
+	return updated
+}
+
+// WithMtime returns a copy of the service whose Pull applies strategy, one of
+// the config.Mtime* values, instead of the configured one.
+func (s Service) WithMtime(strategy string) Service {
+	s.cfg.Mtime = strategy
+	return s
+}
+
+// pullMtime is the modification time Pull gives outPath after writing it, or
+// zero to keep the time of the write. created is the pulled version's
+// creation time from versionCreatedAt.
+func (s Service) pullMtime(outPath string, created time.Time) time.Time {
+	switch s.cfg.Mtime {
+	case config.MtimePreserve:
+		if info, err := s.fs.Stat(outPath); err == nil {
+			return info.ModTime()
+		}
+	case config.MtimeRemote:
+		return created
+	}
+	return time.Time{}
+}
+
+// versionCreatedAt returns when the pulled version was created, which the
+// remote mtime strategy stamps on the file. The secret's own update time
+// would also move when only its metadata is edited, and is newer than a
+// pinned older revision. Providers that do not return the time with the
+// payload are asked for the version list; it is zero under the other
+// strategies, or when the provider does not know it.
+func (s Service) versionCreatedAt(record *secretprovider.SecretRecord, entry MappingEntry, access *secretprovider.SecretVersionRecord) (time.Time, error) {
+	if s.cfg.Mtime != config.MtimeRemote || !access.CreatedAt.IsZero() {
+		return access.CreatedAt, nil
+	}
+	api, _ := s.apiFor(entry) // opened by the lookup
+	versions, err := api.ListSecretVersions(secretprovider.ListSecretVersionsInput{SecretID: record.ID})
+	if err != nil {
+		return time.Time{}, fmt.Errorf("read created time of revision %d: %w", access.Revision, err)
+	}
+	for _, version := range versions {
+		if version.Revision == access.Revision {
+			return version.CreatedAt, nil
+		}
+	}
+	return time.Time{}, nil
+}
+
+// pullPayload renders a fetched version into the bytes pull writes to the
+// mapped file, plus the public key file of an ssh_key pulled as raw.
+func (s Service) pullPayload(target MappingTarget, access *secretprovider.SecretVersionRecord) ([]byte, []byte, error) {
+	payload := access.Data
+	var origins map[string]keyOrigin
+	if len(target.Entry.Compose) > 0 {
+		composed, composedOrigins, err := s.composePayload(target, access)
+		if err != nil {
+			return nil, nil, fmt.Errorf("compose %s: %w", target.Name, err)
+		}
+		payload, origins = composed, composedOrigins
+	}
+	if target.Entry.Keys != nil {
+		filtered, err := target.Entry.Keys.filter(payload)
+		if err != nil {
+			return nil, nil, fmt.Errorf("filter keys %s: %w", target.Name, err)
+		}
+		payload = filtered
+	}
+	if target.Entry.Substitute {
+		substituted, err := s.substitutePayload(payload, target.Entry.Format)
+		if err != nil {
+			return nil, nil, fmt.Errorf("substitute %s: %w", target.Name, err)
+		}
+		payload = substituted
+	}
+	if target.Entry.KeyPrefix != "" || target.Entry.KeySuffix != "" {
+		renamed, err := addKeyAffixes(payload, target.Entry.KeyPrefix, target.Entry.KeySuffix)
+		if err != nil {
+			return nil, nil, fmt.Errorf("rewrite keys %s: %w", target.Name, err)
+		}
+		payload = renamed
+	}
+	var publicKey []byte
+	if isSSHKeyFile(access.Type, target.Entry) {
+		var err error
+		payload, publicKey, err = sshKeyFiles(target.Name, payload)
+		if err != nil {
+			return nil, nil, fmt.Errorf("ssh key %s: %w", target.Name, err)
+		}
+	}
+	if target.Entry.Format == MappingFormatDotenv {
+		var comment func(key string) string
+		if target.Entry.Provenance {
+			comment = provenanceComment(target, access, origins)
+		}
+		converted, err := secretworkflow.JSONToDotenvCommented(payload, comment)
+		if err != nil {
+			return nil, nil, fmt.Errorf("format dotenv %s: %w", target.Name, err)
+		}
+		payload = append(dotenvMarker(target.Name), converted...)
+	}
+	if target.Entry.Format == MappingFormatYAML {
+		converted, err := yamlmap.Render(payload)
+		if err != nil {
+			return nil, nil, fmt.Errorf("format yaml %s: %w", target.Name, err)
+		}
+		payload = append(dotenvMarker(target.Name), converted...)
+	}
+	if target.Entry.Format == MappingFormatTOML {
+		converted, err := tomlmap.Render(payload)
+		if err != nil {
+			return nil, nil, fmt.Errorf("format toml %s: %w", target.Name, err)
+		}
+		payload = append(dotenvMarker(target.Name), converted...)
+	}
+	if target.Entry.Format == MappingFormatJSON {
+		converted, err := secretworkflow.JSONToPretty(payload)
+		if err != nil {
+			return nil, nil, fmt.Errorf("format json %s: %w", target.Name, err)
+		}
+		payload = converted
+	}
+	if target.Entry.Format == MappingFormatTemplate {
+		rendered, err := s.renderTemplate(target.Entry, payload)
+		if err != nil {
+			return nil, nil, fmt.Errorf("format template %s: %w", target.Name, err)
+		}
+		payload = rendered
+	}
+	return payload, publicKey, nil
+}
+
+// provenanceComment names the secret and revision each rendered key of target
+// came from: its compose layer, or the target's own secret. Keys are traced
+// back through key_prefix and key_suffix.
+func provenanceComment(target MappingTarget, access *secretprovider.SecretVersionRecord, origins map[string]keyOrigin) func(string) string {
+	return func(key string) string {
+		key = strings.TrimSuffix(strings.TrimPrefix(key, target.Entry.KeyPrefix), target.Entry.KeySuffix)
+		origin, ok := origins[key]
+		if !ok {
+			origin = keyOrigin{Secret: target.Name, Revision: access.Revision}
+		}
+		return fmt.Sprintf("from %s rev %d", origin.Secret, origin.Revision)
+	}
+}
+
+// Access returns the version a mapped secret reads, the latest enabled one
+// unless the mapping pins a revision, without writing anything locally.
+func (s Service) Access(target MappingTarget) (*secretprovider.SecretVersionRecord, error) {
+	return s.accessVersion(target.Name, target.Entry)
+}
+
+func (s Service) accessVersion(name string, entry MappingEntry) (*secretprovider.SecretVersionRecord, error) {
+	_, access, err := s.resolveVersion(name, entry)
+	return access, err
+}
+
+// resolveVersion returns the secret mapped as name with its latest enabled
+// version, or with the revision entry pins once it is known to be enabled.
+func (s Service) resolveVersion(name string, entry MappingEntry) (*secretprovider.SecretRecord, *secretprovider.SecretVersionRecord, error) {
+	resolvedSecret, mismatch, err := s.lookupCoerced(name, entry)
+	if err != nil {
+		return nil, nil, fmt.Errorf("resolve %s: %w", name, err)
+	}
+	if mismatch != nil {
+		// Reading is always safe; a dotenv mapping still requires the JSON
+		// object its conversion checks for.
+		s.coerce(mismatch)
+	}
+	api, _ := s.apiFor(entry) // opened by the lookup
+	revision := secretprovider.RevisionLatestEnabled
+	if entry.Revision != 0 {
+		if err := checkRevision(api, resolvedSecret.ID, entry.Revision); err != nil {
+			return nil, nil, fmt.Errorf("access %s: %w", name, err)
+		}
+		revision = secretprovider.RevisionSelector(strconv.FormatUint(uint64(entry.Revision), 10))
+	}
+	access, err := api.AccessSecretVersion(secretprovider.AccessSecretVersionInput{
+		SecretID: resolvedSecret.ID,
+		Revision: revision,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("access %s: %w", name, err)
+	}
+	return resolvedSecret, access, nil
+}
+
+// checkRevision reports a pinned revision that does not exist or is not
+// enabled, which providers would otherwise refuse with less helpful errors.
+func checkRevision(api secretprovider.SecretAPI, secretID string, revision uint32) error {
+	versions, err := api.ListSecretVersions(secretprovider.ListSecretVersionsInput{SecretID: secretID})
+	if err != nil {
+		return err
+	}
+	for _, version := range versions {
+		if version.Revision != revision {
+			continue
+		}
+		if version.Status != secretprovider.VersionStatusEnabled {
+			return fmt.Errorf("revision %d is %s", revision, version.Status)
+		}
+		return nil
+	}
+	return fmt.Errorf("revision %d does not exist", revision)
+}
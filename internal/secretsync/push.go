@@ -3,38 +3,89 @@ package secretsync
 import (
 	"errors"
 	"fmt"
-	"os"
 	"time"
 
 	"github.com/bsmartlabs/dev-vault/internal/secretprovider"
 	"github.com/bsmartlabs/dev-vault/internal/secretworkflow"
+	"github.com/bsmartlabs/dev-vault/internal/sshkey"
+	"github.com/bsmartlabs/dev-vault/internal/tomlmap"
+	"github.com/bsmartlabs/dev-vault/internal/yamlmap"
 )
 
+// Push creates a version per target in order. When the service is interrupted
+// it returns the results completed so far together with an *InterruptedError,
+// and likewise with a *ReadOnlyCredentialsError at the first refused write.
 func (s Service) Push(targets []MappingTarget, opts PushOptions) ([]PushResult, error) {
+	s = s.withIndex()
 	desc := s.pushDescription(opts.Description)
 
 	results := make([]PushResult, 0, len(targets))
-	for _, target := range targets {
+	for i, target := range targets {
+		if s.interrupted() {
+			return results, interruptedError("push", targets, i)
+		}
 		payload, err := s.readPushPayload(target.Name, target.Entry)
 		if err != nil {
 			return nil, err
 		}
-		resolvedSecret, err := s.ResolveMappedSecret(target.Name, target.Entry, opts.CreateMissing)
+		resolvedSecret, err := s.ResolveMappedSecret(target.Name, target.Entry, opts.CreateMissing && !s.dryRun)
+		var notFound *SecretLookupMissError
+		create := s.dryRun && opts.CreateMissing && errors.As(err, &notFound)
+		if create {
+			if target.Entry.Type == "" {
+				return nil, fmt.Errorf("push %s: create-missing requires mapping.type", target.Name)
+			}
+			resolvedSecret, err = &secretprovider.SecretRecord{Name: target.Name, Type: secretprovider.SecretType(target.Entry.Type), Path: target.Entry.Path}, nil
+		}
+		var mismatch *SecretTypeMismatchError
+		if s.coerce != nil && errors.As(err, &mismatch) {
+			if err := coercePayload(mismatch.Record.Type, payload); err != nil {
+				return nil, fmt.Errorf("push %s: cannot coerce: %w", target.Name, err)
+			}
+			s.coerce(mismatch)
+			resolvedSecret, err = &mismatch.Record, nil
+		}
+		var readOnly *ReadOnlyCredentialsError
+		if errors.As(err, &readOnly) {
+			return results, err
+		}
 		if err != nil {
 			return nil, err
 		}
+		// A key pulled as a bare PEM file goes back in the ssh_key format.
+		if isSSHKeyFile(resolvedSecret.Type, target.Entry) && sshkey.IsPrivateKey(payload) {
+			payload = sshkey.ToPayload(payload)
+		}
+
+		if s.dryRun {
+			results = append(results, PushResult{Name: target.Name, Create: create})
+			continue
+		}
 
-		version, err := s.api.CreateSecretVersion(createSecretVersionInput(
+		api, _ := s.apiFor(target.Entry) // opened by the lookup
+		version, err := api.CreateSecretVersion(createSecretVersionInput(
 			resolvedSecret.ID,
 			payload,
 			desc,
 			opts.DisablePrevious,
 		))
 		if err != nil {
-			return nil, fmt.Errorf("push %s: create version: %w", target.Name, err)
+			err = writeRefused(fmt.Errorf("push %s: create version: %w", target.Name, err), target.Entry.Profile)
+			if errors.As(err, &readOnly) {
+				return results, err
+			}
+			return nil, err
+		}
+		// The marker records the pushed revision as the file's base for sync.
+		inPaths, _ := s.localPaths(target.Entry) // resolved by readPushPayload
+		for _, inPath := range inPaths {
+			if err := s.markFile(inPath, target.Name, version.Revision); err != nil {
+				return nil, fmt.Errorf("push %s: mark %s: %w", target.Name, inPath, err)
+			}
 		}
 
 		results = append(results, PushResult{Name: target.Name, Revision: version.Revision})
+		s.targetDone(target.Name)
 	}
 
 	return results, nil
@@ -44,27 +95,80 @@ func (s Service) pushDescription(explicit string) string {
 	if explicit != "" {
 		return explicit
 	}
-	host := "unknown-host"
+	return s.defaultDescription("push")
+}
+
+// defaultDescription records what created a version, when, and where.
+func (s Service) defaultDescription(action string) string {
+	return fmt.Sprintf("dev-vault %s %s %s", action, s.now().UTC().Format(time.RFC3339), s.host())
+}
+
+func (s Service) host() string {
 	if h, err := s.hostname(); err == nil && h != "" {
-		host = h
+		return h
 	}
-	return fmt.Sprintf("dev-vault push %s %s", s.now().UTC().Format(time.RFC3339), host)
+	return "unknown-host"
 }
 
 func (s Service) readPushPayload(name string, entry MappingEntry) ([]byte, error) {
+	if len(entry.Files) > 0 {
+		return s.readSplitFiles(name, entry)
+	}
 	inPath, err := s.resolvePath(s.cfg.Root, entry.File)
 	if err != nil {
 		return nil, fmt.Errorf("mapping %s: resolve file: %w", name, err)
 	}
-	raw, err := os.ReadFile(inPath)
+	raw, err := s.fs.ReadFile(inPath)
 	if err != nil {
 		return nil, fmt.Errorf("push %s: read %s: %w", name, inPath, err)
 	}
-	if entry.Format == MappingFormatDotenv {
+	decoded, err := decodeLocal(name, inPath, entry, raw)
+	if err != nil {
+		return nil, err
+	}
+	if entry.Format.structured() {
+		return s.pushKeys(name, entry, decoded)
+	}
+	return decoded, nil
+}
+
+// decodeLocal turns the contents of a dotenv, yaml, toml or json file into
+// the JSON payload it holds, without the mapping's key affixes; other formats
+// are returned as they are.
+func decodeLocal(name, path string, entry MappingEntry, raw []byte) ([]byte, error) {
+	switch entry.Format {
+	case MappingFormatDotenv:
+		if isFakeFile(raw) {
+			return nil, fmt.Errorf("push %s: %s holds placeholders from pull --fake", name, path)
+		}
 		converted, err := secretworkflow.DotenvToJSON(raw)
 		if err != nil {
 			return nil, fmt.Errorf("format dotenv %s: %w", name, err)
 		}
+		if entry.KeyPrefix != "" || entry.KeySuffix != "" {
+			converted, err = stripKeyAffixes(converted, entry.KeyPrefix, entry.KeySuffix)
+			if err != nil {
+				return nil, fmt.Errorf("push %s: %w", name, err)
+			}
+		}
+		return converted, nil
+	case MappingFormatYAML:
+		converted, err := yamlmap.Parse(raw)
+		if err != nil {
+			return nil, fmt.Errorf("format yaml %s: %w", name, err)
+		}
+		return converted, nil
+	case MappingFormatTOML:
+		converted, err := tomlmap.Parse(raw)
+		if err != nil {
+			return nil, fmt.Errorf("format toml %s: %w", name, err)
+		}
+		return converted, nil
+	case MappingFormatJSON:
+		converted, err := secretworkflow.PrettyToJSON(raw)
+		if err != nil {
+			return nil, fmt.Errorf("format json %s: %w", name, err)
+		}
 		return converted, nil
 	}
 	return raw, nil
@@ -97,13 +201,15 @@ func (s Service) ResolveMappedSecret(name string, entry MappingEntry, createMiss
 		return nil, fmt.Errorf("push %s: create-missing requires mapping.type", name)
 	}
 
-	createdSecret, err := s.api.CreateSecret(secretprovider.CreateSecretInput{
+	api, _ := s.apiFor(entry) // opened by the lookup
+	createdSecret, err := api.CreateSecret(secretprovider.CreateSecretInput{
 		Name: name,
 		Type: secretprovider.SecretType(entry.Type),
 		Path: entry.Path,
 	})
 	if err != nil {
-		return nil, fmt.Errorf("push %s: create secret: %w", name, err)
+		return nil, writeRefused(fmt.Errorf("push %s: create secret: %w", name, err), entry.Profile)
 	}
+	s.forgetIndex()
 	return createdSecret, nil
 }
@@ -0,0 +1,33 @@
+package secretsync
+
+import (
+	"errors"
+
+	"github.com/bsmartlabs/dev-vault/internal/secretprovider"
+)
+
+// ReadOnlyCredentialsError reports a write the provider refused because the
+// credentials may only read. A batch stops at the first one: every later
+// target would be refused the same way.
+type ReadOnlyCredentialsError struct {
+	Err error
+	// Profile is the mapping's own profile when it sets one.
+	Profile string
+}
+
+func (e *ReadOnlyCredentialsError) Error() string {
+	return "read-only credentials: " + e.Err.Error()
+}
+
+func (e *ReadOnlyCredentialsError) Unwrap() error {
+	return e.Err
+}
+
+// writeRefused turns a write error caused by missing permissions into a
+// *ReadOnlyCredentialsError and returns other errors unchanged.
+func writeRefused(err error, profile string) error {
+	if errors.Is(err, secretprovider.ErrPermissionDenied) {
+		return &ReadOnlyCredentialsError{Err: err, Profile: profile}
+	}
+	return err
+}
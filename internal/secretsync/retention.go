@@ -0,0 +1,68 @@
+package secretsync
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/bsmartlabs/dev-vault/internal/secretprovider"
+)
+
+// RetentionOptions selects the enabled versions Retain disables; a version
+// either rule selects is disabled. A zero field turns its rule off.
+type RetentionOptions struct {
+	// OlderThan selects versions created longer ago than this.
+	OlderThan time.Duration
+	// KeepEnabled selects all but this many newest enabled versions, the
+	// kept revision included.
+	KeepEnabled int
+}
+
+// Enabled reports whether any retention rule is on.
+func (o RetentionOptions) Enabled() bool {
+	return o.OlderThan > 0 || o.KeepEnabled > 0
+}
+
+// Retain disables the enabled versions of the mapped secret that opts
+// selects and returns their revisions, newest first. Revision keep, the one
+// just pushed, always stays enabled, and so does a version whose provider
+// reports no creation time unless KeepEnabled selects it. When a disable
+// fails, the revisions disabled so far are returned with the error. A dry run
+// passes keep 0, as the version it did not push would be the newest.
+func (s Service) Retain(target MappingTarget, keep uint32, opts RetentionOptions) ([]uint32, error) {
+	record, api, err := s.resolveTarget(target)
+	if err != nil {
+		return nil, err
+	}
+	versions, err := api.ListSecretVersions(secretprovider.ListSecretVersionsInput{SecretID: record.ID})
+	if err != nil {
+		return nil, fmt.Errorf("list versions %s: %w", target.Name, err)
+	}
+	var enabled []secretprovider.SecretVersionRecord
+	for _, version := range versions {
+		if version.Status == secretprovider.VersionStatusEnabled && version.Revision != keep {
+			enabled = append(enabled, version)
+		}
+	}
+	sort.Slice(enabled, func(i, j int) bool { return enabled[i].Revision > enabled[j].Revision })
+
+	cutoff := s.now().Add(-opts.OlderThan)
+	var disabled []uint32
+	for i, version := range enabled {
+		// i+1 enabled versions are newer than this one, keep included.
+		tooMany := opts.KeepEnabled > 0 && i+1 >= opts.KeepEnabled
+		tooOld := opts.OlderThan > 0 && !version.CreatedAt.IsZero() && version.CreatedAt.Before(cutoff)
+		if !tooMany && !tooOld {
+			continue
+		}
+		if s.dryRun {
+			disabled = append(disabled, version.Revision)
+			continue
+		}
+		if err := api.DisableSecretVersion(secretprovider.DisableSecretVersionInput{SecretID: record.ID, Revision: version.Revision}); err != nil {
+			return disabled, writeRefused(fmt.Errorf("disable %s rev=%d: %w", target.Name, version.Revision, err), target.Entry.Profile)
+		}
+		disabled = append(disabled, version.Revision)
+	}
+	return disabled, nil
+}
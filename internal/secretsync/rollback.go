@@ -0,0 +1,45 @@
+package secretsync
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/bsmartlabs/dev-vault/internal/secretprovider"
+)
+
+// Rollback copies the payload of revision into a new version of the mapped
+// secret, which becomes the latest one. The local file is left alone. The
+// payload goes back unchanged into the same secret, so a type differing from
+// mapping.type does not matter.
+func (s Service) Rollback(target MappingTarget, revision uint32, opts PushOptions) (PushResult, error) {
+	record, err := s.lookupMappedSecret(target.Name, target.Entry)
+	var mismatch *SecretTypeMismatchError
+	if errors.As(err, &mismatch) {
+		record, err = &mismatch.Record, nil
+	}
+	if err != nil {
+		return PushResult{}, fmt.Errorf("resolve %s: %w", target.Name, err)
+	}
+	api, _ := s.apiFor(target.Entry) // opened by the lookup
+	if err := checkRevision(api, record.ID, revision); err != nil {
+		return PushResult{}, fmt.Errorf("rollback %s: %w", target.Name, err)
+	}
+	access, err := api.AccessSecretVersion(secretprovider.AccessSecretVersionInput{
+		SecretID: record.ID,
+		Revision: secretprovider.RevisionSelector(strconv.FormatUint(uint64(revision), 10)),
+	})
+	if err != nil {
+		return PushResult{}, fmt.Errorf("access %s: %w", target.Name, err)
+	}
+
+	desc := opts.Description
+	if desc == "" {
+		desc = s.defaultDescription(fmt.Sprintf("rollback to rev=%d", revision))
+	}
+	version, err := api.CreateSecretVersion(createSecretVersionInput(record.ID, access.Data, desc, opts.DisablePrevious))
+	if err != nil {
+		return PushResult{}, writeRefused(fmt.Errorf("rollback %s: create version: %w", target.Name, err), target.Entry.Profile)
+	}
+	return PushResult{Name: target.Name, Revision: version.Revision}, nil
+}
@@ -1,6 +1,8 @@
 package secretsync
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"sort"
 	"strings"
@@ -17,6 +19,18 @@ func (e *SecretLookupMissError) Error() string {
 	return fmt.Sprintf("secret not found: name=%s path=%s", e.Name, e.Path)
 }
 
+// SecretTypeMismatchError reports a secret that exists under the mapped name
+// and path with a type other than mapping.type. Record is the secret found.
+type SecretTypeMismatchError struct {
+	Name   string
+	Want   string
+	Record secretprovider.SecretRecord
+}
+
+func (e *SecretTypeMismatchError) Error() string {
+	return fmt.Sprintf("secret %s is %s but mapping.type is %s (use --coerce to convert where safe)", e.Name, e.Record.Type, e.Want)
+}
+
 func (s Service) LookupMappedSecret(name string, entry MappingEntry) (*secretprovider.SecretRecord, error) {
 	return s.lookupMappedSecret(name, entry)
 }
@@ -31,7 +45,11 @@ func (s Service) lookupMappedSecret(name string, entry MappingEntry) (*secretpro
 		req.Type = secretprovider.SecretType(entry.Type)
 	}
 
-	respSecrets, err := s.api.ListSecrets(req)
+	api, err := s.apiFor(entry)
+	if err != nil {
+		return nil, err
+	}
+	respSecrets, err := s.listForLookup(entry.Profile, api, req)
 	if err != nil {
 		return nil, fmt.Errorf("list secrets: %w", err)
 	}
@@ -43,6 +61,19 @@ func (s Service) lookupMappedSecret(name string, entry MappingEntry) (*secretpro
 		}
 	}
 	if len(matches) == 0 {
+		if entry.Type != "" {
+			// Tell a secret of another type apart from a missing one.
+			untyped := entry
+			untyped.Type = ""
+			other, err := s.lookupMappedSecret(name, untyped)
+			var notFound *SecretLookupMissError
+			switch {
+			case err == nil:
+				return nil, &SecretTypeMismatchError{Name: name, Want: entry.Type, Record: *other}
+			case !errors.As(err, &notFound):
+				return nil, err
+			}
+		}
 		return nil, &SecretLookupMissError{Name: name, Path: entry.Path}
 	}
 	if len(matches) > 1 {
@@ -57,3 +88,40 @@ func (s Service) lookupMappedSecret(name string, entry MappingEntry) (*secretpro
 	resolved := matches[0]
 	return &resolved, nil
 }
+
+// lookupCoerced is lookupMappedSecret for services created WithCoerce: a
+// secret of another type is returned instead of a mismatch error, which is
+// passed along so the caller can check the payload and warn.
+func (s Service) lookupCoerced(name string, entry MappingEntry) (*secretprovider.SecretRecord, *SecretTypeMismatchError, error) {
+	record, err := s.lookupMappedSecret(name, entry)
+	var mismatch *SecretTypeMismatchError
+	if s.coerce == nil || !errors.As(err, &mismatch) {
+		return record, nil, err
+	}
+	return &mismatch.Record, mismatch, nil
+}
+
+// WithCoerce returns a copy of the service that uses a mapped secret whose
+// type differs from mapping.type when its payload converts safely, reporting
+// each such use through warn.
+func (s Service) WithCoerce(warn func(mismatch *SecretTypeMismatchError)) Service {
+	s.coerce = warn
+	return s
+}
+
+// coercePayload checks that payload is valid for a secret of secretType:
+// opaque takes anything, the JSON types need a JSON object, and other types
+// are not converted into.
+func coercePayload(secretType secretprovider.SecretType, payload []byte) error {
+	switch secretType {
+	case secretprovider.SecretTypeOpaque:
+		return nil
+	case secretprovider.SecretTypeKeyValue, secretprovider.SecretTypeBasicCredentials, secretprovider.SecretTypeDatabaseCredentials:
+		var object map[string]json.RawMessage
+		if err := json.Unmarshal(payload, &object); err != nil || object == nil {
+			return fmt.Errorf("a %s secret needs a JSON object payload", secretType)
+		}
+		return nil
+	}
+	return fmt.Errorf("converting into a %s secret is not supported", secretType)
+}
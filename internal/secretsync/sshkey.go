@@ -0,0 +1,47 @@
+package secretsync
+
+import (
+	"fmt"
+
+	"github.com/bsmartlabs/dev-vault/internal/secretprovider"
+	"github.com/bsmartlabs/dev-vault/internal/sshkey"
+)
+
+// isSSHKeyFile reports whether a raw mapping of a secret of type secretType
+// holds a bare PEM private key locally instead of the JSON payload.
+func isSSHKeyFile(secretType secretprovider.SecretType, entry MappingEntry) bool {
+	return secretType == secretprovider.SecretTypeSSHKey && entry.Format == MappingFormatRaw
+}
+
+// sshKeyFiles splits an ssh_key payload into the private key file and the
+// matching .pub line, commented with the secret name.
+func sshKeyFiles(name string, payload []byte) ([]byte, []byte, error) {
+	key, err := sshkey.FromPayload(payload)
+	if err != nil {
+		return nil, nil, err
+	}
+	pub, err := sshkey.AuthorizedKey(key, name)
+	if err != nil {
+		return nil, nil, err
+	}
+	return key, pub, nil
+}
+
+// checkPublicKeyAbsent runs before the private key is written, so a pull
+// without --overwrite never leaves a key next to a stale .pub.
+func (s Service) checkPublicKeyAbsent(name, keyPath string) error {
+	if _, err := s.fs.Lstat(keyPath + ".pub"); err == nil {
+		return fmt.Errorf("pull %s: file exists (use --overwrite): %s.pub", name, keyPath)
+	}
+	return nil
+}
+
+// writePublicKey writes the .pub file next to a pulled private key; its
+// absence was already checked when overwriting is not allowed.
+func (s Service) writePublicKey(name, keyPath string, pub []byte) error {
+	pubPath := keyPath + ".pub"
+	if err := s.fs.WriteFileAtomic(pubPath, pub, 0o644, true); err != nil {
+		return fmt.Errorf("pull %s: write %s: %w", name, pubPath, err)
+	}
+	return nil
+}
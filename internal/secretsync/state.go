@@ -0,0 +1,171 @@
+package secretsync
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/bsmartlabs/dev-vault/internal/secretprovider"
+)
+
+// The state secret is a key_value secret shared by everyone using the
+// manifest. It records, for each mapped secret, the revision last pushed,
+// when, and from which host, so teammates can tell a stale local file from a
+// fresh one without comparing payloads. Each write merges into the latest
+// version; see RecordState.
+const (
+	stateFormatVersion = 1
+	// stateWriteAttempts bounds the writes RecordState makes while teammates
+	// keep replacing the version it merged into.
+	stateWriteAttempts = 3
+)
+
+// stateMapping locates the state secret like a mapping entry would.
+var stateMapping = MappingEntry{Path: "/", Type: string(secretprovider.SecretTypeKeyValue)}
+
+// StateEntry is the shared record of one mapped secret.
+type StateEntry struct {
+	Revision uint32    `json:"revision"`
+	PushedAt time.Time `json:"pushed_at"`
+	PushedBy string    `json:"pushed_by,omitempty"`
+}
+
+// supersedes reports whether e is newer than other: a later revision, or the
+// same revision recorded later.
+func (e StateEntry) supersedes(other StateEntry) bool {
+	if e.Revision != other.Revision {
+		return e.Revision > other.Revision
+	}
+	return e.PushedAt.After(other.PushedAt)
+}
+
+type sharedState struct {
+	Version int                   `json:"version"`
+	Secrets map[string]StateEntry `json:"secrets"`
+}
+
+// merge keeps the newer of each entry and reports whether updates changed
+// anything.
+func (st sharedState) merge(updates map[string]StateEntry) bool {
+	changed := false
+	for name, entry := range updates {
+		if current, ok := st.Secrets[name]; !ok || entry.supersedes(current) {
+			st.Secrets[name] = entry
+			changed = true
+		}
+	}
+	return changed
+}
+
+// SharedState returns the entries of the state secret; one that does not
+// exist yet holds none.
+func (s Service) SharedState() (map[string]StateEntry, error) {
+	state, _, err := s.readState()
+	return state.Secrets, err
+}
+
+// readState returns the latest state and the secret holding it, which is nil
+// until the first write creates it.
+func (s Service) readState() (sharedState, *secretprovider.SecretRecord, error) {
+	name := s.cfg.StateSecret
+	record, access, err := s.resolveVersion(name, stateMapping)
+	var notFound *SecretLookupMissError
+	if errors.As(err, &notFound) {
+		return sharedState{Version: stateFormatVersion, Secrets: map[string]StateEntry{}}, nil, nil
+	}
+	if err != nil {
+		return sharedState{}, nil, fmt.Errorf("state: %w", err)
+	}
+	var state sharedState
+	// The decode error is dropped: it can quote the payload.
+	if err := json.Unmarshal(access.Data, &state); err != nil {
+		return sharedState{}, nil, fmt.Errorf("state %s: payload is not a dev-vault state record", name)
+	}
+	if state.Version != stateFormatVersion {
+		return sharedState{}, nil, fmt.Errorf("state %s: unsupported format version %d", name, state.Version)
+	}
+	if state.Secrets == nil {
+		state.Secrets = map[string]StateEntry{}
+	}
+	return state, record, nil
+}
+
+// RecordState merges the pushed revisions into the state secret, creating it
+// on first use. Entries only move forward, so an older local view never rolls
+// a teammate's record back. After each write the latest version is read
+// again: when a concurrent write replaced it, the merge is redone on top of
+// that one, so neither side's entries are lost. Without a state secret it
+// does nothing, and so does a dry run.
+func (s Service) RecordState(results []PushResult) error {
+	name := s.cfg.StateSecret
+	if name == "" || len(results) == 0 || s.dryRun {
+		return nil
+	}
+	updates := make(map[string]StateEntry, len(results))
+	for _, result := range results {
+		updates[result.Name] = StateEntry{Revision: result.Revision, PushedAt: s.now().UTC(), PushedBy: s.host()}
+	}
+	for attempt := 0; ; attempt++ {
+		state, record, err := s.readState()
+		if err != nil {
+			return err
+		}
+		if !state.merge(updates) {
+			return nil
+		}
+		if attempt == stateWriteAttempts {
+			return fmt.Errorf("state %s: kept changing while recording; push again to retry", name)
+		}
+		if record == nil {
+			record, err = s.api.CreateSecret(secretprovider.CreateSecretInput{
+				Name: name,
+				Type: secretprovider.SecretTypeKeyValue,
+				Path: stateMapping.Path,
+			})
+			if err != nil {
+				return fmt.Errorf("state %s: create secret: %w", name, err)
+			}
+			s.forgetIndex()
+		}
+		payload, _ := json.Marshal(state) // times and strings always encode
+		if _, err := s.api.CreateSecretVersion(createSecretVersionInput(record.ID, payload, s.defaultDescription("state"), true)); err != nil {
+			return fmt.Errorf("state %s: create version: %w", name, err)
+		}
+	}
+}
+
+// StateDrift is one enabled mapping entry set against the shared record.
+type StateDrift struct {
+	Name     string
+	File     string
+	Recorded bool
+	Entry    StateEntry
+	Local    bool
+	// LocalRevision is the revision in the marker of the local file, or 0
+	// when the file has no marker for this secret or one without a revision.
+	LocalRevision uint32
+}
+
+// StateDrifts compares the local file of every enabled mapping entry with
+// the state secret. Only the state secret is read from the provider.
+func (s Service) StateDrifts() ([]StateDrift, error) {
+	shared, err := s.SharedState()
+	if err != nil {
+		return nil, err
+	}
+	targets := s.allTargets()
+	drifts := make([]StateDrift, 0, len(targets))
+	for i, local := range s.LocalFiles(targets) {
+		drift := StateDrift{Name: local.Name, File: local.File, Local: local.Present}
+		drift.Entry, drift.Recorded = shared[local.Name]
+		if local.Present {
+			paths, _ := s.localPaths(targets[i].Entry) // resolved by LocalFiles
+			if marker, ok := s.readMarker(paths[0]); ok && marker.Name == local.Name {
+				drift.LocalRevision = marker.Revision
+			}
+		}
+		drifts = append(drifts, drift)
+	}
+	return drifts, nil
+}
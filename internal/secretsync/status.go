@@ -0,0 +1,226 @@
+package secretsync
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+type LocalStatus struct {
+	Mapped   int
+	Present  int
+	Missing  int
+	LastSync time.Time
+}
+
+type LocalFile struct {
+	Name    string
+	File    string
+	Present bool
+	ModTime time.Time
+	// Expired is set by StaleFiles and ExpiredFiles when the file is present
+	// and its recorded expiry has passed.
+	Expired bool
+	// LastPull is set by StaleFiles for a present file: when pull last wrote
+	// it, or its ModTime when no pull time was recorded.
+	LastPull time.Time
+}
+
+// LocalFiles inspects mapped files on disk only; it never calls the provider.
+// A split secret is present when all its files are, as of the oldest one.
+func (s Service) LocalFiles(targets []MappingTarget) []LocalFile {
+	files := make([]LocalFile, 0, len(targets))
+	for _, target := range targets {
+		file := LocalFile{Name: target.Name, File: target.Entry.FileLabel()}
+		if paths, err := s.localPaths(target.Entry); err == nil {
+			file.Present = true
+			for _, path := range paths {
+				info, err := s.fs.Stat(path)
+				if err != nil || info.IsDir() {
+					file.Present, file.ModTime = false, time.Time{}
+					break
+				}
+				if file.ModTime.IsZero() || info.ModTime().Before(file.ModTime) {
+					file.ModTime = info.ModTime()
+				}
+			}
+		}
+		files = append(files, file)
+	}
+	return files
+}
+
+// LocalStatus counts the local files of enabled mapping entries. LastSync is
+// the latest pull of a present file, from pulled as for StaleFiles.
+func (s Service) LocalStatus(pulled map[string]time.Time) LocalStatus {
+	targets := s.allTargets()
+	status := LocalStatus{Mapped: len(targets)}
+	for _, file := range withLastPull(s.LocalFiles(targets), pulled) {
+		if !file.Present {
+			status.Missing++
+			continue
+		}
+		status.Present++
+		if file.LastPull.After(status.LastSync) {
+			status.LastSync = file.LastPull
+		}
+	}
+	return status
+}
+
+// StaleFiles returns targets whose local file is missing, was last pulled
+// more than olderThan ago, or has expired. expires maps secret names to the
+// expiry recorded when their files were pulled, and pulled to the time they
+// were. The modification time stands in for a file without a pull time; it
+// is not used otherwise, since the mtime strategies can set it to an older
+// time than the pull.
+func (s Service) StaleFiles(targets []MappingTarget, olderThan time.Duration, expires, pulled map[string]time.Time) []LocalFile {
+	now := s.now()
+	cutoff := now.Add(-olderThan)
+	stale := make([]LocalFile, 0, len(targets))
+	for _, file := range withLastPull(s.expiredFiles(targets, expires, now), pulled) {
+		if !file.Present || file.LastPull.Before(cutoff) || file.Expired {
+			stale = append(stale, file)
+		}
+	}
+	return stale
+}
+
+// withLastPull sets LastPull of the present files from pulled.
+func withLastPull(files []LocalFile, pulled map[string]time.Time) []LocalFile {
+	for i, file := range files {
+		if !file.Present {
+			continue
+		}
+		files[i].LastPull = file.ModTime
+		if at, ok := pulled[file.Name]; ok {
+			files[i].LastPull = at
+		}
+	}
+	return files
+}
+
+// ExpiredFiles returns the present local files of enabled mapping entries
+// whose expiry, from expires as for StaleFiles, has passed.
+func (s Service) ExpiredFiles(expires map[string]time.Time) []LocalFile {
+	var expired []LocalFile
+	for _, file := range s.expiredFiles(s.allTargets(), expires, s.now()) {
+		if file.Expired {
+			expired = append(expired, file)
+		}
+	}
+	return expired
+}
+
+// expiredFiles is LocalFiles with Expired set.
+func (s Service) expiredFiles(targets []MappingTarget, expires map[string]time.Time, now time.Time) []LocalFile {
+	files := s.LocalFiles(targets)
+	for i, file := range files {
+		at, ok := expires[file.Name]
+		files[i].Expired = file.Present && ok && !now.Before(at)
+	}
+	return files
+}
+
+// MappingStatus is the health of one enabled mapping entry: both sides of it
+// and whether the local file holds what pull would write now.
+type MappingStatus struct {
+	Name         string
+	File         string
+	Remote       bool
+	Revision     uint32
+	Local        bool
+	LocalModTime time.Time
+	// Managed is set when the local file carries the marker of this entry's
+	// secret, as files written by pull do; see FileMarker.
+	Managed bool
+	// InSync is nil when a side is missing or the latest version cannot be
+	// rendered for this mapping; Problem then says why for the latter.
+	InSync  *bool
+	Problem string
+}
+
+// MappingStatuses reports every enabled mapping entry. The latest enabled
+// version is rendered as pull would and compared with the local file by
+// SHA-256; neither payload nor digest is returned.
+func (s Service) MappingStatuses() ([]MappingStatus, error) {
+	s = s.withIndex()
+	targets := s.allTargets()
+	local := s.LocalFiles(targets)
+	statuses := make([]MappingStatus, 0, len(targets))
+	for i, target := range targets {
+		status, err := s.mappingStatus(target, local[i])
+		if err != nil {
+			return nil, err
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+func (s Service) mappingStatus(target MappingTarget, local LocalFile) (MappingStatus, error) {
+	status := MappingStatus{
+		Name:         target.Name,
+		File:         target.Entry.FileLabel(),
+		Local:        local.Present,
+		LocalModTime: local.ModTime,
+	}
+	access, err := s.accessVersion(target.Name, target.Entry)
+	var notFound *SecretLookupMissError
+	var mismatch *SecretTypeMismatchError
+	switch {
+	case errors.As(err, &notFound):
+		return status, nil
+	case errors.As(err, &mismatch):
+		status.Remote = true
+		status.Problem = mismatch.Error()
+		return status, nil
+	case err != nil:
+		return MappingStatus{}, err
+	}
+	status.Remote = true
+	status.Revision = access.Revision
+	if !status.Local {
+		return status, nil
+	}
+	paths, _ := s.localPaths(target.Entry) // resolved by LocalFiles
+	marker, ok := s.readMarker(paths[0])
+	status.Managed = ok && marker.Name == target.Name
+
+	payloads, err := s.renderFiles(target, access)
+	if err != nil {
+		status.Problem = err.Error()
+		return status, nil
+	}
+	inSync := true
+	for i, path := range paths {
+		onDisk, err := s.fs.ReadFile(path)
+		if err != nil {
+			return MappingStatus{}, fmt.Errorf("status %s: read %s: %w", target.Name, path, err)
+		}
+		want := payloads[i]
+		if target.Entry.PreserveLayout {
+			// Pull keeps the layout of the file in place.
+			want = s.keepLayout(target.Name, path, want)
+		}
+		inSync = inSync && sha256.Sum256(onDisk) == sha256.Sum256(want)
+	}
+	status.InSync = &inSync
+	return status, nil
+}
+
+// allTargets lists the enabled mapping entries; disabled ones are left out of
+// drift checks until re-enabled.
+func (s Service) allTargets() []MappingTarget {
+	targets := make([]MappingTarget, 0, len(s.cfg.Mapping))
+	for name, entry := range s.cfg.Mapping {
+		if entry.Disabled {
+			continue
+		}
+		targets = append(targets, MappingTarget{Name: name, Entry: entry})
+	}
+	sort.Slice(targets, func(i, j int) bool { return targets[i].Name < targets[j].Name })
+	return targets
+}
@@ -0,0 +1,183 @@
+package secretsync
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"reflect"
+	"strconv"
+
+	"github.com/bsmartlabs/dev-vault/internal/secretprovider"
+)
+
+// SyncAction is what sync does with one mapping entry.
+type SyncAction string
+
+const (
+	SyncInSync   SyncAction = "in_sync"
+	SyncPull     SyncAction = "pull"
+	SyncPush     SyncAction = "push"
+	SyncConflict SyncAction = "conflict"
+)
+
+// SyncDecision is the plan for one mapping entry. Revision is the latest
+// remote revision, or the one written once the decision is applied; Reason
+// explains a conflict.
+type SyncDecision struct {
+	Target   MappingTarget
+	Action   SyncAction
+	Revision uint32
+	Reason   string
+}
+
+// PlanSync decides for each target whether sync pulls, pushes, or leaves it
+// alone, without writing anything. The base is the revision recorded in the
+// file's marker: the remote side changed when its latest revision differs,
+// and the local side when the file holds other content than that revision
+// renders to; see sameContent. A change on one side only is carried to the other; a change on both
+// sides, or a file without a recorded base, is a conflict.
+func (s Service) PlanSync(targets []MappingTarget) ([]SyncDecision, error) {
+	s = s.withIndex()
+	plan := make([]SyncDecision, 0, len(targets))
+	for _, target := range targets {
+		decision, err := s.planSync(target)
+		if err != nil {
+			return nil, err
+		}
+		plan = append(plan, decision)
+	}
+	return plan, nil
+}
+
+func (s Service) planSync(target MappingTarget) (SyncDecision, error) {
+	decision := SyncDecision{Target: target}
+	conflict := func(reason string) (SyncDecision, error) {
+		decision.Action, decision.Reason = SyncConflict, reason
+		return decision, nil
+	}
+	// Sync compares one file with one payload.
+	if len(target.Entry.Files) > 0 {
+		return conflict("files mappings cannot be synced; pull or push them")
+	}
+	path, err := s.resolvePath(s.cfg.Root, target.Entry.File)
+	if err != nil {
+		return SyncDecision{}, fmt.Errorf("mapping %s: resolve file: %w", target.Name, err)
+	}
+	local, localErr := s.fs.ReadFile(path)
+	if localErr != nil && !errors.Is(localErr, fs.ErrNotExist) {
+		return SyncDecision{}, fmt.Errorf("sync %s: read %s: %w", target.Name, path, localErr)
+	}
+
+	record, latest, err := s.resolveVersion(target.Name, target.Entry)
+	var notFound *SecretLookupMissError
+	var mismatch *SecretTypeMismatchError
+	switch {
+	case errors.As(err, &notFound) && localErr != nil:
+		return conflict("missing locally and remotely")
+	case errors.As(err, &notFound):
+		return conflict("secret not found (create it with push --create-missing)")
+	case errors.As(err, &mismatch):
+		return conflict(mismatch.Error())
+	case err != nil:
+		return SyncDecision{}, err
+	}
+	decision.Revision = latest.Revision
+	if localErr != nil {
+		decision.Action = SyncPull
+		return decision, nil
+	}
+
+	rendered, _, err := s.pullPayload(target, latest)
+	if err != nil {
+		return conflict(err.Error())
+	}
+	if sameContent(target, path, rendered, local) {
+		decision.Action = SyncInSync
+		return decision, nil
+	}
+	marker, ok := s.readMarker(path)
+	if !ok || marker.Name != target.Name || marker.Revision == 0 {
+		return conflict("no recorded base revision (pull or push it once)")
+	}
+	if marker.Revision == latest.Revision {
+		decision.Action = SyncPush
+		return decision, nil
+	}
+
+	api, _ := s.apiFor(target.Entry) // opened by the lookup
+	base, err := api.AccessSecretVersion(secretprovider.AccessSecretVersionInput{
+		SecretID: record.ID,
+		Revision: secretprovider.RevisionSelector(strconv.FormatUint(uint64(marker.Revision), 10)),
+	})
+	if err != nil {
+		return conflict(fmt.Sprintf("base rev=%d is no longer readable", marker.Revision))
+	}
+	if baseRendered, _, err := s.pullPayload(target, base); err != nil || !sameContent(target, path, baseRendered, local) {
+		return conflict(fmt.Sprintf("both sides changed since rev=%d", marker.Revision))
+	}
+	decision.Action = SyncPull
+	return decision, nil
+}
+
+// sameContent reports whether the local file holds what pull renders. Files
+// of structured formats are compared by the keys and values they hold, so
+// comments, key order, and quoting added by hand, or a layout kept by
+// preserve_layout, do not count as a local change.
+func sameContent(target MappingTarget, path string, rendered, local []byte) bool {
+	if bytes.Equal(rendered, local) {
+		return true
+	}
+	if !target.Entry.Format.structured() {
+		return false
+	}
+	want, errWant := decodeLocal(target.Name, path, target.Entry, rendered)
+	got, errGot := decodeLocal(target.Name, path, target.Entry, local)
+	return errWant == nil && errGot == nil && jsonEqual(want, got)
+}
+
+// jsonEqual reports whether two JSON documents hold the same values.
+func jsonEqual(a, b []byte) bool {
+	var left, right any
+	errLeft, errRight := json.Unmarshal(a, &left), json.Unmarshal(b, &right)
+	return errLeft == nil && errRight == nil && reflect.DeepEqual(left, right)
+}
+
+// ApplySync carries out the pulls and pushes of plan and returns the
+// decisions applied so far with their new revisions. Conflicts are left for
+// the user; entries already in sync get their marker brought up to date.
+func (s Service) ApplySync(plan []SyncDecision, opts PushOptions) ([]SyncDecision, error) {
+	targets := make([]MappingTarget, 0, len(plan))
+	for _, decision := range plan {
+		targets = append(targets, decision.Target)
+	}
+	applied := make([]SyncDecision, 0, len(plan))
+	for i, decision := range plan {
+		if s.interrupted() {
+			return applied, interruptedError("sync", targets, i)
+		}
+		target := []MappingTarget{decision.Target}
+		switch decision.Action {
+		case SyncPull:
+			results, err := s.Pull(target, true)
+			if err != nil {
+				return applied, err
+			}
+			decision.Revision = results[0].Revision
+		case SyncPush:
+			results, err := s.Push(target, opts)
+			if err != nil {
+				return applied, err
+			}
+			decision.Revision = results[0].Revision
+		case SyncInSync:
+			path, _ := s.resolvePath(s.cfg.Root, decision.Target.Entry.File) // resolved by PlanSync
+			if err := s.markFile(path, decision.Target.Name, decision.Revision); err != nil {
+				return applied, fmt.Errorf("sync %s: mark %s: %w", decision.Target.Name, path, err)
+			}
+		}
+		applied = append(applied, decision)
+	}
+	return applied, nil
+}
@@ -0,0 +1,50 @@
+package secretsync
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"text/template"
+)
+
+// templateFuncs extends the text/template builtins for the files templates
+// usually produce.
+var templateFuncs = template.FuncMap{
+	// json quotes a value for a JSON document such as appsettings.json.
+	"json": func(value any) string {
+		out, _ := json.Marshal(value) // decoded JSON values always encode
+		return string(out)
+	},
+}
+
+// renderTemplate executes the template_file of entry with the keys of a
+// JSON-object payload as its data, so {{.DATABASE_URL}} inserts that key. A
+// key the template uses but the secret lacks fails the render rather than
+// writing "<no value>".
+func (s Service) renderTemplate(entry MappingEntry, payload []byte) ([]byte, error) {
+	path, err := s.resolvePath(s.cfg.Root, entry.TemplateFile)
+	if err != nil {
+		return nil, fmt.Errorf("resolve template_file: %w", err)
+	}
+	source, err := s.fs.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
+	tmpl, err := template.New(entry.TemplateFile).Funcs(templateFuncs).Option("missingkey=error").Parse(string(source))
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(payload))
+	dec.UseNumber()
+	var values map[string]any
+	// The decode error is dropped: it can quote the payload.
+	if err := dec.Decode(&values); err != nil || values == nil {
+		return nil, errors.New("expected a JSON object payload")
+	}
+	var out bytes.Buffer
+	if err := tmpl.Execute(&out, values); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
@@ -6,10 +6,12 @@ import (
 	"time"
 
 	"github.com/bsmartlabs/dev-vault/internal/config"
+	"github.com/bsmartlabs/dev-vault/internal/fsx"
 	"github.com/bsmartlabs/dev-vault/internal/secretprovider"
 )
 
 type ListQuery struct {
+	ProjectID    string
 	NameContains []string
 	NameRegex    *regexp.Regexp
 	Path         string
@@ -17,35 +19,112 @@ type ListQuery struct {
 }
 
 type ListRecord struct {
-	ID   string `json:"id"`
-	Name string `json:"name"`
-	Path string `json:"path"`
-	Type string `json:"type"`
+	ID        string `json:"id"`
+	ProjectID string `json:"project_id,omitempty"`
+	Name      string `json:"name"`
+	Path      string `json:"path"`
+	Type      string `json:"type"`
+	Profile   string `json:"profile,omitempty"`
+	// Tags and UpdatedAt feed inventory columns; the --json listing keeps
+	// its original fields.
+	Tags      []string  `json:"-"`
+	UpdatedAt time.Time `json:"-"`
 }
 
 type MappingFormat string
 
 const (
-	MappingFormatRaw    MappingFormat = "raw"
-	MappingFormatDotenv MappingFormat = "dotenv"
+	MappingFormatRaw      MappingFormat = "raw"
+	MappingFormatDotenv   MappingFormat = "dotenv"
+	MappingFormatYAML     MappingFormat = "yaml"
+	MappingFormatJSON     MappingFormat = "json"
+	MappingFormatTOML     MappingFormat = "toml"
+	MappingFormatTemplate MappingFormat = "template"
 )
 
+// structured reports whether files of the format hold a JSON object of keys,
+// as opposed to the payload bytes.
+func (f MappingFormat) structured() bool {
+	switch f {
+	case MappingFormatDotenv, MappingFormatYAML, MappingFormatTOML, MappingFormatJSON:
+		return true
+	}
+	return false
+}
+
 type MappingEntry struct {
-	File   string
-	Format MappingFormat
-	Path   string
-	Type   string
+	File     string
+	Format   MappingFormat
+	Path     string
+	Type     string
+	Disabled bool
+	Compose  []string
+
+	KeyPrefix string
+	KeySuffix string
+	Keys      *KeyFilter
+	FakeKeys  []string
+
+	Substitute     bool
+	Provenance     bool
+	PreserveLayout bool
+
+	// TemplateFile is the text/template a template mapping renders, relative
+	// to the config root.
+	TemplateFile string
+
+	// Files splits a key_value secret across files, one per key, relative to
+	// the config root; File is then empty. See FileLabel.
+	Files map[string]string
+
+	Profile string
+
+	// Revision pins reads to one secret revision; 0 reads the latest enabled.
+	Revision uint32
+}
+
+// KeyFilter selects the secret keys a mapping reads and writes; see
+// config.KeyFilter.
+type KeyFilter struct {
+	Include []string
+	Exclude []string
 }
 
 func MappingEntryFromConfig(entry config.MappingEntry) MappingEntry {
 	return MappingEntry{
-		File:   entry.File,
-		Format: MappingFormat(entry.Format),
-		Path:   entry.Path,
-		Type:   entry.Type,
+		File:     entry.File,
+		Format:   MappingFormat(entry.Format),
+		Path:     entry.Path,
+		Type:     entry.Type,
+		Disabled: entry.Disabled,
+		Compose:  entry.Compose,
+
+		KeyPrefix: entry.KeyPrefix,
+		KeySuffix: entry.KeySuffix,
+		Keys:      keyFilterFromConfig(entry.Keys),
+		FakeKeys:  entry.FakeKeys,
+
+		Substitute:     entry.Substitute,
+		Provenance:     entry.Provenance,
+		PreserveLayout: entry.PreserveLayout,
+
+		TemplateFile: entry.TemplateFile,
+
+		Files: entry.Files,
+
+		Profile: entry.Profile,
+
+		Revision: entry.Revision,
 	}
 }
 
+func keyFilterFromConfig(filter *config.KeyFilter) *KeyFilter {
+	if filter == nil {
+		return nil
+	}
+	return &KeyFilter{Include: filter.Include, Exclude: filter.Exclude}
+}
+
 func mappingFromConfigEntries(entries map[string]config.MappingEntry) map[string]MappingEntry {
 	mapped := make(map[string]MappingEntry, len(entries))
 	for name, entry := range entries {
@@ -64,6 +143,12 @@ type PullResult struct {
 	File     string
 	Revision uint32
 	Type     string
+	// Expires is the earliest certificate expiry of a certificate secret, and
+	// zero for other types or payloads that do not parse as PEM certificates.
+	Expires time.Time
+	// Change is set by a dry run: what the pull would do to the file, or the
+	// most a pull would do to any of the files of a split secret.
+	Change FileChange
 }
 
 type PushOptions struct {
@@ -74,12 +159,24 @@ type PushOptions struct {
 
 type PushResult struct {
 	Name     string
-	Revision uint32
+	Revision uint32 // 0 in a dry run
+	// Create is set by a dry run when push would create the secret first.
+	Create bool
 }
 
 type Config struct {
 	Root    string
 	Mapping map[string]MappingEntry
+	Vars    map[string]string
+	// Mtime is the config.Mtime* strategy Pull applies to the files it
+	// writes; empty means now.
+	Mtime string
+	// StateSecret is the secret RecordState and SharedState use; empty
+	// disables the shared state.
+	StateSecret string
+	// Provider is the manifest's config.Provider* value; empty means
+	// Scaleway.
+	Provider string
 }
 
 type PathResolver func(rootDir string, rel string) (string, error)
@@ -87,21 +184,44 @@ type PathResolver func(rootDir string, rel string) (string, error)
 type Dependencies struct {
 	Now         func() time.Time
 	Hostname    func() (string, error)
+	Getenv      func(string) string
 	ResolvePath PathResolver
+	// FS holds the mapped files; nil means the host filesystem.
+	FS fsx.FS
+	// OpenProfile opens the provider client for mappings that set a profile.
+	// Without it every mapping uses the service's own client.
+	OpenProfile func(profile string) (secretprovider.SecretAPI, error)
+	// Bases records the marker revisions outside the files; without it they
+	// live only in the user.dev-vault attribute.
+	Bases BaseRevisions
 }
 
 type Service struct {
 	cfg         Config
 	api         secretprovider.SecretAPI
+	profiles    *profileClients
+	index       *secretIndex // nil outside batch operations
 	now         func() time.Time
 	hostname    func() (string, error)
+	getenv      func(string) string
 	resolvePath PathResolver
+	fs          fsx.FS
+	interrupt   <-chan struct{}
+	onDone      func(name string)
+	coerce      func(mismatch *SecretTypeMismatchError)
+	dryRun      bool
+	bases       BaseRevisions
 }
 
 func NewFromLoaded(loaded *config.Loaded, api secretprovider.SecretAPI, deps Dependencies) Service {
 	return New(Config{
 		Root:    loaded.Root,
 		Mapping: mappingFromConfigEntries(loaded.Cfg.Mapping),
+		Vars:    loaded.Cfg.Vars,
+		Mtime:   loaded.Cfg.Mtime,
+
+		StateSecret: loaded.Cfg.StateSecret,
+		Provider:    loaded.Cfg.Provider,
 	}, api, deps)
 }
 
@@ -114,15 +234,27 @@ func New(cfg Config, api secretprovider.SecretAPI, deps Dependencies) Service {
 	if hostname == nil {
 		hostname = os.Hostname
 	}
+	getenv := deps.Getenv
+	if getenv == nil {
+		getenv = os.Getenv
+	}
 	resolvePath := deps.ResolvePath
 	if resolvePath == nil {
 		resolvePath = config.ResolveFile
 	}
+	files := deps.FS
+	if files == nil {
+		files = fsx.OS
+	}
 	return Service{
 		cfg:         cfg,
 		api:         api,
+		profiles:    &profileClients{open: deps.OpenProfile, apis: make(map[string]secretprovider.SecretAPI)},
 		now:         now,
 		hostname:    hostname,
+		getenv:      getenv,
 		resolvePath: resolvePath,
+		fs:          files,
+		bases:       deps.Bases,
 	}
 }
@@ -0,0 +1,29 @@
+package secretsync
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/bsmartlabs/dev-vault/internal/secretprovider"
+)
+
+// Versions lists every version of a mapped secret, newest first. No payload
+// is read, so a secret whose type differs from mapping.type is listed too.
+func (s Service) Versions(target MappingTarget) ([]secretprovider.SecretVersionRecord, error) {
+	record, err := s.lookupMappedSecret(target.Name, target.Entry)
+	var mismatch *SecretTypeMismatchError
+	if errors.As(err, &mismatch) {
+		record, err = &mismatch.Record, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("resolve %s: %w", target.Name, err)
+	}
+	api, _ := s.apiFor(target.Entry) // opened by the lookup
+	versions, err := api.ListSecretVersions(secretprovider.ListSecretVersionsInput{SecretID: record.ID})
+	if err != nil {
+		return nil, fmt.Errorf("list versions %s: %w", target.Name, err)
+	}
+	sort.SliceStable(versions, func(i, j int) bool { return versions[i].Revision > versions[j].Revision })
+	return versions, nil
+}
@@ -0,0 +1,56 @@
+package secretsync
+
+import "time"
+
+// Wait sleeps for d and reports whether it slept that long: it returns false
+// as soon as the service is interrupted.
+func (s Service) Wait(d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-s.interrupt:
+		return false
+	}
+}
+
+// Changed returns the targets whose secret has a latest enabled revision other
+// than the one pulled records for it. The provider is asked again rather than
+// the invocation's payload cache, so versions created meanwhile are seen.
+// Targets pinned to a revision never change.
+func (s Service) Changed(targets []MappingTarget, pulled map[string]uint32) ([]MappingTarget, error) {
+	s.ForgetCached()
+	s = s.withIndex()
+	var changed []MappingTarget
+	for _, target := range targets {
+		if target.Entry.Revision != 0 {
+			continue
+		}
+		access, err := s.accessVersion(target.Name, target.Entry)
+		if err != nil {
+			return nil, err
+		}
+		if access.Revision != pulled[target.Name] {
+			changed = append(changed, target)
+		}
+	}
+	return changed, nil
+}
+
+// ForgetCached resets the payload caches of the service's clients, so the
+// next reads ask the provider again. A long-running command calls it before
+// each round of reads.
+func (s Service) ForgetCached() {
+	type resetter interface{ Reset() }
+	if cache, ok := s.api.(resetter); ok {
+		cache.Reset()
+	}
+	s.profiles.mu.Lock()
+	defer s.profiles.mu.Unlock()
+	for _, api := range s.profiles.apis {
+		if cache, ok := api.(resetter); ok {
+			cache.Reset()
+		}
+	}
+}
@@ -1,13 +1,21 @@
 package secretworkflow
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 
 	"github.com/bsmartlabs/dev-vault/internal/dotenv"
 )
 
 func JSONToDotenv(payload []byte) ([]byte, error) {
+	return JSONToDotenvCommented(payload, nil)
+}
+
+// JSONToDotenvCommented converts like JSONToDotenv, preceding each key with
+// the comment line comment returns for it (see dotenv.RenderCommented).
+func JSONToDotenvCommented(payload []byte, comment func(key string) string) ([]byte, error) {
 	var m map[string]json.RawMessage
 	if err := json.Unmarshal(payload, &m); err != nil {
 		return nil, fmt.Errorf("expected JSON object: %w", err)
@@ -21,7 +29,7 @@ func JSONToDotenv(payload []byte) ([]byte, error) {
 		}
 		env[key] = string(raw)
 	}
-	return dotenv.Render(env), nil
+	return dotenv.RenderCommented(env, comment), nil
 }
 
 func DotenvToJSON(payload []byte) ([]byte, error) {
@@ -31,3 +39,32 @@ func DotenvToJSON(payload []byte) ([]byte, error) {
 	}
 	return json.Marshal(env)
 }
+
+// JSONToPretty re-encodes a JSON payload with sorted object keys and
+// two-space indentation. Numbers keep their exact text.
+func JSONToPretty(payload []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(payload))
+	dec.UseNumber()
+	var value any
+	if err := dec.Decode(&value); err != nil {
+		return nil, fmt.Errorf("expected JSON: %w", err)
+	}
+	if dec.More() {
+		return nil, errors.New("expected JSON: trailing data after the value")
+	}
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(value) // decoded values always encode
+	return b.Bytes(), nil
+}
+
+// PrettyToJSON compacts a JSON file back into the payload form.
+func PrettyToJSON(payload []byte) ([]byte, error) {
+	var b bytes.Buffer
+	if err := json.Compact(&b, payload); err != nil {
+		return nil, fmt.Errorf("expected JSON: %w", err)
+	}
+	return b.Bytes(), nil
+}
@@ -37,3 +37,26 @@ func TestDotenvToJSON_InvalidPayload(t *testing.T) {
 		t.Fatal("expected error for invalid dotenv payload")
 	}
 }
+
+func TestJSONToPretty(t *testing.T) {
+	pretty, err := JSONToPretty([]byte(`{"b":[1,2.50],"a":{"url":"http://x/?a=1&b=<2>"},"n":12345678901234567890}`))
+	if err != nil {
+		t.Fatalf("JSONToPretty: %v", err)
+	}
+	want := "{\n  \"a\": {\n    \"url\": \"http://x/?a=1&b=<2>\"\n  },\n  \"b\": [\n    1,\n    2.50\n  ],\n  \"n\": 12345678901234567890\n}\n"
+	if string(pretty) != want {
+		t.Fatalf("unexpected pretty JSON:\n%s", pretty)
+	}
+	compact, err := PrettyToJSON(pretty)
+	if err != nil || string(compact) != `{"a":{"url":"http://x/?a=1&b=<2>"},"b":[1,2.50],"n":12345678901234567890}` {
+		t.Fatalf("unexpected compact JSON: %s %v", compact, err)
+	}
+	for _, bad := range []string{"not-json", `{"a":1} {}`} {
+		if _, err := JSONToPretty([]byte(bad)); err == nil || !strings.Contains(err.Error(), "expected JSON") {
+			t.Fatalf("%s: expected error, got %v", bad, err)
+		}
+	}
+	if _, err := PrettyToJSON([]byte("{,}")); err == nil || !strings.Contains(err.Error(), "expected JSON") {
+		t.Fatalf("expected compact error, got %v", err)
+	}
+}
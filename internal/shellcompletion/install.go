@@ -0,0 +1,152 @@
+package shellcompletion
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bsmartlabs/dev-vault/internal/fsx"
+)
+
+const (
+	beginMarker = "# >>> dev-vault completion >>>"
+	endMarker   = "# <<< dev-vault completion <<<"
+)
+
+// RCFile is the startup file of shell that Install edits: ~/.bashrc,
+// $ZDOTDIR/.zshrc (default ~/.zshrc), or config.fish under
+// $XDG_CONFIG_HOME/fish (default ~/.config/fish).
+func RCFile(shell, home string, getenv func(string) string) (string, error) {
+	switch shell {
+	case Bash:
+		return filepath.Join(home, ".bashrc"), nil
+	case Zsh:
+		dir := getenv("ZDOTDIR")
+		if dir == "" {
+			dir = home
+		}
+		return filepath.Join(dir, ".zshrc"), nil
+	case Fish:
+		dir := getenv("XDG_CONFIG_HOME")
+		if dir == "" {
+			dir = filepath.Join(home, ".config")
+		}
+		return filepath.Join(dir, "fish", "config.fish"), nil
+	}
+	return "", unsupported(shell)
+}
+
+// SourceLine loads the completion script of shell when program is on PATH,
+// so removing program later does not break the shell's startup.
+func SourceLine(shell, program string) (string, error) {
+	switch shell {
+	case Bash, Zsh:
+		return fmt.Sprintf("command -v %s >/dev/null 2>&1 && source <(%s completion %s)", program, program, shell), nil
+	case Fish:
+		return fmt.Sprintf("command -q %s; and %s completion fish | source", program, program), nil
+	}
+	return "", unsupported(shell)
+}
+
+// Install appends line to the rc file at path between marker comments and
+// reports whether it changed the file; a file that already has the markers is
+// left alone. A symbolic link is followed, so dotfile managers keep their
+// link, and the file's permissions are kept.
+func Install(path, line string) (bool, error) {
+	return installWithDeps(path, line, defaultRCDeps())
+}
+
+func installWithDeps(path, line string, deps rcDeps) (bool, error) {
+	target, content, perm, err := readRC(path, deps)
+	if err != nil {
+		return false, err
+	}
+	if strings.Contains(content, beginMarker) {
+		return false, nil
+	}
+	if content != "" && !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	content += beginMarker + "\n" + line + "\n" + endMarker + "\n"
+	return true, writeRC(target, content, perm, deps)
+}
+
+// Uninstall removes the block added by Install and reports whether there was
+// one; the rest of the file is kept byte for byte.
+func Uninstall(path string) (bool, error) {
+	return uninstallWithDeps(path, defaultRCDeps())
+}
+
+func uninstallWithDeps(path string, deps rcDeps) (bool, error) {
+	target, content, perm, err := readRC(path, deps)
+	if err != nil {
+		return false, err
+	}
+	start := strings.Index(content, beginMarker)
+	if start < 0 {
+		return false, nil
+	}
+	end := strings.Index(content[start:], endMarker)
+	if end < 0 {
+		return false, fmt.Errorf("%s: %q has no closing %q; remove the block by hand", target, beginMarker, endMarker)
+	}
+	end += start + len(endMarker)
+	if end < len(content) && content[end] == '\n' {
+		end++
+	}
+	return true, writeRC(target, content[:start]+content[end:], perm, deps)
+}
+
+type rcDeps struct {
+	evalSymlinks func(string) (string, error)
+	lstat        func(string) (fs.FileInfo, error)
+	readFile     func(string) ([]byte, error)
+	write        func(string, []byte, fs.FileMode, bool) error
+}
+
+func defaultRCDeps() rcDeps {
+	return rcDeps{
+		evalSymlinks: filepath.EvalSymlinks,
+		lstat:        os.Lstat,
+		readFile:     os.ReadFile,
+		write:        fsx.AtomicWriteFile,
+	}
+}
+
+// readRC resolves path to the file to edit and returns its content and
+// permissions; a missing file reads as empty with mode 0644.
+func readRC(path string, deps rcDeps) (string, string, fs.FileMode, error) {
+	target, err := deps.evalSymlinks(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		// Only a dangling symbolic link can be found here.
+		if info, err := deps.lstat(path); err == nil {
+			return "", "", 0, fmt.Errorf("refusing to edit %s: %w", path, fsx.CheckReplaceable(info))
+		}
+		return path, "", 0o644, nil
+	}
+	if err != nil {
+		return "", "", 0, fmt.Errorf("resolve %s: %w", path, err)
+	}
+	info, err := deps.lstat(target)
+	if err != nil {
+		return "", "", 0, fmt.Errorf("stat %s: %w", target, err)
+	}
+	if err := fsx.CheckReplaceable(info); err != nil {
+		return "", "", 0, fmt.Errorf("refusing to edit %s: %w", target, err)
+	}
+	raw, err := deps.readFile(target)
+	if err != nil {
+		return "", "", 0, fmt.Errorf("read %s: %w", target, err)
+	}
+	return target, string(raw), info.Mode().Perm(), nil
+}
+
+func writeRC(path, content string, perm fs.FileMode, deps rcDeps) error {
+	if err := deps.write(path, []byte(content), perm, true); err != nil {
+		return fmt.Errorf("write %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,215 @@
+// Package shellcompletion renders tab-completion scripts for dev-vault and
+// installs the line that loads them into a shell's startup file. The line
+// sits between marker comments, so installing twice changes nothing and
+// uninstalling removes exactly what was added.
+package shellcompletion
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const (
+	Bash = "bash"
+	Zsh  = "zsh"
+	Fish = "fish"
+)
+
+// Shells lists the supported shells.
+func Shells() []string {
+	return []string{Bash, Fish, Zsh}
+}
+
+// Flag is a long option; TakesValue flags complete file names after them.
+type Flag struct {
+	Name       string
+	Help       string
+	TakesValue bool
+}
+
+// Command is a subcommand; with Names set its positional arguments complete
+// as secret names.
+type Command struct {
+	Name    string
+	Summary string
+	Flags   []Flag
+	Names   bool
+}
+
+// Spec describes the command line to complete. NamesArgs are the arguments
+// making Program print the secret names, one per line; the global options
+// named in NamesForward that were typed before the command are passed along.
+type Spec struct {
+	Program      string
+	Global       []Flag
+	Commands     []Command
+	NamesArgs    string
+	NamesForward []string
+}
+
+// Script renders the completion script of shell for spec.
+func Script(shell string, spec Spec) (string, error) {
+	switch shell {
+	case Bash:
+		return "# bash completion for " + spec.Program + "\n" + bashScript(spec), nil
+	case Zsh:
+		// zsh runs the bash function through its bash compatibility layer.
+		return "# zsh completion for " + spec.Program + "\n" +
+			"autoload -Uz compinit bashcompinit\n" +
+			"(( $+functions[compdef] )) || compinit\n" +
+			"bashcompinit\n" + bashScript(spec), nil
+	case Fish:
+		return fishScript(spec), nil
+	}
+	return "", unsupported(shell)
+}
+
+// Detect returns the shell named by a $SHELL path such as /bin/zsh.
+func Detect(shellPath string) (string, error) {
+	shell := filepath.Base(shellPath)
+	for _, supported := range Shells() {
+		if shell == supported {
+			return shell, nil
+		}
+	}
+	if shellPath == "" {
+		return "", fmt.Errorf("cannot detect the shell: SHELL is not set")
+	}
+	return "", unsupported(shell)
+}
+
+func unsupported(shell string) error {
+	return fmt.Errorf("unsupported shell %q (expected one of: %s)", shell, strings.Join(Shells(), ", "))
+}
+
+func bashScript(spec Spec) string {
+	fn := "_" + strings.ReplaceAll(spec.Program, "-", "_")
+	valueFlags := map[string]bool{}
+	globals := flagWords(spec.Global, valueFlags)
+	commands := make([]string, 0, len(spec.Commands))
+	commandWords := make([][]string, 0, len(spec.Commands))
+	for _, command := range spec.Commands {
+		commands = append(commands, command.Name)
+		commandWords = append(commandWords, append(flagWords(command.Flags, valueFlags), globals...))
+	}
+	// Global values are skipped while looking for the command, keeping the
+	// ones the names lookup needs; every value completes as a file name.
+	skipped := onlyValues(spec.Global)
+	forwarded := make([]string, 0, len(spec.NamesForward))
+	for _, name := range spec.NamesForward {
+		if skipped["--"+name] {
+			delete(skipped, "--"+name)
+			forwarded = append(forwarded, "--"+name)
+		}
+	}
+	allValues := strings.Join(sortedKeys(valueFlags), "|")
+	var nameCommands []string
+	for _, command := range spec.Commands {
+		if command.Names && spec.NamesArgs != "" {
+			nameCommands = append(nameCommands, command.Name)
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s() {\n", fn)
+	b.WriteString("    local cur=\"${COMP_WORDS[COMP_CWORD]}\" command=\"\" i forward=()\n")
+	b.WriteString("    for ((i = 1; i < COMP_CWORD; i++)); do\n")
+	b.WriteString("        case \"${COMP_WORDS[i]}\" in\n")
+	if len(forwarded) > 0 {
+		fmt.Fprintf(&b, "            %s) forward+=(\"${COMP_WORDS[i]}\" \"${COMP_WORDS[i+1]}\"); ((i++)) ;;\n", strings.Join(forwarded, "|"))
+	}
+	if len(skipped) > 0 {
+		fmt.Fprintf(&b, "            %s) ((i++)) ;;\n", strings.Join(sortedKeys(skipped), "|"))
+	}
+	b.WriteString("            -*) ;;\n")
+	b.WriteString("            *) command=\"${COMP_WORDS[i]}\"; break ;;\n")
+	b.WriteString("        esac\n")
+	b.WriteString("    done\n")
+	b.WriteString("    local words\n")
+	b.WriteString("    case \"$command\" in\n")
+	fmt.Fprintf(&b, "        \"\") words=%q ;;\n", strings.Join(append(globals, commands...), " "))
+	for i, command := range spec.Commands {
+		fmt.Fprintf(&b, "        %s) words=%q ;;\n", command.Name, strings.Join(commandWords[i], " "))
+	}
+	b.WriteString("    esac\n")
+	// Values and positional arguments fall back to file names; positional
+	// secret names come from the program, whose errors stay silent.
+	b.WriteString("    case \"${COMP_WORDS[COMP_CWORD-1]}\" in\n")
+	fmt.Fprintf(&b, "        %s) return ;;\n", allValues)
+	b.WriteString("    esac\n")
+	b.WriteString("    if [[ -n \"$command\" && \"$cur\" != -* ]]; then\n")
+	if len(nameCommands) > 0 {
+		b.WriteString("        case \"$command\" in\n")
+		fmt.Fprintf(&b, "            %s) COMPREPLY=($(compgen -W \"$(%s \"${forward[@]}\" %s 2>/dev/null)\" -- \"$cur\")) ;;\n", strings.Join(nameCommands, "|"), spec.Program, spec.NamesArgs)
+		b.WriteString("        esac\n")
+	}
+	b.WriteString("        return\n")
+	b.WriteString("    fi\n")
+	b.WriteString("    COMPREPLY=($(compgen -W \"$words\" -- \"$cur\"))\n")
+	b.WriteString("}\n")
+	fmt.Fprintf(&b, "complete -o default -F %s %s\n", fn, spec.Program)
+	return b.String()
+}
+
+// flagWords returns the --name words of flags and records the ones taking a
+// value in valueFlags.
+func flagWords(flags []Flag, valueFlags map[string]bool) []string {
+	words := make([]string, 0, len(flags))
+	for _, flag := range flags {
+		words = append(words, "--"+flag.Name)
+		if flag.TakesValue {
+			valueFlags["--"+flag.Name] = true
+		}
+	}
+	return words
+}
+
+func onlyValues(flags []Flag) map[string]bool {
+	values := map[string]bool{}
+	flagWords(flags, values)
+	return values
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func fishScript(spec Spec) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# fish completion for %s\n", spec.Program)
+	for _, flag := range spec.Global {
+		b.WriteString(fishFlag(spec.Program, "", flag))
+	}
+	for _, command := range spec.Commands {
+		fmt.Fprintf(&b, "complete -c %s -n __fish_use_subcommand -a %s -d %s\n", spec.Program, command.Name, fishQuote(command.Summary))
+	}
+	for _, command := range spec.Commands {
+		for _, flag := range command.Flags {
+			b.WriteString(fishFlag(spec.Program, command.Name, flag))
+		}
+	}
+	return b.String()
+}
+
+func fishFlag(program, command string, flag Flag) string {
+	line := "complete -c " + program
+	if command != "" {
+		line += " -n " + fishQuote("__fish_seen_subcommand_from "+command)
+	}
+	line += " -l " + flag.Name
+	if flag.TakesValue {
+		line += " -r"
+	}
+	return line + " -d " + fishQuote(flag.Help) + "\n"
+}
+
+func fishQuote(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s) + "'"
+}
@@ -0,0 +1,262 @@
+package shellcompletion
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bsmartlabs/dev-vault/internal/fsx"
+)
+
+var testSpec = Spec{
+	Program: "dev-vault",
+	Global:  []Flag{{Name: "config", Help: "Path to .scw.json", TakesValue: true}, {Name: "plain", Help: "Plain output"}},
+	Commands: []Command{
+		{Name: "pull", Summary: "Pull secrets", Flags: []Flag{{Name: "all", Help: "Pull everything"}, {Name: "revision", Help: "Revision to pull", TakesValue: true}}},
+		{Name: "ssh", Summary: "Use the agent's keys"},
+	},
+}
+
+func TestScript(t *testing.T) {
+	bash, err := Script(Bash, testSpec)
+	if err != nil {
+		t.Fatalf("bash: %v", err)
+	}
+	for _, want := range []string{
+		"_dev_vault() {\n",
+		"            --config) ((i++)) ;;\n",
+		`        "") words="--config --plain pull ssh" ;;` + "\n",
+		`        pull) words="--all --revision --config --plain" ;;` + "\n",
+		`        ssh) words="--config --plain" ;;` + "\n",
+		"        --config|--revision) return ;;\n",
+		"complete -o default -F _dev_vault dev-vault\n",
+	} {
+		if !strings.Contains(bash, want) {
+			t.Fatalf("bash script lacks %q:\n%s", want, bash)
+		}
+	}
+
+	if strings.Contains(bash, "forward+=") || strings.Contains(bash, "compgen -W \"$(") {
+		t.Fatalf("bash script without names lookup calls it:\n%s", bash)
+	}
+
+	names := testSpec
+	names.Commands = []Command{{Name: "pull", Names: true}, {Name: "push", Names: true}, {Name: "ssh"}}
+	names.NamesArgs, names.NamesForward = "completion --names", []string{"config", "plain", "missing"}
+	namesBash, _ := Script(Bash, names)
+	for _, want := range []string{
+		`            --config) forward+=("${COMP_WORDS[i]}" "${COMP_WORDS[i+1]}"); ((i++)) ;;` + "\n" +
+			"            -*) ;;\n",
+		`            pull|push) COMPREPLY=($(compgen -W "$(dev-vault "${forward[@]}" completion --names 2>/dev/null)" -- "$cur")) ;;` + "\n",
+	} {
+		if !strings.Contains(namesBash, want) {
+			t.Fatalf("bash script lacks %q:\n%s", want, namesBash)
+		}
+	}
+
+	zsh, err := Script(Zsh, testSpec)
+	if err != nil || !strings.HasPrefix(zsh, "# zsh completion for dev-vault\nautoload -Uz compinit bashcompinit\n") || !strings.HasSuffix(zsh, bash[strings.Index(bash, "\n")+1:]) {
+		t.Fatalf("unexpected zsh script: %v\n%s", err, zsh)
+	}
+
+	fish, err := Script(Fish, testSpec)
+	want := "# fish completion for dev-vault\n" +
+		"complete -c dev-vault -l config -r -d 'Path to .scw.json'\n" +
+		"complete -c dev-vault -l plain -d 'Plain output'\n" +
+		"complete -c dev-vault -n __fish_use_subcommand -a pull -d 'Pull secrets'\n" +
+		"complete -c dev-vault -n __fish_use_subcommand -a ssh -d 'Use the agent\\'s keys'\n" +
+		"complete -c dev-vault -n '__fish_seen_subcommand_from pull' -l all -d 'Pull everything'\n" +
+		"complete -c dev-vault -n '__fish_seen_subcommand_from pull' -l revision -r -d 'Revision to pull'\n"
+	if err != nil || fish != want {
+		t.Fatalf("unexpected fish script: %v\n%s", err, fish)
+	}
+
+	if _, err := Script("tcsh", testSpec); err == nil || err.Error() != `unsupported shell "tcsh" (expected one of: bash, fish, zsh)` {
+		t.Fatalf("expected unsupported shell, got %v", err)
+	}
+}
+
+func TestDetect(t *testing.T) {
+	for path, want := range map[string]string{"/bin/bash": Bash, "/usr/local/bin/fish": Fish, "zsh": Zsh} {
+		if got, err := Detect(path); err != nil || got != want {
+			t.Fatalf("%s: got %q %v", path, got, err)
+		}
+	}
+	if _, err := Detect(""); err == nil || err.Error() != "cannot detect the shell: SHELL is not set" {
+		t.Fatalf("expected unset error, got %v", err)
+	}
+	if _, err := Detect("/bin/sh"); err == nil || !strings.HasPrefix(err.Error(), `unsupported shell "sh"`) {
+		t.Fatalf("expected unsupported error, got %v", err)
+	}
+}
+
+func TestRCFileAndSourceLine(t *testing.T) {
+	env := func(vars map[string]string) func(string) string {
+		return func(key string) string { return vars[key] }
+	}
+	cases := []struct {
+		shell string
+		env   map[string]string
+		want  string
+	}{
+		{Bash, nil, "/home/dev/.bashrc"},
+		{Zsh, nil, "/home/dev/.zshrc"},
+		{Zsh, map[string]string{"ZDOTDIR": "/home/dev/.config/zsh"}, "/home/dev/.config/zsh/.zshrc"},
+		{Fish, nil, "/home/dev/.config/fish/config.fish"},
+		{Fish, map[string]string{"XDG_CONFIG_HOME": "/xdg"}, "/xdg/fish/config.fish"},
+	}
+	for _, tc := range cases {
+		if got, err := RCFile(tc.shell, "/home/dev", env(tc.env)); err != nil || got != filepath.FromSlash(tc.want) {
+			t.Fatalf("%s %v: got %q %v", tc.shell, tc.env, got, err)
+		}
+	}
+	if _, err := RCFile("tcsh", "/home/dev", env(nil)); err == nil {
+		t.Fatal("expected unsupported shell")
+	}
+
+	for shell, want := range map[string]string{
+		Bash: "command -v dev-vault >/dev/null 2>&1 && source <(dev-vault completion bash)",
+		Zsh:  "command -v dev-vault >/dev/null 2>&1 && source <(dev-vault completion zsh)",
+		Fish: "command -q dev-vault; and dev-vault completion fish | source",
+	} {
+		if got, err := SourceLine(shell, "dev-vault"); err != nil || got != want {
+			t.Fatalf("%s: got %q %v", shell, got, err)
+		}
+	}
+	if _, err := SourceLine("tcsh", "dev-vault"); err == nil {
+		t.Fatal("expected unsupported shell")
+	}
+}
+
+func TestInstallAndUninstall(t *testing.T) {
+	dir := t.TempDir()
+	const block = beginMarker + "\nsource it\n" + endMarker + "\n"
+
+	t.Run("NewFile", func(t *testing.T) {
+		path := filepath.Join(dir, "new", "config.fish")
+		if changed, err := Install(path, "source it"); err != nil || !changed {
+			t.Fatalf("install: %v %v", changed, err)
+		}
+		if got, _ := os.ReadFile(path); string(got) != block {
+			t.Fatalf("unexpected file %q", got)
+		}
+		if changed, err := Uninstall(path); err != nil || !changed {
+			t.Fatalf("uninstall: %v %v", changed, err)
+		}
+		if got, _ := os.ReadFile(path); string(got) != "" {
+			t.Fatalf("unexpected file %q", got)
+		}
+	})
+
+	t.Run("KeepsContentAndMode", func(t *testing.T) {
+		path := filepath.Join(dir, ".bashrc")
+		if err := os.WriteFile(path, []byte("alias ll='ls -l'"), 0o600); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+		for i, wantChanged := range []bool{true, false} {
+			if changed, err := Install(path, "source it"); err != nil || changed != wantChanged {
+				t.Fatalf("install %d: %v %v", i, changed, err)
+			}
+		}
+		if got, _ := os.ReadFile(path); string(got) != "alias ll='ls -l'\n"+block {
+			t.Fatalf("unexpected file %q", got)
+		}
+		if err := os.WriteFile(path, []byte("export A=1\n"+block+"export B=2\n"), 0o600); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+		if changed, err := Uninstall(path); err != nil || !changed {
+			t.Fatalf("uninstall: %v %v", changed, err)
+		}
+		if changed, err := Uninstall(path); err != nil || changed {
+			t.Fatalf("second uninstall: %v %v", changed, err)
+		}
+		got, _ := os.ReadFile(path)
+		info, _ := os.Stat(path)
+		if string(got) != "export A=1\nexport B=2\n" || info.Mode().Perm() != 0o600 {
+			t.Fatalf("unexpected file %q %v", got, info.Mode())
+		}
+	})
+
+	t.Run("FollowsSymlink", func(t *testing.T) {
+		target := filepath.Join(dir, "dotfiles", "zshrc")
+		if err := os.MkdirAll(filepath.Dir(target), 0o700); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := os.WriteFile(target, []byte("setopt x\n"), 0o644); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+		link := filepath.Join(dir, ".zshrc")
+		if err := os.Symlink(target, link); err != nil {
+			t.Skipf("symlinks unavailable: %v", err)
+		}
+		if changed, err := Install(link, "source it"); err != nil || !changed {
+			t.Fatalf("install: %v %v", changed, err)
+		}
+		if info, err := os.Lstat(link); err != nil || info.Mode()&os.ModeSymlink == 0 {
+			t.Fatalf("link replaced: %v %v", info, err)
+		}
+		if got, _ := os.ReadFile(target); string(got) != "setopt x\n"+block {
+			t.Fatalf("unexpected target %q", got)
+		}
+
+		dangling := filepath.Join(dir, "dangling")
+		if err := os.Symlink(filepath.Join(dir, "missing"), dangling); err != nil {
+			t.Fatalf("symlink: %v", err)
+		}
+		if _, err := Install(dangling, "source it"); !errors.Is(err, fsx.ErrNotRegular) {
+			t.Fatalf("expected dangling link refusal, got %v", err)
+		}
+	})
+
+	t.Run("Refusals", func(t *testing.T) {
+		path := filepath.Join(dir, "linked")
+		if err := os.WriteFile(path, []byte("x\n"), 0o644); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+		if err := os.Link(path, filepath.Join(dir, "other")); err != nil {
+			t.Skipf("hard links unavailable: %v", err)
+		}
+		if _, err := Install(path, "source it"); !errors.Is(err, fsx.ErrHardLinked) || !strings.HasPrefix(err.Error(), "refusing to edit "+path+": ") {
+			t.Fatalf("expected hard link refusal, got %v", err)
+		}
+		if _, err := Uninstall(dir); !errors.Is(err, fsx.ErrNotRegular) {
+			t.Fatalf("expected directory refusal, got %v", err)
+		}
+
+		broken := filepath.Join(dir, "broken")
+		if err := os.WriteFile(broken, []byte("a\n"+beginMarker+"\nsource it\n"), 0o644); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+		if _, err := Uninstall(broken); err == nil || !strings.Contains(err.Error(), "remove the block by hand") {
+			t.Fatalf("expected unterminated block error, got %v", err)
+		}
+	})
+	t.Run("FilesystemErrors", func(t *testing.T) {
+		path := filepath.Join(dir, "errors")
+		if err := os.WriteFile(path, []byte(block), 0o644); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+		boom := errors.New("boom")
+		cases := map[string]func(*rcDeps){
+			"resolve": func(d *rcDeps) { d.evalSymlinks = func(string) (string, error) { return "", boom } },
+			"stat":    func(d *rcDeps) { d.lstat = func(string) (fs.FileInfo, error) { return nil, boom } },
+			"read":    func(d *rcDeps) { d.readFile = func(string) ([]byte, error) { return nil, boom } },
+			"write":   func(d *rcDeps) { d.write = func(string, []byte, fs.FileMode, bool) error { return boom } },
+		}
+		for op, breakDeps := range cases {
+			deps := defaultRCDeps()
+			breakDeps(&deps)
+			if _, err := uninstallWithDeps(path, deps); !errors.Is(err, boom) || !strings.HasPrefix(err.Error(), op+" "+path+": ") {
+				t.Fatalf("%s: expected boom, got %v", op, err)
+			}
+		}
+		deps := defaultRCDeps()
+		cases["write"](&deps)
+		if _, err := installWithDeps(filepath.Join(dir, "unwritten"), "source it", deps); !errors.Is(err, boom) {
+			t.Fatalf("expected boom, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,215 @@
+// Package sshkey handles ssh_key secret payloads: extracting the private key,
+// deriving its authorized_keys line, and loading it into ssh-agent without
+// writing it to disk.
+package sshkey
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"os/exec"
+	"strings"
+)
+
+// PrivateKeyField is the payload field holding the PEM private key.
+const PrivateKeyField = "ssh_private_key"
+
+const opensshMagic = "openssh-key-v1\x00"
+
+// FromPayload returns the PEM private key held by an ssh_key payload, with a
+// trailing newline. A bare PEM payload is accepted as is.
+func FromPayload(payload []byte) ([]byte, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(payload, &fields); err == nil {
+		var key string
+		if err := json.Unmarshal(fields[PrivateKeyField], &key); err != nil || key == "" {
+			return nil, fmt.Errorf("ssh_key payload: missing %s", PrivateKeyField)
+		}
+		payload = []byte(key)
+	}
+	if !IsPrivateKey(payload) {
+		return nil, errors.New("ssh_key payload: expected a PEM private key")
+	}
+	key := bytes.TrimSpace(payload)
+	return append(key[:len(key):len(key)], '\n'), nil
+}
+
+// ToPayload wraps a PEM private key in the ssh_key payload format.
+func ToPayload(key []byte) []byte {
+	payload, _ := json.Marshal(map[string]string{PrivateKeyField: string(key)}) // string maps always encode
+	return payload
+}
+
+// IsPrivateKey reports whether raw starts with a PEM private key block.
+func IsPrivateKey(raw []byte) bool {
+	block, _ := pem.Decode(raw)
+	return block != nil && strings.HasSuffix(block.Type, "PRIVATE KEY")
+}
+
+// AuthorizedKey derives the public key line ("<type> <base64> <comment>") for
+// a PEM private key. OpenSSH keys carry their public half unencrypted, so this
+// works for passphrase-protected keys in that format too.
+func AuthorizedKey(key []byte, comment string) ([]byte, error) {
+	block, _ := pem.Decode(key)
+	if block == nil {
+		return nil, errors.New("decode private key: no PEM block")
+	}
+	var (
+		blob []byte
+		err  error
+	)
+	switch block.Type {
+	case "OPENSSH PRIVATE KEY":
+		blob, err = opensshPublicBlob(block.Bytes)
+	case "RSA PRIVATE KEY":
+		var k *rsa.PrivateKey
+		if k, err = x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+			blob, err = publicBlob(k)
+		}
+	case "EC PRIVATE KEY":
+		var k *ecdsa.PrivateKey
+		if k, err = x509.ParseECPrivateKey(block.Bytes); err == nil {
+			blob, err = publicBlob(k)
+		}
+	case "PRIVATE KEY":
+		var k any
+		if k, err = x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+			blob, err = publicBlob(k)
+		}
+	default:
+		err = fmt.Errorf("unsupported PEM block %q", block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("decode private key: %w", err)
+	}
+	keyType, _, _ := readString(blob) // blobs built or validated above start with their type
+	line := string(keyType) + " " + base64.StdEncoding.EncodeToString(blob)
+	if comment != "" {
+		line += " " + comment
+	}
+	return []byte(line + "\n"), nil
+}
+
+// opensshPublicBlob reads the public key stored in the unencrypted header of
+// the openssh-key-v1 format.
+func opensshPublicBlob(data []byte) ([]byte, error) {
+	rest, ok := bytes.CutPrefix(data, []byte(opensshMagic))
+	if !ok {
+		return nil, errors.New("not an openssh-key-v1 key")
+	}
+	for range 3 { // cipher name, kdf name, kdf options
+		if _, rest, ok = readString(rest); !ok {
+			return nil, errors.New("truncated openssh key header")
+		}
+	}
+	if len(rest) < 4 || binary.BigEndian.Uint32(rest) != 1 {
+		return nil, errors.New("openssh key must hold exactly one key")
+	}
+	blob, _, ok := readString(rest[4:])
+	if !ok {
+		return nil, errors.New("truncated openssh public key")
+	}
+	if _, _, ok := readString(blob); !ok {
+		return nil, errors.New("malformed openssh public key")
+	}
+	return blob, nil
+}
+
+// publicBlob encodes the public half of a parsed private key in the SSH wire
+// format.
+func publicBlob(private any) ([]byte, error) {
+	var b bytes.Buffer
+	switch k := private.(type) {
+	case *rsa.PrivateKey:
+		writeString(&b, []byte("ssh-rsa"))
+		writeString(&b, mpint(big.NewInt(int64(k.E))))
+		writeString(&b, mpint(k.N))
+	case ed25519.PrivateKey:
+		writeString(&b, []byte("ssh-ed25519"))
+		writeString(&b, k.Public().(ed25519.PublicKey))
+	case *ecdsa.PrivateKey:
+		curves := map[elliptic.Curve]string{elliptic.P256(): "nistp256", elliptic.P384(): "nistp384", elliptic.P521(): "nistp521"}
+		curve, ok := curves[k.Curve]
+		if !ok {
+			return nil, errors.New("unsupported ecdsa curve")
+		}
+		point, _ := k.PublicKey.ECDH() // the curve is one of the NIST curves above
+		writeString(&b, []byte("ecdsa-sha2-"+curve))
+		writeString(&b, []byte(curve))
+		writeString(&b, point.Bytes())
+	default:
+		return nil, fmt.Errorf("unsupported key type %T", private)
+	}
+	return b.Bytes(), nil
+}
+
+func readString(data []byte) ([]byte, []byte, bool) {
+	if len(data) < 4 {
+		return nil, nil, false
+	}
+	n := binary.BigEndian.Uint32(data)
+	if uint64(len(data)-4) < uint64(n) {
+		return nil, nil, false
+	}
+	return data[4 : 4+n], data[4+n:], true
+}
+
+func writeString(b *bytes.Buffer, s []byte) {
+	_ = binary.Write(b, binary.BigEndian, uint32(len(s))) // bytes.Buffer writes cannot fail
+	b.Write(s)
+}
+
+// mpint encodes a non-negative integer in the SSH wire format: big-endian
+// with a leading zero byte when the high bit is set.
+func mpint(n *big.Int) []byte {
+	raw := n.Bytes()
+	if len(raw) > 0 && raw[0]&0x80 != 0 {
+		return append([]byte{0}, raw...)
+	}
+	return raw
+}
+
+type agentDeps struct {
+	lookPath func(string) (string, error)
+	run      func(path string, args []string, stdin []byte, stderr io.Writer) error
+}
+
+var defaultAgentDeps = agentDeps{
+	lookPath: exec.LookPath,
+	run:      runSSHAdd,
+}
+
+func runSSHAdd(path string, args []string, stdin []byte, stderr io.Writer) error {
+	cmd := exec.Command(path, args...)
+	cmd.Stdin = bytes.NewReader(stdin)
+	cmd.Stderr = stderr
+	return cmd.Run()
+}
+
+// AddToAgent pipes key into `ssh-add -`, so it reaches ssh-agent without a
+// file. ssh-add reports its own progress and passphrase prompts on stderr.
+func AddToAgent(key []byte, stderr io.Writer) error {
+	return addToAgent(key, stderr, defaultAgentDeps)
+}
+
+func addToAgent(key []byte, stderr io.Writer, deps agentDeps) error {
+	path, err := deps.lookPath("ssh-add")
+	if err != nil {
+		return fmt.Errorf("ssh-add is not on PATH: %w", err)
+	}
+	if err := deps.run(path, []string{"-"}, key, stderr); err != nil {
+		return fmt.Errorf("ssh-add: %w", err)
+	}
+	return nil
+}
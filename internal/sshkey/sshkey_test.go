@@ -0,0 +1,170 @@
+package sshkey
+
+import (
+	"bytes"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/pem"
+	"errors"
+	"io"
+	"math/big"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func pemBlock(typ string, der []byte) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: typ, Bytes: der})
+}
+
+func opensshKey(header ...[]byte) []byte {
+	var b bytes.Buffer
+	b.WriteString(opensshMagic)
+	for _, field := range header {
+		writeString(&b, field)
+	}
+	return b.Bytes()
+}
+
+func TestPayload(t *testing.T) {
+	_, priv, _ := ed25519.GenerateKey(rand.Reader)
+	der, _ := x509.MarshalPKCS8PrivateKey(priv)
+	key := pemBlock("PRIVATE KEY", der)
+
+	got, err := FromPayload(ToPayload(key))
+	if err != nil || !bytes.Equal(got, key) {
+		t.Fatalf("round trip failed: %v", err)
+	}
+	if got, err := FromPayload(append([]byte("\n"), bytes.TrimSpace(key)...)); err != nil || !bytes.Equal(got, key) {
+		t.Fatalf("bare PEM payload: %v", err)
+	}
+	for payload, wantErr := range map[string]string{
+		`{"ssh_public_key":"x"}`:                     "missing ssh_private_key",
+		`{"ssh_private_key":""}`:                     "missing ssh_private_key",
+		`{"ssh_private_key":"x"}`:                    "expected a PEM private key",
+		"not a key":                                  "expected a PEM private key",
+		string(pemBlock("CERTIFICATE", []byte("x"))): "expected a PEM private key",
+	} {
+		if _, err := FromPayload([]byte(payload)); err == nil || !strings.Contains(err.Error(), wantErr) {
+			t.Fatalf("%q: expected %q, got %v", payload, wantErr, err)
+		}
+	}
+}
+
+func TestAuthorizedKey(t *testing.T) {
+	edPub, edPriv, _ := ed25519.GenerateKey(rand.Reader)
+	edDER, _ := x509.MarshalPKCS8PrivateKey(edPriv)
+	line, err := AuthorizedKey(pemBlock("PRIVATE KEY", edDER), "deploy-key-dev")
+	if err != nil {
+		t.Fatalf("ed25519: %v", err)
+	}
+	fields := strings.Fields(string(line))
+	if len(fields) != 3 || fields[0] != "ssh-ed25519" || fields[2] != "deploy-key-dev" || !strings.HasSuffix(string(line), "\n") {
+		t.Fatalf("unexpected ed25519 line: %q", line)
+	}
+	blob, _ := base64.StdEncoding.DecodeString(fields[1])
+	if !bytes.HasSuffix(blob, edPub) {
+		t.Fatal("ed25519 blob does not carry the public key")
+	}
+
+	// The openssh-key-v1 header stores the same blob, even for encrypted keys.
+	openssh := pemBlock("OPENSSH PRIVATE KEY", append(opensshKey([]byte("aes256-ctr"), []byte("bcrypt"), []byte("opts")), append([]byte{0, 0, 0, 1}, opensshKey(blob)[len(opensshMagic):]...)...))
+	if got, err := AuthorizedKey(openssh, ""); err != nil || string(got) != fields[0]+" "+fields[1]+"\n" {
+		t.Fatalf("unexpected openssh line: %q %v", got, err)
+	}
+
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 1024)
+	line, err = AuthorizedKey(pemBlock("RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(rsaKey)), "")
+	if err != nil || !strings.HasPrefix(string(line), "ssh-rsa AAAAB3NzaC1yc2E") {
+		t.Fatalf("unexpected rsa line: %q %v", line, err)
+	}
+	for _, curve := range []struct {
+		curve elliptic.Curve
+		name  string
+	}{{elliptic.P256(), "nistp256"}, {elliptic.P384(), "nistp384"}, {elliptic.P521(), "nistp521"}} {
+		ecKey, _ := ecdsa.GenerateKey(curve.curve, rand.Reader)
+		ecDER, _ := x509.MarshalECPrivateKey(ecKey)
+		line, err := AuthorizedKey(pemBlock("EC PRIVATE KEY", ecDER), "")
+		if err != nil || !strings.HasPrefix(string(line), "ecdsa-sha2-"+curve.name+" ") {
+			t.Fatalf("unexpected %s line: %q %v", curve.name, line, err)
+		}
+	}
+
+	p224, _ := ecdsa.GenerateKey(elliptic.P224(), rand.Reader)
+	p224DER, _ := x509.MarshalECPrivateKey(p224)
+	x25519, _ := ecdh.X25519().GenerateKey(rand.Reader)
+	x25519DER, _ := x509.MarshalPKCS8PrivateKey(x25519)
+	var twoKeys bytes.Buffer
+	twoKeys.Write(opensshKey([]byte("none"), []byte("none"), nil))
+	_ = binary.Write(&twoKeys, binary.BigEndian, uint32(2))
+	for name, tc := range map[string]struct {
+		key     []byte
+		wantErr string
+	}{
+		"not pem":          {[]byte("nope"), "no PEM block"},
+		"unknown block":    {pemBlock("DSA PRIVATE KEY", []byte("x")), `unsupported PEM block "DSA PRIVATE KEY"`},
+		"bad pkcs1":        {pemBlock("RSA PRIVATE KEY", []byte("x")), "decode private key"},
+		"bad sec1":         {pemBlock("EC PRIVATE KEY", []byte("x")), "decode private key"},
+		"bad pkcs8":        {pemBlock("PRIVATE KEY", []byte("x")), "decode private key"},
+		"p224":             {pemBlock("EC PRIVATE KEY", p224DER), "unsupported ecdsa curve"},
+		"x25519":           {pemBlock("PRIVATE KEY", x25519DER), "unsupported key type *ecdh.PrivateKey"},
+		"bad magic":        {pemBlock("OPENSSH PRIVATE KEY", []byte("nope")), "not an openssh-key-v1 key"},
+		"truncated header": {pemBlock("OPENSSH PRIVATE KEY", opensshKey([]byte("none"))), "truncated openssh key header"},
+		"two keys":         {pemBlock("OPENSSH PRIVATE KEY", twoKeys.Bytes()), "exactly one key"},
+		"truncated blob":   {pemBlock("OPENSSH PRIVATE KEY", append(opensshKey([]byte("none"), []byte("none"), nil), 0, 0, 0, 1, 0, 0, 0, 9)), "truncated openssh public key"},
+		"malformed blob":   {pemBlock("OPENSSH PRIVATE KEY", append(opensshKey([]byte("none"), []byte("none"), nil), append([]byte{0, 0, 0, 1}, opensshKey([]byte{0, 0})[len(opensshMagic):]...)...)), "malformed openssh public key"},
+	} {
+		if _, err := AuthorizedKey(tc.key, ""); err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+			t.Fatalf("%s: expected %q, got %v", name, tc.wantErr, err)
+		}
+	}
+}
+
+func TestMpint(t *testing.T) {
+	for n, want := range map[int64][]byte{0: {}, 0x7f: {0x7f}, 0x80: {0, 0x80}} {
+		if got := mpint(big.NewInt(n)); !bytes.Equal(got, want) {
+			t.Fatalf("mpint(%d) = %x, want %x", n, got, want)
+		}
+	}
+}
+
+func TestAddToAgent(t *testing.T) {
+	var gotPath string
+	var gotArgs []string
+	var gotStdin []byte
+	deps := agentDeps{
+		lookPath: func(name string) (string, error) { return "/usr/bin/" + name, nil },
+		run: func(path string, args []string, stdin []byte, _ io.Writer) error {
+			gotPath, gotArgs, gotStdin = path, args, stdin
+			return nil
+		},
+	}
+	if err := addToAgent([]byte("key"), io.Discard, deps); err != nil || gotPath != "/usr/bin/ssh-add" || !reflect.DeepEqual(gotArgs, []string{"-"}) || string(gotStdin) != "key" {
+		t.Fatalf("unexpected ssh-add call: %q %v %q %v", gotPath, gotArgs, gotStdin, err)
+	}
+	missing := deps
+	missing.lookPath = func(string) (string, error) { return "", errors.New("not found") }
+	if err := addToAgent(nil, io.Discard, missing); err == nil || !strings.Contains(err.Error(), "ssh-add is not on PATH") {
+		t.Fatalf("expected lookPath error, got %v", err)
+	}
+	failing := deps
+	failing.run = func(string, []string, []byte, io.Writer) error { return errors.New("exit status 1") }
+	if err := addToAgent(nil, io.Discard, failing); err == nil || err.Error() != "ssh-add: exit status 1" {
+		t.Fatalf("expected run error, got %v", err)
+	}
+	if err := runSSHAdd(filepath.Join(t.TempDir(), "ssh-add"), nil, nil, io.Discard); err == nil {
+		t.Fatal("expected exec error")
+	}
+	t.Setenv("PATH", t.TempDir())
+	if err := AddToAgent([]byte("key"), io.Discard); err == nil {
+		t.Fatal("expected ssh-add lookup to fail with an empty PATH")
+	}
+}
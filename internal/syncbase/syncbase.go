@@ -0,0 +1,118 @@
+// Package syncbase records, per config, the secret and revision each managed
+// file was last pulled or pushed at: the base sync compares both sides with.
+// Unlike the user.dev-vault extended attribute, which only Linux filesystems
+// with user attributes keep, the record works on every platform. Records
+// hold file paths, secret names, and revisions only, never payloads.
+package syncbase
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/bsmartlabs/dev-vault/internal/fsx"
+)
+
+const dirName = "sync"
+
+// Base is the secret a file was written from or pushed to, and the revision.
+type Base struct {
+	Name     string `json:"name"`
+	Revision uint32 `json:"revision"`
+}
+
+type Record struct {
+	Config string `json:"config"`
+	// Files maps the path of a managed file to its base.
+	Files map[string]Base `json:"files"`
+}
+
+// Store keeps one record per config file under a per-user directory.
+type Store struct {
+	dir string
+}
+
+func NewStore(dir string) Store {
+	return Store{dir: filepath.Join(dir, dirName)}
+}
+
+// Path names the record file after a hash of the config path, so files of
+// different repositories never share bases.
+func (s Store) Path(configPath string) string {
+	sum := sha256.Sum256([]byte(configPath))
+	return filepath.Join(s.dir, hex.EncodeToString(sum[:8])+".json")
+}
+
+// Load returns the record of configPath, empty when there is none.
+func (s Store) Load(configPath string) (Record, error) {
+	empty := Record{Config: configPath}
+	raw, err := os.ReadFile(s.Path(configPath))
+	if errors.Is(err, os.ErrNotExist) {
+		return empty, nil
+	}
+	if err != nil {
+		return empty, fmt.Errorf("read sync bases: %w", err)
+	}
+	var record Record
+	if err := json.Unmarshal(raw, &record); err != nil {
+		return empty, fmt.Errorf("decode sync bases: %w", err)
+	}
+	if record.Config != configPath {
+		return empty, nil
+	}
+	return record, nil
+}
+
+func (s Store) Save(record Record) error {
+	raw, _ := json.Marshal(record) // strings and numbers only; always encodes
+	if err := fsx.AtomicWriteFile(s.Path(record.Config), append(raw, '\n'), 0o600, true); err != nil {
+		return fmt.Errorf("write sync bases: %w", err)
+	}
+	return nil
+}
+
+// Tracker holds the record of one config and saves every change to it. It
+// is safe for concurrent use.
+type Tracker struct {
+	store  Store
+	mu     sync.Mutex
+	record Record
+}
+
+// Open loads the record of configPath for tracking.
+func (s Store) Open(configPath string) (*Tracker, error) {
+	record, err := s.Load(configPath)
+	if err != nil {
+		return nil, err
+	}
+	return &Tracker{store: s, record: record}, nil
+}
+
+// Base returns the recorded base of the file at path.
+func (t *Tracker) Base(path string) (name string, revision uint32, ok bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	base, ok := t.record.Files[path]
+	return base.Name, base.Revision, ok
+}
+
+// SetBase records that the file at path was last synced with revision of
+// name. An unchanged base is not written again.
+func (t *Tracker) SetBase(path, name string, revision uint32) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	base := Base{Name: name, Revision: revision}
+	if current, ok := t.record.Files[path]; ok && current == base {
+		return nil
+	}
+	if t.record.Files == nil {
+		t.record.Files = make(map[string]Base)
+	}
+	t.record.Files[path] = base
+	return t.store.Save(t.record)
+}
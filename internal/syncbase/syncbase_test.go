@@ -0,0 +1,82 @@
+package syncbase
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTrackerRoundTrip(t *testing.T) {
+	store := NewStore(t.TempDir())
+	tracker, err := store.Open("/repo/.scw.json")
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	if _, _, ok := tracker.Base("/repo/app.env"); ok {
+		t.Fatal("expected no base in a new record")
+	}
+	if err := tracker.SetBase("/repo/app.env", "app-dev", 3); err != nil {
+		t.Fatalf("SetBase: %v", err)
+	}
+	reopened, err := store.Open("/repo/.scw.json")
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	if name, revision, ok := reopened.Base("/repo/app.env"); !ok || name != "app-dev" || revision != 3 {
+		t.Fatalf("unexpected base: %q %d %v", name, revision, ok)
+	}
+	if other, _ := store.Open("/other/.scw.json"); len(other.record.Files) != 0 {
+		t.Fatal("expected another config to have no record")
+	}
+
+	// An unchanged base is not written again.
+	if err := os.Remove(store.Path("/repo/.scw.json")); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	if err := reopened.SetBase("/repo/app.env", "app-dev", 3); err != nil {
+		t.Fatalf("SetBase: %v", err)
+	}
+	if _, err := os.Stat(store.Path("/repo/.scw.json")); !os.IsNotExist(err) {
+		t.Fatalf("expected no write, got %v", err)
+	}
+
+	// A record copied under another key must not be trusted.
+	if err := reopened.SetBase("/repo/app.env", "app-dev", 4); err != nil {
+		t.Fatalf("SetBase: %v", err)
+	}
+	if err := os.Rename(store.Path("/repo/.scw.json"), store.Path("/b")); err != nil {
+		t.Fatalf("rename: %v", err)
+	}
+	if foreign, err := store.Load("/b"); err != nil || len(foreign.Files) != 0 {
+		t.Fatalf("expected the foreign record ignored, got %#v %v", foreign, err)
+	}
+}
+
+func TestStoreErrors(t *testing.T) {
+	store := NewStore(t.TempDir())
+	path := store.Path("/a")
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte("{"), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if _, err := store.Open("/a"); err == nil || !strings.Contains(err.Error(), "decode sync bases") {
+		t.Fatalf("expected decode error, got %v", err)
+	}
+
+	if err := os.Remove(path); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(path, "child"), 0o700); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if _, err := store.Load("/a"); err == nil || !strings.Contains(err.Error(), "read sync bases") {
+		t.Fatalf("expected read error, got %v", err)
+	}
+	tracker := &Tracker{store: store, record: Record{Config: "/a"}}
+	if err := tracker.SetBase("/a.env", "a-dev", 1); err == nil || !strings.Contains(err.Error(), "write sync bases") {
+		t.Fatalf("expected write error, got %v", err)
+	}
+}
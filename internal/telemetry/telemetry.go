@@ -0,0 +1,152 @@
+// Package telemetry implements strictly opt-in usage recording. Events hold a
+// command name, a duration, and an error kind; nothing identifies the user,
+// the project, or any secret. Events are only written to a local file.
+package telemetry
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/bsmartlabs/dev-vault/internal/fsx"
+)
+
+const (
+	stateFileName         = "telemetry.json"
+	eventsFileName        = "telemetry-events.jsonl"
+	rotatedEventsFileName = "telemetry-events.1.jsonl"
+
+	// maxEventsSize caps the events file. Past it, the file is rotated and
+	// replaces the previous rotation, so at most about twice this is kept.
+	maxEventsSize = 1 << 20
+
+	ErrorKindNone        = "none"
+	ErrorKindUsage       = "usage"
+	ErrorKindFailure     = "failure"
+	ErrorKindInterrupted = "interrupted"
+)
+
+type Event struct {
+	Command    string `json:"command"`
+	DurationMS int64  `json:"duration_ms"`
+	ErrorKind  string `json:"error_kind"`
+}
+
+// ErrorKindForExitCode maps CLI exit codes onto the coarse kinds recorded in
+// events, so error messages (which may contain names or paths) never leak.
+func ErrorKindForExitCode(code int) string {
+	switch code {
+	case 0:
+		return ErrorKindNone
+	case 2:
+		return ErrorKindUsage
+	case 130:
+		return ErrorKindInterrupted
+	default:
+		return ErrorKindFailure
+	}
+}
+
+// DoNotTrack follows the consoledonottrack.com convention: any value other
+// than empty, "0", or "false" disables recording regardless of consent.
+func DoNotTrack(getenv func(string) string) bool {
+	value := strings.TrimSpace(getenv("DO_NOT_TRACK"))
+	return value != "" && value != "0" && !strings.EqualFold(value, "false")
+}
+
+type state struct {
+	Enabled bool `json:"enabled"`
+}
+
+// Store keeps consent and recorded events under a per-user directory.
+type Store struct {
+	dir string
+}
+
+func NewStore(dir string) Store {
+	return Store{dir: dir}
+}
+
+func (s Store) StatePath() string {
+	return filepath.Join(s.dir, stateFileName)
+}
+
+func (s Store) EventsPath() string {
+	return filepath.Join(s.dir, eventsFileName)
+}
+
+// Enabled reports recorded consent; with no state file telemetry is off.
+func (s Store) Enabled() (bool, error) {
+	raw, err := os.ReadFile(s.StatePath())
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("read telemetry state: %w", err)
+	}
+	var st state
+	if err := json.Unmarshal(raw, &st); err != nil {
+		return false, fmt.Errorf("decode telemetry state: %w", err)
+	}
+	return st.Enabled, nil
+}
+
+func (s Store) SetEnabled(enabled bool) error {
+	raw, _ := json.Marshal(state{Enabled: enabled}) // a single bool always encodes
+	if err := fsx.AtomicWriteFile(s.StatePath(), append(raw, '\n'), 0o600, true); err != nil {
+		return fmt.Errorf("write telemetry state: %w", err)
+	}
+	return nil
+}
+
+// RotatedEventsPath is where the events file goes once it reaches the cap.
+func (s Store) RotatedEventsPath() string {
+	return filepath.Join(s.dir, rotatedEventsFileName)
+}
+
+// Record appends one event, first rotating the events file when it has
+// reached maxEventsSize.
+func (s Store) Record(command string, duration time.Duration, exitCode int) error {
+	line, _ := json.Marshal(Event{ // strings and ints always encode
+		Command:    command,
+		DurationMS: duration.Milliseconds(),
+		ErrorKind:  ErrorKindForExitCode(exitCode),
+	})
+	if info, err := os.Stat(s.EventsPath()); err == nil && info.Size() >= maxEventsSize {
+		if err := os.Rename(s.EventsPath(), s.RotatedEventsPath()); err != nil {
+			return fmt.Errorf("rotate telemetry events: %w", err)
+		}
+	}
+	f, err := os.OpenFile(s.EventsPath(), os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+	if err == nil {
+		_, err = f.Write(append(line, '\n'))
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("record telemetry event: %w", err)
+	}
+	return nil
+}
+
+// Count returns the number of recorded events, rotated ones included.
+func (s Store) Count() (int, error) {
+	count := 0
+	for _, path := range []string{s.RotatedEventsPath(), s.EventsPath()} {
+		raw, err := os.ReadFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return 0, fmt.Errorf("read telemetry events: %w", err)
+		}
+		count += bytes.Count(raw, []byte("\n"))
+	}
+	return count, nil
+}
@@ -0,0 +1,149 @@
+package telemetry
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestErrorKindForExitCode(t *testing.T) {
+	cases := map[int]string{0: ErrorKindNone, 1: ErrorKindFailure, 2: ErrorKindUsage, 130: ErrorKindInterrupted}
+	for code, want := range cases {
+		if got := ErrorKindForExitCode(code); got != want {
+			t.Fatalf("ErrorKindForExitCode(%d) = %q, want %q", code, got, want)
+		}
+	}
+}
+
+func TestDoNotTrack(t *testing.T) {
+	cases := map[string]bool{"": false, "0": false, "false": false, "FALSE": false, "1": true, "true": true, "yes": true}
+	for value, want := range cases {
+		getenv := func(key string) string {
+			if key == "DO_NOT_TRACK" {
+				return value
+			}
+			return ""
+		}
+		if got := DoNotTrack(getenv); got != want {
+			t.Fatalf("DoNotTrack(%q) = %v, want %v", value, got, want)
+		}
+	}
+}
+
+func TestStore(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "dev-vault"))
+
+	enabled, err := store.Enabled()
+	if err != nil || enabled {
+		t.Fatalf("expected telemetry off by default, got %v, %v", enabled, err)
+	}
+	if count, err := store.Count(); err != nil || count != 0 {
+		t.Fatalf("expected no events, got %d, %v", count, err)
+	}
+
+	if err := store.SetEnabled(true); err != nil {
+		t.Fatalf("SetEnabled: %v", err)
+	}
+	if enabled, err := store.Enabled(); err != nil || !enabled {
+		t.Fatalf("expected telemetry on, got %v, %v", enabled, err)
+	}
+
+	if err := store.Record("pull", 1500*time.Millisecond, 0); err != nil {
+		t.Fatalf("Record: %v", err)
+	}
+	if err := store.Record("push", 20*time.Millisecond, 2); err != nil {
+		t.Fatalf("Record: %v", err)
+	}
+	if count, err := store.Count(); err != nil || count != 2 {
+		t.Fatalf("expected 2 events, got %d, %v", count, err)
+	}
+	raw, err := os.ReadFile(store.EventsPath())
+	if err != nil {
+		t.Fatalf("read events: %v", err)
+	}
+	want := `{"command":"pull","duration_ms":1500,"error_kind":"none"}` + "\n" + `{"command":"push","duration_ms":20,"error_kind":"usage"}` + "\n"
+	if string(raw) != want {
+		t.Fatalf("unexpected events:\n%s", raw)
+	}
+
+	if err := store.SetEnabled(false); err != nil {
+		t.Fatalf("SetEnabled: %v", err)
+	}
+	if enabled, err := store.Enabled(); err != nil || enabled {
+		t.Fatalf("expected telemetry off, got %v, %v", enabled, err)
+	}
+}
+
+func TestStoreRotatesEvents(t *testing.T) {
+	store := NewStore(t.TempDir())
+	full := strings.Repeat("{}\n", maxEventsSize/3+1)
+	if err := os.WriteFile(store.EventsPath(), []byte(full), 0o600); err != nil {
+		t.Fatalf("write events: %v", err)
+	}
+	if err := store.Record("pull", time.Second, 0); err != nil {
+		t.Fatalf("Record: %v", err)
+	}
+	if rotated, err := os.ReadFile(store.RotatedEventsPath()); err != nil || string(rotated) != full {
+		t.Fatalf("expected the full file rotated, got %d bytes, %v", len(rotated), err)
+	}
+	if raw, err := os.ReadFile(store.EventsPath()); err != nil || string(raw) != `{"command":"pull","duration_ms":1000,"error_kind":"none"}`+"\n" {
+		t.Fatalf("expected a fresh events file, got %q, %v", raw, err)
+	}
+	if count, err := store.Count(); err != nil || count != maxEventsSize/3+2 {
+		t.Fatalf("expected rotated events counted, got %d, %v", count, err)
+	}
+
+	// A rotation that cannot happen fails the record.
+	if err := os.WriteFile(store.EventsPath(), []byte(full), 0o600); err != nil {
+		t.Fatalf("write events: %v", err)
+	}
+	if err := os.Remove(store.RotatedEventsPath()); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(store.RotatedEventsPath(), "child"), 0o700); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := store.Record("pull", time.Second, 0); err == nil || !strings.Contains(err.Error(), "rotate telemetry events") {
+		t.Fatalf("expected rotate error, got %v", err)
+	}
+}
+
+func TestStoreErrors(t *testing.T) {
+	dir := t.TempDir()
+	store := NewStore(dir)
+
+	if err := os.WriteFile(store.StatePath(), []byte("{"), 0o600); err != nil {
+		t.Fatalf("write state: %v", err)
+	}
+	if _, err := store.Enabled(); err == nil || !strings.Contains(err.Error(), "decode telemetry state") {
+		t.Fatalf("expected decode error, got %v", err)
+	}
+
+	for _, path := range []string{store.StatePath(), store.EventsPath()} {
+		if err := os.RemoveAll(path); err != nil {
+			t.Fatalf("remove: %v", err)
+		}
+		if err := os.Mkdir(path, 0o700); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+	}
+	if _, err := store.Enabled(); err == nil || !strings.Contains(err.Error(), "read telemetry state") {
+		t.Fatalf("expected read error, got %v", err)
+	}
+	if err := store.Record("pull", time.Second, 0); err == nil || !strings.Contains(err.Error(), "record telemetry event") {
+		t.Fatalf("expected record error, got %v", err)
+	}
+	if _, err := store.Count(); err == nil || !strings.Contains(err.Error(), "read telemetry events") {
+		t.Fatalf("expected count error, got %v", err)
+	}
+
+	blocker := filepath.Join(dir, "not-a-dir")
+	if err := os.WriteFile(blocker, nil, 0o600); err != nil {
+		t.Fatalf("write blocker: %v", err)
+	}
+	if err := NewStore(filepath.Join(blocker, "dev-vault")).SetEnabled(true); err == nil || !strings.Contains(err.Error(), "write telemetry state") {
+		t.Fatalf("expected write error, got %v", err)
+	}
+}
@@ -0,0 +1,565 @@
+// Package tomlmap converts JSON-object payloads to TOML documents and back.
+// Render sorts keys at every level, writes nested objects as [tables] and
+// arrays of objects as [[arrays of tables]], so the same payload always renders
+// to the same file. Parse reads that output plus the usual hand edits:
+// comments, dotted keys, literal strings, inline arrays (also across lines)
+// and inline tables, and integers with underscores or in hex, octal or
+// binary. Values JSON cannot hold (dates and times, inf and nan) and
+// multi-line strings are refused rather than guessed at; JSON null has no
+// TOML form and fails Render.
+package tomlmap
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+var bareKeyPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// Render renders a JSON-object payload as TOML.
+func Render(payload []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(payload))
+	dec.UseNumber()
+	var m map[string]any
+	if err := dec.Decode(&m); err != nil {
+		return nil, fmt.Errorf("expected JSON object: %w", err)
+	}
+	var b strings.Builder
+	if err := renderTable(&b, nil, m, ""); err != nil {
+		return nil, err
+	}
+	return []byte(b.String()), nil
+}
+
+// renderTable writes header (empty for the root), the plain entries of m, then
+// its tables and arrays of tables, which TOML requires to come last.
+func renderTable(b *strings.Builder, path []string, m map[string]any, header string) error {
+	if header != "" {
+		if b.Len() > 0 {
+			b.WriteByte('\n')
+		}
+		b.WriteString(header + "\n")
+	}
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if isTable(m[key]) || isTableArray(m[key]) {
+			continue
+		}
+		value, err := inline(m[key])
+		if err != nil {
+			return fmt.Errorf("key %s: %w", dotted(append(path, key)), err)
+		}
+		b.WriteString(quoteKey(key) + " = " + value + "\n")
+	}
+	for _, key := range keys {
+		sub := append(append([]string{}, path...), key)
+		switch value := m[key].(type) {
+		case map[string]any:
+			if err := renderTable(b, sub, value, "["+dotted(sub)+"]"); err != nil {
+				return err
+			}
+		case []any:
+			if !isTableArray(value) {
+				continue
+			}
+			for _, item := range value {
+				if err := renderTable(b, sub, item.(map[string]any), "[["+dotted(sub)+"]]"); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func isTable(value any) bool {
+	_, ok := value.(map[string]any)
+	return ok
+}
+
+// isTableArray reports whether value is a non-empty list of objects, which
+// renders as an array of tables rather than inline.
+func isTableArray(value any) bool {
+	list, ok := value.([]any)
+	if !ok || len(list) == 0 {
+		return false
+	}
+	for _, item := range list {
+		if !isTable(item) {
+			return false
+		}
+	}
+	return true
+}
+
+// inline renders value on one line: a scalar, an inline array or an inline
+// table.
+func inline(value any) (string, error) {
+	switch value := value.(type) {
+	case string:
+		return quote(value), nil
+	case json.Number:
+		text := value.String()
+		if !strings.ContainsAny(text, ".eE") {
+			if _, err := strconv.ParseInt(text, 10, 64); err != nil {
+				return "", fmt.Errorf("integer %s does not fit in 64 bits", text)
+			}
+		}
+		return text, nil
+	case bool:
+		return strconv.FormatBool(value), nil
+	case []any:
+		items := make([]string, 0, len(value))
+		for _, item := range value {
+			rendered, err := inline(item)
+			if err != nil {
+				return "", err
+			}
+			items = append(items, rendered)
+		}
+		return "[" + strings.Join(items, ", ") + "]", nil
+	case map[string]any:
+		if len(value) == 0 {
+			return "{}", nil
+		}
+		keys := make([]string, 0, len(value))
+		for key := range value {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		items := make([]string, 0, len(keys))
+		for _, key := range keys {
+			rendered, err := inline(value[key])
+			if err != nil {
+				return "", err
+			}
+			items = append(items, quoteKey(key)+" = "+rendered)
+		}
+		return "{ " + strings.Join(items, ", ") + " }", nil
+	}
+	return "", errors.New("null has no TOML form")
+}
+
+func dotted(path []string) string {
+	parts := make([]string, len(path))
+	for i, key := range path {
+		parts[i] = quoteKey(key)
+	}
+	return strings.Join(parts, ".")
+}
+
+func quoteKey(key string) string {
+	if bareKeyPattern.MatchString(key) {
+		return key
+	}
+	return quote(key)
+}
+
+// quote returns s as a TOML basic string.
+func quote(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\b':
+			b.WriteString(`\b`)
+		case '\t':
+			b.WriteString(`\t`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\f':
+			b.WriteString(`\f`)
+		case '\r':
+			b.WriteString(`\r`)
+		default:
+			if r < 0x20 || r == 0x7f {
+				fmt.Fprintf(&b, `\u%04X`, r)
+				continue
+			}
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+type parser struct {
+	src  string
+	pos  int
+	line int
+	root map[string]any
+	// defined holds the tables a [header] defined, and tableArrays the
+	// arrays of tables, by path.
+	defined     map[string]bool
+	tableArrays map[string]bool
+}
+
+// Parse parses a TOML document into a JSON object.
+func Parse(data []byte) ([]byte, error) {
+	p := &parser{
+		src:         strings.ReplaceAll(string(data), "\r\n", "\n"),
+		line:        1,
+		root:        map[string]any{},
+		defined:     map[string]bool{},
+		tableArrays: map[string]bool{},
+	}
+	if err := p.parseDocument(); err != nil {
+		return nil, fmt.Errorf("line %d: %w", p.line, err)
+	}
+	return json.Marshal(p.root)
+}
+
+func (p *parser) parseDocument() error {
+	current := p.root
+	for {
+		p.skipBlank(true)
+		if p.pos >= len(p.src) {
+			return nil
+		}
+		var err error
+		if p.src[p.pos] == '[' {
+			current, err = p.parseHeader()
+		} else {
+			err = p.parseKeyValue(current)
+		}
+		if err != nil {
+			return err
+		}
+		if err := p.endLine(); err != nil {
+			return err
+		}
+	}
+}
+
+// parseHeader reads a [table] or [[array of tables]] header and returns the
+// table following entries go into.
+func (p *parser) parseHeader() (map[string]any, error) {
+	array := strings.HasPrefix(p.src[p.pos:], "[[")
+	closing := "]"
+	p.pos++
+	if array {
+		closing = "]]"
+		p.pos++
+	}
+	p.skipBlank(false)
+	path, err := p.parseKeyPath()
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(p.src[p.pos:], closing) {
+		return nil, fmt.Errorf("expected %s after the table name", closing)
+	}
+	p.pos += len(closing)
+	parent, err := p.walk(p.root, path[:len(path)-1])
+	if err != nil {
+		return nil, err
+	}
+	key, id := path[len(path)-1], pathID(path)
+	if array {
+		list, exists := parent[key].([]any)
+		if _, taken := parent[key]; taken && (!exists || !p.tableArrays[id]) {
+			return nil, fmt.Errorf("key %s already holds a value", dotted(path))
+		}
+		table := map[string]any{}
+		parent[key] = append(list, table)
+		p.tableArrays[id] = true
+		for defined := range p.defined {
+			if strings.HasPrefix(defined, id+"\x00") {
+				delete(p.defined, defined)
+			}
+		}
+		return table, nil
+	}
+	if p.defined[id] {
+		return nil, fmt.Errorf("table %s is defined twice", dotted(path))
+	}
+	p.defined[id] = true
+	return p.walk(p.root, path)
+}
+
+func (p *parser) parseKeyValue(table map[string]any) error {
+	path, err := p.parseKeyPath()
+	if err != nil {
+		return err
+	}
+	if p.pos >= len(p.src) || p.src[p.pos] != '=' {
+		return fmt.Errorf("expected \"key = value\"")
+	}
+	p.pos++
+	p.skipBlank(false)
+	value, err := p.parseValue()
+	if err != nil {
+		return err
+	}
+	parent, err := p.walk(table, path[:len(path)-1])
+	if err != nil {
+		return err
+	}
+	key := path[len(path)-1]
+	if _, taken := parent[key]; taken {
+		return fmt.Errorf("duplicate key %s", dotted(path))
+	}
+	parent[key] = value
+	return nil
+}
+
+// walk returns the table at path under table, creating missing tables. A
+// path through an array of tables continues in its last table.
+func (p *parser) walk(table map[string]any, path []string) (map[string]any, error) {
+	for i, key := range path {
+		switch value := table[key].(type) {
+		case nil:
+			next := map[string]any{}
+			table[key] = next
+			table = next
+		case map[string]any:
+			table = value
+		case []any:
+			if len(value) == 0 || !isTable(value[len(value)-1]) {
+				return nil, fmt.Errorf("key %s already holds a value", dotted(path[:i+1]))
+			}
+			table = value[len(value)-1].(map[string]any)
+		default:
+			return nil, fmt.Errorf("key %s already holds a value", dotted(path[:i+1]))
+		}
+	}
+	return table, nil
+}
+
+// parseKeyPath reads a dotted key of bare and quoted parts and the blanks
+// after it.
+func (p *parser) parseKeyPath() ([]string, error) {
+	var path []string
+	for {
+		var key string
+		switch {
+		case p.pos < len(p.src) && (p.src[p.pos] == '"' || p.src[p.pos] == '\''):
+			value, err := p.parseString()
+			if err != nil {
+				return nil, err
+			}
+			key = value
+		default:
+			start := p.pos
+			for p.pos < len(p.src) && isBareKeyByte(p.src[p.pos]) {
+				p.pos++
+			}
+			if start == p.pos {
+				return nil, errors.New("expected a key")
+			}
+			key = p.src[start:p.pos]
+		}
+		path = append(path, key)
+		p.skipBlank(false)
+		if p.pos >= len(p.src) || p.src[p.pos] != '.' {
+			return path, nil
+		}
+		p.pos++
+		p.skipBlank(false)
+	}
+}
+
+func isBareKeyByte(c byte) bool {
+	return c == '_' || c == '-' || ('0' <= c && c <= '9') || ('A' <= c && c <= 'Z') || ('a' <= c && c <= 'z')
+}
+
+func (p *parser) parseValue() (any, error) {
+	if p.pos >= len(p.src) {
+		return nil, errors.New("missing value")
+	}
+	switch p.src[p.pos] {
+	case '"', '\'':
+		return p.parseString()
+	case '[':
+		return p.parseArray()
+	case '{':
+		return p.parseInlineTable()
+	}
+	start := p.pos
+	for p.pos < len(p.src) && !strings.ContainsRune(" \t\n,]}#", rune(p.src[p.pos])) {
+		p.pos++
+	}
+	return parseScalar(p.src[start:p.pos])
+}
+
+func (p *parser) parseArray() ([]any, error) {
+	p.pos++ // [
+	list := []any{}
+	for {
+		p.skipBlank(true)
+		if p.pos < len(p.src) && p.src[p.pos] == ']' {
+			p.pos++
+			return list, nil
+		}
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, value)
+		p.skipBlank(true)
+		switch {
+		case p.pos < len(p.src) && p.src[p.pos] == ',':
+			p.pos++
+		case p.pos < len(p.src) && p.src[p.pos] == ']':
+		default:
+			return nil, errors.New("expected , or ] in array")
+		}
+	}
+}
+
+func (p *parser) parseInlineTable() (map[string]any, error) {
+	p.pos++ // {
+	table := map[string]any{}
+	p.skipBlank(false)
+	if p.pos < len(p.src) && p.src[p.pos] == '}' {
+		p.pos++
+		return table, nil
+	}
+	for {
+		if err := p.parseKeyValue(table); err != nil {
+			return nil, err
+		}
+		p.skipBlank(false)
+		if p.pos < len(p.src) && p.src[p.pos] == '}' {
+			p.pos++
+			return table, nil
+		}
+		if p.pos >= len(p.src) || p.src[p.pos] != ',' {
+			return nil, errors.New("expected , or } in inline table")
+		}
+		p.pos++
+		p.skipBlank(false)
+	}
+}
+
+func (p *parser) parseString() (string, error) {
+	quoteChar := p.src[p.pos]
+	if strings.HasPrefix(p.src[p.pos:], strings.Repeat(string(quoteChar), 3)) {
+		return "", errors.New("multi-line strings are not supported; use \\n escapes in a basic string")
+	}
+	p.pos++
+	var b strings.Builder
+	for p.pos < len(p.src) && p.src[p.pos] != '\n' {
+		c := p.src[p.pos]
+		switch {
+		case c == quoteChar:
+			p.pos++
+			return b.String(), nil
+		case c == '\\' && quoteChar == '"':
+			if err := p.parseEscape(&b); err != nil {
+				return "", err
+			}
+			continue
+		}
+		b.WriteByte(c)
+		p.pos++
+	}
+	return "", errors.New("unterminated string")
+}
+
+func (p *parser) parseEscape(b *strings.Builder) error {
+	rest := p.src[p.pos+1:]
+	simple := map[byte]string{'b': "\b", 't': "\t", 'n': "\n", 'f': "\f", 'r': "\r", '"': "\"", '\\': "\\"}
+	if rest != "" {
+		if text, ok := simple[rest[0]]; ok {
+			b.WriteString(text)
+			p.pos += 2
+			return nil
+		}
+		if size := map[byte]int{'u': 4, 'U': 8}[rest[0]]; size > 0 && len(rest) > size {
+			if code, err := strconv.ParseUint(rest[1:1+size], 16, 32); err == nil && utf8.ValidRune(rune(code)) {
+				b.WriteRune(rune(code))
+				p.pos += 2 + size
+				return nil
+			}
+		}
+	}
+	return errors.New("invalid escape in string")
+}
+
+var (
+	decimalPattern = regexp.MustCompile(`^[+-]?(0|[1-9](_?[0-9])*)$`)
+	floatPattern   = regexp.MustCompile(`^[+-]?(0|[1-9](_?[0-9])*)(\.[0-9](_?[0-9])*)?([eE][+-]?[0-9](_?[0-9])*)?$`)
+	prefixPattern  = regexp.MustCompile(`^0([xob])([0-9A-Fa-f](_?[0-9A-Fa-f])*)$`)
+	datePattern    = regexp.MustCompile(`^[0-9]{4}-[0-9]{2}-[0-9]{2}|^[0-9]{2}:[0-9]{2}`)
+)
+
+// parseScalar reads a bare value: a boolean or a number, written the way JSON
+// writes it.
+func parseScalar(text string) (any, error) {
+	switch {
+	case text == "true":
+		return true, nil
+	case text == "false":
+		return false, nil
+	case decimalPattern.MatchString(text):
+		digits := strings.TrimPrefix(strings.ReplaceAll(text, "_", ""), "+")
+		if _, err := strconv.ParseInt(digits, 10, 64); err != nil {
+			return nil, fmt.Errorf("integer %s does not fit in 64 bits", text)
+		}
+		return json.Number(digits), nil
+	case prefixPattern.MatchString(text):
+		match := prefixPattern.FindStringSubmatch(text)
+		n, err := strconv.ParseInt(strings.ReplaceAll(match[2], "_", ""), map[string]int{"x": 16, "o": 8, "b": 2}[match[1]], 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid integer %s", text)
+		}
+		return json.Number(strconv.FormatInt(n, 10)), nil
+	case floatPattern.MatchString(text):
+		return json.Number(strings.TrimPrefix(strings.ReplaceAll(text, "_", ""), "+")), nil
+	case datePattern.MatchString(text):
+		return nil, fmt.Errorf("dates and times are not supported; quote %s", text)
+	case strings.TrimLeft(text, "+-") == "inf" || strings.TrimLeft(text, "+-") == "nan":
+		return nil, fmt.Errorf("%s has no JSON form", text)
+	}
+	return nil, fmt.Errorf("invalid value %q; quote strings", text)
+}
+
+// skipBlank skips spaces, tabs and comments, and newlines too when
+// newlines is set.
+func (p *parser) skipBlank(newlines bool) {
+	for p.pos < len(p.src) {
+		switch c := p.src[p.pos]; {
+		case c == ' ' || c == '\t':
+			p.pos++
+		case c == '#':
+			for p.pos < len(p.src) && p.src[p.pos] != '\n' {
+				p.pos++
+			}
+		case c == '\n' && newlines:
+			p.pos++
+			p.line++
+		default:
+			return
+		}
+	}
+}
+
+// endLine requires the rest of the line to be blank or a comment.
+func (p *parser) endLine() error {
+	p.skipBlank(false)
+	if p.pos < len(p.src) && p.src[p.pos] != '\n' {
+		rest, _, _ := strings.Cut(p.src[p.pos:], "\n")
+		return fmt.Errorf("unexpected %q after the value", rest)
+	}
+	return nil
+}
+
+func pathID(path []string) string {
+	return strings.Join(path, "\x00")
+}
@@ -0,0 +1,151 @@
+package tomlmap
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	payload := `{
+		"name": "api",
+		"port": 5432,
+		"ratio": 0.5,
+		"debug": false,
+		"quote": "say \"hi\"\n\t\\ \u0001",
+		"tags": ["a", 1, {"k": "v", "n": {}}, []],
+		"empty": [],
+		"server": {"host": "0.0.0.0", "tls": {"enabled": true}, "limits": {}},
+		"dotted.key": "x",
+		"replica": [{"name": "a", "meta": {"zone": "z1"}}, {}]
+	}`
+	got, err := Render([]byte(payload))
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	want := `debug = false
+"dotted.key" = "x"
+empty = []
+name = "api"
+port = 5432
+quote = "say \"hi\"\n\t\\ \u0001"
+ratio = 0.5
+tags = ["a", 1, { k = "v", n = {} }, []]
+
+[[replica]]
+name = "a"
+
+[replica.meta]
+zone = "z1"
+
+[[replica]]
+
+[server]
+host = "0.0.0.0"
+
+[server.limits]
+
+[server.tls]
+enabled = true
+`
+	if string(got) != want {
+		t.Fatalf("unexpected TOML:\n%s", got)
+	}
+
+	back, err := Parse(got)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	want = `{"debug":false,"dotted.key":"x","empty":[],"name":"api","port":5432,"quote":"say \"hi\"\n\t\\ \u0001","ratio":0.5,"replica":[{"meta":{"zone":"z1"},"name":"a"},{}],"server":{"host":"0.0.0.0","limits":{},"tls":{"enabled":true}},"tags":["a",1,{"k":"v","n":{}},[]]}`
+	if string(back) != want {
+		t.Fatalf("round trip changed the payload:\n%s", back)
+	}
+
+	if got, err := Render([]byte(`{"ctl": "\b\f\r\u007f"}`)); err != nil || string(got) != "ctl = \"\\b\\f\\r\\u007F\"\n" {
+		t.Fatalf("unexpected control characters: %q %v", got, err)
+	}
+	if got, err := Render([]byte(`{}`)); err != nil || string(got) != "" {
+		t.Fatalf("unexpected empty render: %q %v", got, err)
+	}
+	for payload, wantErr := range map[string]string{
+		`["not", "an object"]`:           "expected JSON object",
+		`{"a": {"b": null}}`:             "key a.b: null has no TOML form",
+		`{"a": [null]}`:                  "key a: null has no TOML form",
+		`{"a": [{"b": 1}, {"c": null}]}`: "key a.c: null has no TOML form",
+		`{"a": {"b": [{"c": null}]}}`:    "key a.b.c: null has no TOML form",
+		`{"a": [{"b": [null]}, 1]}`:      "key a: null has no TOML form",
+		`{"n": 99999999999999999999}`:    "key n: integer 99999999999999999999 does not fit in 64 bits",
+	} {
+		if _, err := Render([]byte(payload)); err == nil || !strings.Contains(err.Error(), wantErr) {
+			t.Fatalf("%s: expected %q, got %v", payload, wantErr, err)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	cases := map[string]string{
+		"":                                    `{}`,
+		"# only a comment\n":                  `{}`,
+		"a = 1 # port\r\nb = 'lit\\eral'\n":   `{"a":1,"b":"lit\\eral"}`,
+		"a.b = 1\na.c = \"x\"\n":              `{"a":{"b":1,"c":"x"}}`,
+		"\"q.k\" . 'x' = true\n":              `{"q.k":{"x":true}}`,
+		"n = [\n  1, # one\n  2,\n]\n":        `{"n":[1,2]}`,
+		"i = { a = 1, b.c = 'x' }\ne = { }\n": `{"e":{},"i":{"a":1,"b":{"c":"x"}}}`,
+		"x = 0x1F\no = 0o17\nb = 0b101\nu = 1_000\np = +5\nneg = -3\n": `{"b":5,"neg":-3,"o":15,"p":5,"u":1000,"x":31}`,
+		"f = 1.5\ng = -2e3\nh = 6.626e-34\nk = 1_0.0_1\nz = +0.5\n":    `{"f":1.5,"g":-2e3,"h":6.626e-34,"k":10.01,"z":0.5}`,
+		"s = \"\\u00e9\\U0001F600\\b\\f\\r\"\n":                        `{"s":"é😀\b\f\r"}`,
+		"[a.b]\nc = 1\n[a]\nd = 2\n":                                   `{"a":{"b":{"c":1},"d":2}}`,
+		"[[t]]\nn = 1\n[t.sub]\nx = 1\n[[t]]\nn = 2\n[t.sub]\nx = 2\n": `{"t":[{"n":1,"sub":{"x":1}},{"n":2,"sub":{"x":2}}]}`,
+		"[ spaced . 'name' ]\nv = 1\n":                                 `{"spaced":{"name":{"v":1}}}`,
+	}
+	for input, want := range cases {
+		got, err := Parse([]byte(input))
+		if err != nil || string(got) != want {
+			t.Fatalf("Parse(%q): got %s %v, want %s", input, got, err, want)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	cases := map[string]string{
+		"a = 1\nb\n":                     `line 2: expected "key = value"`,
+		"= 1\n":                          "line 1: expected a key",
+		"a =\n":                          "line 1: invalid value",
+		"a = ":                           "line 1: missing value",
+		"a = 1 2\n":                      `line 1: unexpected "2" after the value`,
+		"a = 1\na = 2\n":                 "line 2: duplicate key a",
+		"a = 1\na.b = 2\n":               "line 2: key a already holds a value",
+		"a = []\n[a.b]\n":                "line 2: key a already holds a value",
+		"a = 1\n[[a]]\n":                 "line 2: key a already holds a value",
+		"[a]\n[a]\n":                     "line 2: table a is defined twice",
+		"[a\n":                           "line 1: expected ] after the table name",
+		"[[a]\n":                         "line 1: expected ]] after the table name",
+		"[a.]\n":                         "line 1: expected a key",
+		"[a]\n[[a.b.c]]\n[x.y]\n[b.\"\n": "line 4: unterminated string",
+		"[x.'y\n":                        "line 1: unterminated string",
+		"a = [1 2]\n":                    "line 1: expected , or ] in array",
+		"a = [1, x]\n":                   `line 1: invalid value "x"; quote strings`,
+		"a = {b = 1 c = 2}\n":            "line 1: expected , or } in inline table",
+		"a = {b = 1, = 2}\n":             "line 1: expected a key",
+		"a = {b = x}\n":                  "invalid value",
+		"a = \"\"\"x\"\"\"\n":            "line 1: multi-line strings are not supported",
+		"a = '''x'''\n":                  "multi-line strings are not supported",
+		"a = \"open\n":                   "line 1: unterminated string",
+		"a = \"\\q\"\n":                  "line 1: invalid escape in string",
+		"a = \"\\u12\"\n":                "invalid escape in string",
+		"a = \"\\uD800\"\n":              "invalid escape in string",
+		"a = \"\\":                       "invalid escape in string",
+		"a = 1979-05-27\n":               "line 1: dates and times are not supported; quote 1979-05-27",
+		"a = 07:32:00\n":                 "dates and times are not supported",
+		"a = inf\n":                      "line 1: inf has no JSON form",
+		"a = -nan\n":                     "-nan has no JSON form",
+		"a = 99999999999999999999\n":     "line 1: integer 99999999999999999999 does not fit in 64 bits",
+		"a = 0x8000000000000000\n":       "line 1: invalid integer 0x8000000000000000",
+		"a = 0b2\n":                      "invalid integer 0b2",
+		"a = yes\n":                      `line 1: invalid value "yes"; quote strings`,
+	}
+	for input, want := range cases {
+		if _, err := Parse([]byte(input)); err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("Parse(%q): expected %q, got %v", input, want, err)
+		}
+	}
+}
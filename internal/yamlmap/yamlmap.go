@@ -0,0 +1,363 @@
+// Package yamlmap converts JSON-object payloads to block-style YAML and back.
+// Render sorts keys at every level and quotes every string another YAML
+// reader could take for a number, boolean or null, so the same payload always
+// renders to the same file. Parse reads that output plus the usual hand
+// edits: comments, plain and single-quoted scalars, and "- key: value" list
+// items. Flow collections other than {} and [], block scalars (| and >),
+// anchors, aliases and tags are refused rather than guessed at.
+package yamlmap
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// plainPattern matches the strings Render leaves unquoted. Anything starting
+// with a digit, a sign or a dot is quoted, which keeps numbers, dates and
+// YAML 1.1 octals strings.
+var plainPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_./@+-]*$`)
+
+// ambiguousWords are booleans or nulls to at least one YAML version.
+var ambiguousWords = map[string]bool{
+	"true": true, "false": true, "yes": true, "no": true, "on": true, "off": true,
+	"y": true, "n": true, "null": true,
+}
+
+// Render renders a JSON-object payload as YAML.
+func Render(payload []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(payload))
+	dec.UseNumber()
+	var m map[string]any
+	if err := dec.Decode(&m); err != nil {
+		return nil, fmt.Errorf("expected JSON object: %w", err)
+	}
+	var b strings.Builder
+	if len(m) == 0 {
+		b.WriteString("{}\n")
+	}
+	renderMap(&b, m, 0, false)
+	return []byte(b.String()), nil
+}
+
+// renderMap writes the entries of m; with inline set the first one follows
+// the "- " of a list item on the same line.
+func renderMap(b *strings.Builder, m map[string]any, indent int, inline bool) {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for i, key := range keys {
+		if i > 0 || !inline {
+			b.WriteString(strings.Repeat(" ", indent))
+		}
+		b.WriteString(scalar(key))
+		b.WriteByte(':')
+		renderValue(b, m[key], indent+2)
+	}
+}
+
+func renderList(b *strings.Builder, list []any, indent int) {
+	for _, item := range list {
+		b.WriteString(strings.Repeat(" ", indent) + "-")
+		switch item := item.(type) {
+		case map[string]any:
+			if len(item) > 0 {
+				b.WriteByte(' ')
+				renderMap(b, item, indent+2, true)
+				continue
+			}
+		case []any:
+			if len(item) > 0 {
+				b.WriteByte('\n')
+				renderList(b, item, indent+2)
+				continue
+			}
+		}
+		renderValue(b, item, indent+2)
+	}
+}
+
+// renderValue writes what follows a "key:" or "-": a scalar on the same line,
+// or a nested collection on the next lines.
+func renderValue(b *strings.Builder, value any, indent int) {
+	switch value := value.(type) {
+	case map[string]any:
+		if len(value) == 0 {
+			b.WriteString(" {}\n")
+			return
+		}
+		b.WriteByte('\n')
+		renderMap(b, value, indent, false)
+	case []any:
+		if len(value) == 0 {
+			b.WriteString(" []\n")
+			return
+		}
+		b.WriteByte('\n')
+		renderList(b, value, indent)
+	case string:
+		b.WriteString(" " + scalar(value) + "\n")
+	case json.Number:
+		b.WriteString(" " + value.String() + "\n")
+	case bool:
+		b.WriteString(" " + strconv.FormatBool(value) + "\n")
+	default:
+		b.WriteString(" null\n")
+	}
+}
+
+// scalar returns s plain when no YAML reader can take it for anything but
+// that string, and double-quoted otherwise.
+func scalar(s string) string {
+	if plainPattern.MatchString(s) && !ambiguousWords[strings.ToLower(s)] {
+		return s
+	}
+	return strconv.Quote(s)
+}
+
+type line struct {
+	num    int
+	indent int
+	text   string
+}
+
+type parser struct {
+	lines []line
+	pos   int
+}
+
+// Parse parses YAML whose top level is a mapping into a JSON object.
+func Parse(data []byte) ([]byte, error) {
+	p := &parser{}
+	for i, raw := range strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n") {
+		text := stripComment(raw)
+		trimmed := strings.TrimLeft(text, " ")
+		if strings.TrimSpace(trimmed) == "" || (len(p.lines) == 0 && strings.TrimSpace(trimmed) == "---") {
+			continue
+		}
+		if strings.HasPrefix(trimmed, "\t") {
+			return nil, fmt.Errorf("line %d: tabs cannot indent YAML", i+1)
+		}
+		p.lines = append(p.lines, line{num: i + 1, indent: len(text) - len(trimmed), text: strings.TrimRight(trimmed, " \t")})
+	}
+	if len(p.lines) == 1 && p.lines[0].text == "{}" {
+		return []byte("{}"), nil
+	}
+	m := map[string]any{}
+	if len(p.lines) > 0 {
+		if p.lines[0].indent != 0 || isItem(p.lines[0].text) {
+			return nil, fmt.Errorf("line %d: the top level must be a mapping", p.lines[0].num)
+		}
+		value, err := p.parseMap(0)
+		if err != nil {
+			return nil, err
+		}
+		m = value
+	}
+	if p.pos < len(p.lines) {
+		return nil, fmt.Errorf("line %d: unexpected indentation", p.lines[p.pos].num)
+	}
+	return json.Marshal(m)
+}
+
+func (p *parser) parseMap(indent int) (map[string]any, error) {
+	m := map[string]any{}
+	for p.pos < len(p.lines) && p.lines[p.pos].indent == indent && !isItem(p.lines[p.pos].text) {
+		l := p.lines[p.pos]
+		key, rest, ok := splitKey(l.text)
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected \"key: value\"", l.num)
+		}
+		name, err := parseKey(key)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", l.num, err)
+		}
+		if _, dup := m[name]; dup {
+			return nil, fmt.Errorf("line %d: duplicate key %q", l.num, name)
+		}
+		p.pos++
+		value, err := p.parseValue(rest, indent, l.num, true)
+		if err != nil {
+			return nil, err
+		}
+		m[name] = value
+	}
+	return m, nil
+}
+
+func (p *parser) parseList(indent int) ([]any, error) {
+	list := []any{}
+	for p.pos < len(p.lines) && p.lines[p.pos].indent == indent && isItem(p.lines[p.pos].text) {
+		l := p.lines[p.pos]
+		content := strings.TrimLeft(strings.TrimPrefix(l.text, "-"), " ")
+		if content != "" && (isItem(content) || isEntry(content)) {
+			// An inline "- key: value" or "- - x" starts a nested block
+			// at the column of its content.
+			p.lines[p.pos] = line{num: l.num, indent: indent + len(l.text) - len(content), text: content}
+			value, err := p.parseBlock(p.lines[p.pos].indent)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, value)
+			continue
+		}
+		p.pos++
+		value, err := p.parseValue(content, indent, l.num, false)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, value)
+	}
+	return list, nil
+}
+
+func (p *parser) parseBlock(indent int) (any, error) {
+	if isItem(p.lines[p.pos].text) {
+		return p.parseList(indent)
+	}
+	return p.parseMap(indent)
+}
+
+// parseValue parses what follows "key:" or "-" on line num: a scalar, or the
+// nested block on the next lines. A mapping value may be a list at the
+// mapping's own indentation.
+func (p *parser) parseValue(rest string, indent, num int, inMap bool) (any, error) {
+	if rest != "" {
+		value, err := parseScalar(rest)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", num, err)
+		}
+		return value, nil
+	}
+	if p.pos < len(p.lines) {
+		next := p.lines[p.pos]
+		if next.indent > indent || (inMap && next.indent == indent && isItem(next.text)) {
+			return p.parseBlock(next.indent)
+		}
+	}
+	return nil, nil
+}
+
+func isItem(text string) bool {
+	return text == "-" || strings.HasPrefix(text, "- ")
+}
+
+func isEntry(text string) bool {
+	_, _, ok := splitKey(text)
+	return ok
+}
+
+// splitKey splits "key: value" at the first ": " (or a final ":") outside
+// quotes.
+func splitKey(text string) (string, string, bool) {
+	quote := byte(0)
+	for i := 0; i < len(text); i++ {
+		ch := text[i]
+		switch {
+		case quote == '"' && ch == '\\':
+			i++
+		case quote != 0:
+			if ch == quote {
+				quote = 0
+			}
+		case (ch == '"' || ch == '\'') && i == 0:
+			quote = ch
+		case ch == ':' && (i == len(text)-1 || text[i+1] == ' '):
+			return strings.TrimSpace(text[:i]), strings.TrimSpace(text[i+1:]), true
+		}
+	}
+	return "", "", false
+}
+
+func parseKey(key string) (string, error) {
+	value, err := parseScalar(key)
+	if err != nil {
+		return "", err
+	}
+	switch value := value.(type) {
+	case string:
+		return value, nil
+	case nil:
+		if key == "" {
+			return "", errors.New("empty key")
+		}
+	}
+	return key, nil
+}
+
+var (
+	jsonNumberPattern = regexp.MustCompile(`^-?(0|[1-9][0-9]*)(\.[0-9]+)?([eE][-+]?[0-9]+)?$`)
+	yamlNumberPattern = regexp.MustCompile(`^([-+]?(\.[0-9]+|[0-9][0-9_]*(\.[0-9]*)?)([eE][-+]?[0-9]+)?|[-+]?\.(inf|Inf|INF)|\.(nan|NaN|NAN)|0o[0-7]+|0x[0-9a-fA-F]+)$`)
+)
+
+func parseScalar(text string) (any, error) {
+	switch {
+	case text == "{}":
+		return map[string]any{}, nil
+	case text == "[]":
+		return []any{}, nil
+	case text == "" || text == "~" || text == "null" || text == "Null" || text == "NULL":
+		return nil, nil
+	case text[0] == '"':
+		value, err := strconv.Unquote(text)
+		if err != nil {
+			return nil, fmt.Errorf("invalid double-quoted string %s", text)
+		}
+		return value, nil
+	case text[0] == '\'':
+		if len(text) < 2 || text[len(text)-1] != '\'' {
+			return nil, fmt.Errorf("invalid single-quoted string %s", text)
+		}
+		return strings.ReplaceAll(text[1:len(text)-1], "''", "'"), nil
+	case strings.ContainsRune("{[", rune(text[0])):
+		return nil, fmt.Errorf("flow collections are not supported: %s", text)
+	case strings.ContainsRune("|>", rune(text[0])):
+		return nil, errors.New("block scalars (| and >) are not supported; use a double-quoted string")
+	case strings.ContainsRune("&*!", rune(text[0])):
+		return nil, fmt.Errorf("anchors, aliases and tags are not supported: %s", text)
+	}
+	switch text {
+	case "true", "True", "TRUE":
+		return true, nil
+	case "false", "False", "FALSE":
+		return false, nil
+	}
+	if jsonNumberPattern.MatchString(text) {
+		return json.Number(text), nil
+	}
+	if ambiguousWords[strings.ToLower(text)] || yamlNumberPattern.MatchString(text) {
+		return nil, fmt.Errorf("%s reads differently across YAML versions; quote it", text)
+	}
+	return text, nil
+}
+
+// stripComment drops a comment: a # at the start or after a space, outside
+// quotes.
+func stripComment(text string) string {
+	quote := byte(0)
+	for i := 0; i < len(text); i++ {
+		ch := text[i]
+		switch {
+		case quote == '"' && ch == '\\':
+			i++
+		case quote != 0:
+			if ch == quote {
+				quote = 0
+			}
+		case ch == '"' || ch == '\'':
+			if i == 0 || strings.ContainsRune(" :-", rune(text[i-1])) {
+				quote = ch
+			}
+		case ch == '#' && (i == 0 || text[i-1] == ' ' || text[i-1] == '\t'):
+			return text[:i]
+		}
+	}
+	return text
+}
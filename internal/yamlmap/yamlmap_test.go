@@ -0,0 +1,128 @@
+package yamlmap
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	payload := `{
+		"name": "api",
+		"port": "5432",
+		"replicas": 3,
+		"ratio": 0.5,
+		"debug": false,
+		"yes": "no",
+		"empty": "",
+		"none": null,
+		"quote": "say \"hi\"\n# not a comment",
+		"url": "https://api.example.test/v1",
+		"nested": {"b": 1, "a": {"deep": true}},
+		"list": ["x", {"name": "X", "value": "1"}, ["inner"], {}, []],
+		"emptyMap": {},
+		"emptyList": []
+	}`
+	got, err := Render([]byte(payload))
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	want := `debug: false
+empty: ""
+emptyList: []
+emptyMap: {}
+list:
+  - x
+  - name: X
+    value: "1"
+  -
+    - inner
+  - {}
+  - []
+name: api
+nested:
+  a:
+    deep: true
+  b: 1
+none: null
+port: "5432"
+quote: "say \"hi\"\n# not a comment"
+ratio: 0.5
+replicas: 3
+url: "https://api.example.test/v1"
+"yes": "no"
+`
+	if string(got) != want {
+		t.Fatalf("unexpected YAML:\n%s", got)
+	}
+
+	back, err := Parse(got)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	want = `{"debug":false,"empty":"","emptyList":[],"emptyMap":{},"list":["x",{"name":"X","value":"1"},["inner"],{},[]],"name":"api","nested":{"a":{"deep":true},"b":1},"none":null,"port":"5432","quote":"say \"hi\"\n# not a comment","ratio":0.5,"replicas":3,"url":"https://api.example.test/v1","yes":"no"}`
+	if string(back) != want {
+		t.Fatalf("round trip changed the payload:\n%s", back)
+	}
+
+	if got, err := Render([]byte(`{}`)); err != nil || string(got) != "{}\n" {
+		t.Fatalf("unexpected empty render: %q %v", got, err)
+	}
+	if _, err := Render([]byte(`["not", "an object"]`)); err == nil || !strings.Contains(err.Error(), "expected JSON object") {
+		t.Fatalf("expected object error, got %v", err)
+	}
+}
+
+func TestParse(t *testing.T) {
+	cases := map[string]string{
+		"":                              `{}`,
+		"{}\n":                          `{}`,
+		"---\n# managed\nA: 1 # port\n": `{"A":1}`,
+		"env:\n- name: X\n  value: 'it''s'\n- name: Web\n":    `{"env":[{"name":"X","value":"it's"},{"name":"Web"}]}`,
+		"matrix:\n  - - a\n    - b\n  -\n":                    `{"matrix":[["a","b"],null]}`,
+		"a:\n  b:\n  c: ~\nd: Null\n":                         `{"a":{"b":null,"c":null},"d":null}`,
+		"\"quoted: key\": x\n'single': z\n":                   `{"quoted: key":"x","single":"z"}`,
+		"t: TRUE\nf: False\n":                                 `{"f":false,"t":true}`,
+		"1: one\nnull: two\ntrue: three\n":                    `{"1":"one","null":"two","true":"three"}`,
+		"text: a plain # comment\nhash: a#b\nurl: http://x\n": `{"hash":"a#b","text":"a plain","url":"http://x"}`,
+		"windows: 1\r\nlines: 2\r\n":                          `{"lines":2,"windows":1}`,
+		"dash: -\nit: don't # c\n":                            `{"dash":"-","it":"don't"}`,
+		"esc: \"a\\\"b # c\"\n":                               `{"esc":"a\"b # c"}`,
+		"\"k\\\"ey\": 1\n":                                    `{"k\"ey":1}`,
+	}
+	for input, want := range cases {
+		got, err := Parse([]byte(input))
+		if err != nil || string(got) != want {
+			t.Fatalf("Parse(%q): got %s %v, want %s", input, got, err, want)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	cases := map[string]string{
+		"a: 1\n\tb: 2\n":         "line 2: tabs cannot indent YAML",
+		"- a\n":                  "line 1: the top level must be a mapping",
+		"  a: 1\n":               "line 1: the top level must be a mapping",
+		"a:\n    b: 1\n  c: 2\n": "line 3: unexpected indentation",
+		"a: 1\njust text\n":      `line 2: expected "key: value"`,
+		": 1\n":                  "line 1: empty key",
+		"yes: 1\n":               "line 1: yes reads differently across YAML versions; quote it",
+		"a: 1\na: 2\n":           `line 2: duplicate key "a"`,
+		"a: on\n":                "line 1: on reads differently",
+		"a: 0x1F\n":              "line 1: 0x1F reads differently",
+		"a: 1_000\n":             "line 1: 1_000 reads differently",
+		"a: .inf\n":              "line 1: .inf reads differently",
+		"a: \"\\q\"\n":           `line 1: invalid double-quoted string "\q"`,
+		"a: 'open\n":             "line 1: invalid single-quoted string 'open",
+		"a: [1, 2]\n":            "line 1: flow collections are not supported: [1, 2]",
+		"a: |\n  text\n":         "line 1: block scalars (| and >) are not supported",
+		"a: &anchor x\n":         "line 1: anchors, aliases and tags are not supported: &anchor x",
+		"a:\n  - x: on\n":        "line 2: on reads differently",
+		"a:\n  - on\n":           "line 2: on reads differently",
+		"a:\n  b: on\n":          "line 2: on reads differently",
+	}
+	for input, want := range cases {
+		if _, err := Parse([]byte(input)); err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("Parse(%q): expected %q, got %v", input, want, err)
+		}
+	}
+}